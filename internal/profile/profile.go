@@ -32,17 +32,18 @@ type Profile struct {
 	Version string
 	// InstanceURL is the url of your wekalist instance.
 	InstanceURL string
-	WebPushConfig WebPushConfig 
+	// TrustedProxies are the IP addresses or CIDR networks of the reverse proxies in front of the server,
+	// whose X-Forwarded-For entries are trusted to find the client IP.
+	TrustedProxies []string
+	WebPushConfig  WebPushConfig
 }
 
 // Webpush config
 type WebPushConfig struct {
-    VAPIDPublicKey  string
-    VAPIDPrivateKey string
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
 }
 
-
-
 func (p *Profile) IsDev() bool {
 	return p.Mode != "prod"
 }
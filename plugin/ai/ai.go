@@ -0,0 +1,176 @@
+// Package ai is the plugin for pluggable AI text generation providers.
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+var (
+	// timeout is the timeout for provider requests that are not streamed, and for the response headers
+	// of streamed ones. Default to 2 minutes.
+	timeout = 2 * time.Minute
+	// dialTimeout is the timeout for connecting to the provider.
+	dialTimeout = 30 * time.Second
+)
+
+// AIProvider generates text completions from a prompt.
+type AIProvider interface {
+	// Generate returns a single completion for the request.
+	Generate(ctx context.Context, request *GenerateRequest) (*GenerateResponse, error)
+	// GenerateStream generates a completion and calls onDelta with each incremental chunk of text.
+	// The returned response holds the full text and the final usage summary.
+	GenerateStream(ctx context.Context, request *GenerateRequest, onDelta DeltaFunc) (*GenerateResponse, error)
+	// Embed returns one embedding vector per input text.
+	// Providers without embedding support return ErrEmbeddingNotSupported.
+	Embed(ctx context.Context, request *EmbedRequest) (*EmbedResponse, error)
+	// EmbeddingModel returns the name of the model used by Embed.
+	EmbeddingModel() string
+}
+
+// ErrEmbeddingNotSupported is returned by providers that cannot compute embeddings.
+var ErrEmbeddingNotSupported = errors.New("embedding is not supported by the ai provider")
+
+// DeltaFunc receives an incremental chunk of generated text.
+// Returning an error aborts the stream.
+type DeltaFunc func(delta string) error
+
+type GenerateRequest struct {
+	// System is the optional system instruction sent ahead of the prompt.
+	System string
+	// Prompt is the user prompt.
+	Prompt string
+}
+
+type GenerateResponse struct {
+	// Text is the generated completion.
+	Text string
+	// Model is the model that produced the completion.
+	Model string
+	// Usage is the token usage reported by the provider, if any.
+	Usage Usage
+}
+
+type EmbedRequest struct {
+	// Texts are the inputs to embed.
+	Texts []string
+}
+
+type EmbedResponse struct {
+	// Embeddings holds one vector per input text, in input order.
+	Embeddings [][]float32
+	// Model is the model that produced the embeddings.
+	Model string
+	// Usage is the token usage reported by the provider, if any.
+	Usage Usage
+}
+
+type Usage struct {
+	PromptTokens     int32
+	CompletionTokens int32
+	TotalTokens      int32
+}
+
+// EstimateTokens estimates the tokens of the texts, at about four characters per token.
+// It stands in for the usage of the calls whose provider does not report it.
+func EstimateTokens(texts ...string) int32 {
+	length := 0
+	for _, text := range texts {
+		length += len(text)
+	}
+	return int32((length + 3) / 4)
+}
+
+// NewProvider initializes the provider selected by the given workspace AI setting.
+func NewProvider(setting *storepb.WorkspaceAISetting) (AIProvider, error) {
+	if setting == nil {
+		return nil, errors.New("ai setting is required")
+	}
+	switch setting.Provider {
+	case storepb.WorkspaceAISetting_PROVIDER_UNSPECIFIED, storepb.WorkspaceAISetting_WRAPPER:
+		return NewWrapperProvider(setting)
+	case storepb.WorkspaceAISetting_OPENAI:
+		return NewOpenAIProvider(setting)
+	case storepb.WorkspaceAISetting_OLLAMA:
+		return NewOllamaProvider(setting)
+	default:
+		return nil, errors.Errorf("unsupported ai provider: %v", setting.Provider)
+	}
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors.
+// It returns 0 when the vectors have different dimensions or either is a zero vector.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// newHTTPClient returns a client without an overall timeout, as that would also cut off streamed responses.
+// The transport bounds connecting and waiting for the response headers instead, and the requests that are
+// not streamed are bounded with withTimeout.
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// withTimeout bounds a request that is not streamed, including reading its response.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
+
+// postJSON posts the payload as JSON and returns the response if it has a 2xx status code.
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, payload any) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct request to %s", url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to post request to %s", url)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to post request to %s, status code: %d, response body: %s", url, resp.StatusCode, b)
+	}
+	return resp, nil
+}
+
+func joinURL(baseURL, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
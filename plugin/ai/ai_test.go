@@ -0,0 +1,288 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+func TestOpenAIProvider(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		request := &openAIChatRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.Equal(t, "test-model", request.Model)
+		require.Len(t, request.Messages, 2)
+		require.Equal(t, "system", request.Messages[0].Role)
+		require.Equal(t, "hello", request.Messages[1].Content)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"model": "test-model",
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": "hi there"}},
+			},
+			"usage": map[string]any{"prompt_tokens": 3, "completion_tokens": 2, "total_tokens": 5},
+		}))
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OPENAI,
+		BaseUrl:  s.URL + "/v1",
+		Model:    "test-model",
+		ApiKey:   "test-key",
+	})
+	require.NoError(t, err)
+	response, err := provider.Generate(ctx, &GenerateRequest{System: "be brief", Prompt: "hello"})
+	require.NoError(t, err)
+	require.Equal(t, "hi there", response.Text)
+	require.Equal(t, "test-model", response.Model)
+	require.Equal(t, Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, response.Usage)
+}
+
+func TestOllamaProvider(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/generate", r.URL.Path)
+		request := &ollamaGenerateRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.Equal(t, defaultOllamaModel, request.Model)
+		require.Equal(t, "hello", request.Prompt)
+		require.False(t, request.Stream)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"model":             defaultOllamaModel,
+			"response":          "hi there",
+			"done":              true,
+			"prompt_eval_count": 4,
+			"eval_count":        2,
+		}))
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OLLAMA,
+		BaseUrl:  s.URL,
+	})
+	require.NoError(t, err)
+	response, err := provider.Generate(ctx, &GenerateRequest{Prompt: "hello"})
+	require.NoError(t, err)
+	require.Equal(t, "hi there", response.Text)
+	require.Equal(t, Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}, response.Usage)
+}
+
+type testWrapperServer struct {
+	v1pb.UnimplementedAiServiceServer
+}
+
+func (*testWrapperServer) GenAi(ctx context.Context, request *v1pb.GenAiRequest) (*v1pb.GenAiResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &v1pb.GenAiResponse{
+		Prompt:   request.Prompt,
+		Response: request.Prompt + "|" + md.Get("authorization")[0],
+	}, nil
+}
+
+func TestWrapperProvider(t *testing.T) {
+	ctx := context.Background()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	v1pb.RegisterAiServiceServer(grpcServer, &testWrapperServer{})
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_WRAPPER,
+		BaseUrl:  "http://" + listener.Addr().String(),
+		ApiKey:   "test-key",
+	})
+	require.NoError(t, err)
+	response, err := provider.Generate(ctx, &GenerateRequest{Prompt: "hello"})
+	require.NoError(t, err)
+	require.Equal(t, "hello|Bearer test-key", response.Text)
+}
+
+func TestOpenAIProviderStream(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &openAIChatRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.True(t, request.Stream)
+		require.NotNil(t, request.StreamOptions)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"model\":\"test-model\",\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"model\":\"test-model\",\"choices\":[{\"delta\":{\"content\":\" there\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"model\":\"test-model\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2,\"total_tokens\":5}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OPENAI,
+		BaseUrl:  s.URL,
+	})
+	require.NoError(t, err)
+	deltas := []string{}
+	response, err := provider.GenerateStream(ctx, &GenerateRequest{Prompt: "hello"}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hi", " there"}, deltas)
+	require.Equal(t, "hi there", response.Text)
+	require.Equal(t, "test-model", response.Model)
+	require.Equal(t, Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, response.Usage)
+}
+
+func TestOllamaProviderStream(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &ollamaGenerateRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.True(t, request.Stream)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"model":"llama3.2","response":"hi","done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3.2","response":" there","done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3.2","response":"","done":true,"prompt_eval_count":4,"eval_count":2}`)
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OLLAMA,
+		BaseUrl:  s.URL,
+	})
+	require.NoError(t, err)
+	deltas := []string{}
+	response, err := provider.GenerateStream(ctx, &GenerateRequest{Prompt: "hello"}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hi", " there"}, deltas)
+	require.Equal(t, "hi there", response.Text)
+	require.Equal(t, Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}, response.Usage)
+}
+
+func TestOllamaProviderTimeout(t *testing.T) {
+	ctx := context.Background()
+	defer func(previous time.Duration) { timeout = previous }(timeout)
+	timeout = 100 * time.Millisecond
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &ollamaGenerateRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		if !request.Stream {
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprintln(w, `{"model":"llama3.2","response":"too late","done":true}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for _, word := range []string{"slow", " but", " steady"} {
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(w, `{"model":"llama3.2","response":%q,"done":false}`+"\n", word)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintln(w, `{"model":"llama3.2","response":"","done":true}`)
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OLLAMA,
+		BaseUrl:  s.URL,
+	})
+	require.NoError(t, err)
+
+	// A stream may take longer than the timeout once the headers are in.
+	response, err := provider.GenerateStream(ctx, &GenerateRequest{Prompt: "hello"}, func(string) error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, "slow but steady", response.Text)
+
+	// A request that is not streamed may not.
+	_, err = provider.Generate(ctx, &GenerateRequest{Prompt: "hello"})
+	require.Error(t, err)
+}
+
+func TestOpenAIProviderEmbed(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/embeddings", r.URL.Path)
+		request := &openAIEmbeddingRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.Equal(t, "test-embedding", request.Model)
+		require.Equal(t, []string{"a", "b"}, request.Input)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"model":"test-embedding","data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":2,"total_tokens":2}}`)
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider:       storepb.WorkspaceAISetting_OPENAI,
+		BaseUrl:        s.URL + "/v1",
+		EmbeddingModel: "test-embedding",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "test-embedding", provider.EmbeddingModel())
+	response, err := provider.Embed(ctx, &EmbedRequest{Texts: []string{"a", "b"}})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0}, {0, 1}}, response.Embeddings)
+	require.Equal(t, int32(2), response.Usage.TotalTokens)
+}
+
+func TestOllamaProviderEmbed(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/embed", r.URL.Path)
+		request := &ollamaEmbedRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		require.Equal(t, defaultOllamaEmbeddingModel, request.Model)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"model":"nomic-embed-text","embeddings":[[0.5,0.5]],"prompt_eval_count":3}`)
+	}))
+	defer s.Close()
+
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{
+		Provider: storepb.WorkspaceAISetting_OLLAMA,
+		BaseUrl:  s.URL,
+	})
+	require.NoError(t, err)
+	response, err := provider.Embed(ctx, &EmbedRequest{Texts: []string{"hello"}})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{0.5, 0.5}}, response.Embeddings)
+	require.Equal(t, Usage{PromptTokens: 3, TotalTokens: 3}, response.Usage)
+}
+
+func TestWrapperProviderEmbed(t *testing.T) {
+	provider, err := NewProvider(&storepb.WorkspaceAISetting{Provider: storepb.WorkspaceAISetting_WRAPPER})
+	require.NoError(t, err)
+	_, err = provider.Embed(context.Background(), &EmbedRequest{Texts: []string{"hello"}})
+	require.ErrorIs(t, err, ErrEmbeddingNotSupported)
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	require.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-6)
+	require.InDelta(t, 0.0, CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-6)
+	require.Equal(t, 0.0, CosineSimilarity([]float32{1}, []float32{1, 0}))
+	require.Equal(t, 0.0, CosineSimilarity([]float32{0, 0}, []float32{1, 0}))
+}
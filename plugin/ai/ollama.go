@@ -0,0 +1,194 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	defaultOllamaBaseURL        = "http://localhost:11434"
+	defaultOllamaModel          = "llama3.2"
+	defaultOllamaEmbeddingModel = "nomic-embed-text"
+)
+
+// OllamaProvider talks to an Ollama-style local endpoint.
+type OllamaProvider struct {
+	baseURL        string
+	model          string
+	embeddingModel string
+	apiKey         string
+	client         *http.Client
+}
+
+// NewOllamaProvider initializes a new Ollama provider with the given configuration.
+func NewOllamaProvider(setting *storepb.WorkspaceAISetting) (*OllamaProvider, error) {
+	p := &OllamaProvider{
+		baseURL:        setting.BaseUrl,
+		model:          setting.Model,
+		embeddingModel: setting.EmbeddingModel,
+		apiKey:         setting.ApiKey,
+		client:         newHTTPClient(),
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultOllamaBaseURL
+	}
+	if p.model == "" {
+		p.model = defaultOllamaModel
+	}
+	if p.embeddingModel == "" {
+		p.embeddingModel = defaultOllamaEmbeddingModel
+	}
+	return p, nil
+}
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	System string `json:"system,omitempty"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaGenerateResponse struct {
+	Model           string `json:"model"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int32  `json:"prompt_eval_count"`
+	EvalCount       int32  `json:"eval_count"`
+}
+
+func (r *ollamaGenerateResponse) toUsage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// Generate returns a completion for the request.
+func (p *OllamaProvider) Generate(ctx context.Context, request *GenerateRequest) (*GenerateResponse, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/api/generate"), p.apiKey, &ollamaGenerateRequest{
+		Model:  p.model,
+		Prompt: request.Prompt,
+		System: request.System,
+		Stream: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	generateResponse := &ollamaGenerateResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(generateResponse); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal generate response")
+	}
+
+	response := &GenerateResponse{
+		Text:  generateResponse.Response,
+		Model: generateResponse.Model,
+		Usage: generateResponse.toUsage(),
+	}
+	if response.Model == "" {
+		response.Model = p.model
+	}
+	return response, nil
+}
+
+// GenerateStream streams a completion for the request.
+// Ollama streams newline-delimited JSON objects, the last of which carries the token counts.
+func (p *OllamaProvider) GenerateStream(ctx context.Context, request *GenerateRequest, onDelta DeltaFunc) (*GenerateResponse, error) {
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/api/generate"), p.apiKey, &ollamaGenerateRequest{
+		Model:  p.model,
+		Prompt: request.Prompt,
+		System: request.System,
+		Stream: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &GenerateResponse{
+		Model: p.model,
+	}
+	var text strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		chunk := &ollamaGenerateResponse{}
+		if err := decoder.Decode(chunk); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to unmarshal generate chunk")
+		}
+		if chunk.Model != "" {
+			response.Model = chunk.Model
+		}
+		if chunk.Response != "" {
+			text.WriteString(chunk.Response)
+			if err := onDelta(chunk.Response); err != nil {
+				return nil, err
+			}
+		}
+		if chunk.Done {
+			response.Usage = chunk.toUsage()
+			break
+		}
+	}
+	response.Text = text.String()
+	return response, nil
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int32       `json:"prompt_eval_count"`
+}
+
+// EmbeddingModel returns the name of the model used by Embed.
+func (p *OllamaProvider) EmbeddingModel() string {
+	return p.embeddingModel
+}
+
+// Embed returns one embedding vector per input text.
+func (p *OllamaProvider) Embed(ctx context.Context, request *EmbedRequest) (*EmbedResponse, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/api/embed"), p.apiKey, &ollamaEmbedRequest{
+		Model: p.embeddingModel,
+		Input: request.Texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	embedResponse := &ollamaEmbedResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(embedResponse); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal embed response")
+	}
+	if len(embedResponse.Embeddings) != len(request.Texts) {
+		return nil, errors.Errorf("embed response has %d embeddings for %d inputs", len(embedResponse.Embeddings), len(request.Texts))
+	}
+	return &EmbedResponse{
+		Embeddings: embedResponse.Embeddings,
+		Model:      p.embeddingModel,
+		Usage: Usage{
+			PromptTokens: embedResponse.PromptEvalCount,
+			TotalTokens:  embedResponse.PromptEvalCount,
+		},
+	}, nil
+}
@@ -0,0 +1,249 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	defaultOpenAIBaseURL        = "https://api.openai.com/v1"
+	defaultOpenAIModel          = "gpt-4o-mini"
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+)
+
+// OpenAIProvider talks to any OpenAI-compatible chat completions API.
+type OpenAIProvider struct {
+	baseURL        string
+	model          string
+	embeddingModel string
+	apiKey         string
+	client         *http.Client
+}
+
+// NewOpenAIProvider initializes a new OpenAI-compatible provider with the given configuration.
+func NewOpenAIProvider(setting *storepb.WorkspaceAISetting) (*OpenAIProvider, error) {
+	p := &OpenAIProvider{
+		baseURL:        setting.BaseUrl,
+		model:          setting.Model,
+		embeddingModel: setting.EmbeddingModel,
+		apiKey:         setting.ApiKey,
+		client:         newHTTPClient(),
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultOpenAIBaseURL
+	}
+	if p.model == "" {
+		p.model = defaultOpenAIModel
+	}
+	if p.embeddingModel == "" {
+		p.embeddingModel = defaultOpenAIEmbeddingModel
+	}
+	return p, nil
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []*openAIMessage     `json:"messages"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+	TotalTokens      int32 `json:"total_tokens"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+type openAIChatChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta openAIMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (u *openAIUsage) toUsage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+func (p *OpenAIProvider) buildChatRequest(request *GenerateRequest) *openAIChatRequest {
+	messages := []*openAIMessage{}
+	if request.System != "" {
+		messages = append(messages, &openAIMessage{Role: "system", Content: request.System})
+	}
+	messages = append(messages, &openAIMessage{Role: "user", Content: request.Prompt})
+	return &openAIChatRequest{
+		Model:    p.model,
+		Messages: messages,
+	}
+}
+
+// Generate returns a chat completion for the request.
+func (p *OpenAIProvider) Generate(ctx context.Context, request *GenerateRequest) (*GenerateResponse, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/chat/completions"), p.apiKey, p.buildChatRequest(request))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	chatResponse := &openAIChatResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(chatResponse); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chat completion response")
+	}
+	if len(chatResponse.Choices) == 0 {
+		return nil, errors.New("chat completion response has no choices")
+	}
+
+	response := &GenerateResponse{
+		Text:  chatResponse.Choices[0].Message.Content,
+		Model: chatResponse.Model,
+	}
+	if response.Model == "" {
+		response.Model = p.model
+	}
+	response.Usage = chatResponse.Usage.toUsage()
+	return response, nil
+}
+
+// GenerateStream streams a chat completion for the request using server-sent events.
+func (p *OpenAIProvider) GenerateStream(ctx context.Context, request *GenerateRequest, onDelta DeltaFunc) (*GenerateResponse, error) {
+	chatRequest := p.buildChatRequest(request)
+	chatRequest.Stream = true
+	chatRequest.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/chat/completions"), p.apiKey, chatRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &GenerateResponse{
+		Model: p.model,
+	}
+	var text strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		chunk := &openAIChatChunk{}
+		if err := json.Unmarshal([]byte(data), chunk); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal chat completion chunk")
+		}
+		if chunk.Model != "" {
+			response.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			response.Usage = chunk.Usage.toUsage()
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			text.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read chat completion stream")
+	}
+	response.Text = text.String()
+	return response, nil
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// EmbeddingModel returns the name of the model used by Embed.
+func (p *OpenAIProvider) EmbeddingModel() string {
+	return p.embeddingModel
+}
+
+// Embed returns one embedding vector per input text.
+func (p *OpenAIProvider) Embed(ctx context.Context, request *EmbedRequest) (*EmbedResponse, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	resp, err := postJSON(ctx, p.client, joinURL(p.baseURL, "/embeddings"), p.apiKey, &openAIEmbeddingRequest{
+		Model: p.embeddingModel,
+		Input: request.Texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	embeddingResponse := &openAIEmbeddingResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(embeddingResponse); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal embedding response")
+	}
+	embeddings := make([][]float32, len(request.Texts))
+	for _, data := range embeddingResponse.Data {
+		if data.Index < 0 || data.Index >= len(embeddings) {
+			return nil, errors.Errorf("embedding response has invalid index %d", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+	for i, embedding := range embeddings {
+		if len(embedding) == 0 {
+			return nil, errors.Errorf("embedding response is missing input %d", i)
+		}
+	}
+	return &EmbedResponse{
+		Embeddings: embeddings,
+		Model:      p.embeddingModel,
+		Usage:      embeddingResponse.Usage.toUsage(),
+	}, nil
+}
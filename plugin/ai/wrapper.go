@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	defaultWrapperAddress = "wrapper.villebiz.com:443"
+	wrapperModel          = "wrapper"
+)
+
+// WrapperProvider talks to the hosted gRPC wrapper service.
+type WrapperProvider struct {
+	address  string
+	insecure bool
+	apiKey   string
+}
+
+// NewWrapperProvider initializes a new wrapper provider with the given configuration.
+// The base URL is a host:port address; an "http://" prefix disables TLS.
+func NewWrapperProvider(setting *storepb.WorkspaceAISetting) (*WrapperProvider, error) {
+	p := &WrapperProvider{
+		address: setting.BaseUrl,
+		apiKey:  setting.ApiKey,
+	}
+	if p.address == "" {
+		p.address = defaultWrapperAddress
+	}
+	if strings.HasPrefix(p.address, "http://") {
+		p.insecure = true
+	}
+	p.address = strings.TrimPrefix(strings.TrimPrefix(p.address, "http://"), "https://")
+	p.address = strings.TrimSuffix(p.address, "/")
+	if _, _, err := net.SplitHostPort(p.address); err != nil {
+		return nil, errors.Wrapf(err, "invalid wrapper address %q", p.address)
+	}
+	return p, nil
+}
+
+func (p *WrapperProvider) dial() (*grpc.ClientConn, error) {
+	var creds credentials.TransportCredentials
+	if p.insecure {
+		creds = insecure.NewCredentials()
+	} else {
+		host, _, _ := net.SplitHostPort(p.address)
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName: host,
+		})
+	}
+	conn, err := grpc.NewClient(p.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to wrapper service")
+	}
+	return conn, nil
+}
+
+func (p *WrapperProvider) outgoingContext(ctx context.Context) context.Context {
+	if p.apiKey == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+p.apiKey)
+}
+
+// buildPrompt folds the system instruction into the prompt, since the wrapper only accepts a single prompt.
+func buildPrompt(request *GenerateRequest) string {
+	if request.System == "" {
+		return request.Prompt
+	}
+	return request.System + "\n\n" + request.Prompt
+}
+
+// Generate returns a completion for the request.
+func (p *WrapperProvider) Generate(ctx context.Context, request *GenerateRequest) (*GenerateResponse, error) {
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := v1pb.NewAiServiceClient(conn).GenAi(p.outgoingContext(ctx), &v1pb.GenAiRequest{
+		Prompt: buildPrompt(request),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate response from wrapper service")
+	}
+	return &GenerateResponse{
+		Text:  resp.Response,
+		Model: wrapperModel,
+	}, nil
+}
+
+// GenerateStream generates a completion with the unary wrapper RPC and emits it as a single chunk,
+// since the hosted wrapper does not stream.
+func (p *WrapperProvider) GenerateStream(ctx context.Context, request *GenerateRequest, onDelta DeltaFunc) (*GenerateResponse, error) {
+	response, err := p.Generate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Text != "" {
+		if err := onDelta(response.Text); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// EmbeddingModel returns an empty name, since the wrapper cannot compute embeddings.
+func (*WrapperProvider) EmbeddingModel() string {
+	return ""
+}
+
+// Embed is not supported by the wrapper service.
+func (*WrapperProvider) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
+	return nil, ErrEmbeddingNotSupported
+}
@@ -5,4 +5,6 @@ type IdentityProviderUserInfo struct {
 	DisplayName string
 	Email       string
 	AvatarURL   string
+	// Groups the user is a member of, if the identity provider reports them.
+	Groups []string
 }
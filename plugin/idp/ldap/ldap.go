@@ -0,0 +1,219 @@
+// Package ldap is the plugin for LDAP Identity Provider.
+package ldap
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	// RoleAdmin and RoleUser are the roles groups can be mapped to.
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+
+	defaultUserFilter     = "(uid={username})"
+	defaultGroupAttribute = "memberOf"
+	usernamePlaceholder   = "{username}"
+	timeout               = 10 * time.Second
+)
+
+// ErrInvalidCredentials is returned when the username or the password is wrong.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// IdentityProvider represents an LDAP Identity Provider.
+type IdentityProvider struct {
+	config *storepb.LDAPConfig
+}
+
+// NewIdentityProvider initializes a new LDAP Identity Provider with the given configuration.
+func NewIdentityProvider(config *storepb.LDAPConfig) (*IdentityProvider, error) {
+	for v, field := range map[string]string{
+		config.Url:    "url",
+		config.BaseDn: "baseDn",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is empty but required`, field)
+		}
+	}
+	if config.UserFilter != "" && !strings.Contains(config.UserFilter, usernamePlaceholder) {
+		return nil, errors.Errorf(`the field "userFilter" must contain %s`, usernamePlaceholder)
+	}
+	for _, mapping := range config.GroupRoleMappings {
+		if mapping.Role != RoleAdmin && mapping.Role != RoleUser {
+			return nil, errors.Errorf("unsupported role %q for group %q", mapping.Role, mapping.Group)
+		}
+	}
+
+	return &IdentityProvider{
+		config: config,
+	}, nil
+}
+
+// Authenticate checks the password of the user against the directory and returns the mapped user information.
+// The user is searched with the service account, then the password is checked by binding as the user.
+func (p *IdentityProvider) Authenticate(username, password string) (*idp.IdentityProviderUserInfo, error) {
+	// Most servers treat a bind without password as an anonymous bind, which always succeeds.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if p.config.BindDn != "" {
+		if err := conn.Bind(p.config.BindDn, p.config.BindPassword); err != nil {
+			return nil, errors.Wrap(err, "failed to bind with service account")
+		}
+	}
+
+	fieldMapping := p.fieldMapping()
+	groupAttribute := p.groupAttribute()
+	userFilter := p.config.UserFilter
+	if userFilter == "" {
+		userFilter = defaultUserFilter
+	}
+	attributes := []string{}
+	for _, attribute := range []string{fieldMapping.Identifier, fieldMapping.DisplayName, fieldMapping.Email, fieldMapping.AvatarUrl, groupAttribute} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.BaseDn,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(timeout.Seconds()),
+		false,
+		strings.ReplaceAll(userFilter, usernamePlaceholder, ldap.EscapeFilter(username)),
+		attributes,
+		nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, errors.Wrap(err, "failed to search for user")
+	}
+	if result == nil || len(result.Entries) != 1 {
+		// Usernames matching several entries are ambiguous, so refuse them like unknown usernames.
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "failed to bind as user")
+	}
+
+	userInfo := &idp.IdentityProviderUserInfo{
+		Identifier:  entry.GetAttributeValue(fieldMapping.Identifier),
+		DisplayName: entry.GetAttributeValue(fieldMapping.DisplayName),
+		Email:       entry.GetAttributeValue(fieldMapping.Email),
+		AvatarURL:   entry.GetAttributeValue(fieldMapping.AvatarUrl),
+		Groups:      entry.GetAttributeValues(groupAttribute),
+	}
+	if userInfo.Identifier == "" {
+		return nil, errors.Errorf("the attribute %q is not found or has empty value", fieldMapping.Identifier)
+	}
+	if userInfo.DisplayName == "" {
+		userInfo.DisplayName = userInfo.Identifier
+	}
+	return userInfo, nil
+}
+
+// MapRole returns the role for the groups of a user, or false if no group role mappings are configured.
+// Users in no mapped group get RoleUser.
+func (p *IdentityProvider) MapRole(groups []string) (string, bool) {
+	if len(p.config.GroupRoleMappings) == 0 {
+		return "", false
+	}
+	role := RoleUser
+	for _, mapping := range p.config.GroupRoleMappings {
+		for _, group := range groups {
+			if matchGroup(mapping.Group, group) && mapping.Role == RoleAdmin {
+				role = RoleAdmin
+			}
+		}
+	}
+	return role, true
+}
+
+// matchGroup reports whether a group value, usually a DN, matches the configured group.
+// The configured group can be the full DN or the value of its first RDN, such as the group's cn.
+func matchGroup(configured, group string) bool {
+	if strings.EqualFold(configured, group) {
+		return true
+	}
+	dn, err := ldap.ParseDN(group)
+	if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return false
+	}
+	return strings.EqualFold(configured, dn.RDNs[0].Attributes[0].Value)
+}
+
+func (p *IdentityProvider) dial() (*ldap.Conn, error) {
+	serverURL, err := url.Parse(p.config.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+	tlsConfig := &tls.Config{
+		ServerName: serverURL.Hostname(),
+		// Opt-in for directories with self-signed certificates.
+		InsecureSkipVerify: p.config.InsecureSkipVerify,
+	}
+	conn, err := ldap.DialURL(p.config.Url, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to LDAP server")
+	}
+	conn.SetTimeout(timeout)
+	if p.config.StartTls && serverURL.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to start TLS")
+		}
+	}
+	return conn, nil
+}
+
+func (p *IdentityProvider) fieldMapping() *storepb.FieldMapping {
+	fieldMapping := &storepb.FieldMapping{
+		Identifier:  "uid",
+		DisplayName: "cn",
+		Email:       "mail",
+	}
+	if p.config.FieldMapping == nil {
+		return fieldMapping
+	}
+	if p.config.FieldMapping.Identifier != "" {
+		fieldMapping.Identifier = p.config.FieldMapping.Identifier
+	}
+	if p.config.FieldMapping.DisplayName != "" {
+		fieldMapping.DisplayName = p.config.FieldMapping.DisplayName
+	}
+	if p.config.FieldMapping.Email != "" {
+		fieldMapping.Email = p.config.FieldMapping.Email
+	}
+	if p.config.FieldMapping.AvatarUrl != "" {
+		fieldMapping.AvatarUrl = p.config.FieldMapping.AvatarUrl
+	}
+	return fieldMapping
+}
+
+func (p *IdentityProvider) groupAttribute() string {
+	if p.config.GroupAttribute != "" {
+		return p.config.GroupAttribute
+	}
+	return defaultGroupAttribute
+}
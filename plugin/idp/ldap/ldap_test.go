@@ -0,0 +1,158 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/ldap/ldaptest"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+func newTestServer() *ldaptest.Server {
+	return ldaptest.NewServer(
+		&ldaptest.Entry{
+			DN:       "cn=service,dc=example,dc=com",
+			Password: "service-password",
+		},
+		&ldaptest.Entry{
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "alice-password",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"alice"},
+				"cn":          {"Alice"},
+				"mail":        {"alice@example.com"},
+				"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+			},
+		},
+		&ldaptest.Entry{
+			DN:       "uid=bob,ou=people,dc=example,dc=com",
+			Password: "bob-password",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"bob"},
+				"mail":        {"bob@example.com"},
+			},
+		},
+	)
+}
+
+func TestNewIdentityProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *storepb.LDAPConfig
+		containsErr string
+	}{
+		{
+			name:        "no url",
+			config:      &storepb.LDAPConfig{BaseDn: "dc=example,dc=com"},
+			containsErr: `the field "url" is empty but required`,
+		},
+		{
+			name:        "no baseDn",
+			config:      &storepb.LDAPConfig{Url: "ldap://localhost"},
+			containsErr: `the field "baseDn" is empty but required`,
+		},
+		{
+			name:        "user filter without placeholder",
+			config:      &storepb.LDAPConfig{Url: "ldap://localhost", BaseDn: "dc=example,dc=com", UserFilter: "(uid=alice)"},
+			containsErr: `the field "userFilter" must contain {username}`,
+		},
+		{
+			name: "unsupported role",
+			config: &storepb.LDAPConfig{Url: "ldap://localhost", BaseDn: "dc=example,dc=com", GroupRoleMappings: []*storepb.LDAPConfig_GroupRoleMapping{
+				{Group: "hosts", Role: "HOST"},
+			}},
+			containsErr: `unsupported role "HOST"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewIdentityProvider(test.config)
+			require.ErrorContains(t, err, test.containsErr)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	p, err := NewIdentityProvider(&storepb.LDAPConfig{
+		Url:          server.URL(),
+		BindDn:       "cn=service,dc=example,dc=com",
+		BindPassword: "service-password",
+		BaseDn:       "ou=people,dc=example,dc=com",
+		UserFilter:   "(&(objectClass=person)(uid={username}))",
+	})
+	require.NoError(t, err)
+
+	userInfo, err := p.Authenticate("alice", "alice-password")
+	require.NoError(t, err)
+	require.Equal(t, &idp.IdentityProviderUserInfo{
+		Identifier:  "alice",
+		DisplayName: "Alice",
+		Email:       "alice@example.com",
+		Groups:      []string{"cn=admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+	}, userInfo)
+
+	// The display name falls back to the identifier.
+	userInfo, err = p.Authenticate("bob", "bob-password")
+	require.NoError(t, err)
+	require.Equal(t, "bob", userInfo.DisplayName)
+
+	for _, credentials := range [][2]string{
+		{"alice", "wrong-password"},
+		{"alice", ""},
+		{"alice", "bob-password"},
+		{"carol", "alice-password"},
+		// Filter injection matches no entry.
+		{"*", "alice-password"},
+		{"alice)(uid=*", "alice-password"},
+	} {
+		_, err := p.Authenticate(credentials[0], credentials[1])
+		require.ErrorIs(t, err, ErrInvalidCredentials, credentials[0])
+	}
+
+	t.Run("service bind failure", func(t *testing.T) {
+		p, err := NewIdentityProvider(&storepb.LDAPConfig{
+			Url:          server.URL(),
+			BindDn:       "cn=service,dc=example,dc=com",
+			BindPassword: "wrong-password",
+			BaseDn:       "ou=people,dc=example,dc=com",
+		})
+		require.NoError(t, err)
+		_, err = p.Authenticate("alice", "alice-password")
+		require.ErrorContains(t, err, "failed to bind with service account")
+	})
+}
+
+func TestMapRole(t *testing.T) {
+	p, err := NewIdentityProvider(&storepb.LDAPConfig{Url: "ldap://localhost", BaseDn: "dc=example,dc=com"})
+	require.NoError(t, err)
+	_, ok := p.MapRole([]string{"cn=admins,ou=groups,dc=example,dc=com"})
+	require.False(t, ok)
+
+	p, err = NewIdentityProvider(&storepb.LDAPConfig{Url: "ldap://localhost", BaseDn: "dc=example,dc=com", GroupRoleMappings: []*storepb.LDAPConfig_GroupRoleMapping{
+		{Group: "Admins", Role: RoleAdmin},
+		{Group: "cn=staff,ou=groups,dc=example,dc=com", Role: RoleUser},
+	}})
+	require.NoError(t, err)
+	tests := []struct {
+		groups []string
+		role   string
+	}{
+		{groups: []string{"cn=admins,ou=groups,dc=example,dc=com"}, role: RoleAdmin},
+		{groups: []string{"cn=staff,ou=groups,dc=example,dc=com", "admins"}, role: RoleAdmin},
+		{groups: []string{"cn=staff,ou=groups,dc=example,dc=com"}, role: RoleUser},
+		{groups: []string{"cn=admins-old,ou=groups,dc=example,dc=com"}, role: RoleUser},
+		{groups: nil, role: RoleUser},
+	}
+	for _, test := range tests {
+		role, ok := p.MapRole(test.groups)
+		require.True(t, ok)
+		require.Equal(t, test.role, role, test.groups)
+	}
+}
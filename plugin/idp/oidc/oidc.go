@@ -0,0 +1,361 @@
+// Package oidc is the plugin for OpenID Connect Identity Provider.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	// maxResponseSize limits the documents read from the issuer.
+	maxResponseSize = 1 << 20
+	// clockSkew is the leeway allowed when checking the time claims of ID tokens.
+	clockSkew = time.Minute
+)
+
+// supportedSigningAlgorithms are the ID token signing algorithms accepted by the provider.
+// Symmetric algorithms are not accepted since the keys are published in the JWKS.
+var supportedSigningAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Discovery is the subset of the OpenID Provider metadata used by the provider.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// IdentityProvider represents an OpenID Connect Identity Provider.
+type IdentityProvider struct {
+	config     *storepb.OIDCConfig
+	discovery  *Discovery
+	httpClient *http.Client
+}
+
+// NewIdentityProvider initializes a new OpenID Connect Identity Provider with the given configuration,
+// reading its endpoints from the discovery document of the issuer.
+func NewIdentityProvider(ctx context.Context, config *storepb.OIDCConfig) (*IdentityProvider, error) {
+	for v, field := range map[string]string{
+		config.IssuerUrl:    "issuerUrl",
+		config.ClientId:     "clientId",
+		config.ClientSecret: "clientSecret",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is empty but required`, field)
+		}
+	}
+
+	p := &IdentityProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	discovery := &Discovery{}
+	discoveryURL := strings.TrimSuffix(config.IssuerUrl, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, "", discovery); err != nil {
+		return nil, errors.Wrap(err, "failed to get discovery document")
+	}
+	// The issuer must be identical to the configured one, see OpenID Connect Discovery 1.0 section 4.3.
+	if discovery.Issuer != config.IssuerUrl {
+		return nil, errors.Errorf("issuer %q in discovery document does not match %q", discovery.Issuer, config.IssuerUrl)
+	}
+	for v, field := range map[string]string{
+		discovery.AuthorizationEndpoint: "authorization_endpoint",
+		discovery.TokenEndpoint:         "token_endpoint",
+		discovery.JWKSURI:               "jwks_uri",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is missing from discovery document`, field)
+		}
+	}
+	p.discovery = discovery
+	return p, nil
+}
+
+// Discovery returns the discovered provider metadata.
+func (p *IdentityProvider) Discovery() *Discovery {
+	return p.discovery
+}
+
+// AuthCodeURL returns the URL to redirect the user to for authorization.
+// The code verifier is sent as a S256 PKCE challenge and the nonce is bound to the ID token.
+func (p *IdentityProvider) AuthCodeURL(redirectURL, state, codeVerifier, nonce string) string {
+	return p.oauth2Config(redirectURL).AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier), oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// ExchangeToken exchanges the authorization code and returns the raw ID token and the access token.
+func (p *IdentityProvider) ExchangeToken(ctx context.Context, redirectURL, code, codeVerifier string) (string, string, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+	token, err := p.oauth2Config(redirectURL).Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to exchange access token")
+	}
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return "", "", errors.New(`missing "id_token" from token response`)
+	}
+	return idToken, token.AccessToken, nil
+}
+
+// UserInfo verifies the ID token and returns the mapped user information.
+// Claims missing from the ID token are read from the userinfo endpoint when an access token is given.
+func (p *IdentityProvider) UserInfo(ctx context.Context, idToken, accessToken, nonce string) (*idp.IdentityProviderUserInfo, error) {
+	claims, err := p.VerifyIDToken(ctx, idToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken != "" && p.discovery.UserInfoEndpoint != "" {
+		userInfoClaims := map[string]any{}
+		if err := p.getJSON(ctx, p.discovery.UserInfoEndpoint, accessToken, &userInfoClaims); err != nil {
+			return nil, errors.Wrap(err, "failed to get user information")
+		}
+		// The userinfo response must be about the same subject, see OpenID Connect Core 1.0 section 5.3.2.
+		if userInfoClaims["sub"] != claims["sub"] {
+			return nil, errors.New("subject of userinfo response does not match the ID token")
+		}
+		for key, value := range userInfoClaims {
+			if _, ok := claims[key]; !ok {
+				claims[key] = value
+			}
+		}
+	}
+
+	fieldMapping := p.fieldMapping()
+	userInfo := &idp.IdentityProviderUserInfo{}
+	if v, ok := claims[fieldMapping.Identifier].(string); ok {
+		userInfo.Identifier = v
+	}
+	if userInfo.Identifier == "" {
+		return nil, errors.Errorf("the field %q is not found in claims or has empty value", fieldMapping.Identifier)
+	}
+	if v, ok := claims[fieldMapping.DisplayName].(string); ok {
+		userInfo.DisplayName = v
+	}
+	if userInfo.DisplayName == "" {
+		userInfo.DisplayName = userInfo.Identifier
+	}
+	if v, ok := claims[fieldMapping.Email].(string); ok {
+		userInfo.Email = v
+	}
+	if v, ok := claims[fieldMapping.AvatarUrl].(string); ok {
+		userInfo.AvatarURL = v
+	}
+	return userInfo, nil
+}
+
+// VerifyIDToken checks the signature of the ID token against the JWKS of the issuer,
+// along with its issuer, audience, expiry and nonce, and returns its claims.
+func (p *IdentityProvider) VerifyIDToken(ctx context.Context, idToken, nonce string) (jwt.MapClaims, error) {
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, key := range keys {
+			if kid == "" || key.kid == kid {
+				return key.publicKey, nil
+			}
+		}
+		return nil, errors.Errorf("no key found for kid %q", kid)
+	},
+		jwt.WithValidMethods(supportedSigningAlgorithms),
+		jwt.WithIssuer(p.discovery.Issuer),
+		jwt.WithAudience(p.config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+	// When the token has several audiences, it must be issued to this client, see OpenID Connect Core 1.0 section 3.1.3.7.
+	if audience, err := claims.GetAudience(); err == nil && len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientId {
+			return nil, errors.New("invalid ID token: unexpected authorized party")
+		}
+	}
+	if tokenNonce, _ := claims["nonce"].(string); nonce == "" || tokenNonce != nonce {
+		return nil, errors.New("invalid ID token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+func (p *IdentityProvider) oauth2Config(redirectURL string) *oauth2.Config {
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientId,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.discovery.AuthorizationEndpoint,
+			TokenURL: p.discovery.TokenEndpoint,
+		},
+	}
+}
+
+func (p *IdentityProvider) fieldMapping() *storepb.FieldMapping {
+	fieldMapping := &storepb.FieldMapping{
+		Identifier:  "sub",
+		DisplayName: "name",
+		Email:       "email",
+		AvatarUrl:   "picture",
+	}
+	if p.config.FieldMapping == nil {
+		return fieldMapping
+	}
+	if p.config.FieldMapping.Identifier != "" {
+		fieldMapping.Identifier = p.config.FieldMapping.Identifier
+	}
+	if p.config.FieldMapping.DisplayName != "" {
+		fieldMapping.DisplayName = p.config.FieldMapping.DisplayName
+	}
+	if p.config.FieldMapping.Email != "" {
+		fieldMapping.Email = p.config.FieldMapping.Email
+	}
+	if p.config.FieldMapping.AvatarUrl != "" {
+		fieldMapping.AvatarUrl = p.config.FieldMapping.AvatarUrl
+	}
+	return fieldMapping
+}
+
+func (p *IdentityProvider) getJSON(ctx context.Context, url, bearerToken string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to new http request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", bearerToken))
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s", url)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response body")
+	}
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type verificationKey struct {
+	kid       string
+	publicKey crypto.PublicKey
+}
+
+// fetchKeys returns the signing keys published in the JWKS of the issuer.
+// Keys of unsupported types are skipped.
+func (p *IdentityProvider) fetchKeys(ctx context.Context) ([]*verificationKey, error) {
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := p.getJSON(ctx, p.discovery.JWKSURI, "", &jwks); err != nil {
+		return nil, errors.Wrap(err, "failed to get JWKS")
+	}
+	keys := []*verificationKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		publicKey, err := parseJSONWebKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, &verificationKey{kid: jwk.Kid, publicKey: publicKey})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found in JWKS")
+	}
+	return keys, nil
+}
+
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA exponent")
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC x coordinate")
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC y coordinate")
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return publicKey, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
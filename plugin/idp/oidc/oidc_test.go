@@ -0,0 +1,120 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/oidc/oidctest"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const testRedirectURL = "https://wekalist.example.com/auth/callback"
+
+func TestNewIdentityProvider(t *testing.T) {
+	ctx := context.Background()
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+
+	_, err := NewIdentityProvider(ctx, &storepb.OIDCConfig{ClientId: "client", ClientSecret: "secret"})
+	require.ErrorContains(t, err, `the field "issuerUrl" is empty but required`)
+
+	// The issuer in the discovery document must match exactly.
+	_, err = NewIdentityProvider(ctx, &storepb.OIDCConfig{IssuerUrl: issuer.URL() + "/", ClientId: "client", ClientSecret: "secret"})
+	require.ErrorContains(t, err, "does not match")
+
+	p, err := NewIdentityProvider(ctx, &storepb.OIDCConfig{IssuerUrl: issuer.URL(), ClientId: "client", ClientSecret: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, issuer.URL()+"/token", p.Discovery().TokenEndpoint)
+}
+
+func TestIdentityProvider(t *testing.T) {
+	ctx := context.Background()
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+	issuer.Claims = map[string]any{"sub": "1234", "preferred_username": "alice"}
+	issuer.UserInfo = map[string]any{"name": "Alice", "email": "alice@example.com"}
+
+	p, err := NewIdentityProvider(ctx, &storepb.OIDCConfig{
+		IssuerUrl:    issuer.URL(),
+		ClientId:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"openid", "profile", "email"},
+		FieldMapping: &storepb.FieldMapping{Identifier: "preferred_username"},
+	})
+	require.NoError(t, err)
+
+	authorize := func(codeVerifier, nonce string) string {
+		redirect, err := issuer.Authorize(p.AuthCodeURL(testRedirectURL, "state", codeVerifier, nonce))
+		require.NoError(t, err)
+		require.Equal(t, "state", redirect.Query().Get("state"))
+		return redirect.Query().Get("code")
+	}
+
+	t.Run("sign in", func(t *testing.T) {
+		codeVerifier, nonce := oauth2.GenerateVerifier(), "nonce"
+		idToken, accessToken, err := p.ExchangeToken(ctx, testRedirectURL, authorize(codeVerifier, nonce), codeVerifier)
+		require.NoError(t, err)
+		userInfo, err := p.UserInfo(ctx, idToken, accessToken, nonce)
+		require.NoError(t, err)
+		require.Equal(t, &idp.IdentityProviderUserInfo{
+			Identifier:  "alice",
+			DisplayName: "Alice",
+			Email:       "alice@example.com",
+		}, userInfo)
+	})
+
+	t.Run("code verifier mismatch", func(t *testing.T) {
+		_, _, err := p.ExchangeToken(ctx, testRedirectURL, authorize(oauth2.GenerateVerifier(), "nonce"), oauth2.GenerateVerifier())
+		require.Error(t, err)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		codeVerifier := oauth2.GenerateVerifier()
+		idToken, _, err := p.ExchangeToken(ctx, testRedirectURL, authorize(codeVerifier, "nonce"), codeVerifier)
+		require.NoError(t, err)
+		_, err = p.VerifyIDToken(ctx, idToken, "other")
+		require.ErrorContains(t, err, "nonce mismatch")
+	})
+
+	t.Run("invalid ID tokens", func(t *testing.T) {
+		now := time.Now()
+		valid := jwt.MapClaims{"iss": issuer.URL(), "aud": "client", "sub": "1234", "nonce": "nonce", "iat": now.Unix(), "exp": now.Add(time.Hour).Unix()}
+		_, err := p.VerifyIDToken(ctx, issuer.SignIDToken(valid), "nonce")
+		require.NoError(t, err)
+
+		tests := map[string]func(jwt.MapClaims){
+			"wrong issuer":      func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			"wrong audience":    func(c jwt.MapClaims) { c["aud"] = "other" },
+			"expired":           func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+			"missing expiry":    func(c jwt.MapClaims) { delete(c, "exp") },
+			"unauthorized azp":  func(c jwt.MapClaims) { c["aud"] = []string{"client", "other"}; c["azp"] = "other" },
+			"missing the nonce": func(c jwt.MapClaims) { delete(c, "nonce") },
+		}
+		for name, mutate := range tests {
+			claims := jwt.MapClaims{}
+			for key, value := range valid {
+				claims[key] = value
+			}
+			mutate(claims)
+			_, err := p.VerifyIDToken(ctx, issuer.SignIDToken(claims), "nonce")
+			require.Error(t, err, name)
+		}
+
+		// Tokens signed by other keys are rejected.
+		other := oidctest.NewIssuer("client", "secret")
+		defer other.Close()
+		_, err = p.VerifyIDToken(ctx, other.SignIDToken(valid), "nonce")
+		require.Error(t, err)
+		// So are unsigned tokens.
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = p.VerifyIDToken(ctx, unsigned, "nonce")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,202 @@
+// Package saml is the plugin for SAML 2.0 Identity Provider.
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+// IdentityProvider represents a SAML 2.0 Identity Provider, seen from the service provider.
+type IdentityProvider struct {
+	config          *storepb.SAMLConfig
+	serviceProvider *gosaml.ServiceProvider
+}
+
+// NewIdentityProvider initializes a new SAML Identity Provider with the given configuration.
+// The metadata and ACS URLs are the absolute URLs of the service provider endpoints.
+func NewIdentityProvider(config *storepb.SAMLConfig, metadataURL, acsURL string) (*IdentityProvider, error) {
+	for v, field := range map[string]string{
+		config.IdpEntityId:    "idpEntityId",
+		config.IdpSsoUrl:      "idpSsoUrl",
+		config.IdpCertificate: "idpCertificate",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is empty but required`, field)
+		}
+	}
+	certificate, err := parseCertificate(config.IdpCertificate)
+	if err != nil {
+		return nil, err
+	}
+	parsedMetadataURL, err := url.Parse(metadataURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid metadata url")
+	}
+	parsedACSURL, err := url.Parse(acsURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid acs url")
+	}
+
+	return &IdentityProvider{
+		config: config,
+		serviceProvider: &gosaml.ServiceProvider{
+			EntityID:          config.SpEntityId,
+			MetadataURL:       *parsedMetadataURL,
+			AcsURL:            *parsedACSURL,
+			AuthnNameIDFormat: gosaml.UnspecifiedNameIDFormat,
+			IDPMetadata: &gosaml.EntityDescriptor{
+				EntityID: config.IdpEntityId,
+				IDPSSODescriptors: []gosaml.IDPSSODescriptor{
+					{
+						SSODescriptor: gosaml.SSODescriptor{
+							RoleDescriptor: gosaml.RoleDescriptor{
+								KeyDescriptors: []gosaml.KeyDescriptor{
+									{
+										Use: "signing",
+										KeyInfo: gosaml.KeyInfo{
+											X509Data: gosaml.X509Data{
+												X509Certificates: []gosaml.X509Certificate{
+													{Data: base64.StdEncoding.EncodeToString(certificate.Raw)},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						SingleSignOnServices: []gosaml.Endpoint{
+							{Binding: gosaml.HTTPRedirectBinding, Location: config.IdpSsoUrl},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// Metadata returns the XML metadata of the service provider, to be registered with the identity provider.
+func (p *IdentityProvider) Metadata() ([]byte, error) {
+	metadata, err := xml.MarshalIndent(p.serviceProvider.Metadata(), "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal metadata")
+	}
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+// AuthnRequestURL returns the URL redirecting the user to the identity provider with an authentication request,
+// using the HTTP-Redirect binding. Responses are expected in response to the given request ID.
+func (p *IdentityProvider) AuthnRequestURL(requestID, relayState string) (string, error) {
+	request, err := p.serviceProvider.MakeAuthenticationRequest(p.config.IdpSsoUrl, gosaml.HTTPRedirectBinding, gosaml.HTTPPostBinding)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to make authentication request")
+	}
+	request.ID = requestID
+	// The relay state is appended to the query as is.
+	redirectURL, err := request.Redirect(url.QueryEscape(relayState), p.serviceProvider)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode authentication request")
+	}
+	return redirectURL.String(), nil
+}
+
+// InResponseTo returns the request ID a response posted to the ACS claims to answer.
+// The response is not verified, so the ID must be checked before calling ParseResponse.
+func InResponseTo(r *http.Request) (string, error) {
+	rawResponse, err := base64.StdEncoding.DecodeString(r.PostFormValue("SAMLResponse"))
+	if err != nil {
+		return "", errors.Wrap(err, "invalid SAMLResponse")
+	}
+	response := &gosaml.Response{}
+	if err := xml.Unmarshal(rawResponse, response); err != nil {
+		return "", errors.Wrap(err, "invalid SAMLResponse")
+	}
+	return response.InResponseTo, nil
+}
+
+// ParseResponse verifies the response posted to the ACS and returns the mapped user information.
+// The response must be signed with the configured certificate and answer the given request ID.
+func (p *IdentityProvider) ParseResponse(r *http.Request, requestID string) (*idp.IdentityProviderUserInfo, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.Wrap(err, "failed to parse form")
+	}
+	if r.PostForm.Get("SAMLart") != "" {
+		return nil, errors.New("the artifact binding is not supported")
+	}
+	assertion, err := p.serviceProvider.ParseResponse(r, []string{requestID})
+	if err != nil {
+		// The error of the library hides the reason, which is useful to configure the identity provider.
+		if invalidResponseError, ok := err.(*gosaml.InvalidResponseError); ok {
+			err = invalidResponseError.PrivateErr
+		}
+		return nil, errors.Wrap(err, "invalid SAML response")
+	}
+
+	fieldMapping := p.config.FieldMapping
+	if fieldMapping == nil {
+		fieldMapping = &storepb.FieldMapping{}
+	}
+	userInfo := &idp.IdentityProviderUserInfo{
+		DisplayName: attributeValue(assertion, firstNonEmpty(fieldMapping.DisplayName, "displayName")),
+		Email:       attributeValue(assertion, firstNonEmpty(fieldMapping.Email, "email")),
+		AvatarURL:   attributeValue(assertion, fieldMapping.AvatarUrl),
+	}
+	if fieldMapping.Identifier != "" {
+		userInfo.Identifier = attributeValue(assertion, fieldMapping.Identifier)
+	} else if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		userInfo.Identifier = strings.TrimSpace(assertion.Subject.NameID.Value)
+	}
+	if userInfo.Identifier == "" {
+		return nil, errors.New("the identifier is not found in the assertion")
+	}
+	if userInfo.DisplayName == "" {
+		userInfo.DisplayName = userInfo.Identifier
+	}
+	return userInfo, nil
+}
+
+// attributeValue returns the first value of the attribute with the given name or friendly name.
+func attributeValue(assertion *gosaml.Assertion, name string) string {
+	if name == "" {
+		return ""
+	}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if (attribute.Name == name || attribute.FriendlyName == name) && len(attribute.Values) > 0 {
+				return strings.TrimSpace(attribute.Values[0].Value)
+			}
+		}
+	}
+	return ""
+}
+
+func parseCertificate(certificatePEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificatePEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("the field \"idpCertificate\" must be a PEM encoded certificate")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse idp certificate")
+	}
+	return certificate, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package saml
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/saml/samltest"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+const (
+	testMetadataURL = "http://localhost:8080/auth/saml/1/metadata"
+	testACSURL      = "http://localhost:8080/auth/saml/1/acs"
+)
+
+func TestNewIdentityProvider(t *testing.T) {
+	identityProvider := samltest.NewIdentityProvider("https://idp.example.com")
+	tests := []struct {
+		name        string
+		config      *storepb.SAMLConfig
+		containsErr string
+	}{
+		{
+			name:        "no idpEntityId",
+			config:      &storepb.SAMLConfig{IdpSsoUrl: identityProvider.SSOURL(), IdpCertificate: identityProvider.Certificate()},
+			containsErr: `the field "idpEntityId" is empty but required`,
+		},
+		{
+			name:        "no idpCertificate",
+			config:      &storepb.SAMLConfig{IdpEntityId: identityProvider.EntityID(), IdpSsoUrl: identityProvider.SSOURL()},
+			containsErr: `the field "idpCertificate" is empty but required`,
+		},
+		{
+			name:        "invalid idpCertificate",
+			config:      &storepb.SAMLConfig{IdpEntityId: identityProvider.EntityID(), IdpSsoUrl: identityProvider.SSOURL(), IdpCertificate: "certificate"},
+			containsErr: `the field "idpCertificate" must be a PEM encoded certificate`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewIdentityProvider(test.config, testMetadataURL, testACSURL)
+			require.ErrorContains(t, err, test.containsErr)
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	identityProvider := samltest.NewIdentityProvider("https://idp.example.com")
+	identityProvider.Session.NameID = "alice"
+	identityProvider.Session.CustomAttributes = []gosaml.Attribute{
+		{Name: "displayName", Values: []gosaml.AttributeValue{{Value: "Alice"}}},
+		{Name: "email", Values: []gosaml.AttributeValue{{Value: "alice@example.com"}}},
+	}
+	config := &storepb.SAMLConfig{
+		IdpEntityId:    identityProvider.EntityID(),
+		IdpSsoUrl:      identityProvider.SSOURL(),
+		IdpCertificate: identityProvider.Certificate(),
+	}
+	p, err := NewIdentityProvider(config, testMetadataURL, testACSURL)
+	require.NoError(t, err)
+	metadata, err := p.Metadata()
+	require.NoError(t, err)
+	require.Contains(t, string(metadata), `entityID="`+testMetadataURL+`"`)
+	require.Contains(t, string(metadata), `Location="`+testACSURL+`"`)
+	require.NoError(t, identityProvider.RegisterServiceProvider(metadata))
+
+	respond := func(identityProvider *samltest.IdentityProvider, requestID string) *http.Request {
+		authnRequestURL, err := p.AuthnRequestURL(requestID, "auth.signin.SAML-1")
+		require.NoError(t, err)
+		acsURL, form, err := identityProvider.Respond(authnRequestURL)
+		require.NoError(t, err)
+		require.Equal(t, testACSURL, acsURL)
+		require.Equal(t, "auth.signin.SAML-1", form.Get("RelayState"))
+		return newPostRequest(acsURL, form)
+	}
+
+	t.Run("maps the assertion", func(t *testing.T) {
+		request := respond(identityProvider, "id-1")
+		requestID, err := InResponseTo(request)
+		require.NoError(t, err)
+		require.Equal(t, "id-1", requestID)
+		userInfo, err := p.ParseResponse(request, requestID)
+		require.NoError(t, err)
+		require.Equal(t, &idp.IdentityProviderUserInfo{
+			Identifier:  "alice",
+			DisplayName: "Alice",
+			Email:       "alice@example.com",
+		}, userInfo)
+	})
+
+	t.Run("maps configured attributes", func(t *testing.T) {
+		p, err := NewIdentityProvider(&storepb.SAMLConfig{
+			IdpEntityId:    identityProvider.EntityID(),
+			IdpSsoUrl:      identityProvider.SSOURL(),
+			IdpCertificate: identityProvider.Certificate(),
+			FieldMapping:   &storepb.FieldMapping{Identifier: "email", DisplayName: "cn"},
+		}, testMetadataURL, testACSURL)
+		require.NoError(t, err)
+		userInfo, err := p.ParseResponse(respond(identityProvider, "id-2"), "id-2")
+		require.NoError(t, err)
+		require.Equal(t, "alice@example.com", userInfo.Identifier)
+		require.Equal(t, "alice@example.com", userInfo.DisplayName)
+	})
+
+	t.Run("rejects responses to other requests", func(t *testing.T) {
+		_, err := p.ParseResponse(respond(identityProvider, "id-3"), "id-4")
+		require.ErrorContains(t, err, "InResponseTo")
+	})
+
+	t.Run("rejects responses signed with another key", func(t *testing.T) {
+		impostor := samltest.NewIdentityProvider("https://idp.example.com")
+		impostor.Session.NameID = "alice"
+		require.NoError(t, impostor.RegisterServiceProvider(metadata))
+		_, err := p.ParseResponse(respond(impostor, "id-5"), "id-5")
+		require.ErrorContains(t, err, "cannot validate signature")
+	})
+
+	t.Run("rejects altered responses", func(t *testing.T) {
+		request := respond(identityProvider, "id-6")
+		rawResponse, err := base64.StdEncoding.DecodeString(request.PostFormValue("SAMLResponse"))
+		require.NoError(t, err)
+		alteredResponse := strings.ReplaceAll(string(rawResponse), ">alice<", ">mallory<")
+		require.NotEqual(t, string(rawResponse), alteredResponse)
+		_, err = p.ParseResponse(newPostRequest(testACSURL, url.Values{
+			"SAMLResponse": {base64.StdEncoding.EncodeToString([]byte(alteredResponse))},
+		}), "id-6")
+		require.Error(t, err)
+	})
+}
+
+func newPostRequest(target string, form url.Values) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return request
+}
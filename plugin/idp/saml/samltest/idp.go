@@ -0,0 +1,143 @@
+// Package samltest provides a SAML 2.0 identity provider for tests.
+// It answers authentication requests sent with the HTTP-Redirect binding
+// with responses signed by a generated key, as posted to the ACS by the browser.
+package samltest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/pkg/errors"
+)
+
+// IdentityProvider is an identity provider signing in every request as the user of its session.
+type IdentityProvider struct {
+	// Session is the user signed in at the identity provider.
+	Session *gosaml.Session
+
+	identityProvider *gosaml.IdentityProvider
+	certificate      *x509.Certificate
+
+	mu               sync.Mutex
+	serviceProviders map[string]*gosaml.EntityDescriptor
+}
+
+// NewIdentityProvider creates an identity provider with the given entity ID and a new signing key.
+// The single sign-on URL is the entity ID followed by /sso.
+func NewIdentityProvider(entityID string) *IdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "samltest"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	metadataURL, err := url.Parse(entityID)
+	if err != nil {
+		panic(err)
+	}
+	ssoURL := metadataURL.JoinPath("sso")
+
+	p := &IdentityProvider{
+		Session: &gosaml.Session{
+			CreateTime: time.Now(),
+			ExpireTime: time.Now().Add(time.Hour),
+			Index:      "1",
+		},
+		certificate:      certificate,
+		serviceProviders: map[string]*gosaml.EntityDescriptor{},
+	}
+	p.identityProvider = &gosaml.IdentityProvider{
+		Key:                     key,
+		Certificate:             certificate,
+		MetadataURL:             *metadataURL,
+		SSOURL:                  *ssoURL,
+		ServiceProviderProvider: p,
+	}
+	return p
+}
+
+// EntityID returns the entity ID of the identity provider.
+func (p *IdentityProvider) EntityID() string {
+	return p.identityProvider.MetadataURL.String()
+}
+
+// SSOURL returns the single sign-on URL of the identity provider.
+func (p *IdentityProvider) SSOURL() string {
+	return p.identityProvider.SSOURL.String()
+}
+
+// Certificate returns the PEM encoded signing certificate of the identity provider.
+func (p *IdentityProvider) Certificate() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.certificate.Raw}))
+}
+
+// RegisterServiceProvider registers the XML metadata of a service provider.
+func (p *IdentityProvider) RegisterServiceProvider(metadata []byte) error {
+	entityDescriptor := &gosaml.EntityDescriptor{}
+	if err := xml.Unmarshal(metadata, entityDescriptor); err != nil {
+		return errors.Wrap(err, "invalid metadata")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.serviceProviders[entityDescriptor.EntityID] = entityDescriptor
+	return nil
+}
+
+// GetServiceProvider implements gosaml.ServiceProviderProvider.
+func (p *IdentityProvider) GetServiceProvider(_ *http.Request, serviceProviderID string) (*gosaml.EntityDescriptor, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entityDescriptor, ok := p.serviceProviders[serviceProviderID]; ok {
+		return entityDescriptor, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+// Respond answers the authentication request of the redirect URL and returns the ACS URL
+// with the form the browser would post to it.
+func (p *IdentityProvider) Respond(authnRequestURL string) (string, url.Values, error) {
+	request := httptest.NewRequest(http.MethodGet, authnRequestURL, nil)
+	authnRequest, err := gosaml.NewIdpAuthnRequest(p.identityProvider, request)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := authnRequest.Validate(); err != nil {
+		return "", nil, err
+	}
+	if err := (gosaml.DefaultAssertionMaker{}).MakeAssertion(authnRequest, p.Session); err != nil {
+		return "", nil, err
+	}
+	form, err := authnRequest.PostBinding()
+	if err != nil {
+		return "", nil, err
+	}
+	return form.URL, url.Values{
+		"SAMLResponse": {form.SAMLResponse},
+		"RelayState":   {form.RelayState},
+	}, nil
+}
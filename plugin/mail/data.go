@@ -0,0 +1,63 @@
+package mail
+
+// OTPData is the data of TemplateOTP.
+type OTPData struct {
+	Code string
+	// Purpose is "login", "password_reset", "verification" or "registration".
+	Purpose          string
+	ExpiresInMinutes int
+}
+
+// NotificationData is the data of TemplateNotification.
+type NotificationData struct {
+	Items []NotificationItem
+	// MoreCount is the number of notifications not listed.
+	MoreCount int
+	// EventTypes are the types of the notifications, such as "comment" or "version_update".
+	EventTypes []string
+	// UnsubscribeURL is the link turning these emails off, or empty if the instance URL is not set.
+	UnsubscribeURL string
+}
+
+type NotificationItem struct {
+	Title string
+	Body  string
+	URL   string
+}
+
+// Count returns the number of notifications, listed or not.
+func (d NotificationData) Count() int {
+	return len(d.Items) + d.MoreCount
+}
+
+// DigestData is the data of TemplateDigest.
+type DigestData struct {
+	// Frequency is "daily" or "weekly".
+	Frequency string
+	// URL is the instance URL, or empty if it is not set.
+	URL      string
+	Memos    DigestSection
+	Comments DigestSection
+	Inbox    DigestSection
+}
+
+// DigestSection lists the first items of a kind and counts the others.
+type DigestSection struct {
+	Items     []DigestItem
+	MoreCount int
+}
+
+type DigestItem struct {
+	// Kind is "memo", "comment", "reaction", "mention" or "version_update".
+	Kind string
+	// Actor is the name of the user the item is from.
+	Actor string
+	// Body quotes the memo of the item.
+	Body string
+	URL  string
+}
+
+// IsEmpty reports whether the digest lists nothing.
+func (d *DigestData) IsEmpty() bool {
+	return len(d.Memos.Items) == 0 && len(d.Comments.Items) == 0 && len(d.Inbox.Items) == 0
+}
@@ -0,0 +1,179 @@
+package mail
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DKIMOptions signs emails with DKIM, see RFC 6376.
+type DKIMOptions struct {
+	Domain   string
+	Selector string
+	// Signer is the RSA or Ed25519 private key of the selector.
+	Signer crypto.Signer
+}
+
+// dkimSignedHeaders are the headers signed when present.
+var dkimSignedHeaders = []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "List-Unsubscribe", "List-Unsubscribe-Post"}
+
+// ParseDKIMPrivateKey parses a PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519 (PKCS #8) private key.
+func ParseDKIMPrivateKey(pemKey string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(pemKey)))
+	if block == nil {
+		return nil, errors.New("DKIM private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse DKIM private key")
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("unsupported DKIM private key type %T", key)
+	}
+}
+
+// SignDKIM returns the message with a DKIM-Signature header, using the relaxed canonicalization of
+// both the header and the body.
+func SignDKIM(message []byte, options *DKIMOptions) ([]byte, error) {
+	return signDKIM(message, options, time.Now())
+}
+
+func signDKIM(message []byte, options *DKIMOptions, now time.Time) ([]byte, error) {
+	if options.Domain == "" || options.Selector == "" || options.Signer == nil {
+		return nil, errors.New("DKIM requires a domain, a selector and a private key")
+	}
+	var algorithm string
+	var hash crypto.Hash
+	switch options.Signer.(type) {
+	case *rsa.PrivateKey:
+		algorithm, hash = "rsa-sha256", crypto.SHA256
+	case ed25519.PrivateKey:
+		// Ed25519 signs the SHA-256 digest itself, see RFC 8463.
+		algorithm, hash = "ed25519-sha256", crypto.Hash(0)
+	default:
+		return nil, errors.Errorf("unsupported DKIM private key type %T", options.Signer)
+	}
+
+	index := bytes.Index(message, []byte("\r\n\r\n"))
+	if index < 0 {
+		return nil, errors.New("email has no body")
+	}
+	fields := splitHeaderFields(string(message[:index+2]))
+	body := message[index+4:]
+
+	var signedHeader strings.Builder
+	signedNames := []string{}
+	for _, name := range dkimSignedHeaders {
+		// The last field of a name is signed first, see RFC 6376 section 5.4.2.
+		for i := len(fields) - 1; i >= 0; i-- {
+			fieldName, value, _ := strings.Cut(fields[i], ":")
+			if strings.EqualFold(strings.TrimRight(fieldName, " \t"), name) {
+				signedHeader.WriteString(canonicalizeHeaderRelaxed(name, value) + "\r\n")
+				signedNames = append(signedNames, strings.ToLower(name))
+				break
+			}
+		}
+	}
+	bodyHash := sha256.Sum256(canonicalizeBodyRelaxed(body))
+	tags := []string{
+		"v=1",
+		"a=" + algorithm,
+		"c=relaxed/relaxed",
+		"d=" + options.Domain,
+		"s=" + options.Selector,
+		fmt.Sprintf("t=%d", now.Unix()),
+		"h=" + strings.Join(signedNames, ":"),
+		"bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]),
+		"b=",
+	}
+	// The signature header is signed last, with an empty signature and without its line break.
+	signedHeader.WriteString(canonicalizeHeaderRelaxed("DKIM-Signature", " "+strings.Join(tags, "; ")))
+	digest := sha256.Sum256([]byte(signedHeader.String()))
+	signature, err := options.Signer.Sign(rand.Reader, digest[:], hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign email with DKIM")
+	}
+	tags[len(tags)-1] = "b=" + base64.StdEncoding.EncodeToString(signature)
+
+	var signed bytes.Buffer
+	signed.WriteString("DKIM-Signature: " + strings.Join(tags, ";\r\n\t") + "\r\n")
+	signed.Write(message)
+	return signed.Bytes(), nil
+}
+
+// splitHeaderFields splits the header into its fields, keeping the line breaks of folded fields.
+func splitHeaderFields(header string) []string {
+	fields := []string{}
+	for _, line := range strings.SplitAfter(header, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	for i, field := range fields {
+		fields[i] = strings.TrimSuffix(field, "\r\n")
+	}
+	return fields
+}
+
+// canonicalizeHeaderRelaxed canonicalizes a header field, see RFC 6376 section 3.4.2.
+func canonicalizeHeaderRelaxed(name, value string) string {
+	value = compressWhitespace(strings.ReplaceAll(value, "\r\n", ""))
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Trim(value, " ")
+}
+
+// canonicalizeBodyRelaxed canonicalizes a body, see RFC 6376 section 3.4.4.
+func canonicalizeBodyRelaxed(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(compressWhitespace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// compressWhitespace replaces each run of spaces and tabs with a single space.
+func compressWhitespace(s string) string {
+	var builder strings.Builder
+	inWhitespace := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' || s[i] == '\t' {
+			if !inWhitespace {
+				builder.WriteByte(' ')
+			}
+			inWhitespace = true
+			continue
+		}
+		builder.WriteByte(s[i])
+		inWhitespace = false
+	}
+	return builder.String()
+}
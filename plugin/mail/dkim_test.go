@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDKIMCanonicalization(t *testing.T) {
+	// The example of RFC 6376 section 3.4.5.
+	require.Equal(t, "a:X", canonicalizeHeaderRelaxed("A", " X"))
+	require.Equal(t, "b:Y Z", canonicalizeHeaderRelaxed("B ", " Y\t\r\n\tZ  "))
+	require.Equal(t, " C\r\nD E\r\n", string(canonicalizeBodyRelaxed([]byte(" C \r\nD \t E\r\n\r\n\r\n"))))
+	require.Empty(t, canonicalizeBodyRelaxed([]byte("\r\n\r\n")))
+	require.Equal(t, "body\r\n", string(canonicalizeBodyRelaxed([]byte("body"))))
+
+	require.Equal(t, []string{"A: X", "B : Y\t\r\n\tZ  "}, splitHeaderFields("A: X\r\nB : Y\t\r\n\tZ  \r\n"))
+}
+
+func TestParseDKIMPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	pkcs8Ed25519, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	require.NoError(t, err)
+
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8RSA},
+		{Type: "PRIVATE KEY", Bytes: pkcs8Ed25519},
+	} {
+		signer, err := ParseDKIMPrivateKey(string(pem.EncodeToMemory(block)))
+		require.NoError(t, err)
+		require.NotNil(t, signer)
+	}
+	_, err = ParseDKIMPrivateKey("not a key")
+	require.Error(t, err)
+	_, err = ParseDKIMPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})))
+	require.Error(t, err)
+}
+
+func TestSignDKIM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	message := &Message{
+		From:     "wekalist@example.com",
+		To:       []string{"alice@example.com"},
+		Subject:  "Hello",
+		TextBody: "Hello  \t world\n\n\n",
+		HTMLBody: "<p>Hello</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<http://localhost:8080/unsubscribe>"},
+	}
+	messageBytes, err := message.Bytes()
+	require.NoError(t, err)
+
+	for algorithm, signer := range map[string]crypto.Signer{"rsa-sha256": rsaKey, "ed25519-sha256": ed25519Key} {
+		signed, err := signDKIM(messageBytes, &DKIMOptions{Domain: "example.com", Selector: "wekalist", Signer: signer}, time.Unix(1700000000, 0))
+		require.NoError(t, err)
+		require.True(t, bytes.HasSuffix(signed, messageBytes))
+		tags := verifyDKIM(t, signed, signer.Public())
+		require.Equal(t, algorithm, tags["a"])
+		require.Equal(t, "example.com", tags["d"])
+		require.Equal(t, "wekalist", tags["s"])
+		require.Equal(t, "1700000000", tags["t"])
+		require.Equal(t, "from:to:subject:date:message-id:mime-version:content-type:list-unsubscribe", tags["h"])
+
+		// Changing the body or a signed header breaks the signature.
+		tampered := bytes.Replace(signed, []byte("<p>Hello</p>"), []byte("<p>Hallo</p>"), 1)
+		_, bodyHash := getDKIMBodyHash(tampered)
+		require.NotEqual(t, tags["bh"], bodyHash)
+	}
+
+	_, err = SignDKIM(messageBytes, &DKIMOptions{Domain: "example.com", Signer: rsaKey})
+	require.Error(t, err)
+}
+
+// verifyDKIM checks the DKIM signature of the signed message, the first header field, and returns its tags.
+func verifyDKIM(t *testing.T, signed []byte, publicKey crypto.PublicKey) map[string]string {
+	index := bytes.Index(signed, []byte("\r\n\r\n"))
+	require.Positive(t, index)
+	fields := splitHeaderFields(string(signed[:index+2]))
+	name, value, _ := strings.Cut(fields[0], ":")
+	require.Equal(t, "DKIM-Signature", name)
+	tags := map[string]string{}
+	for _, tag := range strings.Split(value, ";") {
+		key, tagValue, _ := strings.Cut(strings.TrimSpace(tag), "=")
+		tags[key] = tagValue
+	}
+
+	message, bodyHash := getDKIMBodyHash(signed[len(fields[0])+2:])
+	require.Equal(t, tags["bh"], bodyHash)
+	messageFields := splitHeaderFields(string(message[:bytes.Index(message, []byte("\r\n\r\n"))+2]))
+	var signedHeader strings.Builder
+	for _, signedName := range strings.Split(tags["h"], ":") {
+		for _, field := range messageFields {
+			fieldName, fieldValue, _ := strings.Cut(field, ":")
+			if strings.EqualFold(fieldName, signedName) {
+				signedHeader.WriteString(canonicalizeHeaderRelaxed(fieldName, fieldValue) + "\r\n")
+			}
+		}
+	}
+	signatureValue := regexp.MustCompile(`b=[^;]*$`).ReplaceAllString(value, "b=")
+	signedHeader.WriteString(canonicalizeHeaderRelaxed(name, signatureValue))
+	digest := sha256.Sum256([]byte(signedHeader.String()))
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	require.NoError(t, err)
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		require.NoError(t, rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature))
+	case ed25519.PublicKey:
+		require.True(t, ed25519.Verify(publicKey, digest[:], signature))
+	default:
+		t.Fatalf("unexpected public key %T", publicKey)
+	}
+	return tags
+}
+
+// getDKIMBodyHash returns the message without its signature and the hash of its body.
+func getDKIMBodyHash(message []byte) ([]byte, string) {
+	if bytes.HasPrefix(message, []byte("DKIM-Signature:")) {
+		fields := splitHeaderFields(string(message[:bytes.Index(message, []byte("\r\n\r\n"))+2]))
+		message = message[len(fields[0])+2:]
+	}
+	hash := sha256.Sum256(canonicalizeBodyRelaxed(message[bytes.Index(message, []byte("\r\n\r\n"))+4:]))
+	return message, base64.StdEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,125 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Message is an email with a plain text body and an HTML body, sent as multipart/alternative.
+type Message struct {
+	From     string
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	// Headers are additional headers of the message, such as List-Unsubscribe.
+	Headers map[string]string
+}
+
+// Validate checks that the message can be sent.
+func (m *Message) Validate() error {
+	if m.From == "" {
+		return errors.New("sender email address is required")
+	}
+	if len(m.To) == 0 {
+		return errors.New("recipient email address is required")
+	}
+	if m.Subject == "" {
+		return errors.New("email subject is required")
+	}
+	if m.TextBody == "" || m.HTMLBody == "" {
+		return errors.New("email body is required")
+	}
+	for _, value := range append([]string{m.From, m.Subject}, m.To...) {
+		if strings.ContainsAny(value, "\r\n") {
+			return errors.New("email headers must not contain line breaks")
+		}
+	}
+	for key, value := range m.Headers {
+		if strings.ContainsAny(key+value, "\r\n") {
+			return errors.New("email headers must not contain line breaks")
+		}
+	}
+	return nil
+}
+
+// Bytes formats the message as defined by RFC 5322, with both bodies quoted-printable encoded.
+func (m *Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		// The preferred alternative comes last, see RFC 2046 section 5.1.4.
+		{contentType: "text/plain; charset=UTF-8", content: m.TextBody},
+		{contentType: "text/html; charset=UTF-8", content: m.HTMLBody},
+	} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create message part")
+		}
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, errors.Wrap(err, "failed to write message part")
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, errors.Wrap(err, "failed to write message part")
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write message")
+	}
+
+	var message bytes.Buffer
+	writeHeader := func(key, value string) {
+		message.WriteString(key + ": " + value + "\r\n")
+	}
+	writeHeader("From", m.From)
+	writeHeader("To", strings.Join(m.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("Message-ID", newMessageID(m.From))
+	headerKeys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		headerKeys = append(headerKeys, key)
+	}
+	sort.Strings(headerKeys)
+	for _, key := range headerKeys {
+		writeHeader(key, m.Headers[key])
+	}
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", writer.Boundary()))
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+// newMessageID returns a unique Message-ID in the domain of the sender.
+func newMessageID(from string) string {
+	domain := "localhost"
+	if index := strings.LastIndex(from, "@"); index >= 0 {
+		domain = strings.TrimSuffix(from[index+1:], ">")
+	}
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
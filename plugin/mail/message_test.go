@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBytes(t *testing.T) {
+	message := &Message{
+		From:     "wekalist@example.com",
+		To:       []string{"alice@example.com", "bob@example.com"},
+		Subject:  "Votre résumé",
+		TextBody: "Bonjour, voici une ligne très longue " + strings.Repeat("=", 100) + "\n",
+		HTMLBody: "<p>Bonjour</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<http://localhost:8080/unsubscribe>"},
+	}
+	messageBytes, err := message.Bytes()
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(messageBytes))
+	require.NoError(t, err)
+	require.Equal(t, "wekalist@example.com", parsed.Header.Get("From"))
+	require.Equal(t, "alice@example.com, bob@example.com", parsed.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Votre résumé", subject)
+	require.Equal(t, "<http://localhost:8080/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+	require.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>"))
+	_, err = parsed.Header.Date()
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, expected := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=UTF-8", content: message.TextBody},
+		{contentType: "text/html; charset=UTF-8", content: message.HTMLBody},
+	} {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		require.Equal(t, expected.contentType, part.Header.Get("Content-Type"))
+		// The multipart reader decodes quoted-printable parts, whose line breaks are CRLF.
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		require.Equal(t, strings.ReplaceAll(expected.content, "\n", "\r\n"), string(content))
+	}
+	_, err = reader.NextPart()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestMessageValidate(t *testing.T) {
+	valid := func() *Message {
+		return &Message{From: "wekalist@example.com", To: []string{"alice@example.com"}, Subject: "Subject", TextBody: "text", HTMLBody: "<p>html</p>"}
+	}
+	require.NoError(t, valid().Validate())
+	for _, update := range []func(*Message){
+		func(m *Message) { m.From = "" },
+		func(m *Message) { m.To = nil },
+		func(m *Message) { m.Subject = "" },
+		func(m *Message) { m.TextBody = "" },
+		func(m *Message) { m.HTMLBody = "" },
+		func(m *Message) { m.Subject = "Subject\r\nBcc: eve@example.com" },
+		func(m *Message) { m.To = []string{"alice@example.com\nBcc: eve@example.com"} },
+		func(m *Message) { m.Headers = map[string]string{"X-Test": "a\r\nb"} },
+	} {
+		message := valid()
+		update(message)
+		require.Error(t, message.Validate())
+		_, err := message.Bytes()
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,181 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Security is how the connection to the SMTP server is secured.
+type Security string
+
+const (
+	// SecurityStartTLS upgrades the connection with STARTTLS, usually on port 587.
+	SecurityStartTLS Security = "starttls"
+	// SecurityTLS connects over TLS, usually on port 465.
+	SecurityTLS Security = "tls"
+	// SecurityPlain does not encrypt the connection, for relays on a trusted network.
+	SecurityPlain Security = "plain"
+)
+
+// defaultSMTPTimeout bounds sending an email, from connecting to the SMTP server to its reply to the message.
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPConfig configures the SMTP server the emails are sent through.
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password authenticate with PLAIN, which net/smtp only allows over TLS or to localhost.
+	// No authentication happens if Username is empty.
+	Username string
+	Password string
+	// From is the sender email address.
+	From string
+	// Security defaults to SecurityStartTLS.
+	Security Security
+	// DKIM, if set, signs the emails.
+	DKIM *DKIMOptions
+	// TLSConfig, if set, is the TLS configuration of the connection, such as the root CAs trusted by tests.
+	// The server name defaults to Host.
+	TLSConfig *tls.Config
+	// Timeout defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// Validate checks that emails can be sent with the configuration.
+func (c *SMTPConfig) Validate() error {
+	if c.Host == "" {
+		return errors.New("SMTP host is required")
+	}
+	if c.Port <= 0 {
+		return errors.New("SMTP port is required")
+	}
+	if c.From == "" {
+		return errors.New("SMTP email is required")
+	}
+	if c.Username != "" && c.Password == "" {
+		return errors.New("SMTP password is required")
+	}
+	switch c.Security {
+	case "", SecurityStartTLS, SecurityTLS, SecurityPlain:
+	default:
+		return errors.Errorf("unknown SMTP security %q", c.Security)
+	}
+	return nil
+}
+
+// Send sends the message from the sender of the configuration, signed with DKIM if configured.
+func Send(ctx context.Context, config *SMTPConfig, message *Message) error {
+	if err := config.Validate(); err != nil {
+		return errors.Wrap(err, "invalid SMTP configuration")
+	}
+	signed := *message
+	signed.From = config.From
+	data, err := signed.Bytes()
+	if err != nil {
+		return errors.Wrap(err, "invalid email")
+	}
+	if config.DKIM != nil {
+		if data, err = SignDKIM(data, config.DKIM); err != nil {
+			return err
+		}
+	}
+	return deliver(ctx, config, signed.From, signed.To, data)
+}
+
+// IsPermanent reports whether the error is a permanent rejection of the SMTP server (a 5xx reply),
+// so sending the same email again fails the same way.
+func IsPermanent(err error) bool {
+	var protocolErr *textproto.Error
+	return errors.As(err, &protocolErr) && protocolErr.Code >= 500
+}
+
+func deliver(ctx context.Context, config *SMTPConfig, from string, to []string, data []byte) error {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultSMTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.Port)))
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to SMTP server")
+	}
+	// net/smtp has no context, so the deadline of the connection bounds the whole exchange.
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to set SMTP connection deadline")
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	tlsConfig := &tls.Config{ServerName: config.Host}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = config.Host
+		}
+	}
+	if config.Security == SecurityTLS {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return errors.Wrap(err, "failed to connect to SMTP server over TLS")
+		}
+		conn = tlsConn
+	}
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to connect to SMTP server")
+	}
+	defer client.Close()
+
+	if config.Security == "" || config.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return errors.Wrap(err, "failed to start TLS")
+		}
+	}
+	if config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return errors.Wrap(err, "failed to authenticate")
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return errors.Wrap(err, "failed to set sender")
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return errors.Wrapf(err, "failed to set recipient %s", recipient)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to start message")
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return errors.Wrap(err, "failed to write message")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "failed to send message")
+	}
+	// The message is accepted at this point, a failing QUIT does not make it undelivered.
+	_ = client.Quit()
+	return nil
+}
@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/plugin/mail/smtptest"
+)
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	message := &Message{
+		To:       []string{"alice@example.com"},
+		Subject:  "Hello",
+		TextBody: ".Hello\n",
+		HTMLBody: "<p>Hello</p>",
+	}
+	for _, test := range []struct {
+		mode     smtptest.Mode
+		security Security
+	}{
+		{mode: smtptest.ModePlain, security: SecurityPlain},
+		{mode: smtptest.ModeStartTLS, security: SecurityStartTLS},
+		{mode: smtptest.ModeStartTLS, security: ""},
+		{mode: smtptest.ModeTLS, security: SecurityTLS},
+	} {
+		server := smtptest.NewServer(test.mode)
+		config := &SMTPConfig{
+			Host:      server.Host,
+			Port:      server.Port,
+			Username:  "wekalist",
+			Password:  "secret",
+			From:      "wekalist@example.com",
+			Security:  test.security,
+			TLSConfig: server.ClientTLSConfig(),
+		}
+		require.NoError(t, Send(ctx, config, message), test.security)
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		received := messages[0]
+		require.Equal(t, "wekalist", received.Username)
+		require.Equal(t, test.mode != smtptest.ModePlain, received.TLS)
+		require.Equal(t, "wekalist@example.com", received.From)
+		require.Equal(t, []string{"alice@example.com"}, received.To)
+		parsed, err := mail.ReadMessage(bytes.NewReader(received.Data))
+		require.NoError(t, err)
+		require.Equal(t, "wekalist@example.com", parsed.Header.Get("From"))
+		// The message of the caller is left as is.
+		require.Empty(t, message.From)
+		server.Close()
+	}
+}
+
+func TestSendFailures(t *testing.T) {
+	ctx := context.Background()
+	message := &Message{To: []string{"alice@example.com"}, Subject: "Hello", TextBody: "Hello", HTMLBody: "<p>Hello</p>"}
+
+	// STARTTLS is required unless the connection is plain.
+	server := smtptest.NewServer(smtptest.ModePlain)
+	defer server.Close()
+	config := &SMTPConfig{Host: server.Host, Port: server.Port, From: "wekalist@example.com", TLSConfig: server.ClientTLSConfig()}
+	require.ErrorContains(t, Send(ctx, config, message), "STARTTLS")
+
+	config.Security = SecurityPlain
+	server.Reject(451, 550)
+	err := Send(ctx, config, message)
+	require.Error(t, err)
+	require.False(t, IsPermanent(err))
+	err = Send(ctx, config, message)
+	require.Error(t, err)
+	require.True(t, IsPermanent(err))
+	require.NoError(t, Send(ctx, config, message))
+	require.Len(t, server.Messages(), 1)
+
+	// A server not answering times out.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	config.Port = listener.Addr().(*net.TCPAddr).Port
+	config.Timeout = 100 * time.Millisecond
+	err = Send(ctx, config, message)
+	require.Error(t, err)
+	require.False(t, IsPermanent(err))
+
+	require.Error(t, Send(ctx, &SMTPConfig{Host: server.Host, Port: server.Port}, message))
+	require.Error(t, Send(ctx, &SMTPConfig{Host: server.Host, Port: server.Port, From: "wekalist@example.com", Security: "ssl"}, message))
+}
+
+func TestSendWithDKIM(t *testing.T) {
+	server := smtptest.NewServer(smtptest.ModeTLS)
+	defer server.Close()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	config := &SMTPConfig{
+		Host:      server.Host,
+		Port:      server.Port,
+		From:      "wekalist@example.com",
+		Security:  SecurityTLS,
+		TLSConfig: server.ClientTLSConfig(),
+		DKIM:      &DKIMOptions{Domain: "example.com", Selector: "wekalist", Signer: privateKey},
+	}
+	message := &Message{To: []string{"alice@example.com"}, Subject: "Hello", TextBody: ".Hello", HTMLBody: "<p>Hello</p>"}
+	require.NoError(t, Send(context.Background(), config, message))
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	tags := verifyDKIM(t, messages[0].Data, publicKey)
+	require.Equal(t, "example.com", tags["d"])
+	require.Equal(t, "wekalist", tags["s"])
+}
@@ -0,0 +1,290 @@
+// Package smtptest provides an in-process SMTP server for tests, in the spirit of net/http/httptest.
+package smtptest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode is how the server secures its connections.
+type Mode int
+
+const (
+	// ModePlain accepts unencrypted connections only.
+	ModePlain Mode = iota
+	// ModeStartTLS accepts unencrypted connections upgraded with STARTTLS.
+	ModeStartTLS
+	// ModeTLS accepts TLS connections only.
+	ModeTLS
+)
+
+// Message is a message received by the server.
+type Message struct {
+	// Username is the user authenticated with AUTH PLAIN, if any.
+	Username string
+	// TLS reports whether the message was received over an encrypted connection.
+	TLS  bool
+	From string
+	To   []string
+	// Data is the message as sent, dot-unstuffed, with CRLF line breaks.
+	Data []byte
+}
+
+// Server is an SMTP server listening on a local port.
+type Server struct {
+	// Host and Port are the address of the server.
+	Host string
+	Port int
+
+	mode      Mode
+	listener  net.Listener
+	tlsConfig *tls.Config
+	roots     *x509.CertPool
+
+	mu       sync.Mutex
+	messages []*Message
+	// failures are the reply codes of the next messages to reject.
+	failures []int
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server in the mode on a local port. It panics if it cannot start.
+func NewServer(mode Mode) *Server {
+	certificate, roots := newCertificate()
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}}
+	var listener net.Listener
+	var err error
+	if mode == ModeTLS {
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		panic(fmt.Sprintf("smtptest: failed to listen: %v", err))
+	}
+	address := listener.Addr().(*net.TCPAddr)
+	s := &Server{
+		Host:      address.IP.String(),
+		Port:      address.Port,
+		mode:      mode,
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		roots:     roots,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// ClientTLSConfig returns a TLS configuration trusting the certificate of the server.
+func (s *Server) ClientTLSConfig() *tls.Config {
+	return &tls.Config{RootCAs: s.roots}
+}
+
+// Messages returns the messages received so far.
+func (s *Server) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Message(nil), s.messages...)
+}
+
+// Reject makes the server reply to the data of the next messages with the codes, one code per message,
+// such as 451 for a temporary failure or 550 for a permanent one.
+func (s *Server) Reject(codes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, codes...)
+}
+
+// Close stops the server and waits for its connections to end.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle serves a connection until QUIT, implementing the subset of SMTP used by net/smtp.
+func (s *Server) handle(conn net.Conn) {
+	reader, writer := bufio.NewReader(conn), bufio.NewWriter(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			writer.WriteString(line + "\r\n")
+		}
+		writer.Flush()
+	}
+	isTLS := s.mode == ModeTLS
+	username := ""
+	message := &Message{}
+
+	reply("220 localhost ESMTP smtptest")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			lines := []string{"250-localhost"}
+			if s.mode == ModeStartTLS && !isTLS {
+				lines = append(lines, "250-STARTTLS")
+			}
+			reply(append(lines, "250-8BITMIME", "250 AUTH PLAIN")...)
+		case "HELO":
+			reply("250 localhost")
+		case "STARTTLS":
+			if s.mode != ModeStartTLS || isTLS {
+				reply("502 STARTTLS not available")
+				continue
+			}
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, isTLS = tlsConn, true
+			reader, writer = bufio.NewReader(conn), bufio.NewWriter(conn)
+		case "AUTH":
+			mechanism, response, _ := strings.Cut(argument, " ")
+			if !strings.EqualFold(mechanism, "PLAIN") {
+				reply("504 Unrecognized authentication type")
+				continue
+			}
+			if response == "" {
+				reply("334 ")
+				if response, err = reader.ReadString('\n'); err != nil {
+					return
+				}
+			}
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(response))
+			parts := strings.Split(string(decoded), "\x00")
+			if err != nil || len(parts) != 3 {
+				reply("501 Invalid credentials")
+				continue
+			}
+			username = parts[1]
+			reply("235 Authentication successful")
+		case "MAIL":
+			message = &Message{Username: username, TLS: isTLS, From: getPath(argument)}
+			reply("250 OK")
+		case "RCPT":
+			message.To = append(message.To, getPath(argument))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(reader)
+			if err != nil {
+				return
+			}
+			message.Data = data
+			s.mu.Lock()
+			code := 0
+			if len(s.failures) > 0 {
+				code, s.failures = s.failures[0], s.failures[1:]
+			} else {
+				s.messages = append(s.messages, message)
+			}
+			s.mu.Unlock()
+			if code != 0 {
+				reply(strconv.Itoa(code) + " Message rejected")
+			} else {
+				reply("250 OK")
+			}
+			message = &Message{}
+		case "RSET":
+			message = &Message{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("500 Unrecognized command")
+		}
+	}
+}
+
+// getPath returns the address of a MAIL FROM:<address> or RCPT TO:<address> argument.
+func getPath(argument string) string {
+	_, path, _ := strings.Cut(argument, ":")
+	path, _, _ = strings.Cut(strings.TrimSpace(path), " ")
+	return strings.Trim(path, "<>")
+}
+
+// readData reads the data of a message up to the line with a single dot, undoing the dot-stuffing.
+func readData(reader *bufio.Reader) ([]byte, error) {
+	var data bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" {
+			return data.Bytes(), nil
+		}
+		data.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// newCertificate returns a self-signed certificate for 127.0.0.1 and localhost, and the pool trusting it.
+func newCertificate() (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("smtptest: failed to generate key: %v", err))
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "smtptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(fmt.Sprintf("smtptest: failed to create certificate: %v", err))
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(fmt.Sprintf("smtptest: failed to parse certificate: %v", err))
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(certificate)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: certificate}, roots
+}
@@ -0,0 +1,243 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/pkg/errors"
+)
+
+// The names of the built-in templates.
+const (
+	// TemplateOTP is the email of a one-time password, rendered with OTPData.
+	TemplateOTP = "otp"
+	// TemplateNotification is the email listing notifications, rendered with NotificationData.
+	TemplateNotification = "notification"
+	// TemplateDigest is the digest email, rendered with DigestData.
+	TemplateDigest = "digest"
+)
+
+// DefaultLocale is the locale of the templates used when there is none for the locale of the recipient.
+const DefaultLocale = "en"
+
+// TemplateNames are the names of the built-in templates, which admins can override.
+var TemplateNames = []string{TemplateOTP, TemplateNotification, TemplateDigest}
+
+//go:embed templates
+var templatesFS embed.FS
+
+// templateFuncs are the functions available to every template.
+var templateFuncs = map[string]any{
+	"dict": dict,
+}
+
+// Template renders the subject, plain text body and HTML body of an email.
+// The subject and the plain text body are text/template templates, the HTML body is an html/template template.
+type Template struct {
+	Name   string
+	Locale string
+
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// TemplateSource is the source of a template, such as the templates admins store in the workspace settings.
+type TemplateSource struct {
+	Name   string
+	Locale string
+	// Subject, Text and HTML are the sources of the templates of the subject, plain text body and HTML body.
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// ParseTemplate parses the source of a template, checking that its name is one of TemplateNames.
+func ParseTemplate(source *TemplateSource) (*Template, error) {
+	if !slices.Contains(TemplateNames, source.Name) {
+		return nil, errors.Errorf("unknown email template %q", source.Name)
+	}
+	if strings.TrimSpace(source.Subject) == "" || strings.TrimSpace(source.Text) == "" || strings.TrimSpace(source.HTML) == "" {
+		return nil, errors.Errorf("email template %q requires a subject, a plain text body and an HTML body", source.Name)
+	}
+	text, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(source.Subject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the subject of email template %q", source.Name)
+	}
+	if _, err := text.New("text").Parse(source.Text); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the plain text body of email template %q", source.Name)
+	}
+	html, err := htmltemplate.New("html").Funcs(templateFuncs).Parse(source.HTML)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the HTML body of email template %q", source.Name)
+	}
+	return &Template{
+		Name:   source.Name,
+		Locale: normalizeLocale(source.Locale),
+		text:   text,
+		html:   html,
+	}, nil
+}
+
+// parseTemplateFile parses a built-in template, a file defining the "subject", "text" and "html" templates.
+func parseTemplateFile(name, locale string, content []byte) (*Template, error) {
+	text, err := texttemplate.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	for _, definedName := range []string{"subject", "text"} {
+		if text.Lookup(definedName) == nil {
+			return nil, errors.Errorf("missing %q template", definedName)
+		}
+	}
+	if html.Lookup("html") == nil {
+		return nil, errors.New(`missing "html" template`)
+	}
+	return &Template{
+		Name:   name,
+		Locale: locale,
+		text:   text,
+		html:   html,
+	}, nil
+}
+
+// Render renders the message of the template with the data. The sender and the recipients are left to the caller.
+func (t *Template) Render(data any) (*Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, errors.Wrapf(err, "failed to render the subject of email template %q", t.Name)
+	}
+	if err := t.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, errors.Wrapf(err, "failed to render the plain text body of email template %q", t.Name)
+	}
+	if err := t.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, errors.Wrapf(err, "failed to render the HTML body of email template %q", t.Name)
+	}
+	return &Message{
+		// Subjects are a single line, whatever the line breaks of the template.
+		Subject:  strings.Join(strings.Fields(subject.String()), " "),
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+	}, nil
+}
+
+// Registry holds the templates of each name and locale.
+type Registry struct {
+	templates map[string]map[string]*Template
+	parent    *Registry
+}
+
+// NewRegistry returns the registry of the built-in templates, stored in templates/{locale}/{name}.tmpl.
+// It panics if a built-in template is invalid.
+func NewRegistry() *Registry {
+	registry := &Registry{templates: map[string]map[string]*Template{}}
+	err := fs.WalkDir(templatesFS, "templates", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := templatesFS.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(path.Base(filePath), ".tmpl")
+		locale := path.Base(path.Dir(filePath))
+		template, err := parseTemplateFile(name, locale, content)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse email template %s", filePath)
+		}
+		registry.add(template)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// WithOverrides returns a registry where the templates take precedence over the templates of the registry
+// for the same name and locale. A locale without an overriding template still uses the template of the registry,
+// rather than the overriding template of another locale.
+func (r *Registry) WithOverrides(templates []*Template) *Registry {
+	registry := &Registry{templates: map[string]map[string]*Template{}, parent: r}
+	for _, template := range templates {
+		registry.add(template)
+	}
+	return registry
+}
+
+func (r *Registry) add(template *Template) {
+	if r.templates[template.Name] == nil {
+		r.templates[template.Name] = map[string]*Template{}
+	}
+	r.templates[template.Name][template.Locale] = template
+}
+
+// Lookup returns the template of the name for the locale, falling back to the language of the locale,
+// such as "pt" for "pt-BR", and then to DefaultLocale. It returns nil if there is no such template.
+func (r *Registry) Lookup(name, locale string) *Template {
+	for _, candidate := range getLocaleCandidates(locale) {
+		for registry := r; registry != nil; registry = registry.parent {
+			if template, ok := registry.templates[name][candidate]; ok {
+				return template
+			}
+		}
+	}
+	return nil
+}
+
+// Render renders the template of the name for the locale with the data.
+func (r *Registry) Render(name, locale string, data any) (*Message, error) {
+	template := r.Lookup(name, locale)
+	if template == nil {
+		return nil, errors.Errorf("unknown email template %q", name)
+	}
+	return template.Render(data)
+}
+
+// getLocaleCandidates returns the locales to look a template up for, from the most specific.
+func getLocaleCandidates(locale string) []string {
+	locale = normalizeLocale(locale)
+	candidates := []string{locale}
+	if language, _, ok := strings.Cut(locale, "-"); ok {
+		candidates = append(candidates, language)
+	}
+	if !slices.Contains(candidates, DefaultLocale) {
+		candidates = append(candidates, DefaultLocale)
+	}
+	return candidates
+}
+
+// normalizeLocale lowercases the locale and separates its subtags with hyphens, such as "zh-hans" for "zh_Hans".
+// The empty locale is DefaultLocale.
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// dict builds the map passed to a nested template from pairs of keys and values.
+func dict(values ...any) (map[string]any, error) {
+	if len(values)%2 != 0 {
+		return nil, errors.New("dict requires pairs of keys and values")
+	}
+	m := make(map[string]any, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key, ok := values[i].(string)
+		if !ok {
+			return nil, errors.New("dict keys must be strings")
+		}
+		m[key] = values[i+1]
+	}
+	return m, nil
+}
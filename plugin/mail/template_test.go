@@ -0,0 +1,115 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	registry := NewRegistry()
+	data := map[string]any{
+		TemplateOTP: OTPData{Code: "123456", Purpose: "password_reset", ExpiresInMinutes: 15},
+		TemplateNotification: NotificationData{
+			Items:          []NotificationItem{{Title: "Bob commented on your memo", Body: "<b>Nice</b>", URL: "http://localhost:8080/memos/abc"}},
+			EventTypes:     []string{"comment"},
+			UnsubscribeURL: "http://localhost:8080/notifications/unsubscribe?token=abc",
+		},
+		TemplateDigest: &DigestData{
+			Frequency: "weekly",
+			URL:       "http://localhost:8080",
+			Memos:     DigestSection{Items: []DigestItem{{Kind: "memo", Actor: "Bob", Body: "Hello <everyone>"}}, MoreCount: 2},
+		},
+	}
+	for _, name := range TemplateNames {
+		for _, locale := range []string{"en", "fr"} {
+			template := registry.Lookup(name, locale)
+			require.NotNil(t, template, name, locale)
+			require.Equal(t, locale, template.Locale)
+			message, err := template.Render(data[name])
+			require.NoError(t, err, name, locale)
+			require.NotEmpty(t, message.Subject)
+			require.NotContains(t, message.Subject, "\n")
+			require.NotEmpty(t, message.TextBody)
+			require.NotEmpty(t, message.HTMLBody)
+		}
+	}
+
+	message, err := registry.Render(TemplateOTP, "en", data[TemplateOTP])
+	require.NoError(t, err)
+	require.Contains(t, message.TextBody, "123456")
+	require.Contains(t, message.TextBody, "15 minutes")
+	require.Contains(t, message.HTMLBody, "123456")
+
+	message, err = registry.Render(TemplateDigest, "en", data[TemplateDigest])
+	require.NoError(t, err)
+	require.Equal(t, "Your weekly wekalist digest", message.Subject)
+	require.Contains(t, message.TextBody, "Bob posted a memo")
+	require.Contains(t, message.TextBody, "Hello <everyone>")
+	require.Contains(t, message.HTMLBody, "Hello &lt;everyone&gt;")
+	require.Contains(t, message.HTMLBody, "And 2 more.")
+
+	message, err = registry.Render(TemplateDigest, "fr", data[TemplateDigest])
+	require.NoError(t, err)
+	require.Equal(t, "Votre résumé hebdomadaire wekalist", message.Subject)
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := NewRegistry()
+	for locale, expected := range map[string]string{
+		"":        "en",
+		"en":      "en",
+		"fr":      "fr",
+		"fr-CA":   "fr",
+		"fr_ca":   "fr",
+		"de":      "en",
+		"zh_Hans": "en",
+	} {
+		template := registry.Lookup(TemplateOTP, locale)
+		require.NotNil(t, template, locale)
+		require.Equal(t, expected, template.Locale, locale)
+	}
+	require.Nil(t, registry.Lookup("unknown", "en"))
+	_, err := registry.Render("unknown", "en", nil)
+	require.Error(t, err)
+}
+
+func TestRegistryWithOverrides(t *testing.T) {
+	override, err := ParseTemplate(&TemplateSource{
+		Name:    TemplateOTP,
+		Locale:  "fr-CA",
+		Subject: "Code {{.Code}}",
+		Text:    "Votre code : {{.Code}}",
+		HTML:    "<p>Votre code : {{.Code}}</p>",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "fr-ca", override.Locale)
+	registry := NewRegistry().WithOverrides([]*Template{override})
+
+	require.Same(t, override, registry.Lookup(TemplateOTP, "fr-CA"))
+	// Other locales keep their built-in template rather than the override of another locale.
+	require.Equal(t, "fr", registry.Lookup(TemplateOTP, "fr").Locale)
+	require.Equal(t, "fr", registry.Lookup(TemplateOTP, "fr-BE").Locale)
+	require.Equal(t, "en", registry.Lookup(TemplateOTP, "de").Locale)
+	require.Equal(t, "fr", registry.Lookup(TemplateNotification, "fr-CA").Locale)
+
+	message, err := registry.Render(TemplateOTP, "fr-CA", OTPData{Code: "<123>"})
+	require.NoError(t, err)
+	require.Equal(t, "Code <123>", message.Subject)
+	require.Equal(t, "Votre code : <123>\n", message.TextBody)
+	require.Equal(t, "<p>Votre code : &lt;123&gt;</p>", message.HTMLBody)
+}
+
+func TestParseTemplate(t *testing.T) {
+	for _, source := range []*TemplateSource{
+		{Name: "unknown", Subject: "s", Text: "t", HTML: "h"},
+		{Name: TemplateOTP, Subject: "", Text: "t", HTML: "h"},
+		{Name: TemplateOTP, Subject: "s", Text: " ", HTML: "h"},
+		{Name: TemplateOTP, Subject: "{{.Code", Text: "t", HTML: "h"},
+		{Name: TemplateOTP, Subject: "s", Text: "{{if .Code}}", HTML: "h"},
+		{Name: TemplateOTP, Subject: "s", Text: "t", HTML: "{{dict}"},
+	} {
+		_, err := ParseTemplate(source)
+		require.Error(t, err, source)
+	}
+}
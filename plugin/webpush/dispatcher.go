@@ -0,0 +1,262 @@
+package webpush
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	webpushgo "github.com/SherClockHolmes/webpush-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWorkers     = 4
+	defaultQueueSize   = 1024
+	defaultMaxAttempts = 4
+	defaultBackoff     = time.Second
+	maxBackoff         = time.Minute
+	// requestTimeout is the timeout of a single request to a push service.
+	requestTimeout = 30 * time.Second
+)
+
+// ErrQueueFull is returned by Enqueue when the dispatcher cannot take more deliveries.
+var ErrQueueFull = errors.New("push delivery queue is full")
+
+// Urgency tells the push service how important a message is, see RFC 8030 section 5.3.
+type Urgency string
+
+const (
+	UrgencyVeryLow Urgency = "very-low"
+	UrgencyLow     Urgency = "low"
+	UrgencyNormal  Urgency = "normal"
+	UrgencyHigh    Urgency = "high"
+)
+
+// Status is the final status of a delivery.
+type Status string
+
+const (
+	// StatusDelivered means the push service accepted the message.
+	StatusDelivered Status = "DELIVERED"
+	// StatusExpired means the subscription is gone (HTTP 404 or 410) and should be deleted.
+	StatusExpired Status = "EXPIRED"
+	// StatusFailed means the message was rejected or every attempt failed.
+	StatusFailed Status = "FAILED"
+)
+
+// Subscription is the push subscription of a browser.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Message is an encrypted push message and the headers it is sent with.
+type Message struct {
+	Payload []byte
+	// TTL is how long the push service keeps the message while the browser is offline.
+	TTL     time.Duration
+	Urgency Urgency
+	// Topic replaces a pending message with the same topic.
+	Topic string
+}
+
+// Delivery is a message to send to a subscription.
+type Delivery struct {
+	Subscription *Subscription
+	Message      *Message
+	// OnOutcome, if set, is called by the worker once the delivery has a final outcome.
+	OnOutcome func(ctx context.Context, outcome *Outcome)
+}
+
+// Outcome is the result of a delivery.
+type Outcome struct {
+	Status Status
+	// StatusCode is the HTTP status code of the last response, or 0 if no response was received.
+	StatusCode int
+	Attempts   int
+	Error      string
+}
+
+// Config configures a Dispatcher. Zero values use the defaults.
+type Config struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	// Subscriber is the contact (mailto: or https: URL) sent to push services in the VAPID token.
+	Subscriber string
+	// Workers is the number of concurrent deliveries.
+	Workers int
+	// QueueSize is the number of deliveries waiting for a worker.
+	QueueSize int
+	// MaxAttempts is the number of attempts of a delivery failing transiently.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each following retry.
+	Backoff time.Duration
+	// HTTPClient sends the requests to the push services, overridden in tests.
+	HTTPClient webpushgo.HTTPClient
+}
+
+// Dispatcher delivers push messages with a bounded pool of workers, retrying transient failures
+// (network errors, HTTP 429 and 5xx) with exponential backoff.
+type Dispatcher struct {
+	config Config
+	queue  chan *Delivery
+
+	startOnce sync.Once
+}
+
+func NewDispatcher(config Config) *Dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = defaultBackoff
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &Dispatcher{
+		config: config,
+		queue:  make(chan *Delivery, config.QueueSize),
+	}
+}
+
+// Start starts the workers, which stop when the context is done. Deliveries still queued are dropped.
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.startOnce.Do(func() {
+		for i := 0; i < d.config.Workers; i++ {
+			go d.work(ctx)
+		}
+	})
+}
+
+// Enqueue queues the delivery without blocking, or returns ErrQueueFull.
+func (d *Dispatcher) Enqueue(delivery *Delivery) error {
+	select {
+	case d.queue <- delivery:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery := <-d.queue:
+			outcome := d.Deliver(ctx, delivery)
+			if delivery.OnOutcome != nil {
+				delivery.OnOutcome(ctx, outcome)
+			}
+		}
+	}
+}
+
+// Deliver sends the delivery synchronously, retrying transient failures, and returns its outcome.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *Delivery) *Outcome {
+	outcome := &Outcome{}
+	backoff := d.config.Backoff
+	for {
+		outcome.Attempts++
+		statusCode, retryAfter, err := d.send(ctx, delivery)
+		outcome.StatusCode = statusCode
+		switch {
+		case err == nil:
+			outcome.Status, outcome.Error = StatusDelivered, ""
+			return outcome
+		case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+			outcome.Status, outcome.Error = StatusExpired, err.Error()
+			return outcome
+		}
+		outcome.Status, outcome.Error = StatusFailed, err.Error()
+		if !isTransient(statusCode, err) || outcome.Attempts >= d.config.MaxAttempts {
+			return outcome
+		}
+
+		delay := backoff
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+		slog.Debug("retrying push delivery", slog.String("endpoint", delivery.Subscription.Endpoint), slog.Int("attempt", outcome.Attempts), slog.Duration("delay", delay))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			outcome.Error = ctx.Err().Error()
+			return outcome
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// send makes a single attempt and returns the status code of the response, if any,
+// and the delay requested by its Retry-After header.
+func (d *Dispatcher) send(ctx context.Context, delivery *Delivery) (int, time.Duration, error) {
+	subscription, message := delivery.Subscription, delivery.Message
+	resp, err := webpushgo.SendNotificationWithContext(ctx, message.Payload, &webpushgo.Subscription{
+		Endpoint: subscription.Endpoint,
+		Keys: webpushgo.Keys{
+			P256dh: subscription.P256dh,
+			Auth:   subscription.Auth,
+		},
+	}, &webpushgo.Options{
+		HTTPClient:      d.config.HTTPClient,
+		Subscriber:      d.config.Subscriber,
+		Topic:           message.Topic,
+		TTL:             int(message.TTL / time.Second),
+		Urgency:         webpushgo.Urgency(message.Urgency),
+		VAPIDPublicKey:  d.config.VAPIDPublicKey,
+		VAPIDPrivateKey: d.config.VAPIDPrivateKey,
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to send push message to %s", subscription.Endpoint)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp.StatusCode, 0, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), errors.Errorf("push service responded with status code %d: %s", resp.StatusCode, body)
+}
+
+// isTransient reports whether a failed attempt may succeed when retried.
+func isTransient(statusCode int, err error) bool {
+	if statusCode == 0 {
+		// Only network errors are retried; the others, such as invalid subscription keys, fail again.
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
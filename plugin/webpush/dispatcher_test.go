@@ -0,0 +1,154 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webpushgo "github.com/SherClockHolmes/webpush-go"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubscription(t *testing.T, endpoint string) *Subscription {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	require.NoError(t, err)
+	return &Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	privateKey, publicKey, err := webpushgo.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	return NewDispatcher(Config{
+		VAPIDPublicKey:  publicKey,
+		VAPIDPrivateKey: privateKey,
+		Subscriber:      "mailto:admin@example.com",
+		MaxAttempts:     3,
+		Backoff:         time.Millisecond,
+	})
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	ctx := context.Background()
+	message := &Message{Payload: []byte(`{"title":"Hello"}`), TTL: time.Hour, Urgency: UrgencyHigh, Topic: "memos"}
+
+	t.Run("sends the message headers", func(t *testing.T) {
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		outcome := newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: newTestSubscription(t, server.URL), Message: message})
+		require.Equal(t, &Outcome{Status: StatusDelivered, StatusCode: http.StatusCreated, Attempts: 1}, outcome)
+		require.Equal(t, "3600", header.Get("TTL"))
+		require.Equal(t, "high", header.Get("Urgency"))
+		require.Equal(t, "memos", header.Get("Topic"))
+		require.Contains(t, header.Get("Authorization"), "vapid ")
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		outcome := newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: newTestSubscription(t, server.URL), Message: message})
+		require.Equal(t, StatusDelivered, outcome.Status)
+		require.Equal(t, 3, outcome.Attempts)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		outcome := newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: newTestSubscription(t, server.URL), Message: message})
+		require.Equal(t, StatusFailed, outcome.Status)
+		require.Equal(t, http.StatusTooManyRequests, outcome.StatusCode)
+		require.Equal(t, 3, outcome.Attempts)
+	})
+
+	t.Run("does not retry rejected messages", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		outcome := newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: newTestSubscription(t, server.URL), Message: message})
+		require.Equal(t, StatusFailed, outcome.Status)
+		require.Equal(t, 1, outcome.Attempts)
+
+		outcome = newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: &Subscription{Endpoint: server.URL, P256dh: "invalid", Auth: "invalid"}, Message: message})
+		require.Equal(t, StatusFailed, outcome.Status)
+		require.Equal(t, 0, outcome.StatusCode)
+		require.Equal(t, 1, outcome.Attempts)
+	})
+
+	t.Run("reports gone subscriptions as expired", func(t *testing.T) {
+		for _, statusCode := range []int{http.StatusNotFound, http.StatusGone} {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(statusCode)
+			}))
+			outcome := newTestDispatcher(t).Deliver(ctx, &Delivery{Subscription: newTestSubscription(t, server.URL), Message: message})
+			server.Close()
+			require.Equal(t, StatusExpired, outcome.Status)
+			require.Equal(t, statusCode, outcome.StatusCode)
+			require.Equal(t, 1, outcome.Attempts)
+		}
+	})
+}
+
+func TestDispatcherWorkers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	dispatcher := newTestDispatcher(t)
+	dispatcher.Start(ctx)
+	outcomes := make(chan *Outcome, 10)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dispatcher.Enqueue(&Delivery{
+			Subscription: newTestSubscription(t, server.URL),
+			Message:      &Message{Payload: []byte("hello")},
+			OnOutcome: func(_ context.Context, outcome *Outcome) {
+				outcomes <- outcome
+			},
+		}))
+	}
+	for i := 0; i < 10; i++ {
+		select {
+		case outcome := <-outcomes:
+			require.Equal(t, StatusDelivered, outcome.Status)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for deliveries")
+		}
+	}
+
+	full := NewDispatcher(Config{QueueSize: 1})
+	require.NoError(t, full.Enqueue(&Delivery{}))
+	require.ErrorIs(t, full.Enqueue(&Delivery{}), ErrQueueFull)
+}
@@ -0,0 +1,116 @@
+package webpush
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	webpushgo "github.com/SherClockHolmes/webpush-go"
+	"github.com/pkg/errors"
+)
+
+// VAPIDRotatedMessageType is the type of the push message asking a browser to subscribe again
+// with the new VAPID public key it carries.
+const VAPIDRotatedMessageType = "vapid-rotated"
+
+// VAPIDKeys is the keypair identifying the server to push services, see RFC 8292.
+// Both keys are base64url encoded: the public key as an uncompressed P-256 point, the private key as a scalar.
+type VAPIDKeys struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
+
+// VAPIDRotatedMessage is the payload of the push message of type VAPIDRotatedMessageType.
+type VAPIDRotatedMessage struct {
+	Type           string `json:"type"`
+	VAPIDPublicKey string `json:"vapidPublicKey"`
+}
+
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	privateKey, publicKey, err := webpushgo.GenerateVAPIDKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate VAPID keys")
+	}
+	return &VAPIDKeys{PublicKey: publicKey, PrivateKey: privateKey}, nil
+}
+
+// Validate checks that both keys are set and well formed.
+func (k *VAPIDKeys) Validate() error {
+	publicKey, err := decodeVAPIDKey(k.PublicKey)
+	if err != nil || len(publicKey) != 65 || publicKey[0] != 0x04 {
+		return errors.New("invalid VAPID public key")
+	}
+	privateKey, err := decodeVAPIDKey(k.PrivateKey)
+	if err != nil || len(privateKey) != 32 {
+		return errors.New("invalid VAPID private key")
+	}
+	return nil
+}
+
+// LoadVAPIDKeys reads the keys stored in the file. The error wraps os.ErrNotExist if there is no such file.
+func LoadVAPIDKeys(path string) (*VAPIDKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read VAPID keys from %s", path)
+	}
+	keys := &VAPIDKeys{}
+	if err := json.Unmarshal(data, keys); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse VAPID keys from %s", path)
+	}
+	if err := keys.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "failed to load VAPID keys from %s", path)
+	}
+	return keys, nil
+}
+
+// SaveVAPIDKeys replaces the file with the keys, readable only by the owner.
+func SaveVAPIDKeys(path string, keys *VAPIDKeys) error {
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal VAPID keys")
+	}
+	// The keys are written to a temporary file renamed over the previous one, so a crash never leaves a partial file.
+	file, err := os.CreateTemp(filepath.Dir(path), ".vapid-*.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to create VAPID keys file")
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return errors.Wrap(err, "failed to write VAPID keys")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "failed to write VAPID keys")
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return errors.Wrapf(err, "failed to save VAPID keys to %s", path)
+	}
+	return nil
+}
+
+// LoadOrGenerateVAPIDKeys reads the keys stored in the file, or generates and stores new keys
+// if there is no such file. It reports whether the keys were generated.
+func LoadOrGenerateVAPIDKeys(path string) (*VAPIDKeys, bool, error) {
+	keys, err := LoadVAPIDKeys(path)
+	if err == nil {
+		return keys, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+	keys, err = GenerateVAPIDKeys()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := SaveVAPIDKeys(path, keys); err != nil {
+		return nil, false, err
+	}
+	return keys, true, nil
+}
+
+// decodeVAPIDKey decodes a base64url key, with or without padding.
+func decodeVAPIDKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
@@ -0,0 +1,50 @@
+package webpush
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrGenerateVAPIDKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vapid.json")
+
+	_, err := LoadVAPIDKeys(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	keys, generated, err := LoadOrGenerateVAPIDKeys(path)
+	require.NoError(t, err)
+	require.True(t, generated)
+	require.NoError(t, keys.Validate())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, generated, err := LoadOrGenerateVAPIDKeys(path)
+	require.NoError(t, err)
+	require.False(t, generated)
+	require.Equal(t, keys, loaded)
+
+	rotated, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	require.NotEqual(t, keys.PublicKey, rotated.PublicKey)
+	require.NoError(t, SaveVAPIDKeys(path, rotated))
+	loaded, err = LoadVAPIDKeys(path)
+	require.NoError(t, err)
+	require.Equal(t, rotated, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"publicKey":"invalid","privateKey":"invalid"}`), 0600))
+	_, _, err = LoadOrGenerateVAPIDKeys(path)
+	require.Error(t, err)
+}
+
+func TestVAPIDKeysValidate(t *testing.T) {
+	keys, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	require.NoError(t, keys.Validate())
+	require.NoError(t, (&VAPIDKeys{PublicKey: keys.PublicKey + "=", PrivateKey: keys.PrivateKey + "="}).Validate())
+	require.Error(t, (&VAPIDKeys{PublicKey: keys.PublicKey}).Validate())
+	require.Error(t, (&VAPIDKeys{PublicKey: keys.PrivateKey, PrivateKey: keys.PrivateKey}).Validate())
+}
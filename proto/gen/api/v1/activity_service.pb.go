@@ -33,6 +33,10 @@ const (
 	Activity_MEMO_COMMENT Activity_Type = 1
 	// Version update activity.
 	Activity_VERSION_UPDATE Activity_Type = 2
+	// Memo reaction activity.
+	Activity_MEMO_REACTION Activity_Type = 3
+	// Memo mention activity.
+	Activity_MEMO_MENTION Activity_Type = 4
 )
 
 // Enum value maps for Activity_Type.
@@ -41,11 +45,15 @@ var (
 		0: "TYPE_UNSPECIFIED",
 		1: "MEMO_COMMENT",
 		2: "VERSION_UPDATE",
+		3: "MEMO_REACTION",
+		4: "MEMO_MENTION",
 	}
 	Activity_Type_value = map[string]int32{
 		"TYPE_UNSPECIFIED": 0,
 		"MEMO_COMMENT":     1,
 		"VERSION_UPDATE":   2,
+		"MEMO_REACTION":    3,
+		"MEMO_MENTION":     4,
 	}
 )
 
@@ -230,6 +238,8 @@ type ActivityPayload struct {
 	// Types that are valid to be assigned to Payload:
 	//
 	//	*ActivityPayload_MemoComment
+	//	*ActivityPayload_MemoReaction
+	//	*ActivityPayload_MemoMention
 	Payload       isActivityPayload_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -281,6 +291,24 @@ func (x *ActivityPayload) GetMemoComment() *ActivityMemoCommentPayload {
 	return nil
 }
 
+func (x *ActivityPayload) GetMemoReaction() *ActivityMemoReactionPayload {
+	if x != nil {
+		if x, ok := x.Payload.(*ActivityPayload_MemoReaction); ok {
+			return x.MemoReaction
+		}
+	}
+	return nil
+}
+
+func (x *ActivityPayload) GetMemoMention() *ActivityMemoMentionPayload {
+	if x != nil {
+		if x, ok := x.Payload.(*ActivityPayload_MemoMention); ok {
+			return x.MemoMention
+		}
+	}
+	return nil
+}
+
 type isActivityPayload_Payload interface {
 	isActivityPayload_Payload()
 }
@@ -290,8 +318,22 @@ type ActivityPayload_MemoComment struct {
 	MemoComment *ActivityMemoCommentPayload `protobuf:"bytes,1,opt,name=memo_comment,json=memoComment,proto3,oneof"`
 }
 
+type ActivityPayload_MemoReaction struct {
+	// Memo reaction activity payload.
+	MemoReaction *ActivityMemoReactionPayload `protobuf:"bytes,2,opt,name=memo_reaction,json=memoReaction,proto3,oneof"`
+}
+
+type ActivityPayload_MemoMention struct {
+	// Memo mention activity payload.
+	MemoMention *ActivityMemoMentionPayload `protobuf:"bytes,3,opt,name=memo_mention,json=memoMention,proto3,oneof"`
+}
+
 func (*ActivityPayload_MemoComment) isActivityPayload_Payload() {}
 
+func (*ActivityPayload_MemoReaction) isActivityPayload_Payload() {}
+
+func (*ActivityPayload_MemoMention) isActivityPayload_Payload() {}
+
 // ActivityMemoCommentPayload represents the payload of a memo comment activity.
 type ActivityMemoCommentPayload struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -349,6 +391,109 @@ func (x *ActivityMemoCommentPayload) GetRelatedMemo() string {
 	return ""
 }
 
+// ActivityMemoReactionPayload represents the payload of a memo reaction activity.
+type ActivityMemoReactionPayload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the memo reacted to.
+	// Format: memos/{memo}
+	Memo string `protobuf:"bytes,1,opt,name=memo,proto3" json:"memo,omitempty"`
+	// The type of the reaction.
+	ReactionType  string `protobuf:"bytes,2,opt,name=reaction_type,json=reactionType,proto3" json:"reaction_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityMemoReactionPayload) Reset() {
+	*x = ActivityMemoReactionPayload{}
+	mi := &file_api_v1_activity_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityMemoReactionPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityMemoReactionPayload) ProtoMessage() {}
+
+func (x *ActivityMemoReactionPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_activity_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityMemoReactionPayload.ProtoReflect.Descriptor instead.
+func (*ActivityMemoReactionPayload) Descriptor() ([]byte, []int) {
+	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ActivityMemoReactionPayload) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *ActivityMemoReactionPayload) GetReactionType() string {
+	if x != nil {
+		return x.ReactionType
+	}
+	return ""
+}
+
+// ActivityMemoMentionPayload represents the payload of a memo mention activity.
+type ActivityMemoMentionPayload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the memo mentioning the user.
+	// Format: memos/{memo}
+	Memo          string `protobuf:"bytes,1,opt,name=memo,proto3" json:"memo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityMemoMentionPayload) Reset() {
+	*x = ActivityMemoMentionPayload{}
+	mi := &file_api_v1_activity_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityMemoMentionPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityMemoMentionPayload) ProtoMessage() {}
+
+func (x *ActivityMemoMentionPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_activity_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityMemoMentionPayload.ProtoReflect.Descriptor instead.
+func (*ActivityMemoMentionPayload) Descriptor() ([]byte, []int) {
+	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ActivityMemoMentionPayload) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
 type ListActivitiesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The maximum number of activities to return.
@@ -365,7 +510,7 @@ type ListActivitiesRequest struct {
 
 func (x *ListActivitiesRequest) Reset() {
 	*x = ListActivitiesRequest{}
-	mi := &file_api_v1_activity_service_proto_msgTypes[3]
+	mi := &file_api_v1_activity_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -377,7 +522,7 @@ func (x *ListActivitiesRequest) String() string {
 func (*ListActivitiesRequest) ProtoMessage() {}
 
 func (x *ListActivitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_activity_service_proto_msgTypes[3]
+	mi := &file_api_v1_activity_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +535,7 @@ func (x *ListActivitiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListActivitiesRequest.ProtoReflect.Descriptor instead.
 func (*ListActivitiesRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{3}
+	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListActivitiesRequest) GetPageSize() int32 {
@@ -421,7 +566,7 @@ type ListActivitiesResponse struct {
 
 func (x *ListActivitiesResponse) Reset() {
 	*x = ListActivitiesResponse{}
-	mi := &file_api_v1_activity_service_proto_msgTypes[4]
+	mi := &file_api_v1_activity_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -433,7 +578,7 @@ func (x *ListActivitiesResponse) String() string {
 func (*ListActivitiesResponse) ProtoMessage() {}
 
 func (x *ListActivitiesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_activity_service_proto_msgTypes[4]
+	mi := &file_api_v1_activity_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -446,7 +591,7 @@ func (x *ListActivitiesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListActivitiesResponse.ProtoReflect.Descriptor instead.
 func (*ListActivitiesResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{4}
+	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListActivitiesResponse) GetActivities() []*Activity {
@@ -474,7 +619,7 @@ type GetActivityRequest struct {
 
 func (x *GetActivityRequest) Reset() {
 	*x = GetActivityRequest{}
-	mi := &file_api_v1_activity_service_proto_msgTypes[5]
+	mi := &file_api_v1_activity_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -486,7 +631,7 @@ func (x *GetActivityRequest) String() string {
 func (*GetActivityRequest) ProtoMessage() {}
 
 func (x *GetActivityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_activity_service_proto_msgTypes[5]
+	mi := &file_api_v1_activity_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -499,7 +644,7 @@ func (x *GetActivityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetActivityRequest.ProtoReflect.Descriptor instead.
 func (*GetActivityRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{5}
+	return file_api_v1_activity_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetActivityRequest) GetName() string {
@@ -513,7 +658,7 @@ var File_api_v1_activity_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_activity_service_proto_rawDesc = "" +
 	"\n" +
-	"\x1dapi/v1/activity_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x92\x04\n" +
+	"\x1dapi/v1/activity_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb7\x04\n" +
 	"\bActivity\x12\x1a\n" +
 	"\x04name\x18\x01 \x01(\tB\x06\xe0A\x03\xe0A\bR\x04name\x12\x1d\n" +
 	"\acreator\x18\x02 \x01(\tB\x03\xe0A\x03R\acreator\x127\n" +
@@ -521,24 +666,33 @@ const file_api_v1_activity_service_proto_rawDesc = "" +
 	"\x05level\x18\x04 \x01(\x0e2\x1f.wekalist.api.v1.Activity.LevelB\x03\xe0A\x03R\x05level\x12@\n" +
 	"\vcreate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
 	"createTime\x12?\n" +
-	"\apayload\x18\x06 \x01(\v2 .wekalist.api.v1.ActivityPayloadB\x03\xe0A\x03R\apayload\"B\n" +
+	"\apayload\x18\x06 \x01(\v2 .wekalist.api.v1.ActivityPayloadB\x03\xe0A\x03R\apayload\"g\n" +
 	"\x04Type\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
 	"\fMEMO_COMMENT\x10\x01\x12\x12\n" +
-	"\x0eVERSION_UPDATE\x10\x02\"=\n" +
+	"\x0eVERSION_UPDATE\x10\x02\x12\x11\n" +
+	"\rMEMO_REACTION\x10\x03\x12\x10\n" +
+	"\fMEMO_MENTION\x10\x04\"=\n" +
 	"\x05Level\x12\x15\n" +
 	"\x11LEVEL_UNSPECIFIED\x10\x00\x12\b\n" +
 	"\x04INFO\x10\x01\x12\b\n" +
 	"\x04WARN\x10\x02\x12\t\n" +
 	"\x05ERROR\x10\x03:P\xeaAM\n" +
 	"\x18wekalist.api.v1/Activity\x12\x15activities/{activity}\x1a\x04name*\n" +
-	"activities2\bactivity\"n\n" +
+	"activities2\bactivity\"\x95\x02\n" +
 	"\x0fActivityPayload\x12P\n" +
-	"\fmemo_comment\x18\x01 \x01(\v2+.wekalist.api.v1.ActivityMemoCommentPayloadH\x00R\vmemoCommentB\t\n" +
+	"\fmemo_comment\x18\x01 \x01(\v2+.wekalist.api.v1.ActivityMemoCommentPayloadH\x00R\vmemoComment\x12S\n" +
+	"\rmemo_reaction\x18\x02 \x01(\v2,.wekalist.api.v1.ActivityMemoReactionPayloadH\x00R\fmemoReaction\x12P\n" +
+	"\fmemo_mention\x18\x03 \x01(\v2+.wekalist.api.v1.ActivityMemoMentionPayloadH\x00R\vmemoMentionB\t\n" +
 	"\apayload\"S\n" +
 	"\x1aActivityMemoCommentPayload\x12\x12\n" +
 	"\x04memo\x18\x01 \x01(\tR\x04memo\x12!\n" +
-	"\frelated_memo\x18\x02 \x01(\tR\vrelatedMemo\"S\n" +
+	"\frelated_memo\x18\x02 \x01(\tR\vrelatedMemo\"V\n" +
+	"\x1bActivityMemoReactionPayload\x12\x12\n" +
+	"\x04memo\x18\x01 \x01(\tR\x04memo\x12#\n" +
+	"\rreaction_type\x18\x02 \x01(\tR\freactionType\"0\n" +
+	"\x1aActivityMemoMentionPayload\x12\x12\n" +
+	"\x04memo\x18\x01 \x01(\tR\x04memo\"S\n" +
 	"\x15ListActivitiesRequest\x12\x1b\n" +
 	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
 	"\n" +
@@ -569,34 +723,38 @@ func file_api_v1_activity_service_proto_rawDescGZIP() []byte {
 }
 
 var file_api_v1_activity_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_v1_activity_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_v1_activity_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_api_v1_activity_service_proto_goTypes = []any{
-	(Activity_Type)(0),                 // 0: wekalist.api.v1.Activity.Type
-	(Activity_Level)(0),                // 1: wekalist.api.v1.Activity.Level
-	(*Activity)(nil),                   // 2: wekalist.api.v1.Activity
-	(*ActivityPayload)(nil),            // 3: wekalist.api.v1.ActivityPayload
-	(*ActivityMemoCommentPayload)(nil), // 4: wekalist.api.v1.ActivityMemoCommentPayload
-	(*ListActivitiesRequest)(nil),      // 5: wekalist.api.v1.ListActivitiesRequest
-	(*ListActivitiesResponse)(nil),     // 6: wekalist.api.v1.ListActivitiesResponse
-	(*GetActivityRequest)(nil),         // 7: wekalist.api.v1.GetActivityRequest
-	(*timestamppb.Timestamp)(nil),      // 8: google.protobuf.Timestamp
+	(Activity_Type)(0),                  // 0: wekalist.api.v1.Activity.Type
+	(Activity_Level)(0),                 // 1: wekalist.api.v1.Activity.Level
+	(*Activity)(nil),                    // 2: wekalist.api.v1.Activity
+	(*ActivityPayload)(nil),             // 3: wekalist.api.v1.ActivityPayload
+	(*ActivityMemoCommentPayload)(nil),  // 4: wekalist.api.v1.ActivityMemoCommentPayload
+	(*ActivityMemoReactionPayload)(nil), // 5: wekalist.api.v1.ActivityMemoReactionPayload
+	(*ActivityMemoMentionPayload)(nil),  // 6: wekalist.api.v1.ActivityMemoMentionPayload
+	(*ListActivitiesRequest)(nil),       // 7: wekalist.api.v1.ListActivitiesRequest
+	(*ListActivitiesResponse)(nil),      // 8: wekalist.api.v1.ListActivitiesResponse
+	(*GetActivityRequest)(nil),          // 9: wekalist.api.v1.GetActivityRequest
+	(*timestamppb.Timestamp)(nil),       // 10: google.protobuf.Timestamp
 }
 var file_api_v1_activity_service_proto_depIdxs = []int32{
-	0, // 0: wekalist.api.v1.Activity.type:type_name -> wekalist.api.v1.Activity.Type
-	1, // 1: wekalist.api.v1.Activity.level:type_name -> wekalist.api.v1.Activity.Level
-	8, // 2: wekalist.api.v1.Activity.create_time:type_name -> google.protobuf.Timestamp
-	3, // 3: wekalist.api.v1.Activity.payload:type_name -> wekalist.api.v1.ActivityPayload
-	4, // 4: wekalist.api.v1.ActivityPayload.memo_comment:type_name -> wekalist.api.v1.ActivityMemoCommentPayload
-	2, // 5: wekalist.api.v1.ListActivitiesResponse.activities:type_name -> wekalist.api.v1.Activity
-	5, // 6: wekalist.api.v1.ActivityService.ListActivities:input_type -> wekalist.api.v1.ListActivitiesRequest
-	7, // 7: wekalist.api.v1.ActivityService.GetActivity:input_type -> wekalist.api.v1.GetActivityRequest
-	6, // 8: wekalist.api.v1.ActivityService.ListActivities:output_type -> wekalist.api.v1.ListActivitiesResponse
-	2, // 9: wekalist.api.v1.ActivityService.GetActivity:output_type -> wekalist.api.v1.Activity
-	8, // [8:10] is the sub-list for method output_type
-	6, // [6:8] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: wekalist.api.v1.Activity.type:type_name -> wekalist.api.v1.Activity.Type
+	1,  // 1: wekalist.api.v1.Activity.level:type_name -> wekalist.api.v1.Activity.Level
+	10, // 2: wekalist.api.v1.Activity.create_time:type_name -> google.protobuf.Timestamp
+	3,  // 3: wekalist.api.v1.Activity.payload:type_name -> wekalist.api.v1.ActivityPayload
+	4,  // 4: wekalist.api.v1.ActivityPayload.memo_comment:type_name -> wekalist.api.v1.ActivityMemoCommentPayload
+	5,  // 5: wekalist.api.v1.ActivityPayload.memo_reaction:type_name -> wekalist.api.v1.ActivityMemoReactionPayload
+	6,  // 6: wekalist.api.v1.ActivityPayload.memo_mention:type_name -> wekalist.api.v1.ActivityMemoMentionPayload
+	2,  // 7: wekalist.api.v1.ListActivitiesResponse.activities:type_name -> wekalist.api.v1.Activity
+	7,  // 8: wekalist.api.v1.ActivityService.ListActivities:input_type -> wekalist.api.v1.ListActivitiesRequest
+	9,  // 9: wekalist.api.v1.ActivityService.GetActivity:input_type -> wekalist.api.v1.GetActivityRequest
+	8,  // 10: wekalist.api.v1.ActivityService.ListActivities:output_type -> wekalist.api.v1.ListActivitiesResponse
+	2,  // 11: wekalist.api.v1.ActivityService.GetActivity:output_type -> wekalist.api.v1.Activity
+	10, // [10:12] is the sub-list for method output_type
+	8,  // [8:10] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_activity_service_proto_init() }
@@ -606,6 +764,8 @@ func file_api_v1_activity_service_proto_init() {
 	}
 	file_api_v1_activity_service_proto_msgTypes[1].OneofWrappers = []any{
 		(*ActivityPayload_MemoComment)(nil),
+		(*ActivityPayload_MemoReaction)(nil),
+		(*ActivityPayload_MemoMention)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -613,7 +773,7 @@ func file_api_v1_activity_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_activity_service_proto_rawDesc), len(file_api_v1_activity_service_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -11,6 +11,8 @@ import (
 	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -131,19 +133,1125 @@ func (x *GenAiResponse) GetStatus() *status.Status {
 	return nil
 }
 
+type StreamGenAiResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*StreamGenAiResponse_Delta
+	//	*StreamGenAiResponse_Usage
+	Event         isStreamGenAiResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamGenAiResponse) Reset() {
+	*x = StreamGenAiResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamGenAiResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamGenAiResponse) ProtoMessage() {}
+
+func (x *StreamGenAiResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamGenAiResponse.ProtoReflect.Descriptor instead.
+func (*StreamGenAiResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamGenAiResponse) GetEvent() isStreamGenAiResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *StreamGenAiResponse) GetDelta() string {
+	if x != nil {
+		if x, ok := x.Event.(*StreamGenAiResponse_Delta); ok {
+			return x.Delta
+		}
+	}
+	return ""
+}
+
+func (x *StreamGenAiResponse) GetUsage() *GenAiUsage {
+	if x != nil {
+		if x, ok := x.Event.(*StreamGenAiResponse_Usage); ok {
+			return x.Usage
+		}
+	}
+	return nil
+}
+
+type isStreamGenAiResponse_Event interface {
+	isStreamGenAiResponse_Event()
+}
+
+type StreamGenAiResponse_Delta struct {
+	// Incremental chunk of the AI generated response
+	Delta string `protobuf:"bytes,1,opt,name=delta,proto3,oneof"`
+}
+
+type StreamGenAiResponse_Usage struct {
+	// Usage summary, sent once as the last message of the stream
+	Usage *GenAiUsage `protobuf:"bytes,2,opt,name=usage,proto3,oneof"`
+}
+
+func (*StreamGenAiResponse_Delta) isStreamGenAiResponse_Event() {}
+
+func (*StreamGenAiResponse_Usage) isStreamGenAiResponse_Event() {}
+
+type GenAiUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The model that produced the response
+	Model            string `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	PromptTokens     int32  `protobuf:"varint,2,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens int32  `protobuf:"varint,3,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	TotalTokens      int32  `protobuf:"varint,4,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GenAiUsage) Reset() {
+	*x = GenAiUsage{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenAiUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenAiUsage) ProtoMessage() {}
+
+func (x *GenAiUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenAiUsage.ProtoReflect.Descriptor instead.
+func (*GenAiUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenAiUsage) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *GenAiUsage) GetPromptTokens() int32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *GenAiUsage) GetCompletionTokens() int32 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+func (x *GenAiUsage) GetTotalTokens() int32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+type SummarizeMemoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	// Format: memos/{memo}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SummarizeMemoRequest) Reset() {
+	*x = SummarizeMemoRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummarizeMemoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeMemoRequest) ProtoMessage() {}
+
+func (x *SummarizeMemoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeMemoRequest.ProtoReflect.Descriptor instead.
+func (*SummarizeMemoRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SummarizeMemoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SummarizeMemoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The plain text summary of the memo content.
+	Summary       string `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SummarizeMemoResponse) Reset() {
+	*x = SummarizeMemoResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummarizeMemoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeMemoResponse) ProtoMessage() {}
+
+func (x *SummarizeMemoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeMemoResponse.ProtoReflect.Descriptor instead.
+func (*SummarizeMemoResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SummarizeMemoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SummarizeMemoResponse) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+type RewriteMemoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	// Format: memos/{memo}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional instruction on how to rewrite, e.g. "make it more formal".
+	// Defaults to improving clarity and fixing grammar.
+	Instruction   string `protobuf:"bytes,2,opt,name=instruction,proto3" json:"instruction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RewriteMemoRequest) Reset() {
+	*x = RewriteMemoRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RewriteMemoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewriteMemoRequest) ProtoMessage() {}
+
+func (x *RewriteMemoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewriteMemoRequest.ProtoReflect.Descriptor instead.
+func (*RewriteMemoRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RewriteMemoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RewriteMemoRequest) GetInstruction() string {
+	if x != nil {
+		return x.Instruction
+	}
+	return ""
+}
+
+type RewriteMemoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The rewritten memo content.
+	// Apply it with UpdateMemo using the update mask "content".
+	Content       string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RewriteMemoResponse) Reset() {
+	*x = RewriteMemoResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RewriteMemoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewriteMemoResponse) ProtoMessage() {}
+
+func (x *RewriteMemoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewriteMemoResponse.ProtoReflect.Descriptor instead.
+func (*RewriteMemoResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RewriteMemoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RewriteMemoResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ExtractTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	// Format: memos/{memo}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractTasksRequest) Reset() {
+	*x = ExtractTasksRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractTasksRequest) ProtoMessage() {}
+
+func (x *ExtractTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractTasksRequest.ProtoReflect.Descriptor instead.
+func (*ExtractTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExtractTasksRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ExtractTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The extracted tasks that are not yet in the memo's task list.
+	Tasks []string `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// The memo content with the extracted tasks appended as a task list.
+	// Apply it with UpdateMemo using the update mask "content".
+	Content       string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractTasksResponse) Reset() {
+	*x = ExtractTasksResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractTasksResponse) ProtoMessage() {}
+
+func (x *ExtractTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractTasksResponse.ProtoReflect.Descriptor instead.
+func (*ExtractTasksResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExtractTasksResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExtractTasksResponse) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ExtractTasksResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type SuggestTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	// Format: memos/{memo}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTagsRequest) Reset() {
+	*x = SuggestTagsRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTagsRequest) ProtoMessage() {}
+
+func (x *SuggestTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTagsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTagsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SuggestTagsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SuggestTagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the memo.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The suggested tags without the "#" prefix, excluding tags the memo already has.
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The memo content with the suggested tags appended.
+	// Apply it with UpdateMemo using the update mask "content".
+	Content       string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTagsResponse) Reset() {
+	*x = SuggestTagsResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTagsResponse) ProtoMessage() {}
+
+func (x *SuggestTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTagsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestTagsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SuggestTagsResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SuggestTagsResponse) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SuggestTagsResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type SearchMemosSemanticRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The natural language query to search for.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional. The maximum number of memos to return.
+	// Defaults to 10, capped at 100.
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMemosSemanticRequest) Reset() {
+	*x = SearchMemosSemanticRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMemosSemanticRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMemosSemanticRequest) ProtoMessage() {}
+
+func (x *SearchMemosSemanticRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMemosSemanticRequest.ProtoReflect.Descriptor instead.
+func (*SearchMemosSemanticRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchMemosSemanticRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchMemosSemanticRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type SearchMemosSemanticResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matched memos, most similar first.
+	Results       []*SearchMemosSemanticResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMemosSemanticResponse) Reset() {
+	*x = SearchMemosSemanticResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMemosSemanticResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMemosSemanticResponse) ProtoMessage() {}
+
+func (x *SearchMemosSemanticResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMemosSemanticResponse.ProtoReflect.Descriptor instead.
+func (*SearchMemosSemanticResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchMemosSemanticResponse) GetResults() []*SearchMemosSemanticResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type AskMemosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The question to answer, e.g. "what did we decide about the release freeze?".
+	Question string `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	// Optional. The maximum number of memos sent to the AI provider as context.
+	// Defaults to 5, capped at 20.
+	ContextSize   int32 `protobuf:"varint,2,opt,name=context_size,json=contextSize,proto3" json:"context_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AskMemosRequest) Reset() {
+	*x = AskMemosRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskMemosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskMemosRequest) ProtoMessage() {}
+
+func (x *AskMemosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskMemosRequest.ProtoReflect.Descriptor instead.
+func (*AskMemosRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AskMemosRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *AskMemosRequest) GetContextSize() int32 {
+	if x != nil {
+		return x.ContextSize
+	}
+	return 0
+}
+
+type AskMemosResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The answer grounded in the retrieved memos.
+	Answer string `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	// The resource names of the memos the answer is based on.
+	// Format: memos/{memo}
+	Citations     []string `protobuf:"bytes,2,rep,name=citations,proto3" json:"citations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AskMemosResponse) Reset() {
+	*x = AskMemosResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskMemosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskMemosResponse) ProtoMessage() {}
+
+func (x *AskMemosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskMemosResponse.ProtoReflect.Descriptor instead.
+func (*AskMemosResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AskMemosResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *AskMemosResponse) GetCitations() []string {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+type GetAiUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. The user to inspect.
+	// Format: users/{user}
+	// Leave empty to inspect the whole workspace.
+	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAiUsageRequest) Reset() {
+	*x = GetAiUsageRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAiUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAiUsageRequest) ProtoMessage() {}
+
+func (x *GetAiUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAiUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetAiUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetAiUsageRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type AiUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The inspected user, empty for the whole workspace.
+	// Format: users/{user}
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// The quota that applies to the inspected usage.
+	Quota *WorkspaceAISetting_Quota `protobuf:"bytes,2,opt,name=quota,proto3" json:"quota,omitempty"`
+	// The start of the current quota window.
+	WindowStartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=window_start_time,json=windowStartTime,proto3" json:"window_start_time,omitempty"`
+	// The end of the current quota window, when the usage resets.
+	WindowEndTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=window_end_time,json=windowEndTime,proto3" json:"window_end_time,omitempty"`
+	// The number of AI calls in the current window.
+	RequestCount int64 `protobuf:"varint,5,opt,name=request_count,json=requestCount,proto3" json:"request_count,omitempty"`
+	// The number of tokens consumed in the current window.
+	TotalTokens   int64 `protobuf:"varint,6,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AiUsage) Reset() {
+	*x = AiUsage{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AiUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AiUsage) ProtoMessage() {}
+
+func (x *AiUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AiUsage.ProtoReflect.Descriptor instead.
+func (*AiUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AiUsage) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AiUsage) GetQuota() *WorkspaceAISetting_Quota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+func (x *AiUsage) GetWindowStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowStartTime
+	}
+	return nil
+}
+
+func (x *AiUsage) GetWindowEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowEndTime
+	}
+	return nil
+}
+
+func (x *AiUsage) GetRequestCount() int64 {
+	if x != nil {
+		return x.RequestCount
+	}
+	return 0
+}
+
+func (x *AiUsage) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+type ResetAiUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. The user whose usage is reset.
+	// Format: users/{user}
+	// Leave empty to reset the usage of the whole workspace.
+	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetAiUsageRequest) Reset() {
+	*x = ResetAiUsageRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetAiUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetAiUsageRequest) ProtoMessage() {}
+
+func (x *ResetAiUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetAiUsageRequest.ProtoReflect.Descriptor instead.
+func (*ResetAiUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ResetAiUsageRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type SearchMemosSemanticResponse_Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matched memo.
+	Memo *Memo `protobuf:"bytes,1,opt,name=memo,proto3" json:"memo,omitempty"`
+	// The cosine similarity between the query and the memo, in [-1, 1].
+	Score         float32 `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMemosSemanticResponse_Result) Reset() {
+	*x = SearchMemosSemanticResponse_Result{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMemosSemanticResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMemosSemanticResponse_Result) ProtoMessage() {}
+
+func (x *SearchMemosSemanticResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMemosSemanticResponse_Result.ProtoReflect.Descriptor instead.
+func (*SearchMemosSemanticResponse_Result) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{13, 0}
+}
+
+func (x *SearchMemosSemanticResponse_Result) GetMemo() *Memo {
+	if x != nil {
+		return x.Memo
+	}
+	return nil
+}
+
+func (x *SearchMemosSemanticResponse_Result) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
 var File_api_v1_ai_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\n" +
-	"\x17api/v1/ai_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/rpc/status.proto\"+\n" +
+	"\x17api/v1/ai_service.proto\x12\x0fwekalist.api.v1\x1a\x19api/v1/memo_service.proto\x1a\x1eapi/v1/workspace_service.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"+\n" +
 	"\fGenAiRequest\x12\x1b\n" +
 	"\x06prompt\x18\x01 \x01(\tB\x03\xe0A\x02R\x06prompt\"~\n" +
 	"\rGenAiResponse\x12\x1b\n" +
 	"\x06prompt\x18\x01 \x01(\tB\x03\xe0A\x02R\x06prompt\x12\x1f\n" +
 	"\bresponse\x18\x02 \x01(\tB\x03\xe0A\x02R\bresponse\x12/\n" +
-	"\x06status\x18\x03 \x01(\v2\x12.google.rpc.StatusB\x03\xe0A\x01R\x06status2n\n" +
+	"\x06status\x18\x03 \x01(\v2\x12.google.rpc.StatusB\x03\xe0A\x01R\x06status\"k\n" +
+	"\x13StreamGenAiResponse\x12\x16\n" +
+	"\x05delta\x18\x01 \x01(\tH\x00R\x05delta\x123\n" +
+	"\x05usage\x18\x02 \x01(\v2\x1b.wekalist.api.v1.GenAiUsageH\x00R\x05usageB\a\n" +
+	"\x05event\"\x97\x01\n" +
+	"\n" +
+	"GenAiUsage\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12#\n" +
+	"\rprompt_tokens\x18\x02 \x01(\x05R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x03 \x01(\x05R\x10completionTokens\x12!\n" +
+	"\ftotal_tokens\x18\x04 \x01(\x05R\vtotalTokens\"H\n" +
+	"\x14SummarizeMemoRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/MemoR\x04name\"E\n" +
+	"\x15SummarizeMemoResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\"m\n" +
+	"\x12RewriteMemoRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/MemoR\x04name\x12%\n" +
+	"\vinstruction\x18\x02 \x01(\tB\x03\xe0A\x01R\vinstruction\"C\n" +
+	"\x13RewriteMemoResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"G\n" +
+	"\x13ExtractTasksRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/MemoR\x04name\"Z\n" +
+	"\x14ExtractTasksResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05tasks\x18\x02 \x03(\tR\x05tasks\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"F\n" +
+	"\x12SuggestTagsRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/MemoR\x04name\"W\n" +
+	"\x13SuggestTagsResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"Y\n" +
+	"\x1aSearchMemosSemanticRequest\x12\x19\n" +
+	"\x05query\x18\x01 \x01(\tB\x03\xe0A\x02R\x05query\x12 \n" +
+	"\tpage_size\x18\x02 \x01(\x05B\x03\xe0A\x01R\bpageSize\"\xb7\x01\n" +
+	"\x1bSearchMemosSemanticResponse\x12M\n" +
+	"\aresults\x18\x01 \x03(\v23.wekalist.api.v1.SearchMemosSemanticResponse.ResultR\aresults\x1aI\n" +
+	"\x06Result\x12)\n" +
+	"\x04memo\x18\x01 \x01(\v2\x15.wekalist.api.v1.MemoR\x04memo\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x02R\x05score\"Z\n" +
+	"\x0fAskMemosRequest\x12\x1f\n" +
+	"\bquestion\x18\x01 \x01(\tB\x03\xe0A\x02R\bquestion\x12&\n" +
+	"\fcontext_size\x18\x02 \x01(\x05B\x03\xe0A\x01R\vcontextSize\"H\n" +
+	"\x10AskMemosResponse\x12\x16\n" +
+	"\x06answer\x18\x01 \x01(\tR\x06answer\x12\x1c\n" +
+	"\tcitations\x18\x02 \x03(\tR\tcitations\",\n" +
+	"\x11GetAiUsageRequest\x12\x17\n" +
+	"\x04user\x18\x01 \x01(\tB\x03\xe0A\x01R\x04user\"\xb2\x02\n" +
+	"\aAiUsage\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12?\n" +
+	"\x05quota\x18\x02 \x01(\v2).wekalist.api.v1.WorkspaceAISetting.QuotaR\x05quota\x12F\n" +
+	"\x11window_start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0fwindowStartTime\x12B\n" +
+	"\x0fwindow_end_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rwindowEndTime\x12#\n" +
+	"\rrequest_count\x18\x05 \x01(\x03R\frequestCount\x12!\n" +
+	"\ftotal_tokens\x18\x06 \x01(\x03R\vtotalTokens\".\n" +
+	"\x13ResetAiUsageRequest\x12\x17\n" +
+	"\x04user\x18\x01 \x01(\tB\x03\xe0A\x01R\x04user2\xb6\n" +
+	"\n" +
 	"\tAiService\x12a\n" +
-	"\x05GenAi\x12\x1d.wekalist.api.v1.GenAiRequest\x1a\x1e.wekalist.api.v1.GenAiResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x06prompt\"\t/v1/genaiB\xb6\x01\n" +
+	"\x05GenAi\x12\x1d.wekalist.api.v1.GenAiRequest\x1a\x1e.wekalist.api.v1.GenAiResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x06prompt\"\t/v1/genai\x12z\n" +
+	"\vStreamGenAi\x12\x1d.wekalist.api.v1.GenAiRequest\x1a$.wekalist.api.v1.StreamGenAiResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x06prompt\"\x14/api/v1/genai:stream0\x01\x12\x92\x01\n" +
+	"\rSummarizeMemo\x12%.wekalist.api.v1.SummarizeMemoRequest\x1a&.wekalist.api.v1.SummarizeMemoResponse\"2\xdaA\x04name\x82\xd3\xe4\x93\x02%:\x01*\" /api/v1/{name=memos/*}:summarize\x12\x96\x01\n" +
+	"\vRewriteMemo\x12#.wekalist.api.v1.RewriteMemoRequest\x1a$.wekalist.api.v1.RewriteMemoResponse\"<\xdaA\x10name,instruction\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/api/v1/{name=memos/*}:rewrite\x12\x92\x01\n" +
+	"\fExtractTasks\x12$.wekalist.api.v1.ExtractTasksRequest\x1a%.wekalist.api.v1.ExtractTasksResponse\"5\xdaA\x04name\x82\xd3\xe4\x93\x02(:\x01*\"#/api/v1/{name=memos/*}:extractTasks\x12\x8e\x01\n" +
+	"\vSuggestTags\x12#.wekalist.api.v1.SuggestTagsRequest\x1a$.wekalist.api.v1.SuggestTagsResponse\"4\xdaA\x04name\x82\xd3\xe4\x93\x02':\x01*\"\"/api/v1/{name=memos/*}:suggestTags\x12\x9e\x01\n" +
+	"\x13SearchMemosSemantic\x12+.wekalist.api.v1.SearchMemosSemanticRequest\x1a,.wekalist.api.v1.SearchMemosSemanticResponse\",\xdaA\x05query\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/v1/memos:searchSemantic\x12x\n" +
+	"\bAskMemos\x12 .wekalist.api.v1.AskMemosRequest\x1a!.wekalist.api.v1.AskMemosResponse\"'\xdaA\bquestion\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/v1/memos:ask\x12g\n" +
+	"\n" +
+	"GetAiUsage\x12\".wekalist.api.v1.GetAiUsageRequest\x1a\x18.wekalist.api.v1.AiUsage\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v1/genai/usage\x12r\n" +
+	"\fResetAiUsage\x12$.wekalist.api.v1.ResetAiUsageRequest\x1a\x16.google.protobuf.Empty\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/api/v1/genai/usage:resetB\xb6\x01\n" +
 	"\x13com.wekalist.api.v1B\x0eAiServiceProtoP\x01Z1github.com/imrany/wekalist/proto/gen/api/v1;apiv1\xa2\x02\x03WAX\xaa\x02\x0fWekalist.Api.V1\xca\x02\x0fWekalist\\Api\\V1\xe2\x02\x1bWekalist\\Api\\V1\\GPBMetadata\xea\x02\x11Wekalist::Api::V1b\x06proto3"
 
 var (
@@ -158,21 +1266,67 @@ func file_api_v1_ai_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_ai_service_proto_rawDescData
 }
 
-var file_api_v1_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_v1_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_api_v1_ai_service_proto_goTypes = []any{
-	(*GenAiRequest)(nil),  // 0: wekalist.api.v1.GenAiRequest
-	(*GenAiResponse)(nil), // 1: wekalist.api.v1.GenAiResponse
-	(*status.Status)(nil), // 2: google.rpc.Status
+	(*GenAiRequest)(nil),                       // 0: wekalist.api.v1.GenAiRequest
+	(*GenAiResponse)(nil),                      // 1: wekalist.api.v1.GenAiResponse
+	(*StreamGenAiResponse)(nil),                // 2: wekalist.api.v1.StreamGenAiResponse
+	(*GenAiUsage)(nil),                         // 3: wekalist.api.v1.GenAiUsage
+	(*SummarizeMemoRequest)(nil),               // 4: wekalist.api.v1.SummarizeMemoRequest
+	(*SummarizeMemoResponse)(nil),              // 5: wekalist.api.v1.SummarizeMemoResponse
+	(*RewriteMemoRequest)(nil),                 // 6: wekalist.api.v1.RewriteMemoRequest
+	(*RewriteMemoResponse)(nil),                // 7: wekalist.api.v1.RewriteMemoResponse
+	(*ExtractTasksRequest)(nil),                // 8: wekalist.api.v1.ExtractTasksRequest
+	(*ExtractTasksResponse)(nil),               // 9: wekalist.api.v1.ExtractTasksResponse
+	(*SuggestTagsRequest)(nil),                 // 10: wekalist.api.v1.SuggestTagsRequest
+	(*SuggestTagsResponse)(nil),                // 11: wekalist.api.v1.SuggestTagsResponse
+	(*SearchMemosSemanticRequest)(nil),         // 12: wekalist.api.v1.SearchMemosSemanticRequest
+	(*SearchMemosSemanticResponse)(nil),        // 13: wekalist.api.v1.SearchMemosSemanticResponse
+	(*AskMemosRequest)(nil),                    // 14: wekalist.api.v1.AskMemosRequest
+	(*AskMemosResponse)(nil),                   // 15: wekalist.api.v1.AskMemosResponse
+	(*GetAiUsageRequest)(nil),                  // 16: wekalist.api.v1.GetAiUsageRequest
+	(*AiUsage)(nil),                            // 17: wekalist.api.v1.AiUsage
+	(*ResetAiUsageRequest)(nil),                // 18: wekalist.api.v1.ResetAiUsageRequest
+	(*SearchMemosSemanticResponse_Result)(nil), // 19: wekalist.api.v1.SearchMemosSemanticResponse.Result
+	(*status.Status)(nil),                      // 20: google.rpc.Status
+	(*WorkspaceAISetting_Quota)(nil),           // 21: wekalist.api.v1.WorkspaceAISetting.Quota
+	(*timestamppb.Timestamp)(nil),              // 22: google.protobuf.Timestamp
+	(*Memo)(nil),                               // 23: wekalist.api.v1.Memo
+	(*emptypb.Empty)(nil),                      // 24: google.protobuf.Empty
 }
 var file_api_v1_ai_service_proto_depIdxs = []int32{
-	2, // 0: wekalist.api.v1.GenAiResponse.status:type_name -> google.rpc.Status
-	0, // 1: wekalist.api.v1.AiService.GenAi:input_type -> wekalist.api.v1.GenAiRequest
-	1, // 2: wekalist.api.v1.AiService.GenAi:output_type -> wekalist.api.v1.GenAiResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	20, // 0: wekalist.api.v1.GenAiResponse.status:type_name -> google.rpc.Status
+	3,  // 1: wekalist.api.v1.StreamGenAiResponse.usage:type_name -> wekalist.api.v1.GenAiUsage
+	19, // 2: wekalist.api.v1.SearchMemosSemanticResponse.results:type_name -> wekalist.api.v1.SearchMemosSemanticResponse.Result
+	21, // 3: wekalist.api.v1.AiUsage.quota:type_name -> wekalist.api.v1.WorkspaceAISetting.Quota
+	22, // 4: wekalist.api.v1.AiUsage.window_start_time:type_name -> google.protobuf.Timestamp
+	22, // 5: wekalist.api.v1.AiUsage.window_end_time:type_name -> google.protobuf.Timestamp
+	23, // 6: wekalist.api.v1.SearchMemosSemanticResponse.Result.memo:type_name -> wekalist.api.v1.Memo
+	0,  // 7: wekalist.api.v1.AiService.GenAi:input_type -> wekalist.api.v1.GenAiRequest
+	0,  // 8: wekalist.api.v1.AiService.StreamGenAi:input_type -> wekalist.api.v1.GenAiRequest
+	4,  // 9: wekalist.api.v1.AiService.SummarizeMemo:input_type -> wekalist.api.v1.SummarizeMemoRequest
+	6,  // 10: wekalist.api.v1.AiService.RewriteMemo:input_type -> wekalist.api.v1.RewriteMemoRequest
+	8,  // 11: wekalist.api.v1.AiService.ExtractTasks:input_type -> wekalist.api.v1.ExtractTasksRequest
+	10, // 12: wekalist.api.v1.AiService.SuggestTags:input_type -> wekalist.api.v1.SuggestTagsRequest
+	12, // 13: wekalist.api.v1.AiService.SearchMemosSemantic:input_type -> wekalist.api.v1.SearchMemosSemanticRequest
+	14, // 14: wekalist.api.v1.AiService.AskMemos:input_type -> wekalist.api.v1.AskMemosRequest
+	16, // 15: wekalist.api.v1.AiService.GetAiUsage:input_type -> wekalist.api.v1.GetAiUsageRequest
+	18, // 16: wekalist.api.v1.AiService.ResetAiUsage:input_type -> wekalist.api.v1.ResetAiUsageRequest
+	1,  // 17: wekalist.api.v1.AiService.GenAi:output_type -> wekalist.api.v1.GenAiResponse
+	2,  // 18: wekalist.api.v1.AiService.StreamGenAi:output_type -> wekalist.api.v1.StreamGenAiResponse
+	5,  // 19: wekalist.api.v1.AiService.SummarizeMemo:output_type -> wekalist.api.v1.SummarizeMemoResponse
+	7,  // 20: wekalist.api.v1.AiService.RewriteMemo:output_type -> wekalist.api.v1.RewriteMemoResponse
+	9,  // 21: wekalist.api.v1.AiService.ExtractTasks:output_type -> wekalist.api.v1.ExtractTasksResponse
+	11, // 22: wekalist.api.v1.AiService.SuggestTags:output_type -> wekalist.api.v1.SuggestTagsResponse
+	13, // 23: wekalist.api.v1.AiService.SearchMemosSemantic:output_type -> wekalist.api.v1.SearchMemosSemanticResponse
+	15, // 24: wekalist.api.v1.AiService.AskMemos:output_type -> wekalist.api.v1.AskMemosResponse
+	17, // 25: wekalist.api.v1.AiService.GetAiUsage:output_type -> wekalist.api.v1.AiUsage
+	24, // 26: wekalist.api.v1.AiService.ResetAiUsage:output_type -> google.protobuf.Empty
+	17, // [17:27] is the sub-list for method output_type
+	7,  // [7:17] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_ai_service_proto_init() }
@@ -180,13 +1334,19 @@ func file_api_v1_ai_service_proto_init() {
 	if File_api_v1_ai_service_proto != nil {
 		return
 	}
+	file_api_v1_memo_service_proto_init()
+	file_api_v1_workspace_service_proto_init()
+	file_api_v1_ai_service_proto_msgTypes[2].OneofWrappers = []any{
+		(*StreamGenAiResponse_Delta)(nil),
+		(*StreamGenAiResponse_Usage)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_ai_service_proto_rawDesc), len(file_api_v1_ai_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
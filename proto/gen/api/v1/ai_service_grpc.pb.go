@@ -11,6 +11,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -19,7 +20,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AiService_GenAi_FullMethodName = "/wekalist.api.v1.AiService/GenAi"
+	AiService_GenAi_FullMethodName               = "/wekalist.api.v1.AiService/GenAi"
+	AiService_StreamGenAi_FullMethodName         = "/wekalist.api.v1.AiService/StreamGenAi"
+	AiService_SummarizeMemo_FullMethodName       = "/wekalist.api.v1.AiService/SummarizeMemo"
+	AiService_RewriteMemo_FullMethodName         = "/wekalist.api.v1.AiService/RewriteMemo"
+	AiService_ExtractTasks_FullMethodName        = "/wekalist.api.v1.AiService/ExtractTasks"
+	AiService_SuggestTags_FullMethodName         = "/wekalist.api.v1.AiService/SuggestTags"
+	AiService_SearchMemosSemantic_FullMethodName = "/wekalist.api.v1.AiService/SearchMemosSemantic"
+	AiService_AskMemos_FullMethodName            = "/wekalist.api.v1.AiService/AskMemos"
+	AiService_GetAiUsage_FullMethodName          = "/wekalist.api.v1.AiService/GetAiUsage"
+	AiService_ResetAiUsage_FullMethodName        = "/wekalist.api.v1.AiService/ResetAiUsage"
 )
 
 // AiServiceClient is the client API for AiService service.
@@ -27,6 +37,26 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AiServiceClient interface {
 	GenAi(ctx context.Context, in *GenAiRequest, opts ...grpc.CallOption) (*GenAiResponse, error)
+	// StreamGenAi streams the AI response as incremental deltas followed by a usage summary.
+	StreamGenAi(ctx context.Context, in *GenAiRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamGenAiResponse], error)
+	// SummarizeMemo summarizes the content of a memo.
+	SummarizeMemo(ctx context.Context, in *SummarizeMemoRequest, opts ...grpc.CallOption) (*SummarizeMemoResponse, error)
+	// RewriteMemo rewrites the content of a memo.
+	RewriteMemo(ctx context.Context, in *RewriteMemoRequest, opts ...grpc.CallOption) (*RewriteMemoResponse, error)
+	// ExtractTasks extracts actionable tasks from the content of a memo.
+	ExtractTasks(ctx context.Context, in *ExtractTasksRequest, opts ...grpc.CallOption) (*ExtractTasksResponse, error)
+	// SuggestTags suggests tags for a memo.
+	SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error)
+	// SearchMemosSemantic returns the visible memos ranked by semantic similarity to a query.
+	SearchMemosSemantic(ctx context.Context, in *SearchMemosSemanticRequest, opts ...grpc.CallOption) (*SearchMemosSemanticResponse, error)
+	// AskMemos answers a question using the memos visible to the caller as context.
+	AskMemos(ctx context.Context, in *AskMemosRequest, opts ...grpc.CallOption) (*AskMemosResponse, error)
+	// GetAiUsage returns the AI usage of a user or of the whole workspace in the current quota window.
+	// Users can inspect their own usage, admins can inspect any usage.
+	GetAiUsage(ctx context.Context, in *GetAiUsageRequest, opts ...grpc.CallOption) (*AiUsage, error)
+	// ResetAiUsage clears the recorded AI usage of a user or of the whole workspace.
+	// Only admins can reset usage.
+	ResetAiUsage(ctx context.Context, in *ResetAiUsageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type aiServiceClient struct {
@@ -47,11 +77,130 @@ func (c *aiServiceClient) GenAi(ctx context.Context, in *GenAiRequest, opts ...g
 	return out, nil
 }
 
+func (c *aiServiceClient) StreamGenAi(ctx context.Context, in *GenAiRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamGenAiResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AiService_ServiceDesc.Streams[0], AiService_StreamGenAi_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenAiRequest, StreamGenAiResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiService_StreamGenAiClient = grpc.ServerStreamingClient[StreamGenAiResponse]
+
+func (c *aiServiceClient) SummarizeMemo(ctx context.Context, in *SummarizeMemoRequest, opts ...grpc.CallOption) (*SummarizeMemoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SummarizeMemoResponse)
+	err := c.cc.Invoke(ctx, AiService_SummarizeMemo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) RewriteMemo(ctx context.Context, in *RewriteMemoRequest, opts ...grpc.CallOption) (*RewriteMemoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RewriteMemoResponse)
+	err := c.cc.Invoke(ctx, AiService_RewriteMemo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) ExtractTasks(ctx context.Context, in *ExtractTasksRequest, opts ...grpc.CallOption) (*ExtractTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtractTasksResponse)
+	err := c.cc.Invoke(ctx, AiService_ExtractTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestTagsResponse)
+	err := c.cc.Invoke(ctx, AiService_SuggestTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) SearchMemosSemantic(ctx context.Context, in *SearchMemosSemanticRequest, opts ...grpc.CallOption) (*SearchMemosSemanticResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMemosSemanticResponse)
+	err := c.cc.Invoke(ctx, AiService_SearchMemosSemantic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) AskMemos(ctx context.Context, in *AskMemosRequest, opts ...grpc.CallOption) (*AskMemosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AskMemosResponse)
+	err := c.cc.Invoke(ctx, AiService_AskMemos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) GetAiUsage(ctx context.Context, in *GetAiUsageRequest, opts ...grpc.CallOption) (*AiUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AiUsage)
+	err := c.cc.Invoke(ctx, AiService_GetAiUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) ResetAiUsage(ctx context.Context, in *ResetAiUsageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AiService_ResetAiUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AiServiceServer is the server API for AiService service.
 // All implementations must embed UnimplementedAiServiceServer
 // for forward compatibility.
 type AiServiceServer interface {
 	GenAi(context.Context, *GenAiRequest) (*GenAiResponse, error)
+	// StreamGenAi streams the AI response as incremental deltas followed by a usage summary.
+	StreamGenAi(*GenAiRequest, grpc.ServerStreamingServer[StreamGenAiResponse]) error
+	// SummarizeMemo summarizes the content of a memo.
+	SummarizeMemo(context.Context, *SummarizeMemoRequest) (*SummarizeMemoResponse, error)
+	// RewriteMemo rewrites the content of a memo.
+	RewriteMemo(context.Context, *RewriteMemoRequest) (*RewriteMemoResponse, error)
+	// ExtractTasks extracts actionable tasks from the content of a memo.
+	ExtractTasks(context.Context, *ExtractTasksRequest) (*ExtractTasksResponse, error)
+	// SuggestTags suggests tags for a memo.
+	SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error)
+	// SearchMemosSemantic returns the visible memos ranked by semantic similarity to a query.
+	SearchMemosSemantic(context.Context, *SearchMemosSemanticRequest) (*SearchMemosSemanticResponse, error)
+	// AskMemos answers a question using the memos visible to the caller as context.
+	AskMemos(context.Context, *AskMemosRequest) (*AskMemosResponse, error)
+	// GetAiUsage returns the AI usage of a user or of the whole workspace in the current quota window.
+	// Users can inspect their own usage, admins can inspect any usage.
+	GetAiUsage(context.Context, *GetAiUsageRequest) (*AiUsage, error)
+	// ResetAiUsage clears the recorded AI usage of a user or of the whole workspace.
+	// Only admins can reset usage.
+	ResetAiUsage(context.Context, *ResetAiUsageRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedAiServiceServer()
 }
 
@@ -65,6 +214,33 @@ type UnimplementedAiServiceServer struct{}
 func (UnimplementedAiServiceServer) GenAi(context.Context, *GenAiRequest) (*GenAiResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GenAi not implemented")
 }
+func (UnimplementedAiServiceServer) StreamGenAi(*GenAiRequest, grpc.ServerStreamingServer[StreamGenAiResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamGenAi not implemented")
+}
+func (UnimplementedAiServiceServer) SummarizeMemo(context.Context, *SummarizeMemoRequest) (*SummarizeMemoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SummarizeMemo not implemented")
+}
+func (UnimplementedAiServiceServer) RewriteMemo(context.Context, *RewriteMemoRequest) (*RewriteMemoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RewriteMemo not implemented")
+}
+func (UnimplementedAiServiceServer) ExtractTasks(context.Context, *ExtractTasksRequest) (*ExtractTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractTasks not implemented")
+}
+func (UnimplementedAiServiceServer) SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTags not implemented")
+}
+func (UnimplementedAiServiceServer) SearchMemosSemantic(context.Context, *SearchMemosSemanticRequest) (*SearchMemosSemanticResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMemosSemantic not implemented")
+}
+func (UnimplementedAiServiceServer) AskMemos(context.Context, *AskMemosRequest) (*AskMemosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AskMemos not implemented")
+}
+func (UnimplementedAiServiceServer) GetAiUsage(context.Context, *GetAiUsageRequest) (*AiUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAiUsage not implemented")
+}
+func (UnimplementedAiServiceServer) ResetAiUsage(context.Context, *ResetAiUsageRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetAiUsage not implemented")
+}
 func (UnimplementedAiServiceServer) mustEmbedUnimplementedAiServiceServer() {}
 func (UnimplementedAiServiceServer) testEmbeddedByValue()                   {}
 
@@ -104,6 +280,161 @@ func _AiService_GenAi_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AiService_StreamGenAi_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenAiRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AiServiceServer).StreamGenAi(m, &grpc.GenericServerStream[GenAiRequest, StreamGenAiResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiService_StreamGenAiServer = grpc.ServerStreamingServer[StreamGenAiResponse]
+
+func _AiService_SummarizeMemo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SummarizeMemoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).SummarizeMemo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_SummarizeMemo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).SummarizeMemo(ctx, req.(*SummarizeMemoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_RewriteMemo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RewriteMemoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).RewriteMemo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_RewriteMemo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).RewriteMemo(ctx, req.(*RewriteMemoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_ExtractTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtractTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).ExtractTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_ExtractTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).ExtractTasks(ctx, req.(*ExtractTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_SuggestTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).SuggestTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_SuggestTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).SuggestTags(ctx, req.(*SuggestTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_SearchMemosSemantic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMemosSemanticRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).SearchMemosSemantic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_SearchMemosSemantic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).SearchMemosSemantic(ctx, req.(*SearchMemosSemanticRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_AskMemos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AskMemosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).AskMemos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_AskMemos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).AskMemos(ctx, req.(*AskMemosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_GetAiUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAiUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).GetAiUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_GetAiUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).GetAiUsage(ctx, req.(*GetAiUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_ResetAiUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetAiUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).ResetAiUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_ResetAiUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).ResetAiUsage(ctx, req.(*ResetAiUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AiService_ServiceDesc is the grpc.ServiceDesc for AiService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +446,45 @@ var AiService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GenAi",
 			Handler:    _AiService_GenAi_Handler,
 		},
+		{
+			MethodName: "SummarizeMemo",
+			Handler:    _AiService_SummarizeMemo_Handler,
+		},
+		{
+			MethodName: "RewriteMemo",
+			Handler:    _AiService_RewriteMemo_Handler,
+		},
+		{
+			MethodName: "ExtractTasks",
+			Handler:    _AiService_ExtractTasks_Handler,
+		},
+		{
+			MethodName: "SuggestTags",
+			Handler:    _AiService_SuggestTags_Handler,
+		},
+		{
+			MethodName: "SearchMemosSemantic",
+			Handler:    _AiService_SearchMemosSemantic_Handler,
+		},
+		{
+			MethodName: "AskMemos",
+			Handler:    _AiService_AskMemos_Handler,
+		},
+		{
+			MethodName: "GetAiUsage",
+			Handler:    _AiService_GetAiUsage_Handler,
+		},
+		{
+			MethodName: "ResetAiUsage",
+			Handler:    _AiService_ResetAiUsage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamGenAi",
+			Handler:       _AiService_StreamGenAi_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/ai_service.proto",
 }
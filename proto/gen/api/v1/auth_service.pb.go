@@ -116,13 +116,15 @@ func (x *GetCurrentSessionResponse) GetLastAccessedAt() *timestamppb.Timestamp {
 
 type CreateSessionRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Provide one authentication method (username/password or SSO).
+	// Provide one authentication method (username/password, SSO or passkey).
 	// Required field to specify the authentication method.
 	//
 	// Types that are valid to be assigned to Credentials:
 	//
 	//	*CreateSessionRequest_PasswordCredentials_
 	//	*CreateSessionRequest_SsoCredentials
+	//	*CreateSessionRequest_TwoFactorCredentials_
+	//	*CreateSessionRequest_PasskeyCredentials_
 	Credentials   isCreateSessionRequest_Credentials `protobuf_oneof:"credentials"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -183,6 +185,24 @@ func (x *CreateSessionRequest) GetSsoCredentials() *CreateSessionRequest_SSOCred
 	return nil
 }
 
+func (x *CreateSessionRequest) GetTwoFactorCredentials() *CreateSessionRequest_TwoFactorCredentials {
+	if x != nil {
+		if x, ok := x.Credentials.(*CreateSessionRequest_TwoFactorCredentials_); ok {
+			return x.TwoFactorCredentials
+		}
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetPasskeyCredentials() *CreateSessionRequest_PasskeyCredentials {
+	if x != nil {
+		if x, ok := x.Credentials.(*CreateSessionRequest_PasskeyCredentials_); ok {
+			return x.PasskeyCredentials
+		}
+	}
+	return nil
+}
+
 type isCreateSessionRequest_Credentials interface {
 	isCreateSessionRequest_Credentials()
 }
@@ -197,10 +217,24 @@ type CreateSessionRequest_SsoCredentials struct {
 	SsoCredentials *CreateSessionRequest_SSOCredentials `protobuf:"bytes,2,opt,name=sso_credentials,json=ssoCredentials,proto3,oneof"`
 }
 
+type CreateSessionRequest_TwoFactorCredentials_ struct {
+	// Second sign-in step for users with two-factor authentication.
+	TwoFactorCredentials *CreateSessionRequest_TwoFactorCredentials `protobuf:"bytes,3,opt,name=two_factor_credentials,json=twoFactorCredentials,proto3,oneof"`
+}
+
+type CreateSessionRequest_PasskeyCredentials_ struct {
+	// Passkey authentication method.
+	PasskeyCredentials *CreateSessionRequest_PasskeyCredentials `protobuf:"bytes,4,opt,name=passkey_credentials,json=passkeyCredentials,proto3,oneof"`
+}
+
 func (*CreateSessionRequest_PasswordCredentials_) isCreateSessionRequest_Credentials() {}
 
 func (*CreateSessionRequest_SsoCredentials) isCreateSessionRequest_Credentials() {}
 
+func (*CreateSessionRequest_TwoFactorCredentials_) isCreateSessionRequest_Credentials() {}
+
+func (*CreateSessionRequest_PasskeyCredentials_) isCreateSessionRequest_Credentials() {}
+
 type CreateSessionResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The authenticated user information.
@@ -208,8 +242,13 @@ type CreateSessionResponse struct {
 	// Last time the session was accessed.
 	// Used for sliding expiration calculation (last_accessed_time + 2 weeks).
 	LastAccessedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Set when the credentials are valid but a second factor is required.
+	// No session is created; call CreateSession again with two_factor_credentials.
+	TwoFactorChallenge string `protobuf:"bytes,3,opt,name=two_factor_challenge,json=twoFactorChallenge,proto3" json:"two_factor_challenge,omitempty"`
+	// Whether the user must set up two-factor authentication before using the workspace.
+	TwoFactorSetupRequired bool `protobuf:"varint,4,opt,name=two_factor_setup_required,json=twoFactorSetupRequired,proto3" json:"two_factor_setup_required,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *CreateSessionResponse) Reset() {
@@ -256,6 +295,20 @@ func (x *CreateSessionResponse) GetLastAccessedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *CreateSessionResponse) GetTwoFactorChallenge() string {
+	if x != nil {
+		return x.TwoFactorChallenge
+	}
+	return ""
+}
+
+func (x *CreateSessionResponse) GetTwoFactorSetupRequired() bool {
+	if x != nil {
+		return x.TwoFactorSetupRequired
+	}
+	return false
+}
+
 type DeleteSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -292,6 +345,332 @@ func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
 	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{4}
 }
 
+type RequestPasswordResetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The email of the account to reset the password for.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RequestPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ConfirmPasswordResetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The email of the account to reset the password for.
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// The reset code sent to the email.
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// The new password.
+	NewPassword   string `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetRequest) Reset() {
+	*x = ConfirmPasswordResetRequest{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetRequest) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConfirmPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ConfirmPasswordResetRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ConfirmPasswordResetRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type BeginPasskeyAssertionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. The username or email to sign in with.
+	// If empty, the authenticator lets the user pick one of their discoverable passkeys.
+	Username      string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyAssertionRequest) Reset() {
+	*x = BeginPasskeyAssertionRequest{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyAssertionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyAssertionRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyAssertionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyAssertionRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyAssertionRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BeginPasskeyAssertionRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type BeginPasskeyAssertionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The PublicKeyCredentialRequestOptions as JSON, with binary values base64url encoded.
+	OptionsJson string `protobuf:"bytes,1,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"`
+	// An opaque token to send back with the passkey credentials.
+	CeremonyToken string `protobuf:"bytes,2,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyAssertionResponse) Reset() {
+	*x = BeginPasskeyAssertionResponse{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyAssertionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyAssertionResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyAssertionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyAssertionResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyAssertionResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BeginPasskeyAssertionResponse) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+func (x *BeginPasskeyAssertionResponse) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
+type BeginSSOAuthorizationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The ID of the identity provider.
+	IdpId int32 `protobuf:"varint,1,opt,name=idp_id,json=idpId,proto3" json:"idp_id,omitempty"`
+	// Required. The redirect URI the identity provider sends the authorization code to.
+	RedirectUri string `protobuf:"bytes,2,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// Optional. An opaque value passed back to the redirect URI.
+	State         string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginSSOAuthorizationRequest) Reset() {
+	*x = BeginSSOAuthorizationRequest{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginSSOAuthorizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginSSOAuthorizationRequest) ProtoMessage() {}
+
+func (x *BeginSSOAuthorizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginSSOAuthorizationRequest.ProtoReflect.Descriptor instead.
+func (*BeginSSOAuthorizationRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BeginSSOAuthorizationRequest) GetIdpId() int32 {
+	if x != nil {
+		return x.IdpId
+	}
+	return 0
+}
+
+func (x *BeginSSOAuthorizationRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *BeginSSOAuthorizationRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type BeginSSOAuthorizationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The authorization URL to redirect the browser to.
+	AuthorizationUrl string `protobuf:"bytes,1,opt,name=authorization_url,json=authorizationUrl,proto3" json:"authorization_url,omitempty"`
+	// An opaque token to send back with the SSO credentials.
+	// It carries the PKCE code verifier and the nonce, so keep it out of URLs.
+	CeremonyToken string `protobuf:"bytes,2,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginSSOAuthorizationResponse) Reset() {
+	*x = BeginSSOAuthorizationResponse{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginSSOAuthorizationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginSSOAuthorizationResponse) ProtoMessage() {}
+
+func (x *BeginSSOAuthorizationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginSSOAuthorizationResponse.ProtoReflect.Descriptor instead.
+func (*BeginSSOAuthorizationResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BeginSSOAuthorizationResponse) GetAuthorizationUrl() string {
+	if x != nil {
+		return x.AuthorizationUrl
+	}
+	return ""
+}
+
+func (x *BeginSSOAuthorizationResponse) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
 // Nested message for password-based authentication credentials.
 type CreateSessionRequest_PasswordCredentials struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -300,14 +679,17 @@ type CreateSessionRequest_PasswordCredentials struct {
 	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
 	// The password to sign in with.
 	// Required field for password-based authentication.
-	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// The ID of an LDAP identity provider to check the password against.
+	// If empty, the local password of the user is checked.
+	IdpId         int32 `protobuf:"varint,3,opt,name=idp_id,json=idpId,proto3" json:"idp_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateSessionRequest_PasswordCredentials) Reset() {
 	*x = CreateSessionRequest_PasswordCredentials{}
-	mi := &file_api_v1_auth_service_proto_msgTypes[5]
+	mi := &file_api_v1_auth_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -319,7 +701,7 @@ func (x *CreateSessionRequest_PasswordCredentials) String() string {
 func (*CreateSessionRequest_PasswordCredentials) ProtoMessage() {}
 
 func (x *CreateSessionRequest_PasswordCredentials) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_auth_service_proto_msgTypes[5]
+	mi := &file_api_v1_auth_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,6 +731,13 @@ func (x *CreateSessionRequest_PasswordCredentials) GetPassword() string {
 	return ""
 }
 
+func (x *CreateSessionRequest_PasswordCredentials) GetIdpId() int32 {
+	if x != nil {
+		return x.IdpId
+	}
+	return 0
+}
+
 // Nested message for SSO authentication credentials.
 type CreateSessionRequest_SSOCredentials struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -360,14 +749,17 @@ type CreateSessionRequest_SSOCredentials struct {
 	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
 	// The redirect URI used in the SSO flow.
 	// Required field for security validation.
-	RedirectUri   string `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	RedirectUri string `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The ceremony token returned by BeginSSOAuthorization.
+	// Required for OpenID Connect identity providers.
+	CeremonyToken string `protobuf:"bytes,4,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateSessionRequest_SSOCredentials) Reset() {
 	*x = CreateSessionRequest_SSOCredentials{}
-	mi := &file_api_v1_auth_service_proto_msgTypes[6]
+	mi := &file_api_v1_auth_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -379,7 +771,7 @@ func (x *CreateSessionRequest_SSOCredentials) String() string {
 func (*CreateSessionRequest_SSOCredentials) ProtoMessage() {}
 
 func (x *CreateSessionRequest_SSOCredentials) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_auth_service_proto_msgTypes[6]
+	mi := &file_api_v1_auth_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -416,6 +808,123 @@ func (x *CreateSessionRequest_SSOCredentials) GetRedirectUri() string {
 	return ""
 }
 
+func (x *CreateSessionRequest_SSOCredentials) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
+// Nested message for completing a two-factor authentication challenge.
+type CreateSessionRequest_TwoFactorCredentials struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The two_factor_challenge returned by the first sign-in step.
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// A TOTP code or a recovery code.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest_TwoFactorCredentials) Reset() {
+	*x = CreateSessionRequest_TwoFactorCredentials{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest_TwoFactorCredentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest_TwoFactorCredentials) ProtoMessage() {}
+
+func (x *CreateSessionRequest_TwoFactorCredentials) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest_TwoFactorCredentials.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest_TwoFactorCredentials) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{2, 2}
+}
+
+func (x *CreateSessionRequest_TwoFactorCredentials) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest_TwoFactorCredentials) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// Nested message for passkey authentication credentials.
+type CreateSessionRequest_PasskeyCredentials struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ceremony token returned by BeginPasskeyAssertion.
+	CeremonyToken string `protobuf:"bytes,1,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
+	// The PublicKeyCredential returned by navigator.credentials.get() as JSON.
+	CredentialJson string `protobuf:"bytes,2,opt,name=credential_json,json=credentialJson,proto3" json:"credential_json,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest_PasskeyCredentials) Reset() {
+	*x = CreateSessionRequest_PasskeyCredentials{}
+	mi := &file_api_v1_auth_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest_PasskeyCredentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest_PasskeyCredentials) ProtoMessage() {}
+
+func (x *CreateSessionRequest_PasskeyCredentials) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_auth_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest_PasskeyCredentials.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest_PasskeyCredentials) Descriptor() ([]byte, []int) {
+	return file_api_v1_auth_service_proto_rawDescGZIP(), []int{2, 3}
+}
+
+func (x *CreateSessionRequest_PasskeyCredentials) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest_PasskeyCredentials) GetCredentialJson() string {
+	if x != nil {
+		return x.CredentialJson
+	}
+	return ""
+}
+
 var File_api_v1_auth_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_auth_service_proto_rawDesc = "" +
@@ -424,26 +933,60 @@ const file_api_v1_auth_service_proto_rawDesc = "" +
 	"\x18GetCurrentSessionRequest\"\x8c\x01\n" +
 	"\x19GetCurrentSessionResponse\x12)\n" +
 	"\x04user\x18\x01 \x01(\v2\x15.wekalist.api.v1.UserR\x04user\x12D\n" +
-	"\x10last_accessed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\"\xbe\x03\n" +
+	"\x10last_accessed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\"\xac\a\n" +
 	"\x14CreateSessionRequest\x12n\n" +
 	"\x14password_credentials\x18\x01 \x01(\v29.wekalist.api.v1.CreateSessionRequest.PasswordCredentialsH\x00R\x13passwordCredentials\x12_\n" +
-	"\x0fsso_credentials\x18\x02 \x01(\v24.wekalist.api.v1.CreateSessionRequest.SSOCredentialsH\x00R\x0essoCredentials\x1aW\n" +
+	"\x0fsso_credentials\x18\x02 \x01(\v24.wekalist.api.v1.CreateSessionRequest.SSOCredentialsH\x00R\x0essoCredentials\x12r\n" +
+	"\x16two_factor_credentials\x18\x03 \x01(\v2:.wekalist.api.v1.CreateSessionRequest.TwoFactorCredentialsH\x00R\x14twoFactorCredentials\x12k\n" +
+	"\x13passkey_credentials\x18\x04 \x01(\v28.wekalist.api.v1.CreateSessionRequest.PasskeyCredentialsH\x00R\x12passkeyCredentials\x1as\n" +
 	"\x13PasswordCredentials\x12\x1f\n" +
 	"\busername\x18\x01 \x01(\tB\x03\xe0A\x02R\busername\x12\x1f\n" +
-	"\bpassword\x18\x02 \x01(\tB\x03\xe0A\x02R\bpassword\x1am\n" +
+	"\bpassword\x18\x02 \x01(\tB\x03\xe0A\x02R\bpassword\x12\x1a\n" +
+	"\x06idp_id\x18\x03 \x01(\x05B\x03\xe0A\x01R\x05idpId\x1a\x99\x01\n" +
 	"\x0eSSOCredentials\x12\x1a\n" +
 	"\x06idp_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x05idpId\x12\x17\n" +
 	"\x04code\x18\x02 \x01(\tB\x03\xe0A\x02R\x04code\x12&\n" +
-	"\fredirect_uri\x18\x03 \x01(\tB\x03\xe0A\x02R\vredirectUriB\r\n" +
-	"\vcredentials\"\x88\x01\n" +
+	"\fredirect_uri\x18\x03 \x01(\tB\x03\xe0A\x02R\vredirectUri\x12*\n" +
+	"\x0eceremony_token\x18\x04 \x01(\tB\x03\xe0A\x01R\rceremonyToken\x1aR\n" +
+	"\x14TwoFactorCredentials\x12!\n" +
+	"\tchallenge\x18\x01 \x01(\tB\x03\xe0A\x02R\tchallenge\x12\x17\n" +
+	"\x04code\x18\x02 \x01(\tB\x03\xe0A\x02R\x04code\x1an\n" +
+	"\x12PasskeyCredentials\x12*\n" +
+	"\x0eceremony_token\x18\x01 \x01(\tB\x03\xe0A\x02R\rceremonyToken\x12,\n" +
+	"\x0fcredential_json\x18\x02 \x01(\tB\x03\xe0A\x02R\x0ecredentialJsonB\r\n" +
+	"\vcredentials\"\xf5\x01\n" +
 	"\x15CreateSessionResponse\x12)\n" +
 	"\x04user\x18\x01 \x01(\v2\x15.wekalist.api.v1.UserR\x04user\x12D\n" +
-	"\x10last_accessed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\"\x16\n" +
-	"\x14DeleteSessionRequest2\x9b\x03\n" +
+	"\x10last_accessed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x120\n" +
+	"\x14two_factor_challenge\x18\x03 \x01(\tR\x12twoFactorChallenge\x129\n" +
+	"\x19two_factor_setup_required\x18\x04 \x01(\bR\x16twoFactorSetupRequired\"\x16\n" +
+	"\x14DeleteSessionRequest\"8\n" +
+	"\x1bRequestPasswordResetRequest\x12\x19\n" +
+	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05email\"y\n" +
+	"\x1bConfirmPasswordResetRequest\x12\x19\n" +
+	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05email\x12\x17\n" +
+	"\x04code\x18\x02 \x01(\tB\x03\xe0A\x02R\x04code\x12&\n" +
+	"\fnew_password\x18\x03 \x01(\tB\x03\xe0A\x02R\vnewPassword\"?\n" +
+	"\x1cBeginPasskeyAssertionRequest\x12\x1f\n" +
+	"\busername\x18\x01 \x01(\tB\x03\xe0A\x01R\busername\"i\n" +
+	"\x1dBeginPasskeyAssertionResponse\x12!\n" +
+	"\foptions_json\x18\x01 \x01(\tR\voptionsJson\x12%\n" +
+	"\x0eceremony_token\x18\x02 \x01(\tR\rceremonyToken\"}\n" +
+	"\x1cBeginSSOAuthorizationRequest\x12\x1a\n" +
+	"\x06idp_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x05idpId\x12&\n" +
+	"\fredirect_uri\x18\x02 \x01(\tB\x03\xe0A\x02R\vredirectUri\x12\x19\n" +
+	"\x05state\x18\x03 \x01(\tB\x03\xe0A\x01R\x05state\"s\n" +
+	"\x1dBeginSSOAuthorizationResponse\x12+\n" +
+	"\x11authorization_url\x18\x01 \x01(\tR\x10authorizationUrl\x12%\n" +
+	"\x0eceremony_token\x18\x02 \x01(\tR\rceremonyToken2\x8a\b\n" +
 	"\vAuthService\x12\x91\x01\n" +
 	"\x11GetCurrentSession\x12).wekalist.api.v1.GetCurrentSessionRequest\x1a*.wekalist.api.v1.GetCurrentSessionResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/v1/auth/sessions/current\x12\x80\x01\n" +
 	"\rCreateSession\x12%.wekalist.api.v1.CreateSessionRequest\x1a&.wekalist.api.v1.CreateSessionResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v1/auth/sessions\x12u\n" +
-	"\rDeleteSession\x12%.wekalist.api.v1.DeleteSessionRequest\x1a\x16.google.protobuf.Empty\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/auth/sessions/currentB\xb8\x01\n" +
+	"\rDeleteSession\x12%.wekalist.api.v1.DeleteSessionRequest\x1a\x16.google.protobuf.Empty\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/auth/sessions/current\x12\x8b\x01\n" +
+	"\x14RequestPasswordReset\x12,.wekalist.api.v1.RequestPasswordResetRequest\x1a\x16.google.protobuf.Empty\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/api/v1/auth/password:requestReset\x12\x8b\x01\n" +
+	"\x14ConfirmPasswordReset\x12,.wekalist.api.v1.ConfirmPasswordResetRequest\x1a\x16.google.protobuf.Empty\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/api/v1/auth/password:confirmReset\x12\xa7\x01\n" +
+	"\x15BeginPasskeyAssertion\x12-.wekalist.api.v1.BeginPasskeyAssertionRequest\x1a..wekalist.api.v1.BeginPasskeyAssertionResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/api/v1/auth/passkeys:beginAssertion\x12\xa6\x01\n" +
+	"\x15BeginSSOAuthorization\x12-.wekalist.api.v1.BeginSSOAuthorizationRequest\x1a..wekalist.api.v1.BeginSSOAuthorizationResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/api/v1/auth/sso:beginAuthorizationB\xb8\x01\n" +
 	"\x13com.wekalist.api.v1B\x10AuthServiceProtoP\x01Z1github.com/imrany/wekalist/proto/gen/api/v1;apiv1\xa2\x02\x03WAX\xaa\x02\x0fWekalist.Api.V1\xca\x02\x0fWekalist\\Api\\V1\xe2\x02\x1bWekalist\\Api\\V1\\GPBMetadata\xea\x02\x11Wekalist::Api::V1b\x06proto3"
 
 var (
@@ -458,37 +1001,55 @@ func file_api_v1_auth_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_auth_service_proto_rawDescData
 }
 
-var file_api_v1_auth_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_v1_auth_service_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_api_v1_auth_service_proto_goTypes = []any{
-	(*GetCurrentSessionRequest)(nil),                 // 0: wekalist.api.v1.GetCurrentSessionRequest
-	(*GetCurrentSessionResponse)(nil),                // 1: wekalist.api.v1.GetCurrentSessionResponse
-	(*CreateSessionRequest)(nil),                     // 2: wekalist.api.v1.CreateSessionRequest
-	(*CreateSessionResponse)(nil),                    // 3: wekalist.api.v1.CreateSessionResponse
-	(*DeleteSessionRequest)(nil),                     // 4: wekalist.api.v1.DeleteSessionRequest
-	(*CreateSessionRequest_PasswordCredentials)(nil), // 5: wekalist.api.v1.CreateSessionRequest.PasswordCredentials
-	(*CreateSessionRequest_SSOCredentials)(nil),      // 6: wekalist.api.v1.CreateSessionRequest.SSOCredentials
-	(*User)(nil),                  // 7: wekalist.api.v1.User
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 9: google.protobuf.Empty
+	(*GetCurrentSessionRequest)(nil),                  // 0: wekalist.api.v1.GetCurrentSessionRequest
+	(*GetCurrentSessionResponse)(nil),                 // 1: wekalist.api.v1.GetCurrentSessionResponse
+	(*CreateSessionRequest)(nil),                      // 2: wekalist.api.v1.CreateSessionRequest
+	(*CreateSessionResponse)(nil),                     // 3: wekalist.api.v1.CreateSessionResponse
+	(*DeleteSessionRequest)(nil),                      // 4: wekalist.api.v1.DeleteSessionRequest
+	(*RequestPasswordResetRequest)(nil),               // 5: wekalist.api.v1.RequestPasswordResetRequest
+	(*ConfirmPasswordResetRequest)(nil),               // 6: wekalist.api.v1.ConfirmPasswordResetRequest
+	(*BeginPasskeyAssertionRequest)(nil),              // 7: wekalist.api.v1.BeginPasskeyAssertionRequest
+	(*BeginPasskeyAssertionResponse)(nil),             // 8: wekalist.api.v1.BeginPasskeyAssertionResponse
+	(*BeginSSOAuthorizationRequest)(nil),              // 9: wekalist.api.v1.BeginSSOAuthorizationRequest
+	(*BeginSSOAuthorizationResponse)(nil),             // 10: wekalist.api.v1.BeginSSOAuthorizationResponse
+	(*CreateSessionRequest_PasswordCredentials)(nil),  // 11: wekalist.api.v1.CreateSessionRequest.PasswordCredentials
+	(*CreateSessionRequest_SSOCredentials)(nil),       // 12: wekalist.api.v1.CreateSessionRequest.SSOCredentials
+	(*CreateSessionRequest_TwoFactorCredentials)(nil), // 13: wekalist.api.v1.CreateSessionRequest.TwoFactorCredentials
+	(*CreateSessionRequest_PasskeyCredentials)(nil),   // 14: wekalist.api.v1.CreateSessionRequest.PasskeyCredentials
+	(*User)(nil),                  // 15: wekalist.api.v1.User
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 17: google.protobuf.Empty
 }
 var file_api_v1_auth_service_proto_depIdxs = []int32{
-	7, // 0: wekalist.api.v1.GetCurrentSessionResponse.user:type_name -> wekalist.api.v1.User
-	8, // 1: wekalist.api.v1.GetCurrentSessionResponse.last_accessed_at:type_name -> google.protobuf.Timestamp
-	5, // 2: wekalist.api.v1.CreateSessionRequest.password_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.PasswordCredentials
-	6, // 3: wekalist.api.v1.CreateSessionRequest.sso_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.SSOCredentials
-	7, // 4: wekalist.api.v1.CreateSessionResponse.user:type_name -> wekalist.api.v1.User
-	8, // 5: wekalist.api.v1.CreateSessionResponse.last_accessed_at:type_name -> google.protobuf.Timestamp
-	0, // 6: wekalist.api.v1.AuthService.GetCurrentSession:input_type -> wekalist.api.v1.GetCurrentSessionRequest
-	2, // 7: wekalist.api.v1.AuthService.CreateSession:input_type -> wekalist.api.v1.CreateSessionRequest
-	4, // 8: wekalist.api.v1.AuthService.DeleteSession:input_type -> wekalist.api.v1.DeleteSessionRequest
-	1, // 9: wekalist.api.v1.AuthService.GetCurrentSession:output_type -> wekalist.api.v1.GetCurrentSessionResponse
-	3, // 10: wekalist.api.v1.AuthService.CreateSession:output_type -> wekalist.api.v1.CreateSessionResponse
-	9, // 11: wekalist.api.v1.AuthService.DeleteSession:output_type -> google.protobuf.Empty
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	15, // 0: wekalist.api.v1.GetCurrentSessionResponse.user:type_name -> wekalist.api.v1.User
+	16, // 1: wekalist.api.v1.GetCurrentSessionResponse.last_accessed_at:type_name -> google.protobuf.Timestamp
+	11, // 2: wekalist.api.v1.CreateSessionRequest.password_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.PasswordCredentials
+	12, // 3: wekalist.api.v1.CreateSessionRequest.sso_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.SSOCredentials
+	13, // 4: wekalist.api.v1.CreateSessionRequest.two_factor_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.TwoFactorCredentials
+	14, // 5: wekalist.api.v1.CreateSessionRequest.passkey_credentials:type_name -> wekalist.api.v1.CreateSessionRequest.PasskeyCredentials
+	15, // 6: wekalist.api.v1.CreateSessionResponse.user:type_name -> wekalist.api.v1.User
+	16, // 7: wekalist.api.v1.CreateSessionResponse.last_accessed_at:type_name -> google.protobuf.Timestamp
+	0,  // 8: wekalist.api.v1.AuthService.GetCurrentSession:input_type -> wekalist.api.v1.GetCurrentSessionRequest
+	2,  // 9: wekalist.api.v1.AuthService.CreateSession:input_type -> wekalist.api.v1.CreateSessionRequest
+	4,  // 10: wekalist.api.v1.AuthService.DeleteSession:input_type -> wekalist.api.v1.DeleteSessionRequest
+	5,  // 11: wekalist.api.v1.AuthService.RequestPasswordReset:input_type -> wekalist.api.v1.RequestPasswordResetRequest
+	6,  // 12: wekalist.api.v1.AuthService.ConfirmPasswordReset:input_type -> wekalist.api.v1.ConfirmPasswordResetRequest
+	7,  // 13: wekalist.api.v1.AuthService.BeginPasskeyAssertion:input_type -> wekalist.api.v1.BeginPasskeyAssertionRequest
+	9,  // 14: wekalist.api.v1.AuthService.BeginSSOAuthorization:input_type -> wekalist.api.v1.BeginSSOAuthorizationRequest
+	1,  // 15: wekalist.api.v1.AuthService.GetCurrentSession:output_type -> wekalist.api.v1.GetCurrentSessionResponse
+	3,  // 16: wekalist.api.v1.AuthService.CreateSession:output_type -> wekalist.api.v1.CreateSessionResponse
+	17, // 17: wekalist.api.v1.AuthService.DeleteSession:output_type -> google.protobuf.Empty
+	17, // 18: wekalist.api.v1.AuthService.RequestPasswordReset:output_type -> google.protobuf.Empty
+	17, // 19: wekalist.api.v1.AuthService.ConfirmPasswordReset:output_type -> google.protobuf.Empty
+	8,  // 20: wekalist.api.v1.AuthService.BeginPasskeyAssertion:output_type -> wekalist.api.v1.BeginPasskeyAssertionResponse
+	10, // 21: wekalist.api.v1.AuthService.BeginSSOAuthorization:output_type -> wekalist.api.v1.BeginSSOAuthorizationResponse
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_auth_service_proto_init() }
@@ -500,6 +1061,8 @@ func file_api_v1_auth_service_proto_init() {
 	file_api_v1_auth_service_proto_msgTypes[2].OneofWrappers = []any{
 		(*CreateSessionRequest_PasswordCredentials_)(nil),
 		(*CreateSessionRequest_SsoCredentials)(nil),
+		(*CreateSessionRequest_TwoFactorCredentials_)(nil),
+		(*CreateSessionRequest_PasskeyCredentials_)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -507,7 +1070,7 @@ func file_api_v1_auth_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_auth_service_proto_rawDesc), len(file_api_v1_auth_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
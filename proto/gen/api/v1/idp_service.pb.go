@@ -30,6 +30,12 @@ const (
 	IdentityProvider_TYPE_UNSPECIFIED IdentityProvider_Type = 0
 	// OAuth2 identity provider.
 	IdentityProvider_OAUTH2 IdentityProvider_Type = 1
+	// OpenID Connect identity provider.
+	IdentityProvider_OIDC IdentityProvider_Type = 2
+	// LDAP directory, signed in to with the directory password.
+	IdentityProvider_LDAP IdentityProvider_Type = 3
+	// SAML 2.0 identity provider, signed in to through the service provider endpoints.
+	IdentityProvider_SAML IdentityProvider_Type = 4
 )
 
 // Enum value maps for IdentityProvider_Type.
@@ -37,10 +43,16 @@ var (
 	IdentityProvider_Type_name = map[int32]string{
 		0: "TYPE_UNSPECIFIED",
 		1: "OAUTH2",
+		2: "OIDC",
+		3: "LDAP",
+		4: "SAML",
 	}
 	IdentityProvider_Type_value = map[string]int32{
 		"TYPE_UNSPECIFIED": 0,
 		"OAUTH2":           1,
+		"OIDC":             2,
+		"LDAP":             3,
+		"SAML":             4,
 	}
 )
 
@@ -158,6 +170,9 @@ type IdentityProviderConfig struct {
 	// Types that are valid to be assigned to Config:
 	//
 	//	*IdentityProviderConfig_Oauth2Config
+	//	*IdentityProviderConfig_OidcConfig
+	//	*IdentityProviderConfig_LdapConfig
+	//	*IdentityProviderConfig_SamlConfig
 	Config        isIdentityProviderConfig_Config `protobuf_oneof:"config"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -209,6 +224,33 @@ func (x *IdentityProviderConfig) GetOauth2Config() *OAuth2Config {
 	return nil
 }
 
+func (x *IdentityProviderConfig) GetOidcConfig() *OIDCConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_OidcConfig); ok {
+			return x.OidcConfig
+		}
+	}
+	return nil
+}
+
+func (x *IdentityProviderConfig) GetLdapConfig() *LDAPConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_LdapConfig); ok {
+			return x.LdapConfig
+		}
+	}
+	return nil
+}
+
+func (x *IdentityProviderConfig) GetSamlConfig() *SAMLConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_SamlConfig); ok {
+			return x.SamlConfig
+		}
+	}
+	return nil
+}
+
 type isIdentityProviderConfig_Config interface {
 	isIdentityProviderConfig_Config()
 }
@@ -217,8 +259,26 @@ type IdentityProviderConfig_Oauth2Config struct {
 	Oauth2Config *OAuth2Config `protobuf:"bytes,1,opt,name=oauth2_config,json=oauth2Config,proto3,oneof"`
 }
 
+type IdentityProviderConfig_OidcConfig struct {
+	OidcConfig *OIDCConfig `protobuf:"bytes,2,opt,name=oidc_config,json=oidcConfig,proto3,oneof"`
+}
+
+type IdentityProviderConfig_LdapConfig struct {
+	LdapConfig *LDAPConfig `protobuf:"bytes,3,opt,name=ldap_config,json=ldapConfig,proto3,oneof"`
+}
+
+type IdentityProviderConfig_SamlConfig struct {
+	SamlConfig *SAMLConfig `protobuf:"bytes,4,opt,name=saml_config,json=samlConfig,proto3,oneof"`
+}
+
 func (*IdentityProviderConfig_Oauth2Config) isIdentityProviderConfig_Config() {}
 
+func (*IdentityProviderConfig_OidcConfig) isIdentityProviderConfig_Config() {}
+
+func (*IdentityProviderConfig_LdapConfig) isIdentityProviderConfig_Config() {}
+
+func (*IdentityProviderConfig_SamlConfig) isIdentityProviderConfig_Config() {}
+
 type FieldMapping struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
@@ -379,6 +439,291 @@ func (x *OAuth2Config) GetFieldMapping() *FieldMapping {
 	return nil
 }
 
+type OIDCConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The issuer URL, used to discover the endpoints from /.well-known/openid-configuration.
+	IssuerUrl    string `protobuf:"bytes,1,opt,name=issuer_url,json=issuerUrl,proto3" json:"issuer_url,omitempty"`
+	ClientId     string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret string `protobuf:"bytes,3,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// Scopes requested in addition to "openid".
+	Scopes []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// Optional. Claims to map, defaults to the standard claims (sub, name, email and picture).
+	FieldMapping  *FieldMapping `protobuf:"bytes,5,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OIDCConfig) Reset() {
+	*x = OIDCConfig{}
+	mi := &file_api_v1_idp_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OIDCConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OIDCConfig) ProtoMessage() {}
+
+func (x *OIDCConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_idp_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OIDCConfig.ProtoReflect.Descriptor instead.
+func (*OIDCConfig) Descriptor() ([]byte, []int) {
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OIDCConfig) GetIssuerUrl() string {
+	if x != nil {
+		return x.IssuerUrl
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *OIDCConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
+type LDAPConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The server URL, such as ldap://ldap.example.com:389 or ldaps://ldap.example.com:636.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Upgrade ldap:// connections with StartTLS.
+	StartTls           bool `protobuf:"varint,2,opt,name=start_tls,json=startTls,proto3" json:"start_tls,omitempty"`
+	InsecureSkipVerify bool `protobuf:"varint,3,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	// The service account used to search for users. Searches are anonymous when empty.
+	BindDn       string `protobuf:"bytes,4,opt,name=bind_dn,json=bindDn,proto3" json:"bind_dn,omitempty"`
+	BindPassword string `protobuf:"bytes,5,opt,name=bind_password,json=bindPassword,proto3" json:"bind_password,omitempty"`
+	// The base DN to search for users in.
+	BaseDn string `protobuf:"bytes,6,opt,name=base_dn,json=baseDn,proto3" json:"base_dn,omitempty"`
+	// The filter to find a user, where {username} is replaced by the escaped username.
+	// Defaults to (uid={username}).
+	UserFilter string `protobuf:"bytes,7,opt,name=user_filter,json=userFilter,proto3" json:"user_filter,omitempty"`
+	// Optional. Attributes to map, defaults to uid, cn and mail.
+	FieldMapping *FieldMapping `protobuf:"bytes,8,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	// The attribute listing the groups of a user. Defaults to memberOf.
+	GroupAttribute string `protobuf:"bytes,9,opt,name=group_attribute,json=groupAttribute,proto3" json:"group_attribute,omitempty"`
+	// Optional. When set, the role of users is synced from their groups on every sign-in.
+	GroupRoleMappings []*LDAPConfig_GroupRoleMapping `protobuf:"bytes,10,rep,name=group_role_mappings,json=groupRoleMappings,proto3" json:"group_role_mappings,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LDAPConfig) Reset() {
+	*x = LDAPConfig{}
+	mi := &file_api_v1_idp_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LDAPConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LDAPConfig) ProtoMessage() {}
+
+func (x *LDAPConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_idp_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LDAPConfig.ProtoReflect.Descriptor instead.
+func (*LDAPConfig) Descriptor() ([]byte, []int) {
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LDAPConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetStartTls() bool {
+	if x != nil {
+		return x.StartTls
+	}
+	return false
+}
+
+func (x *LDAPConfig) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+func (x *LDAPConfig) GetBindDn() string {
+	if x != nil {
+		return x.BindDn
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetBindPassword() string {
+	if x != nil {
+		return x.BindPassword
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetBaseDn() string {
+	if x != nil {
+		return x.BaseDn
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetUserFilter() string {
+	if x != nil {
+		return x.UserFilter
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
+func (x *LDAPConfig) GetGroupAttribute() string {
+	if x != nil {
+		return x.GroupAttribute
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetGroupRoleMappings() []*LDAPConfig_GroupRoleMapping {
+	if x != nil {
+		return x.GroupRoleMappings
+	}
+	return nil
+}
+
+type SAMLConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The entity ID of the identity provider, checked against the issuer of responses.
+	IdpEntityId string `protobuf:"bytes,1,opt,name=idp_entity_id,json=idpEntityId,proto3" json:"idp_entity_id,omitempty"`
+	// The single sign-on URL of the identity provider, used with the HTTP-Redirect binding.
+	IdpSsoUrl string `protobuf:"bytes,2,opt,name=idp_sso_url,json=idpSsoUrl,proto3" json:"idp_sso_url,omitempty"`
+	// The PEM encoded certificate the identity provider signs assertions with.
+	IdpCertificate string `protobuf:"bytes,3,opt,name=idp_certificate,json=idpCertificate,proto3" json:"idp_certificate,omitempty"`
+	// Optional. The entity ID of the service provider. Defaults to the metadata URL.
+	SpEntityId string `protobuf:"bytes,4,opt,name=sp_entity_id,json=spEntityId,proto3" json:"sp_entity_id,omitempty"`
+	// Optional. Attributes to map. The identifier defaults to the NameID of the subject.
+	FieldMapping  *FieldMapping `protobuf:"bytes,5,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SAMLConfig) Reset() {
+	*x = SAMLConfig{}
+	mi := &file_api_v1_idp_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SAMLConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SAMLConfig) ProtoMessage() {}
+
+func (x *SAMLConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_idp_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SAMLConfig.ProtoReflect.Descriptor instead.
+func (*SAMLConfig) Descriptor() ([]byte, []int) {
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SAMLConfig) GetIdpEntityId() string {
+	if x != nil {
+		return x.IdpEntityId
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetIdpSsoUrl() string {
+	if x != nil {
+		return x.IdpSsoUrl
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetIdpCertificate() string {
+	if x != nil {
+		return x.IdpCertificate
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetSpEntityId() string {
+	if x != nil {
+		return x.SpEntityId
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
 type ListIdentityProvidersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -387,7 +732,7 @@ type ListIdentityProvidersRequest struct {
 
 func (x *ListIdentityProvidersRequest) Reset() {
 	*x = ListIdentityProvidersRequest{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[4]
+	mi := &file_api_v1_idp_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -399,7 +744,7 @@ func (x *ListIdentityProvidersRequest) String() string {
 func (*ListIdentityProvidersRequest) ProtoMessage() {}
 
 func (x *ListIdentityProvidersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[4]
+	mi := &file_api_v1_idp_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -412,7 +757,7 @@ func (x *ListIdentityProvidersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListIdentityProvidersRequest.ProtoReflect.Descriptor instead.
 func (*ListIdentityProvidersRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{4}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{7}
 }
 
 type ListIdentityProvidersResponse struct {
@@ -425,7 +770,7 @@ type ListIdentityProvidersResponse struct {
 
 func (x *ListIdentityProvidersResponse) Reset() {
 	*x = ListIdentityProvidersResponse{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[5]
+	mi := &file_api_v1_idp_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -437,7 +782,7 @@ func (x *ListIdentityProvidersResponse) String() string {
 func (*ListIdentityProvidersResponse) ProtoMessage() {}
 
 func (x *ListIdentityProvidersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[5]
+	mi := &file_api_v1_idp_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -450,7 +795,7 @@ func (x *ListIdentityProvidersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListIdentityProvidersResponse.ProtoReflect.Descriptor instead.
 func (*ListIdentityProvidersResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{5}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListIdentityProvidersResponse) GetIdentityProviders() []*IdentityProvider {
@@ -471,7 +816,7 @@ type GetIdentityProviderRequest struct {
 
 func (x *GetIdentityProviderRequest) Reset() {
 	*x = GetIdentityProviderRequest{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[6]
+	mi := &file_api_v1_idp_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -483,7 +828,7 @@ func (x *GetIdentityProviderRequest) String() string {
 func (*GetIdentityProviderRequest) ProtoMessage() {}
 
 func (x *GetIdentityProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[6]
+	mi := &file_api_v1_idp_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,7 +841,7 @@ func (x *GetIdentityProviderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIdentityProviderRequest.ProtoReflect.Descriptor instead.
 func (*GetIdentityProviderRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{6}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetIdentityProviderRequest) GetName() string {
@@ -519,7 +864,7 @@ type CreateIdentityProviderRequest struct {
 
 func (x *CreateIdentityProviderRequest) Reset() {
 	*x = CreateIdentityProviderRequest{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[7]
+	mi := &file_api_v1_idp_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +876,7 @@ func (x *CreateIdentityProviderRequest) String() string {
 func (*CreateIdentityProviderRequest) ProtoMessage() {}
 
 func (x *CreateIdentityProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[7]
+	mi := &file_api_v1_idp_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +889,7 @@ func (x *CreateIdentityProviderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateIdentityProviderRequest.ProtoReflect.Descriptor instead.
 func (*CreateIdentityProviderRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{7}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CreateIdentityProviderRequest) GetIdentityProvider() *IdentityProvider {
@@ -574,7 +919,7 @@ type UpdateIdentityProviderRequest struct {
 
 func (x *UpdateIdentityProviderRequest) Reset() {
 	*x = UpdateIdentityProviderRequest{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[8]
+	mi := &file_api_v1_idp_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -586,7 +931,7 @@ func (x *UpdateIdentityProviderRequest) String() string {
 func (*UpdateIdentityProviderRequest) ProtoMessage() {}
 
 func (x *UpdateIdentityProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[8]
+	mi := &file_api_v1_idp_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +944,7 @@ func (x *UpdateIdentityProviderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateIdentityProviderRequest.ProtoReflect.Descriptor instead.
 func (*UpdateIdentityProviderRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{8}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *UpdateIdentityProviderRequest) GetIdentityProvider() *IdentityProvider {
@@ -627,7 +972,7 @@ type DeleteIdentityProviderRequest struct {
 
 func (x *DeleteIdentityProviderRequest) Reset() {
 	*x = DeleteIdentityProviderRequest{}
-	mi := &file_api_v1_idp_service_proto_msgTypes[9]
+	mi := &file_api_v1_idp_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -639,7 +984,7 @@ func (x *DeleteIdentityProviderRequest) String() string {
 func (*DeleteIdentityProviderRequest) ProtoMessage() {}
 
 func (x *DeleteIdentityProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_idp_service_proto_msgTypes[9]
+	mi := &file_api_v1_idp_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +997,7 @@ func (x *DeleteIdentityProviderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteIdentityProviderRequest.ProtoReflect.Descriptor instead.
 func (*DeleteIdentityProviderRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{9}
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeleteIdentityProviderRequest) GetName() string {
@@ -662,24 +1007,87 @@ func (x *DeleteIdentityProviderRequest) GetName() string {
 	return ""
 }
 
+type LDAPConfig_GroupRoleMapping struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The group DN or name, as listed in the group attribute.
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// The role given to members of the group, ADMIN or USER.
+	Role          string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LDAPConfig_GroupRoleMapping) Reset() {
+	*x = LDAPConfig_GroupRoleMapping{}
+	mi := &file_api_v1_idp_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LDAPConfig_GroupRoleMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LDAPConfig_GroupRoleMapping) ProtoMessage() {}
+
+func (x *LDAPConfig_GroupRoleMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_idp_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LDAPConfig_GroupRoleMapping.ProtoReflect.Descriptor instead.
+func (*LDAPConfig_GroupRoleMapping) Descriptor() ([]byte, []int) {
+	return file_api_v1_idp_service_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *LDAPConfig_GroupRoleMapping) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *LDAPConfig_GroupRoleMapping) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
 var File_api_v1_idp_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_idp_service_proto_rawDesc = "" +
 	"\n" +
-	"\x18api/v1/idp_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\x94\x03\n" +
+	"\x18api/v1/idp_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\xb2\x03\n" +
 	"\x10IdentityProvider\x12\x17\n" +
 	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12?\n" +
 	"\x04type\x18\x02 \x01(\x0e2&.wekalist.api.v1.IdentityProvider.TypeB\x03\xe0A\x02R\x04type\x12\x19\n" +
 	"\x05title\x18\x03 \x01(\tB\x03\xe0A\x02R\x05title\x120\n" +
 	"\x11identifier_filter\x18\x04 \x01(\tB\x03\xe0A\x01R\x10identifierFilter\x12D\n" +
-	"\x06config\x18\x05 \x01(\v2'.wekalist.api.v1.IdentityProviderConfigB\x03\xe0A\x02R\x06config\"(\n" +
+	"\x06config\x18\x05 \x01(\v2'.wekalist.api.v1.IdentityProviderConfigB\x03\xe0A\x02R\x06config\"F\n" +
 	"\x04Type\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
-	"\x06OAUTH2\x10\x01:i\xeaAf\n" +
-	" wekalist.api.v1/IdentityProvider\x12\x17identityProviders/{idp}\x1a\x04name*\x11identityProviders2\x10identityProvider\"h\n" +
+	"\x06OAUTH2\x10\x01\x12\b\n" +
+	"\x04OIDC\x10\x02\x12\b\n" +
+	"\x04LDAP\x10\x03\x12\b\n" +
+	"\x04SAML\x10\x04:i\xeaAf\n" +
+	" wekalist.api.v1/IdentityProvider\x12\x17identityProviders/{idp}\x1a\x04name*\x11identityProviders2\x10identityProvider\"\xa8\x02\n" +
 	"\x16IdentityProviderConfig\x12D\n" +
-	"\roauth2_config\x18\x01 \x01(\v2\x1d.wekalist.api.v1.OAuth2ConfigH\x00R\foauth2ConfigB\b\n" +
+	"\roauth2_config\x18\x01 \x01(\v2\x1d.wekalist.api.v1.OAuth2ConfigH\x00R\foauth2Config\x12>\n" +
+	"\voidc_config\x18\x02 \x01(\v2\x1b.wekalist.api.v1.OIDCConfigH\x00R\n" +
+	"oidcConfig\x12>\n" +
+	"\vldap_config\x18\x03 \x01(\v2\x1b.wekalist.api.v1.LDAPConfigH\x00R\n" +
+	"ldapConfig\x12>\n" +
+	"\vsaml_config\x18\x04 \x01(\v2\x1b.wekalist.api.v1.SAMLConfigH\x00R\n" +
+	"samlConfigB\b\n" +
 	"\x06config\"\x86\x01\n" +
 	"\fFieldMapping\x12\x1e\n" +
 	"\n" +
@@ -696,7 +1104,40 @@ const file_api_v1_idp_service_proto_rawDesc = "" +
 	"\ttoken_url\x18\x04 \x01(\tR\btokenUrl\x12\"\n" +
 	"\ruser_info_url\x18\x05 \x01(\tR\vuserInfoUrl\x12\x16\n" +
 	"\x06scopes\x18\x06 \x03(\tR\x06scopes\x12B\n" +
-	"\rfield_mapping\x18\a \x01(\v2\x1d.wekalist.api.v1.FieldMappingR\ffieldMapping\"\x1e\n" +
+	"\rfield_mapping\x18\a \x01(\v2\x1d.wekalist.api.v1.FieldMappingR\ffieldMapping\"\xc9\x01\n" +
+	"\n" +
+	"OIDCConfig\x12\x1d\n" +
+	"\n" +
+	"issuer_url\x18\x01 \x01(\tR\tissuerUrl\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12#\n" +
+	"\rclient_secret\x18\x03 \x01(\tR\fclientSecret\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12B\n" +
+	"\rfield_mapping\x18\x05 \x01(\v2\x1d.wekalist.api.v1.FieldMappingR\ffieldMapping\"\xee\x03\n" +
+	"\n" +
+	"LDAPConfig\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1b\n" +
+	"\tstart_tls\x18\x02 \x01(\bR\bstartTls\x120\n" +
+	"\x14insecure_skip_verify\x18\x03 \x01(\bR\x12insecureSkipVerify\x12\x17\n" +
+	"\abind_dn\x18\x04 \x01(\tR\x06bindDn\x12#\n" +
+	"\rbind_password\x18\x05 \x01(\tR\fbindPassword\x12\x17\n" +
+	"\abase_dn\x18\x06 \x01(\tR\x06baseDn\x12\x1f\n" +
+	"\vuser_filter\x18\a \x01(\tR\n" +
+	"userFilter\x12B\n" +
+	"\rfield_mapping\x18\b \x01(\v2\x1d.wekalist.api.v1.FieldMappingR\ffieldMapping\x12'\n" +
+	"\x0fgroup_attribute\x18\t \x01(\tR\x0egroupAttribute\x12\\\n" +
+	"\x13group_role_mappings\x18\n" +
+	" \x03(\v2,.wekalist.api.v1.LDAPConfig.GroupRoleMappingR\x11groupRoleMappings\x1a<\n" +
+	"\x10GroupRoleMapping\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\xdf\x01\n" +
+	"\n" +
+	"SAMLConfig\x12\"\n" +
+	"\ridp_entity_id\x18\x01 \x01(\tR\vidpEntityId\x12\x1e\n" +
+	"\vidp_sso_url\x18\x02 \x01(\tR\tidpSsoUrl\x12'\n" +
+	"\x0fidp_certificate\x18\x03 \x01(\tR\x0eidpCertificate\x12 \n" +
+	"\fsp_entity_id\x18\x04 \x01(\tR\n" +
+	"spEntityId\x12B\n" +
+	"\rfield_mapping\x18\x05 \x01(\v2\x1d.wekalist.api.v1.FieldMappingR\ffieldMapping\"\x1e\n" +
 	"\x1cListIdentityProvidersRequest\"q\n" +
 	"\x1dListIdentityProvidersResponse\x12P\n" +
 	"\x12identity_providers\x18\x01 \x03(\v2!.wekalist.api.v1.IdentityProviderR\x11identityProviders\"Z\n" +
@@ -734,46 +1175,57 @@ func file_api_v1_idp_service_proto_rawDescGZIP() []byte {
 }
 
 var file_api_v1_idp_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_v1_idp_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_v1_idp_service_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_api_v1_idp_service_proto_goTypes = []any{
 	(IdentityProvider_Type)(0),            // 0: wekalist.api.v1.IdentityProvider.Type
 	(*IdentityProvider)(nil),              // 1: wekalist.api.v1.IdentityProvider
 	(*IdentityProviderConfig)(nil),        // 2: wekalist.api.v1.IdentityProviderConfig
 	(*FieldMapping)(nil),                  // 3: wekalist.api.v1.FieldMapping
 	(*OAuth2Config)(nil),                  // 4: wekalist.api.v1.OAuth2Config
-	(*ListIdentityProvidersRequest)(nil),  // 5: wekalist.api.v1.ListIdentityProvidersRequest
-	(*ListIdentityProvidersResponse)(nil), // 6: wekalist.api.v1.ListIdentityProvidersResponse
-	(*GetIdentityProviderRequest)(nil),    // 7: wekalist.api.v1.GetIdentityProviderRequest
-	(*CreateIdentityProviderRequest)(nil), // 8: wekalist.api.v1.CreateIdentityProviderRequest
-	(*UpdateIdentityProviderRequest)(nil), // 9: wekalist.api.v1.UpdateIdentityProviderRequest
-	(*DeleteIdentityProviderRequest)(nil), // 10: wekalist.api.v1.DeleteIdentityProviderRequest
-	(*fieldmaskpb.FieldMask)(nil),         // 11: google.protobuf.FieldMask
-	(*emptypb.Empty)(nil),                 // 12: google.protobuf.Empty
+	(*OIDCConfig)(nil),                    // 5: wekalist.api.v1.OIDCConfig
+	(*LDAPConfig)(nil),                    // 6: wekalist.api.v1.LDAPConfig
+	(*SAMLConfig)(nil),                    // 7: wekalist.api.v1.SAMLConfig
+	(*ListIdentityProvidersRequest)(nil),  // 8: wekalist.api.v1.ListIdentityProvidersRequest
+	(*ListIdentityProvidersResponse)(nil), // 9: wekalist.api.v1.ListIdentityProvidersResponse
+	(*GetIdentityProviderRequest)(nil),    // 10: wekalist.api.v1.GetIdentityProviderRequest
+	(*CreateIdentityProviderRequest)(nil), // 11: wekalist.api.v1.CreateIdentityProviderRequest
+	(*UpdateIdentityProviderRequest)(nil), // 12: wekalist.api.v1.UpdateIdentityProviderRequest
+	(*DeleteIdentityProviderRequest)(nil), // 13: wekalist.api.v1.DeleteIdentityProviderRequest
+	(*LDAPConfig_GroupRoleMapping)(nil),   // 14: wekalist.api.v1.LDAPConfig.GroupRoleMapping
+	(*fieldmaskpb.FieldMask)(nil),         // 15: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                 // 16: google.protobuf.Empty
 }
 var file_api_v1_idp_service_proto_depIdxs = []int32{
 	0,  // 0: wekalist.api.v1.IdentityProvider.type:type_name -> wekalist.api.v1.IdentityProvider.Type
 	2,  // 1: wekalist.api.v1.IdentityProvider.config:type_name -> wekalist.api.v1.IdentityProviderConfig
 	4,  // 2: wekalist.api.v1.IdentityProviderConfig.oauth2_config:type_name -> wekalist.api.v1.OAuth2Config
-	3,  // 3: wekalist.api.v1.OAuth2Config.field_mapping:type_name -> wekalist.api.v1.FieldMapping
-	1,  // 4: wekalist.api.v1.ListIdentityProvidersResponse.identity_providers:type_name -> wekalist.api.v1.IdentityProvider
-	1,  // 5: wekalist.api.v1.CreateIdentityProviderRequest.identity_provider:type_name -> wekalist.api.v1.IdentityProvider
-	1,  // 6: wekalist.api.v1.UpdateIdentityProviderRequest.identity_provider:type_name -> wekalist.api.v1.IdentityProvider
-	11, // 7: wekalist.api.v1.UpdateIdentityProviderRequest.update_mask:type_name -> google.protobuf.FieldMask
-	5,  // 8: wekalist.api.v1.IdentityProviderService.ListIdentityProviders:input_type -> wekalist.api.v1.ListIdentityProvidersRequest
-	7,  // 9: wekalist.api.v1.IdentityProviderService.GetIdentityProvider:input_type -> wekalist.api.v1.GetIdentityProviderRequest
-	8,  // 10: wekalist.api.v1.IdentityProviderService.CreateIdentityProvider:input_type -> wekalist.api.v1.CreateIdentityProviderRequest
-	9,  // 11: wekalist.api.v1.IdentityProviderService.UpdateIdentityProvider:input_type -> wekalist.api.v1.UpdateIdentityProviderRequest
-	10, // 12: wekalist.api.v1.IdentityProviderService.DeleteIdentityProvider:input_type -> wekalist.api.v1.DeleteIdentityProviderRequest
-	6,  // 13: wekalist.api.v1.IdentityProviderService.ListIdentityProviders:output_type -> wekalist.api.v1.ListIdentityProvidersResponse
-	1,  // 14: wekalist.api.v1.IdentityProviderService.GetIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
-	1,  // 15: wekalist.api.v1.IdentityProviderService.CreateIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
-	1,  // 16: wekalist.api.v1.IdentityProviderService.UpdateIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
-	12, // 17: wekalist.api.v1.IdentityProviderService.DeleteIdentityProvider:output_type -> google.protobuf.Empty
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	5,  // 3: wekalist.api.v1.IdentityProviderConfig.oidc_config:type_name -> wekalist.api.v1.OIDCConfig
+	6,  // 4: wekalist.api.v1.IdentityProviderConfig.ldap_config:type_name -> wekalist.api.v1.LDAPConfig
+	7,  // 5: wekalist.api.v1.IdentityProviderConfig.saml_config:type_name -> wekalist.api.v1.SAMLConfig
+	3,  // 6: wekalist.api.v1.OAuth2Config.field_mapping:type_name -> wekalist.api.v1.FieldMapping
+	3,  // 7: wekalist.api.v1.OIDCConfig.field_mapping:type_name -> wekalist.api.v1.FieldMapping
+	3,  // 8: wekalist.api.v1.LDAPConfig.field_mapping:type_name -> wekalist.api.v1.FieldMapping
+	14, // 9: wekalist.api.v1.LDAPConfig.group_role_mappings:type_name -> wekalist.api.v1.LDAPConfig.GroupRoleMapping
+	3,  // 10: wekalist.api.v1.SAMLConfig.field_mapping:type_name -> wekalist.api.v1.FieldMapping
+	1,  // 11: wekalist.api.v1.ListIdentityProvidersResponse.identity_providers:type_name -> wekalist.api.v1.IdentityProvider
+	1,  // 12: wekalist.api.v1.CreateIdentityProviderRequest.identity_provider:type_name -> wekalist.api.v1.IdentityProvider
+	1,  // 13: wekalist.api.v1.UpdateIdentityProviderRequest.identity_provider:type_name -> wekalist.api.v1.IdentityProvider
+	15, // 14: wekalist.api.v1.UpdateIdentityProviderRequest.update_mask:type_name -> google.protobuf.FieldMask
+	8,  // 15: wekalist.api.v1.IdentityProviderService.ListIdentityProviders:input_type -> wekalist.api.v1.ListIdentityProvidersRequest
+	10, // 16: wekalist.api.v1.IdentityProviderService.GetIdentityProvider:input_type -> wekalist.api.v1.GetIdentityProviderRequest
+	11, // 17: wekalist.api.v1.IdentityProviderService.CreateIdentityProvider:input_type -> wekalist.api.v1.CreateIdentityProviderRequest
+	12, // 18: wekalist.api.v1.IdentityProviderService.UpdateIdentityProvider:input_type -> wekalist.api.v1.UpdateIdentityProviderRequest
+	13, // 19: wekalist.api.v1.IdentityProviderService.DeleteIdentityProvider:input_type -> wekalist.api.v1.DeleteIdentityProviderRequest
+	9,  // 20: wekalist.api.v1.IdentityProviderService.ListIdentityProviders:output_type -> wekalist.api.v1.ListIdentityProvidersResponse
+	1,  // 21: wekalist.api.v1.IdentityProviderService.GetIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
+	1,  // 22: wekalist.api.v1.IdentityProviderService.CreateIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
+	1,  // 23: wekalist.api.v1.IdentityProviderService.UpdateIdentityProvider:output_type -> wekalist.api.v1.IdentityProvider
+	16, // 24: wekalist.api.v1.IdentityProviderService.DeleteIdentityProvider:output_type -> google.protobuf.Empty
+	20, // [20:25] is the sub-list for method output_type
+	15, // [15:20] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_idp_service_proto_init() }
@@ -783,6 +1235,9 @@ func file_api_v1_idp_service_proto_init() {
 	}
 	file_api_v1_idp_service_proto_msgTypes[1].OneofWrappers = []any{
 		(*IdentityProviderConfig_Oauth2Config)(nil),
+		(*IdentityProviderConfig_OidcConfig)(nil),
+		(*IdentityProviderConfig_LdapConfig)(nil),
+		(*IdentityProviderConfig_SamlConfig)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -790,7 +1245,7 @@ func file_api_v1_idp_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_idp_service_proto_rawDesc), len(file_api_v1_idp_service_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   10,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -11,6 +11,8 @@ import (
 	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,11 +25,120 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Urgency tells the push service how important the notification is, see RFC 8030.
+type SendNotificationRequest_Urgency int32
+
+const (
+	SendNotificationRequest_URGENCY_UNSPECIFIED SendNotificationRequest_Urgency = 0
+	SendNotificationRequest_VERY_LOW            SendNotificationRequest_Urgency = 1
+	SendNotificationRequest_LOW                 SendNotificationRequest_Urgency = 2
+	SendNotificationRequest_NORMAL              SendNotificationRequest_Urgency = 3
+	SendNotificationRequest_HIGH                SendNotificationRequest_Urgency = 4
+)
+
+// Enum value maps for SendNotificationRequest_Urgency.
+var (
+	SendNotificationRequest_Urgency_name = map[int32]string{
+		0: "URGENCY_UNSPECIFIED",
+		1: "VERY_LOW",
+		2: "LOW",
+		3: "NORMAL",
+		4: "HIGH",
+	}
+	SendNotificationRequest_Urgency_value = map[string]int32{
+		"URGENCY_UNSPECIFIED": 0,
+		"VERY_LOW":            1,
+		"LOW":                 2,
+		"NORMAL":              3,
+		"HIGH":                4,
+	}
+)
+
+func (x SendNotificationRequest_Urgency) Enum() *SendNotificationRequest_Urgency {
+	p := new(SendNotificationRequest_Urgency)
+	*p = x
+	return p
+}
+
+func (x SendNotificationRequest_Urgency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SendNotificationRequest_Urgency) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_subscription_service_proto_enumTypes[0].Descriptor()
+}
+
+func (SendNotificationRequest_Urgency) Type() protoreflect.EnumType {
+	return &file_api_v1_subscription_service_proto_enumTypes[0]
+}
+
+func (x SendNotificationRequest_Urgency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SendNotificationRequest_Urgency.Descriptor instead.
+func (SendNotificationRequest_Urgency) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_subscription_service_proto_rawDescGZIP(), []int{3, 0}
+}
+
+type PushDelivery_Status int32
+
+const (
+	PushDelivery_STATUS_UNSPECIFIED PushDelivery_Status = 0
+	// The push service accepted the notification.
+	PushDelivery_DELIVERED PushDelivery_Status = 1
+	// The subscription is gone and was deleted.
+	PushDelivery_EXPIRED PushDelivery_Status = 2
+	// The notification was rejected or every attempt failed.
+	PushDelivery_FAILED PushDelivery_Status = 3
+)
+
+// Enum value maps for PushDelivery_Status.
+var (
+	PushDelivery_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "DELIVERED",
+		2: "EXPIRED",
+		3: "FAILED",
+	}
+	PushDelivery_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"DELIVERED":          1,
+		"EXPIRED":            2,
+		"FAILED":             3,
+	}
+)
+
+func (x PushDelivery_Status) Enum() *PushDelivery_Status {
+	p := new(PushDelivery_Status)
+	*p = x
+	return p
+}
+
+func (x PushDelivery_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PushDelivery_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_subscription_service_proto_enumTypes[1].Descriptor()
+}
+
+func (PushDelivery_Status) Type() protoreflect.EnumType {
+	return &file_api_v1_subscription_service_proto_enumTypes[1]
+}
+
+func (x PushDelivery_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PushDelivery_Status.Descriptor instead.
+func (PushDelivery_Status) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_subscription_service_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type SubscriptionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
 	Keys          map[string]string      `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -70,20 +181,6 @@ func (x *SubscriptionRequest) GetEndpoint() string {
 	return ""
 }
 
-func (x *SubscriptionRequest) GetUsername() string {
-	if x != nil {
-		return x.Username
-	}
-	return ""
-}
-
-func (x *SubscriptionRequest) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
-}
-
 func (x *SubscriptionRequest) GetKeys() map[string]string {
 	if x != nil {
 		return x.Keys
@@ -94,7 +191,6 @@ func (x *SubscriptionRequest) GetKeys() map[string]string {
 type RemoveSubscriptionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,13 +232,6 @@ func (x *RemoveSubscriptionRequest) GetEndpoint() string {
 	return ""
 }
 
-func (x *RemoveSubscriptionRequest) GetUsername() string {
-	if x != nil {
-		return x.Username
-	}
-	return ""
-}
-
 type SubscriptionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -204,14 +293,22 @@ func (x *SubscriptionResponse) GetStatus() *status.Status {
 }
 
 type SendNotificationRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Username        string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Email           string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Payload         *NotificationPayload   `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	SendToAll       bool                   `protobuf:"varint,4,opt,name=send_to_all,json=sendToAll,proto3" json:"send_to_all,omitempty"`
-	SendToAllExcept string                 `protobuf:"bytes,5,opt,name=send_to_all_except,json=sendToAllExcept,proto3" json:"send_to_all_except,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The username of the user to notify.
+	Username  string               `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Email     string               `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Payload   *NotificationPayload `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	SendToAll bool                 `protobuf:"varint,4,opt,name=send_to_all,json=sendToAll,proto3" json:"send_to_all,omitempty"`
+	// The username of the user not to notify when sending to all.
+	SendToAllExcept string `protobuf:"bytes,5,opt,name=send_to_all_except,json=sendToAllExcept,proto3" json:"send_to_all_except,omitempty"`
+	// How long the push service keeps the notification while the browser is offline. Defaults to 60 seconds.
+	Ttl     *durationpb.Duration            `protobuf:"bytes,6,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Urgency SendNotificationRequest_Urgency `protobuf:"varint,7,opt,name=urgency,proto3,enum=wekalist.api.v1.SendNotificationRequest_Urgency" json:"urgency,omitempty"`
+	// A pending notification with the same topic is replaced by this one.
+	// At most 32 characters of the URL-safe base64 alphabet.
+	Topic         string `protobuf:"bytes,8,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendNotificationRequest) Reset() {
@@ -279,6 +376,27 @@ func (x *SendNotificationRequest) GetSendToAllExcept() string {
 	return ""
 }
 
+func (x *SendNotificationRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *SendNotificationRequest) GetUrgency() SendNotificationRequest_Urgency {
+	if x != nil {
+		return x.Urgency
+	}
+	return SendNotificationRequest_URGENCY_UNSPECIFIED
+}
+
+func (x *SendNotificationRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
 type NotificationPayload struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -364,10 +482,11 @@ func (x *NotificationPayload) GetData() map[string]string {
 }
 
 type SendNotificationResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	RecipientsCount int32                  `protobuf:"varint,3,opt,name=recipients_count,json=recipientsCount,proto3" json:"recipients_count,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The number of subscriptions the notification was queued for.
+	RecipientsCount int32 `protobuf:"varint,3,opt,name=recipients_count,json=recipientsCount,proto3" json:"recipients_count,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -423,32 +542,270 @@ func (x *SendNotificationResponse) GetRecipientsCount() int32 {
 	return 0
 }
 
+type ListPushDeliveriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The username of the user whose deliveries to list.
+	Username      string              `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Status        PushDelivery_Status `protobuf:"varint,2,opt,name=status,proto3,enum=wekalist.api.v1.PushDelivery_Status" json:"status,omitempty"`
+	PageSize      int32               `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string              `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPushDeliveriesRequest) Reset() {
+	*x = ListPushDeliveriesRequest{}
+	mi := &file_api_v1_subscription_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPushDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPushDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListPushDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_subscription_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPushDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListPushDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_subscription_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListPushDeliveriesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ListPushDeliveriesRequest) GetStatus() PushDelivery_Status {
+	if x != nil {
+		return x.Status
+	}
+	return PushDelivery_STATUS_UNSPECIFIED
+}
+
+func (x *ListPushDeliveriesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPushDeliveriesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListPushDeliveriesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PushDeliveries []*PushDelivery        `protobuf:"bytes,1,rep,name=push_deliveries,json=pushDeliveries,proto3" json:"push_deliveries,omitempty"`
+	NextPageToken  string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListPushDeliveriesResponse) Reset() {
+	*x = ListPushDeliveriesResponse{}
+	mi := &file_api_v1_subscription_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPushDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPushDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListPushDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_subscription_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPushDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListPushDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_subscription_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPushDeliveriesResponse) GetPushDeliveries() []*PushDelivery {
+	if x != nil {
+		return x.PushDeliveries
+	}
+	return nil
+}
+
+func (x *ListPushDeliveriesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// PushDelivery is the outcome of delivering a push notification to a subscription.
+type PushDelivery struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Endpoint string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Topic    string                 `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Status   PushDelivery_Status    `protobuf:"varint,5,opt,name=status,proto3,enum=wekalist.api.v1.PushDelivery_Status" json:"status,omitempty"`
+	// The HTTP status code of the last response of the push service, 0 if none.
+	StatusCode    int32                  `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Attempts      int32                  `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushDelivery) Reset() {
+	*x = PushDelivery{}
+	mi := &file_api_v1_subscription_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushDelivery) ProtoMessage() {}
+
+func (x *PushDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_subscription_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushDelivery.ProtoReflect.Descriptor instead.
+func (*PushDelivery) Descriptor() ([]byte, []int) {
+	return file_api_v1_subscription_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PushDelivery) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PushDelivery) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PushDelivery) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *PushDelivery) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *PushDelivery) GetStatus() PushDelivery_Status {
+	if x != nil {
+		return x.Status
+	}
+	return PushDelivery_STATUS_UNSPECIFIED
+}
+
+func (x *PushDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *PushDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *PushDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PushDelivery) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
 var File_api_v1_subscription_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_subscription_service_proto_rawDesc = "" +
 	"\n" +
-	"!api/v1/subscription_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/rpc/status.proto\"\xf4\x01\n" +
+	"!api/v1/subscription_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"\xd5\x01\n" +
 	"\x13SubscriptionRequest\x12\x1f\n" +
-	"\bendpoint\x18\x01 \x01(\tB\x03\xe0A\x02R\bendpoint\x12\x1f\n" +
-	"\busername\x18\x02 \x01(\tB\x03\xe0A\x02R\busername\x12\x19\n" +
-	"\x05email\x18\x03 \x01(\tB\x03\xe0A\x01R\x05email\x12G\n" +
+	"\bendpoint\x18\x01 \x01(\tB\x03\xe0A\x02R\bendpoint\x12G\n" +
 	"\x04keys\x18\x04 \x03(\v2..wekalist.api.v1.SubscriptionRequest.KeysEntryB\x03\xe0A\x02R\x04keys\x1a7\n" +
 	"\tKeysEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\busernameR\x05email\"L\n" +
 	"\x19RemoveSubscriptionRequest\x12\x1f\n" +
-	"\bendpoint\x18\x01 \x01(\tB\x03\xe0A\x02R\bendpoint\x12\x1f\n" +
-	"\busername\x18\x02 \x01(\tB\x03\xe0A\x02R\busername\"{\n" +
+	"\bendpoint\x18\x01 \x01(\tB\x03\xe0A\x02R\bendpointJ\x04\b\x02\x10\x03R\busername\"{\n" +
 	"\x14SubscriptionResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
-	"\x06status\x18\x03 \x01(\v2\x12.google.rpc.StatusB\x03\xe0A\x01R\x06status\"\xf1\x01\n" +
+	"\x06status\x18\x03 \x01(\v2\x12.google.rpc.StatusB\x03\xe0A\x01R\x06status\"\xe0\x03\n" +
 	"\x17SendNotificationRequest\x12\x1f\n" +
 	"\busername\x18\x01 \x01(\tB\x03\xe0A\x01R\busername\x12\x19\n" +
 	"\x05email\x18\x02 \x01(\tB\x03\xe0A\x01R\x05email\x12C\n" +
 	"\apayload\x18\x03 \x01(\v2$.wekalist.api.v1.NotificationPayloadB\x03\xe0A\x02R\apayload\x12#\n" +
 	"\vsend_to_all\x18\x04 \x01(\bB\x03\xe0A\x01R\tsendToAll\x120\n" +
-	"\x12send_to_all_except\x18\x05 \x01(\tB\x03\xe0A\x01R\x0fsendToAllExcept\"\x96\x02\n" +
+	"\x12send_to_all_except\x18\x05 \x01(\tB\x03\xe0A\x01R\x0fsendToAllExcept\x120\n" +
+	"\x03ttl\x18\x06 \x01(\v2\x19.google.protobuf.DurationB\x03\xe0A\x01R\x03ttl\x12O\n" +
+	"\aurgency\x18\a \x01(\x0e20.wekalist.api.v1.SendNotificationRequest.UrgencyB\x03\xe0A\x01R\aurgency\x12\x19\n" +
+	"\x05topic\x18\b \x01(\tB\x03\xe0A\x01R\x05topic\"O\n" +
+	"\aUrgency\x12\x17\n" +
+	"\x13URGENCY_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bVERY_LOW\x10\x01\x12\a\n" +
+	"\x03LOW\x10\x02\x12\n" +
+	"\n" +
+	"\x06NORMAL\x10\x03\x12\b\n" +
+	"\x04HIGH\x10\x04\"\x96\x02\n" +
 	"\x13NotificationPayload\x12\x19\n" +
 	"\x05title\x18\x01 \x01(\tB\x03\xe0A\x02R\x05title\x12\x17\n" +
 	"\x04body\x18\x02 \x01(\tB\x03\xe0A\x02R\x04body\x12\x17\n" +
@@ -462,11 +819,39 @@ const file_api_v1_subscription_service_proto_rawDesc = "" +
 	"\x18SendNotificationResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12)\n" +
-	"\x10recipients_count\x18\x03 \x01(\x05R\x0frecipientsCount2\xb3\x03\n" +
+	"\x10recipients_count\x18\x03 \x01(\x05R\x0frecipientsCount\"\xc5\x01\n" +
+	"\x19ListPushDeliveriesRequest\x12\x1f\n" +
+	"\busername\x18\x01 \x01(\tB\x03\xe0A\x01R\busername\x12A\n" +
+	"\x06status\x18\x02 \x01(\x0e2$.wekalist.api.v1.PushDelivery.StatusB\x03\xe0A\x01R\x06status\x12 \n" +
+	"\tpage_size\x18\x03 \x01(\x05B\x03\xe0A\x01R\bpageSize\x12\"\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tB\x03\xe0A\x01R\tpageToken\"\x8c\x01\n" +
+	"\x1aListPushDeliveriesResponse\x12F\n" +
+	"\x0fpush_deliveries\x18\x01 \x03(\v2\x1d.wekalist.api.v1.PushDeliveryR\x0epushDeliveries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x84\x03\n" +
+	"\fPushDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12\x14\n" +
+	"\x05topic\x18\x04 \x01(\tR\x05topic\x12<\n" +
+	"\x06status\x18\x05 \x01(\x0e2$.wekalist.api.v1.PushDelivery.StatusR\x06status\x12\x1f\n" +
+	"\vstatus_code\x18\x06 \x01(\x05R\n" +
+	"statusCode\x12\x1a\n" +
+	"\battempts\x18\a \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12;\n" +
+	"\vcreate_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\"H\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tDELIVERED\x10\x01\x12\v\n" +
+	"\aEXPIRED\x10\x02\x12\n" +
+	"\n" +
+	"\x06FAILED\x10\x032\xc9\x04\n" +
 	"\x13SubscriptionService\x12x\n" +
 	"\x0fAddSubscription\x12$.wekalist.api.v1.SubscriptionRequest\x1a%.wekalist.api.v1.SubscriptionResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/subscribe\x12\x94\x01\n" +
 	"\x12RemoveSubscription\x12*.wekalist.api.v1.RemoveSubscriptionRequest\x1a%.wekalist.api.v1.SubscriptionResponse\"+\x82\xd3\xe4\x93\x02%Z\x14:\x01*\"\x0f/v1/unsubscribe*\r/v1/subscribe\x12\x8a\x01\n" +
-	"\x10SendNotification\x12(.wekalist.api.v1.SendNotificationRequest\x1a).wekalist.api.v1.SendNotificationResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/notifications/sendB\xc0\x01\n" +
+	"\x10SendNotification\x12(.wekalist.api.v1.SendNotificationRequest\x1a).wekalist.api.v1.SendNotificationResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/notifications/send\x12\x93\x01\n" +
+	"\x12ListPushDeliveries\x12*.wekalist.api.v1.ListPushDeliveriesRequest\x1a+.wekalist.api.v1.ListPushDeliveriesResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/notifications/deliveriesB\xc0\x01\n" +
 	"\x13com.wekalist.api.v1B\x18SubscriptionServiceProtoP\x01Z1github.com/imrany/wekalist/proto/gen/api/v1;apiv1\xa2\x02\x03WAX\xaa\x02\x0fWekalist.Api.V1\xca\x02\x0fWekalist\\Api\\V1\xe2\x02\x1bWekalist\\Api\\V1\\GPBMetadata\xea\x02\x11Wekalist::Api::V1b\x06proto3"
 
 var (
@@ -481,34 +866,50 @@ func file_api_v1_subscription_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_subscription_service_proto_rawDescData
 }
 
-var file_api_v1_subscription_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_v1_subscription_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_v1_subscription_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_v1_subscription_service_proto_goTypes = []any{
-	(*SubscriptionRequest)(nil),       // 0: wekalist.api.v1.SubscriptionRequest
-	(*RemoveSubscriptionRequest)(nil), // 1: wekalist.api.v1.RemoveSubscriptionRequest
-	(*SubscriptionResponse)(nil),      // 2: wekalist.api.v1.SubscriptionResponse
-	(*SendNotificationRequest)(nil),   // 3: wekalist.api.v1.SendNotificationRequest
-	(*NotificationPayload)(nil),       // 4: wekalist.api.v1.NotificationPayload
-	(*SendNotificationResponse)(nil),  // 5: wekalist.api.v1.SendNotificationResponse
-	nil,                               // 6: wekalist.api.v1.SubscriptionRequest.KeysEntry
-	nil,                               // 7: wekalist.api.v1.NotificationPayload.DataEntry
-	(*status.Status)(nil),             // 8: google.rpc.Status
+	(SendNotificationRequest_Urgency)(0), // 0: wekalist.api.v1.SendNotificationRequest.Urgency
+	(PushDelivery_Status)(0),             // 1: wekalist.api.v1.PushDelivery.Status
+	(*SubscriptionRequest)(nil),          // 2: wekalist.api.v1.SubscriptionRequest
+	(*RemoveSubscriptionRequest)(nil),    // 3: wekalist.api.v1.RemoveSubscriptionRequest
+	(*SubscriptionResponse)(nil),         // 4: wekalist.api.v1.SubscriptionResponse
+	(*SendNotificationRequest)(nil),      // 5: wekalist.api.v1.SendNotificationRequest
+	(*NotificationPayload)(nil),          // 6: wekalist.api.v1.NotificationPayload
+	(*SendNotificationResponse)(nil),     // 7: wekalist.api.v1.SendNotificationResponse
+	(*ListPushDeliveriesRequest)(nil),    // 8: wekalist.api.v1.ListPushDeliveriesRequest
+	(*ListPushDeliveriesResponse)(nil),   // 9: wekalist.api.v1.ListPushDeliveriesResponse
+	(*PushDelivery)(nil),                 // 10: wekalist.api.v1.PushDelivery
+	nil,                                  // 11: wekalist.api.v1.SubscriptionRequest.KeysEntry
+	nil,                                  // 12: wekalist.api.v1.NotificationPayload.DataEntry
+	(*status.Status)(nil),                // 13: google.rpc.Status
+	(*durationpb.Duration)(nil),          // 14: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),        // 15: google.protobuf.Timestamp
 }
 var file_api_v1_subscription_service_proto_depIdxs = []int32{
-	6, // 0: wekalist.api.v1.SubscriptionRequest.keys:type_name -> wekalist.api.v1.SubscriptionRequest.KeysEntry
-	8, // 1: wekalist.api.v1.SubscriptionResponse.status:type_name -> google.rpc.Status
-	4, // 2: wekalist.api.v1.SendNotificationRequest.payload:type_name -> wekalist.api.v1.NotificationPayload
-	7, // 3: wekalist.api.v1.NotificationPayload.data:type_name -> wekalist.api.v1.NotificationPayload.DataEntry
-	0, // 4: wekalist.api.v1.SubscriptionService.AddSubscription:input_type -> wekalist.api.v1.SubscriptionRequest
-	1, // 5: wekalist.api.v1.SubscriptionService.RemoveSubscription:input_type -> wekalist.api.v1.RemoveSubscriptionRequest
-	3, // 6: wekalist.api.v1.SubscriptionService.SendNotification:input_type -> wekalist.api.v1.SendNotificationRequest
-	2, // 7: wekalist.api.v1.SubscriptionService.AddSubscription:output_type -> wekalist.api.v1.SubscriptionResponse
-	2, // 8: wekalist.api.v1.SubscriptionService.RemoveSubscription:output_type -> wekalist.api.v1.SubscriptionResponse
-	5, // 9: wekalist.api.v1.SubscriptionService.SendNotification:output_type -> wekalist.api.v1.SendNotificationResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	11, // 0: wekalist.api.v1.SubscriptionRequest.keys:type_name -> wekalist.api.v1.SubscriptionRequest.KeysEntry
+	13, // 1: wekalist.api.v1.SubscriptionResponse.status:type_name -> google.rpc.Status
+	6,  // 2: wekalist.api.v1.SendNotificationRequest.payload:type_name -> wekalist.api.v1.NotificationPayload
+	14, // 3: wekalist.api.v1.SendNotificationRequest.ttl:type_name -> google.protobuf.Duration
+	0,  // 4: wekalist.api.v1.SendNotificationRequest.urgency:type_name -> wekalist.api.v1.SendNotificationRequest.Urgency
+	12, // 5: wekalist.api.v1.NotificationPayload.data:type_name -> wekalist.api.v1.NotificationPayload.DataEntry
+	1,  // 6: wekalist.api.v1.ListPushDeliveriesRequest.status:type_name -> wekalist.api.v1.PushDelivery.Status
+	10, // 7: wekalist.api.v1.ListPushDeliveriesResponse.push_deliveries:type_name -> wekalist.api.v1.PushDelivery
+	1,  // 8: wekalist.api.v1.PushDelivery.status:type_name -> wekalist.api.v1.PushDelivery.Status
+	15, // 9: wekalist.api.v1.PushDelivery.create_time:type_name -> google.protobuf.Timestamp
+	2,  // 10: wekalist.api.v1.SubscriptionService.AddSubscription:input_type -> wekalist.api.v1.SubscriptionRequest
+	3,  // 11: wekalist.api.v1.SubscriptionService.RemoveSubscription:input_type -> wekalist.api.v1.RemoveSubscriptionRequest
+	5,  // 12: wekalist.api.v1.SubscriptionService.SendNotification:input_type -> wekalist.api.v1.SendNotificationRequest
+	8,  // 13: wekalist.api.v1.SubscriptionService.ListPushDeliveries:input_type -> wekalist.api.v1.ListPushDeliveriesRequest
+	4,  // 14: wekalist.api.v1.SubscriptionService.AddSubscription:output_type -> wekalist.api.v1.SubscriptionResponse
+	4,  // 15: wekalist.api.v1.SubscriptionService.RemoveSubscription:output_type -> wekalist.api.v1.SubscriptionResponse
+	7,  // 16: wekalist.api.v1.SubscriptionService.SendNotification:output_type -> wekalist.api.v1.SendNotificationResponse
+	9,  // 17: wekalist.api.v1.SubscriptionService.ListPushDeliveries:output_type -> wekalist.api.v1.ListPushDeliveriesResponse
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_subscription_service_proto_init() }
@@ -521,13 +922,14 @@ func file_api_v1_subscription_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_subscription_service_proto_rawDesc), len(file_api_v1_subscription_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_v1_subscription_service_proto_goTypes,
 		DependencyIndexes: file_api_v1_subscription_service_proto_depIdxs,
+		EnumInfos:         file_api_v1_subscription_service_proto_enumTypes,
 		MessageInfos:      file_api_v1_subscription_service_proto_msgTypes,
 	}.Build()
 	File_api_v1_subscription_service_proto = out.File
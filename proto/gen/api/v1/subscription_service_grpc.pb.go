@@ -22,15 +22,21 @@ const (
 	SubscriptionService_AddSubscription_FullMethodName    = "/wekalist.api.v1.SubscriptionService/AddSubscription"
 	SubscriptionService_RemoveSubscription_FullMethodName = "/wekalist.api.v1.SubscriptionService/RemoveSubscription"
 	SubscriptionService_SendNotification_FullMethodName   = "/wekalist.api.v1.SubscriptionService/SendNotification"
+	SubscriptionService_ListPushDeliveries_FullMethodName = "/wekalist.api.v1.SubscriptionService/ListPushDeliveries"
 )
 
 // SubscriptionServiceClient is the client API for SubscriptionService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SubscriptionServiceClient interface {
+	// AddSubscription subscribes the browser of the current user to push notifications.
 	AddSubscription(ctx context.Context, in *SubscriptionRequest, opts ...grpc.CallOption) (*SubscriptionResponse, error)
+	// RemoveSubscription removes the subscription of the endpoint, if it belongs to the current user.
 	RemoveSubscription(ctx context.Context, in *RemoveSubscriptionRequest, opts ...grpc.CallOption) (*SubscriptionResponse, error)
+	// SendNotification queues a push notification to users. Admins only.
 	SendNotification(ctx context.Context, in *SendNotificationRequest, opts ...grpc.CallOption) (*SendNotificationResponse, error)
+	// ListPushDeliveries lists the outcomes of the push notification deliveries, the latest first. Admins only.
+	ListPushDeliveries(ctx context.Context, in *ListPushDeliveriesRequest, opts ...grpc.CallOption) (*ListPushDeliveriesResponse, error)
 }
 
 type subscriptionServiceClient struct {
@@ -71,13 +77,28 @@ func (c *subscriptionServiceClient) SendNotification(ctx context.Context, in *Se
 	return out, nil
 }
 
+func (c *subscriptionServiceClient) ListPushDeliveries(ctx context.Context, in *ListPushDeliveriesRequest, opts ...grpc.CallOption) (*ListPushDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPushDeliveriesResponse)
+	err := c.cc.Invoke(ctx, SubscriptionService_ListPushDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubscriptionServiceServer is the server API for SubscriptionService service.
 // All implementations must embed UnimplementedSubscriptionServiceServer
 // for forward compatibility.
 type SubscriptionServiceServer interface {
+	// AddSubscription subscribes the browser of the current user to push notifications.
 	AddSubscription(context.Context, *SubscriptionRequest) (*SubscriptionResponse, error)
+	// RemoveSubscription removes the subscription of the endpoint, if it belongs to the current user.
 	RemoveSubscription(context.Context, *RemoveSubscriptionRequest) (*SubscriptionResponse, error)
+	// SendNotification queues a push notification to users. Admins only.
 	SendNotification(context.Context, *SendNotificationRequest) (*SendNotificationResponse, error)
+	// ListPushDeliveries lists the outcomes of the push notification deliveries, the latest first. Admins only.
+	ListPushDeliveries(context.Context, *ListPushDeliveriesRequest) (*ListPushDeliveriesResponse, error)
 	mustEmbedUnimplementedSubscriptionServiceServer()
 }
 
@@ -97,6 +118,9 @@ func (UnimplementedSubscriptionServiceServer) RemoveSubscription(context.Context
 func (UnimplementedSubscriptionServiceServer) SendNotification(context.Context, *SendNotificationRequest) (*SendNotificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendNotification not implemented")
 }
+func (UnimplementedSubscriptionServiceServer) ListPushDeliveries(context.Context, *ListPushDeliveriesRequest) (*ListPushDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPushDeliveries not implemented")
+}
 func (UnimplementedSubscriptionServiceServer) mustEmbedUnimplementedSubscriptionServiceServer() {}
 func (UnimplementedSubscriptionServiceServer) testEmbeddedByValue()                             {}
 
@@ -172,6 +196,24 @@ func _SubscriptionService_SendNotification_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SubscriptionService_ListPushDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPushDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionServiceServer).ListPushDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionService_ListPushDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionServiceServer).ListPushDeliveries(ctx, req.(*ListPushDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SubscriptionService_ServiceDesc is the grpc.ServiceDesc for SubscriptionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +233,10 @@ var SubscriptionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendNotification",
 			Handler:    _SubscriptionService_SendNotification_Handler,
 		},
+		{
+			MethodName: "ListPushDeliveries",
+			Handler:    _SubscriptionService_ListPushDeliveries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/subscription_service.proto",
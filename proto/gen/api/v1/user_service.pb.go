@@ -83,6 +83,179 @@ func (User_Role) EnumDescriptor() ([]byte, []int) {
 	return file_api_v1_user_service_proto_rawDescGZIP(), []int{0, 0}
 }
 
+// How often the user is emailed a digest of new memos, comments and inbox items.
+type UserSetting_DigestFrequency int32
+
+const (
+	// No digest is sent.
+	UserSetting_DIGEST_FREQUENCY_UNSPECIFIED UserSetting_DigestFrequency = 0
+	UserSetting_DAILY                        UserSetting_DigestFrequency = 1
+	UserSetting_WEEKLY                       UserSetting_DigestFrequency = 2
+)
+
+// Enum value maps for UserSetting_DigestFrequency.
+var (
+	UserSetting_DigestFrequency_name = map[int32]string{
+		0: "DIGEST_FREQUENCY_UNSPECIFIED",
+		1: "DAILY",
+		2: "WEEKLY",
+	}
+	UserSetting_DigestFrequency_value = map[string]int32{
+		"DIGEST_FREQUENCY_UNSPECIFIED": 0,
+		"DAILY":                        1,
+		"WEEKLY":                       2,
+	}
+)
+
+func (x UserSetting_DigestFrequency) Enum() *UserSetting_DigestFrequency {
+	p := new(UserSetting_DigestFrequency)
+	*p = x
+	return p
+}
+
+func (x UserSetting_DigestFrequency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserSetting_DigestFrequency) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_user_service_proto_enumTypes[1].Descriptor()
+}
+
+func (UserSetting_DigestFrequency) Type() protoreflect.EnumType {
+	return &file_api_v1_user_service_proto_enumTypes[1]
+}
+
+func (x UserSetting_DigestFrequency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserSetting_DigestFrequency.Descriptor instead.
+func (UserSetting_DigestFrequency) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{14, 0}
+}
+
+// The events users are notified of.
+type NotificationPreference_EventType int32
+
+const (
+	// Unspecified event type.
+	NotificationPreference_EVENT_TYPE_UNSPECIFIED NotificationPreference_EventType = 0
+	// A comment on a memo of the user.
+	NotificationPreference_COMMENT NotificationPreference_EventType = 1
+	// A memo mentioning the user.
+	NotificationPreference_MENTION NotificationPreference_EventType = 2
+	// A reaction to a memo of the user.
+	NotificationPreference_REACTION NotificationPreference_EventType = 3
+	// A reminder of the user. Not sent yet, so not offered as a preference.
+	NotificationPreference_REMINDER NotificationPreference_EventType = 4
+	// A new version is available. Not sent yet, so not offered as a preference.
+	NotificationPreference_VERSION_UPDATE NotificationPreference_EventType = 5
+)
+
+// Enum value maps for NotificationPreference_EventType.
+var (
+	NotificationPreference_EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "COMMENT",
+		2: "MENTION",
+		3: "REACTION",
+		4: "REMINDER",
+		5: "VERSION_UPDATE",
+	}
+	NotificationPreference_EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED": 0,
+		"COMMENT":                1,
+		"MENTION":                2,
+		"REACTION":               3,
+		"REMINDER":               4,
+		"VERSION_UPDATE":         5,
+	}
+)
+
+func (x NotificationPreference_EventType) Enum() *NotificationPreference_EventType {
+	p := new(NotificationPreference_EventType)
+	*p = x
+	return p
+}
+
+func (x NotificationPreference_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationPreference_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_user_service_proto_enumTypes[2].Descriptor()
+}
+
+func (NotificationPreference_EventType) Type() protoreflect.EnumType {
+	return &file_api_v1_user_service_proto_enumTypes[2]
+}
+
+func (x NotificationPreference_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationPreference_EventType.Descriptor instead.
+func (NotificationPreference_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{15, 0}
+}
+
+// The channels notifications are delivered through.
+type NotificationPreference_Channel int32
+
+const (
+	// Unspecified channel.
+	NotificationPreference_CHANNEL_UNSPECIFIED NotificationPreference_Channel = 0
+	// The inbox of the user.
+	NotificationPreference_INBOX NotificationPreference_Channel = 1
+	// Web push notifications to the subscribed browsers of the user.
+	NotificationPreference_PUSH NotificationPreference_Channel = 2
+	// Emails sent to the email address of the user.
+	NotificationPreference_EMAIL NotificationPreference_Channel = 3
+)
+
+// Enum value maps for NotificationPreference_Channel.
+var (
+	NotificationPreference_Channel_name = map[int32]string{
+		0: "CHANNEL_UNSPECIFIED",
+		1: "INBOX",
+		2: "PUSH",
+		3: "EMAIL",
+	}
+	NotificationPreference_Channel_value = map[string]int32{
+		"CHANNEL_UNSPECIFIED": 0,
+		"INBOX":               1,
+		"PUSH":                2,
+		"EMAIL":               3,
+	}
+)
+
+func (x NotificationPreference_Channel) Enum() *NotificationPreference_Channel {
+	p := new(NotificationPreference_Channel)
+	*p = x
+	return p
+}
+
+func (x NotificationPreference_Channel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationPreference_Channel) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_user_service_proto_enumTypes[3].Descriptor()
+}
+
+func (NotificationPreference_Channel) Type() protoreflect.EnumType {
+	return &file_api_v1_user_service_proto_enumTypes[3]
+}
+
+func (x NotificationPreference_Channel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationPreference_Channel.Descriptor instead.
+func (NotificationPreference_Channel) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{15, 1}
+}
+
 type User struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The resource name of the user.
@@ -312,9 +485,7 @@ func (x *VerifyRequest) GetEmail() string {
 type VerifyResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The user email
-	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	// The One Time Password (OTP)
-	Otp           string `protobuf:"bytes,2,opt,name=otp,proto3" json:"otp,omitempty"`
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -356,13 +527,6 @@ func (x *VerifyResponse) GetEmail() string {
 	return ""
 }
 
-func (x *VerifyResponse) GetOtp() string {
-	if x != nil {
-		return x.Otp
-	}
-	return ""
-}
-
 type ListUsersRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Optional. The maximum number of users to return.
@@ -584,7 +748,10 @@ type CreateUserRequest struct {
 	ValidateOnly bool `protobuf:"varint,3,opt,name=validate_only,json=validateOnly,proto3" json:"validate_only,omitempty"`
 	// Optional. An idempotency token that can be used to ensure that multiple
 	// requests to create a user have the same result.
-	RequestId     string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Optional. The verification code sent by VerifyUser.
+	// Required when email verification is enabled.
+	Otp           string `protobuf:"bytes,5,opt,name=otp,proto3" json:"otp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -647,6 +814,13 @@ func (x *CreateUserRequest) GetRequestId() string {
 	return ""
 }
 
+func (x *CreateUserRequest) GetOtp() string {
+	if x != nil {
+		return x.Otp
+	}
+	return ""
+}
+
 type UpdateUserRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Required. The user to update.
@@ -1099,8 +1273,14 @@ type UserSetting struct {
 	WrapperUsageCounter int32 `protobuf:"varint,8,opt,name=wrapper_usage_counter,json=wrapperUsageCounter,proto3" json:"wrapper_usage_counter,omitempty"`
 	// This is the maximum wrapper usage
 	WrapperMaxUsage int32 `protobuf:"varint,9,opt,name=wrapper_max_usage,json=wrapperMaxUsage,proto3" json:"wrapper_max_usage,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Whether two-factor authentication is enabled for the user.
+	TwoFactorEnabled bool `protobuf:"varint,10,opt,name=two_factor_enabled,json=twoFactorEnabled,proto3" json:"two_factor_enabled,omitempty"`
+	// The notification preferences of the user, one per event type and channel.
+	// Updating them replaces the preferences of the listed event types and channels.
+	NotificationPreferences []*NotificationPreference   `protobuf:"bytes,11,rep,name=notification_preferences,json=notificationPreferences,proto3" json:"notification_preferences,omitempty"`
+	DigestFrequency         UserSetting_DigestFrequency `protobuf:"varint,12,opt,name=digest_frequency,json=digestFrequency,proto3,enum=wekalist.api.v1.UserSetting_DigestFrequency" json:"digest_frequency,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *UserSetting) Reset() {
@@ -1196,6 +1376,88 @@ func (x *UserSetting) GetWrapperMaxUsage() int32 {
 	return 0
 }
 
+func (x *UserSetting) GetTwoFactorEnabled() bool {
+	if x != nil {
+		return x.TwoFactorEnabled
+	}
+	return false
+}
+
+func (x *UserSetting) GetNotificationPreferences() []*NotificationPreference {
+	if x != nil {
+		return x.NotificationPreferences
+	}
+	return nil
+}
+
+func (x *UserSetting) GetDigestFrequency() UserSetting_DigestFrequency {
+	if x != nil {
+		return x.DigestFrequency
+	}
+	return UserSetting_DIGEST_FREQUENCY_UNSPECIFIED
+}
+
+// NotificationPreference enables or disables a channel for a type of events.
+type NotificationPreference struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	EventType     NotificationPreference_EventType `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=wekalist.api.v1.NotificationPreference_EventType" json:"event_type,omitempty"`
+	Channel       NotificationPreference_Channel   `protobuf:"varint,2,opt,name=channel,proto3,enum=wekalist.api.v1.NotificationPreference_Channel" json:"channel,omitempty"`
+	Enabled       bool                             `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationPreference) Reset() {
+	*x = NotificationPreference{}
+	mi := &file_api_v1_user_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreference) ProtoMessage() {}
+
+func (x *NotificationPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreference.ProtoReflect.Descriptor instead.
+func (*NotificationPreference) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NotificationPreference) GetEventType() NotificationPreference_EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return NotificationPreference_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *NotificationPreference) GetChannel() NotificationPreference_Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return NotificationPreference_CHANNEL_UNSPECIFIED
+}
+
+func (x *NotificationPreference) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
 type GetUserSettingRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Required. The resource name of the user.
@@ -1207,7 +1469,7 @@ type GetUserSettingRequest struct {
 
 func (x *GetUserSettingRequest) Reset() {
 	*x = GetUserSettingRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[15]
+	mi := &file_api_v1_user_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1219,7 +1481,7 @@ func (x *GetUserSettingRequest) String() string {
 func (*GetUserSettingRequest) ProtoMessage() {}
 
 func (x *GetUserSettingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[15]
+	mi := &file_api_v1_user_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1232,7 +1494,7 @@ func (x *GetUserSettingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserSettingRequest.ProtoReflect.Descriptor instead.
 func (*GetUserSettingRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{15}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetUserSettingRequest) GetName() string {
@@ -1254,7 +1516,7 @@ type UpdateUserSettingRequest struct {
 
 func (x *UpdateUserSettingRequest) Reset() {
 	*x = UpdateUserSettingRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[16]
+	mi := &file_api_v1_user_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1266,7 +1528,7 @@ func (x *UpdateUserSettingRequest) String() string {
 func (*UpdateUserSettingRequest) ProtoMessage() {}
 
 func (x *UpdateUserSettingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[16]
+	mi := &file_api_v1_user_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1279,7 +1541,7 @@ func (x *UpdateUserSettingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserSettingRequest.ProtoReflect.Descriptor instead.
 func (*UpdateUserSettingRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{16}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UpdateUserSettingRequest) GetSetting() *UserSetting {
@@ -1303,20 +1565,26 @@ type UserAccessToken struct {
 	// Format: users/{user}/accessTokens/{access_token}
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Output only. The access token value.
+	// Only returned when the access token is created, since only its hash is stored.
 	AccessToken string `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
 	// The description of the access token.
 	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	// Output only. The issued timestamp.
 	IssuedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
 	// Optional. The expiration timestamp.
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Optional. The scopes granted to the access token: memos:read, memos:write, attachments:write and admin.
+	// The admin scope grants the full rights of the user. Defaults to memos:read.
+	Scopes []string `protobuf:"bytes,6,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// Output only. The last time the access token was used.
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UserAccessToken) Reset() {
 	*x = UserAccessToken{}
-	mi := &file_api_v1_user_service_proto_msgTypes[17]
+	mi := &file_api_v1_user_service_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1328,7 +1596,7 @@ func (x *UserAccessToken) String() string {
 func (*UserAccessToken) ProtoMessage() {}
 
 func (x *UserAccessToken) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[17]
+	mi := &file_api_v1_user_service_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1341,7 +1609,7 @@ func (x *UserAccessToken) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserAccessToken.ProtoReflect.Descriptor instead.
 func (*UserAccessToken) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{17}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UserAccessToken) GetName() string {
@@ -1379,6 +1647,20 @@ func (x *UserAccessToken) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *UserAccessToken) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *UserAccessToken) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
 type ListUserAccessTokensRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Required. The parent resource whose access tokens will be listed.
@@ -1394,7 +1676,7 @@ type ListUserAccessTokensRequest struct {
 
 func (x *ListUserAccessTokensRequest) Reset() {
 	*x = ListUserAccessTokensRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[18]
+	mi := &file_api_v1_user_service_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1406,7 +1688,7 @@ func (x *ListUserAccessTokensRequest) String() string {
 func (*ListUserAccessTokensRequest) ProtoMessage() {}
 
 func (x *ListUserAccessTokensRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[18]
+	mi := &file_api_v1_user_service_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1419,7 +1701,7 @@ func (x *ListUserAccessTokensRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserAccessTokensRequest.ProtoReflect.Descriptor instead.
 func (*ListUserAccessTokensRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{18}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListUserAccessTokensRequest) GetParent() string {
@@ -1457,7 +1739,7 @@ type ListUserAccessTokensResponse struct {
 
 func (x *ListUserAccessTokensResponse) Reset() {
 	*x = ListUserAccessTokensResponse{}
-	mi := &file_api_v1_user_service_proto_msgTypes[19]
+	mi := &file_api_v1_user_service_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1469,7 +1751,7 @@ func (x *ListUserAccessTokensResponse) String() string {
 func (*ListUserAccessTokensResponse) ProtoMessage() {}
 
 func (x *ListUserAccessTokensResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[19]
+	mi := &file_api_v1_user_service_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1482,7 +1764,7 @@ func (x *ListUserAccessTokensResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserAccessTokensResponse.ProtoReflect.Descriptor instead.
 func (*ListUserAccessTokensResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{19}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ListUserAccessTokensResponse) GetAccessTokens() []*UserAccessToken {
@@ -1521,7 +1803,7 @@ type CreateUserAccessTokenRequest struct {
 
 func (x *CreateUserAccessTokenRequest) Reset() {
 	*x = CreateUserAccessTokenRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[20]
+	mi := &file_api_v1_user_service_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1533,7 +1815,7 @@ func (x *CreateUserAccessTokenRequest) String() string {
 func (*CreateUserAccessTokenRequest) ProtoMessage() {}
 
 func (x *CreateUserAccessTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[20]
+	mi := &file_api_v1_user_service_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1546,7 +1828,7 @@ func (x *CreateUserAccessTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateUserAccessTokenRequest.ProtoReflect.Descriptor instead.
 func (*CreateUserAccessTokenRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{20}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CreateUserAccessTokenRequest) GetParent() string {
@@ -1581,7 +1863,7 @@ type DeleteUserAccessTokenRequest struct {
 
 func (x *DeleteUserAccessTokenRequest) Reset() {
 	*x = DeleteUserAccessTokenRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[21]
+	mi := &file_api_v1_user_service_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1593,7 +1875,7 @@ func (x *DeleteUserAccessTokenRequest) String() string {
 func (*DeleteUserAccessTokenRequest) ProtoMessage() {}
 
 func (x *DeleteUserAccessTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[21]
+	mi := &file_api_v1_user_service_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1606,7 +1888,7 @@ func (x *DeleteUserAccessTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteUserAccessTokenRequest.ProtoReflect.Descriptor instead.
 func (*DeleteUserAccessTokenRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{21}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *DeleteUserAccessTokenRequest) GetName() string {
@@ -1626,17 +1908,19 @@ type UserSession struct {
 	// The timestamp when the session was created.
 	CreateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
 	// The timestamp when the session was last accessed.
-	// Used for sliding expiration calculation (last_accessed_time + 2 weeks).
+	// Sessions idle for 2 weeks expire.
 	LastAccessedTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_accessed_time,json=lastAccessedTime,proto3" json:"last_accessed_time,omitempty"`
 	// Client information associated with this session.
-	ClientInfo    *UserSession_ClientInfo `protobuf:"bytes,5,opt,name=client_info,json=clientInfo,proto3" json:"client_info,omitempty"`
+	ClientInfo *UserSession_ClientInfo `protobuf:"bytes,5,opt,name=client_info,json=clientInfo,proto3" json:"client_info,omitempty"`
+	// The timestamp when the session expires, either by being idle or by reaching its maximum lifetime.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UserSession) Reset() {
 	*x = UserSession{}
-	mi := &file_api_v1_user_service_proto_msgTypes[22]
+	mi := &file_api_v1_user_service_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1648,7 +1932,7 @@ func (x *UserSession) String() string {
 func (*UserSession) ProtoMessage() {}
 
 func (x *UserSession) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[22]
+	mi := &file_api_v1_user_service_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1661,7 +1945,7 @@ func (x *UserSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserSession.ProtoReflect.Descriptor instead.
 func (*UserSession) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{22}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *UserSession) GetName() string {
@@ -1699,6 +1983,13 @@ func (x *UserSession) GetClientInfo() *UserSession_ClientInfo {
 	return nil
 }
 
+func (x *UserSession) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
 type ListUserSessionsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Required. The resource name of the parent.
@@ -1710,7 +2001,7 @@ type ListUserSessionsRequest struct {
 
 func (x *ListUserSessionsRequest) Reset() {
 	*x = ListUserSessionsRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[23]
+	mi := &file_api_v1_user_service_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1722,7 +2013,7 @@ func (x *ListUserSessionsRequest) String() string {
 func (*ListUserSessionsRequest) ProtoMessage() {}
 
 func (x *ListUserSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[23]
+	mi := &file_api_v1_user_service_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1735,7 +2026,7 @@ func (x *ListUserSessionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserSessionsRequest.ProtoReflect.Descriptor instead.
 func (*ListUserSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{23}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ListUserSessionsRequest) GetParent() string {
@@ -1755,7 +2046,7 @@ type ListUserSessionsResponse struct {
 
 func (x *ListUserSessionsResponse) Reset() {
 	*x = ListUserSessionsResponse{}
-	mi := &file_api_v1_user_service_proto_msgTypes[24]
+	mi := &file_api_v1_user_service_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1767,7 +2058,7 @@ func (x *ListUserSessionsResponse) String() string {
 func (*ListUserSessionsResponse) ProtoMessage() {}
 
 func (x *ListUserSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[24]
+	mi := &file_api_v1_user_service_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1780,7 +2071,7 @@ func (x *ListUserSessionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserSessionsResponse.ProtoReflect.Descriptor instead.
 func (*ListUserSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{24}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ListUserSessionsResponse) GetSessions() []*UserSession {
@@ -1801,7 +2092,7 @@ type RevokeUserSessionRequest struct {
 
 func (x *RevokeUserSessionRequest) Reset() {
 	*x = RevokeUserSessionRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[25]
+	mi := &file_api_v1_user_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1813,7 +2104,7 @@ func (x *RevokeUserSessionRequest) String() string {
 func (*RevokeUserSessionRequest) ProtoMessage() {}
 
 func (x *RevokeUserSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[25]
+	mi := &file_api_v1_user_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1826,7 +2117,7 @@ func (x *RevokeUserSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeUserSessionRequest.ProtoReflect.Descriptor instead.
 func (*RevokeUserSessionRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{25}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *RevokeUserSessionRequest) GetName() string {
@@ -1836,31 +2127,30 @@ func (x *RevokeUserSessionRequest) GetName() string {
 	return ""
 }
 
-type ListAllUserStatsRequest struct {
+type RevokeAllUserSessionsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional. The maximum number of user stats to return.
-	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	// Optional. A page token for pagination.
-	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Required. The resource name of the user whose sessions to revoke.
+	// Format: users/{user}
+	Parent        string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAllUserStatsRequest) Reset() {
-	*x = ListAllUserStatsRequest{}
-	mi := &file_api_v1_user_service_proto_msgTypes[26]
+func (x *RevokeAllUserSessionsRequest) Reset() {
+	*x = RevokeAllUserSessionsRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAllUserStatsRequest) String() string {
+func (x *RevokeAllUserSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAllUserStatsRequest) ProtoMessage() {}
+func (*RevokeAllUserSessionsRequest) ProtoMessage() {}
 
-func (x *ListAllUserStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[26]
+func (x *RevokeAllUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1871,29 +2161,680 @@ func (x *ListAllUserStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAllUserStatsRequest.ProtoReflect.Descriptor instead.
-func (*ListAllUserStatsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *ListAllUserStatsRequest) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
-	}
-	return 0
+// Deprecated: Use RevokeAllUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *ListAllUserStatsRequest) GetPageToken() string {
+func (x *RevokeAllUserSessionsRequest) GetParent() string {
 	if x != nil {
-		return x.PageToken
+		return x.Parent
 	}
 	return ""
 }
 
-type ListAllUserStatsResponse struct {
+type SetupUserTwoFactorRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The list of user statistics.
-	UserStats []*UserStats `protobuf:"bytes,1,rep,name=user_stats,json=userStats,proto3" json:"user_stats,omitempty"`
+	// Required. The resource name of the user.
+	// Format: users/{user}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetupUserTwoFactorRequest) Reset() {
+	*x = SetupUserTwoFactorRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupUserTwoFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupUserTwoFactorRequest) ProtoMessage() {}
+
+func (x *SetupUserTwoFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupUserTwoFactorRequest.ProtoReflect.Descriptor instead.
+func (*SetupUserTwoFactorRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SetupUserTwoFactorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetupUserTwoFactorResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The base32 encoded TOTP secret, for manual entry in authenticator apps.
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// The otpauth:// provisioning URI to be shown as a QR code.
+	ProvisioningUri string `protobuf:"bytes,2,opt,name=provisioning_uri,json=provisioningUri,proto3" json:"provisioning_uri,omitempty"`
+	// Single-use recovery codes that can be used instead of a TOTP code.
+	// They are only returned once.
+	RecoveryCodes []string `protobuf:"bytes,3,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetupUserTwoFactorResponse) Reset() {
+	*x = SetupUserTwoFactorResponse{}
+	mi := &file_api_v1_user_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupUserTwoFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupUserTwoFactorResponse) ProtoMessage() {}
+
+func (x *SetupUserTwoFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupUserTwoFactorResponse.ProtoReflect.Descriptor instead.
+func (*SetupUserTwoFactorResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SetupUserTwoFactorResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *SetupUserTwoFactorResponse) GetProvisioningUri() string {
+	if x != nil {
+		return x.ProvisioningUri
+	}
+	return ""
+}
+
+func (x *SetupUserTwoFactorResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type EnableUserTwoFactorRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the user.
+	// Format: users/{user}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Required. The current TOTP code from the authenticator app.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableUserTwoFactorRequest) Reset() {
+	*x = EnableUserTwoFactorRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableUserTwoFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableUserTwoFactorRequest) ProtoMessage() {}
+
+func (x *EnableUserTwoFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableUserTwoFactorRequest.ProtoReflect.Descriptor instead.
+func (*EnableUserTwoFactorRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *EnableUserTwoFactorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnableUserTwoFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DisableUserTwoFactorRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the user.
+	// Format: users/{user}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A TOTP or recovery code of the user.
+	// Not required when an admin disables two-factor authentication for another user.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableUserTwoFactorRequest) Reset() {
+	*x = DisableUserTwoFactorRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableUserTwoFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableUserTwoFactorRequest) ProtoMessage() {}
+
+func (x *DisableUserTwoFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableUserTwoFactorRequest.ProtoReflect.Descriptor instead.
+func (*DisableUserTwoFactorRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DisableUserTwoFactorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DisableUserTwoFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type UserPasskey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the passkey.
+	// Format: users/{user}/passkeys/{passkey}, where passkey is the base64url encoded credential ID.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A user-facing name for the passkey.
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// The time when the passkey was registered.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// The time when the passkey was last used to sign in.
+	LastUsedTime  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_used_time,json=lastUsedTime,proto3" json:"last_used_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserPasskey) Reset() {
+	*x = UserPasskey{}
+	mi := &file_api_v1_user_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserPasskey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserPasskey) ProtoMessage() {}
+
+func (x *UserPasskey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserPasskey.ProtoReflect.Descriptor instead.
+func (*UserPasskey) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UserPasskey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserPasskey) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UserPasskey) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *UserPasskey) GetLastUsedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedTime
+	}
+	return nil
+}
+
+type ListUserPasskeysRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the parent.
+	// Format: users/{user}
+	Parent        string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserPasskeysRequest) Reset() {
+	*x = ListUserPasskeysRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserPasskeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserPasskeysRequest) ProtoMessage() {}
+
+func (x *ListUserPasskeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserPasskeysRequest.ProtoReflect.Descriptor instead.
+func (*ListUserPasskeysRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListUserPasskeysRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+type ListUserPasskeysResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The list of passkeys.
+	Passkeys      []*UserPasskey `protobuf:"bytes,1,rep,name=passkeys,proto3" json:"passkeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserPasskeysResponse) Reset() {
+	*x = ListUserPasskeysResponse{}
+	mi := &file_api_v1_user_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserPasskeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserPasskeysResponse) ProtoMessage() {}
+
+func (x *ListUserPasskeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserPasskeysResponse.ProtoReflect.Descriptor instead.
+func (*ListUserPasskeysResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListUserPasskeysResponse) GetPasskeys() []*UserPasskey {
+	if x != nil {
+		return x.Passkeys
+	}
+	return nil
+}
+
+type BeginPasskeyRegistrationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the parent.
+	// Format: users/{user}
+	Parent        string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationRequest) Reset() {
+	*x = BeginPasskeyRegistrationRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BeginPasskeyRegistrationRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+type BeginPasskeyRegistrationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The PublicKeyCredentialCreationOptions as JSON, with binary values base64url encoded.
+	OptionsJson string `protobuf:"bytes,1,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"`
+	// An opaque token to send back with FinishPasskeyRegistration.
+	CeremonyToken string `protobuf:"bytes,2,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationResponse) Reset() {
+	*x = BeginPasskeyRegistrationResponse{}
+	mi := &file_api_v1_user_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *BeginPasskeyRegistrationResponse) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+func (x *BeginPasskeyRegistrationResponse) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the parent.
+	// Format: users/{user}
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// Required. The ceremony token returned by BeginPasskeyRegistration.
+	CeremonyToken string `protobuf:"bytes,2,opt,name=ceremony_token,json=ceremonyToken,proto3" json:"ceremony_token,omitempty"`
+	// Required. The PublicKeyCredential returned by navigator.credentials.create() as JSON.
+	CredentialJson string `protobuf:"bytes,3,opt,name=credential_json,json=credentialJson,proto3" json:"credential_json,omitempty"`
+	// Optional. A user-facing name for the passkey.
+	DisplayName   string `protobuf:"bytes,4,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationRequest) Reset() {
+	*x = FinishPasskeyRegistrationRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCeremonyToken() string {
+	if x != nil {
+		return x.CeremonyToken
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCredentialJson() string {
+	if x != nil {
+		return x.CredentialJson
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type DeleteUserPasskeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the passkey to delete.
+	// Format: users/{user}/passkeys/{passkey}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserPasskeyRequest) Reset() {
+	*x = DeleteUserPasskeyRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserPasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserPasskeyRequest) ProtoMessage() {}
+
+func (x *DeleteUserPasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserPasskeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserPasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteUserPasskeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListAllUserStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. The maximum number of user stats to return.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Optional. A page token for pagination.
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAllUserStatsRequest) Reset() {
+	*x = ListAllUserStatsRequest{}
+	mi := &file_api_v1_user_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAllUserStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllUserStatsRequest) ProtoMessage() {}
+
+func (x *ListAllUserStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_user_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllUserStatsRequest.ProtoReflect.Descriptor instead.
+func (*ListAllUserStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListAllUserStatsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAllUserStatsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAllUserStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The list of user statistics.
+	UserStats []*UserStats `protobuf:"bytes,1,rep,name=user_stats,json=userStats,proto3" json:"user_stats,omitempty"`
 	// A token for the next page of results.
 	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	// The total count of user statistics.
@@ -1904,7 +2845,7 @@ type ListAllUserStatsResponse struct {
 
 func (x *ListAllUserStatsResponse) Reset() {
 	*x = ListAllUserStatsResponse{}
-	mi := &file_api_v1_user_service_proto_msgTypes[27]
+	mi := &file_api_v1_user_service_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1916,7 +2857,7 @@ func (x *ListAllUserStatsResponse) String() string {
 func (*ListAllUserStatsResponse) ProtoMessage() {}
 
 func (x *ListAllUserStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[27]
+	mi := &file_api_v1_user_service_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1929,7 +2870,7 @@ func (x *ListAllUserStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAllUserStatsResponse.ProtoReflect.Descriptor instead.
 func (*ListAllUserStatsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{27}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ListAllUserStatsResponse) GetUserStats() []*UserStats {
@@ -1966,7 +2907,7 @@ type UserStats_MemoTypeStats struct {
 
 func (x *UserStats_MemoTypeStats) Reset() {
 	*x = UserStats_MemoTypeStats{}
-	mi := &file_api_v1_user_service_proto_msgTypes[29]
+	mi := &file_api_v1_user_service_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1978,7 +2919,7 @@ func (x *UserStats_MemoTypeStats) String() string {
 func (*UserStats_MemoTypeStats) ProtoMessage() {}
 
 func (x *UserStats_MemoTypeStats) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[29]
+	mi := &file_api_v1_user_service_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2040,7 +2981,7 @@ type UserSession_ClientInfo struct {
 
 func (x *UserSession_ClientInfo) Reset() {
 	*x = UserSession_ClientInfo{}
-	mi := &file_api_v1_user_service_proto_msgTypes[30]
+	mi := &file_api_v1_user_service_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2052,7 +2993,7 @@ func (x *UserSession_ClientInfo) String() string {
 func (*UserSession_ClientInfo) ProtoMessage() {}
 
 func (x *UserSession_ClientInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_user_service_proto_msgTypes[30]
+	mi := &file_api_v1_user_service_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2065,7 +3006,7 @@ func (x *UserSession_ClientInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserSession_ClientInfo.ProtoReflect.Descriptor instead.
 func (*UserSession_ClientInfo) Descriptor() ([]byte, []int) {
-	return file_api_v1_user_service_proto_rawDescGZIP(), []int{22, 0}
+	return file_api_v1_user_service_proto_rawDescGZIP(), []int{23, 0}
 }
 
 func (x *UserSession_ClientInfo) GetUserAgent() string {
@@ -2136,10 +3077,9 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x04USER\x10\x03::\xeaA7\n" +
 	"\x14wekalist.api.v1/User\x12\fusers/{user}\x1a\x04name*\x05users2\x04user\"*\n" +
 	"\rVerifyRequest\x12\x19\n" +
-	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05email\"B\n" +
+	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05email\"6\n" +
 	"\x0eVerifyResponse\x12\x19\n" +
-	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05email\x12\x15\n" +
-	"\x03otp\x18\x02 \x01(\tB\x03\xe0A\x02R\x03otp\"\xbd\x01\n" +
+	"\x05email\x18\x01 \x01(\tB\x03\xe0A\x02R\x05emailJ\x04\b\x02\x10\x03R\x03otp\"\xbd\x01\n" +
 	"\x10ListUsersRequest\x12 \n" +
 	"\tpage_size\x18\x01 \x01(\x05B\x03\xe0A\x01R\bpageSize\x12\"\n" +
 	"\n" +
@@ -2155,13 +3095,14 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x0eGetUserRequest\x120\n" +
 	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
 	"\x14wekalist.api.v1/UserR\x04name\x12<\n" +
-	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskB\x03\xe0A\x01R\breadMask\"\xb2\x01\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskB\x03\xe0A\x01R\breadMask\"\xc9\x01\n" +
 	"\x11CreateUserRequest\x121\n" +
 	"\x04user\x18\x01 \x01(\v2\x15.wekalist.api.v1.UserB\x06\xe0A\x02\xe0A\x04R\x04user\x12\x1c\n" +
 	"\auser_id\x18\x02 \x01(\tB\x03\xe0A\x01R\x06userId\x12(\n" +
 	"\rvalidate_only\x18\x03 \x01(\bB\x03\xe0A\x01R\fvalidateOnly\x12\"\n" +
 	"\n" +
-	"request_id\x18\x04 \x01(\tB\x03\xe0A\x01R\trequestId\"\xaf\x01\n" +
+	"request_id\x18\x04 \x01(\tB\x03\xe0A\x01R\trequestId\x12\x15\n" +
+	"\x03otp\x18\x05 \x01(\tB\x03\xe0A\x01R\x03otp\"\xaf\x01\n" +
 	"\x11UpdateUserRequest\x12.\n" +
 	"\x04user\x18\x01 \x01(\v2\x15.wekalist.api.v1.UserB\x03\xe0A\x02R\x04user\x12@\n" +
 	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskB\x03\xe0A\x02R\n" +
@@ -2206,7 +3147,7 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x19wekalist.api.v1/UserStats\x12\fusers/{user}*\tuserStats2\tuserStats\"G\n" +
 	"\x13GetUserStatsRequest\x120\n" +
 	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
-	"\x14wekalist.api.v1/UserR\x04name\"\xcb\x03\n" +
+	"\x14wekalist.api.v1/UserR\x04name\"\x91\x06\n" +
 	"\vUserSetting\x12\x17\n" +
 	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12\x1b\n" +
 	"\x06locale\x18\x02 \x01(\tB\x03\xe0A\x01R\x06locale\x12#\n" +
@@ -2218,22 +3159,51 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x14enable_notifications\x18\x06 \x01(\bB\x03\xe0A\x01R\x13enableNotifications\x12+\n" +
 	"\x0fwrapper_api_key\x18\a \x01(\tB\x03\xe0A\x01R\rwrapperApiKey\x127\n" +
 	"\x15wrapper_usage_counter\x18\b \x01(\x05B\x03\xe0A\x01R\x13wrapperUsageCounter\x12/\n" +
-	"\x11wrapper_max_usage\x18\t \x01(\x05B\x03\xe0A\x01R\x0fwrapperMaxUsage:I\xeaAF\n" +
-	"\x1bwekalist.api.v1/UserSetting\x12\fusers/{user}*\fuserSettings2\vuserSetting\"I\n" +
+	"\x11wrapper_max_usage\x18\t \x01(\x05B\x03\xe0A\x01R\x0fwrapperMaxUsage\x121\n" +
+	"\x12two_factor_enabled\x18\n" +
+	" \x01(\bB\x03\xe0A\x03R\x10twoFactorEnabled\x12g\n" +
+	"\x18notification_preferences\x18\v \x03(\v2'.wekalist.api.v1.NotificationPreferenceB\x03\xe0A\x01R\x17notificationPreferences\x12\\\n" +
+	"\x10digest_frequency\x18\f \x01(\x0e2,.wekalist.api.v1.UserSetting.DigestFrequencyB\x03\xe0A\x01R\x0fdigestFrequency\"J\n" +
+	"\x0fDigestFrequency\x12 \n" +
+	"\x1cDIGEST_FREQUENCY_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\n" +
+	"\n" +
+	"\x06WEEKLY\x10\x02:I\xeaAF\n" +
+	"\x1bwekalist.api.v1/UserSetting\x12\fusers/{user}*\fuserSettings2\vuserSetting\"\x90\x03\n" +
+	"\x16NotificationPreference\x12U\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\x0e21.wekalist.api.v1.NotificationPreference.EventTypeB\x03\xe0A\x02R\teventType\x12N\n" +
+	"\achannel\x18\x02 \x01(\x0e2/.wekalist.api.v1.NotificationPreference.ChannelB\x03\xe0A\x02R\achannel\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\"q\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCOMMENT\x10\x01\x12\v\n" +
+	"\aMENTION\x10\x02\x12\f\n" +
+	"\bREACTION\x10\x03\x12\f\n" +
+	"\bREMINDER\x10\x04\x12\x12\n" +
+	"\x0eVERSION_UPDATE\x10\x05\"B\n" +
+	"\aChannel\x12\x17\n" +
+	"\x13CHANNEL_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05INBOX\x10\x01\x12\b\n" +
+	"\x04PUSH\x10\x02\x12\t\n" +
+	"\x05EMAIL\x10\x03\"I\n" +
 	"\x15GetUserSettingRequest\x120\n" +
 	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
 	"\x14wekalist.api.v1/UserR\x04name\"\x99\x01\n" +
 	"\x18UpdateUserSettingRequest\x12;\n" +
 	"\asetting\x18\x01 \x01(\v2\x1c.wekalist.api.v1.UserSettingB\x03\xe0A\x02R\asetting\x12@\n" +
 	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskB\x03\xe0A\x02R\n" +
-	"updateMask\"\xea\x02\n" +
+	"updateMask\"\xca\x03\n" +
 	"\x0fUserAccessToken\x12\x17\n" +
 	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12&\n" +
 	"\faccess_token\x18\x02 \x01(\tB\x03\xe0A\x03R\vaccessToken\x12%\n" +
 	"\vdescription\x18\x03 \x01(\tB\x03\xe0A\x01R\vdescription\x12<\n" +
 	"\tissued_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\bissuedAt\x12>\n" +
 	"\n" +
-	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x01R\texpiresAt:q\xeaAn\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x01R\texpiresAt\x12\x1b\n" +
+	"\x06scopes\x18\x06 \x03(\tB\x03\xe0A\x01R\x06scopes\x12A\n" +
+	"\flast_used_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"lastUsedAt:q\xeaAn\n" +
 	"\x1fwekalist.api.v1/UserAccessToken\x12(users/{user}/accessTokens/{access_token}*\x10userAccessTokens2\x0fuserAccessToken\"\x99\x01\n" +
 	"\x1bListUserAccessTokensRequest\x124\n" +
 	"\x06parent\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
@@ -2253,7 +3223,7 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x0faccess_token_id\x18\x03 \x01(\tB\x03\xe0A\x01R\raccessTokenId\"[\n" +
 	"\x1cDeleteUserAccessTokenRequest\x12;\n" +
 	"\x04name\x18\x01 \x01(\tB'\xe0A\x02\xfaA!\n" +
-	"\x1fwekalist.api.v1/UserAccessTokenR\x04name\"\x9a\x04\n" +
+	"\x1fwekalist.api.v1/UserAccessTokenR\x04name\"\xdc\x04\n" +
 	"\vUserSession\x12\x17\n" +
 	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12\"\n" +
 	"\n" +
@@ -2262,7 +3232,9 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"createTime\x12M\n" +
 	"\x12last_accessed_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\x10lastAccessedTime\x12M\n" +
 	"\vclient_info\x18\x05 \x01(\v2'.wekalist.api.v1.UserSession.ClientInfoB\x03\xe0A\x03R\n" +
-	"clientInfo\x1a\xa4\x01\n" +
+	"clientInfo\x12@\n" +
+	"\vexpire_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"expireTime\x1a\xa4\x01\n" +
 	"\n" +
 	"ClientInfo\x12\x1d\n" +
 	"\n" +
@@ -2281,7 +3253,52 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\bsessions\x18\x01 \x03(\v2\x1c.wekalist.api.v1.UserSessionR\bsessions\"S\n" +
 	"\x18RevokeUserSessionRequest\x127\n" +
 	"\x04name\x18\x01 \x01(\tB#\xe0A\x02\xfaA\x1d\n" +
-	"\x1bwekalist.api.v1/UserSessionR\x04name\"_\n" +
+	"\x1bwekalist.api.v1/UserSessionR\x04name\"T\n" +
+	"\x1cRevokeAllUserSessionsRequest\x124\n" +
+	"\x06parent\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x06parent\"M\n" +
+	"\x19SetupUserTwoFactorRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x04name\"\x86\x01\n" +
+	"\x1aSetupUserTwoFactorResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12)\n" +
+	"\x10provisioning_uri\x18\x02 \x01(\tR\x0fprovisioningUri\x12%\n" +
+	"\x0erecovery_codes\x18\x03 \x03(\tR\rrecoveryCodes\"g\n" +
+	"\x1aEnableUserTwoFactorRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x04name\x12\x17\n" +
+	"\x04code\x18\x02 \x01(\tB\x03\xe0A\x02R\x04code\"h\n" +
+	"\x1bDisableUserTwoFactorRequest\x120\n" +
+	"\x04name\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x04name\x12\x17\n" +
+	"\x04code\x18\x02 \x01(\tB\x03\xe0A\x01R\x04code\"\x9b\x02\n" +
+	"\vUserPasskey\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12@\n" +
+	"\vcreate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"createTime\x12E\n" +
+	"\x0elast_used_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\flastUsedTime:G\xeaAD\n" +
+	"\x1bwekalist.api.v1/UserPasskey\x12\x1fusers/{user}/passkeys/{passkey}\x1a\x04name\"O\n" +
+	"\x17ListUserPasskeysRequest\x124\n" +
+	"\x06parent\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x06parent\"T\n" +
+	"\x18ListUserPasskeysResponse\x128\n" +
+	"\bpasskeys\x18\x01 \x03(\v2\x1c.wekalist.api.v1.UserPasskeyR\bpasskeys\"W\n" +
+	"\x1fBeginPasskeyRegistrationRequest\x124\n" +
+	"\x06parent\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x06parent\"l\n" +
+	" BeginPasskeyRegistrationResponse\x12!\n" +
+	"\foptions_json\x18\x01 \x01(\tR\voptionsJson\x12%\n" +
+	"\x0eceremony_token\x18\x02 \x01(\tR\rceremonyToken\"\xda\x01\n" +
+	" FinishPasskeyRegistrationRequest\x124\n" +
+	"\x06parent\x18\x01 \x01(\tB\x1c\xe0A\x02\xfaA\x16\n" +
+	"\x14wekalist.api.v1/UserR\x06parent\x12*\n" +
+	"\x0eceremony_token\x18\x02 \x01(\tB\x03\xe0A\x02R\rceremonyToken\x12,\n" +
+	"\x0fcredential_json\x18\x03 \x01(\tB\x03\xe0A\x02R\x0ecredentialJson\x12&\n" +
+	"\fdisplay_name\x18\x04 \x01(\tB\x03\xe0A\x01R\vdisplayName\"S\n" +
+	"\x18DeleteUserPasskeyRequest\x127\n" +
+	"\x04name\x18\x01 \x01(\tB#\xe0A\x02\xfaA\x1d\n" +
+	"\x1bwekalist.api.v1/UserPasskeyR\x04name\"_\n" +
 	"\x17ListAllUserStatsRequest\x12 \n" +
 	"\tpage_size\x18\x01 \x01(\x05B\x03\xe0A\x01R\bpageSize\x12\"\n" +
 	"\n" +
@@ -2291,7 +3308,7 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"user_stats\x18\x01 \x03(\v2\x1a.wekalist.api.v1.UserStatsR\tuserStats\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
 	"\n" +
-	"total_size\x18\x03 \x01(\x05R\ttotalSize2\xa7\x12\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize2\xda\x1c\n" +
 	"\vUserService\x12l\n" +
 	"\n" +
 	"VerifyUser\x12\x1e.wekalist.api.v1.VerifyRequest\x1a\x1f.wekalist.api.v1.VerifyResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x05email\"\x0e/api/v1/verify\x12i\n" +
@@ -2313,7 +3330,15 @@ const file_api_v1_user_service_proto_rawDesc = "" +
 	"\x15CreateUserAccessToken\x12-.wekalist.api.v1.CreateUserAccessTokenRequest\x1a .wekalist.api.v1.UserAccessToken\"Q\xdaA\x13parent,access_token\x82\xd3\xe4\x93\x025:\faccess_token\"%/api/v1/{parent=users/*}/accessTokens\x12\x94\x01\n" +
 	"\x15DeleteUserAccessToken\x12-.wekalist.api.v1.DeleteUserAccessTokenRequest\x1a\x16.google.protobuf.Empty\"4\xdaA\x04name\x82\xd3\xe4\x93\x02'*%/api/v1/{name=users/*/accessTokens/*}\x12\x9b\x01\n" +
 	"\x10ListUserSessions\x12(.wekalist.api.v1.ListUserSessionsRequest\x1a).wekalist.api.v1.ListUserSessionsResponse\"2\xdaA\x06parent\x82\xd3\xe4\x93\x02#\x12!/api/v1/{parent=users/*}/sessions\x12\x88\x01\n" +
-	"\x11RevokeUserSession\x12).wekalist.api.v1.RevokeUserSessionRequest\x1a\x16.google.protobuf.Empty\"0\xdaA\x04name\x82\xd3\xe4\x93\x02#*!/api/v1/{name=users/*/sessions/*}B\xb8\x01\n" +
+	"\x11RevokeUserSession\x12).wekalist.api.v1.RevokeUserSessionRequest\x1a\x16.google.protobuf.Empty\"0\xdaA\x04name\x82\xd3\xe4\x93\x02#*!/api/v1/{name=users/*/sessions/*}\x12\x9f\x01\n" +
+	"\x15RevokeAllUserSessions\x12-.wekalist.api.v1.RevokeAllUserSessionsRequest\x1a\x16.google.protobuf.Empty\"?\xdaA\x06parent\x82\xd3\xe4\x93\x020:\x01*\"+/api/v1/{parent=users/*}/sessions:revokeAll\x12\xa6\x01\n" +
+	"\x12SetupUserTwoFactor\x12*.wekalist.api.v1.SetupUserTwoFactorRequest\x1a+.wekalist.api.v1.SetupUserTwoFactorResponse\"7\xdaA\x04name\x82\xd3\xe4\x93\x02*:\x01*\"%/api/v1/{name=users/*}:setupTwoFactor\x12\x99\x01\n" +
+	"\x13EnableUserTwoFactor\x12+.wekalist.api.v1.EnableUserTwoFactorRequest\x1a\x16.google.protobuf.Empty\"=\xdaA\tname,code\x82\xd3\xe4\x93\x02+:\x01*\"&/api/v1/{name=users/*}:enableTwoFactor\x12\x9c\x01\n" +
+	"\x14DisableUserTwoFactor\x12,.wekalist.api.v1.DisableUserTwoFactorRequest\x1a\x16.google.protobuf.Empty\">\xdaA\tname,code\x82\xd3\xe4\x93\x02,:\x01*\"'/api/v1/{name=users/*}:disableTwoFactor\x12\x9b\x01\n" +
+	"\x10ListUserPasskeys\x12(.wekalist.api.v1.ListUserPasskeysRequest\x1a).wekalist.api.v1.ListUserPasskeysResponse\"2\xdaA\x06parent\x82\xd3\xe4\x93\x02#\x12!/api/v1/{parent=users/*}/passkeys\x12\xc8\x01\n" +
+	"\x18BeginPasskeyRegistration\x120.wekalist.api.v1.BeginPasskeyRegistrationRequest\x1a1.wekalist.api.v1.BeginPasskeyRegistrationResponse\"G\xdaA\x06parent\x82\xd3\xe4\x93\x028:\x01*\"3/api/v1/{parent=users/*}/passkeys:beginRegistration\x12\xb6\x01\n" +
+	"\x19FinishPasskeyRegistration\x121.wekalist.api.v1.FinishPasskeyRegistrationRequest\x1a\x1c.wekalist.api.v1.UserPasskey\"H\xdaA\x06parent\x82\xd3\xe4\x93\x029:\x01*\"4/api/v1/{parent=users/*}/passkeys:finishRegistration\x12\x88\x01\n" +
+	"\x11DeleteUserPasskey\x12).wekalist.api.v1.DeleteUserPasskeyRequest\x1a\x16.google.protobuf.Empty\"0\xdaA\x04name\x82\xd3\xe4\x93\x02#*!/api/v1/{name=users/*/passkeys/*}B\xb8\x01\n" +
 	"\x13com.wekalist.api.v1B\x10UserServiceProtoP\x01Z1github.com/imrany/wekalist/proto/gen/api/v1;apiv1\xa2\x02\x03WAX\xaa\x02\x0fWekalist.Api.V1\xca\x02\x0fWekalist\\Api\\V1\xe2\x02\x1bWekalist\\Api\\V1\\GPBMetadata\xea\x02\x11Wekalist::Api::V1b\x06proto3"
 
 var (
@@ -2328,111 +3353,152 @@ func file_api_v1_user_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_user_service_proto_rawDescData
 }
 
-var file_api_v1_user_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_v1_user_service_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_api_v1_user_service_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_api_v1_user_service_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_api_v1_user_service_proto_goTypes = []any{
-	(User_Role)(0),                       // 0: wekalist.api.v1.User.Role
-	(*User)(nil),                         // 1: wekalist.api.v1.User
-	(*VerifyRequest)(nil),                // 2: wekalist.api.v1.VerifyRequest
-	(*VerifyResponse)(nil),               // 3: wekalist.api.v1.VerifyResponse
-	(*ListUsersRequest)(nil),             // 4: wekalist.api.v1.ListUsersRequest
-	(*ListUsersResponse)(nil),            // 5: wekalist.api.v1.ListUsersResponse
-	(*GetUserRequest)(nil),               // 6: wekalist.api.v1.GetUserRequest
-	(*CreateUserRequest)(nil),            // 7: wekalist.api.v1.CreateUserRequest
-	(*UpdateUserRequest)(nil),            // 8: wekalist.api.v1.UpdateUserRequest
-	(*DeleteUserRequest)(nil),            // 9: wekalist.api.v1.DeleteUserRequest
-	(*SearchUsersRequest)(nil),           // 10: wekalist.api.v1.SearchUsersRequest
-	(*SearchUsersResponse)(nil),          // 11: wekalist.api.v1.SearchUsersResponse
-	(*GetUserAvatarRequest)(nil),         // 12: wekalist.api.v1.GetUserAvatarRequest
-	(*UserStats)(nil),                    // 13: wekalist.api.v1.UserStats
-	(*GetUserStatsRequest)(nil),          // 14: wekalist.api.v1.GetUserStatsRequest
-	(*UserSetting)(nil),                  // 15: wekalist.api.v1.UserSetting
-	(*GetUserSettingRequest)(nil),        // 16: wekalist.api.v1.GetUserSettingRequest
-	(*UpdateUserSettingRequest)(nil),     // 17: wekalist.api.v1.UpdateUserSettingRequest
-	(*UserAccessToken)(nil),              // 18: wekalist.api.v1.UserAccessToken
-	(*ListUserAccessTokensRequest)(nil),  // 19: wekalist.api.v1.ListUserAccessTokensRequest
-	(*ListUserAccessTokensResponse)(nil), // 20: wekalist.api.v1.ListUserAccessTokensResponse
-	(*CreateUserAccessTokenRequest)(nil), // 21: wekalist.api.v1.CreateUserAccessTokenRequest
-	(*DeleteUserAccessTokenRequest)(nil), // 22: wekalist.api.v1.DeleteUserAccessTokenRequest
-	(*UserSession)(nil),                  // 23: wekalist.api.v1.UserSession
-	(*ListUserSessionsRequest)(nil),      // 24: wekalist.api.v1.ListUserSessionsRequest
-	(*ListUserSessionsResponse)(nil),     // 25: wekalist.api.v1.ListUserSessionsResponse
-	(*RevokeUserSessionRequest)(nil),     // 26: wekalist.api.v1.RevokeUserSessionRequest
-	(*ListAllUserStatsRequest)(nil),      // 27: wekalist.api.v1.ListAllUserStatsRequest
-	(*ListAllUserStatsResponse)(nil),     // 28: wekalist.api.v1.ListAllUserStatsResponse
-	nil,                                  // 29: wekalist.api.v1.UserStats.TagCountEntry
-	(*UserStats_MemoTypeStats)(nil),      // 30: wekalist.api.v1.UserStats.MemoTypeStats
-	(*UserSession_ClientInfo)(nil),       // 31: wekalist.api.v1.UserSession.ClientInfo
-	(State)(0),                           // 32: wekalist.api.v1.State
-	(*timestamppb.Timestamp)(nil),        // 33: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil),        // 34: google.protobuf.FieldMask
-	(*emptypb.Empty)(nil),                // 35: google.protobuf.Empty
-	(*httpbody.HttpBody)(nil),            // 36: google.api.HttpBody
+	(User_Role)(0),                           // 0: wekalist.api.v1.User.Role
+	(UserSetting_DigestFrequency)(0),         // 1: wekalist.api.v1.UserSetting.DigestFrequency
+	(NotificationPreference_EventType)(0),    // 2: wekalist.api.v1.NotificationPreference.EventType
+	(NotificationPreference_Channel)(0),      // 3: wekalist.api.v1.NotificationPreference.Channel
+	(*User)(nil),                             // 4: wekalist.api.v1.User
+	(*VerifyRequest)(nil),                    // 5: wekalist.api.v1.VerifyRequest
+	(*VerifyResponse)(nil),                   // 6: wekalist.api.v1.VerifyResponse
+	(*ListUsersRequest)(nil),                 // 7: wekalist.api.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),                // 8: wekalist.api.v1.ListUsersResponse
+	(*GetUserRequest)(nil),                   // 9: wekalist.api.v1.GetUserRequest
+	(*CreateUserRequest)(nil),                // 10: wekalist.api.v1.CreateUserRequest
+	(*UpdateUserRequest)(nil),                // 11: wekalist.api.v1.UpdateUserRequest
+	(*DeleteUserRequest)(nil),                // 12: wekalist.api.v1.DeleteUserRequest
+	(*SearchUsersRequest)(nil),               // 13: wekalist.api.v1.SearchUsersRequest
+	(*SearchUsersResponse)(nil),              // 14: wekalist.api.v1.SearchUsersResponse
+	(*GetUserAvatarRequest)(nil),             // 15: wekalist.api.v1.GetUserAvatarRequest
+	(*UserStats)(nil),                        // 16: wekalist.api.v1.UserStats
+	(*GetUserStatsRequest)(nil),              // 17: wekalist.api.v1.GetUserStatsRequest
+	(*UserSetting)(nil),                      // 18: wekalist.api.v1.UserSetting
+	(*NotificationPreference)(nil),           // 19: wekalist.api.v1.NotificationPreference
+	(*GetUserSettingRequest)(nil),            // 20: wekalist.api.v1.GetUserSettingRequest
+	(*UpdateUserSettingRequest)(nil),         // 21: wekalist.api.v1.UpdateUserSettingRequest
+	(*UserAccessToken)(nil),                  // 22: wekalist.api.v1.UserAccessToken
+	(*ListUserAccessTokensRequest)(nil),      // 23: wekalist.api.v1.ListUserAccessTokensRequest
+	(*ListUserAccessTokensResponse)(nil),     // 24: wekalist.api.v1.ListUserAccessTokensResponse
+	(*CreateUserAccessTokenRequest)(nil),     // 25: wekalist.api.v1.CreateUserAccessTokenRequest
+	(*DeleteUserAccessTokenRequest)(nil),     // 26: wekalist.api.v1.DeleteUserAccessTokenRequest
+	(*UserSession)(nil),                      // 27: wekalist.api.v1.UserSession
+	(*ListUserSessionsRequest)(nil),          // 28: wekalist.api.v1.ListUserSessionsRequest
+	(*ListUserSessionsResponse)(nil),         // 29: wekalist.api.v1.ListUserSessionsResponse
+	(*RevokeUserSessionRequest)(nil),         // 30: wekalist.api.v1.RevokeUserSessionRequest
+	(*RevokeAllUserSessionsRequest)(nil),     // 31: wekalist.api.v1.RevokeAllUserSessionsRequest
+	(*SetupUserTwoFactorRequest)(nil),        // 32: wekalist.api.v1.SetupUserTwoFactorRequest
+	(*SetupUserTwoFactorResponse)(nil),       // 33: wekalist.api.v1.SetupUserTwoFactorResponse
+	(*EnableUserTwoFactorRequest)(nil),       // 34: wekalist.api.v1.EnableUserTwoFactorRequest
+	(*DisableUserTwoFactorRequest)(nil),      // 35: wekalist.api.v1.DisableUserTwoFactorRequest
+	(*UserPasskey)(nil),                      // 36: wekalist.api.v1.UserPasskey
+	(*ListUserPasskeysRequest)(nil),          // 37: wekalist.api.v1.ListUserPasskeysRequest
+	(*ListUserPasskeysResponse)(nil),         // 38: wekalist.api.v1.ListUserPasskeysResponse
+	(*BeginPasskeyRegistrationRequest)(nil),  // 39: wekalist.api.v1.BeginPasskeyRegistrationRequest
+	(*BeginPasskeyRegistrationResponse)(nil), // 40: wekalist.api.v1.BeginPasskeyRegistrationResponse
+	(*FinishPasskeyRegistrationRequest)(nil), // 41: wekalist.api.v1.FinishPasskeyRegistrationRequest
+	(*DeleteUserPasskeyRequest)(nil),         // 42: wekalist.api.v1.DeleteUserPasskeyRequest
+	(*ListAllUserStatsRequest)(nil),          // 43: wekalist.api.v1.ListAllUserStatsRequest
+	(*ListAllUserStatsResponse)(nil),         // 44: wekalist.api.v1.ListAllUserStatsResponse
+	nil,                                      // 45: wekalist.api.v1.UserStats.TagCountEntry
+	(*UserStats_MemoTypeStats)(nil),          // 46: wekalist.api.v1.UserStats.MemoTypeStats
+	(*UserSession_ClientInfo)(nil),           // 47: wekalist.api.v1.UserSession.ClientInfo
+	(State)(0),                               // 48: wekalist.api.v1.State
+	(*timestamppb.Timestamp)(nil),            // 49: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),            // 50: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                    // 51: google.protobuf.Empty
+	(*httpbody.HttpBody)(nil),                // 52: google.api.HttpBody
 }
 var file_api_v1_user_service_proto_depIdxs = []int32{
 	0,  // 0: wekalist.api.v1.User.role:type_name -> wekalist.api.v1.User.Role
-	32, // 1: wekalist.api.v1.User.state:type_name -> wekalist.api.v1.State
-	33, // 2: wekalist.api.v1.User.create_time:type_name -> google.protobuf.Timestamp
-	33, // 3: wekalist.api.v1.User.update_time:type_name -> google.protobuf.Timestamp
-	1,  // 4: wekalist.api.v1.ListUsersResponse.users:type_name -> wekalist.api.v1.User
-	34, // 5: wekalist.api.v1.GetUserRequest.read_mask:type_name -> google.protobuf.FieldMask
-	1,  // 6: wekalist.api.v1.CreateUserRequest.user:type_name -> wekalist.api.v1.User
-	1,  // 7: wekalist.api.v1.UpdateUserRequest.user:type_name -> wekalist.api.v1.User
-	34, // 8: wekalist.api.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
-	1,  // 9: wekalist.api.v1.SearchUsersResponse.users:type_name -> wekalist.api.v1.User
-	33, // 10: wekalist.api.v1.UserStats.memo_display_timestamps:type_name -> google.protobuf.Timestamp
-	30, // 11: wekalist.api.v1.UserStats.memo_type_stats:type_name -> wekalist.api.v1.UserStats.MemoTypeStats
-	29, // 12: wekalist.api.v1.UserStats.tag_count:type_name -> wekalist.api.v1.UserStats.TagCountEntry
-	15, // 13: wekalist.api.v1.UpdateUserSettingRequest.setting:type_name -> wekalist.api.v1.UserSetting
-	34, // 14: wekalist.api.v1.UpdateUserSettingRequest.update_mask:type_name -> google.protobuf.FieldMask
-	33, // 15: wekalist.api.v1.UserAccessToken.issued_at:type_name -> google.protobuf.Timestamp
-	33, // 16: wekalist.api.v1.UserAccessToken.expires_at:type_name -> google.protobuf.Timestamp
-	18, // 17: wekalist.api.v1.ListUserAccessTokensResponse.access_tokens:type_name -> wekalist.api.v1.UserAccessToken
-	18, // 18: wekalist.api.v1.CreateUserAccessTokenRequest.access_token:type_name -> wekalist.api.v1.UserAccessToken
-	33, // 19: wekalist.api.v1.UserSession.create_time:type_name -> google.protobuf.Timestamp
-	33, // 20: wekalist.api.v1.UserSession.last_accessed_time:type_name -> google.protobuf.Timestamp
-	31, // 21: wekalist.api.v1.UserSession.client_info:type_name -> wekalist.api.v1.UserSession.ClientInfo
-	23, // 22: wekalist.api.v1.ListUserSessionsResponse.sessions:type_name -> wekalist.api.v1.UserSession
-	13, // 23: wekalist.api.v1.ListAllUserStatsResponse.user_stats:type_name -> wekalist.api.v1.UserStats
-	2,  // 24: wekalist.api.v1.UserService.VerifyUser:input_type -> wekalist.api.v1.VerifyRequest
-	4,  // 25: wekalist.api.v1.UserService.ListUsers:input_type -> wekalist.api.v1.ListUsersRequest
-	6,  // 26: wekalist.api.v1.UserService.GetUser:input_type -> wekalist.api.v1.GetUserRequest
-	7,  // 27: wekalist.api.v1.UserService.CreateUser:input_type -> wekalist.api.v1.CreateUserRequest
-	8,  // 28: wekalist.api.v1.UserService.UpdateUser:input_type -> wekalist.api.v1.UpdateUserRequest
-	9,  // 29: wekalist.api.v1.UserService.DeleteUser:input_type -> wekalist.api.v1.DeleteUserRequest
-	10, // 30: wekalist.api.v1.UserService.SearchUsers:input_type -> wekalist.api.v1.SearchUsersRequest
-	12, // 31: wekalist.api.v1.UserService.GetUserAvatar:input_type -> wekalist.api.v1.GetUserAvatarRequest
-	27, // 32: wekalist.api.v1.UserService.ListAllUserStats:input_type -> wekalist.api.v1.ListAllUserStatsRequest
-	14, // 33: wekalist.api.v1.UserService.GetUserStats:input_type -> wekalist.api.v1.GetUserStatsRequest
-	16, // 34: wekalist.api.v1.UserService.GetUserSetting:input_type -> wekalist.api.v1.GetUserSettingRequest
-	17, // 35: wekalist.api.v1.UserService.UpdateUserSetting:input_type -> wekalist.api.v1.UpdateUserSettingRequest
-	19, // 36: wekalist.api.v1.UserService.ListUserAccessTokens:input_type -> wekalist.api.v1.ListUserAccessTokensRequest
-	21, // 37: wekalist.api.v1.UserService.CreateUserAccessToken:input_type -> wekalist.api.v1.CreateUserAccessTokenRequest
-	22, // 38: wekalist.api.v1.UserService.DeleteUserAccessToken:input_type -> wekalist.api.v1.DeleteUserAccessTokenRequest
-	24, // 39: wekalist.api.v1.UserService.ListUserSessions:input_type -> wekalist.api.v1.ListUserSessionsRequest
-	26, // 40: wekalist.api.v1.UserService.RevokeUserSession:input_type -> wekalist.api.v1.RevokeUserSessionRequest
-	3,  // 41: wekalist.api.v1.UserService.VerifyUser:output_type -> wekalist.api.v1.VerifyResponse
-	5,  // 42: wekalist.api.v1.UserService.ListUsers:output_type -> wekalist.api.v1.ListUsersResponse
-	1,  // 43: wekalist.api.v1.UserService.GetUser:output_type -> wekalist.api.v1.User
-	1,  // 44: wekalist.api.v1.UserService.CreateUser:output_type -> wekalist.api.v1.User
-	1,  // 45: wekalist.api.v1.UserService.UpdateUser:output_type -> wekalist.api.v1.User
-	35, // 46: wekalist.api.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
-	11, // 47: wekalist.api.v1.UserService.SearchUsers:output_type -> wekalist.api.v1.SearchUsersResponse
-	36, // 48: wekalist.api.v1.UserService.GetUserAvatar:output_type -> google.api.HttpBody
-	28, // 49: wekalist.api.v1.UserService.ListAllUserStats:output_type -> wekalist.api.v1.ListAllUserStatsResponse
-	13, // 50: wekalist.api.v1.UserService.GetUserStats:output_type -> wekalist.api.v1.UserStats
-	15, // 51: wekalist.api.v1.UserService.GetUserSetting:output_type -> wekalist.api.v1.UserSetting
-	15, // 52: wekalist.api.v1.UserService.UpdateUserSetting:output_type -> wekalist.api.v1.UserSetting
-	20, // 53: wekalist.api.v1.UserService.ListUserAccessTokens:output_type -> wekalist.api.v1.ListUserAccessTokensResponse
-	18, // 54: wekalist.api.v1.UserService.CreateUserAccessToken:output_type -> wekalist.api.v1.UserAccessToken
-	35, // 55: wekalist.api.v1.UserService.DeleteUserAccessToken:output_type -> google.protobuf.Empty
-	25, // 56: wekalist.api.v1.UserService.ListUserSessions:output_type -> wekalist.api.v1.ListUserSessionsResponse
-	35, // 57: wekalist.api.v1.UserService.RevokeUserSession:output_type -> google.protobuf.Empty
-	41, // [41:58] is the sub-list for method output_type
-	24, // [24:41] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	48, // 1: wekalist.api.v1.User.state:type_name -> wekalist.api.v1.State
+	49, // 2: wekalist.api.v1.User.create_time:type_name -> google.protobuf.Timestamp
+	49, // 3: wekalist.api.v1.User.update_time:type_name -> google.protobuf.Timestamp
+	4,  // 4: wekalist.api.v1.ListUsersResponse.users:type_name -> wekalist.api.v1.User
+	50, // 5: wekalist.api.v1.GetUserRequest.read_mask:type_name -> google.protobuf.FieldMask
+	4,  // 6: wekalist.api.v1.CreateUserRequest.user:type_name -> wekalist.api.v1.User
+	4,  // 7: wekalist.api.v1.UpdateUserRequest.user:type_name -> wekalist.api.v1.User
+	50, // 8: wekalist.api.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	4,  // 9: wekalist.api.v1.SearchUsersResponse.users:type_name -> wekalist.api.v1.User
+	49, // 10: wekalist.api.v1.UserStats.memo_display_timestamps:type_name -> google.protobuf.Timestamp
+	46, // 11: wekalist.api.v1.UserStats.memo_type_stats:type_name -> wekalist.api.v1.UserStats.MemoTypeStats
+	45, // 12: wekalist.api.v1.UserStats.tag_count:type_name -> wekalist.api.v1.UserStats.TagCountEntry
+	19, // 13: wekalist.api.v1.UserSetting.notification_preferences:type_name -> wekalist.api.v1.NotificationPreference
+	1,  // 14: wekalist.api.v1.UserSetting.digest_frequency:type_name -> wekalist.api.v1.UserSetting.DigestFrequency
+	2,  // 15: wekalist.api.v1.NotificationPreference.event_type:type_name -> wekalist.api.v1.NotificationPreference.EventType
+	3,  // 16: wekalist.api.v1.NotificationPreference.channel:type_name -> wekalist.api.v1.NotificationPreference.Channel
+	18, // 17: wekalist.api.v1.UpdateUserSettingRequest.setting:type_name -> wekalist.api.v1.UserSetting
+	50, // 18: wekalist.api.v1.UpdateUserSettingRequest.update_mask:type_name -> google.protobuf.FieldMask
+	49, // 19: wekalist.api.v1.UserAccessToken.issued_at:type_name -> google.protobuf.Timestamp
+	49, // 20: wekalist.api.v1.UserAccessToken.expires_at:type_name -> google.protobuf.Timestamp
+	49, // 21: wekalist.api.v1.UserAccessToken.last_used_at:type_name -> google.protobuf.Timestamp
+	22, // 22: wekalist.api.v1.ListUserAccessTokensResponse.access_tokens:type_name -> wekalist.api.v1.UserAccessToken
+	22, // 23: wekalist.api.v1.CreateUserAccessTokenRequest.access_token:type_name -> wekalist.api.v1.UserAccessToken
+	49, // 24: wekalist.api.v1.UserSession.create_time:type_name -> google.protobuf.Timestamp
+	49, // 25: wekalist.api.v1.UserSession.last_accessed_time:type_name -> google.protobuf.Timestamp
+	47, // 26: wekalist.api.v1.UserSession.client_info:type_name -> wekalist.api.v1.UserSession.ClientInfo
+	49, // 27: wekalist.api.v1.UserSession.expire_time:type_name -> google.protobuf.Timestamp
+	27, // 28: wekalist.api.v1.ListUserSessionsResponse.sessions:type_name -> wekalist.api.v1.UserSession
+	49, // 29: wekalist.api.v1.UserPasskey.create_time:type_name -> google.protobuf.Timestamp
+	49, // 30: wekalist.api.v1.UserPasskey.last_used_time:type_name -> google.protobuf.Timestamp
+	36, // 31: wekalist.api.v1.ListUserPasskeysResponse.passkeys:type_name -> wekalist.api.v1.UserPasskey
+	16, // 32: wekalist.api.v1.ListAllUserStatsResponse.user_stats:type_name -> wekalist.api.v1.UserStats
+	5,  // 33: wekalist.api.v1.UserService.VerifyUser:input_type -> wekalist.api.v1.VerifyRequest
+	7,  // 34: wekalist.api.v1.UserService.ListUsers:input_type -> wekalist.api.v1.ListUsersRequest
+	9,  // 35: wekalist.api.v1.UserService.GetUser:input_type -> wekalist.api.v1.GetUserRequest
+	10, // 36: wekalist.api.v1.UserService.CreateUser:input_type -> wekalist.api.v1.CreateUserRequest
+	11, // 37: wekalist.api.v1.UserService.UpdateUser:input_type -> wekalist.api.v1.UpdateUserRequest
+	12, // 38: wekalist.api.v1.UserService.DeleteUser:input_type -> wekalist.api.v1.DeleteUserRequest
+	13, // 39: wekalist.api.v1.UserService.SearchUsers:input_type -> wekalist.api.v1.SearchUsersRequest
+	15, // 40: wekalist.api.v1.UserService.GetUserAvatar:input_type -> wekalist.api.v1.GetUserAvatarRequest
+	43, // 41: wekalist.api.v1.UserService.ListAllUserStats:input_type -> wekalist.api.v1.ListAllUserStatsRequest
+	17, // 42: wekalist.api.v1.UserService.GetUserStats:input_type -> wekalist.api.v1.GetUserStatsRequest
+	20, // 43: wekalist.api.v1.UserService.GetUserSetting:input_type -> wekalist.api.v1.GetUserSettingRequest
+	21, // 44: wekalist.api.v1.UserService.UpdateUserSetting:input_type -> wekalist.api.v1.UpdateUserSettingRequest
+	23, // 45: wekalist.api.v1.UserService.ListUserAccessTokens:input_type -> wekalist.api.v1.ListUserAccessTokensRequest
+	25, // 46: wekalist.api.v1.UserService.CreateUserAccessToken:input_type -> wekalist.api.v1.CreateUserAccessTokenRequest
+	26, // 47: wekalist.api.v1.UserService.DeleteUserAccessToken:input_type -> wekalist.api.v1.DeleteUserAccessTokenRequest
+	28, // 48: wekalist.api.v1.UserService.ListUserSessions:input_type -> wekalist.api.v1.ListUserSessionsRequest
+	30, // 49: wekalist.api.v1.UserService.RevokeUserSession:input_type -> wekalist.api.v1.RevokeUserSessionRequest
+	31, // 50: wekalist.api.v1.UserService.RevokeAllUserSessions:input_type -> wekalist.api.v1.RevokeAllUserSessionsRequest
+	32, // 51: wekalist.api.v1.UserService.SetupUserTwoFactor:input_type -> wekalist.api.v1.SetupUserTwoFactorRequest
+	34, // 52: wekalist.api.v1.UserService.EnableUserTwoFactor:input_type -> wekalist.api.v1.EnableUserTwoFactorRequest
+	35, // 53: wekalist.api.v1.UserService.DisableUserTwoFactor:input_type -> wekalist.api.v1.DisableUserTwoFactorRequest
+	37, // 54: wekalist.api.v1.UserService.ListUserPasskeys:input_type -> wekalist.api.v1.ListUserPasskeysRequest
+	39, // 55: wekalist.api.v1.UserService.BeginPasskeyRegistration:input_type -> wekalist.api.v1.BeginPasskeyRegistrationRequest
+	41, // 56: wekalist.api.v1.UserService.FinishPasskeyRegistration:input_type -> wekalist.api.v1.FinishPasskeyRegistrationRequest
+	42, // 57: wekalist.api.v1.UserService.DeleteUserPasskey:input_type -> wekalist.api.v1.DeleteUserPasskeyRequest
+	6,  // 58: wekalist.api.v1.UserService.VerifyUser:output_type -> wekalist.api.v1.VerifyResponse
+	8,  // 59: wekalist.api.v1.UserService.ListUsers:output_type -> wekalist.api.v1.ListUsersResponse
+	4,  // 60: wekalist.api.v1.UserService.GetUser:output_type -> wekalist.api.v1.User
+	4,  // 61: wekalist.api.v1.UserService.CreateUser:output_type -> wekalist.api.v1.User
+	4,  // 62: wekalist.api.v1.UserService.UpdateUser:output_type -> wekalist.api.v1.User
+	51, // 63: wekalist.api.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	14, // 64: wekalist.api.v1.UserService.SearchUsers:output_type -> wekalist.api.v1.SearchUsersResponse
+	52, // 65: wekalist.api.v1.UserService.GetUserAvatar:output_type -> google.api.HttpBody
+	44, // 66: wekalist.api.v1.UserService.ListAllUserStats:output_type -> wekalist.api.v1.ListAllUserStatsResponse
+	16, // 67: wekalist.api.v1.UserService.GetUserStats:output_type -> wekalist.api.v1.UserStats
+	18, // 68: wekalist.api.v1.UserService.GetUserSetting:output_type -> wekalist.api.v1.UserSetting
+	18, // 69: wekalist.api.v1.UserService.UpdateUserSetting:output_type -> wekalist.api.v1.UserSetting
+	24, // 70: wekalist.api.v1.UserService.ListUserAccessTokens:output_type -> wekalist.api.v1.ListUserAccessTokensResponse
+	22, // 71: wekalist.api.v1.UserService.CreateUserAccessToken:output_type -> wekalist.api.v1.UserAccessToken
+	51, // 72: wekalist.api.v1.UserService.DeleteUserAccessToken:output_type -> google.protobuf.Empty
+	29, // 73: wekalist.api.v1.UserService.ListUserSessions:output_type -> wekalist.api.v1.ListUserSessionsResponse
+	51, // 74: wekalist.api.v1.UserService.RevokeUserSession:output_type -> google.protobuf.Empty
+	51, // 75: wekalist.api.v1.UserService.RevokeAllUserSessions:output_type -> google.protobuf.Empty
+	33, // 76: wekalist.api.v1.UserService.SetupUserTwoFactor:output_type -> wekalist.api.v1.SetupUserTwoFactorResponse
+	51, // 77: wekalist.api.v1.UserService.EnableUserTwoFactor:output_type -> google.protobuf.Empty
+	51, // 78: wekalist.api.v1.UserService.DisableUserTwoFactor:output_type -> google.protobuf.Empty
+	38, // 79: wekalist.api.v1.UserService.ListUserPasskeys:output_type -> wekalist.api.v1.ListUserPasskeysResponse
+	40, // 80: wekalist.api.v1.UserService.BeginPasskeyRegistration:output_type -> wekalist.api.v1.BeginPasskeyRegistrationResponse
+	36, // 81: wekalist.api.v1.UserService.FinishPasskeyRegistration:output_type -> wekalist.api.v1.UserPasskey
+	51, // 82: wekalist.api.v1.UserService.DeleteUserPasskey:output_type -> google.protobuf.Empty
+	58, // [58:83] is the sub-list for method output_type
+	33, // [33:58] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_user_service_proto_init() }
@@ -2446,8 +3512,8 @@ func file_api_v1_user_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_user_service_proto_rawDesc), len(file_api_v1_user_service_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   31,
+			NumEnums:      4,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
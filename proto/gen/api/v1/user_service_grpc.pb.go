@@ -21,23 +21,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_VerifyUser_FullMethodName            = "/wekalist.api.v1.UserService/VerifyUser"
-	UserService_ListUsers_FullMethodName             = "/wekalist.api.v1.UserService/ListUsers"
-	UserService_GetUser_FullMethodName               = "/wekalist.api.v1.UserService/GetUser"
-	UserService_CreateUser_FullMethodName            = "/wekalist.api.v1.UserService/CreateUser"
-	UserService_UpdateUser_FullMethodName            = "/wekalist.api.v1.UserService/UpdateUser"
-	UserService_DeleteUser_FullMethodName            = "/wekalist.api.v1.UserService/DeleteUser"
-	UserService_SearchUsers_FullMethodName           = "/wekalist.api.v1.UserService/SearchUsers"
-	UserService_GetUserAvatar_FullMethodName         = "/wekalist.api.v1.UserService/GetUserAvatar"
-	UserService_ListAllUserStats_FullMethodName      = "/wekalist.api.v1.UserService/ListAllUserStats"
-	UserService_GetUserStats_FullMethodName          = "/wekalist.api.v1.UserService/GetUserStats"
-	UserService_GetUserSetting_FullMethodName        = "/wekalist.api.v1.UserService/GetUserSetting"
-	UserService_UpdateUserSetting_FullMethodName     = "/wekalist.api.v1.UserService/UpdateUserSetting"
-	UserService_ListUserAccessTokens_FullMethodName  = "/wekalist.api.v1.UserService/ListUserAccessTokens"
-	UserService_CreateUserAccessToken_FullMethodName = "/wekalist.api.v1.UserService/CreateUserAccessToken"
-	UserService_DeleteUserAccessToken_FullMethodName = "/wekalist.api.v1.UserService/DeleteUserAccessToken"
-	UserService_ListUserSessions_FullMethodName      = "/wekalist.api.v1.UserService/ListUserSessions"
-	UserService_RevokeUserSession_FullMethodName     = "/wekalist.api.v1.UserService/RevokeUserSession"
+	UserService_VerifyUser_FullMethodName                = "/wekalist.api.v1.UserService/VerifyUser"
+	UserService_ListUsers_FullMethodName                 = "/wekalist.api.v1.UserService/ListUsers"
+	UserService_GetUser_FullMethodName                   = "/wekalist.api.v1.UserService/GetUser"
+	UserService_CreateUser_FullMethodName                = "/wekalist.api.v1.UserService/CreateUser"
+	UserService_UpdateUser_FullMethodName                = "/wekalist.api.v1.UserService/UpdateUser"
+	UserService_DeleteUser_FullMethodName                = "/wekalist.api.v1.UserService/DeleteUser"
+	UserService_SearchUsers_FullMethodName               = "/wekalist.api.v1.UserService/SearchUsers"
+	UserService_GetUserAvatar_FullMethodName             = "/wekalist.api.v1.UserService/GetUserAvatar"
+	UserService_ListAllUserStats_FullMethodName          = "/wekalist.api.v1.UserService/ListAllUserStats"
+	UserService_GetUserStats_FullMethodName              = "/wekalist.api.v1.UserService/GetUserStats"
+	UserService_GetUserSetting_FullMethodName            = "/wekalist.api.v1.UserService/GetUserSetting"
+	UserService_UpdateUserSetting_FullMethodName         = "/wekalist.api.v1.UserService/UpdateUserSetting"
+	UserService_ListUserAccessTokens_FullMethodName      = "/wekalist.api.v1.UserService/ListUserAccessTokens"
+	UserService_CreateUserAccessToken_FullMethodName     = "/wekalist.api.v1.UserService/CreateUserAccessToken"
+	UserService_DeleteUserAccessToken_FullMethodName     = "/wekalist.api.v1.UserService/DeleteUserAccessToken"
+	UserService_ListUserSessions_FullMethodName          = "/wekalist.api.v1.UserService/ListUserSessions"
+	UserService_RevokeUserSession_FullMethodName         = "/wekalist.api.v1.UserService/RevokeUserSession"
+	UserService_RevokeAllUserSessions_FullMethodName     = "/wekalist.api.v1.UserService/RevokeAllUserSessions"
+	UserService_SetupUserTwoFactor_FullMethodName        = "/wekalist.api.v1.UserService/SetupUserTwoFactor"
+	UserService_EnableUserTwoFactor_FullMethodName       = "/wekalist.api.v1.UserService/EnableUserTwoFactor"
+	UserService_DisableUserTwoFactor_FullMethodName      = "/wekalist.api.v1.UserService/DisableUserTwoFactor"
+	UserService_ListUserPasskeys_FullMethodName          = "/wekalist.api.v1.UserService/ListUserPasskeys"
+	UserService_BeginPasskeyRegistration_FullMethodName  = "/wekalist.api.v1.UserService/BeginPasskeyRegistration"
+	UserService_FinishPasskeyRegistration_FullMethodName = "/wekalist.api.v1.UserService/FinishPasskeyRegistration"
+	UserService_DeleteUserPasskey_FullMethodName         = "/wekalist.api.v1.UserService/DeleteUserPasskey"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -78,6 +86,25 @@ type UserServiceClient interface {
 	ListUserSessions(ctx context.Context, in *ListUserSessionsRequest, opts ...grpc.CallOption) (*ListUserSessionsResponse, error)
 	// RevokeUserSession revokes a specific session for a user.
 	RevokeUserSession(ctx context.Context, in *RevokeUserSessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RevokeAllUserSessions revokes all sessions of a user, signing the user out everywhere.
+	// Admins can revoke the sessions of any user.
+	RevokeAllUserSessions(ctx context.Context, in *RevokeAllUserSessionsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SetupUserTwoFactor starts TOTP enrolment for a user.
+	// Returns the provisioning URI for authenticator apps and a new set of recovery codes.
+	SetupUserTwoFactor(ctx context.Context, in *SetupUserTwoFactorRequest, opts ...grpc.CallOption) (*SetupUserTwoFactorResponse, error)
+	// EnableUserTwoFactor completes TOTP enrolment with a code from the authenticator app.
+	EnableUserTwoFactor(ctx context.Context, in *EnableUserTwoFactorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DisableUserTwoFactor turns off two-factor authentication for a user.
+	DisableUserTwoFactor(ctx context.Context, in *DisableUserTwoFactorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListUserPasskeys returns the passkeys registered by a user.
+	ListUserPasskeys(ctx context.Context, in *ListUserPasskeysRequest, opts ...grpc.CallOption) (*ListUserPasskeysResponse, error)
+	// BeginPasskeyRegistration starts registering a new passkey for a user.
+	// Returns the options to pass to navigator.credentials.create().
+	BeginPasskeyRegistration(ctx context.Context, in *BeginPasskeyRegistrationRequest, opts ...grpc.CallOption) (*BeginPasskeyRegistrationResponse, error)
+	// FinishPasskeyRegistration verifies the new credential and stores the passkey.
+	FinishPasskeyRegistration(ctx context.Context, in *FinishPasskeyRegistrationRequest, opts ...grpc.CallOption) (*UserPasskey, error)
+	// DeleteUserPasskey revokes a passkey of a user.
+	DeleteUserPasskey(ctx context.Context, in *DeleteUserPasskeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type userServiceClient struct {
@@ -258,6 +285,86 @@ func (c *userServiceClient) RevokeUserSession(ctx context.Context, in *RevokeUse
 	return out, nil
 }
 
+func (c *userServiceClient) RevokeAllUserSessions(ctx context.Context, in *RevokeAllUserSessionsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_RevokeAllUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetupUserTwoFactor(ctx context.Context, in *SetupUserTwoFactorRequest, opts ...grpc.CallOption) (*SetupUserTwoFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetupUserTwoFactorResponse)
+	err := c.cc.Invoke(ctx, UserService_SetupUserTwoFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnableUserTwoFactor(ctx context.Context, in *EnableUserTwoFactorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_EnableUserTwoFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DisableUserTwoFactor(ctx context.Context, in *DisableUserTwoFactorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DisableUserTwoFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUserPasskeys(ctx context.Context, in *ListUserPasskeysRequest, opts ...grpc.CallOption) (*ListUserPasskeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserPasskeysResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUserPasskeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) BeginPasskeyRegistration(ctx context.Context, in *BeginPasskeyRegistrationRequest, opts ...grpc.CallOption) (*BeginPasskeyRegistrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginPasskeyRegistrationResponse)
+	err := c.cc.Invoke(ctx, UserService_BeginPasskeyRegistration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) FinishPasskeyRegistration(ctx context.Context, in *FinishPasskeyRegistrationRequest, opts ...grpc.CallOption) (*UserPasskey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPasskey)
+	err := c.cc.Invoke(ctx, UserService_FinishPasskeyRegistration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUserPasskey(ctx context.Context, in *DeleteUserPasskeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteUserPasskey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -296,6 +403,25 @@ type UserServiceServer interface {
 	ListUserSessions(context.Context, *ListUserSessionsRequest) (*ListUserSessionsResponse, error)
 	// RevokeUserSession revokes a specific session for a user.
 	RevokeUserSession(context.Context, *RevokeUserSessionRequest) (*emptypb.Empty, error)
+	// RevokeAllUserSessions revokes all sessions of a user, signing the user out everywhere.
+	// Admins can revoke the sessions of any user.
+	RevokeAllUserSessions(context.Context, *RevokeAllUserSessionsRequest) (*emptypb.Empty, error)
+	// SetupUserTwoFactor starts TOTP enrolment for a user.
+	// Returns the provisioning URI for authenticator apps and a new set of recovery codes.
+	SetupUserTwoFactor(context.Context, *SetupUserTwoFactorRequest) (*SetupUserTwoFactorResponse, error)
+	// EnableUserTwoFactor completes TOTP enrolment with a code from the authenticator app.
+	EnableUserTwoFactor(context.Context, *EnableUserTwoFactorRequest) (*emptypb.Empty, error)
+	// DisableUserTwoFactor turns off two-factor authentication for a user.
+	DisableUserTwoFactor(context.Context, *DisableUserTwoFactorRequest) (*emptypb.Empty, error)
+	// ListUserPasskeys returns the passkeys registered by a user.
+	ListUserPasskeys(context.Context, *ListUserPasskeysRequest) (*ListUserPasskeysResponse, error)
+	// BeginPasskeyRegistration starts registering a new passkey for a user.
+	// Returns the options to pass to navigator.credentials.create().
+	BeginPasskeyRegistration(context.Context, *BeginPasskeyRegistrationRequest) (*BeginPasskeyRegistrationResponse, error)
+	// FinishPasskeyRegistration verifies the new credential and stores the passkey.
+	FinishPasskeyRegistration(context.Context, *FinishPasskeyRegistrationRequest) (*UserPasskey, error)
+	// DeleteUserPasskey revokes a passkey of a user.
+	DeleteUserPasskey(context.Context, *DeleteUserPasskeyRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -357,6 +483,30 @@ func (UnimplementedUserServiceServer) ListUserSessions(context.Context, *ListUse
 func (UnimplementedUserServiceServer) RevokeUserSession(context.Context, *RevokeUserSessionRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserSession not implemented")
 }
+func (UnimplementedUserServiceServer) RevokeAllUserSessions(context.Context, *RevokeAllUserSessionsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllUserSessions not implemented")
+}
+func (UnimplementedUserServiceServer) SetupUserTwoFactor(context.Context, *SetupUserTwoFactorRequest) (*SetupUserTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetupUserTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) EnableUserTwoFactor(context.Context, *EnableUserTwoFactorRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableUserTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) DisableUserTwoFactor(context.Context, *DisableUserTwoFactorRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableUserTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) ListUserPasskeys(context.Context, *ListUserPasskeysRequest) (*ListUserPasskeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserPasskeys not implemented")
+}
+func (UnimplementedUserServiceServer) BeginPasskeyRegistration(context.Context, *BeginPasskeyRegistrationRequest) (*BeginPasskeyRegistrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginPasskeyRegistration not implemented")
+}
+func (UnimplementedUserServiceServer) FinishPasskeyRegistration(context.Context, *FinishPasskeyRegistrationRequest) (*UserPasskey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishPasskeyRegistration not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUserPasskey(context.Context, *DeleteUserPasskeyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserPasskey not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -684,6 +834,150 @@ func _UserService_RevokeUserSession_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_RevokeAllUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeAllUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokeAllUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeAllUserSessions(ctx, req.(*RevokeAllUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetupUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetupUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetupUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetupUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetupUserTwoFactor(ctx, req.(*SetupUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnableUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnableUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_EnableUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnableUserTwoFactor(ctx, req.(*EnableUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DisableUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DisableUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DisableUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DisableUserTwoFactor(ctx, req.(*DisableUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUserPasskeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserPasskeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUserPasskeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUserPasskeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUserPasskeys(ctx, req.(*ListUserPasskeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_BeginPasskeyRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginPasskeyRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).BeginPasskeyRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_BeginPasskeyRegistration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).BeginPasskeyRegistration(ctx, req.(*BeginPasskeyRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_FinishPasskeyRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishPasskeyRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).FinishPasskeyRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_FinishPasskeyRegistration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).FinishPasskeyRegistration(ctx, req.(*FinishPasskeyRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUserPasskey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserPasskeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUserPasskey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUserPasskey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUserPasskey(ctx, req.(*DeleteUserPasskeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -759,6 +1053,38 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RevokeUserSession",
 			Handler:    _UserService_RevokeUserSession_Handler,
 		},
+		{
+			MethodName: "RevokeAllUserSessions",
+			Handler:    _UserService_RevokeAllUserSessions_Handler,
+		},
+		{
+			MethodName: "SetupUserTwoFactor",
+			Handler:    _UserService_SetupUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "EnableUserTwoFactor",
+			Handler:    _UserService_EnableUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "DisableUserTwoFactor",
+			Handler:    _UserService_DisableUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "ListUserPasskeys",
+			Handler:    _UserService_ListUserPasskeys_Handler,
+		},
+		{
+			MethodName: "BeginPasskeyRegistration",
+			Handler:    _UserService_BeginPasskeyRegistration_Handler,
+		},
+		{
+			MethodName: "FinishPasskeyRegistration",
+			Handler:    _UserService_FinishPasskeyRegistration_Handler,
+		},
+		{
+			MethodName: "DeleteUserPasskey",
+			Handler:    _UserService_DeleteUserPasskey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/user_service.proto",
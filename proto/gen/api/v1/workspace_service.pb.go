@@ -10,7 +10,10 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -35,6 +38,10 @@ const (
 	WorkspaceSetting_STORAGE WorkspaceSetting_Key = 3
 	// MEMO_RELATED is the key for memo related settings.
 	WorkspaceSetting_MEMO_RELATED WorkspaceSetting_Key = 4
+	// AI is the key for AI provider settings.
+	WorkspaceSetting_AI WorkspaceSetting_Key = 5
+	// EMAIL_TEMPLATES is the key for the email templates replacing the built-in ones.
+	WorkspaceSetting_EMAIL_TEMPLATES WorkspaceSetting_Key = 6
 )
 
 // Enum value maps for WorkspaceSetting_Key.
@@ -45,6 +52,8 @@ var (
 		2: "GENERAL",
 		3: "STORAGE",
 		4: "MEMO_RELATED",
+		5: "AI",
+		6: "EMAIL_TEMPLATES",
 	}
 	WorkspaceSetting_Key_value = map[string]int32{
 		"KEY_UNSPECIFIED": 0,
@@ -52,6 +61,8 @@ var (
 		"GENERAL":         2,
 		"STORAGE":         3,
 		"MEMO_RELATED":    4,
+		"AI":              5,
+		"EMAIL_TEMPLATES": 6,
 	}
 )
 
@@ -82,6 +93,63 @@ func (WorkspaceSetting_Key) EnumDescriptor() ([]byte, []int) {
 	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{2, 0}
 }
 
+type WorkspaceGeneralSetting_SmtpSecurity int32
+
+const (
+	// SMTP_SECURITY_UNSPECIFIED upgrades the connection with STARTTLS.
+	WorkspaceGeneralSetting_SMTP_SECURITY_UNSPECIFIED WorkspaceGeneralSetting_SmtpSecurity = 0
+	// STARTTLS upgrades the connection with STARTTLS, usually on port 587.
+	WorkspaceGeneralSetting_STARTTLS WorkspaceGeneralSetting_SmtpSecurity = 1
+	// TLS connects over TLS, usually on port 465.
+	WorkspaceGeneralSetting_TLS WorkspaceGeneralSetting_SmtpSecurity = 2
+	// PLAIN does not encrypt the connection, for relays on a trusted network.
+	// Credentials are then only sent to localhost.
+	WorkspaceGeneralSetting_PLAIN WorkspaceGeneralSetting_SmtpSecurity = 3
+)
+
+// Enum value maps for WorkspaceGeneralSetting_SmtpSecurity.
+var (
+	WorkspaceGeneralSetting_SmtpSecurity_name = map[int32]string{
+		0: "SMTP_SECURITY_UNSPECIFIED",
+		1: "STARTTLS",
+		2: "TLS",
+		3: "PLAIN",
+	}
+	WorkspaceGeneralSetting_SmtpSecurity_value = map[string]int32{
+		"SMTP_SECURITY_UNSPECIFIED": 0,
+		"STARTTLS":                  1,
+		"TLS":                       2,
+		"PLAIN":                     3,
+	}
+)
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) Enum() *WorkspaceGeneralSetting_SmtpSecurity {
+	p := new(WorkspaceGeneralSetting_SmtpSecurity)
+	*p = x
+	return p
+}
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceGeneralSetting_SmtpSecurity) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_workspace_service_proto_enumTypes[1].Descriptor()
+}
+
+func (WorkspaceGeneralSetting_SmtpSecurity) Type() protoreflect.EnumType {
+	return &file_api_v1_workspace_service_proto_enumTypes[1]
+}
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceGeneralSetting_SmtpSecurity.Descriptor instead.
+func (WorkspaceGeneralSetting_SmtpSecurity) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type WorkspaceStorageSetting_StorageType int32
 
 const (
@@ -121,11 +189,11 @@ func (x WorkspaceStorageSetting_StorageType) String() string {
 }
 
 func (WorkspaceStorageSetting_StorageType) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_v1_workspace_service_proto_enumTypes[1].Descriptor()
+	return file_api_v1_workspace_service_proto_enumTypes[2].Descriptor()
 }
 
 func (WorkspaceStorageSetting_StorageType) Type() protoreflect.EnumType {
-	return &file_api_v1_workspace_service_proto_enumTypes[1]
+	return &file_api_v1_workspace_service_proto_enumTypes[2]
 }
 
 func (x WorkspaceStorageSetting_StorageType) Number() protoreflect.EnumNumber {
@@ -134,7 +202,113 @@ func (x WorkspaceStorageSetting_StorageType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WorkspaceStorageSetting_StorageType.Descriptor instead.
 func (WorkspaceStorageSetting_StorageType) EnumDescriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{5, 0}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{6, 0}
+}
+
+type WorkspaceAISetting_Provider int32
+
+const (
+	WorkspaceAISetting_PROVIDER_UNSPECIFIED WorkspaceAISetting_Provider = 0
+	// WRAPPER is the hosted gRPC wrapper service.
+	WorkspaceAISetting_WRAPPER WorkspaceAISetting_Provider = 1
+	// OPENAI is any OpenAI-compatible HTTP API.
+	WorkspaceAISetting_OPENAI WorkspaceAISetting_Provider = 2
+	// OLLAMA is an Ollama-style local endpoint.
+	WorkspaceAISetting_OLLAMA WorkspaceAISetting_Provider = 3
+)
+
+// Enum value maps for WorkspaceAISetting_Provider.
+var (
+	WorkspaceAISetting_Provider_name = map[int32]string{
+		0: "PROVIDER_UNSPECIFIED",
+		1: "WRAPPER",
+		2: "OPENAI",
+		3: "OLLAMA",
+	}
+	WorkspaceAISetting_Provider_value = map[string]int32{
+		"PROVIDER_UNSPECIFIED": 0,
+		"WRAPPER":              1,
+		"OPENAI":               2,
+		"OLLAMA":               3,
+	}
+)
+
+func (x WorkspaceAISetting_Provider) Enum() *WorkspaceAISetting_Provider {
+	p := new(WorkspaceAISetting_Provider)
+	*p = x
+	return p
+}
+
+func (x WorkspaceAISetting_Provider) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceAISetting_Provider) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_workspace_service_proto_enumTypes[3].Descriptor()
+}
+
+func (WorkspaceAISetting_Provider) Type() protoreflect.EnumType {
+	return &file_api_v1_workspace_service_proto_enumTypes[3]
+}
+
+func (x WorkspaceAISetting_Provider) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Provider.Descriptor instead.
+func (WorkspaceAISetting_Provider) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type WorkspaceAISetting_Quota_Window int32
+
+const (
+	WorkspaceAISetting_Quota_WINDOW_UNSPECIFIED WorkspaceAISetting_Quota_Window = 0
+	// DAILY resets at midnight UTC.
+	WorkspaceAISetting_Quota_DAILY WorkspaceAISetting_Quota_Window = 1
+	// MONTHLY resets at midnight UTC on the first day of the month.
+	WorkspaceAISetting_Quota_MONTHLY WorkspaceAISetting_Quota_Window = 2
+)
+
+// Enum value maps for WorkspaceAISetting_Quota_Window.
+var (
+	WorkspaceAISetting_Quota_Window_name = map[int32]string{
+		0: "WINDOW_UNSPECIFIED",
+		1: "DAILY",
+		2: "MONTHLY",
+	}
+	WorkspaceAISetting_Quota_Window_value = map[string]int32{
+		"WINDOW_UNSPECIFIED": 0,
+		"DAILY":              1,
+		"MONTHLY":            2,
+	}
+)
+
+func (x WorkspaceAISetting_Quota_Window) Enum() *WorkspaceAISetting_Quota_Window {
+	p := new(WorkspaceAISetting_Quota_Window)
+	*p = x
+	return p
+}
+
+func (x WorkspaceAISetting_Quota_Window) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceAISetting_Quota_Window) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_workspace_service_proto_enumTypes[4].Descriptor()
+}
+
+func (WorkspaceAISetting_Quota_Window) Type() protoreflect.EnumType {
+	return &file_api_v1_workspace_service_proto_enumTypes[4]
+}
+
+func (x WorkspaceAISetting_Quota_Window) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Quota_Window.Descriptor instead.
+func (WorkspaceAISetting_Quota_Window) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{8, 0, 0}
 }
 
 // Workspace profile message containing basic workspace information.
@@ -148,9 +322,11 @@ type WorkspaceProfile struct {
 	// Mode is the instance mode (e.g. "prod", "dev" or "demo").
 	Mode string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
 	// Instance URL is the URL of the instance.
-	InstanceUrl   string `protobuf:"bytes,6,opt,name=instance_url,json=instanceUrl,proto3" json:"instance_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	InstanceUrl string `protobuf:"bytes,6,opt,name=instance_url,json=instanceUrl,proto3" json:"instance_url,omitempty"`
+	// The VAPID public key browsers subscribe to push notifications with.
+	VapidPublicKey string `protobuf:"bytes,7,opt,name=vapid_public_key,json=vapidPublicKey,proto3" json:"vapid_public_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *WorkspaceProfile) Reset() {
@@ -211,6 +387,13 @@ func (x *WorkspaceProfile) GetInstanceUrl() string {
 	return ""
 }
 
+func (x *WorkspaceProfile) GetVapidPublicKey() string {
+	if x != nil {
+		return x.VapidPublicKey
+	}
+	return ""
+}
+
 // Request for workspace profile.
 type GetWorkspaceProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -259,6 +442,8 @@ type WorkspaceSetting struct {
 	//	*WorkspaceSetting_GeneralSetting
 	//	*WorkspaceSetting_StorageSetting
 	//	*WorkspaceSetting_MemoRelatedSetting
+	//	*WorkspaceSetting_AiSetting
+	//	*WorkspaceSetting_EmailTemplatesSetting
 	Value         isWorkspaceSetting_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -335,6 +520,24 @@ func (x *WorkspaceSetting) GetMemoRelatedSetting() *WorkspaceMemoRelatedSetting
 	return nil
 }
 
+func (x *WorkspaceSetting) GetAiSetting() *WorkspaceAISetting {
+	if x != nil {
+		if x, ok := x.Value.(*WorkspaceSetting_AiSetting); ok {
+			return x.AiSetting
+		}
+	}
+	return nil
+}
+
+func (x *WorkspaceSetting) GetEmailTemplatesSetting() *WorkspaceEmailTemplatesSetting {
+	if x != nil {
+		if x, ok := x.Value.(*WorkspaceSetting_EmailTemplatesSetting); ok {
+			return x.EmailTemplatesSetting
+		}
+	}
+	return nil
+}
+
 type isWorkspaceSetting_Value interface {
 	isWorkspaceSetting_Value()
 }
@@ -351,12 +554,24 @@ type WorkspaceSetting_MemoRelatedSetting struct {
 	MemoRelatedSetting *WorkspaceMemoRelatedSetting `protobuf:"bytes,4,opt,name=memo_related_setting,json=memoRelatedSetting,proto3,oneof"`
 }
 
+type WorkspaceSetting_AiSetting struct {
+	AiSetting *WorkspaceAISetting `protobuf:"bytes,5,opt,name=ai_setting,json=aiSetting,proto3,oneof"`
+}
+
+type WorkspaceSetting_EmailTemplatesSetting struct {
+	EmailTemplatesSetting *WorkspaceEmailTemplatesSetting `protobuf:"bytes,6,opt,name=email_templates_setting,json=emailTemplatesSetting,proto3,oneof"`
+}
+
 func (*WorkspaceSetting_GeneralSetting) isWorkspaceSetting_Value() {}
 
 func (*WorkspaceSetting_StorageSetting) isWorkspaceSetting_Value() {}
 
 func (*WorkspaceSetting_MemoRelatedSetting) isWorkspaceSetting_Value() {}
 
+func (*WorkspaceSetting_AiSetting) isWorkspaceSetting_Value() {}
+
+func (*WorkspaceSetting_EmailTemplatesSetting) isWorkspaceSetting_Value() {}
+
 type WorkspaceGeneralSetting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// theme is the name of the selected theme.
@@ -392,8 +607,20 @@ type WorkspaceGeneralSetting struct {
 	SmtpAccountEmail string `protobuf:"bytes,14,opt,name=smtp_account_email,json=smtpAccountEmail,proto3" json:"smtp_account_email,omitempty"`
 	// smtp_account_password is the host's sender email address password
 	SmtpAccountPassword string `protobuf:"bytes,15,opt,name=smtp_account_password,json=smtpAccountPassword,proto3" json:"smtp_account_password,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// require_two_factor requires all users to enable two-factor authentication.
+	RequireTwoFactor bool `protobuf:"varint,16,opt,name=require_two_factor,json=requireTwoFactor,proto3" json:"require_two_factor,omitempty"`
+	// rate_limit limits sign-in, OTP and registration requests.
+	RateLimit *WorkspaceRateLimit `protobuf:"bytes,17,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// smtp_security is how the connection to the SMTP server is secured.
+	SmtpSecurity WorkspaceGeneralSetting_SmtpSecurity `protobuf:"varint,18,opt,name=smtp_security,json=smtpSecurity,proto3,enum=wekalist.api.v1.WorkspaceGeneralSetting_SmtpSecurity" json:"smtp_security,omitempty"`
+	// dkim_domain is the domain signing the emails with DKIM. Emails are not signed if empty.
+	DkimDomain string `protobuf:"bytes,19,opt,name=dkim_domain,json=dkimDomain,proto3" json:"dkim_domain,omitempty"`
+	// dkim_selector is the selector of the DKIM public key, published at {selector}._domainkey.{domain}.
+	DkimSelector string `protobuf:"bytes,20,opt,name=dkim_selector,json=dkimSelector,proto3" json:"dkim_selector,omitempty"`
+	// dkim_private_key is the PEM encoded RSA or Ed25519 private key signing the emails.
+	DkimPrivateKey string `protobuf:"bytes,21,opt,name=dkim_private_key,json=dkimPrivateKey,proto3" json:"dkim_private_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *WorkspaceGeneralSetting) Reset() {
@@ -531,6 +758,135 @@ func (x *WorkspaceGeneralSetting) GetSmtpAccountPassword() string {
 	return ""
 }
 
+func (x *WorkspaceGeneralSetting) GetRequireTwoFactor() bool {
+	if x != nil {
+		return x.RequireTwoFactor
+	}
+	return false
+}
+
+func (x *WorkspaceGeneralSetting) GetRateLimit() *WorkspaceRateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *WorkspaceGeneralSetting) GetSmtpSecurity() WorkspaceGeneralSetting_SmtpSecurity {
+	if x != nil {
+		return x.SmtpSecurity
+	}
+	return WorkspaceGeneralSetting_SMTP_SECURITY_UNSPECIFIED
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimDomain() string {
+	if x != nil {
+		return x.DkimDomain
+	}
+	return ""
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimSelector() string {
+	if x != nil {
+		return x.DkimSelector
+	}
+	return ""
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimPrivateKey() string {
+	if x != nil {
+		return x.DkimPrivateKey
+	}
+	return ""
+}
+
+// WorkspaceRateLimit limits how often sign-in, OTP and registration requests can be made,
+// per client IP and per account. Zero values use the defaults.
+type WorkspaceRateLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// disabled turns rate limiting off.
+	Disabled bool `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// ip_requests_per_minute is the number of requests a client IP can make per minute and method.
+	// Default is 20.
+	IpRequestsPerMinute int32 `protobuf:"varint,2,opt,name=ip_requests_per_minute,json=ipRequestsPerMinute,proto3" json:"ip_requests_per_minute,omitempty"`
+	// account_requests_per_minute is the number of requests per minute and method for an account.
+	// Default is 5.
+	AccountRequestsPerMinute int32 `protobuf:"varint,3,opt,name=account_requests_per_minute,json=accountRequestsPerMinute,proto3" json:"account_requests_per_minute,omitempty"`
+	// lockout_threshold is the number of consecutive failed sign-ins locking an account.
+	// Default is 5.
+	LockoutThreshold int32 `protobuf:"varint,4,opt,name=lockout_threshold,json=lockoutThreshold,proto3" json:"lockout_threshold,omitempty"`
+	// lockout_seconds is how long a locked account cannot sign in.
+	// Default is 900.
+	LockoutSeconds int32 `protobuf:"varint,5,opt,name=lockout_seconds,json=lockoutSeconds,proto3" json:"lockout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WorkspaceRateLimit) Reset() {
+	*x = WorkspaceRateLimit{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceRateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceRateLimit) ProtoMessage() {}
+
+func (x *WorkspaceRateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceRateLimit.ProtoReflect.Descriptor instead.
+func (*WorkspaceRateLimit) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WorkspaceRateLimit) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *WorkspaceRateLimit) GetIpRequestsPerMinute() int32 {
+	if x != nil {
+		return x.IpRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetAccountRequestsPerMinute() int32 {
+	if x != nil {
+		return x.AccountRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetLockoutThreshold() int32 {
+	if x != nil {
+		return x.LockoutThreshold
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetLockoutSeconds() int32 {
+	if x != nil {
+		return x.LockoutSeconds
+	}
+	return 0
+}
+
 type WorkspaceCustomProfile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -544,7 +900,7 @@ type WorkspaceCustomProfile struct {
 
 func (x *WorkspaceCustomProfile) Reset() {
 	*x = WorkspaceCustomProfile{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[4]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -556,7 +912,7 @@ func (x *WorkspaceCustomProfile) String() string {
 func (*WorkspaceCustomProfile) ProtoMessage() {}
 
 func (x *WorkspaceCustomProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[4]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -569,7 +925,7 @@ func (x *WorkspaceCustomProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceCustomProfile.ProtoReflect.Descriptor instead.
 func (*WorkspaceCustomProfile) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{4}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *WorkspaceCustomProfile) GetTitle() string {
@@ -624,7 +980,7 @@ type WorkspaceStorageSetting struct {
 
 func (x *WorkspaceStorageSetting) Reset() {
 	*x = WorkspaceStorageSetting{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[5]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -636,7 +992,7 @@ func (x *WorkspaceStorageSetting) String() string {
 func (*WorkspaceStorageSetting) ProtoMessage() {}
 
 func (x *WorkspaceStorageSetting) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[5]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -649,7 +1005,7 @@ func (x *WorkspaceStorageSetting) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceStorageSetting.ProtoReflect.Descriptor instead.
 func (*WorkspaceStorageSetting) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{5}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *WorkspaceStorageSetting) GetStorageType() WorkspaceStorageSetting_StorageType {
@@ -705,7 +1061,7 @@ type WorkspaceMemoRelatedSetting struct {
 
 func (x *WorkspaceMemoRelatedSetting) Reset() {
 	*x = WorkspaceMemoRelatedSetting{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[6]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -717,7 +1073,7 @@ func (x *WorkspaceMemoRelatedSetting) String() string {
 func (*WorkspaceMemoRelatedSetting) ProtoMessage() {}
 
 func (x *WorkspaceMemoRelatedSetting) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[6]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -730,7 +1086,7 @@ func (x *WorkspaceMemoRelatedSetting) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceMemoRelatedSetting.ProtoReflect.Descriptor instead.
 func (*WorkspaceMemoRelatedSetting) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{6}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *WorkspaceMemoRelatedSetting) GetDisallowPublicVisibility() bool {
@@ -789,31 +1145,43 @@ func (x *WorkspaceMemoRelatedSetting) GetNsfwTags() []string {
 	return nil
 }
 
-// Request message for GetWorkspaceSetting method.
-type GetWorkspaceSettingRequest struct {
+// AI provider workspace settings.
+type WorkspaceAISetting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The resource name of the workspace setting.
-	// Format: workspace/settings/{setting}
-	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetWorkspaceSettingRequest) Reset() {
-	*x = GetWorkspaceSettingRequest{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[7]
+	// provider is the AI provider used for generation.
+	Provider WorkspaceAISetting_Provider `protobuf:"varint,1,opt,name=provider,proto3,enum=wekalist.api.v1.WorkspaceAISetting_Provider" json:"provider,omitempty"`
+	// base_url is the endpoint of the provider.
+	// Leave empty to use the provider's default endpoint.
+	BaseUrl string `protobuf:"bytes,2,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// model is the name of the model to use.
+	Model string `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	// api_key is the credential sent to the provider.
+	ApiKey string `protobuf:"bytes,4,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// embedding_model is the name of the model used to compute memo embeddings.
+	EmbeddingModel string `protobuf:"bytes,5,opt,name=embedding_model,json=embeddingModel,proto3" json:"embedding_model,omitempty"`
+	// user_quota limits the AI usage of each user.
+	UserQuota *WorkspaceAISetting_Quota `protobuf:"bytes,6,opt,name=user_quota,json=userQuota,proto3" json:"user_quota,omitempty"`
+	// workspace_quota limits the AI usage of the whole workspace.
+	WorkspaceQuota *WorkspaceAISetting_Quota `protobuf:"bytes,7,opt,name=workspace_quota,json=workspaceQuota,proto3" json:"workspace_quota,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WorkspaceAISetting) Reset() {
+	*x = WorkspaceAISetting{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetWorkspaceSettingRequest) String() string {
+func (x *WorkspaceAISetting) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetWorkspaceSettingRequest) ProtoMessage() {}
+func (*WorkspaceAISetting) ProtoMessage() {}
 
-func (x *GetWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[7]
+func (x *WorkspaceAISetting) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -824,46 +1192,263 @@ func (x *GetWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetWorkspaceSettingRequest.ProtoReflect.Descriptor instead.
-func (*GetWorkspaceSettingRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use WorkspaceAISetting.ProtoReflect.Descriptor instead.
+func (*WorkspaceAISetting) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetWorkspaceSettingRequest) GetName() string {
+func (x *WorkspaceAISetting) GetProvider() WorkspaceAISetting_Provider {
 	if x != nil {
-		return x.Name
+		return x.Provider
 	}
-	return ""
+	return WorkspaceAISetting_PROVIDER_UNSPECIFIED
 }
 
-// Request message for UpdateWorkspaceSetting method.
-type UpdateWorkspaceSettingRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The workspace setting resource which replaces the resource on the server.
-	Setting *WorkspaceSetting `protobuf:"bytes,1,opt,name=setting,proto3" json:"setting,omitempty"`
-	// The list of fields to update.
-	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *WorkspaceAISetting) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
 }
 
-func (x *UpdateWorkspaceSettingRequest) Reset() {
-	*x = UpdateWorkspaceSettingRequest{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *WorkspaceAISetting) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
 }
 
-func (x *UpdateWorkspaceSettingRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *WorkspaceAISetting) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
 }
 
-func (*UpdateWorkspaceSettingRequest) ProtoMessage() {}
+func (x *WorkspaceAISetting) GetEmbeddingModel() string {
+	if x != nil {
+		return x.EmbeddingModel
+	}
+	return ""
+}
 
-func (x *UpdateWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[8]
+func (x *WorkspaceAISetting) GetUserQuota() *WorkspaceAISetting_Quota {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		return x.UserQuota
+	}
+	return nil
+}
+
+func (x *WorkspaceAISetting) GetWorkspaceQuota() *WorkspaceAISetting_Quota {
+	if x != nil {
+		return x.WorkspaceQuota
+	}
+	return nil
+}
+
+// Email templates workspace settings.
+type WorkspaceEmailTemplatesSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// templates replace the built-in email templates of the same name and locale.
+	Templates     []*EmailTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceEmailTemplatesSetting) Reset() {
+	*x = WorkspaceEmailTemplatesSetting{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceEmailTemplatesSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceEmailTemplatesSetting) ProtoMessage() {}
+
+func (x *WorkspaceEmailTemplatesSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceEmailTemplatesSetting.ProtoReflect.Descriptor instead.
+func (*WorkspaceEmailTemplatesSetting) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WorkspaceEmailTemplatesSetting) GetTemplates() []*EmailTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+type EmailTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the name of the built-in template replaced, such as "otp", "notification" or "digest".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// locale is the locale of the recipients the template is used for, such as "fr" or "pt-BR".
+	// Empty for the default locale, used when there is no template for the locale of the recipient.
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	// subject is the text/template of the subject.
+	Subject string `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	// text_body is the text/template of the plain text body.
+	TextBody string `protobuf:"bytes,4,opt,name=text_body,json=textBody,proto3" json:"text_body,omitempty"`
+	// html_body is the html/template of the HTML body.
+	HtmlBody      string `protobuf:"bytes,5,opt,name=html_body,json=htmlBody,proto3" json:"html_body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplate) Reset() {
+	*x = EmailTemplate{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplate) ProtoMessage() {}
+
+func (x *EmailTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplate.ProtoReflect.Descriptor instead.
+func (*EmailTemplate) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EmailTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetTextBody() string {
+	if x != nil {
+		return x.TextBody
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetHtmlBody() string {
+	if x != nil {
+		return x.HtmlBody
+	}
+	return ""
+}
+
+// Request message for GetWorkspaceSetting method.
+type GetWorkspaceSettingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the workspace setting.
+	// Format: workspace/settings/{setting}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkspaceSettingRequest) Reset() {
+	*x = GetWorkspaceSettingRequest{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkspaceSettingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspaceSettingRequest) ProtoMessage() {}
+
+func (x *GetWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspaceSettingRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkspaceSettingRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetWorkspaceSettingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Request message for UpdateWorkspaceSetting method.
+type UpdateWorkspaceSettingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The workspace setting resource which replaces the resource on the server.
+	Setting *WorkspaceSetting `protobuf:"bytes,1,opt,name=setting,proto3" json:"setting,omitempty"`
+	// The list of fields to update.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWorkspaceSettingRequest) Reset() {
+	*x = UpdateWorkspaceSettingRequest{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWorkspaceSettingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWorkspaceSettingRequest) ProtoMessage() {}
+
+func (x *UpdateWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
@@ -874,7 +1459,7 @@ func (x *UpdateWorkspaceSettingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateWorkspaceSettingRequest.ProtoReflect.Descriptor instead.
 func (*UpdateWorkspaceSettingRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{8}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UpdateWorkspaceSettingRequest) GetSetting() *WorkspaceSetting {
@@ -891,6 +1476,245 @@ func (x *UpdateWorkspaceSettingRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	return nil
 }
 
+// A key JWTs such as access tokens are signed with. The secret is never returned.
+type SigningKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resource name of the signing key, the key ID being the kid of the JWT headers.
+	// Format: workspace/signingKeys/{signing_key}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Output only. The creation timestamp.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Output only. The time JWTs signed by a retired key stop validating.
+	// Unset for the active key.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SigningKey) Reset() {
+	*x = SigningKey{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SigningKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningKey) ProtoMessage() {}
+
+func (x *SigningKey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningKey.ProtoReflect.Descriptor instead.
+func (*SigningKey) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SigningKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SigningKey) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *SigningKey) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type ListSigningKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSigningKeysRequest) Reset() {
+	*x = ListSigningKeysRequest{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSigningKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSigningKeysRequest) ProtoMessage() {}
+
+func (x *ListSigningKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSigningKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListSigningKeysRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{14}
+}
+
+type ListSigningKeysResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The signing keys, the active key first.
+	SigningKeys   []*SigningKey `protobuf:"bytes,1,rep,name=signing_keys,json=signingKeys,proto3" json:"signing_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSigningKeysResponse) Reset() {
+	*x = ListSigningKeysResponse{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSigningKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSigningKeysResponse) ProtoMessage() {}
+
+func (x *ListSigningKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSigningKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListSigningKeysResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListSigningKeysResponse) GetSigningKeys() []*SigningKey {
+	if x != nil {
+		return x.SigningKeys
+	}
+	return nil
+}
+
+type RotateSigningKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. How long JWTs signed by the previous key keep validating.
+	// Defaults to 7 days, zero revokes the previous key immediately.
+	GracePeriod   *durationpb.Duration `protobuf:"bytes,1,opt,name=grace_period,json=gracePeriod,proto3" json:"grace_period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSigningKeyRequest) Reset() {
+	*x = RotateSigningKeyRequest{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSigningKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSigningKeyRequest) ProtoMessage() {}
+
+func (x *RotateSigningKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSigningKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateSigningKeyRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RotateSigningKeyRequest) GetGracePeriod() *durationpb.Duration {
+	if x != nil {
+		return x.GracePeriod
+	}
+	return nil
+}
+
+type RevokeSigningKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. The resource name of the signing key to revoke.
+	// Format: workspace/signingKeys/{signing_key}
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSigningKeyRequest) Reset() {
+	*x = RevokeSigningKeyRequest{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSigningKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSigningKeyRequest) ProtoMessage() {}
+
+func (x *RevokeSigningKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSigningKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSigningKeyRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RevokeSigningKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 // Reference: https://developers.cloudflare.com/r2/examples/aws/aws-sdk-go/
 type WorkspaceStorageSetting_S3Config struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -906,7 +1730,7 @@ type WorkspaceStorageSetting_S3Config struct {
 
 func (x *WorkspaceStorageSetting_S3Config) Reset() {
 	*x = WorkspaceStorageSetting_S3Config{}
-	mi := &file_api_v1_workspace_service_proto_msgTypes[9]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -918,7 +1742,7 @@ func (x *WorkspaceStorageSetting_S3Config) String() string {
 func (*WorkspaceStorageSetting_S3Config) ProtoMessage() {}
 
 func (x *WorkspaceStorageSetting_S3Config) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_workspace_service_proto_msgTypes[9]
+	mi := &file_api_v1_workspace_service_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -931,7 +1755,7 @@ func (x *WorkspaceStorageSetting_S3Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceStorageSetting_S3Config.ProtoReflect.Descriptor instead.
 func (*WorkspaceStorageSetting_S3Config) Descriptor() ([]byte, []int) {
-	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{5, 0}
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{6, 0}
 }
 
 func (x *WorkspaceStorageSetting_S3Config) GetAccessKeyId() string {
@@ -976,30 +1800,101 @@ func (x *WorkspaceStorageSetting_S3Config) GetUsePathStyle() bool {
 	return false
 }
 
+// Quota limits AI usage within a time window.
+// A limit of 0 means unlimited.
+type WorkspaceAISetting_Quota struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// window is the period the limits apply to. Defaults to DAILY.
+	Window WorkspaceAISetting_Quota_Window `protobuf:"varint,1,opt,name=window,proto3,enum=wekalist.api.v1.WorkspaceAISetting_Quota_Window" json:"window,omitempty"`
+	// max_requests is the maximum number of AI calls in the window.
+	MaxRequests int64 `protobuf:"varint,2,opt,name=max_requests,json=maxRequests,proto3" json:"max_requests,omitempty"`
+	// max_tokens is the maximum number of tokens consumed in the window.
+	MaxTokens     int64 `protobuf:"varint,3,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceAISetting_Quota) Reset() {
+	*x = WorkspaceAISetting_Quota{}
+	mi := &file_api_v1_workspace_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceAISetting_Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceAISetting_Quota) ProtoMessage() {}
+
+func (x *WorkspaceAISetting_Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_workspace_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Quota.ProtoReflect.Descriptor instead.
+func (*WorkspaceAISetting_Quota) Descriptor() ([]byte, []int) {
+	return file_api_v1_workspace_service_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *WorkspaceAISetting_Quota) GetWindow() WorkspaceAISetting_Quota_Window {
+	if x != nil {
+		return x.Window
+	}
+	return WorkspaceAISetting_Quota_WINDOW_UNSPECIFIED
+}
+
+func (x *WorkspaceAISetting_Quota) GetMaxRequests() int64 {
+	if x != nil {
+		return x.MaxRequests
+	}
+	return 0
+}
+
+func (x *WorkspaceAISetting_Quota) GetMaxTokens() int64 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
 var File_api_v1_workspace_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_workspace_service_proto_rawDesc = "" +
 	"\n" +
-	"\x1eapi/v1/workspace_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a google/protobuf/field_mask.proto\"y\n" +
+	"\x1eapi/v1/workspace_service.proto\x12\x0fwekalist.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x01\n" +
 	"\x10WorkspaceProfile\x12\x14\n" +
 	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12\x12\n" +
 	"\x04mode\x18\x03 \x01(\tR\x04mode\x12!\n" +
-	"\finstance_url\x18\x06 \x01(\tR\vinstanceUrl\"\x1c\n" +
-	"\x1aGetWorkspaceProfileRequest\"\xfe\x03\n" +
+	"\finstance_url\x18\x06 \x01(\tR\vinstanceUrl\x12(\n" +
+	"\x10vapid_public_key\x18\a \x01(\tR\x0evapidPublicKey\"\x1c\n" +
+	"\x1aGetWorkspaceProfileRequest\"\xcc\x05\n" +
 	"\x10WorkspaceSetting\x12\x17\n" +
 	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12S\n" +
 	"\x0fgeneral_setting\x18\x02 \x01(\v2(.wekalist.api.v1.WorkspaceGeneralSettingH\x00R\x0egeneralSetting\x12S\n" +
 	"\x0fstorage_setting\x18\x03 \x01(\v2(.wekalist.api.v1.WorkspaceStorageSettingH\x00R\x0estorageSetting\x12`\n" +
-	"\x14memo_related_setting\x18\x04 \x01(\v2,.wekalist.api.v1.WorkspaceMemoRelatedSettingH\x00R\x12memoRelatedSetting\"Q\n" +
+	"\x14memo_related_setting\x18\x04 \x01(\v2,.wekalist.api.v1.WorkspaceMemoRelatedSettingH\x00R\x12memoRelatedSetting\x12D\n" +
+	"\n" +
+	"ai_setting\x18\x05 \x01(\v2#.wekalist.api.v1.WorkspaceAISettingH\x00R\taiSetting\x12i\n" +
+	"\x17email_templates_setting\x18\x06 \x01(\v2/.wekalist.api.v1.WorkspaceEmailTemplatesSettingH\x00R\x15emailTemplatesSetting\"n\n" +
 	"\x03Key\x12\x13\n" +
 	"\x0fKEY_UNSPECIFIED\x10\x00\x12\t\n" +
 	"\x05BASIC\x10\x01\x12\v\n" +
 	"\aGENERAL\x10\x02\x12\v\n" +
 	"\aSTORAGE\x10\x03\x12\x10\n" +
-	"\fMEMO_RELATED\x10\x04:i\xeaAf\n" +
+	"\fMEMO_RELATED\x10\x04\x12\x06\n" +
+	"\x02AI\x10\x05\x12\x13\n" +
+	"\x0fEMAIL_TEMPLATES\x10\x06:i\xeaAf\n" +
 	"!api.wekalist.dev/WorkspaceSetting\x12\x1cworkspace/settings/{setting}*\x11workspaceSettings2\x10workspaceSettingB\a\n" +
-	"\x05value\"\xfe\x05\n" +
+	"\x05value\"\x8d\t\n" +
 	"\x17WorkspaceGeneralSetting\x12\x14\n" +
 	"\x05theme\x18\x01 \x01(\tR\x05theme\x12<\n" +
 	"\x1adisallow_user_registration\x18\x02 \x01(\bR\x18disallowUserRegistration\x124\n" +
@@ -1016,7 +1911,26 @@ const file_api_v1_workspace_service_proto_rawDesc = "" +
 	"\tsmtp_port\x18\f \x01(\x05R\bsmtpPort\x122\n" +
 	"\x15smtp_account_username\x18\r \x01(\tR\x13smtpAccountUsername\x12,\n" +
 	"\x12smtp_account_email\x18\x0e \x01(\tR\x10smtpAccountEmail\x122\n" +
-	"\x15smtp_account_password\x18\x0f \x01(\tR\x13smtpAccountPassword\"\xa3\x01\n" +
+	"\x15smtp_account_password\x18\x0f \x01(\tR\x13smtpAccountPassword\x12,\n" +
+	"\x12require_two_factor\x18\x10 \x01(\bR\x10requireTwoFactor\x12B\n" +
+	"\n" +
+	"rate_limit\x18\x11 \x01(\v2#.wekalist.api.v1.WorkspaceRateLimitR\trateLimit\x12Z\n" +
+	"\rsmtp_security\x18\x12 \x01(\x0e25.wekalist.api.v1.WorkspaceGeneralSetting.SmtpSecurityR\fsmtpSecurity\x12\x1f\n" +
+	"\vdkim_domain\x18\x13 \x01(\tR\n" +
+	"dkimDomain\x12#\n" +
+	"\rdkim_selector\x18\x14 \x01(\tR\fdkimSelector\x12(\n" +
+	"\x10dkim_private_key\x18\x15 \x01(\tR\x0edkimPrivateKey\"O\n" +
+	"\fSmtpSecurity\x12\x1d\n" +
+	"\x19SMTP_SECURITY_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSTARTTLS\x10\x01\x12\a\n" +
+	"\x03TLS\x10\x02\x12\t\n" +
+	"\x05PLAIN\x10\x03\"\xfa\x01\n" +
+	"\x12WorkspaceRateLimit\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x123\n" +
+	"\x16ip_requests_per_minute\x18\x02 \x01(\x05R\x13ipRequestsPerMinute\x12=\n" +
+	"\x1baccount_requests_per_minute\x18\x03 \x01(\x05R\x18accountRequestsPerMinute\x12+\n" +
+	"\x11lockout_threshold\x18\x04 \x01(\x05R\x10lockoutThreshold\x12'\n" +
+	"\x0flockout_seconds\x18\x05 \x01(\x05R\x0elockoutSeconds\"\xa3\x01\n" +
 	"\x16WorkspaceCustomProfile\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x19\n" +
@@ -1050,18 +1964,71 @@ const file_api_v1_workspace_service_proto_rawDesc = "" +
 	"\x13enable_link_preview\x18\x05 \x01(\bR\x11enableLinkPreview\x12\x1c\n" +
 	"\treactions\x18\a \x03(\tR\treactions\x127\n" +
 	"\x18enable_blur_nsfw_content\x18\b \x01(\bR\x15enableBlurNsfwContent\x12\x1b\n" +
-	"\tnsfw_tags\x18\t \x03(\tR\bnsfwTags\"[\n" +
+	"\tnsfw_tags\x18\t \x03(\tR\bnsfwTags\"\x8a\x05\n" +
+	"\x12WorkspaceAISetting\x12H\n" +
+	"\bprovider\x18\x01 \x01(\x0e2,.wekalist.api.v1.WorkspaceAISetting.ProviderR\bprovider\x12\x19\n" +
+	"\bbase_url\x18\x02 \x01(\tR\abaseUrl\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x17\n" +
+	"\aapi_key\x18\x04 \x01(\tR\x06apiKey\x12'\n" +
+	"\x0fembedding_model\x18\x05 \x01(\tR\x0eembeddingModel\x12H\n" +
+	"\n" +
+	"user_quota\x18\x06 \x01(\v2).wekalist.api.v1.WorkspaceAISetting.QuotaR\tuserQuota\x12R\n" +
+	"\x0fworkspace_quota\x18\a \x01(\v2).wekalist.api.v1.WorkspaceAISetting.QuotaR\x0eworkspaceQuota\x1a\xcd\x01\n" +
+	"\x05Quota\x12H\n" +
+	"\x06window\x18\x01 \x01(\x0e20.wekalist.api.v1.WorkspaceAISetting.Quota.WindowR\x06window\x12!\n" +
+	"\fmax_requests\x18\x02 \x01(\x03R\vmaxRequests\x12\x1d\n" +
+	"\n" +
+	"max_tokens\x18\x03 \x01(\x03R\tmaxTokens\"8\n" +
+	"\x06Window\x12\x16\n" +
+	"\x12WINDOW_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\v\n" +
+	"\aMONTHLY\x10\x02\"I\n" +
+	"\bProvider\x12\x18\n" +
+	"\x14PROVIDER_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aWRAPPER\x10\x01\x12\n" +
+	"\n" +
+	"\x06OPENAI\x10\x02\x12\n" +
+	"\n" +
+	"\x06OLLAMA\x10\x03\"^\n" +
+	"\x1eWorkspaceEmailTemplatesSetting\x12<\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x1e.wekalist.api.v1.EmailTemplateR\ttemplates\"\x8f\x01\n" +
+	"\rEmailTemplate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x1b\n" +
+	"\ttext_body\x18\x04 \x01(\tR\btextBody\x12\x1b\n" +
+	"\thtml_body\x18\x05 \x01(\tR\bhtmlBody\"[\n" +
 	"\x1aGetWorkspaceSettingRequest\x12=\n" +
 	"\x04name\x18\x01 \x01(\tB)\xe0A\x02\xfaA#\n" +
 	"!api.wekalist.dev/WorkspaceSettingR\x04name\"\xa3\x01\n" +
 	"\x1dUpdateWorkspaceSettingRequest\x12@\n" +
 	"\asetting\x18\x01 \x01(\v2!.wekalist.api.v1.WorkspaceSettingB\x03\xe0A\x02R\asetting\x12@\n" +
 	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskB\x03\xe0A\x01R\n" +
-	"updateMask2\xfb\x03\n" +
+	"updateMask\"\x89\x02\n" +
+	"\n" +
+	"SigningKey\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tB\x03\xe0A\bR\x04name\x12@\n" +
+	"\vcreate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"createTime\x12@\n" +
+	"\vexpire_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"expireTime:^\xeaA[\n" +
+	"\x1bapi.wekalist.dev/SigningKey\x12#workspace/signingKeys/{signing_key}*\vsigningKeys2\n" +
+	"signingKey\"\x18\n" +
+	"\x16ListSigningKeysRequest\"Y\n" +
+	"\x17ListSigningKeysResponse\x12>\n" +
+	"\fsigning_keys\x18\x01 \x03(\v2\x1b.wekalist.api.v1.SigningKeyR\vsigningKeys\"\\\n" +
+	"\x17RotateSigningKeyRequest\x12A\n" +
+	"\fgrace_period\x18\x01 \x01(\v2\x19.google.protobuf.DurationB\x03\xe0A\x01R\vgracePeriod\"R\n" +
+	"\x17RevokeSigningKeyRequest\x127\n" +
+	"\x04name\x18\x01 \x01(\tB#\xe0A\x02\xfaA\x1d\n" +
+	"\x1bapi.wekalist.dev/SigningKeyR\x04name2\xa4\a\n" +
 	"\x10WorkspaceService\x12\x88\x01\n" +
 	"\x13GetWorkspaceProfile\x12+.wekalist.api.v1.GetWorkspaceProfileRequest\x1a!.wekalist.api.v1.WorkspaceProfile\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/workspace/profile\x12\x99\x01\n" +
 	"\x13GetWorkspaceSetting\x12+.wekalist.api.v1.GetWorkspaceSettingRequest\x1a!.wekalist.api.v1.WorkspaceSetting\"2\xdaA\x04name\x82\xd3\xe4\x93\x02%\x12#/api/v1/{name=workspace/settings/*}\x12\xbf\x01\n" +
-	"\x16UpdateWorkspaceSetting\x12..wekalist.api.v1.UpdateWorkspaceSettingRequest\x1a!.wekalist.api.v1.WorkspaceSetting\"R\xdaA\x13setting,update_mask\x82\xd3\xe4\x93\x026:\asetting2+/api/v1/{setting.name=workspace/settings/*}B\xbd\x01\n" +
+	"\x16UpdateWorkspaceSetting\x12..wekalist.api.v1.UpdateWorkspaceSettingRequest\x1a!.wekalist.api.v1.WorkspaceSetting\"R\xdaA\x13setting,update_mask\x82\xd3\xe4\x93\x026:\asetting2+/api/v1/{setting.name=workspace/settings/*}\x12\x8b\x01\n" +
+	"\x0fListSigningKeys\x12'.wekalist.api.v1.ListSigningKeysRequest\x1a(.wekalist.api.v1.ListSigningKeysResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/v1/workspace/signingKeys\x12\x8a\x01\n" +
+	"\x10RotateSigningKey\x12(.wekalist.api.v1.RotateSigningKeyRequest\x1a\x1b.wekalist.api.v1.SigningKey\"/\x82\xd3\xe4\x93\x02):\x01*\"$/api/v1/workspace/signingKeys:rotate\x12\x8b\x01\n" +
+	"\x10RevokeSigningKey\x12(.wekalist.api.v1.RevokeSigningKeyRequest\x1a\x16.google.protobuf.Empty\"5\xdaA\x04name\x82\xd3\xe4\x93\x02(*&/api/v1/{name=workspace/signingKeys/*}B\xbd\x01\n" +
 	"\x13com.wekalist.api.v1B\x15WorkspaceServiceProtoP\x01Z1github.com/imrany/wekalist/proto/gen/api/v1;apiv1\xa2\x02\x03WAX\xaa\x02\x0fWekalist.Api.V1\xca\x02\x0fWekalist\\Api\\V1\xe2\x02\x1bWekalist\\Api\\V1\\GPBMetadata\xea\x02\x11Wekalist::Api::V1b\x06proto3"
 
 var (
@@ -1076,43 +2043,78 @@ func file_api_v1_workspace_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_workspace_service_proto_rawDescData
 }
 
-var file_api_v1_workspace_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_v1_workspace_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_v1_workspace_service_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_api_v1_workspace_service_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_api_v1_workspace_service_proto_goTypes = []any{
-	(WorkspaceSetting_Key)(0),                // 0: wekalist.api.v1.WorkspaceSetting.Key
-	(WorkspaceStorageSetting_StorageType)(0), // 1: wekalist.api.v1.WorkspaceStorageSetting.StorageType
-	(*WorkspaceProfile)(nil),                 // 2: wekalist.api.v1.WorkspaceProfile
-	(*GetWorkspaceProfileRequest)(nil),       // 3: wekalist.api.v1.GetWorkspaceProfileRequest
-	(*WorkspaceSetting)(nil),                 // 4: wekalist.api.v1.WorkspaceSetting
-	(*WorkspaceGeneralSetting)(nil),          // 5: wekalist.api.v1.WorkspaceGeneralSetting
-	(*WorkspaceCustomProfile)(nil),           // 6: wekalist.api.v1.WorkspaceCustomProfile
-	(*WorkspaceStorageSetting)(nil),          // 7: wekalist.api.v1.WorkspaceStorageSetting
-	(*WorkspaceMemoRelatedSetting)(nil),      // 8: wekalist.api.v1.WorkspaceMemoRelatedSetting
-	(*GetWorkspaceSettingRequest)(nil),       // 9: wekalist.api.v1.GetWorkspaceSettingRequest
-	(*UpdateWorkspaceSettingRequest)(nil),    // 10: wekalist.api.v1.UpdateWorkspaceSettingRequest
-	(*WorkspaceStorageSetting_S3Config)(nil), // 11: wekalist.api.v1.WorkspaceStorageSetting.S3Config
-	(*fieldmaskpb.FieldMask)(nil),            // 12: google.protobuf.FieldMask
+	(WorkspaceSetting_Key)(0),                 // 0: wekalist.api.v1.WorkspaceSetting.Key
+	(WorkspaceGeneralSetting_SmtpSecurity)(0), // 1: wekalist.api.v1.WorkspaceGeneralSetting.SmtpSecurity
+	(WorkspaceStorageSetting_StorageType)(0),  // 2: wekalist.api.v1.WorkspaceStorageSetting.StorageType
+	(WorkspaceAISetting_Provider)(0),          // 3: wekalist.api.v1.WorkspaceAISetting.Provider
+	(WorkspaceAISetting_Quota_Window)(0),      // 4: wekalist.api.v1.WorkspaceAISetting.Quota.Window
+	(*WorkspaceProfile)(nil),                  // 5: wekalist.api.v1.WorkspaceProfile
+	(*GetWorkspaceProfileRequest)(nil),        // 6: wekalist.api.v1.GetWorkspaceProfileRequest
+	(*WorkspaceSetting)(nil),                  // 7: wekalist.api.v1.WorkspaceSetting
+	(*WorkspaceGeneralSetting)(nil),           // 8: wekalist.api.v1.WorkspaceGeneralSetting
+	(*WorkspaceRateLimit)(nil),                // 9: wekalist.api.v1.WorkspaceRateLimit
+	(*WorkspaceCustomProfile)(nil),            // 10: wekalist.api.v1.WorkspaceCustomProfile
+	(*WorkspaceStorageSetting)(nil),           // 11: wekalist.api.v1.WorkspaceStorageSetting
+	(*WorkspaceMemoRelatedSetting)(nil),       // 12: wekalist.api.v1.WorkspaceMemoRelatedSetting
+	(*WorkspaceAISetting)(nil),                // 13: wekalist.api.v1.WorkspaceAISetting
+	(*WorkspaceEmailTemplatesSetting)(nil),    // 14: wekalist.api.v1.WorkspaceEmailTemplatesSetting
+	(*EmailTemplate)(nil),                     // 15: wekalist.api.v1.EmailTemplate
+	(*GetWorkspaceSettingRequest)(nil),        // 16: wekalist.api.v1.GetWorkspaceSettingRequest
+	(*UpdateWorkspaceSettingRequest)(nil),     // 17: wekalist.api.v1.UpdateWorkspaceSettingRequest
+	(*SigningKey)(nil),                        // 18: wekalist.api.v1.SigningKey
+	(*ListSigningKeysRequest)(nil),            // 19: wekalist.api.v1.ListSigningKeysRequest
+	(*ListSigningKeysResponse)(nil),           // 20: wekalist.api.v1.ListSigningKeysResponse
+	(*RotateSigningKeyRequest)(nil),           // 21: wekalist.api.v1.RotateSigningKeyRequest
+	(*RevokeSigningKeyRequest)(nil),           // 22: wekalist.api.v1.RevokeSigningKeyRequest
+	(*WorkspaceStorageSetting_S3Config)(nil),  // 23: wekalist.api.v1.WorkspaceStorageSetting.S3Config
+	(*WorkspaceAISetting_Quota)(nil),          // 24: wekalist.api.v1.WorkspaceAISetting.Quota
+	(*fieldmaskpb.FieldMask)(nil),             // 25: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),             // 26: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),               // 27: google.protobuf.Duration
+	(*emptypb.Empty)(nil),                     // 28: google.protobuf.Empty
 }
 var file_api_v1_workspace_service_proto_depIdxs = []int32{
-	5,  // 0: wekalist.api.v1.WorkspaceSetting.general_setting:type_name -> wekalist.api.v1.WorkspaceGeneralSetting
-	7,  // 1: wekalist.api.v1.WorkspaceSetting.storage_setting:type_name -> wekalist.api.v1.WorkspaceStorageSetting
-	8,  // 2: wekalist.api.v1.WorkspaceSetting.memo_related_setting:type_name -> wekalist.api.v1.WorkspaceMemoRelatedSetting
-	6,  // 3: wekalist.api.v1.WorkspaceGeneralSetting.custom_profile:type_name -> wekalist.api.v1.WorkspaceCustomProfile
-	1,  // 4: wekalist.api.v1.WorkspaceStorageSetting.storage_type:type_name -> wekalist.api.v1.WorkspaceStorageSetting.StorageType
-	11, // 5: wekalist.api.v1.WorkspaceStorageSetting.s3_config:type_name -> wekalist.api.v1.WorkspaceStorageSetting.S3Config
-	4,  // 6: wekalist.api.v1.UpdateWorkspaceSettingRequest.setting:type_name -> wekalist.api.v1.WorkspaceSetting
-	12, // 7: wekalist.api.v1.UpdateWorkspaceSettingRequest.update_mask:type_name -> google.protobuf.FieldMask
-	3,  // 8: wekalist.api.v1.WorkspaceService.GetWorkspaceProfile:input_type -> wekalist.api.v1.GetWorkspaceProfileRequest
-	9,  // 9: wekalist.api.v1.WorkspaceService.GetWorkspaceSetting:input_type -> wekalist.api.v1.GetWorkspaceSettingRequest
-	10, // 10: wekalist.api.v1.WorkspaceService.UpdateWorkspaceSetting:input_type -> wekalist.api.v1.UpdateWorkspaceSettingRequest
-	2,  // 11: wekalist.api.v1.WorkspaceService.GetWorkspaceProfile:output_type -> wekalist.api.v1.WorkspaceProfile
-	4,  // 12: wekalist.api.v1.WorkspaceService.GetWorkspaceSetting:output_type -> wekalist.api.v1.WorkspaceSetting
-	4,  // 13: wekalist.api.v1.WorkspaceService.UpdateWorkspaceSetting:output_type -> wekalist.api.v1.WorkspaceSetting
-	11, // [11:14] is the sub-list for method output_type
-	8,  // [8:11] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	8,  // 0: wekalist.api.v1.WorkspaceSetting.general_setting:type_name -> wekalist.api.v1.WorkspaceGeneralSetting
+	11, // 1: wekalist.api.v1.WorkspaceSetting.storage_setting:type_name -> wekalist.api.v1.WorkspaceStorageSetting
+	12, // 2: wekalist.api.v1.WorkspaceSetting.memo_related_setting:type_name -> wekalist.api.v1.WorkspaceMemoRelatedSetting
+	13, // 3: wekalist.api.v1.WorkspaceSetting.ai_setting:type_name -> wekalist.api.v1.WorkspaceAISetting
+	14, // 4: wekalist.api.v1.WorkspaceSetting.email_templates_setting:type_name -> wekalist.api.v1.WorkspaceEmailTemplatesSetting
+	10, // 5: wekalist.api.v1.WorkspaceGeneralSetting.custom_profile:type_name -> wekalist.api.v1.WorkspaceCustomProfile
+	9,  // 6: wekalist.api.v1.WorkspaceGeneralSetting.rate_limit:type_name -> wekalist.api.v1.WorkspaceRateLimit
+	1,  // 7: wekalist.api.v1.WorkspaceGeneralSetting.smtp_security:type_name -> wekalist.api.v1.WorkspaceGeneralSetting.SmtpSecurity
+	2,  // 8: wekalist.api.v1.WorkspaceStorageSetting.storage_type:type_name -> wekalist.api.v1.WorkspaceStorageSetting.StorageType
+	23, // 9: wekalist.api.v1.WorkspaceStorageSetting.s3_config:type_name -> wekalist.api.v1.WorkspaceStorageSetting.S3Config
+	3,  // 10: wekalist.api.v1.WorkspaceAISetting.provider:type_name -> wekalist.api.v1.WorkspaceAISetting.Provider
+	24, // 11: wekalist.api.v1.WorkspaceAISetting.user_quota:type_name -> wekalist.api.v1.WorkspaceAISetting.Quota
+	24, // 12: wekalist.api.v1.WorkspaceAISetting.workspace_quota:type_name -> wekalist.api.v1.WorkspaceAISetting.Quota
+	15, // 13: wekalist.api.v1.WorkspaceEmailTemplatesSetting.templates:type_name -> wekalist.api.v1.EmailTemplate
+	7,  // 14: wekalist.api.v1.UpdateWorkspaceSettingRequest.setting:type_name -> wekalist.api.v1.WorkspaceSetting
+	25, // 15: wekalist.api.v1.UpdateWorkspaceSettingRequest.update_mask:type_name -> google.protobuf.FieldMask
+	26, // 16: wekalist.api.v1.SigningKey.create_time:type_name -> google.protobuf.Timestamp
+	26, // 17: wekalist.api.v1.SigningKey.expire_time:type_name -> google.protobuf.Timestamp
+	18, // 18: wekalist.api.v1.ListSigningKeysResponse.signing_keys:type_name -> wekalist.api.v1.SigningKey
+	27, // 19: wekalist.api.v1.RotateSigningKeyRequest.grace_period:type_name -> google.protobuf.Duration
+	4,  // 20: wekalist.api.v1.WorkspaceAISetting.Quota.window:type_name -> wekalist.api.v1.WorkspaceAISetting.Quota.Window
+	6,  // 21: wekalist.api.v1.WorkspaceService.GetWorkspaceProfile:input_type -> wekalist.api.v1.GetWorkspaceProfileRequest
+	16, // 22: wekalist.api.v1.WorkspaceService.GetWorkspaceSetting:input_type -> wekalist.api.v1.GetWorkspaceSettingRequest
+	17, // 23: wekalist.api.v1.WorkspaceService.UpdateWorkspaceSetting:input_type -> wekalist.api.v1.UpdateWorkspaceSettingRequest
+	19, // 24: wekalist.api.v1.WorkspaceService.ListSigningKeys:input_type -> wekalist.api.v1.ListSigningKeysRequest
+	21, // 25: wekalist.api.v1.WorkspaceService.RotateSigningKey:input_type -> wekalist.api.v1.RotateSigningKeyRequest
+	22, // 26: wekalist.api.v1.WorkspaceService.RevokeSigningKey:input_type -> wekalist.api.v1.RevokeSigningKeyRequest
+	5,  // 27: wekalist.api.v1.WorkspaceService.GetWorkspaceProfile:output_type -> wekalist.api.v1.WorkspaceProfile
+	7,  // 28: wekalist.api.v1.WorkspaceService.GetWorkspaceSetting:output_type -> wekalist.api.v1.WorkspaceSetting
+	7,  // 29: wekalist.api.v1.WorkspaceService.UpdateWorkspaceSetting:output_type -> wekalist.api.v1.WorkspaceSetting
+	20, // 30: wekalist.api.v1.WorkspaceService.ListSigningKeys:output_type -> wekalist.api.v1.ListSigningKeysResponse
+	18, // 31: wekalist.api.v1.WorkspaceService.RotateSigningKey:output_type -> wekalist.api.v1.SigningKey
+	28, // 32: wekalist.api.v1.WorkspaceService.RevokeSigningKey:output_type -> google.protobuf.Empty
+	27, // [27:33] is the sub-list for method output_type
+	21, // [21:27] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_workspace_service_proto_init() }
@@ -1124,14 +2126,16 @@ func file_api_v1_workspace_service_proto_init() {
 		(*WorkspaceSetting_GeneralSetting)(nil),
 		(*WorkspaceSetting_StorageSetting)(nil),
 		(*WorkspaceSetting_MemoRelatedSetting)(nil),
+		(*WorkspaceSetting_AiSetting)(nil),
+		(*WorkspaceSetting_EmailTemplatesSetting)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_workspace_service_proto_rawDesc), len(file_api_v1_workspace_service_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   10,
+			NumEnums:      5,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,9 @@ type IdentityProvider_Type int32
 const (
 	IdentityProvider_TYPE_UNSPECIFIED IdentityProvider_Type = 0
 	IdentityProvider_OAUTH2           IdentityProvider_Type = 1
+	IdentityProvider_OIDC             IdentityProvider_Type = 2
+	IdentityProvider_LDAP             IdentityProvider_Type = 3
+	IdentityProvider_SAML             IdentityProvider_Type = 4
 )
 
 // Enum value maps for IdentityProvider_Type.
@@ -33,10 +36,16 @@ var (
 	IdentityProvider_Type_name = map[int32]string{
 		0: "TYPE_UNSPECIFIED",
 		1: "OAUTH2",
+		2: "OIDC",
+		3: "LDAP",
+		4: "SAML",
 	}
 	IdentityProvider_Type_value = map[string]int32{
 		"TYPE_UNSPECIFIED": 0,
 		"OAUTH2":           1,
+		"OIDC":             2,
+		"LDAP":             3,
+		"SAML":             4,
 	}
 )
 
@@ -148,6 +157,9 @@ type IdentityProviderConfig struct {
 	// Types that are valid to be assigned to Config:
 	//
 	//	*IdentityProviderConfig_Oauth2Config
+	//	*IdentityProviderConfig_OidcConfig
+	//	*IdentityProviderConfig_LdapConfig
+	//	*IdentityProviderConfig_SamlConfig
 	Config        isIdentityProviderConfig_Config `protobuf_oneof:"config"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -199,6 +211,33 @@ func (x *IdentityProviderConfig) GetOauth2Config() *OAuth2Config {
 	return nil
 }
 
+func (x *IdentityProviderConfig) GetOidcConfig() *OIDCConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_OidcConfig); ok {
+			return x.OidcConfig
+		}
+	}
+	return nil
+}
+
+func (x *IdentityProviderConfig) GetLdapConfig() *LDAPConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_LdapConfig); ok {
+			return x.LdapConfig
+		}
+	}
+	return nil
+}
+
+func (x *IdentityProviderConfig) GetSamlConfig() *SAMLConfig {
+	if x != nil {
+		if x, ok := x.Config.(*IdentityProviderConfig_SamlConfig); ok {
+			return x.SamlConfig
+		}
+	}
+	return nil
+}
+
 type isIdentityProviderConfig_Config interface {
 	isIdentityProviderConfig_Config()
 }
@@ -207,8 +246,26 @@ type IdentityProviderConfig_Oauth2Config struct {
 	Oauth2Config *OAuth2Config `protobuf:"bytes,1,opt,name=oauth2_config,json=oauth2Config,proto3,oneof"`
 }
 
+type IdentityProviderConfig_OidcConfig struct {
+	OidcConfig *OIDCConfig `protobuf:"bytes,2,opt,name=oidc_config,json=oidcConfig,proto3,oneof"`
+}
+
+type IdentityProviderConfig_LdapConfig struct {
+	LdapConfig *LDAPConfig `protobuf:"bytes,3,opt,name=ldap_config,json=ldapConfig,proto3,oneof"`
+}
+
+type IdentityProviderConfig_SamlConfig struct {
+	SamlConfig *SAMLConfig `protobuf:"bytes,4,opt,name=saml_config,json=samlConfig,proto3,oneof"`
+}
+
 func (*IdentityProviderConfig_Oauth2Config) isIdentityProviderConfig_Config() {}
 
+func (*IdentityProviderConfig_OidcConfig) isIdentityProviderConfig_Config() {}
+
+func (*IdentityProviderConfig_LdapConfig) isIdentityProviderConfig_Config() {}
+
+func (*IdentityProviderConfig_SamlConfig) isIdentityProviderConfig_Config() {}
+
 type FieldMapping struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
@@ -369,23 +426,352 @@ func (x *OAuth2Config) GetFieldMapping() *FieldMapping {
 	return nil
 }
 
+type OIDCConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IssuerUrl     string                 `protobuf:"bytes,1,opt,name=issuer_url,json=issuerUrl,proto3" json:"issuer_url,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret  string                 `protobuf:"bytes,3,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	FieldMapping  *FieldMapping          `protobuf:"bytes,5,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OIDCConfig) Reset() {
+	*x = OIDCConfig{}
+	mi := &file_store_idp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OIDCConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OIDCConfig) ProtoMessage() {}
+
+func (x *OIDCConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_store_idp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OIDCConfig.ProtoReflect.Descriptor instead.
+func (*OIDCConfig) Descriptor() ([]byte, []int) {
+	return file_store_idp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OIDCConfig) GetIssuerUrl() string {
+	if x != nil {
+		return x.IssuerUrl
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *OIDCConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
+type LDAPConfig struct {
+	state              protoimpl.MessageState         `protogen:"open.v1"`
+	Url                string                         `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	StartTls           bool                           `protobuf:"varint,2,opt,name=start_tls,json=startTls,proto3" json:"start_tls,omitempty"`
+	InsecureSkipVerify bool                           `protobuf:"varint,3,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	BindDn             string                         `protobuf:"bytes,4,opt,name=bind_dn,json=bindDn,proto3" json:"bind_dn,omitempty"`
+	BindPassword       string                         `protobuf:"bytes,5,opt,name=bind_password,json=bindPassword,proto3" json:"bind_password,omitempty"`
+	BaseDn             string                         `protobuf:"bytes,6,opt,name=base_dn,json=baseDn,proto3" json:"base_dn,omitempty"`
+	UserFilter         string                         `protobuf:"bytes,7,opt,name=user_filter,json=userFilter,proto3" json:"user_filter,omitempty"`
+	FieldMapping       *FieldMapping                  `protobuf:"bytes,8,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	GroupAttribute     string                         `protobuf:"bytes,9,opt,name=group_attribute,json=groupAttribute,proto3" json:"group_attribute,omitempty"`
+	GroupRoleMappings  []*LDAPConfig_GroupRoleMapping `protobuf:"bytes,10,rep,name=group_role_mappings,json=groupRoleMappings,proto3" json:"group_role_mappings,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *LDAPConfig) Reset() {
+	*x = LDAPConfig{}
+	mi := &file_store_idp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LDAPConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LDAPConfig) ProtoMessage() {}
+
+func (x *LDAPConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_store_idp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LDAPConfig.ProtoReflect.Descriptor instead.
+func (*LDAPConfig) Descriptor() ([]byte, []int) {
+	return file_store_idp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LDAPConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetStartTls() bool {
+	if x != nil {
+		return x.StartTls
+	}
+	return false
+}
+
+func (x *LDAPConfig) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+func (x *LDAPConfig) GetBindDn() string {
+	if x != nil {
+		return x.BindDn
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetBindPassword() string {
+	if x != nil {
+		return x.BindPassword
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetBaseDn() string {
+	if x != nil {
+		return x.BaseDn
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetUserFilter() string {
+	if x != nil {
+		return x.UserFilter
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
+func (x *LDAPConfig) GetGroupAttribute() string {
+	if x != nil {
+		return x.GroupAttribute
+	}
+	return ""
+}
+
+func (x *LDAPConfig) GetGroupRoleMappings() []*LDAPConfig_GroupRoleMapping {
+	if x != nil {
+		return x.GroupRoleMappings
+	}
+	return nil
+}
+
+type SAMLConfig struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IdpEntityId    string                 `protobuf:"bytes,1,opt,name=idp_entity_id,json=idpEntityId,proto3" json:"idp_entity_id,omitempty"`
+	IdpSsoUrl      string                 `protobuf:"bytes,2,opt,name=idp_sso_url,json=idpSsoUrl,proto3" json:"idp_sso_url,omitempty"`
+	IdpCertificate string                 `protobuf:"bytes,3,opt,name=idp_certificate,json=idpCertificate,proto3" json:"idp_certificate,omitempty"`
+	SpEntityId     string                 `protobuf:"bytes,4,opt,name=sp_entity_id,json=spEntityId,proto3" json:"sp_entity_id,omitempty"`
+	FieldMapping   *FieldMapping          `protobuf:"bytes,5,opt,name=field_mapping,json=fieldMapping,proto3" json:"field_mapping,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SAMLConfig) Reset() {
+	*x = SAMLConfig{}
+	mi := &file_store_idp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SAMLConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SAMLConfig) ProtoMessage() {}
+
+func (x *SAMLConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_store_idp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SAMLConfig.ProtoReflect.Descriptor instead.
+func (*SAMLConfig) Descriptor() ([]byte, []int) {
+	return file_store_idp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SAMLConfig) GetIdpEntityId() string {
+	if x != nil {
+		return x.IdpEntityId
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetIdpSsoUrl() string {
+	if x != nil {
+		return x.IdpSsoUrl
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetIdpCertificate() string {
+	if x != nil {
+		return x.IdpCertificate
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetSpEntityId() string {
+	if x != nil {
+		return x.SpEntityId
+	}
+	return ""
+}
+
+func (x *SAMLConfig) GetFieldMapping() *FieldMapping {
+	if x != nil {
+		return x.FieldMapping
+	}
+	return nil
+}
+
+type LDAPConfig_GroupRoleMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LDAPConfig_GroupRoleMapping) Reset() {
+	*x = LDAPConfig_GroupRoleMapping{}
+	mi := &file_store_idp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LDAPConfig_GroupRoleMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LDAPConfig_GroupRoleMapping) ProtoMessage() {}
+
+func (x *LDAPConfig_GroupRoleMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_store_idp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LDAPConfig_GroupRoleMapping.ProtoReflect.Descriptor instead.
+func (*LDAPConfig_GroupRoleMapping) Descriptor() ([]byte, []int) {
+	return file_store_idp_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *LDAPConfig_GroupRoleMapping) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *LDAPConfig_GroupRoleMapping) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
 var File_store_idp_proto protoreflect.FileDescriptor
 
 const file_store_idp_proto_rawDesc = "" +
 	"\n" +
-	"\x0fstore/idp.proto\x12\x0ewekalist.store\"\x88\x02\n" +
+	"\x0fstore/idp.proto\x12\x0ewekalist.store\"\xa6\x02\n" +
 	"\x10IdentityProvider\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
 	"\x04type\x18\x03 \x01(\x0e2%.wekalist.store.IdentityProvider.TypeR\x04type\x12+\n" +
 	"\x11identifier_filter\x18\x04 \x01(\tR\x10identifierFilter\x12>\n" +
-	"\x06config\x18\x05 \x01(\v2&.wekalist.store.IdentityProviderConfigR\x06config\"(\n" +
+	"\x06config\x18\x05 \x01(\v2&.wekalist.store.IdentityProviderConfigR\x06config\"F\n" +
 	"\x04Type\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
-	"\x06OAUTH2\x10\x01\"g\n" +
+	"\x06OAUTH2\x10\x01\x12\b\n" +
+	"\x04OIDC\x10\x02\x12\b\n" +
+	"\x04LDAP\x10\x03\x12\b\n" +
+	"\x04SAML\x10\x04\"\xa4\x02\n" +
 	"\x16IdentityProviderConfig\x12C\n" +
-	"\roauth2_config\x18\x01 \x01(\v2\x1c.wekalist.store.OAuth2ConfigH\x00R\foauth2ConfigB\b\n" +
+	"\roauth2_config\x18\x01 \x01(\v2\x1c.wekalist.store.OAuth2ConfigH\x00R\foauth2Config\x12=\n" +
+	"\voidc_config\x18\x02 \x01(\v2\x1a.wekalist.store.OIDCConfigH\x00R\n" +
+	"oidcConfig\x12=\n" +
+	"\vldap_config\x18\x03 \x01(\v2\x1a.wekalist.store.LDAPConfigH\x00R\n" +
+	"ldapConfig\x12=\n" +
+	"\vsaml_config\x18\x04 \x01(\v2\x1a.wekalist.store.SAMLConfigH\x00R\n" +
+	"samlConfigB\b\n" +
 	"\x06config\"\x86\x01\n" +
 	"\fFieldMapping\x12\x1e\n" +
 	"\n" +
@@ -402,7 +788,40 @@ const file_store_idp_proto_rawDesc = "" +
 	"\ttoken_url\x18\x04 \x01(\tR\btokenUrl\x12\"\n" +
 	"\ruser_info_url\x18\x05 \x01(\tR\vuserInfoUrl\x12\x16\n" +
 	"\x06scopes\x18\x06 \x03(\tR\x06scopes\x12A\n" +
-	"\rfield_mapping\x18\a \x01(\v2\x1c.wekalist.store.FieldMappingR\ffieldMappingB\xa3\x01\n" +
+	"\rfield_mapping\x18\a \x01(\v2\x1c.wekalist.store.FieldMappingR\ffieldMapping\"\xc8\x01\n" +
+	"\n" +
+	"OIDCConfig\x12\x1d\n" +
+	"\n" +
+	"issuer_url\x18\x01 \x01(\tR\tissuerUrl\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12#\n" +
+	"\rclient_secret\x18\x03 \x01(\tR\fclientSecret\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12A\n" +
+	"\rfield_mapping\x18\x05 \x01(\v2\x1c.wekalist.store.FieldMappingR\ffieldMapping\"\xec\x03\n" +
+	"\n" +
+	"LDAPConfig\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1b\n" +
+	"\tstart_tls\x18\x02 \x01(\bR\bstartTls\x120\n" +
+	"\x14insecure_skip_verify\x18\x03 \x01(\bR\x12insecureSkipVerify\x12\x17\n" +
+	"\abind_dn\x18\x04 \x01(\tR\x06bindDn\x12#\n" +
+	"\rbind_password\x18\x05 \x01(\tR\fbindPassword\x12\x17\n" +
+	"\abase_dn\x18\x06 \x01(\tR\x06baseDn\x12\x1f\n" +
+	"\vuser_filter\x18\a \x01(\tR\n" +
+	"userFilter\x12A\n" +
+	"\rfield_mapping\x18\b \x01(\v2\x1c.wekalist.store.FieldMappingR\ffieldMapping\x12'\n" +
+	"\x0fgroup_attribute\x18\t \x01(\tR\x0egroupAttribute\x12[\n" +
+	"\x13group_role_mappings\x18\n" +
+	" \x03(\v2+.wekalist.store.LDAPConfig.GroupRoleMappingR\x11groupRoleMappings\x1a<\n" +
+	"\x10GroupRoleMapping\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\xde\x01\n" +
+	"\n" +
+	"SAMLConfig\x12\"\n" +
+	"\ridp_entity_id\x18\x01 \x01(\tR\vidpEntityId\x12\x1e\n" +
+	"\vidp_sso_url\x18\x02 \x01(\tR\tidpSsoUrl\x12'\n" +
+	"\x0fidp_certificate\x18\x03 \x01(\tR\x0eidpCertificate\x12 \n" +
+	"\fsp_entity_id\x18\x04 \x01(\tR\n" +
+	"spEntityId\x12A\n" +
+	"\rfield_mapping\x18\x05 \x01(\v2\x1c.wekalist.store.FieldMappingR\ffieldMappingB\xa3\x01\n" +
 	"\x12com.wekalist.storeB\bIdpProtoP\x01Z*github.com/imrany/wekalist/proto/gen/store\xa2\x02\x03WSX\xaa\x02\x0eWekalist.Store\xca\x02\x0eWekalist\\Store\xe2\x02\x1aWekalist\\Store\\GPBMetadata\xea\x02\x0fWekalist::Storeb\x06proto3"
 
 var (
@@ -418,24 +837,35 @@ func file_store_idp_proto_rawDescGZIP() []byte {
 }
 
 var file_store_idp_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_store_idp_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_store_idp_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_store_idp_proto_goTypes = []any{
-	(IdentityProvider_Type)(0),     // 0: wekalist.store.IdentityProvider.Type
-	(*IdentityProvider)(nil),       // 1: wekalist.store.IdentityProvider
-	(*IdentityProviderConfig)(nil), // 2: wekalist.store.IdentityProviderConfig
-	(*FieldMapping)(nil),           // 3: wekalist.store.FieldMapping
-	(*OAuth2Config)(nil),           // 4: wekalist.store.OAuth2Config
+	(IdentityProvider_Type)(0),          // 0: wekalist.store.IdentityProvider.Type
+	(*IdentityProvider)(nil),            // 1: wekalist.store.IdentityProvider
+	(*IdentityProviderConfig)(nil),      // 2: wekalist.store.IdentityProviderConfig
+	(*FieldMapping)(nil),                // 3: wekalist.store.FieldMapping
+	(*OAuth2Config)(nil),                // 4: wekalist.store.OAuth2Config
+	(*OIDCConfig)(nil),                  // 5: wekalist.store.OIDCConfig
+	(*LDAPConfig)(nil),                  // 6: wekalist.store.LDAPConfig
+	(*SAMLConfig)(nil),                  // 7: wekalist.store.SAMLConfig
+	(*LDAPConfig_GroupRoleMapping)(nil), // 8: wekalist.store.LDAPConfig.GroupRoleMapping
 }
 var file_store_idp_proto_depIdxs = []int32{
-	0, // 0: wekalist.store.IdentityProvider.type:type_name -> wekalist.store.IdentityProvider.Type
-	2, // 1: wekalist.store.IdentityProvider.config:type_name -> wekalist.store.IdentityProviderConfig
-	4, // 2: wekalist.store.IdentityProviderConfig.oauth2_config:type_name -> wekalist.store.OAuth2Config
-	3, // 3: wekalist.store.OAuth2Config.field_mapping:type_name -> wekalist.store.FieldMapping
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: wekalist.store.IdentityProvider.type:type_name -> wekalist.store.IdentityProvider.Type
+	2,  // 1: wekalist.store.IdentityProvider.config:type_name -> wekalist.store.IdentityProviderConfig
+	4,  // 2: wekalist.store.IdentityProviderConfig.oauth2_config:type_name -> wekalist.store.OAuth2Config
+	5,  // 3: wekalist.store.IdentityProviderConfig.oidc_config:type_name -> wekalist.store.OIDCConfig
+	6,  // 4: wekalist.store.IdentityProviderConfig.ldap_config:type_name -> wekalist.store.LDAPConfig
+	7,  // 5: wekalist.store.IdentityProviderConfig.saml_config:type_name -> wekalist.store.SAMLConfig
+	3,  // 6: wekalist.store.OAuth2Config.field_mapping:type_name -> wekalist.store.FieldMapping
+	3,  // 7: wekalist.store.OIDCConfig.field_mapping:type_name -> wekalist.store.FieldMapping
+	3,  // 8: wekalist.store.LDAPConfig.field_mapping:type_name -> wekalist.store.FieldMapping
+	8,  // 9: wekalist.store.LDAPConfig.group_role_mappings:type_name -> wekalist.store.LDAPConfig.GroupRoleMapping
+	3,  // 10: wekalist.store.SAMLConfig.field_mapping:type_name -> wekalist.store.FieldMapping
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_store_idp_proto_init() }
@@ -445,6 +875,9 @@ func file_store_idp_proto_init() {
 	}
 	file_store_idp_proto_msgTypes[1].OneofWrappers = []any{
 		(*IdentityProviderConfig_Oauth2Config)(nil),
+		(*IdentityProviderConfig_OidcConfig)(nil),
+		(*IdentityProviderConfig_LdapConfig)(nil),
+		(*IdentityProviderConfig_SamlConfig)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -452,7 +885,7 @@ func file_store_idp_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_store_idp_proto_rawDesc), len(file_store_idp_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
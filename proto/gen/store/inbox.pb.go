@@ -27,6 +27,8 @@ const (
 	InboxMessage_TYPE_UNSPECIFIED InboxMessage_Type = 0
 	InboxMessage_MEMO_COMMENT     InboxMessage_Type = 1
 	InboxMessage_VERSION_UPDATE   InboxMessage_Type = 2
+	InboxMessage_MEMO_REACTION    InboxMessage_Type = 3
+	InboxMessage_MEMO_MENTION     InboxMessage_Type = 4
 )
 
 // Enum value maps for InboxMessage_Type.
@@ -35,11 +37,15 @@ var (
 		0: "TYPE_UNSPECIFIED",
 		1: "MEMO_COMMENT",
 		2: "VERSION_UPDATE",
+		3: "MEMO_REACTION",
+		4: "MEMO_MENTION",
 	}
 	InboxMessage_Type_value = map[string]int32{
 		"TYPE_UNSPECIFIED": 0,
 		"MEMO_COMMENT":     1,
 		"VERSION_UPDATE":   2,
+		"MEMO_REACTION":    3,
+		"MEMO_MENTION":     4,
 	}
 )
 
@@ -126,15 +132,17 @@ var File_store_inbox_proto protoreflect.FileDescriptor
 
 const file_store_inbox_proto_rawDesc = "" +
 	"\n" +
-	"\x11store/inbox.proto\x12\x0ewekalist.store\"\xbf\x01\n" +
+	"\x11store/inbox.proto\x12\x0ewekalist.store\"\xe4\x01\n" +
 	"\fInboxMessage\x125\n" +
 	"\x04type\x18\x01 \x01(\x0e2!.wekalist.store.InboxMessage.TypeR\x04type\x12$\n" +
 	"\vactivity_id\x18\x02 \x01(\x05H\x00R\n" +
-	"activityId\x88\x01\x01\"B\n" +
+	"activityId\x88\x01\x01\"g\n" +
 	"\x04Type\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
 	"\fMEMO_COMMENT\x10\x01\x12\x12\n" +
-	"\x0eVERSION_UPDATE\x10\x02B\x0e\n" +
+	"\x0eVERSION_UPDATE\x10\x02\x12\x11\n" +
+	"\rMEMO_REACTION\x10\x03\x12\x10\n" +
+	"\fMEMO_MENTION\x10\x04B\x0e\n" +
 	"\f_activity_idB\xa5\x01\n" +
 	"\x12com.wekalist.storeB\n" +
 	"InboxProtoP\x01Z*github.com/imrany/wekalist/proto/gen/store\xa2\x02\x03WSX\xaa\x02\x0eWekalist.Store\xca\x02\x0eWekalist\\Store\xe2\x02\x1aWekalist\\Store\\GPBMetadata\xea\x02\x0fWekalist::Storeb\x06proto3"
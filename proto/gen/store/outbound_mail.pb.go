@@ -0,0 +1,166 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: store/outbound_mail.proto
+
+package store
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OutboundMailMessage is a queued email. The sender is the SMTP account at the time the email is sent.
+type OutboundMailMessage struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	To       []string               `protobuf:"bytes,1,rep,name=to,proto3" json:"to,omitempty"`
+	Subject  string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	TextBody string                 `protobuf:"bytes,3,opt,name=text_body,json=textBody,proto3" json:"text_body,omitempty"`
+	HtmlBody string                 `protobuf:"bytes,4,opt,name=html_body,json=htmlBody,proto3" json:"html_body,omitempty"`
+	// headers are additional headers of the email, such as List-Unsubscribe.
+	Headers       map[string]string `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundMailMessage) Reset() {
+	*x = OutboundMailMessage{}
+	mi := &file_store_outbound_mail_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundMailMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundMailMessage) ProtoMessage() {}
+
+func (x *OutboundMailMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_store_outbound_mail_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundMailMessage.ProtoReflect.Descriptor instead.
+func (*OutboundMailMessage) Descriptor() ([]byte, []int) {
+	return file_store_outbound_mail_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OutboundMailMessage) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *OutboundMailMessage) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *OutboundMailMessage) GetTextBody() string {
+	if x != nil {
+		return x.TextBody
+	}
+	return ""
+}
+
+func (x *OutboundMailMessage) GetHtmlBody() string {
+	if x != nil {
+		return x.HtmlBody
+	}
+	return ""
+}
+
+func (x *OutboundMailMessage) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+var File_store_outbound_mail_proto protoreflect.FileDescriptor
+
+const file_store_outbound_mail_proto_rawDesc = "" +
+	"\n" +
+	"\x19store/outbound_mail.proto\x12\x0ewekalist.store\"\x81\x02\n" +
+	"\x13OutboundMailMessage\x12\x0e\n" +
+	"\x02to\x18\x01 \x03(\tR\x02to\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1b\n" +
+	"\ttext_body\x18\x03 \x01(\tR\btextBody\x12\x1b\n" +
+	"\thtml_body\x18\x04 \x01(\tR\bhtmlBody\x12J\n" +
+	"\aheaders\x18\x05 \x03(\v20.wekalist.store.OutboundMailMessage.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\xac\x01\n" +
+	"\x12com.wekalist.storeB\x11OutboundMailProtoP\x01Z*github.com/imrany/wekalist/proto/gen/store\xa2\x02\x03WSX\xaa\x02\x0eWekalist.Store\xca\x02\x0eWekalist\\Store\xe2\x02\x1aWekalist\\Store\\GPBMetadata\xea\x02\x0fWekalist::Storeb\x06proto3"
+
+var (
+	file_store_outbound_mail_proto_rawDescOnce sync.Once
+	file_store_outbound_mail_proto_rawDescData []byte
+)
+
+func file_store_outbound_mail_proto_rawDescGZIP() []byte {
+	file_store_outbound_mail_proto_rawDescOnce.Do(func() {
+		file_store_outbound_mail_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_store_outbound_mail_proto_rawDesc), len(file_store_outbound_mail_proto_rawDesc)))
+	})
+	return file_store_outbound_mail_proto_rawDescData
+}
+
+var file_store_outbound_mail_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_store_outbound_mail_proto_goTypes = []any{
+	(*OutboundMailMessage)(nil), // 0: wekalist.store.OutboundMailMessage
+	nil,                         // 1: wekalist.store.OutboundMailMessage.HeadersEntry
+}
+var file_store_outbound_mail_proto_depIdxs = []int32{
+	1, // 0: wekalist.store.OutboundMailMessage.headers:type_name -> wekalist.store.OutboundMailMessage.HeadersEntry
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_store_outbound_mail_proto_init() }
+func file_store_outbound_mail_proto_init() {
+	if File_store_outbound_mail_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_store_outbound_mail_proto_rawDesc), len(file_store_outbound_mail_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_store_outbound_mail_proto_goTypes,
+		DependencyIndexes: file_store_outbound_mail_proto_depIdxs,
+		MessageInfos:      file_store_outbound_mail_proto_msgTypes,
+	}.Build()
+	File_store_outbound_mail_proto = out.File
+	file_store_outbound_mail_proto_goTypes = nil
+	file_store_outbound_mail_proto_depIdxs = nil
+}
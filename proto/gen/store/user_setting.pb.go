@@ -36,6 +36,14 @@ const (
 	UserSetting_SHORTCUTS UserSetting_Key = 4
 	// The webhooks of the user.
 	UserSetting_WEBHOOKS UserSetting_Key = 5
+	// The two-factor authentication of the user.
+	UserSetting_TWO_FACTOR UserSetting_Key = 6
+	// The passkeys of the user.
+	UserSetting_PASSKEYS UserSetting_Key = 7
+	// The notification preferences of the user.
+	UserSetting_NOTIFICATIONS UserSetting_Key = 8
+	// The email digest of the user.
+	UserSetting_DIGEST UserSetting_Key = 9
 )
 
 // Enum value maps for UserSetting_Key.
@@ -47,6 +55,10 @@ var (
 		3: "ACCESS_TOKENS",
 		4: "SHORTCUTS",
 		5: "WEBHOOKS",
+		6: "TWO_FACTOR",
+		7: "PASSKEYS",
+		8: "NOTIFICATIONS",
+		9: "DIGEST",
 	}
 	UserSetting_Key_value = map[string]int32{
 		"KEY_UNSPECIFIED": 0,
@@ -55,6 +67,10 @@ var (
 		"ACCESS_TOKENS":   3,
 		"SHORTCUTS":       4,
 		"WEBHOOKS":        5,
+		"TWO_FACTOR":      6,
+		"PASSKEYS":        7,
+		"NOTIFICATIONS":   8,
+		"DIGEST":          9,
 	}
 )
 
@@ -85,6 +101,177 @@ func (UserSetting_Key) EnumDescriptor() ([]byte, []int) {
 	return file_store_user_setting_proto_rawDescGZIP(), []int{0, 0}
 }
 
+// The events users are notified of.
+type NotificationsUserSetting_EventType int32
+
+const (
+	NotificationsUserSetting_EVENT_TYPE_UNSPECIFIED NotificationsUserSetting_EventType = 0
+	// A comment on a memo of the user.
+	NotificationsUserSetting_COMMENT NotificationsUserSetting_EventType = 1
+	// A memo mentioning the user.
+	NotificationsUserSetting_MENTION NotificationsUserSetting_EventType = 2
+	// A reaction to a memo of the user.
+	NotificationsUserSetting_REACTION NotificationsUserSetting_EventType = 3
+	// A reminder of the user. Not sent yet, so not offered as a preference.
+	NotificationsUserSetting_REMINDER NotificationsUserSetting_EventType = 4
+	// A new version is available. Not sent yet, so not offered as a preference.
+	NotificationsUserSetting_VERSION_UPDATE NotificationsUserSetting_EventType = 5
+)
+
+// Enum value maps for NotificationsUserSetting_EventType.
+var (
+	NotificationsUserSetting_EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "COMMENT",
+		2: "MENTION",
+		3: "REACTION",
+		4: "REMINDER",
+		5: "VERSION_UPDATE",
+	}
+	NotificationsUserSetting_EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED": 0,
+		"COMMENT":                1,
+		"MENTION":                2,
+		"REACTION":               3,
+		"REMINDER":               4,
+		"VERSION_UPDATE":         5,
+	}
+)
+
+func (x NotificationsUserSetting_EventType) Enum() *NotificationsUserSetting_EventType {
+	p := new(NotificationsUserSetting_EventType)
+	*p = x
+	return p
+}
+
+func (x NotificationsUserSetting_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationsUserSetting_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_user_setting_proto_enumTypes[1].Descriptor()
+}
+
+func (NotificationsUserSetting_EventType) Type() protoreflect.EnumType {
+	return &file_store_user_setting_proto_enumTypes[1]
+}
+
+func (x NotificationsUserSetting_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationsUserSetting_EventType.Descriptor instead.
+func (NotificationsUserSetting_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{8, 0}
+}
+
+// The channels notifications are delivered through.
+type NotificationsUserSetting_Channel int32
+
+const (
+	NotificationsUserSetting_CHANNEL_UNSPECIFIED NotificationsUserSetting_Channel = 0
+	// The inbox of the user.
+	NotificationsUserSetting_INBOX NotificationsUserSetting_Channel = 1
+	// Web push notifications to the subscribed browsers of the user.
+	NotificationsUserSetting_PUSH NotificationsUserSetting_Channel = 2
+	// Emails sent to the email address of the user.
+	NotificationsUserSetting_EMAIL NotificationsUserSetting_Channel = 3
+)
+
+// Enum value maps for NotificationsUserSetting_Channel.
+var (
+	NotificationsUserSetting_Channel_name = map[int32]string{
+		0: "CHANNEL_UNSPECIFIED",
+		1: "INBOX",
+		2: "PUSH",
+		3: "EMAIL",
+	}
+	NotificationsUserSetting_Channel_value = map[string]int32{
+		"CHANNEL_UNSPECIFIED": 0,
+		"INBOX":               1,
+		"PUSH":                2,
+		"EMAIL":               3,
+	}
+)
+
+func (x NotificationsUserSetting_Channel) Enum() *NotificationsUserSetting_Channel {
+	p := new(NotificationsUserSetting_Channel)
+	*p = x
+	return p
+}
+
+func (x NotificationsUserSetting_Channel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationsUserSetting_Channel) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_user_setting_proto_enumTypes[2].Descriptor()
+}
+
+func (NotificationsUserSetting_Channel) Type() protoreflect.EnumType {
+	return &file_store_user_setting_proto_enumTypes[2]
+}
+
+func (x NotificationsUserSetting_Channel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationsUserSetting_Channel.Descriptor instead.
+func (NotificationsUserSetting_Channel) EnumDescriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{8, 1}
+}
+
+// How often the digest is sent.
+type DigestUserSetting_Frequency int32
+
+const (
+	// No digest is sent.
+	DigestUserSetting_FREQUENCY_UNSPECIFIED DigestUserSetting_Frequency = 0
+	DigestUserSetting_DAILY                 DigestUserSetting_Frequency = 1
+	DigestUserSetting_WEEKLY                DigestUserSetting_Frequency = 2
+)
+
+// Enum value maps for DigestUserSetting_Frequency.
+var (
+	DigestUserSetting_Frequency_name = map[int32]string{
+		0: "FREQUENCY_UNSPECIFIED",
+		1: "DAILY",
+		2: "WEEKLY",
+	}
+	DigestUserSetting_Frequency_value = map[string]int32{
+		"FREQUENCY_UNSPECIFIED": 0,
+		"DAILY":                 1,
+		"WEEKLY":                2,
+	}
+)
+
+func (x DigestUserSetting_Frequency) Enum() *DigestUserSetting_Frequency {
+	p := new(DigestUserSetting_Frequency)
+	*p = x
+	return p
+}
+
+func (x DigestUserSetting_Frequency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DigestUserSetting_Frequency) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_user_setting_proto_enumTypes[3].Descriptor()
+}
+
+func (DigestUserSetting_Frequency) Type() protoreflect.EnumType {
+	return &file_store_user_setting_proto_enumTypes[3]
+}
+
+func (x DigestUserSetting_Frequency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DigestUserSetting_Frequency.Descriptor instead.
+func (DigestUserSetting_Frequency) EnumDescriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{9, 0}
+}
+
 type UserSetting struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	UserId int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -96,6 +283,10 @@ type UserSetting struct {
 	//	*UserSetting_AccessTokens
 	//	*UserSetting_Shortcuts
 	//	*UserSetting_Webhooks
+	//	*UserSetting_TwoFactor
+	//	*UserSetting_Passkeys
+	//	*UserSetting_Notifications
+	//	*UserSetting_Digest
 	Value         isUserSetting_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -197,6 +388,42 @@ func (x *UserSetting) GetWebhooks() *WebhooksUserSetting {
 	return nil
 }
 
+func (x *UserSetting) GetTwoFactor() *TwoFactorUserSetting {
+	if x != nil {
+		if x, ok := x.Value.(*UserSetting_TwoFactor); ok {
+			return x.TwoFactor
+		}
+	}
+	return nil
+}
+
+func (x *UserSetting) GetPasskeys() *PasskeysUserSetting {
+	if x != nil {
+		if x, ok := x.Value.(*UserSetting_Passkeys); ok {
+			return x.Passkeys
+		}
+	}
+	return nil
+}
+
+func (x *UserSetting) GetNotifications() *NotificationsUserSetting {
+	if x != nil {
+		if x, ok := x.Value.(*UserSetting_Notifications); ok {
+			return x.Notifications
+		}
+	}
+	return nil
+}
+
+func (x *UserSetting) GetDigest() *DigestUserSetting {
+	if x != nil {
+		if x, ok := x.Value.(*UserSetting_Digest); ok {
+			return x.Digest
+		}
+	}
+	return nil
+}
+
 type isUserSetting_Value interface {
 	isUserSetting_Value()
 }
@@ -221,6 +448,22 @@ type UserSetting_Webhooks struct {
 	Webhooks *WebhooksUserSetting `protobuf:"bytes,7,opt,name=webhooks,proto3,oneof"`
 }
 
+type UserSetting_TwoFactor struct {
+	TwoFactor *TwoFactorUserSetting `protobuf:"bytes,8,opt,name=two_factor,json=twoFactor,proto3,oneof"`
+}
+
+type UserSetting_Passkeys struct {
+	Passkeys *PasskeysUserSetting `protobuf:"bytes,9,opt,name=passkeys,proto3,oneof"`
+}
+
+type UserSetting_Notifications struct {
+	Notifications *NotificationsUserSetting `protobuf:"bytes,10,opt,name=notifications,proto3,oneof"`
+}
+
+type UserSetting_Digest struct {
+	Digest *DigestUserSetting `protobuf:"bytes,11,opt,name=digest,proto3,oneof"`
+}
+
 func (*UserSetting_General) isUserSetting_Value() {}
 
 func (*UserSetting_Sessions) isUserSetting_Value() {}
@@ -231,6 +474,14 @@ func (*UserSetting_Shortcuts) isUserSetting_Value() {}
 
 func (*UserSetting_Webhooks) isUserSetting_Value() {}
 
+func (*UserSetting_TwoFactor) isUserSetting_Value() {}
+
+func (*UserSetting_Passkeys) isUserSetting_Value() {}
+
+func (*UserSetting_Notifications) isUserSetting_Value() {}
+
+func (*UserSetting_Digest) isUserSetting_Value() {}
+
 type GeneralUserSetting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The user's locale.
@@ -340,6 +591,8 @@ func (x *GeneralUserSetting) GetWrapperMaxUsage() int32 {
 	return 0
 }
 
+// Deprecated: sessions are stored in the user_session table.
+// ClientInfo is still the client information stored with a session.
 type SessionsUserSetting struct {
 	state         protoimpl.MessageState         `protogen:"open.v1"`
 	Sessions      []*SessionsUserSetting_Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
@@ -516,6 +769,220 @@ func (x *WebhooksUserSetting) GetWebhooks() []*WebhooksUserSetting_Webhook {
 	return nil
 }
 
+type TwoFactorUserSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The base32 encoded TOTP secret.
+	TotpSecret string `protobuf:"bytes,1,opt,name=totp_secret,json=totpSecret,proto3" json:"totp_secret,omitempty"`
+	// Whether TOTP is enabled. Set once the enrolment is confirmed with a valid code.
+	Enabled bool `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// SHA-256 hashes of the unused recovery codes.
+	RecoveryCodeHashes []string `protobuf:"bytes,3,rep,name=recovery_code_hashes,json=recoveryCodeHashes,proto3" json:"recovery_code_hashes,omitempty"`
+	// The time step of the last accepted TOTP code, used to reject replayed codes.
+	LastUsedStep  int64 `protobuf:"varint,4,opt,name=last_used_step,json=lastUsedStep,proto3" json:"last_used_step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TwoFactorUserSetting) Reset() {
+	*x = TwoFactorUserSetting{}
+	mi := &file_store_user_setting_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TwoFactorUserSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TwoFactorUserSetting) ProtoMessage() {}
+
+func (x *TwoFactorUserSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TwoFactorUserSetting.ProtoReflect.Descriptor instead.
+func (*TwoFactorUserSetting) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TwoFactorUserSetting) GetTotpSecret() string {
+	if x != nil {
+		return x.TotpSecret
+	}
+	return ""
+}
+
+func (x *TwoFactorUserSetting) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *TwoFactorUserSetting) GetRecoveryCodeHashes() []string {
+	if x != nil {
+		return x.RecoveryCodeHashes
+	}
+	return nil
+}
+
+func (x *TwoFactorUserSetting) GetLastUsedStep() int64 {
+	if x != nil {
+		return x.LastUsedStep
+	}
+	return 0
+}
+
+type PasskeysUserSetting struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Passkeys      []*PasskeysUserSetting_Passkey `protobuf:"bytes,1,rep,name=passkeys,proto3" json:"passkeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasskeysUserSetting) Reset() {
+	*x = PasskeysUserSetting{}
+	mi := &file_store_user_setting_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasskeysUserSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasskeysUserSetting) ProtoMessage() {}
+
+func (x *PasskeysUserSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasskeysUserSetting.ProtoReflect.Descriptor instead.
+func (*PasskeysUserSetting) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PasskeysUserSetting) GetPasskeys() []*PasskeysUserSetting_Passkey {
+	if x != nil {
+		return x.Passkeys
+	}
+	return nil
+}
+
+type NotificationsUserSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The preferences set by the user. Channels without a preference use the default.
+	Preferences   []*NotificationsUserSetting_Preference `protobuf:"bytes,1,rep,name=preferences,proto3" json:"preferences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationsUserSetting) Reset() {
+	*x = NotificationsUserSetting{}
+	mi := &file_store_user_setting_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationsUserSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationsUserSetting) ProtoMessage() {}
+
+func (x *NotificationsUserSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationsUserSetting.ProtoReflect.Descriptor instead.
+func (*NotificationsUserSetting) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NotificationsUserSetting) GetPreferences() []*NotificationsUserSetting_Preference {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type DigestUserSetting struct {
+	state     protoimpl.MessageState      `protogen:"open.v1"`
+	Frequency DigestUserSetting_Frequency `protobuf:"varint,1,opt,name=frequency,proto3,enum=wekalist.store.DigestUserSetting_Frequency" json:"frequency,omitempty"`
+	// The time the previous digest was sent, the next digest lists what happened since.
+	LastSentTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_sent_time,json=lastSentTime,proto3" json:"last_sent_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DigestUserSetting) Reset() {
+	*x = DigestUserSetting{}
+	mi := &file_store_user_setting_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DigestUserSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DigestUserSetting) ProtoMessage() {}
+
+func (x *DigestUserSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DigestUserSetting.ProtoReflect.Descriptor instead.
+func (*DigestUserSetting) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DigestUserSetting) GetFrequency() DigestUserSetting_Frequency {
+	if x != nil {
+		return x.Frequency
+	}
+	return DigestUserSetting_FREQUENCY_UNSPECIFIED
+}
+
+func (x *DigestUserSetting) GetLastSentTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSentTime
+	}
+	return nil
+}
+
 type SessionsUserSetting_Session struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Unique session identifier.
@@ -533,7 +1000,7 @@ type SessionsUserSetting_Session struct {
 
 func (x *SessionsUserSetting_Session) Reset() {
 	*x = SessionsUserSetting_Session{}
-	mi := &file_store_user_setting_proto_msgTypes[6]
+	mi := &file_store_user_setting_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -545,7 +1012,7 @@ func (x *SessionsUserSetting_Session) String() string {
 func (*SessionsUserSetting_Session) ProtoMessage() {}
 
 func (x *SessionsUserSetting_Session) ProtoReflect() protoreflect.Message {
-	mi := &file_store_user_setting_proto_msgTypes[6]
+	mi := &file_store_user_setting_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,7 +1074,7 @@ type SessionsUserSetting_ClientInfo struct {
 
 func (x *SessionsUserSetting_ClientInfo) Reset() {
 	*x = SessionsUserSetting_ClientInfo{}
-	mi := &file_store_user_setting_proto_msgTypes[7]
+	mi := &file_store_user_setting_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -619,7 +1086,7 @@ func (x *SessionsUserSetting_ClientInfo) String() string {
 func (*SessionsUserSetting_ClientInfo) ProtoMessage() {}
 
 func (x *SessionsUserSetting_ClientInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_store_user_setting_proto_msgTypes[7]
+	mi := &file_store_user_setting_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,18 +1139,28 @@ func (x *SessionsUserSetting_ClientInfo) GetBrowser() string {
 
 type AccessTokensUserSetting_AccessToken struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The access token is a JWT token.
-	// Including expiration time, issuer, etc.
+	// Deprecated: tokens are only stored as token_hash.
+	// Set on tokens created before hashing, which are migrated when read.
 	AccessToken string `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
 	// A description for the access token.
-	Description   string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// The ID of the access token, used in its resource name.
+	Id string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// The hex encoded SHA-256 hash of the access token, a JWT token.
+	TokenHash string `protobuf:"bytes,4,opt,name=token_hash,json=tokenHash,proto3" json:"token_hash,omitempty"`
+	// The scopes granted to the access token, such as memos:read.
+	Scopes     []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	IssuedTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=issued_time,json=issuedTime,proto3" json:"issued_time,omitempty"`
+	// Unset for access tokens that never expire.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	LastUsedTime  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_used_time,json=lastUsedTime,proto3" json:"last_used_time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AccessTokensUserSetting_AccessToken) Reset() {
 	*x = AccessTokensUserSetting_AccessToken{}
-	mi := &file_store_user_setting_proto_msgTypes[8]
+	mi := &file_store_user_setting_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +1172,7 @@ func (x *AccessTokensUserSetting_AccessToken) String() string {
 func (*AccessTokensUserSetting_AccessToken) ProtoMessage() {}
 
 func (x *AccessTokensUserSetting_AccessToken) ProtoReflect() protoreflect.Message {
-	mi := &file_store_user_setting_proto_msgTypes[8]
+	mi := &file_store_user_setting_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -725,6 +1202,48 @@ func (x *AccessTokensUserSetting_AccessToken) GetDescription() string {
 	return ""
 }
 
+func (x *AccessTokensUserSetting_AccessToken) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AccessTokensUserSetting_AccessToken) GetTokenHash() string {
+	if x != nil {
+		return x.TokenHash
+	}
+	return ""
+}
+
+func (x *AccessTokensUserSetting_AccessToken) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AccessTokensUserSetting_AccessToken) GetIssuedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedTime
+	}
+	return nil
+}
+
+func (x *AccessTokensUserSetting_AccessToken) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+func (x *AccessTokensUserSetting_AccessToken) GetLastUsedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedTime
+	}
+	return nil
+}
+
 type ShortcutsUserSetting_Shortcut struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -736,7 +1255,7 @@ type ShortcutsUserSetting_Shortcut struct {
 
 func (x *ShortcutsUserSetting_Shortcut) Reset() {
 	*x = ShortcutsUserSetting_Shortcut{}
-	mi := &file_store_user_setting_proto_msgTypes[9]
+	mi := &file_store_user_setting_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -748,7 +1267,7 @@ func (x *ShortcutsUserSetting_Shortcut) String() string {
 func (*ShortcutsUserSetting_Shortcut) ProtoMessage() {}
 
 func (x *ShortcutsUserSetting_Shortcut) ProtoReflect() protoreflect.Message {
-	mi := &file_store_user_setting_proto_msgTypes[9]
+	mi := &file_store_user_setting_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,7 +1318,7 @@ type WebhooksUserSetting_Webhook struct {
 
 func (x *WebhooksUserSetting_Webhook) Reset() {
 	*x = WebhooksUserSetting_Webhook{}
-	mi := &file_store_user_setting_proto_msgTypes[10]
+	mi := &file_store_user_setting_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -811,7 +1330,7 @@ func (x *WebhooksUserSetting_Webhook) String() string {
 func (*WebhooksUserSetting_Webhook) ProtoMessage() {}
 
 func (x *WebhooksUserSetting_Webhook) ProtoReflect() protoreflect.Message {
-	mi := &file_store_user_setting_proto_msgTypes[10]
+	mi := &file_store_user_setting_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,11 +1367,197 @@ func (x *WebhooksUserSetting_Webhook) GetUrl() string {
 	return ""
 }
 
+type PasskeysUserSetting_Passkey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The credential ID, base64url encoded without padding.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// A user-facing name for the passkey.
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// The COSE encoded public key of the credential.
+	PublicKey []byte `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// The attestation format used when creating the credential.
+	AttestationType string `protobuf:"bytes,4,opt,name=attestation_type,json=attestationType,proto3" json:"attestation_type,omitempty"`
+	// The transports supported by the authenticator.
+	Transports []string `protobuf:"bytes,5,rep,name=transports,proto3" json:"transports,omitempty"`
+	// The authenticator data flags reported at registration.
+	Flags uint32 `protobuf:"varint,6,opt,name=flags,proto3" json:"flags,omitempty"`
+	// The AAGUID of the authenticator model.
+	Aaguid []byte `protobuf:"bytes,7,opt,name=aaguid,proto3" json:"aaguid,omitempty"`
+	// The last signature counter reported by the authenticator.
+	SignCount uint32 `protobuf:"varint,8,opt,name=sign_count,json=signCount,proto3" json:"sign_count,omitempty"`
+	// Timestamp when the passkey was registered.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Timestamp when the passkey was last used to sign in.
+	LastUsedTime  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_used_time,json=lastUsedTime,proto3" json:"last_used_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasskeysUserSetting_Passkey) Reset() {
+	*x = PasskeysUserSetting_Passkey{}
+	mi := &file_store_user_setting_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasskeysUserSetting_Passkey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasskeysUserSetting_Passkey) ProtoMessage() {}
+
+func (x *PasskeysUserSetting_Passkey) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasskeysUserSetting_Passkey.ProtoReflect.Descriptor instead.
+func (*PasskeysUserSetting_Passkey) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *PasskeysUserSetting_Passkey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PasskeysUserSetting_Passkey) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *PasskeysUserSetting_Passkey) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *PasskeysUserSetting_Passkey) GetAttestationType() string {
+	if x != nil {
+		return x.AttestationType
+	}
+	return ""
+}
+
+func (x *PasskeysUserSetting_Passkey) GetTransports() []string {
+	if x != nil {
+		return x.Transports
+	}
+	return nil
+}
+
+func (x *PasskeysUserSetting_Passkey) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *PasskeysUserSetting_Passkey) GetAaguid() []byte {
+	if x != nil {
+		return x.Aaguid
+	}
+	return nil
+}
+
+func (x *PasskeysUserSetting_Passkey) GetSignCount() uint32 {
+	if x != nil {
+		return x.SignCount
+	}
+	return 0
+}
+
+func (x *PasskeysUserSetting_Passkey) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *PasskeysUserSetting_Passkey) GetLastUsedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedTime
+	}
+	return nil
+}
+
+type NotificationsUserSetting_Preference struct {
+	state         protoimpl.MessageState             `protogen:"open.v1"`
+	EventType     NotificationsUserSetting_EventType `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=wekalist.store.NotificationsUserSetting_EventType" json:"event_type,omitempty"`
+	Channel       NotificationsUserSetting_Channel   `protobuf:"varint,2,opt,name=channel,proto3,enum=wekalist.store.NotificationsUserSetting_Channel" json:"channel,omitempty"`
+	Enabled       bool                               `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationsUserSetting_Preference) Reset() {
+	*x = NotificationsUserSetting_Preference{}
+	mi := &file_store_user_setting_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationsUserSetting_Preference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationsUserSetting_Preference) ProtoMessage() {}
+
+func (x *NotificationsUserSetting_Preference) ProtoReflect() protoreflect.Message {
+	mi := &file_store_user_setting_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationsUserSetting_Preference.ProtoReflect.Descriptor instead.
+func (*NotificationsUserSetting_Preference) Descriptor() ([]byte, []int) {
+	return file_store_user_setting_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *NotificationsUserSetting_Preference) GetEventType() NotificationsUserSetting_EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return NotificationsUserSetting_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *NotificationsUserSetting_Preference) GetChannel() NotificationsUserSetting_Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return NotificationsUserSetting_CHANNEL_UNSPECIFIED
+}
+
+func (x *NotificationsUserSetting_Preference) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
 var File_store_user_setting_proto protoreflect.FileDescriptor
 
 const file_store_user_setting_proto_rawDesc = "" +
 	"\n" +
-	"\x18store/user_setting.proto\x12\x0ewekalist.store\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa5\x04\n" +
+	"\x18store/user_setting.proto\x12\x0ewekalist.store\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfc\x06\n" +
 	"\vUserSetting\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x121\n" +
 	"\x03key\x18\x02 \x01(\x0e2\x1f.wekalist.store.UserSetting.KeyR\x03key\x12>\n" +
@@ -860,14 +1565,26 @@ const file_store_user_setting_proto_rawDesc = "" +
 	"\bsessions\x18\x04 \x01(\v2#.wekalist.store.SessionsUserSettingH\x00R\bsessions\x12N\n" +
 	"\raccess_tokens\x18\x05 \x01(\v2'.wekalist.store.AccessTokensUserSettingH\x00R\faccessTokens\x12D\n" +
 	"\tshortcuts\x18\x06 \x01(\v2$.wekalist.store.ShortcutsUserSettingH\x00R\tshortcuts\x12A\n" +
-	"\bwebhooks\x18\a \x01(\v2#.wekalist.store.WebhooksUserSettingH\x00R\bwebhooks\"e\n" +
+	"\bwebhooks\x18\a \x01(\v2#.wekalist.store.WebhooksUserSettingH\x00R\bwebhooks\x12E\n" +
+	"\n" +
+	"two_factor\x18\b \x01(\v2$.wekalist.store.TwoFactorUserSettingH\x00R\ttwoFactor\x12A\n" +
+	"\bpasskeys\x18\t \x01(\v2#.wekalist.store.PasskeysUserSettingH\x00R\bpasskeys\x12P\n" +
+	"\rnotifications\x18\n" +
+	" \x01(\v2(.wekalist.store.NotificationsUserSettingH\x00R\rnotifications\x12;\n" +
+	"\x06digest\x18\v \x01(\v2!.wekalist.store.DigestUserSettingH\x00R\x06digest\"\xa2\x01\n" +
 	"\x03Key\x12\x13\n" +
 	"\x0fKEY_UNSPECIFIED\x10\x00\x12\v\n" +
 	"\aGENERAL\x10\x01\x12\f\n" +
 	"\bSESSIONS\x10\x02\x12\x11\n" +
 	"\rACCESS_TOKENS\x10\x03\x12\r\n" +
 	"\tSHORTCUTS\x10\x04\x12\f\n" +
-	"\bWEBHOOKS\x10\x05B\a\n" +
+	"\bWEBHOOKS\x10\x05\x12\x0e\n" +
+	"\n" +
+	"TWO_FACTOR\x10\x06\x12\f\n" +
+	"\bPASSKEYS\x10\a\x12\x11\n" +
+	"\rNOTIFICATIONS\x10\b\x12\n" +
+	"\n" +
+	"\x06DIGEST\x10\tB\a\n" +
 	"\x05value\"\xc6\x02\n" +
 	"\x12GeneralUserSetting\x12\x16\n" +
 	"\x06locale\x18\x01 \x01(\tR\x06locale\x12\x1e\n" +
@@ -899,12 +1616,21 @@ const file_store_user_setting_proto_rawDesc = "" +
 	"\vdevice_type\x18\x03 \x01(\tR\n" +
 	"deviceType\x12\x0e\n" +
 	"\x02os\x18\x04 \x01(\tR\x02os\x12\x18\n" +
-	"\abrowser\x18\x05 \x01(\tR\abrowser\"\xc7\x01\n" +
+	"\abrowser\x18\x05 \x01(\tR\abrowser\"\xcb\x03\n" +
 	"\x17AccessTokensUserSetting\x12X\n" +
-	"\raccess_tokens\x18\x01 \x03(\v23.wekalist.store.AccessTokensUserSetting.AccessTokenR\faccessTokens\x1aR\n" +
+	"\raccess_tokens\x18\x01 \x03(\v23.wekalist.store.AccessTokensUserSetting.AccessTokenR\faccessTokens\x1a\xd5\x02\n" +
 	"\vAccessToken\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"\xad\x01\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"token_hash\x18\x04 \x01(\tR\ttokenHash\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12;\n" +
+	"\vissued_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"issuedTime\x12;\n" +
+	"\vexpire_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expireTime\x12@\n" +
+	"\x0elast_used_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\flastUsedTime\"\xad\x01\n" +
 	"\x14ShortcutsUserSetting\x12K\n" +
 	"\tshortcuts\x18\x01 \x03(\v2-.wekalist.store.ShortcutsUserSetting.ShortcutR\tshortcuts\x1aH\n" +
 	"\bShortcut\x12\x0e\n" +
@@ -916,7 +1642,60 @@ const file_store_user_setting_proto_rawDesc = "" +
 	"\aWebhook\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x10\n" +
-	"\x03url\x18\x03 \x01(\tR\x03urlB\xab\x01\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\"\xa9\x01\n" +
+	"\x14TwoFactorUserSetting\x12\x1f\n" +
+	"\vtotp_secret\x18\x01 \x01(\tR\n" +
+	"totpSecret\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x120\n" +
+	"\x14recovery_code_hashes\x18\x03 \x03(\tR\x12recoveryCodeHashes\x12$\n" +
+	"\x0elast_used_step\x18\x04 \x01(\x03R\flastUsedStep\"\xd3\x03\n" +
+	"\x13PasskeysUserSetting\x12G\n" +
+	"\bpasskeys\x18\x01 \x03(\v2+.wekalist.store.PasskeysUserSetting.PasskeyR\bpasskeys\x1a\xf2\x02\n" +
+	"\aPasskey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey\x12)\n" +
+	"\x10attestation_type\x18\x04 \x01(\tR\x0fattestationType\x12\x1e\n" +
+	"\n" +
+	"transports\x18\x05 \x03(\tR\n" +
+	"transports\x12\x14\n" +
+	"\x05flags\x18\x06 \x01(\rR\x05flags\x12\x16\n" +
+	"\x06aaguid\x18\a \x01(\fR\x06aaguid\x12\x1d\n" +
+	"\n" +
+	"sign_count\x18\b \x01(\rR\tsignCount\x12;\n" +
+	"\vcreate_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12@\n" +
+	"\x0elast_used_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\flastUsedTime\"\xf0\x03\n" +
+	"\x18NotificationsUserSetting\x12U\n" +
+	"\vpreferences\x18\x01 \x03(\v23.wekalist.store.NotificationsUserSetting.PreferenceR\vpreferences\x1a\xc5\x01\n" +
+	"\n" +
+	"Preference\x12Q\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\x0e22.wekalist.store.NotificationsUserSetting.EventTypeR\teventType\x12J\n" +
+	"\achannel\x18\x02 \x01(\x0e20.wekalist.store.NotificationsUserSetting.ChannelR\achannel\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\"q\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCOMMENT\x10\x01\x12\v\n" +
+	"\aMENTION\x10\x02\x12\f\n" +
+	"\bREACTION\x10\x03\x12\f\n" +
+	"\bREMINDER\x10\x04\x12\x12\n" +
+	"\x0eVERSION_UPDATE\x10\x05\"B\n" +
+	"\aChannel\x12\x17\n" +
+	"\x13CHANNEL_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05INBOX\x10\x01\x12\b\n" +
+	"\x04PUSH\x10\x02\x12\t\n" +
+	"\x05EMAIL\x10\x03\"\xdf\x01\n" +
+	"\x11DigestUserSetting\x12I\n" +
+	"\tfrequency\x18\x01 \x01(\x0e2+.wekalist.store.DigestUserSetting.FrequencyR\tfrequency\x12@\n" +
+	"\x0elast_sent_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\flastSentTime\"=\n" +
+	"\tFrequency\x12\x19\n" +
+	"\x15FREQUENCY_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\n" +
+	"\n" +
+	"\x06WEEKLY\x10\x02B\xab\x01\n" +
 	"\x12com.wekalist.storeB\x10UserSettingProtoP\x01Z*github.com/imrany/wekalist/proto/gen/store\xa2\x02\x03WSX\xaa\x02\x0eWekalist.Store\xca\x02\x0eWekalist\\Store\xe2\x02\x1aWekalist\\Store\\GPBMetadata\xea\x02\x0fWekalist::Storeb\x06proto3"
 
 var (
@@ -931,42 +1710,66 @@ func file_store_user_setting_proto_rawDescGZIP() []byte {
 	return file_store_user_setting_proto_rawDescData
 }
 
-var file_store_user_setting_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_store_user_setting_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_store_user_setting_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_store_user_setting_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_store_user_setting_proto_goTypes = []any{
 	(UserSetting_Key)(0),                        // 0: wekalist.store.UserSetting.Key
-	(*UserSetting)(nil),                         // 1: wekalist.store.UserSetting
-	(*GeneralUserSetting)(nil),                  // 2: wekalist.store.GeneralUserSetting
-	(*SessionsUserSetting)(nil),                 // 3: wekalist.store.SessionsUserSetting
-	(*AccessTokensUserSetting)(nil),             // 4: wekalist.store.AccessTokensUserSetting
-	(*ShortcutsUserSetting)(nil),                // 5: wekalist.store.ShortcutsUserSetting
-	(*WebhooksUserSetting)(nil),                 // 6: wekalist.store.WebhooksUserSetting
-	(*SessionsUserSetting_Session)(nil),         // 7: wekalist.store.SessionsUserSetting.Session
-	(*SessionsUserSetting_ClientInfo)(nil),      // 8: wekalist.store.SessionsUserSetting.ClientInfo
-	(*AccessTokensUserSetting_AccessToken)(nil), // 9: wekalist.store.AccessTokensUserSetting.AccessToken
-	(*ShortcutsUserSetting_Shortcut)(nil),       // 10: wekalist.store.ShortcutsUserSetting.Shortcut
-	(*WebhooksUserSetting_Webhook)(nil),         // 11: wekalist.store.WebhooksUserSetting.Webhook
-	(*timestamppb.Timestamp)(nil),               // 12: google.protobuf.Timestamp
+	(NotificationsUserSetting_EventType)(0),     // 1: wekalist.store.NotificationsUserSetting.EventType
+	(NotificationsUserSetting_Channel)(0),       // 2: wekalist.store.NotificationsUserSetting.Channel
+	(DigestUserSetting_Frequency)(0),            // 3: wekalist.store.DigestUserSetting.Frequency
+	(*UserSetting)(nil),                         // 4: wekalist.store.UserSetting
+	(*GeneralUserSetting)(nil),                  // 5: wekalist.store.GeneralUserSetting
+	(*SessionsUserSetting)(nil),                 // 6: wekalist.store.SessionsUserSetting
+	(*AccessTokensUserSetting)(nil),             // 7: wekalist.store.AccessTokensUserSetting
+	(*ShortcutsUserSetting)(nil),                // 8: wekalist.store.ShortcutsUserSetting
+	(*WebhooksUserSetting)(nil),                 // 9: wekalist.store.WebhooksUserSetting
+	(*TwoFactorUserSetting)(nil),                // 10: wekalist.store.TwoFactorUserSetting
+	(*PasskeysUserSetting)(nil),                 // 11: wekalist.store.PasskeysUserSetting
+	(*NotificationsUserSetting)(nil),            // 12: wekalist.store.NotificationsUserSetting
+	(*DigestUserSetting)(nil),                   // 13: wekalist.store.DigestUserSetting
+	(*SessionsUserSetting_Session)(nil),         // 14: wekalist.store.SessionsUserSetting.Session
+	(*SessionsUserSetting_ClientInfo)(nil),      // 15: wekalist.store.SessionsUserSetting.ClientInfo
+	(*AccessTokensUserSetting_AccessToken)(nil), // 16: wekalist.store.AccessTokensUserSetting.AccessToken
+	(*ShortcutsUserSetting_Shortcut)(nil),       // 17: wekalist.store.ShortcutsUserSetting.Shortcut
+	(*WebhooksUserSetting_Webhook)(nil),         // 18: wekalist.store.WebhooksUserSetting.Webhook
+	(*PasskeysUserSetting_Passkey)(nil),         // 19: wekalist.store.PasskeysUserSetting.Passkey
+	(*NotificationsUserSetting_Preference)(nil), // 20: wekalist.store.NotificationsUserSetting.Preference
+	(*timestamppb.Timestamp)(nil),               // 21: google.protobuf.Timestamp
 }
 var file_store_user_setting_proto_depIdxs = []int32{
 	0,  // 0: wekalist.store.UserSetting.key:type_name -> wekalist.store.UserSetting.Key
-	2,  // 1: wekalist.store.UserSetting.general:type_name -> wekalist.store.GeneralUserSetting
-	3,  // 2: wekalist.store.UserSetting.sessions:type_name -> wekalist.store.SessionsUserSetting
-	4,  // 3: wekalist.store.UserSetting.access_tokens:type_name -> wekalist.store.AccessTokensUserSetting
-	5,  // 4: wekalist.store.UserSetting.shortcuts:type_name -> wekalist.store.ShortcutsUserSetting
-	6,  // 5: wekalist.store.UserSetting.webhooks:type_name -> wekalist.store.WebhooksUserSetting
-	7,  // 6: wekalist.store.SessionsUserSetting.sessions:type_name -> wekalist.store.SessionsUserSetting.Session
-	9,  // 7: wekalist.store.AccessTokensUserSetting.access_tokens:type_name -> wekalist.store.AccessTokensUserSetting.AccessToken
-	10, // 8: wekalist.store.ShortcutsUserSetting.shortcuts:type_name -> wekalist.store.ShortcutsUserSetting.Shortcut
-	11, // 9: wekalist.store.WebhooksUserSetting.webhooks:type_name -> wekalist.store.WebhooksUserSetting.Webhook
-	12, // 10: wekalist.store.SessionsUserSetting.Session.create_time:type_name -> google.protobuf.Timestamp
-	12, // 11: wekalist.store.SessionsUserSetting.Session.last_accessed_time:type_name -> google.protobuf.Timestamp
-	8,  // 12: wekalist.store.SessionsUserSetting.Session.client_info:type_name -> wekalist.store.SessionsUserSetting.ClientInfo
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	5,  // 1: wekalist.store.UserSetting.general:type_name -> wekalist.store.GeneralUserSetting
+	6,  // 2: wekalist.store.UserSetting.sessions:type_name -> wekalist.store.SessionsUserSetting
+	7,  // 3: wekalist.store.UserSetting.access_tokens:type_name -> wekalist.store.AccessTokensUserSetting
+	8,  // 4: wekalist.store.UserSetting.shortcuts:type_name -> wekalist.store.ShortcutsUserSetting
+	9,  // 5: wekalist.store.UserSetting.webhooks:type_name -> wekalist.store.WebhooksUserSetting
+	10, // 6: wekalist.store.UserSetting.two_factor:type_name -> wekalist.store.TwoFactorUserSetting
+	11, // 7: wekalist.store.UserSetting.passkeys:type_name -> wekalist.store.PasskeysUserSetting
+	12, // 8: wekalist.store.UserSetting.notifications:type_name -> wekalist.store.NotificationsUserSetting
+	13, // 9: wekalist.store.UserSetting.digest:type_name -> wekalist.store.DigestUserSetting
+	14, // 10: wekalist.store.SessionsUserSetting.sessions:type_name -> wekalist.store.SessionsUserSetting.Session
+	16, // 11: wekalist.store.AccessTokensUserSetting.access_tokens:type_name -> wekalist.store.AccessTokensUserSetting.AccessToken
+	17, // 12: wekalist.store.ShortcutsUserSetting.shortcuts:type_name -> wekalist.store.ShortcutsUserSetting.Shortcut
+	18, // 13: wekalist.store.WebhooksUserSetting.webhooks:type_name -> wekalist.store.WebhooksUserSetting.Webhook
+	19, // 14: wekalist.store.PasskeysUserSetting.passkeys:type_name -> wekalist.store.PasskeysUserSetting.Passkey
+	20, // 15: wekalist.store.NotificationsUserSetting.preferences:type_name -> wekalist.store.NotificationsUserSetting.Preference
+	3,  // 16: wekalist.store.DigestUserSetting.frequency:type_name -> wekalist.store.DigestUserSetting.Frequency
+	21, // 17: wekalist.store.DigestUserSetting.last_sent_time:type_name -> google.protobuf.Timestamp
+	21, // 18: wekalist.store.SessionsUserSetting.Session.create_time:type_name -> google.protobuf.Timestamp
+	21, // 19: wekalist.store.SessionsUserSetting.Session.last_accessed_time:type_name -> google.protobuf.Timestamp
+	15, // 20: wekalist.store.SessionsUserSetting.Session.client_info:type_name -> wekalist.store.SessionsUserSetting.ClientInfo
+	21, // 21: wekalist.store.AccessTokensUserSetting.AccessToken.issued_time:type_name -> google.protobuf.Timestamp
+	21, // 22: wekalist.store.AccessTokensUserSetting.AccessToken.expire_time:type_name -> google.protobuf.Timestamp
+	21, // 23: wekalist.store.AccessTokensUserSetting.AccessToken.last_used_time:type_name -> google.protobuf.Timestamp
+	21, // 24: wekalist.store.PasskeysUserSetting.Passkey.create_time:type_name -> google.protobuf.Timestamp
+	21, // 25: wekalist.store.PasskeysUserSetting.Passkey.last_used_time:type_name -> google.protobuf.Timestamp
+	1,  // 26: wekalist.store.NotificationsUserSetting.Preference.event_type:type_name -> wekalist.store.NotificationsUserSetting.EventType
+	2,  // 27: wekalist.store.NotificationsUserSetting.Preference.channel:type_name -> wekalist.store.NotificationsUserSetting.Channel
+	28, // [28:28] is the sub-list for method output_type
+	28, // [28:28] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_store_user_setting_proto_init() }
@@ -980,14 +1783,18 @@ func file_store_user_setting_proto_init() {
 		(*UserSetting_AccessTokens)(nil),
 		(*UserSetting_Shortcuts)(nil),
 		(*UserSetting_Webhooks)(nil),
+		(*UserSetting_TwoFactor)(nil),
+		(*UserSetting_Passkeys)(nil),
+		(*UserSetting_Notifications)(nil),
+		(*UserSetting_Digest)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_store_user_setting_proto_rawDesc), len(file_store_user_setting_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      4,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -9,6 +9,7 @@ package store
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -33,6 +34,10 @@ const (
 	WorkspaceSettingKey_STORAGE WorkspaceSettingKey = 3
 	// MEMO_RELATED is the key for memo related settings.
 	WorkspaceSettingKey_MEMO_RELATED WorkspaceSettingKey = 4
+	// AI is the key for AI provider settings.
+	WorkspaceSettingKey_AI WorkspaceSettingKey = 5
+	// EMAIL_TEMPLATES is the key for the email templates replacing the built-in ones.
+	WorkspaceSettingKey_EMAIL_TEMPLATES WorkspaceSettingKey = 6
 )
 
 // Enum value maps for WorkspaceSettingKey.
@@ -43,6 +48,8 @@ var (
 		2: "GENERAL",
 		3: "STORAGE",
 		4: "MEMO_RELATED",
+		5: "AI",
+		6: "EMAIL_TEMPLATES",
 	}
 	WorkspaceSettingKey_value = map[string]int32{
 		"WORKSPACE_SETTING_KEY_UNSPECIFIED": 0,
@@ -50,6 +57,8 @@ var (
 		"GENERAL":                           2,
 		"STORAGE":                           3,
 		"MEMO_RELATED":                      4,
+		"AI":                                5,
+		"EMAIL_TEMPLATES":                   6,
 	}
 )
 
@@ -80,6 +89,63 @@ func (WorkspaceSettingKey) EnumDescriptor() ([]byte, []int) {
 	return file_store_workspace_setting_proto_rawDescGZIP(), []int{0}
 }
 
+type WorkspaceGeneralSetting_SmtpSecurity int32
+
+const (
+	// SMTP_SECURITY_UNSPECIFIED upgrades the connection with STARTTLS.
+	WorkspaceGeneralSetting_SMTP_SECURITY_UNSPECIFIED WorkspaceGeneralSetting_SmtpSecurity = 0
+	// STARTTLS upgrades the connection with STARTTLS, usually on port 587.
+	WorkspaceGeneralSetting_STARTTLS WorkspaceGeneralSetting_SmtpSecurity = 1
+	// TLS connects over TLS, usually on port 465.
+	WorkspaceGeneralSetting_TLS WorkspaceGeneralSetting_SmtpSecurity = 2
+	// PLAIN does not encrypt the connection, for relays on a trusted network.
+	// Credentials are then only sent to localhost.
+	WorkspaceGeneralSetting_PLAIN WorkspaceGeneralSetting_SmtpSecurity = 3
+)
+
+// Enum value maps for WorkspaceGeneralSetting_SmtpSecurity.
+var (
+	WorkspaceGeneralSetting_SmtpSecurity_name = map[int32]string{
+		0: "SMTP_SECURITY_UNSPECIFIED",
+		1: "STARTTLS",
+		2: "TLS",
+		3: "PLAIN",
+	}
+	WorkspaceGeneralSetting_SmtpSecurity_value = map[string]int32{
+		"SMTP_SECURITY_UNSPECIFIED": 0,
+		"STARTTLS":                  1,
+		"TLS":                       2,
+		"PLAIN":                     3,
+	}
+)
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) Enum() *WorkspaceGeneralSetting_SmtpSecurity {
+	p := new(WorkspaceGeneralSetting_SmtpSecurity)
+	*p = x
+	return p
+}
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceGeneralSetting_SmtpSecurity) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_workspace_setting_proto_enumTypes[1].Descriptor()
+}
+
+func (WorkspaceGeneralSetting_SmtpSecurity) Type() protoreflect.EnumType {
+	return &file_store_workspace_setting_proto_enumTypes[1]
+}
+
+func (x WorkspaceGeneralSetting_SmtpSecurity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceGeneralSetting_SmtpSecurity.Descriptor instead.
+func (WorkspaceGeneralSetting_SmtpSecurity) EnumDescriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type WorkspaceStorageSetting_StorageType int32
 
 const (
@@ -119,11 +185,11 @@ func (x WorkspaceStorageSetting_StorageType) String() string {
 }
 
 func (WorkspaceStorageSetting_StorageType) Descriptor() protoreflect.EnumDescriptor {
-	return file_store_workspace_setting_proto_enumTypes[1].Descriptor()
+	return file_store_workspace_setting_proto_enumTypes[2].Descriptor()
 }
 
 func (WorkspaceStorageSetting_StorageType) Type() protoreflect.EnumType {
-	return &file_store_workspace_setting_proto_enumTypes[1]
+	return &file_store_workspace_setting_proto_enumTypes[2]
 }
 
 func (x WorkspaceStorageSetting_StorageType) Number() protoreflect.EnumNumber {
@@ -132,7 +198,113 @@ func (x WorkspaceStorageSetting_StorageType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WorkspaceStorageSetting_StorageType.Descriptor instead.
 func (WorkspaceStorageSetting_StorageType) EnumDescriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{4, 0}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{6, 0}
+}
+
+type WorkspaceAISetting_Provider int32
+
+const (
+	WorkspaceAISetting_PROVIDER_UNSPECIFIED WorkspaceAISetting_Provider = 0
+	// WRAPPER is the hosted gRPC wrapper service.
+	WorkspaceAISetting_WRAPPER WorkspaceAISetting_Provider = 1
+	// OPENAI is any OpenAI-compatible HTTP API.
+	WorkspaceAISetting_OPENAI WorkspaceAISetting_Provider = 2
+	// OLLAMA is an Ollama-style local endpoint.
+	WorkspaceAISetting_OLLAMA WorkspaceAISetting_Provider = 3
+)
+
+// Enum value maps for WorkspaceAISetting_Provider.
+var (
+	WorkspaceAISetting_Provider_name = map[int32]string{
+		0: "PROVIDER_UNSPECIFIED",
+		1: "WRAPPER",
+		2: "OPENAI",
+		3: "OLLAMA",
+	}
+	WorkspaceAISetting_Provider_value = map[string]int32{
+		"PROVIDER_UNSPECIFIED": 0,
+		"WRAPPER":              1,
+		"OPENAI":               2,
+		"OLLAMA":               3,
+	}
+)
+
+func (x WorkspaceAISetting_Provider) Enum() *WorkspaceAISetting_Provider {
+	p := new(WorkspaceAISetting_Provider)
+	*p = x
+	return p
+}
+
+func (x WorkspaceAISetting_Provider) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceAISetting_Provider) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_workspace_setting_proto_enumTypes[3].Descriptor()
+}
+
+func (WorkspaceAISetting_Provider) Type() protoreflect.EnumType {
+	return &file_store_workspace_setting_proto_enumTypes[3]
+}
+
+func (x WorkspaceAISetting_Provider) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Provider.Descriptor instead.
+func (WorkspaceAISetting_Provider) EnumDescriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type WorkspaceAISetting_Quota_Window int32
+
+const (
+	WorkspaceAISetting_Quota_WINDOW_UNSPECIFIED WorkspaceAISetting_Quota_Window = 0
+	// DAILY resets at midnight UTC.
+	WorkspaceAISetting_Quota_DAILY WorkspaceAISetting_Quota_Window = 1
+	// MONTHLY resets at midnight UTC on the first day of the month.
+	WorkspaceAISetting_Quota_MONTHLY WorkspaceAISetting_Quota_Window = 2
+)
+
+// Enum value maps for WorkspaceAISetting_Quota_Window.
+var (
+	WorkspaceAISetting_Quota_Window_name = map[int32]string{
+		0: "WINDOW_UNSPECIFIED",
+		1: "DAILY",
+		2: "MONTHLY",
+	}
+	WorkspaceAISetting_Quota_Window_value = map[string]int32{
+		"WINDOW_UNSPECIFIED": 0,
+		"DAILY":              1,
+		"MONTHLY":            2,
+	}
+)
+
+func (x WorkspaceAISetting_Quota_Window) Enum() *WorkspaceAISetting_Quota_Window {
+	p := new(WorkspaceAISetting_Quota_Window)
+	*p = x
+	return p
+}
+
+func (x WorkspaceAISetting_Quota_Window) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceAISetting_Quota_Window) Descriptor() protoreflect.EnumDescriptor {
+	return file_store_workspace_setting_proto_enumTypes[4].Descriptor()
+}
+
+func (WorkspaceAISetting_Quota_Window) Type() protoreflect.EnumType {
+	return &file_store_workspace_setting_proto_enumTypes[4]
+}
+
+func (x WorkspaceAISetting_Quota_Window) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Quota_Window.Descriptor instead.
+func (WorkspaceAISetting_Quota_Window) EnumDescriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{9, 0, 0}
 }
 
 type WorkspaceSetting struct {
@@ -144,6 +316,8 @@ type WorkspaceSetting struct {
 	//	*WorkspaceSetting_GeneralSetting
 	//	*WorkspaceSetting_StorageSetting
 	//	*WorkspaceSetting_MemoRelatedSetting
+	//	*WorkspaceSetting_AiSetting
+	//	*WorkspaceSetting_EmailTemplatesSetting
 	Value         isWorkspaceSetting_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -229,6 +403,24 @@ func (x *WorkspaceSetting) GetMemoRelatedSetting() *WorkspaceMemoRelatedSetting
 	return nil
 }
 
+func (x *WorkspaceSetting) GetAiSetting() *WorkspaceAISetting {
+	if x != nil {
+		if x, ok := x.Value.(*WorkspaceSetting_AiSetting); ok {
+			return x.AiSetting
+		}
+	}
+	return nil
+}
+
+func (x *WorkspaceSetting) GetEmailTemplatesSetting() *WorkspaceEmailTemplatesSetting {
+	if x != nil {
+		if x, ok := x.Value.(*WorkspaceSetting_EmailTemplatesSetting); ok {
+			return x.EmailTemplatesSetting
+		}
+	}
+	return nil
+}
+
 type isWorkspaceSetting_Value interface {
 	isWorkspaceSetting_Value()
 }
@@ -249,6 +441,14 @@ type WorkspaceSetting_MemoRelatedSetting struct {
 	MemoRelatedSetting *WorkspaceMemoRelatedSetting `protobuf:"bytes,5,opt,name=memo_related_setting,json=memoRelatedSetting,proto3,oneof"`
 }
 
+type WorkspaceSetting_AiSetting struct {
+	AiSetting *WorkspaceAISetting `protobuf:"bytes,6,opt,name=ai_setting,json=aiSetting,proto3,oneof"`
+}
+
+type WorkspaceSetting_EmailTemplatesSetting struct {
+	EmailTemplatesSetting *WorkspaceEmailTemplatesSetting `protobuf:"bytes,7,opt,name=email_templates_setting,json=emailTemplatesSetting,proto3,oneof"`
+}
+
 func (*WorkspaceSetting_BasicSetting) isWorkspaceSetting_Value() {}
 
 func (*WorkspaceSetting_GeneralSetting) isWorkspaceSetting_Value() {}
@@ -257,14 +457,21 @@ func (*WorkspaceSetting_StorageSetting) isWorkspaceSetting_Value() {}
 
 func (*WorkspaceSetting_MemoRelatedSetting) isWorkspaceSetting_Value() {}
 
+func (*WorkspaceSetting_AiSetting) isWorkspaceSetting_Value() {}
+
+func (*WorkspaceSetting_EmailTemplatesSetting) isWorkspaceSetting_Value() {}
+
 type WorkspaceBasicSetting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The secret key for workspace. Mainly used for session management.
 	SecretKey string `protobuf:"bytes,1,opt,name=secret_key,json=secretKey,proto3" json:"secret_key,omitempty"`
 	// The current schema version of database.
 	SchemaVersion string `protobuf:"bytes,2,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// The keys JWTs are signed with, the active key first. Seeded on startup,
+	// JWTs are signed with the server secret under the key ID "v1" while it is empty.
+	JwtSigningKeys []*JWTSigningKey `protobuf:"bytes,3,rep,name=jwt_signing_keys,json=jwtSigningKeys,proto3" json:"jwt_signing_keys,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *WorkspaceBasicSetting) Reset() {
@@ -311,6 +518,84 @@ func (x *WorkspaceBasicSetting) GetSchemaVersion() string {
 	return ""
 }
 
+func (x *WorkspaceBasicSetting) GetJwtSigningKeys() []*JWTSigningKey {
+	if x != nil {
+		return x.JwtSigningKeys
+	}
+	return nil
+}
+
+type JWTSigningKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The key ID, set as kid in the JWT headers.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The HMAC secret.
+	Secret     string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Unset for the active key. JWTs signed by a retired key validate until then.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JWTSigningKey) Reset() {
+	*x = JWTSigningKey{}
+	mi := &file_store_workspace_setting_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JWTSigningKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JWTSigningKey) ProtoMessage() {}
+
+func (x *JWTSigningKey) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JWTSigningKey.ProtoReflect.Descriptor instead.
+func (*JWTSigningKey) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *JWTSigningKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JWTSigningKey) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *JWTSigningKey) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *JWTSigningKey) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
 type WorkspaceGeneralSetting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// theme is the name of the selected theme.
@@ -346,13 +631,25 @@ type WorkspaceGeneralSetting struct {
 	SmtpAccountEmail string `protobuf:"bytes,14,opt,name=smtp_account_email,json=smtpAccountEmail,proto3" json:"smtp_account_email,omitempty"`
 	// smtp_account_password is the host's sender email address password
 	SmtpAccountPassword string `protobuf:"bytes,15,opt,name=smtp_account_password,json=smtpAccountPassword,proto3" json:"smtp_account_password,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// require_two_factor requires all users to enable two-factor authentication.
+	RequireTwoFactor bool `protobuf:"varint,16,opt,name=require_two_factor,json=requireTwoFactor,proto3" json:"require_two_factor,omitempty"`
+	// rate_limit limits sign-in, OTP and registration requests.
+	RateLimit *WorkspaceRateLimit `protobuf:"bytes,17,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// smtp_security is how the connection to the SMTP server is secured.
+	SmtpSecurity WorkspaceGeneralSetting_SmtpSecurity `protobuf:"varint,18,opt,name=smtp_security,json=smtpSecurity,proto3,enum=wekalist.store.WorkspaceGeneralSetting_SmtpSecurity" json:"smtp_security,omitempty"`
+	// dkim_domain is the domain signing the emails with DKIM. Emails are not signed if empty.
+	DkimDomain string `protobuf:"bytes,19,opt,name=dkim_domain,json=dkimDomain,proto3" json:"dkim_domain,omitempty"`
+	// dkim_selector is the selector of the DKIM public key, published at {selector}._domainkey.{domain}.
+	DkimSelector string `protobuf:"bytes,20,opt,name=dkim_selector,json=dkimSelector,proto3" json:"dkim_selector,omitempty"`
+	// dkim_private_key is the PEM encoded RSA or Ed25519 private key signing the emails.
+	DkimPrivateKey string `protobuf:"bytes,21,opt,name=dkim_private_key,json=dkimPrivateKey,proto3" json:"dkim_private_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *WorkspaceGeneralSetting) Reset() {
 	*x = WorkspaceGeneralSetting{}
-	mi := &file_store_workspace_setting_proto_msgTypes[2]
+	mi := &file_store_workspace_setting_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -364,7 +661,7 @@ func (x *WorkspaceGeneralSetting) String() string {
 func (*WorkspaceGeneralSetting) ProtoMessage() {}
 
 func (x *WorkspaceGeneralSetting) ProtoReflect() protoreflect.Message {
-	mi := &file_store_workspace_setting_proto_msgTypes[2]
+	mi := &file_store_workspace_setting_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,7 +674,7 @@ func (x *WorkspaceGeneralSetting) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceGeneralSetting.ProtoReflect.Descriptor instead.
 func (*WorkspaceGeneralSetting) Descriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{2}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *WorkspaceGeneralSetting) GetTheme() string {
@@ -485,6 +782,135 @@ func (x *WorkspaceGeneralSetting) GetSmtpAccountPassword() string {
 	return ""
 }
 
+func (x *WorkspaceGeneralSetting) GetRequireTwoFactor() bool {
+	if x != nil {
+		return x.RequireTwoFactor
+	}
+	return false
+}
+
+func (x *WorkspaceGeneralSetting) GetRateLimit() *WorkspaceRateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *WorkspaceGeneralSetting) GetSmtpSecurity() WorkspaceGeneralSetting_SmtpSecurity {
+	if x != nil {
+		return x.SmtpSecurity
+	}
+	return WorkspaceGeneralSetting_SMTP_SECURITY_UNSPECIFIED
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimDomain() string {
+	if x != nil {
+		return x.DkimDomain
+	}
+	return ""
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimSelector() string {
+	if x != nil {
+		return x.DkimSelector
+	}
+	return ""
+}
+
+func (x *WorkspaceGeneralSetting) GetDkimPrivateKey() string {
+	if x != nil {
+		return x.DkimPrivateKey
+	}
+	return ""
+}
+
+// WorkspaceRateLimit limits how often sign-in, OTP and registration requests can be made,
+// per client IP and per account. Zero values use the defaults.
+type WorkspaceRateLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// disabled turns rate limiting off.
+	Disabled bool `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// ip_requests_per_minute is the number of requests a client IP can make per minute and method.
+	// Default is 20.
+	IpRequestsPerMinute int32 `protobuf:"varint,2,opt,name=ip_requests_per_minute,json=ipRequestsPerMinute,proto3" json:"ip_requests_per_minute,omitempty"`
+	// account_requests_per_minute is the number of requests per minute and method for an account.
+	// Default is 5.
+	AccountRequestsPerMinute int32 `protobuf:"varint,3,opt,name=account_requests_per_minute,json=accountRequestsPerMinute,proto3" json:"account_requests_per_minute,omitempty"`
+	// lockout_threshold is the number of consecutive failed sign-ins locking an account.
+	// Default is 5.
+	LockoutThreshold int32 `protobuf:"varint,4,opt,name=lockout_threshold,json=lockoutThreshold,proto3" json:"lockout_threshold,omitempty"`
+	// lockout_seconds is how long a locked account cannot sign in.
+	// Default is 900.
+	LockoutSeconds int32 `protobuf:"varint,5,opt,name=lockout_seconds,json=lockoutSeconds,proto3" json:"lockout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WorkspaceRateLimit) Reset() {
+	*x = WorkspaceRateLimit{}
+	mi := &file_store_workspace_setting_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceRateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceRateLimit) ProtoMessage() {}
+
+func (x *WorkspaceRateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceRateLimit.ProtoReflect.Descriptor instead.
+func (*WorkspaceRateLimit) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WorkspaceRateLimit) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *WorkspaceRateLimit) GetIpRequestsPerMinute() int32 {
+	if x != nil {
+		return x.IpRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetAccountRequestsPerMinute() int32 {
+	if x != nil {
+		return x.AccountRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetLockoutThreshold() int32 {
+	if x != nil {
+		return x.LockoutThreshold
+	}
+	return 0
+}
+
+func (x *WorkspaceRateLimit) GetLockoutSeconds() int32 {
+	if x != nil {
+		return x.LockoutSeconds
+	}
+	return 0
+}
+
 type WorkspaceCustomProfile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -498,7 +924,7 @@ type WorkspaceCustomProfile struct {
 
 func (x *WorkspaceCustomProfile) Reset() {
 	*x = WorkspaceCustomProfile{}
-	mi := &file_store_workspace_setting_proto_msgTypes[3]
+	mi := &file_store_workspace_setting_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -510,7 +936,7 @@ func (x *WorkspaceCustomProfile) String() string {
 func (*WorkspaceCustomProfile) ProtoMessage() {}
 
 func (x *WorkspaceCustomProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_store_workspace_setting_proto_msgTypes[3]
+	mi := &file_store_workspace_setting_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -523,7 +949,7 @@ func (x *WorkspaceCustomProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceCustomProfile.ProtoReflect.Descriptor instead.
 func (*WorkspaceCustomProfile) Descriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{3}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *WorkspaceCustomProfile) GetTitle() string {
@@ -578,7 +1004,7 @@ type WorkspaceStorageSetting struct {
 
 func (x *WorkspaceStorageSetting) Reset() {
 	*x = WorkspaceStorageSetting{}
-	mi := &file_store_workspace_setting_proto_msgTypes[4]
+	mi := &file_store_workspace_setting_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -590,7 +1016,7 @@ func (x *WorkspaceStorageSetting) String() string {
 func (*WorkspaceStorageSetting) ProtoMessage() {}
 
 func (x *WorkspaceStorageSetting) ProtoReflect() protoreflect.Message {
-	mi := &file_store_workspace_setting_proto_msgTypes[4]
+	mi := &file_store_workspace_setting_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,7 +1029,7 @@ func (x *WorkspaceStorageSetting) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceStorageSetting.ProtoReflect.Descriptor instead.
 func (*WorkspaceStorageSetting) Descriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{4}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *WorkspaceStorageSetting) GetStorageType() WorkspaceStorageSetting_StorageType {
@@ -649,7 +1075,7 @@ type StorageS3Config struct {
 
 func (x *StorageS3Config) Reset() {
 	*x = StorageS3Config{}
-	mi := &file_store_workspace_setting_proto_msgTypes[5]
+	mi := &file_store_workspace_setting_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -661,7 +1087,7 @@ func (x *StorageS3Config) String() string {
 func (*StorageS3Config) ProtoMessage() {}
 
 func (x *StorageS3Config) ProtoReflect() protoreflect.Message {
-	mi := &file_store_workspace_setting_proto_msgTypes[5]
+	mi := &file_store_workspace_setting_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -674,7 +1100,7 @@ func (x *StorageS3Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StorageS3Config.ProtoReflect.Descriptor instead.
 func (*StorageS3Config) Descriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{5}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StorageS3Config) GetAccessKeyId() string {
@@ -743,7 +1169,7 @@ type WorkspaceMemoRelatedSetting struct {
 
 func (x *WorkspaceMemoRelatedSetting) Reset() {
 	*x = WorkspaceMemoRelatedSetting{}
-	mi := &file_store_workspace_setting_proto_msgTypes[6]
+	mi := &file_store_workspace_setting_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -755,7 +1181,7 @@ func (x *WorkspaceMemoRelatedSetting) String() string {
 func (*WorkspaceMemoRelatedSetting) ProtoMessage() {}
 
 func (x *WorkspaceMemoRelatedSetting) ProtoReflect() protoreflect.Message {
-	mi := &file_store_workspace_setting_proto_msgTypes[6]
+	mi := &file_store_workspace_setting_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -768,7 +1194,7 @@ func (x *WorkspaceMemoRelatedSetting) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkspaceMemoRelatedSetting.ProtoReflect.Descriptor instead.
 func (*WorkspaceMemoRelatedSetting) Descriptor() ([]byte, []int) {
-	return file_store_workspace_setting_proto_rawDescGZIP(), []int{6}
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *WorkspaceMemoRelatedSetting) GetDisallowPublicVisibility() bool {
@@ -827,22 +1253,325 @@ func (x *WorkspaceMemoRelatedSetting) GetNsfwTags() []string {
 	return nil
 }
 
+type WorkspaceAISetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// provider is the AI provider used for generation.
+	Provider WorkspaceAISetting_Provider `protobuf:"varint,1,opt,name=provider,proto3,enum=wekalist.store.WorkspaceAISetting_Provider" json:"provider,omitempty"`
+	// base_url is the endpoint of the provider.
+	// Leave empty to use the provider's default endpoint.
+	BaseUrl string `protobuf:"bytes,2,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// model is the name of the model to use.
+	Model string `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	// api_key is the credential sent to the provider.
+	ApiKey string `protobuf:"bytes,4,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// embedding_model is the name of the model used to compute memo embeddings.
+	EmbeddingModel string `protobuf:"bytes,5,opt,name=embedding_model,json=embeddingModel,proto3" json:"embedding_model,omitempty"`
+	// user_quota limits the AI usage of each user.
+	UserQuota *WorkspaceAISetting_Quota `protobuf:"bytes,6,opt,name=user_quota,json=userQuota,proto3" json:"user_quota,omitempty"`
+	// workspace_quota limits the AI usage of the whole workspace.
+	WorkspaceQuota *WorkspaceAISetting_Quota `protobuf:"bytes,7,opt,name=workspace_quota,json=workspaceQuota,proto3" json:"workspace_quota,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WorkspaceAISetting) Reset() {
+	*x = WorkspaceAISetting{}
+	mi := &file_store_workspace_setting_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceAISetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceAISetting) ProtoMessage() {}
+
+func (x *WorkspaceAISetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceAISetting.ProtoReflect.Descriptor instead.
+func (*WorkspaceAISetting) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WorkspaceAISetting) GetProvider() WorkspaceAISetting_Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return WorkspaceAISetting_PROVIDER_UNSPECIFIED
+}
+
+func (x *WorkspaceAISetting) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *WorkspaceAISetting) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *WorkspaceAISetting) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *WorkspaceAISetting) GetEmbeddingModel() string {
+	if x != nil {
+		return x.EmbeddingModel
+	}
+	return ""
+}
+
+func (x *WorkspaceAISetting) GetUserQuota() *WorkspaceAISetting_Quota {
+	if x != nil {
+		return x.UserQuota
+	}
+	return nil
+}
+
+func (x *WorkspaceAISetting) GetWorkspaceQuota() *WorkspaceAISetting_Quota {
+	if x != nil {
+		return x.WorkspaceQuota
+	}
+	return nil
+}
+
+type WorkspaceEmailTemplatesSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// templates replace the built-in email templates of the same name and locale.
+	Templates     []*EmailTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceEmailTemplatesSetting) Reset() {
+	*x = WorkspaceEmailTemplatesSetting{}
+	mi := &file_store_workspace_setting_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceEmailTemplatesSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceEmailTemplatesSetting) ProtoMessage() {}
+
+func (x *WorkspaceEmailTemplatesSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceEmailTemplatesSetting.ProtoReflect.Descriptor instead.
+func (*WorkspaceEmailTemplatesSetting) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WorkspaceEmailTemplatesSetting) GetTemplates() []*EmailTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+type EmailTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the name of the built-in template replaced, such as "otp", "notification" or "digest".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// locale is the locale of the recipients the template is used for, such as "fr" or "pt-BR".
+	// Empty for the default locale, used when there is no template for the locale of the recipient.
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	// subject is the text/template of the subject.
+	Subject string `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	// text_body is the text/template of the plain text body.
+	TextBody string `protobuf:"bytes,4,opt,name=text_body,json=textBody,proto3" json:"text_body,omitempty"`
+	// html_body is the html/template of the HTML body.
+	HtmlBody      string `protobuf:"bytes,5,opt,name=html_body,json=htmlBody,proto3" json:"html_body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplate) Reset() {
+	*x = EmailTemplate{}
+	mi := &file_store_workspace_setting_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplate) ProtoMessage() {}
+
+func (x *EmailTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplate.ProtoReflect.Descriptor instead.
+func (*EmailTemplate) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EmailTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetTextBody() string {
+	if x != nil {
+		return x.TextBody
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetHtmlBody() string {
+	if x != nil {
+		return x.HtmlBody
+	}
+	return ""
+}
+
+// Quota limits AI usage within a time window.
+// A limit of 0 means unlimited.
+type WorkspaceAISetting_Quota struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// window is the period the limits apply to. Defaults to DAILY.
+	Window WorkspaceAISetting_Quota_Window `protobuf:"varint,1,opt,name=window,proto3,enum=wekalist.store.WorkspaceAISetting_Quota_Window" json:"window,omitempty"`
+	// max_requests is the maximum number of AI calls in the window.
+	MaxRequests int64 `protobuf:"varint,2,opt,name=max_requests,json=maxRequests,proto3" json:"max_requests,omitempty"`
+	// max_tokens is the maximum number of tokens consumed in the window.
+	MaxTokens     int64 `protobuf:"varint,3,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceAISetting_Quota) Reset() {
+	*x = WorkspaceAISetting_Quota{}
+	mi := &file_store_workspace_setting_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceAISetting_Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceAISetting_Quota) ProtoMessage() {}
+
+func (x *WorkspaceAISetting_Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_store_workspace_setting_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceAISetting_Quota.ProtoReflect.Descriptor instead.
+func (*WorkspaceAISetting_Quota) Descriptor() ([]byte, []int) {
+	return file_store_workspace_setting_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *WorkspaceAISetting_Quota) GetWindow() WorkspaceAISetting_Quota_Window {
+	if x != nil {
+		return x.Window
+	}
+	return WorkspaceAISetting_Quota_WINDOW_UNSPECIFIED
+}
+
+func (x *WorkspaceAISetting_Quota) GetMaxRequests() int64 {
+	if x != nil {
+		return x.MaxRequests
+	}
+	return 0
+}
+
+func (x *WorkspaceAISetting_Quota) GetMaxTokens() int64 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
 var File_store_workspace_setting_proto protoreflect.FileDescriptor
 
 const file_store_workspace_setting_proto_rawDesc = "" +
 	"\n" +
-	"\x1dstore/workspace_setting.proto\x12\x0ewekalist.store\"\xa9\x03\n" +
+	"\x1dstore/workspace_setting.proto\x12\x0ewekalist.store\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd8\x04\n" +
 	"\x10WorkspaceSetting\x125\n" +
 	"\x03key\x18\x01 \x01(\x0e2#.wekalist.store.WorkspaceSettingKeyR\x03key\x12L\n" +
 	"\rbasic_setting\x18\x02 \x01(\v2%.wekalist.store.WorkspaceBasicSettingH\x00R\fbasicSetting\x12R\n" +
 	"\x0fgeneral_setting\x18\x03 \x01(\v2'.wekalist.store.WorkspaceGeneralSettingH\x00R\x0egeneralSetting\x12R\n" +
 	"\x0fstorage_setting\x18\x04 \x01(\v2'.wekalist.store.WorkspaceStorageSettingH\x00R\x0estorageSetting\x12_\n" +
-	"\x14memo_related_setting\x18\x05 \x01(\v2+.wekalist.store.WorkspaceMemoRelatedSettingH\x00R\x12memoRelatedSettingB\a\n" +
-	"\x05value\"]\n" +
+	"\x14memo_related_setting\x18\x05 \x01(\v2+.wekalist.store.WorkspaceMemoRelatedSettingH\x00R\x12memoRelatedSetting\x12C\n" +
+	"\n" +
+	"ai_setting\x18\x06 \x01(\v2\".wekalist.store.WorkspaceAISettingH\x00R\taiSetting\x12h\n" +
+	"\x17email_templates_setting\x18\a \x01(\v2..wekalist.store.WorkspaceEmailTemplatesSettingH\x00R\x15emailTemplatesSettingB\a\n" +
+	"\x05value\"\xa6\x01\n" +
 	"\x15WorkspaceBasicSetting\x12\x1d\n" +
 	"\n" +
 	"secret_key\x18\x01 \x01(\tR\tsecretKey\x12%\n" +
-	"\x0eschema_version\x18\x02 \x01(\tR\rschemaVersion\"\xfd\x05\n" +
+	"\x0eschema_version\x18\x02 \x01(\tR\rschemaVersion\x12G\n" +
+	"\x10jwt_signing_keys\x18\x03 \x03(\v2\x1d.wekalist.store.JWTSigningKeyR\x0ejwtSigningKeys\"\xb1\x01\n" +
+	"\rJWTSigningKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12;\n" +
+	"\vcreate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vexpire_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expireTime\"\x8a\t\n" +
 	"\x17WorkspaceGeneralSetting\x12\x14\n" +
 	"\x05theme\x18\x01 \x01(\tR\x05theme\x12<\n" +
 	"\x1adisallow_user_registration\x18\x02 \x01(\bR\x18disallowUserRegistration\x124\n" +
@@ -859,7 +1588,26 @@ const file_store_workspace_setting_proto_rawDesc = "" +
 	"\tsmtp_port\x18\f \x01(\x05R\bsmtpPort\x122\n" +
 	"\x15smtp_account_username\x18\r \x01(\tR\x13smtpAccountUsername\x12,\n" +
 	"\x12smtp_account_email\x18\x0e \x01(\tR\x10smtpAccountEmail\x122\n" +
-	"\x15smtp_account_password\x18\x0f \x01(\tR\x13smtpAccountPassword\"\xa3\x01\n" +
+	"\x15smtp_account_password\x18\x0f \x01(\tR\x13smtpAccountPassword\x12,\n" +
+	"\x12require_two_factor\x18\x10 \x01(\bR\x10requireTwoFactor\x12A\n" +
+	"\n" +
+	"rate_limit\x18\x11 \x01(\v2\".wekalist.store.WorkspaceRateLimitR\trateLimit\x12Y\n" +
+	"\rsmtp_security\x18\x12 \x01(\x0e24.wekalist.store.WorkspaceGeneralSetting.SmtpSecurityR\fsmtpSecurity\x12\x1f\n" +
+	"\vdkim_domain\x18\x13 \x01(\tR\n" +
+	"dkimDomain\x12#\n" +
+	"\rdkim_selector\x18\x14 \x01(\tR\fdkimSelector\x12(\n" +
+	"\x10dkim_private_key\x18\x15 \x01(\tR\x0edkimPrivateKey\"O\n" +
+	"\fSmtpSecurity\x12\x1d\n" +
+	"\x19SMTP_SECURITY_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSTARTTLS\x10\x01\x12\a\n" +
+	"\x03TLS\x10\x02\x12\t\n" +
+	"\x05PLAIN\x10\x03\"\xfa\x01\n" +
+	"\x12WorkspaceRateLimit\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x123\n" +
+	"\x16ip_requests_per_minute\x18\x02 \x01(\x05R\x13ipRequestsPerMinute\x12=\n" +
+	"\x1baccount_requests_per_minute\x18\x03 \x01(\x05R\x18accountRequestsPerMinute\x12+\n" +
+	"\x11lockout_threshold\x18\x04 \x01(\x05R\x10lockoutThreshold\x12'\n" +
+	"\x0flockout_seconds\x18\x05 \x01(\x05R\x0elockoutSeconds\"\xa3\x01\n" +
 	"\x16WorkspaceCustomProfile\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x19\n" +
@@ -893,13 +1641,48 @@ const file_store_workspace_setting_proto_rawDesc = "" +
 	"\x13enable_link_preview\x18\x05 \x01(\bR\x11enableLinkPreview\x12\x1c\n" +
 	"\treactions\x18\a \x03(\tR\treactions\x127\n" +
 	"\x18enable_blur_nsfw_content\x18\b \x01(\bR\x15enableBlurNsfwContent\x12\x1b\n" +
-	"\tnsfw_tags\x18\t \x03(\tR\bnsfwTags*s\n" +
+	"\tnsfw_tags\x18\t \x03(\tR\bnsfwTags\"\x86\x05\n" +
+	"\x12WorkspaceAISetting\x12G\n" +
+	"\bprovider\x18\x01 \x01(\x0e2+.wekalist.store.WorkspaceAISetting.ProviderR\bprovider\x12\x19\n" +
+	"\bbase_url\x18\x02 \x01(\tR\abaseUrl\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x17\n" +
+	"\aapi_key\x18\x04 \x01(\tR\x06apiKey\x12'\n" +
+	"\x0fembedding_model\x18\x05 \x01(\tR\x0eembeddingModel\x12G\n" +
+	"\n" +
+	"user_quota\x18\x06 \x01(\v2(.wekalist.store.WorkspaceAISetting.QuotaR\tuserQuota\x12Q\n" +
+	"\x0fworkspace_quota\x18\a \x01(\v2(.wekalist.store.WorkspaceAISetting.QuotaR\x0eworkspaceQuota\x1a\xcc\x01\n" +
+	"\x05Quota\x12G\n" +
+	"\x06window\x18\x01 \x01(\x0e2/.wekalist.store.WorkspaceAISetting.Quota.WindowR\x06window\x12!\n" +
+	"\fmax_requests\x18\x02 \x01(\x03R\vmaxRequests\x12\x1d\n" +
+	"\n" +
+	"max_tokens\x18\x03 \x01(\x03R\tmaxTokens\"8\n" +
+	"\x06Window\x12\x16\n" +
+	"\x12WINDOW_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\v\n" +
+	"\aMONTHLY\x10\x02\"I\n" +
+	"\bProvider\x12\x18\n" +
+	"\x14PROVIDER_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aWRAPPER\x10\x01\x12\n" +
+	"\n" +
+	"\x06OPENAI\x10\x02\x12\n" +
+	"\n" +
+	"\x06OLLAMA\x10\x03\"]\n" +
+	"\x1eWorkspaceEmailTemplatesSetting\x12;\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x1d.wekalist.store.EmailTemplateR\ttemplates\"\x8f\x01\n" +
+	"\rEmailTemplate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x1b\n" +
+	"\ttext_body\x18\x04 \x01(\tR\btextBody\x12\x1b\n" +
+	"\thtml_body\x18\x05 \x01(\tR\bhtmlBody*\x90\x01\n" +
 	"\x13WorkspaceSettingKey\x12%\n" +
 	"!WORKSPACE_SETTING_KEY_UNSPECIFIED\x10\x00\x12\t\n" +
 	"\x05BASIC\x10\x01\x12\v\n" +
 	"\aGENERAL\x10\x02\x12\v\n" +
 	"\aSTORAGE\x10\x03\x12\x10\n" +
-	"\fMEMO_RELATED\x10\x04B\xb0\x01\n" +
+	"\fMEMO_RELATED\x10\x04\x12\x06\n" +
+	"\x02AI\x10\x05\x12\x13\n" +
+	"\x0fEMAIL_TEMPLATES\x10\x06B\xb0\x01\n" +
 	"\x12com.wekalist.storeB\x15WorkspaceSettingProtoP\x01Z*github.com/imrany/wekalist/proto/gen/store\xa2\x02\x03WSX\xaa\x02\x0eWekalist.Store\xca\x02\x0eWekalist\\Store\xe2\x02\x1aWekalist\\Store\\GPBMetadata\xea\x02\x0fWekalist::Storeb\x06proto3"
 
 var (
@@ -914,33 +1697,55 @@ func file_store_workspace_setting_proto_rawDescGZIP() []byte {
 	return file_store_workspace_setting_proto_rawDescData
 }
 
-var file_store_workspace_setting_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_store_workspace_setting_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_store_workspace_setting_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_store_workspace_setting_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_store_workspace_setting_proto_goTypes = []any{
-	(WorkspaceSettingKey)(0),                 // 0: wekalist.store.WorkspaceSettingKey
-	(WorkspaceStorageSetting_StorageType)(0), // 1: wekalist.store.WorkspaceStorageSetting.StorageType
-	(*WorkspaceSetting)(nil),                 // 2: wekalist.store.WorkspaceSetting
-	(*WorkspaceBasicSetting)(nil),            // 3: wekalist.store.WorkspaceBasicSetting
-	(*WorkspaceGeneralSetting)(nil),          // 4: wekalist.store.WorkspaceGeneralSetting
-	(*WorkspaceCustomProfile)(nil),           // 5: wekalist.store.WorkspaceCustomProfile
-	(*WorkspaceStorageSetting)(nil),          // 6: wekalist.store.WorkspaceStorageSetting
-	(*StorageS3Config)(nil),                  // 7: wekalist.store.StorageS3Config
-	(*WorkspaceMemoRelatedSetting)(nil),      // 8: wekalist.store.WorkspaceMemoRelatedSetting
+	(WorkspaceSettingKey)(0),                  // 0: wekalist.store.WorkspaceSettingKey
+	(WorkspaceGeneralSetting_SmtpSecurity)(0), // 1: wekalist.store.WorkspaceGeneralSetting.SmtpSecurity
+	(WorkspaceStorageSetting_StorageType)(0),  // 2: wekalist.store.WorkspaceStorageSetting.StorageType
+	(WorkspaceAISetting_Provider)(0),          // 3: wekalist.store.WorkspaceAISetting.Provider
+	(WorkspaceAISetting_Quota_Window)(0),      // 4: wekalist.store.WorkspaceAISetting.Quota.Window
+	(*WorkspaceSetting)(nil),                  // 5: wekalist.store.WorkspaceSetting
+	(*WorkspaceBasicSetting)(nil),             // 6: wekalist.store.WorkspaceBasicSetting
+	(*JWTSigningKey)(nil),                     // 7: wekalist.store.JWTSigningKey
+	(*WorkspaceGeneralSetting)(nil),           // 8: wekalist.store.WorkspaceGeneralSetting
+	(*WorkspaceRateLimit)(nil),                // 9: wekalist.store.WorkspaceRateLimit
+	(*WorkspaceCustomProfile)(nil),            // 10: wekalist.store.WorkspaceCustomProfile
+	(*WorkspaceStorageSetting)(nil),           // 11: wekalist.store.WorkspaceStorageSetting
+	(*StorageS3Config)(nil),                   // 12: wekalist.store.StorageS3Config
+	(*WorkspaceMemoRelatedSetting)(nil),       // 13: wekalist.store.WorkspaceMemoRelatedSetting
+	(*WorkspaceAISetting)(nil),                // 14: wekalist.store.WorkspaceAISetting
+	(*WorkspaceEmailTemplatesSetting)(nil),    // 15: wekalist.store.WorkspaceEmailTemplatesSetting
+	(*EmailTemplate)(nil),                     // 16: wekalist.store.EmailTemplate
+	(*WorkspaceAISetting_Quota)(nil),          // 17: wekalist.store.WorkspaceAISetting.Quota
+	(*timestamppb.Timestamp)(nil),             // 18: google.protobuf.Timestamp
 }
 var file_store_workspace_setting_proto_depIdxs = []int32{
-	0, // 0: wekalist.store.WorkspaceSetting.key:type_name -> wekalist.store.WorkspaceSettingKey
-	3, // 1: wekalist.store.WorkspaceSetting.basic_setting:type_name -> wekalist.store.WorkspaceBasicSetting
-	4, // 2: wekalist.store.WorkspaceSetting.general_setting:type_name -> wekalist.store.WorkspaceGeneralSetting
-	6, // 3: wekalist.store.WorkspaceSetting.storage_setting:type_name -> wekalist.store.WorkspaceStorageSetting
-	8, // 4: wekalist.store.WorkspaceSetting.memo_related_setting:type_name -> wekalist.store.WorkspaceMemoRelatedSetting
-	5, // 5: wekalist.store.WorkspaceGeneralSetting.custom_profile:type_name -> wekalist.store.WorkspaceCustomProfile
-	1, // 6: wekalist.store.WorkspaceStorageSetting.storage_type:type_name -> wekalist.store.WorkspaceStorageSetting.StorageType
-	7, // 7: wekalist.store.WorkspaceStorageSetting.s3_config:type_name -> wekalist.store.StorageS3Config
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	0,  // 0: wekalist.store.WorkspaceSetting.key:type_name -> wekalist.store.WorkspaceSettingKey
+	6,  // 1: wekalist.store.WorkspaceSetting.basic_setting:type_name -> wekalist.store.WorkspaceBasicSetting
+	8,  // 2: wekalist.store.WorkspaceSetting.general_setting:type_name -> wekalist.store.WorkspaceGeneralSetting
+	11, // 3: wekalist.store.WorkspaceSetting.storage_setting:type_name -> wekalist.store.WorkspaceStorageSetting
+	13, // 4: wekalist.store.WorkspaceSetting.memo_related_setting:type_name -> wekalist.store.WorkspaceMemoRelatedSetting
+	14, // 5: wekalist.store.WorkspaceSetting.ai_setting:type_name -> wekalist.store.WorkspaceAISetting
+	15, // 6: wekalist.store.WorkspaceSetting.email_templates_setting:type_name -> wekalist.store.WorkspaceEmailTemplatesSetting
+	7,  // 7: wekalist.store.WorkspaceBasicSetting.jwt_signing_keys:type_name -> wekalist.store.JWTSigningKey
+	18, // 8: wekalist.store.JWTSigningKey.create_time:type_name -> google.protobuf.Timestamp
+	18, // 9: wekalist.store.JWTSigningKey.expire_time:type_name -> google.protobuf.Timestamp
+	10, // 10: wekalist.store.WorkspaceGeneralSetting.custom_profile:type_name -> wekalist.store.WorkspaceCustomProfile
+	9,  // 11: wekalist.store.WorkspaceGeneralSetting.rate_limit:type_name -> wekalist.store.WorkspaceRateLimit
+	1,  // 12: wekalist.store.WorkspaceGeneralSetting.smtp_security:type_name -> wekalist.store.WorkspaceGeneralSetting.SmtpSecurity
+	2,  // 13: wekalist.store.WorkspaceStorageSetting.storage_type:type_name -> wekalist.store.WorkspaceStorageSetting.StorageType
+	12, // 14: wekalist.store.WorkspaceStorageSetting.s3_config:type_name -> wekalist.store.StorageS3Config
+	3,  // 15: wekalist.store.WorkspaceAISetting.provider:type_name -> wekalist.store.WorkspaceAISetting.Provider
+	17, // 16: wekalist.store.WorkspaceAISetting.user_quota:type_name -> wekalist.store.WorkspaceAISetting.Quota
+	17, // 17: wekalist.store.WorkspaceAISetting.workspace_quota:type_name -> wekalist.store.WorkspaceAISetting.Quota
+	16, // 18: wekalist.store.WorkspaceEmailTemplatesSetting.templates:type_name -> wekalist.store.EmailTemplate
+	4,  // 19: wekalist.store.WorkspaceAISetting.Quota.window:type_name -> wekalist.store.WorkspaceAISetting.Quota.Window
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_store_workspace_setting_proto_init() }
@@ -953,14 +1758,16 @@ func file_store_workspace_setting_proto_init() {
 		(*WorkspaceSetting_GeneralSetting)(nil),
 		(*WorkspaceSetting_StorageSetting)(nil),
 		(*WorkspaceSetting_MemoRelatedSetting)(nil),
+		(*WorkspaceSetting_AiSetting)(nil),
+		(*WorkspaceSetting_EmailTemplatesSetting)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_store_workspace_setting_proto_rawDesc), len(file_store_workspace_setting_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   7,
+			NumEnums:      5,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -2,11 +2,11 @@ package v1
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -47,6 +47,35 @@ func NewGRPCAuthInterceptor(store *store.Store, secret string) *GRPCAuthIntercep
 
 // AuthenticationInterceptor is the unary interceptor for gRPC API.
 func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, request any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := in.authenticate(ctx, serverInfo.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+// AuthenticationStreamInterceptor is the stream interceptor for gRPC API.
+func (in *GRPCAuthInterceptor) AuthenticationStreamInterceptor(srv any, serverStream grpc.ServerStream, serverInfo *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := in.authenticate(serverStream.Context(), serverInfo.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedServerStream{ServerStream: serverStream, ctx: ctx})
+}
+
+// authenticatedServerStream overrides the context of a server stream with the authenticated one.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate resolves the caller of the given method from the incoming metadata
+// and returns a context carrying the authenticated user.
+func (in *GRPCAuthInterceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Errorf(codes.Unauthenticated, "failed to parse metadata from incoming context")
@@ -61,21 +90,24 @@ func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, re
 			if parseErr != nil {
 				return nil, status.Errorf(codes.Internal, "failed to parse session cookie: %v", parseErr)
 			}
-			return in.handleAuthenticatedRequest(ctx, request, serverInfo, handler, user, sessionID, "")
+			return in.handleAuthenticatedRequest(ctx, fullMethod, user, sessionID, "")
 		}
 	}
 
 	// Try to authenticate via JWT access token (from Authorization header)
 	if accessToken, err := getAccessTokenFromMetadata(md); err == nil && accessToken != "" {
-		user, err := in.authenticateByJWT(ctx, accessToken)
+		user, userAccessToken, err := in.authenticateByJWT(ctx, accessToken)
 		if err == nil && user != nil {
-			return in.handleAuthenticatedRequest(ctx, request, serverInfo, handler, user, "", accessToken)
+			if !isAccessTokenScopeAllowedMethod(userAccessToken.Scopes, fullMethod) {
+				return nil, status.Errorf(codes.PermissionDenied, "access token scopes %v do not allow %s", userAccessToken.Scopes, fullMethod)
+			}
+			return in.handleAuthenticatedRequest(ctx, fullMethod, user, "", accessToken)
 		}
 	}
 
 	// If no valid authentication found, check if this method is in the allowlist (public endpoints)
-	if isUnauthorizeAllowedMethod(serverInfo.FullMethod) {
-		return handler(ctx, request)
+	if isUnauthorizeAllowedMethod(fullMethod) {
+		return ctx, nil
 	}
 
 	// If authentication is required but not found, reject the request
@@ -83,14 +115,17 @@ func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, re
 }
 
 // handleAuthenticatedRequest processes an authenticated request with the given user and auth info.
-func (in *GRPCAuthInterceptor) handleAuthenticatedRequest(ctx context.Context, request any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler, user *store.User, sessionID, accessToken string) (any, error) {
+func (in *GRPCAuthInterceptor) handleAuthenticatedRequest(ctx context.Context, fullMethod string, user *store.User, sessionID, accessToken string) (context.Context, error) {
 	// Check user status
 	if user.RowStatus == store.Archived {
 		return nil, errors.Errorf("user %q is archived", user.Username)
 	}
-	if isOnlyForAdminAllowedMethod(serverInfo.FullMethod) && user.Role != store.RoleHost && user.Role != store.RoleAdmin {
+	if isOnlyForAdminAllowedMethod(fullMethod) && user.Role != store.RoleHost && user.Role != store.RoleAdmin {
 		return nil, errors.Errorf("user %q is not admin", user.Username)
 	}
+	if err := in.checkTwoFactorSetup(ctx, fullMethod, user); err != nil {
+		return nil, err
+	}
 
 	// Set context values
 	ctx = context.WithValue(ctx, userIDContextKey, user.ID)
@@ -98,65 +133,87 @@ func (in *GRPCAuthInterceptor) handleAuthenticatedRequest(ctx context.Context, r
 	if sessionID != "" {
 		// Session-based authentication
 		ctx = context.WithValue(ctx, sessionIDContextKey, sessionID)
-		// Update session last accessed time
-		_ = in.updateSessionLastAccessed(ctx, user.ID, sessionID)
 	} else if accessToken != "" {
 		// JWT access token-based authentication
 		ctx = context.WithValue(ctx, accessTokenContextKey, accessToken)
 	}
 
-	return handler(ctx, request)
+	return ctx, nil
+}
+
+// checkTwoFactorSetup rejects requests of users without two-factor authentication when the workspace requires it,
+// except for the methods needed to set it up.
+func (in *GRPCAuthInterceptor) checkTwoFactorSetup(ctx context.Context, fullMethod string, user *store.User) error {
+	if isTwoFactorSetupAllowedMethod(fullMethod) {
+		return nil
+	}
+	workspaceGeneralSetting, err := in.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get workspace general setting: %v", err)
+	}
+	if !workspaceGeneralSetting.RequireTwoFactor {
+		return nil
+	}
+	twoFactor, err := in.Store.GetUserTwoFactor(ctx, user.ID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get two-factor setting: %v", err)
+	}
+	if !twoFactor.GetEnabled() {
+		return status.Errorf(codes.FailedPrecondition, "two-factor authentication must be set up")
+	}
+	return nil
 }
 
 // authenticateByJWT authenticates a user using JWT access token from Authorization header.
-func (in *GRPCAuthInterceptor) authenticateByJWT(ctx context.Context, accessToken string) (*store.User, error) {
+// It returns the stored access token as well, which carries the scopes granted to the request.
+func (in *GRPCAuthInterceptor) authenticateByJWT(ctx context.Context, accessToken string) (*store.User, *storepb.AccessTokensUserSetting_AccessToken, error) {
 	if accessToken == "" {
-		return nil, status.Errorf(codes.Unauthenticated, "access token not found")
+		return nil, nil, status.Errorf(codes.Unauthenticated, "access token not found")
 	}
 	claims := &ClaimsMessage{}
-	_, err := jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (any, error) {
-		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-			return nil, status.Errorf(codes.Unauthenticated, "unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
-		}
-		if kid, ok := t.Header["kid"].(string); ok {
-			if kid == "v1" {
-				return []byte(in.secret), nil
-			}
-		}
-		return nil, status.Errorf(codes.Unauthenticated, "unexpected access token kid=%v", t.Header["kid"])
-	})
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "Invalid or expired access token")
+	// Access tokens signed by a retired key stop working once the grace period of the key is over.
+	if err := parseJWT(ctx, in.Store, in.secret, accessToken, claims); err != nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "Invalid or expired access token")
 	}
 
 	// Get user from JWT claims
 	userID, err := util.ConvertStringToInt32(claims.Subject)
 	if err != nil {
-		return nil, errors.Wrap(err, "malformed ID in the token")
+		return nil, nil, errors.Wrap(err, "malformed ID in the token")
 	}
 	user, err := in.Store.GetUser(ctx, &store.FindUser{
 		ID: &userID,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get user")
+		return nil, nil, errors.Wrap(err, "failed to get user")
 	}
 	if user == nil {
-		return nil, errors.Errorf("user %q not exists", userID)
+		return nil, nil, errors.Errorf("user %q not exists", userID)
 	}
 	if user.RowStatus == store.Archived {
-		return nil, errors.Errorf("user %q is archived", userID)
+		return nil, nil, errors.Errorf("user %q is archived", userID)
 	}
 
 	// Validate that this access token exists in the user's access tokens
 	accessTokens, err := in.Store.GetUserAccessTokens(ctx, user.ID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get user access tokens")
+		return nil, nil, errors.Wrapf(err, "failed to get user access tokens")
+	}
+	userAccessToken := findUserAccessToken(accessToken, accessTokens)
+	if userAccessToken == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "invalid access token")
 	}
-	if !validateAccessToken(accessToken, accessTokens) {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid access token")
+	if userAccessToken.ExpireTime != nil && userAccessToken.ExpireTime.AsTime().Before(time.Now()) {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "access token expired")
+	}
+	// Record the last use, at most once per interval to avoid a write on every request.
+	if userAccessToken.LastUsedTime == nil || time.Since(userAccessToken.LastUsedTime.AsTime()) > accessTokenLastUsedInterval {
+		if err := in.Store.UpdateUserAccessTokenLastUsedTime(ctx, user.ID, userAccessToken.Id, timestamppb.Now()); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to update access token")
+		}
 	}
 
-	return user, nil
+	return user, userAccessToken, nil
 }
 
 // authenticateBySession authenticates a user using session ID from cookie.
@@ -185,57 +242,65 @@ func (in *GRPCAuthInterceptor) authenticateBySession(ctx context.Context, sessio
 		return nil, status.Errorf(codes.Unauthenticated, "user is archived")
 	}
 
-	// Get user sessions and validate the sessionID
-	sessions, err := in.Store.GetUserSessions(ctx, userID)
+	userSession, err := in.Store.GetUserSession(ctx, &store.FindUserSession{
+		ID:     &sessionID,
+		UserID: &userID,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get user sessions")
+		return nil, errors.Wrap(err, "failed to get user session")
 	}
-
-	if !validateUserSession(sessionID, sessions) {
+	if userSession == nil || !validateUserSession(userSession) {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired session")
 	}
+	// Record the last access, at most once per interval to avoid a write on every request.
+	if time.Since(time.Unix(userSession.LastAccessedTs, 0)) > sessionLastAccessedInterval {
+		lastAccessedTs := time.Now().Unix()
+		if err := in.Store.UpdateUserSession(ctx, &store.UpdateUserSession{
+			ID:             sessionID,
+			LastAccessedTs: &lastAccessedTs,
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to update user session")
+		}
+	}
 
 	return user, nil
 }
 
-// updateSessionLastAccessed updates the last accessed time for a user session.
-func (in *GRPCAuthInterceptor) updateSessionLastAccessed(ctx context.Context, userID int32, sessionID string) error {
-	return in.Store.UpdateUserSessionLastAccessed(ctx, userID, sessionID, timestamppb.Now())
+// validateUserSession checks if a session is still valid, neither idle for too long nor past its maximum lifetime.
+func validateUserSession(userSession *store.UserSession) bool {
+	return getUserSessionExpireTime(userSession).After(time.Now())
 }
 
-// validateUserSession checks if a session exists and is still valid using sliding expiration.
-func validateUserSession(sessionID string, userSessions []*storepb.SessionsUserSetting_Session) bool {
-	for _, session := range userSessions {
-		if sessionID == session.SessionId {
-			// Use sliding expiration: check if last_accessed_time + 2 weeks > current_time
-			if session.LastAccessedTime != nil {
-				expirationTime := session.LastAccessedTime.AsTime().Add(SessionSlidingDuration)
-				if expirationTime.Before(time.Now()) {
-					return false
-				}
-			}
-			return true
-		}
+// getUserSessionExpireTime returns when the session expires, by being idle or by reaching its maximum lifetime.
+func getUserSessionExpireTime(userSession *store.UserSession) time.Time {
+	expireTime := time.Unix(userSession.LastAccessedTs, 0).Add(SessionSlidingDuration)
+	if absoluteExpireTime := time.Unix(userSession.ExpiresTs, 0); absoluteExpireTime.Before(expireTime) {
+		return absoluteExpireTime
 	}
-	return false
+	return expireTime
 }
 
 // getSessionIDFromMetadata extracts session cookie value from cookie.
 func getSessionIDFromMetadata(md metadata.MD) (string, error) {
-	// Check the cookie header for session cookie value
-	var sessionCookieValue string
+	sessionCookieValue := getCookieFromMetadata(md, SessionCookieName)
+	if sessionCookieValue == "" {
+		return "", errors.New("session cookie not found")
+	}
+	return sessionCookieValue, nil
+}
+
+// getCookieFromMetadata returns the value of the named cookie of the request, or an empty string.
+func getCookieFromMetadata(md metadata.MD, name string) string {
+	var value string
 	for _, t := range append(md.Get("grpcgateway-cookie"), md.Get("cookie")...) {
 		header := http.Header{}
 		header.Add("Cookie", t)
 		request := http.Request{Header: header}
-		if v, _ := request.Cookie(SessionCookieName); v != nil {
-			sessionCookieValue = v.Value
+		if v, _ := request.Cookie(name); v != nil {
+			value = v.Value
 		}
 	}
-	if sessionCookieValue == "" {
-		return "", errors.New("session cookie not found")
-	}
-	return sessionCookieValue, nil
+	return value
 }
 
 // getAccessTokenFromMetadata extracts access token from Authorization header.
@@ -252,11 +317,13 @@ func getAccessTokenFromMetadata(md metadata.MD) (string, error) {
 	return authHeaderParts[1], nil
 }
 
-func validateAccessToken(accessTokenString string, userAccessTokens []*storepb.AccessTokensUserSetting_AccessToken) bool {
+// findUserAccessToken returns the stored access token matching the hash of the given one.
+func findUserAccessToken(accessTokenString string, userAccessTokens []*storepb.AccessTokensUserSetting_AccessToken) *storepb.AccessTokensUserSetting_AccessToken {
+	tokenHash := store.HashAccessToken(accessTokenString)
 	for _, userAccessToken := range userAccessTokens {
-		if accessTokenString == userAccessToken.AccessToken {
-			return true
+		if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(userAccessToken.TokenHash)) == 1 {
+			return userAccessToken
 		}
 	}
-	return false
+	return nil
 }
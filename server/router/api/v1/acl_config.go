@@ -1,25 +1,32 @@
 package v1
 
+import (
+	"slices"
+
+	"github.com/imrany/wekalist/store"
+)
+
 var authenticationAllowlistMethods = map[string]bool{
 	"/wekalist.api.v1.WorkspaceService/GetWorkspaceProfile":          true,
 	"/wekalist.api.v1.WorkspaceService/GetWorkspaceSetting":          true,
 	"/wekalist.api.v1.IdentityProviderService/ListIdentityProviders": true,
 	"/wekalist.api.v1.AuthService/CreateSession":                     true,
 	"/wekalist.api.v1.AuthService/GetCurrentSession":                 true,
+	"/wekalist.api.v1.AuthService/RequestPasswordReset":              true,
+	"/wekalist.api.v1.AuthService/ConfirmPasswordReset":              true,
+	"/wekalist.api.v1.AuthService/BeginPasskeyAssertion":             true,
+	"/wekalist.api.v1.AuthService/BeginSSOAuthorization":             true,
 	"/wekalist.api.v1.UserService/VerifyUser":                        true,
 	"/wekalist.api.v1.UserService/CreateUser":                        true,
 	"/wekalist.api.v1.UserService/GetUser":                           true,
 	"/wekalist.api.v1.UserService/GetUserAvatar":                     true,
 	"/wekalist.api.v1.UserService/GetUsererviceStats":                true,
-	"/wekalist.api.v1.AiService/GenAi":                      		  true,
+	"/wekalist.api.v1.AiService/GenAi":                               true,
 	"/wekalist.api.v1.UserService/ListAllUserStats":                  true,
 	"/wekalist.api.v1.UserService/SearchUsers":                       true,
 	"/wekalist.api.v1.MemoService/GetMemo":                           true,
 	"/wekalist.api.v1.MemoService/ListMemos":                         true,
 	"/wekalist.api.v1.MarkdownService/GetLinkMetadata":               true,
-	"/wekalist.api.v1.SubscriptionService/AddSubscription":           true,
-	"/wekalist.api.v1.SubscriptionService/RemoveSubscription":        true,
-	"/wekalist.api.v1.SubscriptionService/SendNotification":          true,
 	"/wekalist.api.v1.AttachmentService/GetAttachmentBinary":         true,
 }
 
@@ -31,9 +38,89 @@ func isUnauthorizeAllowedMethod(fullMethodName string) bool {
 var allowedMethodsOnlyForAdmin = map[string]bool{
 	"/wekalist.api.v1.UserService/CreateUser":                  true,
 	"/wekalist.api.v1.WorkspaceService/UpdateWorkspaceSetting": true,
+	"/wekalist.api.v1.WorkspaceService/ListSigningKeys":        true,
+	"/wekalist.api.v1.WorkspaceService/RotateSigningKey":       true,
+	"/wekalist.api.v1.WorkspaceService/RevokeSigningKey":       true,
+	"/wekalist.api.v1.SubscriptionService/SendNotification":    true,
+	"/wekalist.api.v1.SubscriptionService/ListPushDeliveries":  true,
 }
 
 // isOnlyForAdminAllowedMethod returns true if the method is allowed to be called only by admin.
 func isOnlyForAdminAllowedMethod(methodName string) bool {
 	return allowedMethodsOnlyForAdmin[methodName]
 }
+
+// twoFactorSetupAllowedMethods are the methods available to users who still have to set up
+// two-factor authentication when the workspace requires it.
+var twoFactorSetupAllowedMethods = map[string]bool{
+	"/wekalist.api.v1.AuthService/DeleteSession":            true,
+	"/wekalist.api.v1.UserService/GetUserSetting":           true,
+	"/wekalist.api.v1.UserService/SetupUserTwoFactor":       true,
+	"/wekalist.api.v1.UserService/EnableUserTwoFactor":      true,
+	"/wekalist.api.v1.WorkspaceService/GetWorkspaceProfile": true,
+}
+
+// isTwoFactorSetupAllowedMethod returns whether the method can be called before setting up required two-factor authentication.
+func isTwoFactorSetupAllowedMethod(methodName string) bool {
+	return twoFactorSetupAllowedMethods[methodName] || isUnauthorizeAllowedMethod(methodName)
+}
+
+// accessTokenScopeMethods are the methods granted by each access token scope.
+// The admin scope grants every method the user can call.
+var accessTokenScopeMethods = map[string]map[string]bool{
+	store.AccessTokenScopeMemosRead: {
+		"/wekalist.api.v1.MemoService/ListMemos":                 true,
+		"/wekalist.api.v1.MemoService/GetMemo":                   true,
+		"/wekalist.api.v1.MemoService/ListMemoAttachments":       true,
+		"/wekalist.api.v1.MemoService/ListMemoRelations":         true,
+		"/wekalist.api.v1.MemoService/ListMemoComments":          true,
+		"/wekalist.api.v1.MemoService/ListMemoReactions":         true,
+		"/wekalist.api.v1.AttachmentService/ListAttachments":     true,
+		"/wekalist.api.v1.AttachmentService/GetAttachment":       true,
+		"/wekalist.api.v1.AttachmentService/GetAttachmentBinary": true,
+	},
+	store.AccessTokenScopeMemosWrite: {
+		"/wekalist.api.v1.MemoService/CreateMemo":         true,
+		"/wekalist.api.v1.MemoService/UpdateMemo":         true,
+		"/wekalist.api.v1.MemoService/DeleteMemo":         true,
+		"/wekalist.api.v1.MemoService/RenameMemoTag":      true,
+		"/wekalist.api.v1.MemoService/DeleteMemoTag":      true,
+		"/wekalist.api.v1.MemoService/SetMemoAttachments": true,
+		"/wekalist.api.v1.MemoService/SetMemoRelations":   true,
+		"/wekalist.api.v1.MemoService/CreateMemoComment":  true,
+		"/wekalist.api.v1.MemoService/UpsertMemoReaction": true,
+		"/wekalist.api.v1.MemoService/DeleteMemoReaction": true,
+	},
+	store.AccessTokenScopeAttachmentsWrite: {
+		"/wekalist.api.v1.AttachmentService/CreateAttachment": true,
+		"/wekalist.api.v1.AttachmentService/UpdateAttachment": true,
+		"/wekalist.api.v1.AttachmentService/DeleteAttachment": true,
+	},
+	store.AccessTokenScopeAdmin: {},
+}
+
+// accessTokenCommonMethods are available to access tokens of any scope.
+var accessTokenCommonMethods = map[string]bool{
+	"/wekalist.api.v1.AuthService/GetCurrentSession":        true,
+	"/wekalist.api.v1.UserService/GetUser":                  true,
+	"/wekalist.api.v1.WorkspaceService/GetWorkspaceProfile": true,
+}
+
+// isValidAccessTokenScope returns whether the scope is known.
+func isValidAccessTokenScope(scope string) bool {
+	_, ok := accessTokenScopeMethods[scope]
+	return ok
+}
+
+// isAccessTokenScopeAllowedMethod returns whether an access token with the given scopes can call the method.
+func isAccessTokenScopeAllowedMethod(scopes []string, methodName string) bool {
+	if slices.Contains(scopes, store.AccessTokenScopeAdmin) || accessTokenCommonMethods[methodName] {
+		return true
+	}
+	for _, scope := range scopes {
+		if accessTokenScopeMethods[scope][methodName] {
+			return true
+		}
+	}
+	return false
+}
@@ -75,6 +75,10 @@ func (s *APIV1Service) convertActivityFromStore(ctx context.Context, activity *s
 	switch activity.Type {
 	case store.ActivityTypeMemoComment:
 		activityType = v1pb.Activity_MEMO_COMMENT
+	case store.ActivityTypeMemoReaction:
+		activityType = v1pb.Activity_MEMO_REACTION
+	case store.ActivityTypeMemoMention:
+		activityType = v1pb.Activity_MEMO_MENTION
 	default:
 		activityType = v1pb.Activity_TYPE_UNSPECIFIED
 	}
@@ -125,5 +129,40 @@ func (s *APIV1Service) convertActivityPayloadFromStore(ctx context.Context, payl
 			},
 		}
 	}
+	if payload.MemoReaction != nil {
+		memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+			ID:             &payload.MemoReaction.MemoId,
+			ExcludeContent: true,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+		}
+		if memo == nil {
+			return nil, status.Errorf(codes.NotFound, "memo does not exist")
+		}
+		v2Payload.Payload = &v1pb.ActivityPayload_MemoReaction{
+			MemoReaction: &v1pb.ActivityMemoReactionPayload{
+				Memo:         fmt.Sprintf("%s%s", MemoNamePrefix, memo.UID),
+				ReactionType: payload.MemoReaction.ReactionType,
+			},
+		}
+	}
+	if payload.MemoMention != nil {
+		memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+			ID:             &payload.MemoMention.MemoId,
+			ExcludeContent: true,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+		}
+		if memo == nil {
+			return nil, status.Errorf(codes.NotFound, "memo does not exist")
+		}
+		v2Payload.Payload = &v1pb.ActivityPayload_MemoMention{
+			MemoMention: &v1pb.ActivityMemoMentionPayload{
+				Memo: fmt.Sprintf("%s%s", MemoNamePrefix, memo.UID),
+			},
+		}
+	}
 	return v2Payload, nil
 }
@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/ai"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	askMemosSystemPrompt = "You answer questions using only the personal notes provided. " +
+		"Each note starts with its name in square brackets, e.g. [memos/abc]. " +
+		"Cite every note you use by writing its name in square brackets. " +
+		"If the notes do not contain the answer, say that you do not know. " +
+		"Reply in the same language as the question."
+
+	defaultAskMemosContextSize = 5
+	maxAskMemosContextSize     = 20
+	// maxAskMemosContentLength bounds the length of each memo sent as context.
+	maxAskMemosContentLength = 2000
+	// maxKeywordSearchCandidates bounds the memos scanned by the keyword fallback.
+	maxKeywordSearchCandidates = 100
+	maxKeywords                = 10
+)
+
+// askMemosStopWords are common question words ignored by the keyword fallback.
+var askMemosStopWords = map[string]bool{
+	"about": true, "and": true, "are": true, "did": true, "does": true, "for": true, "from": true,
+	"have": true, "how": true, "that": true, "the": true, "there": true, "this": true, "was": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true, "who": true, "why": true,
+	"with": true, "you": true, "your": true,
+}
+
+// AskMemos answers a question using the memos visible to the caller as context.
+func (s *APIV1Service) AskMemos(ctx context.Context, request *v1pb.AskMemosRequest) (*v1pb.AskMemosResponse, error) {
+	question := strings.TrimSpace(request.Question)
+	if question == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "question is required")
+	}
+	contextSize := int(request.ContextSize)
+	if contextSize <= 0 {
+		contextSize = defaultAskMemosContextSize
+	}
+	contextSize = min(contextSize, maxAskMemosContextSize)
+
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	provider, err := s.newAIProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Prefer semantic retrieval, and fall back to keywords for providers without embeddings.
+	scoredMemos, err := s.searchMemosSemantic(ctx, provider, currentUser, question, contextSize)
+	if errors.Is(err, ai.ErrEmbeddingNotSupported) {
+		scoredMemos, err = s.searchMemosByKeywords(ctx, currentUser, question, contextSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	memos := make([]*store.Memo, 0, len(scoredMemos))
+	for _, scoredMemo := range scoredMemos {
+		memos = append(memos, scoredMemo.memo)
+	}
+	prompt := buildAskMemosPrompt(question, memos)
+	reservation, err := s.reserveAIUsage(ctx, currentUser.ID, ai.EstimateTokens(askMemosSystemPrompt, prompt))
+	if err != nil {
+		return nil, err
+	}
+	result, err := provider.Generate(ctx, &ai.GenerateRequest{
+		System: askMemosSystemPrompt,
+		Prompt: prompt,
+	})
+	if err != nil {
+		s.releaseAIUsage(ctx, reservation)
+		return nil, status.Errorf(codes.Internal, "failed to generate AI response: %v", err)
+	}
+	s.settleAIUsage(ctx, reservation, result.Model, result.Usage, result.Text)
+
+	answer := strings.TrimSpace(result.Text)
+	return &v1pb.AskMemosResponse{
+		Answer:    answer,
+		Citations: extractMemoCitations(answer, memos),
+	}, nil
+}
+
+// searchMemosByKeywords returns up to limit memos visible to currentUser, ranked by the number of
+// question keywords they contain.
+func (s *APIV1Service) searchMemosByKeywords(ctx context.Context, currentUser *store.User, question string, limit int) ([]*scoredMemo, error) {
+	keywords := extractKeywords(question)
+	if len(keywords) == 0 {
+		return []*scoredMemo{}, nil
+	}
+	conditions := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		conditions = append(conditions, fmt.Sprintf("content.contains(%s)", strconv.Quote(keyword)))
+	}
+
+	normalStatus := store.Normal
+	candidateLimit := maxKeywordSearchCandidates
+	memoFind := &store.FindMemo{
+		RowStatus:       &normalStatus,
+		ExcludeComments: true,
+		Filters:         []string{strings.Join(conditions, " || ")},
+		Limit:           &candidateLimit,
+	}
+	applyMemoVisibilityFilter(memoFind, currentUser)
+	memos, err := s.Store.ListMemos(ctx, memoFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+	}
+
+	scoredMemos := make([]*scoredMemo, 0, len(memos))
+	for _, memo := range memos {
+		content := strings.ToLower(memo.Content)
+		matches := 0
+		for _, keyword := range keywords {
+			if strings.Contains(content, keyword) {
+				matches++
+			}
+		}
+		scoredMemos = append(scoredMemos, &scoredMemo{memo: memo, score: float64(matches) / float64(len(keywords))})
+	}
+	slices.SortStableFunc(scoredMemos, func(a, b *scoredMemo) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	return scoredMemos[:min(limit, len(scoredMemos))], nil
+}
+
+// extractKeywords returns the distinct lowercase words of the text that are worth searching for.
+func extractKeywords(text string) []string {
+	keywords := []string{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 3 || askMemosStopWords[word] || slices.Contains(keywords, word) {
+			continue
+		}
+		keywords = append(keywords, word)
+		if len(keywords) == maxKeywords {
+			break
+		}
+	}
+	return keywords
+}
+
+func buildAskMemosPrompt(question string, memos []*store.Memo) string {
+	var builder strings.Builder
+	builder.WriteString("Notes:\n\n")
+	if len(memos) == 0 {
+		builder.WriteString("(no notes found)\n\n")
+	}
+	for _, memo := range memos {
+		fmt.Fprintf(&builder, "[%s%s] (%s)\n%s\n\n", MemoNamePrefix, memo.UID,
+			time.Unix(memo.CreatedTs, 0).UTC().Format(time.DateOnly), truncateText(memo.Content, maxAskMemosContentLength))
+	}
+	builder.WriteString("Question: ")
+	builder.WriteString(question)
+	return builder.String()
+}
+
+// extractMemoCitations returns the names of the given memos cited in the answer, in order of first citation.
+func extractMemoCitations(answer string, memos []*store.Memo) []string {
+	citations := []string{}
+	positions := map[string]int{}
+	for _, memo := range memos {
+		name := MemoNamePrefix + memo.UID
+		if index := indexMemoName(answer, name); index >= 0 {
+			citations = append(citations, name)
+			positions[name] = index
+		}
+	}
+	slices.SortStableFunc(citations, func(a, b string) int {
+		return cmp.Compare(positions[a], positions[b])
+	})
+	return citations
+}
+
+// indexMemoName returns the index of the first occurrence of the memo name in text that is not
+// part of a longer name, or -1 if there is none.
+func indexMemoName(text, name string) int {
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], name)
+		if index < 0 {
+			return -1
+		}
+		end := offset + index + len(name)
+		if end == len(text) || !isMemoUIDRune(rune(text[end])) {
+			return offset + index
+		}
+		offset = end
+	}
+	return -1
+}
+
+func isMemoUIDRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestExtractKeywords(t *testing.T) {
+	require.Equal(t, []string{"decide", "release", "freeze"}, extractKeywords("What did we decide about the release freeze? Release!"))
+	require.Empty(t, extractKeywords("Who is it?"))
+}
+
+func TestExtractMemoCitations(t *testing.T) {
+	memos := []*store.Memo{{UID: "abc"}, {UID: "abcd"}, {UID: "xyz"}, {UID: "unused"}}
+
+	tests := []struct {
+		name     string
+		answer   string
+		expected []string
+	}{
+		{
+			name:     "orders citations by first appearance",
+			answer:   "Per [memos/xyz] and [memos/abc], yes.",
+			expected: []string{"memos/xyz", "memos/abc"},
+		},
+		{
+			name:     "does not match a prefix of a longer name",
+			answer:   "See memos/abcd.",
+			expected: []string{"memos/abcd"},
+		},
+		{
+			name:     "returns no citations when none are cited",
+			answer:   "I do not know.",
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, extractMemoCitations(tt.answer, memos))
+		})
+	}
+}
+
+func TestSearchMemosByKeywords(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Store: testStore}
+
+	owner, err := testStore.CreateUser(ctx, &store.User{Username: "owner", Role: store.RoleUser, Email: "owner@example.com"})
+	require.NoError(t, err)
+	other, err := testStore.CreateUser(ctx, &store.User{Username: "other", Role: store.RoleUser, Email: "other@example.com"})
+	require.NoError(t, err)
+	for _, memo := range []*store.Memo{
+		{UID: "freeze", Content: "The release freeze starts on Monday", Visibility: store.Protected},
+		{UID: "release", Content: "Release notes draft", Visibility: store.Public},
+		{UID: "private", Content: "Private release freeze notes", Visibility: store.Private},
+		{UID: "unrelated", Content: "Groceries", Visibility: store.Public},
+	} {
+		memo.CreatorID = owner.ID
+		_, err := testStore.CreateMemo(ctx, memo)
+		require.NoError(t, err)
+	}
+
+	scoredMemos, err := service.searchMemosByKeywords(ctx, other, "What did we decide about the release freeze?", 5)
+	require.NoError(t, err)
+	uids := []string{}
+	for _, scoredMemo := range scoredMemos {
+		uids = append(uids, scoredMemo.memo.UID)
+	}
+	require.Equal(t, []string{"freeze", "release"}, uids)
+}
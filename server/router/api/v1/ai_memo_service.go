@@ -0,0 +1,394 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/ai"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	summarizeMemoSystemPrompt = "You summarize personal notes written in Markdown. " +
+		"Reply with a concise plain text summary in the same language as the note, without any preamble."
+	rewriteMemoSystemPrompt = "You rewrite personal notes written in Markdown. " +
+		"Keep the meaning, the language, the Markdown formatting, #tags, links and task list items. " +
+		"Reply with the rewritten note only, without any preamble."
+	extractTasksSystemPrompt = "You extract actionable tasks from personal notes. " +
+		"Reply with a JSON array of strings only, one short imperative task per item, in the same language as the note. " +
+		"Reply with [] when there are no tasks."
+	suggestTagsSystemPrompt = "You suggest tags for personal notes. " +
+		"Reply with a JSON array of at most 5 short lowercase tags only, without the # prefix and without spaces. " +
+		"Reply with [] when no tag fits."
+
+	defaultRewriteInstruction = "Improve clarity and fix grammar and spelling."
+
+	defaultSemanticSearchPageSize = 10
+	maxSemanticSearchPageSize     = 100
+)
+
+// SummarizeMemo summarizes the content of a memo.
+func (s *APIV1Service) SummarizeMemo(ctx context.Context, request *v1pb.SummarizeMemoRequest) (*v1pb.SummarizeMemoResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.generateForMemo(ctx, summarizeMemoSystemPrompt, fmt.Sprintf("Summarize the following note:\n\n%s", memo.Content))
+	if err != nil {
+		return nil, err
+	}
+	return &v1pb.SummarizeMemoResponse{
+		Name:    request.Name,
+		Summary: strings.TrimSpace(text),
+	}, nil
+}
+
+// RewriteMemo rewrites the content of a memo following the optional instruction.
+func (s *APIV1Service) RewriteMemo(ctx context.Context, request *v1pb.RewriteMemoRequest) (*v1pb.RewriteMemoResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction := strings.TrimSpace(request.Instruction)
+	if instruction == "" {
+		instruction = defaultRewriteInstruction
+	}
+	text, err := s.generateForMemo(ctx, rewriteMemoSystemPrompt, fmt.Sprintf("%s\n\nNote:\n\n%s", instruction, memo.Content))
+	if err != nil {
+		return nil, err
+	}
+	return &v1pb.RewriteMemoResponse{
+		Name:    request.Name,
+		Content: trimCodeFence(text),
+	}, nil
+}
+
+// ExtractTasks extracts actionable tasks from the content of a memo.
+func (s *APIV1Service) ExtractTasks(ctx context.Context, request *v1pb.ExtractTasksRequest) (*v1pb.ExtractTasksResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.generateForMemo(ctx, extractTasksSystemPrompt, fmt.Sprintf("Extract the tasks from the following note:\n\n%s", memo.Content))
+	if err != nil {
+		return nil, err
+	}
+	tasks := []string{}
+	for _, task := range parseStringList(text) {
+		task = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(task, "[ ]"), "[x]"))
+		if task == "" || slices.Contains(tasks, task) || hasTaskItem(memo.Content, task) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	content := memo.Content
+	if len(tasks) > 0 {
+		lines := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			lines = append(lines, "- [ ] "+task)
+		}
+		content = appendMemoContent(content, strings.Join(lines, "\n"))
+	}
+	return &v1pb.ExtractTasksResponse{
+		Name:    request.Name,
+		Tasks:   tasks,
+		Content: content,
+	}, nil
+}
+
+// SuggestTags suggests tags for a memo, excluding the tags it already has.
+func (s *APIV1Service) SuggestTags(ctx context.Context, request *v1pb.SuggestTagsRequest) (*v1pb.SuggestTagsResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existingTags := []string{}
+	if memo.Payload != nil {
+		existingTags = memo.Payload.Tags
+	}
+	prompt := fmt.Sprintf("Suggest tags for the following note:\n\n%s", memo.Content)
+	if len(existingTags) > 0 {
+		prompt = fmt.Sprintf("The note is already tagged with: %s. Suggest additional tags for the following note:\n\n%s", strings.Join(existingTags, ", "), memo.Content)
+	}
+	text, err := s.generateForMemo(ctx, suggestTagsSystemPrompt, prompt)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, tag := range parseStringList(text) {
+		tag = normalizeTag(tag)
+		if tag == "" || slices.Contains(tags, tag) || slices.ContainsFunc(existingTags, func(existing string) bool {
+			return strings.EqualFold(existing, tag)
+		}) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	content := memo.Content
+	if len(tags) > 0 {
+		hashtags := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			hashtags = append(hashtags, "#"+tag)
+		}
+		content = appendMemoContent(content, strings.Join(hashtags, " "))
+	}
+	return &v1pb.SuggestTagsResponse{
+		Name:    request.Name,
+		Tags:    tags,
+		Content: content,
+	}, nil
+}
+
+// SearchMemosSemantic returns the visible memos ranked by cosine similarity to the query.
+func (s *APIV1Service) SearchMemosSemantic(ctx context.Context, request *v1pb.SearchMemosSemanticRequest) (*v1pb.SearchMemosSemanticResponse, error) {
+	query := strings.TrimSpace(request.Query)
+	if query == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "query is required")
+	}
+	pageSize := int(request.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultSemanticSearchPageSize
+	}
+	pageSize = min(pageSize, maxSemanticSearchPageSize)
+
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	provider, err := s.newAIProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scoredMemos, err := s.searchMemosSemantic(ctx, provider, currentUser, query, pageSize)
+	if err != nil {
+		if errors.Is(err, ai.ErrEmbeddingNotSupported) {
+			return nil, status.Errorf(codes.FailedPrecondition, "the configured AI provider does not support embeddings")
+		}
+		return nil, err
+	}
+
+	results := []*v1pb.SearchMemosSemanticResponse_Result{}
+	for _, scoredMemo := range scoredMemos {
+		memoMessage, err := s.convertMemoFromStore(ctx, scoredMemo.memo)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert memo: %v", err)
+		}
+		results = append(results, &v1pb.SearchMemosSemanticResponse_Result{
+			Memo:  memoMessage,
+			Score: float32(scoredMemo.score),
+		})
+	}
+	return &v1pb.SearchMemosSemanticResponse{Results: results}, nil
+}
+
+type scoredMemo struct {
+	memo  *store.Memo
+	score float64
+}
+
+// searchMemosSemantic returns up to limit memos visible to currentUser, ranked by cosine similarity to the query.
+// It returns ai.ErrEmbeddingNotSupported as is when the provider cannot compute embeddings.
+func (s *APIV1Service) searchMemosSemantic(ctx context.Context, provider ai.AIProvider, currentUser *store.User, query string, limit int) ([]*scoredMemo, error) {
+	reservation, err := s.reserveAIUsage(ctx, currentUser.ID, ai.EstimateTokens(query))
+	if err != nil {
+		return nil, err
+	}
+	result, err := provider.Embed(ctx, &ai.EmbedRequest{Texts: []string{query}})
+	if err != nil {
+		s.releaseAIUsage(ctx, reservation)
+		if errors.Is(err, ai.ErrEmbeddingNotSupported) {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to embed query: %v", err)
+	}
+	s.settleAIUsage(ctx, reservation, result.Model, result.Usage, "")
+	queryEmbedding := result.Embeddings[0]
+
+	// Only the embeddings of the memos visible to the user are scored, a page of memos at a time.
+	scores := map[int32]float64{}
+	memoIDList := []int32{}
+	normalStatus := store.Normal
+	for offset := 0; ; offset += maxSemanticSearchPageSize {
+		pageSize := maxSemanticSearchPageSize
+		memoFind := &store.FindMemo{
+			RowStatus:       &normalStatus,
+			ExcludeContent:  true,
+			ExcludeComments: true,
+			Limit:           &pageSize,
+			Offset:          &offset,
+		}
+		applyMemoVisibilityFilter(memoFind, currentUser)
+		memos, err := s.Store.ListMemos(ctx, memoFind)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+		}
+		if len(memos) == 0 {
+			break
+		}
+		visibleMemoIDList := make([]int32, 0, len(memos))
+		for _, memo := range memos {
+			visibleMemoIDList = append(visibleMemoIDList, memo.ID)
+		}
+		embeddings, err := s.Store.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{MemoIDList: visibleMemoIDList, Model: &result.Model})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list memo embeddings: %v", err)
+		}
+		for _, embedding := range embeddings {
+			if len(embedding.Embedding) != len(queryEmbedding) {
+				continue
+			}
+			if _, ok := scores[embedding.MemoID]; !ok {
+				memoIDList = append(memoIDList, embedding.MemoID)
+			}
+			scores[embedding.MemoID] = ai.CosineSimilarity(queryEmbedding, embedding.Embedding)
+		}
+		if len(memos) < pageSize {
+			break
+		}
+	}
+	slices.SortStableFunc(memoIDList, func(a, b int32) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	memoIDList = memoIDList[:min(limit, len(memoIDList))]
+	if len(memoIDList) == 0 {
+		return []*scoredMemo{}, nil
+	}
+
+	memoFind := &store.FindMemo{
+		IDList:          memoIDList,
+		RowStatus:       &normalStatus,
+		ExcludeComments: true,
+	}
+	applyMemoVisibilityFilter(memoFind, currentUser)
+	memos, err := s.Store.ListMemos(ctx, memoFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+	}
+	memoMap := make(map[int32]*store.Memo, len(memos))
+	for _, memo := range memos {
+		memoMap[memo.ID] = memo
+	}
+	scoredMemos := make([]*scoredMemo, 0, len(memoIDList))
+	for _, memoID := range memoIDList {
+		if memo, ok := memoMap[memoID]; ok {
+			scoredMemos = append(scoredMemos, &scoredMemo{memo: memo, score: scores[memoID]})
+		}
+	}
+	return scoredMemos, nil
+}
+
+// generateForMemo runs a completion with a server-side prompt on behalf of the current user.
+func (s *APIV1Service) generateForMemo(ctx context.Context, system, prompt string) (string, error) {
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return "", status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	provider, err := s.newAIProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+	reservation, err := s.reserveAIUsage(ctx, currentUser.ID, ai.EstimateTokens(system, prompt))
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.Generate(ctx, &ai.GenerateRequest{
+		System: system,
+		Prompt: prompt,
+	})
+	if err != nil {
+		s.releaseAIUsage(ctx, reservation)
+		return "", status.Errorf(codes.Internal, "failed to generate AI response: %v", err)
+	}
+	s.settleAIUsage(ctx, reservation, result.Model, result.Usage, result.Text)
+	return result.Text, nil
+}
+
+// parseStringList parses a list of strings from a model reply.
+// It accepts a JSON array, optionally wrapped in prose or a code fence, and falls back to one item per line.
+func parseStringList(text string) []string {
+	text = trimCodeFence(text)
+	if start, end := strings.Index(text, "["), strings.LastIndex(text, "]"); start >= 0 && end > start {
+		list := []string{}
+		if err := json.Unmarshal([]byte(text[start:end+1]), &list); err == nil {
+			return list
+		}
+	}
+
+	list := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "-*•0123456789.) ")
+		if line != "" {
+			list = append(list, line)
+		}
+	}
+	return list
+}
+
+// trimCodeFence removes a Markdown code fence wrapping the whole reply.
+func trimCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	text = strings.TrimSuffix(text, "```")
+	// Drop the opening fence together with its optional language hint.
+	if index := strings.Index(text, "\n"); index >= 0 {
+		text = text[index+1:]
+	} else {
+		text = strings.TrimPrefix(text, "```")
+	}
+	return strings.TrimSpace(text)
+}
+
+// normalizeTag turns a suggested tag into a valid memo tag.
+func normalizeTag(tag string) string {
+	tag = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+	return strings.Join(strings.FieldsFunc(tag, unicode.IsSpace), "-")
+}
+
+// hasTaskItem reports whether the content already has a task list item with the given text.
+func hasTaskItem(content, task string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"- [ ]", "- [x]", "- [X]", "* [ ]", "* [x]", "* [X]"} {
+			if rest, ok := strings.CutPrefix(line, prefix); ok && strings.EqualFold(strings.TrimSpace(rest), task) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appendMemoContent appends a block to the memo content, separated by a blank line.
+func appendMemoContent(content, block string) string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return block
+	}
+	return content + "\n\n" + block
+}
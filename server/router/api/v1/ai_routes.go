@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+)
+
+const streamGenAiMethod = "/wekalist.api.v1.AiService/StreamGenAi"
+
+// registerAIRoutes registers the server-sent events endpoint for streaming AI responses.
+// Browsers consume it with fetch, posting a JSON body. Only JSON is accepted, as a cross-site page can
+// send a form or a GET with the cookies of the user, but not a JSON request without a CORS preflight.
+func (s *APIV1Service) registerAIRoutes(echoServer *echo.Echo) {
+	echoServer.POST("/api/v1/genai/stream", s.handleGenAiStream)
+}
+
+func (s *APIV1Service) handleGenAiStream(c echo.Context) error {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || mediaType != echo.MIMEApplicationJSON {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "content type must be application/json")
+	}
+	request := &struct {
+		Prompt string `json:"prompt"`
+	}{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	ctx, err := s.authenticateHTTPRequest(c.Request(), streamGenAiMethod)
+	if err != nil {
+		return echo.NewHTTPError(runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+
+	started := false
+	send := func(event *v1pb.StreamGenAiResponse) error {
+		if !started {
+			c.Response().WriteHeader(http.StatusOK)
+			started = true
+		}
+		name := "delta"
+		if event.GetUsage() != nil {
+			name = "usage"
+		}
+		return writeServerSentEvent(c.Response(), name, event)
+	}
+	if err := s.streamGenAi(ctx, request.Prompt, send); err != nil {
+		if !started {
+			return echo.NewHTTPError(runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+		}
+		return writeServerSentEvent(c.Response(), "error", status.Convert(err).Proto())
+	}
+	return nil
+}
+
+// authenticateHTTPRequest authenticates a plain HTTP request with the same rules as the gRPC API,
+// treating it as a call to the given gRPC method.
+func (s *APIV1Service) authenticateHTTPRequest(r *http.Request, fullMethod string) (context.Context, error) {
+	md := metadata.MD{}
+	if cookie := r.Header.Get("Cookie"); cookie != "" {
+		md.Set("cookie", cookie)
+	}
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		md.Set("authorization", authorization)
+	}
+	md.Set("user-agent", r.UserAgent())
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	return NewGRPCAuthInterceptor(s.Store, s.Secret).authenticate(ctx, fullMethod)
+}
+
+// writeServerSentEvent writes the message as a named SSE event with a JSON payload and flushes it.
+func writeServerSentEvent(w *echo.Response, name string, message proto.Message) error {
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
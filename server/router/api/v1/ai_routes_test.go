@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestHandleGenAiStream(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"model":"test-model","response":"hello","done":false}`)
+		fmt.Fprintln(w, `{"model":"test-model","response":"","done":true,"prompt_eval_count":3,"eval_count":1}`)
+	}))
+	defer ollama.Close()
+	_, err := testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_AI,
+		Value: &storepb.WorkspaceSetting_AiSetting{
+			AiSetting: &storepb.WorkspaceAISetting{Provider: storepb.WorkspaceAISetting_OLLAMA, BaseUrl: ollama.URL},
+		},
+	})
+	require.NoError(t, err)
+
+	user, err := testStore.CreateUser(ctx, &store.User{Username: "user", Role: store.RoleUser, Email: "user@example.com"})
+	require.NoError(t, err)
+	sessionID, err := GenerateSessionID()
+	require.NoError(t, err)
+	_, err = testStore.CreateUserSession(ctx, &store.UserSession{
+		ID:             sessionID,
+		UserID:         user.ID,
+		CreatedTs:      time.Now().Unix(),
+		LastAccessedTs: time.Now().Unix(),
+		ExpiresTs:      time.Now().Add(SessionAbsoluteDuration).Unix(),
+	})
+	require.NoError(t, err)
+
+	e := echo.New()
+	service.registerAIRoutes(e)
+
+	t.Run("streams events to an authenticated user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/genai/stream", strings.NewReader(`{"prompt":"say hello"}`))
+		req.Header.Set(echo.HeaderContentType, "application/json; charset=utf-8")
+		req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: BuildSessionCookieValue(user.ID, sessionID)})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+		body := rec.Body.String()
+		require.Contains(t, body, "event: delta\ndata: {\"delta\":\"hello\"}\n\n")
+		require.Contains(t, body, "event: usage\n")
+		require.Contains(t, body, `"totalTokens":4`)
+	})
+
+	t.Run("rejects requests a cross-site page can send", func(t *testing.T) {
+		cookie := &http.Cookie{Name: SessionCookieName, Value: BuildSessionCookieValue(user.ID, sessionID)}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/genai/stream?prompt="+url.QueryEscape("say hello"), nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		for _, contentType := range []string{echo.MIMEApplicationForm, echo.MIMETextPlain} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/genai/stream", strings.NewReader(`{"prompt":"say hello"}`))
+			req.Header.Set(echo.HeaderContentType, contentType)
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		}
+	})
+
+	t.Run("rejects anonymous requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/genai/stream", strings.NewReader(`{"prompt":"say hello"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -2,17 +2,12 @@ package v1
 
 import (
 	"context"
-	"crypto/tls"
 
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
+	"github.com/imrany/wekalist/plugin/ai"
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
-	storepb "github.com/imrany/wekalist/proto/gen/store"
-	"github.com/imrany/wekalist/store"
 )
 
 // Generate AI response based on user prompt
@@ -26,104 +21,108 @@ func (s *APIV1Service) GenAi(ctx context.Context, request *v1pb.GenAiRequest) (*
 		return nil, status.Errorf(codes.InvalidArgument, "prompt cannot be empty")
 	}
 
-	// Get current user
 	currentUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get current user: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
 	}
 
-	// Get the current general setting
-	existingGeneralSetting, err := s.Store.GetUserSetting(ctx, &store.FindUserSetting{
-		UserID: &currentUser.ID,
-		Key:    storepb.UserSetting_GENERAL,
-	})
+	provider, err := s.newAIProvider(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get user settings: %v", err)
+		return nil, err
 	}
 
-	// Check usage limits
-	// if existingGeneralSetting != nil && existingGeneralSetting.GetGeneral() != nil {
-	// 	general := existingGeneralSetting.GetGeneral()
-	// 	if general.WrapperUsageCounter >= general.WrapperMaxUsage {
-	// 		return nil, status.Error(codes.ResourceExhausted, "maximum usage limit reached, please renew your API key")
-	// 	}
-	// }
-
-	// Establish gRPC connection with proper credentials
-	serverAddr := "wrapper.villebiz.com:443"
-	
-	// Use TLS credentials for HTTPS connection
-	var creds credentials.TransportCredentials
-	if true { // Set to false for local development without TLS
-		creds = credentials.NewTLS(&tls.Config{
-			ServerName: "wrapper.villebiz.com",
-		})
-	} else {
-		creds = insecure.NewCredentials()
-	}
-	
-	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(creds))
+	reservation, err := s.reserveAIUsage(ctx, currentUser.ID, ai.EstimateTokens(prompt))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to connect to AI service: %s", err.Error())
+		return nil, err
 	}
-	defer conn.Close()
-
-	// Create AI service client
-	client := v1pb.NewAiServiceClient(conn)
-
-	// Call AI service
-	resp, err := client.GenAi(ctx, &v1pb.GenAiRequest{
-		Prompt: request.Prompt,
+	// Call AI provider
+	result, err := provider.Generate(ctx, &ai.GenerateRequest{
+		Prompt: prompt,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate AI response: %v", err.Error())
+		s.releaseAIUsage(ctx, reservation)
+		return nil, status.Errorf(codes.Internal, "failed to generate AI response: %v", err)
 	}
+	s.settleAIUsage(ctx, reservation, result.Model, result.Usage, result.Text)
 
-	// Update usage counter after successful AI call
-	if err := s.incrementUsageCounter(ctx, currentUser.ID, existingGeneralSetting); err != nil {
-		// Log the error but don't fail the request since AI response was successful
-		// Consider using a proper logger here
-		// s.logger.Errorf("failed to update usage counter: %v", err)
-	}
+	return &v1pb.GenAiResponse{
+		Prompt:   prompt,
+		Response: result.Text,
+	}, nil
+}
 
-	return resp, nil
+// StreamGenAi streams the AI response for the user prompt as incremental deltas followed by a usage summary.
+func (s *APIV1Service) StreamGenAi(request *v1pb.GenAiRequest, stream v1pb.AiService_StreamGenAiServer) error {
+	return s.streamGenAi(stream.Context(), request.GetPrompt(), stream.Send)
 }
 
-// incrementUsageCounter handles the logic for updating the usage counter
-func (s *APIV1Service) incrementUsageCounter(ctx context.Context, userID int32, existingSetting *storepb.UserSetting) error {
-	// Default settings
-	generalSetting := &storepb.GeneralUserSetting{
-		Locale:              "en",
-		Appearance:          "system",
-		MemoVisibility:      "PRIVATE",
-		Theme:               "",
-		EnableNotifications: false,
-		WrapperApiKey:       "",
-		WrapperUsageCounter: 1, // Start with 1 since this is the first usage
-		WrapperMaxUsage:     100, // Default max usage, consider making configurable
+// streamGenAi generates the AI response for the prompt and hands every event to send.
+// It backs both the gRPC stream and the SSE endpoint.
+func (s *APIV1Service) streamGenAi(ctx context.Context, prompt string, send func(*v1pb.StreamGenAiResponse) error) error {
+	if prompt == "" {
+		return status.Errorf(codes.InvalidArgument, "prompt cannot be empty")
+	}
+
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	provider, err := s.newAIProvider(ctx)
+	if err != nil {
+		return err
 	}
 
-	// If there's an existing setting, preserve existing values and increment counter
-	if existingSetting != nil && existingSetting.GetGeneral() != nil {
-		existing := existingSetting.GetGeneral()
-		generalSetting.Locale = existing.Locale
-		generalSetting.Appearance = existing.Appearance
-		generalSetting.MemoVisibility = existing.MemoVisibility
-		generalSetting.Theme = existing.Theme
-		generalSetting.EnableNotifications = existing.EnableNotifications
-		generalSetting.WrapperApiKey = existing.WrapperApiKey
-		generalSetting.WrapperMaxUsage = existing.WrapperMaxUsage
-		generalSetting.WrapperUsageCounter = existing.WrapperUsageCounter + 1
+	reservation, err := s.reserveAIUsage(ctx, currentUser.ID, ai.EstimateTokens(prompt))
+	if err != nil {
+		return err
+	}
+	result, err := provider.GenerateStream(ctx, &ai.GenerateRequest{
+		Prompt: prompt,
+	}, func(delta string) error {
+		return send(&v1pb.StreamGenAiResponse{
+			Event: &v1pb.StreamGenAiResponse_Delta{Delta: delta},
+		})
+	})
+	if err != nil {
+		// A cancelled stream keeps its reservation, as the provider may have generated part of the response.
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		s.releaseAIUsage(ctx, reservation)
+		return status.Errorf(codes.Internal, "failed to generate AI response: %v", err)
 	}
 
-	// Upsert the general setting
-	_, err := s.Store.UpsertUserSetting(ctx, &storepb.UserSetting{
-		UserId: userID,
-		Key:    storepb.UserSetting_GENERAL,
-		Value: &storepb.UserSetting_General{
-			General: generalSetting,
+	s.settleAIUsage(ctx, reservation, result.Model, result.Usage, result.Text)
+
+	return send(&v1pb.StreamGenAiResponse{
+		Event: &v1pb.StreamGenAiResponse_Usage{
+			Usage: &v1pb.GenAiUsage{
+				Model:            result.Model,
+				PromptTokens:     result.Usage.PromptTokens,
+				CompletionTokens: result.Usage.CompletionTokens,
+				TotalTokens:      result.Usage.TotalTokens,
+			},
 		},
 	})
-	
-	return err
-}
\ No newline at end of file
+}
+
+// newAIProvider creates the AI provider configured in the workspace AI setting.
+// Every call to the provider is made with a reservation of its usage, see reserveAIUsage.
+func (s *APIV1Service) newAIProvider(ctx context.Context) (ai.AIProvider, error) {
+	workspaceAISetting, err := s.Store.GetWorkspaceAISetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ai setting: %v", err)
+	}
+	provider, err := ai.NewProvider(workspaceAISetting)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to create AI provider: %v", err)
+	}
+	return provider, nil
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/imrany/wekalist/plugin/ai"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+// GetAiUsage returns the AI usage of a user or of the whole workspace in the current quota window.
+func (s *APIV1Service) GetAiUsage(ctx context.Context, request *v1pb.GetAiUsageRequest) (*v1pb.AiUsage, error) {
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, err := extractOptionalUserID(request.User)
+	if err != nil {
+		return nil, err
+	}
+	if (userID == nil || *userID != currentUser.ID) && !isSuperUser(currentUser) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	workspaceAISetting, err := s.Store.GetWorkspaceAISetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ai setting: %v", err)
+	}
+	quota := workspaceAISetting.WorkspaceQuota
+	if userID != nil {
+		quota = workspaceAISetting.UserQuota
+	}
+	windowStart, windowEnd := store.GetAIQuotaWindow(quota, time.Now())
+	windowStartTs := windowStart.Unix()
+	stats, err := s.Store.GetAIUsageStats(ctx, &store.FindAIUsage{
+		UserID:         userID,
+		CreatedTsAfter: &windowStartTs,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get ai usage: %v", err)
+	}
+
+	return &v1pb.AiUsage{
+		User:            request.User,
+		Quota:           convertWorkspaceAIQuotaFromStore(quota),
+		WindowStartTime: timestamppb.New(windowStart),
+		WindowEndTime:   timestamppb.New(windowEnd),
+		RequestCount:    stats.RequestCount,
+		TotalTokens:     stats.TotalTokens,
+	}, nil
+}
+
+// ResetAiUsage clears the recorded AI usage of a user or of the whole workspace.
+func (s *APIV1Service) ResetAiUsage(ctx context.Context, request *v1pb.ResetAiUsageRequest) (*emptypb.Empty, error) {
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	if !isSuperUser(currentUser) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	userID, err := extractOptionalUserID(request.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Store.DeleteAIUsage(ctx, &store.DeleteAIUsage{UserID: userID}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset ai usage: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// reserveAIUsage reserves the estimated usage of an AI call for the user before the call is made, and returns a
+// ResourceExhausted error if it would go over the user or the workspace quota. The quotas are checked and the
+// usage reserved in one transaction, so that concurrent and streaming calls cannot all pass the check.
+// The reservation is settled with the actual usage by settleAIUsage, or released by releaseAIUsage.
+func (s *APIV1Service) reserveAIUsage(ctx context.Context, userID int32, estimatedTokens int32) (*store.AIUsage, error) {
+	workspaceAISetting, err := s.Store.GetWorkspaceAISetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ai setting: %v", err)
+	}
+	now := time.Now()
+	reservation, err := s.Store.ReserveAIUsage(ctx, &store.ReserveAIUsage{
+		Usage: &store.AIUsage{
+			UserID:       userID,
+			PromptTokens: estimatedTokens,
+			TotalTokens:  estimatedTokens,
+		},
+		Limits: store.GetAIUsageLimits(workspaceAISetting, userID, now),
+	})
+	var limitErr *store.AIUsageLimitError
+	if errors.As(err, &limitErr) {
+		owner := "the workspace"
+		if limitErr.Limit.Find.UserID != nil {
+			owner = "your"
+		}
+		quota := limitErr.Limit.Quota
+		_, windowEnd := store.GetAIQuotaWindow(quota, now)
+		return nil, status.Errorf(codes.ResourceExhausted, "%s %s AI quota is exhausted, it resets at %s",
+			owner, strings.ToLower(store.GetAIQuotaWindowType(quota).String()), windowEnd.Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reserve ai usage: %v", err)
+	}
+	return reservation, nil
+}
+
+// settleAIUsage replaces the reserved usage with the actual usage of the call, logging instead of failing on error.
+// Providers that report no usage, like the wrapper, are charged the reserved prompt estimate plus an estimate
+// of the completion, so that the token quotas still hold for them.
+func (s *APIV1Service) settleAIUsage(ctx context.Context, reservation *store.AIUsage, model string, usage ai.Usage, completion string) {
+	if usage.TotalTokens == 0 {
+		usage.PromptTokens = reservation.PromptTokens
+		usage.CompletionTokens = ai.EstimateTokens(completion)
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if err := s.Store.UpdateAIUsage(ctx, &store.UpdateAIUsage{
+		ID:               reservation.ID,
+		Model:            &model,
+		PromptTokens:     &usage.PromptTokens,
+		CompletionTokens: &usage.CompletionTokens,
+		TotalTokens:      &usage.TotalTokens,
+	}); err != nil {
+		slog.Warn("failed to record AI usage", slog.Int("userID", int(reservation.UserID)), slog.Any("err", err))
+	}
+}
+
+// releaseAIUsage drops the reservation of a call that failed, logging instead of failing on error.
+func (s *APIV1Service) releaseAIUsage(ctx context.Context, reservation *store.AIUsage) {
+	if err := s.Store.DeleteAIUsage(ctx, &store.DeleteAIUsage{ID: &reservation.ID}); err != nil {
+		slog.Warn("failed to release AI usage", slog.Int("userID", int(reservation.UserID)), slog.Any("err", err))
+	}
+}
+
+// extractOptionalUserID parses an optional user resource name, returning nil for an empty name.
+func extractOptionalUserID(name string) (*int32, error) {
+	if name == "" {
+		return nil, nil
+	}
+	userID, err := ExtractUserIDFromName(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user name: %v", err)
+	}
+	return &userID, nil
+}
@@ -16,14 +16,19 @@ import (
 const (
 	// issuer is the issuer of the jwt token.
 	Issuer = "wekalist"
-	// Signing key section. For now, this is only used for signing, not for verifying since we only
-	// have 1 version. But it will be used to maintain backward compatibility if we change the signing mechanism.
+	// KeyID is the ID of the signing key derived from the server secret, used until the first key rotation.
 	KeyID = "v1"
 	// AccessTokenAudienceName is the audience name of the access token.
 	AccessTokenAudienceName = "user.access-token"
 	// SessionSlidingDuration is the sliding expiration duration for user sessions (2 weeks).
 	// Sessions are considered valid if last_accessed_time + SessionSlidingDuration > current_time.
 	SessionSlidingDuration = 14 * 24 * time.Hour
+	// SessionAbsoluteDuration is the maximum lifetime of user sessions (30 days), however often they are used.
+	SessionAbsoluteDuration = 30 * 24 * time.Hour
+	// sessionLastAccessedInterval is how often the last accessed time of a session is recorded.
+	sessionLastAccessedInterval = time.Minute
+	// accessTokenLastUsedInterval is how often the last used time of an access token is recorded.
+	accessTokenLastUsedInterval = time.Minute
 
 	// SessionCookieName is the cookie name of user session ID.
 	SessionCookieName = "user_session"
@@ -35,18 +40,17 @@ type ClaimsMessage struct {
 }
 
 // GenerateOTP generates One Time Password
-func GenerateOTP()string{
-	otp, _:=util.RandomString(6)
+func GenerateOTP() string {
+	otp, _ := util.RandomString(6)
 	return otp
 }
 
-
 // GenerateOTPWithLength generates OTP with custom length
 func GenerateOTPWithLength(length int) string {
 	if length <= 0 {
 		length = 6
 	}
-	
+
 	otp := ""
 	for i := 0; i < length; i++ {
 		num, _ := rand.Int(rand.Reader, big.NewInt(10))
@@ -60,7 +64,7 @@ func GenerateAlphanumericOTP(length int) string {
 	if length <= 0 {
 		length = 6
 	}
-	
+
 	const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	otp := ""
 	for i := 0; i < length; i++ {
@@ -70,37 +74,23 @@ func GenerateAlphanumericOTP(length int) string {
 	return otp
 }
 
-// GenerateAccessToken generates an access token.
-func GenerateAccessToken(username string, userID int32, expirationTime time.Time, secret []byte) (string, error) {
-	return generateToken(username, userID, AccessTokenAudienceName, expirationTime, secret)
-}
-
-// generateToken generates a jwt token.
-func generateToken(username string, userID int32, audience string, expirationTime time.Time, secret []byte) (string, error) {
+// newTokenClaims returns the claims of a jwt token issued to a user, to be signed with the active signing key.
+// The ID makes access tokens issued to the same user in the same second distinct.
+func newTokenClaims(username string, userID int32, audience, id string, expirationTime time.Time) *ClaimsMessage {
 	registeredClaims := jwt.RegisteredClaims{
 		Issuer:   Issuer,
 		Audience: jwt.ClaimStrings{audience},
 		IssuedAt: jwt.NewNumericDate(time.Now()),
 		Subject:  fmt.Sprint(userID),
+		ID:       id,
 	}
 	if !expirationTime.IsZero() {
 		registeredClaims.ExpiresAt = jwt.NewNumericDate(expirationTime)
 	}
-
-	// Declare the token with the HS256 algorithm used for signing, and the claims.
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &ClaimsMessage{
+	return &ClaimsMessage{
 		Name:             username,
 		RegisteredClaims: registeredClaims,
-	})
-	token.Header["kid"] = KeyID
-
-	// Create the JWT string.
-	tokenString, err := token.SignedString(secret)
-	if err != nil {
-		return "", err
 	}
-
-	return tokenString, nil
 }
 
 // GenerateSessionID generates a unique session ID using UUIDv4.
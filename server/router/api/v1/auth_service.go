@@ -20,6 +20,7 @@ import (
 	"github.com/imrany/wekalist/internal/base"
 	"github.com/imrany/wekalist/internal/util"
 	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/ldap"
 	"github.com/imrany/wekalist/plugin/idp/oauth2"
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
 	storepb "github.com/imrany/wekalist/proto/gen/store"
@@ -42,14 +43,18 @@ func (s *APIV1Service) GetCurrentSession(ctx context.Context, _ *v1pb.GetCurrent
 	}
 
 	var lastAccessedAt *timestamppb.Timestamp
-	// Update session last accessed time if we have a session ID and get the current session info
+	// Get the current session info if we have a session ID, its last accessed time is recorded by the interceptor
 	if sessionID, ok := ctx.Value(sessionIDContextKey).(string); ok && sessionID != "" {
-		now := timestamppb.Now()
-		if err := s.Store.UpdateUserSessionLastAccessed(ctx, user.ID, sessionID, now); err != nil {
+		userSession, err := s.Store.GetUserSession(ctx, &store.FindUserSession{
+			ID:     &sessionID,
+			UserID: &user.ID,
+		})
+		if err != nil {
 			// Log error but don't fail the request
-			slog.Error("failed to update session last accessed time", "error", err)
+			slog.Error("failed to get user session", "error", err)
+		} else if userSession != nil {
+			lastAccessedAt = timestamppb.New(time.Unix(userSession.LastAccessedTs, 0))
 		}
-		lastAccessedAt = now
 	}
 
 	return &v1pb.GetCurrentSessionResponse{
@@ -60,7 +65,13 @@ func (s *APIV1Service) GetCurrentSession(ctx context.Context, _ *v1pb.GetCurrent
 
 func (s *APIV1Service) CreateSession(ctx context.Context, request *v1pb.CreateSessionRequest) (*v1pb.CreateSessionResponse, error) {
 	var existingUser *store.User
-	if passwordCredentials := request.GetPasswordCredentials(); passwordCredentials != nil {
+	if passwordCredentials := request.GetPasswordCredentials(); passwordCredentials != nil && passwordCredentials.IdpId != 0 {
+		user, err := s.signInWithLDAP(ctx, passwordCredentials)
+		if err != nil {
+			return nil, err
+		}
+		existingUser = user
+	} else if passwordCredentials != nil {
 		// Try to find user by username first
 		user, err := s.Store.GetUser(ctx, &store.FindUser{
 			Username: &passwordCredentials.Username,
@@ -123,57 +134,54 @@ func (s *APIV1Service) CreateSession(ctx context.Context, request *v1pb.CreateSe
 			if err != nil {
 				return nil, status.Errorf(codes.Internal, "failed to get user info, error: %v", err)
 			}
-		}
-
-		identifierFilter := identityProvider.IdentifierFilter
-		if identifierFilter != "" {
-			identifierFilterRegex, err := regexp.Compile(identifierFilter)
+		} else if identityProvider.Type == storepb.IdentityProvider_OIDC {
+			userInfo, err = s.getOIDCUserInfo(ctx, identityProvider, ssoCredentials)
 			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to compile identifier filter regex, error: %v", err.Error())
+				return nil, err
 			}
-			if !identifierFilterRegex.MatchString(userInfo.Identifier) {
-				return nil, status.Errorf(codes.PermissionDenied, "identifier %s is not allowed", userInfo.Identifier)
+		} else if identityProvider.Type == storepb.IdentityProvider_SAML {
+			userInfo, err = s.getSAMLUserInfo(ctx, identityProvider, ssoCredentials)
+			if err != nil {
+				return nil, err
 			}
+		} else {
+			return nil, status.Errorf(codes.InvalidArgument, "identity provider does not support sso sign-in")
 		}
 
+		user, err := s.getOrCreateIdentityProviderUser(ctx, identityProvider, userInfo)
+		if err != nil {
+			return nil, err
+		}
+		existingUser = user
+	} else if twoFactorCredentials := request.GetTwoFactorCredentials(); twoFactorCredentials != nil {
+		userID, err := s.parseTwoFactorChallenge(ctx, twoFactorCredentials.Challenge)
+		if err != nil {
+			return nil, err
+		}
 		user, err := s.Store.GetUser(ctx, &store.FindUser{
-			Username: &userInfo.Identifier,
+			ID: &userID,
 		})
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err.Error())
+			return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
 		}
 		if user == nil {
-			// Check if the user is allowed to sign up.
-			workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to get workspace general setting, error: %v", err.Error())
-			}
-			if workspaceGeneralSetting.DisallowUserRegistration {
-				return nil, status.Errorf(codes.PermissionDenied, "user registration is not allowed")
-			}
-
-			// Create a new user with the user info from the identity provider.
-			userCreate := &store.User{
-				Username: userInfo.Identifier,
-				// The new signup user should be normal user by default.
-				Role:      store.RoleUser,
-				Nickname:  userInfo.DisplayName,
-				Email:     userInfo.Email,
-				AvatarURL: userInfo.AvatarURL,
-			}
-			password, err := util.RandomString(20)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to generate random password, error: %v", err)
-			}
-			passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to generate password hash, error: %v", err)
-			}
-			userCreate.PasswordHash = string(passwordHash)
-			user, err = s.Store.CreateUser(ctx, userCreate)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to create user, error: %v", err)
-			}
+			return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+		}
+		twoFactor, err := s.Store.GetUserTwoFactor(ctx, user.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get two-factor setting, error: %v", err)
+		}
+		if !twoFactor.GetEnabled() {
+			return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+		}
+		if err := s.validateTwoFactorCode(ctx, user.ID, twoFactor, twoFactorCredentials.Code); err != nil {
+			return nil, err
+		}
+		existingUser = user
+	} else if passkeyCredentials := request.GetPasskeyCredentials(); passkeyCredentials != nil {
+		user, err := s.validatePasskeyAssertion(ctx, passkeyCredentials)
+		if err != nil {
+			return nil, err
 		}
 		existingUser = user
 	}
@@ -185,18 +193,141 @@ func (s *APIV1Service) CreateSession(ctx context.Context, request *v1pb.CreateSe
 		return nil, status.Errorf(codes.PermissionDenied, "This account has been archived")
 	}
 
-	// Default session expiration time is 100 year
-	expireTime := time.Now().Add(100 * 365 * 24 * time.Hour)
+	twoFactorSetupRequired := false
+	if request.GetTwoFactorCredentials() == nil {
+		twoFactor, err := s.Store.GetUserTwoFactor(ctx, existingUser.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get two-factor setting, error: %v", err)
+		}
+		// Ask for the second factor before creating a session.
+		// Passkeys already verify the user on the authenticator and count as both factors.
+		if twoFactor.GetEnabled() && request.GetPasskeyCredentials() == nil {
+			challenge, err := s.generateTwoFactorChallenge(ctx, existingUser)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to generate two-factor challenge, error: %v", err)
+			}
+			return &v1pb.CreateSessionResponse{
+				TwoFactorChallenge: challenge,
+			}, nil
+		}
+		workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace general setting, error: %v", err)
+		}
+		twoFactorSetupRequired = workspaceGeneralSetting.RequireTwoFactor && !twoFactor.GetEnabled()
+	}
+
+	// The session cookie lasts as long as the session can.
+	expireTime := time.Now().Add(SessionAbsoluteDuration)
 	if err := s.doSignIn(ctx, existingUser, expireTime); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to sign in, error: %v", err)
 	}
 
 	return &v1pb.CreateSessionResponse{
-		User:           convertUserFromStore(existingUser),
-		LastAccessedAt: timestamppb.Now(),
+		User:                   convertUserFromStore(existingUser),
+		LastAccessedAt:         timestamppb.Now(),
+		TwoFactorSetupRequired: twoFactorSetupRequired,
 	}, nil
 }
 
+// getOrCreateIdentityProviderUser returns the user signed in with an identity provider,
+// creating it from the user info on first sign-in when registration is allowed.
+func (s *APIV1Service) getOrCreateIdentityProviderUser(ctx context.Context, identityProvider *storepb.IdentityProvider, userInfo *idp.IdentityProviderUserInfo) (*store.User, error) {
+	identifierFilter := identityProvider.IdentifierFilter
+	if identifierFilter != "" {
+		identifierFilterRegex, err := regexp.Compile(identifierFilter)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to compile identifier filter regex, error: %v", err.Error())
+		}
+		if !identifierFilterRegex.MatchString(userInfo.Identifier) {
+			return nil, status.Errorf(codes.PermissionDenied, "identifier %s is not allowed", userInfo.Identifier)
+		}
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		Username: &userInfo.Identifier,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err.Error())
+	}
+	if user == nil {
+		// Check if the user is allowed to sign up.
+		workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace general setting, error: %v", err.Error())
+		}
+		if workspaceGeneralSetting.DisallowUserRegistration {
+			return nil, status.Errorf(codes.PermissionDenied, "user registration is not allowed")
+		}
+
+		// Create a new user with the user info from the identity provider.
+		userCreate := &store.User{
+			Username: userInfo.Identifier,
+			// The new signup user should be normal user by default.
+			Role:      store.RoleUser,
+			Nickname:  userInfo.DisplayName,
+			Email:     userInfo.Email,
+			AvatarURL: userInfo.AvatarURL,
+		}
+		password, err := util.RandomString(20)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate random password, error: %v", err)
+		}
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate password hash, error: %v", err)
+		}
+		userCreate.PasswordHash = string(passwordHash)
+		user, err = s.Store.CreateUser(ctx, userCreate)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create user, error: %v", err)
+		}
+	}
+	return user, nil
+}
+
+// signInWithLDAP checks the password against an LDAP identity provider and returns the user,
+// syncing its role from the directory groups when group role mappings are configured.
+func (s *APIV1Service) signInWithLDAP(ctx context.Context, credentials *v1pb.CreateSessionRequest_PasswordCredentials) (*store.User, error) {
+	identityProvider, err := s.Store.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &credentials.IdpId,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get identity provider, error: %v", err)
+	}
+	if identityProvider == nil || identityProvider.Type != storepb.IdentityProvider_LDAP {
+		return nil, status.Errorf(codes.InvalidArgument, "ldap identity provider not found")
+	}
+	ldapIdentityProvider, err := ldap.NewIdentityProvider(identityProvider.Config.GetLdapConfig())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create ldap identity provider, error: %v", err)
+	}
+	userInfo, err := ldapIdentityProvider.Authenticate(credentials.Username, credentials.Password)
+	if err != nil {
+		if errors.Is(err, ldap.ErrInvalidCredentials) {
+			return nil, status.Errorf(codes.InvalidArgument, unmatchedUsernameAndPasswordError)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to authenticate with ldap, error: %v", err)
+	}
+
+	user, err := s.getOrCreateIdentityProviderUser(ctx, identityProvider, userInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The host role is never managed by the directory.
+	if role, ok := ldapIdentityProvider.MapRole(userInfo.Groups); ok && user.Role != store.RoleHost && user.Role != store.Role(role) {
+		newRole := store.Role(role)
+		user, err = s.Store.UpdateUser(ctx, &store.UpdateUser{
+			ID:   user.ID,
+			Role: &newRole,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update user role, error: %v", err)
+		}
+	}
+	return user, nil
+}
+
 func (s *APIV1Service) doSignIn(ctx context.Context, user *store.User, expireTime time.Time) error {
 	// Generate unique session ID for web use
 	sessionID, err := GenerateSessionID()
@@ -204,11 +335,9 @@ func (s *APIV1Service) doSignIn(ctx context.Context, user *store.User, expireTim
 		return status.Errorf(codes.Internal, "failed to generate session ID, error: %v", err)
 	}
 
-	// Track session in user settings
-	if err := s.trackUserSession(ctx, user.ID, sessionID); err != nil {
-		// Log the error but don't fail the login if session tracking fails
-		// This ensures backward compatibility
-		slog.Error("failed to track user session", "error", err)
+	// Track session, which the session cookie is only valid with
+	if err := s.trackUserSession(ctx, user.ID, sessionID, expireTime); err != nil {
+		return status.Errorf(codes.Internal, "failed to track user session, error: %v", err)
 	}
 
 	// Set session cookie for web use (format: userID-sessionID)
@@ -237,8 +366,11 @@ func (s *APIV1Service) DeleteSession(ctx context.Context, _ *v1pb.DeleteSessionR
 
 	// Check if we have a session ID (from cookie-based auth)
 	if sessionID, ok := ctx.Value(sessionIDContextKey).(string); ok && sessionID != "" {
-		// Remove session from user settings
-		if err := s.Store.RemoveUserSession(ctx, user.ID, sessionID); err != nil {
+		// Remove session
+		if _, err := s.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{
+			ID:     &sessionID,
+			UserID: &user.ID,
+		}); err != nil {
 			slog.Error("failed to remove user session", "error", err)
 		}
 	}
@@ -249,6 +381,74 @@ func (s *APIV1Service) DeleteSession(ctx context.Context, _ *v1pb.DeleteSessionR
 	return &emptypb.Empty{}, nil
 }
 
+// RequestPasswordReset sends a password reset code to the email of an active account.
+func (s *APIV1Service) RequestPasswordReset(ctx context.Context, request *v1pb.RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	email := strings.TrimSpace(request.Email)
+	if !base.EMAILMatcher.MatchString(strings.ToLower(email)) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid email: %s", request.Email)
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		Email: &email,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	// Don't reveal whether an account exists for the email.
+	if user == nil || user.RowStatus == store.Archived {
+		return &emptypb.Empty{}, nil
+	}
+
+	// Neither are failures to send the code, such as a throttled resend or a missing SMTP setting.
+	if err := s.sendOTP(ctx, OtpPurposePasswordReset, user.Email, s.getEmailLocale(ctx, user)); err != nil {
+		slog.Warn("Failed to send password reset code", slog.Int("user", int(user.ID)), slog.Any("err", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ConfirmPasswordReset sets a new password using a reset code and revokes all sessions and access tokens of the user.
+func (s *APIV1Service) ConfirmPasswordReset(ctx context.Context, request *v1pb.ConfirmPasswordResetRequest) (*emptypb.Empty, error) {
+	email := strings.TrimSpace(request.Email)
+	if email == "" || request.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "email and code are required")
+	}
+	if request.NewPassword == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "new password is required")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		Email: &email,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if user == nil || user.RowStatus == store.Archived {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid verification code")
+	}
+	if err := s.verifyOTP(ctx, OtpPurposePasswordReset, user.Email, request.Code); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate password hash, error: %v", err)
+	}
+	passwordHashStr := string(passwordHash)
+	if _, err := s.Store.UpdateUser(ctx, &store.UpdateUser{
+		ID:           user.ID,
+		PasswordHash: &passwordHashStr,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user, error: %v", err)
+	}
+
+	// Sign the user out everywhere, since the old password may have been compromised.
+	if _, err := s.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{UserID: &user.ID}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke user sessions, error: %v", err)
+	}
+	if err := s.Store.RemoveAllUserAccessTokens(ctx, user.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke user access tokens, error: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
 func (s *APIV1Service) clearAuthCookies(ctx context.Context) error {
 	// Clear session cookie
 	sessionCookie, err := s.buildSessionCookie(ctx, "", time.Time{})
@@ -313,18 +513,20 @@ func (s *APIV1Service) GetCurrentUser(ctx context.Context) (*store.User, error)
 }
 
 // Helper function to track user session for session management.
-func (s *APIV1Service) trackUserSession(ctx context.Context, userID int32, sessionID string) error {
+func (s *APIV1Service) trackUserSession(ctx context.Context, userID int32, sessionID string, expireTime time.Time) error {
 	// Extract client information from the context
 	clientInfo := s.extractClientInfo(ctx)
 
-	session := &storepb.SessionsUserSetting_Session{
-		SessionId:        sessionID,
-		CreateTime:       timestamppb.Now(),
-		LastAccessedTime: timestamppb.Now(),
-		ClientInfo:       clientInfo,
-	}
-
-	return s.Store.AddUserSession(ctx, userID, session)
+	now := time.Now().Unix()
+	_, err := s.Store.CreateUserSession(ctx, &store.UserSession{
+		ID:             sessionID,
+		UserID:         userID,
+		CreatedTs:      now,
+		LastAccessedTs: now,
+		ExpiresTs:      expireTime.Unix(),
+		ClientInfo:     clientInfo,
+	})
+	return err
 }
 
 // Helper function to extract client information from the gRPC context.
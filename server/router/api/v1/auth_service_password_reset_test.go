@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+
+	user, err := testStore.CreateUser(ctx, &store.User{Username: "user", Role: store.RoleUser, Email: "user@example.com", PasswordHash: "old"})
+	require.NoError(t, err)
+	_, err = testStore.CreateUserSession(ctx, &store.UserSession{
+		ID:             "session",
+		UserID:         user.ID,
+		CreatedTs:      time.Now().Unix(),
+		LastAccessedTs: time.Now().Unix(),
+		ExpiresTs:      time.Now().Add(SessionAbsoluteDuration).Unix(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, testStore.UpsertUserAccessToken(ctx, user.ID, &storepb.AccessTokensUserSetting_AccessToken{
+		Id:        "token",
+		TokenHash: store.HashAccessToken("token"),
+	}))
+
+	t.Run("does not reveal unknown emails", func(t *testing.T) {
+		_, err := service.RequestPasswordReset(ctx, &v1pb.RequestPasswordResetRequest{Email: "nobody@example.com"})
+		require.NoError(t, err)
+		_, err = service.RequestPasswordReset(ctx, &v1pb.RequestPasswordResetRequest{Email: "not-an-email"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		// Failing to send the code, here without SMTP, looks the same as an unknown email.
+		_, err = service.RequestPasswordReset(ctx, &v1pb.RequestPasswordResetRequest{Email: "user@example.com"})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects codes for other purposes", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposeVerification, "user@example.com")
+		require.NoError(t, err)
+		_, err = service.ConfirmPasswordReset(ctx, &v1pb.ConfirmPasswordResetRequest{Email: "user@example.com", Code: code, NewPassword: "new-password"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("resets the password and revokes credentials", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposePasswordReset, "user@example.com")
+		require.NoError(t, err)
+		request := &v1pb.ConfirmPasswordResetRequest{Email: "user@example.com", Code: code, NewPassword: "new-password"}
+		_, err = service.ConfirmPasswordReset(ctx, request)
+		require.NoError(t, err)
+
+		updatedUser, err := testStore.GetUser(ctx, &store.FindUser{ID: &user.ID})
+		require.NoError(t, err)
+		require.NoError(t, bcrypt.CompareHashAndPassword([]byte(updatedUser.PasswordHash), []byte("new-password")))
+		sessions, err := testStore.ListUserSessions(ctx, &store.FindUserSession{UserID: &user.ID})
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+		accessTokens, err := testStore.GetUserAccessTokens(ctx, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, accessTokens)
+
+		// Codes are single use.
+		_, err = service.ConfirmPasswordReset(ctx, request)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/internal/util"
+	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/oidc"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// SSOCeremonyAudienceName is the audience name of the SSO authorization ceremony token.
+	SSOCeremonyAudienceName = "user.sso-ceremony"
+	// ssoCeremonyDuration is how long the user has to sign in with the identity provider.
+	ssoCeremonyDuration = 10 * time.Minute
+)
+
+// ssoCeremonyClaims carries the PKCE code verifier and the nonce of an authorization request
+// until the authorization code is exchanged in CreateSession.
+type ssoCeremonyClaims struct {
+	IdpID        int32  `json:"idp_id"`
+	RedirectURI  string `json:"redirect_uri"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// BeginSSOAuthorization returns the authorization URL of an OpenID Connect identity provider,
+// along with a ceremony token holding the PKCE code verifier and the nonce of the request.
+func (s *APIV1Service) BeginSSOAuthorization(ctx context.Context, request *v1pb.BeginSSOAuthorizationRequest) (*v1pb.BeginSSOAuthorizationResponse, error) {
+	if request.RedirectUri == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "redirect uri is required")
+	}
+	identityProvider, err := s.Store.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &request.IdpId,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get identity provider, error: %v", err)
+	}
+	if identityProvider == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "identity provider not found")
+	}
+	if identityProvider.Type != storepb.IdentityProvider_OIDC {
+		return nil, status.Errorf(codes.InvalidArgument, "identity provider does not support authorization ceremonies")
+	}
+	oidcIdentityProvider, err := oidc.NewIdentityProvider(ctx, identityProvider.Config.GetOidcConfig())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create oidc identity provider, error: %v", err)
+	}
+
+	nonce, err := util.RandomString(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate nonce, error: %v", err)
+	}
+	claims := &ssoCeremonyClaims{
+		IdpID:        identityProvider.Id,
+		RedirectURI:  request.RedirectUri,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{SSOCeremonyAudienceName},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ssoCeremonyDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	ceremonyToken, err := s.signJWT(ctx, claims)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate ceremony token, error: %v", err)
+	}
+	return &v1pb.BeginSSOAuthorizationResponse{
+		AuthorizationUrl: oidcIdentityProvider.AuthCodeURL(request.RedirectUri, request.State, claims.CodeVerifier, claims.Nonce),
+		CeremonyToken:    ceremonyToken,
+	}, nil
+}
+
+// getOIDCUserInfo exchanges the authorization code of an OpenID Connect identity provider
+// and returns the user information from the verified ID token.
+func (s *APIV1Service) getOIDCUserInfo(ctx context.Context, identityProvider *storepb.IdentityProvider, credentials *v1pb.CreateSessionRequest_SSOCredentials) (*idp.IdentityProviderUserInfo, error) {
+	claims := &ssoCeremonyClaims{}
+	if err := s.parseJWT(ctx, credentials.CeremonyToken, claims, jwt.WithAudience(SSOCeremonyAudienceName), jwt.WithExpirationRequired()); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired ceremony token")
+	}
+	if claims.IdpID != identityProvider.Id || claims.RedirectURI != credentials.RedirectUri {
+		return nil, status.Errorf(codes.InvalidArgument, "ceremony token was issued for another authorization request")
+	}
+
+	oidcIdentityProvider, err := oidc.NewIdentityProvider(ctx, identityProvider.Config.GetOidcConfig())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create oidc identity provider, error: %v", err)
+	}
+	idToken, accessToken, err := oidcIdentityProvider.ExchangeToken(ctx, credentials.RedirectUri, credentials.Code, claims.CodeVerifier)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to exchange token, error: %v", err)
+	}
+	userInfo, err := oidcIdentityProvider.UserInfo(ctx, idToken, accessToken, claims.Nonce)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to get user info, error: %v", err)
+	}
+	return userInfo, nil
+}
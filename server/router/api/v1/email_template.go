@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/mail"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+// emailTemplates are the built-in email templates.
+var emailTemplates = mail.NewRegistry()
+
+// validateEmailTemplates checks that the templates of the setting parse, with one template per name and locale.
+func validateEmailTemplates(setting *storepb.WorkspaceEmailTemplatesSetting) error {
+	templates := map[string]bool{}
+	for _, source := range setting.GetTemplates() {
+		template, err := mail.ParseTemplate(convertEmailTemplateSource(source))
+		if err != nil {
+			return err
+		}
+		key := template.Name + "/" + template.Locale
+		if templates[key] {
+			return errors.Errorf("duplicate email template %q for locale %q", template.Name, template.Locale)
+		}
+		templates[key] = true
+	}
+	return nil
+}
+
+// getEmailTemplates returns the built-in email templates, overridden by the templates of the workspace setting.
+func (s *APIV1Service) getEmailTemplates(ctx context.Context) (*mail.Registry, error) {
+	setting, err := s.Store.GetWorkspaceEmailTemplatesSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(setting.Templates) == 0 {
+		return emailTemplates, nil
+	}
+	overrides := make([]*mail.Template, 0, len(setting.Templates))
+	for _, source := range setting.Templates {
+		template, err := mail.ParseTemplate(convertEmailTemplateSource(source))
+		if err != nil {
+			slog.Warn("Failed to parse email template", slog.String("name", source.Name), slog.String("locale", source.Locale), slog.Any("err", err))
+			continue
+		}
+		overrides = append(overrides, template)
+	}
+	return emailTemplates.WithOverrides(overrides), nil
+}
+
+// getEmailLocale returns the locale of the emails to the user, or of the workspace if the user is nil
+// or has no locale.
+func (s *APIV1Service) getEmailLocale(ctx context.Context, user *store.User) string {
+	if user != nil {
+		userSetting, err := s.Store.GetUserSetting(ctx, &store.FindUserSetting{
+			UserID: &user.ID,
+			Key:    storepb.UserSetting_GENERAL,
+		})
+		if err != nil {
+			slog.Warn("Failed to get user general setting", slog.Int("user", int(user.ID)), slog.Any("err", err))
+		} else if locale := userSetting.GetGeneral().GetLocale(); locale != "" {
+			return locale
+		}
+	}
+	workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		slog.Warn("Failed to get workspace general setting", slog.Any("err", err))
+		return mail.DefaultLocale
+	}
+	if locale := workspaceGeneralSetting.GetCustomProfile().GetLocale(); locale != "" {
+		return locale
+	}
+	return mail.DefaultLocale
+}
+
+// renderEmail renders the email template of the name for the locale. An email template of the workspace
+// failing to render falls back to the built-in one, so a broken template does not stop the emails.
+func (s *APIV1Service) renderEmail(ctx context.Context, name, locale string, data any) (*mail.Message, error) {
+	templates, err := s.getEmailTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	message, err := templates.Render(name, locale, data)
+	if err != nil && templates != emailTemplates {
+		slog.Warn("Failed to render email template, using the built-in one", slog.String("name", name), slog.String("locale", locale), slog.Any("err", err))
+		message, err = emailTemplates.Render(name, locale, data)
+	}
+	return message, err
+}
+
+// sendTemplateEmail renders the email template of the name for the locale and queues it for the address.
+func (s *APIV1Service) sendTemplateEmail(ctx context.Context, to, locale, name string, data any, headers map[string]string) error {
+	message, err := s.renderEmail(ctx, name, locale, data)
+	if err != nil {
+		return err
+	}
+	message.To = []string{to}
+	message.Headers = headers
+	return s.queueEmail(ctx, message)
+}
+
+// SendUserEmail queues the email template of the name for the user, in the locale of the user.
+func (s *APIV1Service) SendUserEmail(ctx context.Context, user *store.User, name string, data any) error {
+	return s.sendTemplateEmail(ctx, user.Email, s.getEmailLocale(ctx, user), name, data, nil)
+}
+
+func convertEmailTemplateSource(template *storepb.EmailTemplate) *mail.TemplateSource {
+	return &mail.TemplateSource{
+		Name:    template.Name,
+		Locale:  template.Locale,
+		Subject: template.Subject,
+		Text:    template.TextBody,
+		HTML:    template.HtmlBody,
+	}
+}
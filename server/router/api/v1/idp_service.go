@@ -35,11 +35,19 @@ func (s *APIV1Service) ListIdentityProviders(ctx context.Context, _ *v1pb.ListId
 		return nil, status.Errorf(codes.Internal, "failed to list identity providers, error: %+v", err)
 	}
 
+	isHost, err := s.isCurrentUserHost(ctx)
+	if err != nil {
+		return nil, err
+	}
 	response := &v1pb.ListIdentityProvidersResponse{
 		IdentityProviders: []*v1pb.IdentityProvider{},
 	}
 	for _, identityProvider := range identityProviders {
-		response.IdentityProviders = append(response.IdentityProviders, convertIdentityProviderFromStore(identityProvider))
+		identityProviderMessage := convertIdentityProviderFromStore(identityProvider)
+		if !isHost {
+			redactIdentityProviderCredentials(identityProviderMessage)
+		}
+		response.IdentityProviders = append(response.IdentityProviders, identityProviderMessage)
 	}
 	return response, nil
 }
@@ -58,7 +66,15 @@ func (s *APIV1Service) GetIdentityProvider(ctx context.Context, request *v1pb.Ge
 	if identityProvider == nil {
 		return nil, status.Errorf(codes.NotFound, "identity provider not found")
 	}
-	return convertIdentityProviderFromStore(identityProvider), nil
+	isHost, err := s.isCurrentUserHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identityProviderMessage := convertIdentityProviderFromStore(identityProvider)
+	if !isHost {
+		redactIdentityProviderCredentials(identityProviderMessage)
+	}
+	return identityProviderMessage, nil
 }
 
 func (s *APIV1Service) UpdateIdentityProvider(ctx context.Context, request *v1pb.UpdateIdentityProviderRequest) (*v1pb.IdentityProvider, error) {
@@ -113,6 +129,22 @@ func (s *APIV1Service) DeleteIdentityProvider(ctx context.Context, request *v1pb
 	return &emptypb.Empty{}, nil
 }
 
+func (s *APIV1Service) isCurrentUserHost(ctx context.Context) (bool, error) {
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	return currentUser != nil && currentUser.Role == store.RoleHost, nil
+}
+
+// redactIdentityProviderCredentials removes the credentials only needed by the server,
+// since identity providers are listed on the sign-in page.
+func redactIdentityProviderCredentials(identityProvider *v1pb.IdentityProvider) {
+	if ldapConfig := identityProvider.GetConfig().GetLdapConfig(); ldapConfig != nil {
+		ldapConfig.BindPassword = ""
+	}
+}
+
 func convertIdentityProviderFromStore(identityProvider *storepb.IdentityProvider) *v1pb.IdentityProvider {
 	temp := &v1pb.IdentityProvider{
 		Name:             fmt.Sprintf("%s%d", IdentityProviderNamePrefix, identityProvider.Id),
@@ -140,6 +172,69 @@ func convertIdentityProviderFromStore(identityProvider *storepb.IdentityProvider
 				},
 			},
 		}
+	} else if identityProvider.Type == storepb.IdentityProvider_OIDC {
+		oidcConfig := identityProvider.Config.GetOidcConfig()
+		temp.Config = &v1pb.IdentityProviderConfig{
+			Config: &v1pb.IdentityProviderConfig_OidcConfig{
+				OidcConfig: &v1pb.OIDCConfig{
+					IssuerUrl:    oidcConfig.IssuerUrl,
+					ClientId:     oidcConfig.ClientId,
+					ClientSecret: oidcConfig.ClientSecret,
+					Scopes:       oidcConfig.Scopes,
+					FieldMapping: &v1pb.FieldMapping{
+						Identifier:  oidcConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: oidcConfig.GetFieldMapping().GetDisplayName(),
+						Email:       oidcConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   oidcConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+				},
+			},
+		}
+	} else if identityProvider.Type == storepb.IdentityProvider_LDAP {
+		ldapConfig := identityProvider.Config.GetLdapConfig()
+		groupRoleMappings := []*v1pb.LDAPConfig_GroupRoleMapping{}
+		for _, mapping := range ldapConfig.GroupRoleMappings {
+			groupRoleMappings = append(groupRoleMappings, &v1pb.LDAPConfig_GroupRoleMapping{Group: mapping.Group, Role: mapping.Role})
+		}
+		temp.Config = &v1pb.IdentityProviderConfig{
+			Config: &v1pb.IdentityProviderConfig_LdapConfig{
+				LdapConfig: &v1pb.LDAPConfig{
+					Url:                ldapConfig.Url,
+					StartTls:           ldapConfig.StartTls,
+					InsecureSkipVerify: ldapConfig.InsecureSkipVerify,
+					BindDn:             ldapConfig.BindDn,
+					BindPassword:       ldapConfig.BindPassword,
+					BaseDn:             ldapConfig.BaseDn,
+					UserFilter:         ldapConfig.UserFilter,
+					FieldMapping: &v1pb.FieldMapping{
+						Identifier:  ldapConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: ldapConfig.GetFieldMapping().GetDisplayName(),
+						Email:       ldapConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   ldapConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+					GroupAttribute:    ldapConfig.GroupAttribute,
+					GroupRoleMappings: groupRoleMappings,
+				},
+			},
+		}
+	} else if identityProvider.Type == storepb.IdentityProvider_SAML {
+		samlConfig := identityProvider.Config.GetSamlConfig()
+		temp.Config = &v1pb.IdentityProviderConfig{
+			Config: &v1pb.IdentityProviderConfig_SamlConfig{
+				SamlConfig: &v1pb.SAMLConfig{
+					IdpEntityId:    samlConfig.IdpEntityId,
+					IdpSsoUrl:      samlConfig.IdpSsoUrl,
+					IdpCertificate: samlConfig.IdpCertificate,
+					SpEntityId:     samlConfig.SpEntityId,
+					FieldMapping: &v1pb.FieldMapping{
+						Identifier:  samlConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: samlConfig.GetFieldMapping().GetDisplayName(),
+						Email:       samlConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   samlConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+				},
+			},
+		}
 	}
 	return temp
 }
@@ -178,6 +273,69 @@ func convertIdentityProviderConfigToStore(identityProviderType v1pb.IdentityProv
 				},
 			},
 		}
+	} else if identityProviderType == v1pb.IdentityProvider_OIDC {
+		oidcConfig := config.GetOidcConfig()
+		return &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_OidcConfig{
+				OidcConfig: &storepb.OIDCConfig{
+					IssuerUrl:    oidcConfig.GetIssuerUrl(),
+					ClientId:     oidcConfig.GetClientId(),
+					ClientSecret: oidcConfig.GetClientSecret(),
+					Scopes:       oidcConfig.GetScopes(),
+					FieldMapping: &storepb.FieldMapping{
+						Identifier:  oidcConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: oidcConfig.GetFieldMapping().GetDisplayName(),
+						Email:       oidcConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   oidcConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+				},
+			},
+		}
+	} else if identityProviderType == v1pb.IdentityProvider_LDAP {
+		ldapConfig := config.GetLdapConfig()
+		groupRoleMappings := []*storepb.LDAPConfig_GroupRoleMapping{}
+		for _, mapping := range ldapConfig.GetGroupRoleMappings() {
+			groupRoleMappings = append(groupRoleMappings, &storepb.LDAPConfig_GroupRoleMapping{Group: mapping.Group, Role: mapping.Role})
+		}
+		return &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_LdapConfig{
+				LdapConfig: &storepb.LDAPConfig{
+					Url:                ldapConfig.GetUrl(),
+					StartTls:           ldapConfig.GetStartTls(),
+					InsecureSkipVerify: ldapConfig.GetInsecureSkipVerify(),
+					BindDn:             ldapConfig.GetBindDn(),
+					BindPassword:       ldapConfig.GetBindPassword(),
+					BaseDn:             ldapConfig.GetBaseDn(),
+					UserFilter:         ldapConfig.GetUserFilter(),
+					FieldMapping: &storepb.FieldMapping{
+						Identifier:  ldapConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: ldapConfig.GetFieldMapping().GetDisplayName(),
+						Email:       ldapConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   ldapConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+					GroupAttribute:    ldapConfig.GetGroupAttribute(),
+					GroupRoleMappings: groupRoleMappings,
+				},
+			},
+		}
+	} else if identityProviderType == v1pb.IdentityProvider_SAML {
+		samlConfig := config.GetSamlConfig()
+		return &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_SamlConfig{
+				SamlConfig: &storepb.SAMLConfig{
+					IdpEntityId:    samlConfig.GetIdpEntityId(),
+					IdpSsoUrl:      samlConfig.GetIdpSsoUrl(),
+					IdpCertificate: samlConfig.GetIdpCertificate(),
+					SpEntityId:     samlConfig.GetSpEntityId(),
+					FieldMapping: &storepb.FieldMapping{
+						Identifier:  samlConfig.GetFieldMapping().GetIdentifier(),
+						DisplayName: samlConfig.GetFieldMapping().GetDisplayName(),
+						Email:       samlConfig.GetFieldMapping().GetEmail(),
+						AvatarUrl:   samlConfig.GetFieldMapping().GetAvatarUrl(),
+					},
+				},
+			},
+		}
 	}
 	return nil
 }
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/mail"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// mailQueueInterval is how often the mail queue looks for emails due to be sent again.
+	mailQueueInterval = time.Minute
+	// mailQueueBatchSize is the number of emails sent at a time.
+	mailQueueBatchSize = 20
+	// maxMailAttempts is the number of attempts to send an email before giving up on it.
+	maxMailAttempts = 8
+	// mailRetryBackoff is the delay before the second attempt, doubled after each failed attempt up to maxMailRetryBackoff.
+	mailRetryBackoff    = 30 * time.Second
+	maxMailRetryBackoff = time.Hour
+	// outboundMailRetention is how long sent and failed emails are kept.
+	outboundMailRetention = 30 * 24 * time.Hour
+)
+
+// MailQueue sends the emails of the outbound mail queue in the background, retrying failed emails with an
+// exponential backoff. Emails are stored before being sent, so they survive a restart or an SMTP outage.
+type MailQueue struct {
+	store     *store.Store
+	getConfig func(ctx context.Context) (*mail.SMTPConfig, error)
+
+	wake      chan struct{}
+	startOnce sync.Once
+}
+
+func NewMailQueue(store *store.Store, getConfig func(ctx context.Context) (*mail.SMTPConfig, error)) *MailQueue {
+	return &MailQueue{
+		store:     store,
+		getConfig: getConfig,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Wake makes the mail queue look for emails to send right away.
+func (q *MailQueue) Wake() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends the emails once due until the context is done, and prunes the emails sent or failed long ago.
+func (q *MailQueue) Start(ctx context.Context) {
+	q.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(mailQueueInterval)
+			defer ticker.Stop()
+			lastPruneTime := time.Time{}
+			for {
+				now := time.Now()
+				if q.sendDue(ctx, now) {
+					q.Wake()
+				}
+				if now.Sub(lastPruneTime) >= time.Hour {
+					q.prune(ctx, now)
+					lastPruneTime = now
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				case <-q.wake:
+				}
+			}
+		}()
+	})
+}
+
+// sendDue sends the pending emails due by the time, and reports whether more emails may be due.
+func (q *MailQueue) sendDue(ctx context.Context, now time.Time) bool {
+	config, err := q.getConfig(ctx)
+	if err != nil {
+		slog.Warn("Failed to get smtp configuration", slog.Any("err", err))
+		return false
+	}
+	// Emails wait in the queue, without using up their attempts, until an admin configures SMTP.
+	if err := config.Validate(); err != nil {
+		return false
+	}
+
+	status, dueTs, limit := store.OutboundMailPending, now.Unix(), mailQueueBatchSize
+	outboundMails, err := q.store.ListOutboundMails(ctx, &store.FindOutboundMail{
+		Status: &status,
+		DueTs:  &dueTs,
+		Limit:  &limit,
+	})
+	if err != nil {
+		slog.Warn("Failed to list outbound mails", slog.Any("err", err))
+		return false
+	}
+	for _, outboundMail := range outboundMails {
+		if ctx.Err() != nil {
+			return false
+		}
+		q.send(ctx, config, outboundMail, now)
+	}
+	return len(outboundMails) == limit
+}
+
+// send makes an attempt to send the email, and schedules the next attempt if it fails temporarily.
+func (q *MailQueue) send(ctx context.Context, config *mail.SMTPConfig, outboundMail *store.OutboundMail, now time.Time) {
+	err := mail.Send(ctx, config, convertOutboundMailMessageFromStore(outboundMail.Message))
+	attempts := outboundMail.Attempts + 1
+	update := &store.UpdateOutboundMail{ID: outboundMail.ID, Attempts: &attempts}
+	errorMessage := ""
+	switch {
+	case err == nil:
+		status := store.OutboundMailSent
+		update.Status = &status
+	case mail.IsPermanent(err) || attempts >= maxMailAttempts:
+		slog.Warn("Failed to send email", slog.Int("id", int(outboundMail.ID)), slog.Int("attempts", int(attempts)), slog.Any("err", err))
+		status := store.OutboundMailFailed
+		update.Status = &status
+		errorMessage = err.Error()
+	default:
+		nextAttemptTs := now.Add(getMailRetryBackoff(attempts)).Unix()
+		update.NextAttemptTs = &nextAttemptTs
+		errorMessage = err.Error()
+	}
+	update.Error = &errorMessage
+	if err := q.store.UpdateOutboundMail(ctx, update); err != nil {
+		slog.Warn("Failed to update outbound mail", slog.Int("id", int(outboundMail.ID)), slog.Any("err", err))
+	}
+}
+
+// prune deletes the emails sent or failed before the retention.
+func (q *MailQueue) prune(ctx context.Context, now time.Time) {
+	createdTsBefore := now.Add(-outboundMailRetention).Unix()
+	for _, status := range []store.OutboundMailStatus{store.OutboundMailSent, store.OutboundMailFailed} {
+		if _, err := q.store.DeleteOutboundMails(ctx, &store.DeleteOutboundMail{Status: &status, CreatedTsBefore: &createdTsBefore}); err != nil {
+			slog.Warn("Failed to delete outbound mails", slog.String("status", string(status)), slog.Any("err", err))
+		}
+	}
+}
+
+// getMailRetryBackoff returns the delay before the next attempt to send an email after the failed attempts.
+func getMailRetryBackoff(attempts int32) time.Duration {
+	backoff := mailRetryBackoff
+	for i := int32(1); i < attempts && backoff < maxMailRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxMailRetryBackoff)
+}
+
+// queueEmail stores the message in the outbound mail queue and wakes the mail queue to send it.
+func (s *APIV1Service) queueEmail(ctx context.Context, message *mail.Message) error {
+	if _, err := s.Store.CreateOutboundMail(ctx, &store.OutboundMail{
+		Status:        store.OutboundMailPending,
+		Message:       convertOutboundMailMessageToStore(message),
+		NextAttemptTs: time.Now().Unix(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to queue email")
+	}
+	if s.MailQueue != nil {
+		s.MailQueue.Wake()
+	}
+	return nil
+}
+
+func convertOutboundMailMessageToStore(message *mail.Message) *storepb.OutboundMailMessage {
+	return &storepb.OutboundMailMessage{
+		To:       message.To,
+		Subject:  message.Subject,
+		TextBody: message.TextBody,
+		HtmlBody: message.HTMLBody,
+		Headers:  message.Headers,
+	}
+}
+
+func convertOutboundMailMessageFromStore(message *storepb.OutboundMailMessage) *mail.Message {
+	return &mail.Message{
+		To:       message.To,
+		Subject:  message.Subject,
+		TextBody: message.TextBody,
+		HTMLBody: message.HtmlBody,
+		Headers:  message.Headers,
+	}
+}
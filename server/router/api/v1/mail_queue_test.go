@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/plugin/mail"
+	"github.com/imrany/wekalist/plugin/mail/smtptest"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestMailQueue(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+	queue := NewMailQueue(testStore, service.getMailConfig)
+	server := smtptest.NewServer(smtptest.ModePlain)
+	defer server.Close()
+
+	getOutboundMail := func(id int32) *store.OutboundMail {
+		outboundMail, err := testStore.GetOutboundMail(ctx, &store.FindOutboundMail{ID: &id})
+		require.NoError(t, err)
+		require.NotNil(t, outboundMail)
+		return outboundMail
+	}
+	queueEmail := func(to string) *store.OutboundMail {
+		require.NoError(t, service.queueEmail(ctx, &mail.Message{To: []string{to}, Subject: "Hello", TextBody: "Hello", HTMLBody: "<p>Hello</p>"}))
+		outboundMails, err := testStore.ListOutboundMails(ctx, &store.FindOutboundMail{})
+		require.NoError(t, err)
+		outboundMail := slices.MaxFunc(outboundMails, func(a, b *store.OutboundMail) int { return cmp.Compare(a.ID, b.ID) })
+		require.Equal(t, []string{to}, outboundMail.Message.To)
+		return outboundMail
+	}
+
+	// Emails wait until SMTP is configured, without using up their attempts.
+	waiting := queueEmail("waiting@example.com")
+	require.False(t, queue.sendDue(ctx, time.Now()))
+	require.Equal(t, store.OutboundMailPending, getOutboundMail(waiting.ID).Status)
+	require.Zero(t, getOutboundMail(waiting.ID).Attempts)
+
+	_, err := testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_GENERAL,
+		Value: &storepb.WorkspaceSetting_GeneralSetting{GeneralSetting: &storepb.WorkspaceGeneralSetting{
+			SmtpHost:         server.Host,
+			SmtpPort:         int32(server.Port),
+			SmtpAccountEmail: "wekalist@example.com",
+			SmtpSecurity:     storepb.WorkspaceGeneralSetting_PLAIN,
+		}},
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	require.False(t, queue.sendDue(ctx, now))
+	require.Equal(t, store.OutboundMailSent, getOutboundMail(waiting.ID).Status)
+	require.Len(t, server.Messages(), 1)
+
+	t.Run("retries temporary failures with a backoff", func(t *testing.T) {
+		server.Reject(451)
+		retried := queueEmail("retried@example.com")
+		queue.sendDue(ctx, now)
+		outboundMail := getOutboundMail(retried.ID)
+		require.Equal(t, store.OutboundMailPending, outboundMail.Status)
+		require.Equal(t, int32(1), outboundMail.Attempts)
+		require.Equal(t, now.Add(mailRetryBackoff).Unix(), outboundMail.NextAttemptTs)
+		require.Contains(t, outboundMail.Error, "451")
+
+		// Not due before its next attempt.
+		queue.sendDue(ctx, now)
+		require.Equal(t, int32(1), getOutboundMail(retried.ID).Attempts)
+
+		queue.sendDue(ctx, now.Add(mailRetryBackoff))
+		outboundMail = getOutboundMail(retried.ID)
+		require.Equal(t, store.OutboundMailSent, outboundMail.Status)
+		require.Equal(t, int32(2), outboundMail.Attempts)
+		require.Empty(t, outboundMail.Error)
+	})
+
+	t.Run("gives up on permanent failures", func(t *testing.T) {
+		server.Reject(550)
+		rejected := queueEmail("rejected@example.com")
+		queue.sendDue(ctx, now)
+		outboundMail := getOutboundMail(rejected.ID)
+		require.Equal(t, store.OutboundMailFailed, outboundMail.Status)
+		require.Contains(t, outboundMail.Error, "550")
+	})
+
+	t.Run("backoff doubles up to the maximum", func(t *testing.T) {
+		require.Equal(t, mailRetryBackoff, getMailRetryBackoff(1))
+		require.Equal(t, 2*mailRetryBackoff, getMailRetryBackoff(2))
+		require.Equal(t, 4*mailRetryBackoff, getMailRetryBackoff(3))
+		require.Equal(t, maxMailRetryBackoff, getMailRetryBackoff(maxMailAttempts))
+	})
+
+	t.Run("prunes old emails", func(t *testing.T) {
+		pending := queueEmail("pending@example.com")
+		queue.prune(ctx, time.Now().Add(outboundMailRetention+time.Hour))
+		outboundMails, err := testStore.ListOutboundMails(ctx, &store.FindOutboundMail{})
+		require.NoError(t, err)
+		require.Len(t, outboundMails, 1)
+		require.Equal(t, pending.ID, outboundMails[0].ID)
+	})
+
+	t.Run("sends queued emails once started", func(t *testing.T) {
+		service.MailQueue = queue
+		defer func() { service.MailQueue = nil }()
+		queueContext, cancel := context.WithCancel(ctx)
+		defer cancel()
+		queue.Start(queueContext)
+
+		require.NoError(t, service.sendOTP(ctx, OtpPurposeVerification, "alice@example.com", "en"))
+		require.Eventually(t, func() bool {
+			messages := server.Messages()
+			return messages[len(messages)-1].To[0] == "alice@example.com"
+		}, 5*time.Second, 10*time.Millisecond)
+		// The email queued before starting is sent as well.
+		require.Eventually(t, func() bool {
+			status := store.OutboundMailPending
+			outboundMails, err := testStore.ListOutboundMails(ctx, &store.FindOutboundMail{Status: &status})
+			return err == nil && len(outboundMails) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestConvertMailConfigFromStore(t *testing.T) {
+	setting := &storepb.WorkspaceGeneralSetting{SmtpHost: "smtp.example.com", SmtpPort: 465, SmtpAccountEmail: "wekalist@example.com"}
+	for security, expected := range map[storepb.WorkspaceGeneralSetting_SmtpSecurity]mail.Security{
+		storepb.WorkspaceGeneralSetting_SMTP_SECURITY_UNSPECIFIED: mail.SecurityStartTLS,
+		storepb.WorkspaceGeneralSetting_STARTTLS:                  mail.SecurityStartTLS,
+		storepb.WorkspaceGeneralSetting_TLS:                       mail.SecurityTLS,
+		storepb.WorkspaceGeneralSetting_PLAIN:                     mail.SecurityPlain,
+	} {
+		setting.SmtpSecurity = security
+		config, err := convertMailConfigFromStore(setting)
+		require.NoError(t, err)
+		require.Equal(t, expected, config.Security)
+		require.Nil(t, config.DKIM)
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	setting.DkimDomain, setting.DkimSelector = "example.com", "wekalist"
+	setting.DkimPrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	require.NoError(t, validateDKIMSetting(setting))
+	config, err := convertMailConfigFromStore(setting)
+	require.NoError(t, err)
+	require.Equal(t, "example.com", config.DKIM.Domain)
+	require.Equal(t, "wekalist", config.DKIM.Selector)
+	require.Equal(t, privateKey, config.DKIM.Signer)
+
+	setting.DkimSelector = ""
+	require.Error(t, validateDKIMSetting(setting))
+	setting.DkimSelector, setting.DkimPrivateKey = "wekalist", "not a key"
+	require.Error(t, validateDKIMSetting(setting))
+	require.NoError(t, validateDKIMSetting(&storepb.WorkspaceGeneralSetting{}))
+}
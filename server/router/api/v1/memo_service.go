@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -22,10 +24,17 @@ import (
 	"github.com/imrany/wekalist/plugin/webhook"
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
 	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/server/runner/embedding"
 	"github.com/imrany/wekalist/server/runner/memopayload"
 	"github.com/imrany/wekalist/store"
 )
 
+// maxMemoMentions is the number of users notified of being mentioned in a memo.
+const maxMemoMentions = 20
+
+// mentionRegexp matches the mentions of usernames, not preceded by a word character as in email addresses.
+var mentionRegexp = regexp.MustCompile(`(^|[^\w@])@([a-zA-Z0-9](?:[a-zA-Z0-9-]{0,30}[a-zA-Z0-9])?)\b`)
+
 func (s *APIV1Service) CreateMemo(ctx context.Context, request *v1pb.CreateMemoRequest) (*v1pb.Memo, error) {
 	user, err := s.GetCurrentUser(ctx)
 	if err != nil {
@@ -86,6 +95,9 @@ func (s *APIV1Service) CreateMemo(ctx context.Context, request *v1pb.CreateMemoR
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to convert memo")
 	}
+	if err := s.notifyMemoMentions(ctx, memo, nil); err != nil {
+		return nil, err
+	}
 	// Try to dispatch webhook when memo is created.
 	if err := s.DispatchMemoCreatedWebhook(ctx, memoMessage); err != nil {
 		slog.Warn("Failed to dispatch memo created webhook", slog.Any("err", err))
@@ -128,16 +140,7 @@ func (s *APIV1Service) ListMemos(ctx context.Context, request *v1pb.ListMemosReq
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get user")
 	}
-	if currentUser == nil {
-		memoFind.VisibilityList = []store.Visibility{store.Public}
-	} else {
-		if memoFind.CreatorID == nil {
-			filter := fmt.Sprintf(`creator_id == %d || visibility in ["PUBLIC", "PROTECTED"]`, currentUser.ID)
-			memoFind.Filters = append(memoFind.Filters, filter)
-		} else if *memoFind.CreatorID != currentUser.ID {
-			memoFind.VisibilityList = []store.Visibility{store.Public, store.Protected}
-		}
-	}
+	applyMemoVisibilityFilter(memoFind, currentUser)
 
 	workspaceMemoRelatedSetting, err := s.Store.GetWorkspaceMemoRelatedSetting(ctx)
 	if err != nil {
@@ -215,7 +218,21 @@ func (s *APIV1Service) ListMemos(ctx context.Context, request *v1pb.ListMemosReq
 }
 
 func (s *APIV1Service) GetMemo(ctx context.Context, request *v1pb.GetMemoRequest) (*v1pb.Memo, error) {
-	memoUID, err := ExtractMemoUIDFromName(request.Name)
+	memo, err := s.getVisibleMemo(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	memoMessage, err := s.convertMemoFromStore(ctx, memo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert memo")
+	}
+	return memoMessage, nil
+}
+
+// getVisibleMemo returns the memo with the given name if the current user is allowed to view it.
+func (s *APIV1Service) getVisibleMemo(ctx context.Context, name string) (*store.Memo, error) {
+	memoUID, err := ExtractMemoUIDFromName(name)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid memo name: %v", err)
 	}
@@ -240,12 +257,7 @@ func (s *APIV1Service) GetMemo(ctx context.Context, request *v1pb.GetMemoRequest
 			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 		}
 	}
-
-	memoMessage, err := s.convertMemoFromStore(ctx, memo)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to convert memo")
-	}
-	return memoMessage, nil
+	return memo, nil
 }
 
 func (s *APIV1Service) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoRequest) (*v1pb.Memo, error) {
@@ -277,6 +289,12 @@ func (s *APIV1Service) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoR
 	update := &store.UpdateMemo{
 		ID: memo.ID,
 	}
+	// The users mentioned before the update were already notified, unless they could not see the memo.
+	previousMentions := []string{}
+	if memo.Visibility != store.Private {
+		previousMentions = extractMemoMentions(memo.Content)
+	}
+	refreshEmbedding := false
 	for _, path := range request.UpdateMask.Paths {
 		switch path {
 		case "content":
@@ -293,6 +311,7 @@ func (s *APIV1Service) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoR
 			}
 			update.Content = &memo.Content
 			update.Payload = memo.Payload
+			refreshEmbedding = true
 		case "visibility":
 			workspaceMemoRelatedSetting, err := s.Store.GetWorkspaceMemoRelatedSetting(ctx)
 			if err != nil {
@@ -354,6 +373,9 @@ func (s *APIV1Service) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoR
 	if err = s.Store.UpdateMemo(ctx, update); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update memo")
 	}
+	if refreshEmbedding {
+		embedding.RefreshMemoEmbeddingAsync(s.Store, memo.ID)
+	}
 
 	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
 		ID: &memo.ID,
@@ -365,6 +387,9 @@ func (s *APIV1Service) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoR
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to convert memo")
 	}
+	if err := s.notifyMemoMentions(ctx, memo, previousMentions); err != nil {
+		return nil, err
+	}
 	// Try to dispatch webhook when memo is updated.
 	if err := s.DispatchMemoUpdatedWebhook(ctx, memoMessage); err != nil {
 		slog.Warn("Failed to dispatch memo updated webhook", slog.Any("err", err))
@@ -497,17 +522,22 @@ func (s *APIV1Service) CreateMemoComment(ctx context.Context, request *v1pb.Crea
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to create activity")
 		}
-		if _, err := s.Store.CreateInbox(ctx, &store.Inbox{
+		creator, err := s.Store.GetUser(ctx, &store.FindUser{ID: &creatorID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get memo creator")
+		}
+		s.NotifyUser(ctx, &NotificationEvent{
+			Type:       storepb.NotificationsUserSetting_COMMENT,
 			SenderID:   creatorID,
 			ReceiverID: relatedMemo.CreatorID,
-			Status:     store.UNREAD,
-			Message: &storepb.InboxMessage{
+			InboxMessage: &storepb.InboxMessage{
 				Type:       storepb.InboxMessage_MEMO_COMMENT,
 				ActivityId: &activity.ID,
 			},
-		}); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to create inbox")
-		}
+			Title: fmt.Sprintf("%s commented on your memo", getNotificationSenderName(creator)),
+			Body:  truncateNotificationText(memo.Content),
+			Link:  fmt.Sprintf("/%s%s", MemoNamePrefix, relatedMemo.UID),
+		})
 	}
 
 	return memoComment, nil
@@ -662,6 +692,85 @@ func (s *APIV1Service) DeleteMemoTag(ctx context.Context, request *v1pb.DeleteMe
 	return &emptypb.Empty{}, nil
 }
 
+// extractMemoMentions returns the usernames mentioned with @username in the text of the content,
+// leaving out code and links.
+func extractMemoMentions(content string) []string {
+	usernames := []string{}
+	nodes, err := parser.Parse(tokenizer.Tokenize(content))
+	if err != nil {
+		return usernames
+	}
+	memopayload.TraverseASTNodes(nodes, func(node ast.Node) {
+		text, ok := node.(*ast.Text)
+		if !ok {
+			return
+		}
+		for _, match := range mentionRegexp.FindAllStringSubmatch(text.Content, -1) {
+			if !slices.Contains(usernames, match[2]) {
+				usernames = append(usernames, match[2])
+			}
+		}
+	})
+	return usernames
+}
+
+// notifyMemoMentions notifies the users mentioned in the memo, except its creator and the previously mentioned users.
+func (s *APIV1Service) notifyMemoMentions(ctx context.Context, memo *store.Memo, previousMentions []string) error {
+	if memo.Visibility == store.Private {
+		return nil
+	}
+	mentions := extractMemoMentions(memo.Content)
+	if len(mentions) > maxMemoMentions {
+		mentions = mentions[:maxMemoMentions]
+	}
+	var creator *store.User
+	for _, username := range mentions {
+		if slices.Contains(previousMentions, username) {
+			continue
+		}
+		user, err := s.Store.GetUser(ctx, &store.FindUser{Username: &username})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get mentioned user")
+		}
+		if user == nil || user.ID == memo.CreatorID {
+			continue
+		}
+		if creator == nil {
+			creator, err = s.Store.GetUser(ctx, &store.FindUser{ID: &memo.CreatorID})
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to get memo creator")
+			}
+		}
+
+		activity, err := s.Store.CreateActivity(ctx, &store.Activity{
+			CreatorID: memo.CreatorID,
+			Type:      store.ActivityTypeMemoMention,
+			Level:     store.ActivityLevelInfo,
+			Payload: &storepb.ActivityPayload{
+				MemoMention: &storepb.ActivityMemoMentionPayload{
+					MemoId: memo.ID,
+				},
+			},
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to create activity")
+		}
+		s.NotifyUser(ctx, &NotificationEvent{
+			Type:       storepb.NotificationsUserSetting_MENTION,
+			SenderID:   memo.CreatorID,
+			ReceiverID: user.ID,
+			InboxMessage: &storepb.InboxMessage{
+				Type:       storepb.InboxMessage_MEMO_MENTION,
+				ActivityId: &activity.ID,
+			},
+			Title: fmt.Sprintf("%s mentioned you in a memo", getNotificationSenderName(creator)),
+			Body:  truncateNotificationText(memo.Content),
+			Link:  fmt.Sprintf("/%s%s", MemoNamePrefix, memo.UID),
+		})
+	}
+	return nil
+}
+
 func (s *APIV1Service) getContentLengthLimit(ctx context.Context) (int, error) {
 	workspaceMemoRelatedSetting, err := s.Store.GetWorkspaceMemoRelatedSetting(ctx)
 	if err != nil {
@@ -751,6 +860,21 @@ func substring(s string, length int) string {
 	return s[:byteIndex]
 }
 
+// applyMemoVisibilityFilter restricts memoFind to the memos the current user is allowed to see.
+// Anonymous users only see public memos, signed-in users also see protected memos and their own.
+func applyMemoVisibilityFilter(memoFind *store.FindMemo, currentUser *store.User) {
+	if currentUser == nil {
+		memoFind.VisibilityList = []store.Visibility{store.Public}
+		return
+	}
+	if memoFind.CreatorID == nil {
+		filter := fmt.Sprintf(`creator_id == %d || visibility in ["PUBLIC", "PROTECTED"]`, currentUser.ID)
+		memoFind.Filters = append(memoFind.Filters, filter)
+	} else if *memoFind.CreatorID != currentUser.ID {
+		memoFind.VisibilityList = []store.Visibility{store.Public, store.Protected}
+	}
+}
+
 // parseMemoOrderBy parses the order_by field and sets the appropriate ordering in memoFind.
 func (*APIV1Service) parseMemoOrderBy(orderBy string, memoFind *store.FindMemo) error {
 	// Parse order_by field like "display_time desc" or "create_time asc"
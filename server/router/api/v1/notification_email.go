@@ -0,0 +1,323 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/mail"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// notificationEmailWindow is how long the notification emails of a receiver are gathered into one email.
+	notificationEmailWindow = 5 * time.Minute
+	// maxNotificationEmailItems is the number of notifications listed in an email, the others are counted.
+	maxNotificationEmailItems = 20
+	// unsubscribePath is the page of the unsubscribe links of notification emails.
+	unsubscribePath = "/notifications/unsubscribe"
+)
+
+var unsubscribePageTemplate = htmltemplate.Must(htmltemplate.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Unsubscribe</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+	<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+		{{- if .Done}}
+		<p>You will no longer receive {{.Label}} emails. You can turn them on again in your settings.</p>
+		{{- else}}
+		<p>Stop receiving {{.Label}} emails?</p>
+		<form method="post">
+			<button type="submit">Unsubscribe</button>
+		</form>
+		{{- end}}
+	</div>
+</body>
+</html>
+`))
+
+// NotificationEmailBatcher gathers the notification emails of each receiver for a window of time,
+// so a busy memo sends one email listing its comments rather than one email per comment.
+type NotificationEmailBatcher struct {
+	window time.Duration
+	send   func(ctx context.Context, receiverID int32, events []*NotificationEvent) error
+
+	mu        sync.Mutex
+	batches   map[int32]*notificationEmailBatch
+	startOnce sync.Once
+}
+
+type notificationEmailBatch struct {
+	events  []*NotificationEvent
+	dueTime time.Time
+}
+
+func NewNotificationEmailBatcher(window time.Duration, send func(ctx context.Context, receiverID int32, events []*NotificationEvent) error) *NotificationEmailBatcher {
+	return &NotificationEmailBatcher{
+		window:  window,
+		send:    send,
+		batches: map[int32]*notificationEmailBatch{},
+	}
+}
+
+// Add adds the event to the batch of its receiver, sent once the window has passed since the first event of the batch.
+func (b *NotificationEmailBatcher) Add(event *NotificationEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	batch, ok := b.batches[event.ReceiverID]
+	if !ok {
+		batch = &notificationEmailBatch{dueTime: time.Now().Add(b.window)}
+		b.batches[event.ReceiverID] = batch
+	}
+	batch.events = append(batch.events, event)
+}
+
+// Start sends the batches once due until the context is done, and then the remaining batches.
+func (b *NotificationEmailBatcher) Start(ctx context.Context) {
+	b.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(max(b.window/10, 10*time.Millisecond))
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					b.flush(context.WithoutCancel(ctx), time.Time{})
+					return
+				case now := <-ticker.C:
+					b.flush(ctx, now)
+				}
+			}
+		}()
+	})
+}
+
+// flush sends the batches due by the time, or every batch if the time is zero.
+func (b *NotificationEmailBatcher) flush(ctx context.Context, now time.Time) {
+	due := map[int32][]*NotificationEvent{}
+	b.mu.Lock()
+	for receiverID, batch := range b.batches {
+		if now.IsZero() || !batch.dueTime.After(now) {
+			due[receiverID] = batch.events
+			delete(b.batches, receiverID)
+		}
+	}
+	b.mu.Unlock()
+
+	for receiverID, events := range due {
+		if err := b.send(ctx, receiverID, events); err != nil {
+			slog.Warn("Failed to send notification email", slog.Int("user", int(receiverID)), slog.Any("err", err))
+		}
+	}
+}
+
+// sendNotificationEmail queues one email listing the events to the receiver.
+func (s *APIV1Service) sendNotificationEmail(ctx context.Context, receiverID int32, events []*NotificationEvent) error {
+	receiver, err := s.Store.GetUser(ctx, &store.FindUser{ID: &receiverID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get receiver")
+	}
+	if receiver == nil || receiver.Email == "" {
+		return nil
+	}
+	// The receiver may have unsubscribed since the events were batched.
+	preferences, err := s.getNotificationPreferences(ctx, receiverID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get notification preferences")
+	}
+	events = slices.DeleteFunc(slices.Clone(events), func(event *NotificationEvent) bool {
+		return !isNotificationEnabled(preferences, event.Type, storepb.NotificationsUserSetting_EMAIL)
+	})
+	if len(events) == 0 {
+		return nil
+	}
+	smtpConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if smtpConfig.Host == "" {
+		return nil
+	}
+
+	message, err := s.buildNotificationEmail(ctx, receiver, events)
+	if err != nil {
+		return err
+	}
+	return s.queueEmail(ctx, message)
+}
+
+// buildNotificationEmail renders the email listing the events in the locale of the receiver, with links to the instance.
+func (s *APIV1Service) buildNotificationEmail(ctx context.Context, receiver *store.User, events []*NotificationEvent) (*mail.Message, error) {
+	baseURL := ""
+	if s.Profile != nil {
+		baseURL = strings.TrimSuffix(s.Profile.InstanceURL, "/")
+	}
+
+	data := mail.NotificationData{}
+	eventTypes := []storepb.NotificationsUserSetting_EventType{}
+	for _, event := range events {
+		if !slices.Contains(eventTypes, event.Type) {
+			eventTypes = append(eventTypes, event.Type)
+			data.EventTypes = append(data.EventTypes, strings.ToLower(event.Type.String()))
+		}
+		if len(data.Items) == maxNotificationEmailItems {
+			data.MoreCount++
+			continue
+		}
+		item := mail.NotificationItem{
+			Title: event.Title,
+			Body:  event.Body,
+		}
+		if baseURL != "" && event.Link != "" {
+			item.URL = baseURL + event.Link
+		}
+		data.Items = append(data.Items, item)
+	}
+	if baseURL != "" {
+		data.UnsubscribeURL = baseURL + unsubscribePath + "?token=" + url.QueryEscape(s.newUnsubscribeToken(receiver.ID, eventTypes))
+	}
+
+	message, err := s.renderEmail(ctx, mail.TemplateNotification, s.getEmailLocale(ctx, receiver), data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render notification email")
+	}
+	message.To = []string{receiver.Email}
+	if data.UnsubscribeURL != "" {
+		// One-click unsubscription from mail clients, see RFC 8058.
+		message.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	return message, nil
+}
+
+func isNotificationEnabled(preferences []*storepb.NotificationsUserSetting_Preference, eventType storepb.NotificationsUserSetting_EventType, channel storepb.NotificationsUserSetting_Channel) bool {
+	for _, preference := range preferences {
+		if preference.EventType == eventType && preference.Channel == channel {
+			return preference.Enabled
+		}
+	}
+	return false
+}
+
+// getNotificationEventLabel returns the name of the event type shown to users, such as "version update".
+func getNotificationEventLabel(eventType storepb.NotificationsUserSetting_EventType) string {
+	return strings.ToLower(strings.ReplaceAll(eventType.String(), "_", " "))
+}
+
+// newUnsubscribeToken returns the token of the unsubscribe link turning off the emails of the event types.
+// Unsubscribe links do not expire, so the token is only signed.
+func (s *APIV1Service) newUnsubscribeToken(userID int32, eventTypes []storepb.NotificationsUserSetting_EventType) string {
+	values := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		values = append(values, strconv.Itoa(int(eventType)))
+	}
+	payload := fmt.Sprintf("%d.%s", userID, strings.Join(values, ","))
+	return payload + "." + s.signUnsubscribePayload(payload)
+}
+
+// parseUnsubscribeToken returns the user and the event types of a token made by newUnsubscribeToken.
+func (s *APIV1Service) parseUnsubscribeToken(token string) (int32, []storepb.NotificationsUserSetting_EventType, error) {
+	index := strings.LastIndex(token, ".")
+	if index < 0 {
+		return 0, nil, errors.New("malformed token")
+	}
+	payload, signature := token[:index], token[index+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.signUnsubscribePayload(payload))) {
+		return 0, nil, errors.New("invalid token signature")
+	}
+	userIDValue, eventTypeValues, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, nil, errors.New("malformed token")
+	}
+	userID, err := strconv.ParseInt(userIDValue, 10, 32)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "malformed token")
+	}
+	eventTypes := []storepb.NotificationsUserSetting_EventType{}
+	for _, value := range strings.Split(eventTypeValues, ",") {
+		eventType, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "malformed token")
+		}
+		eventTypes = append(eventTypes, storepb.NotificationsUserSetting_EventType(eventType))
+	}
+	return int32(userID), eventTypes, nil
+}
+
+func (s *APIV1Service) signUnsubscribePayload(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *APIV1Service) registerNotificationRoutes(echoServer *echo.Echo) {
+	echoServer.GET(unsubscribePath, s.handleUnsubscribe)
+	// Mail clients unsubscribe with a POST request, see RFC 8058.
+	echoServer.POST(unsubscribePath, s.handleUnsubscribe)
+}
+
+// handleUnsubscribe asks to confirm the unsubscription, so link scanners of mail servers do not unsubscribe, and
+// turns the emails of the event types of the token off once confirmed.
+func (s *APIV1Service) handleUnsubscribe(c echo.Context) error {
+	userID, eventTypes, err := s.parseUnsubscribeToken(c.QueryParam("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid unsubscribe link")
+	}
+	labels := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		labels = append(labels, getNotificationEventLabel(eventType))
+	}
+	page := struct {
+		Label string
+		Done  bool
+	}{Label: strings.Join(labels, " and ")}
+
+	if c.Request().Method == http.MethodPost {
+		user, err := s.Store.GetUser(c.Request().Context(), &store.FindUser{ID: &userID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get user")
+		}
+		if user == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+		preferences := make([]*storepb.NotificationsUserSetting_Preference, 0, len(eventTypes))
+		for _, eventType := range eventTypes {
+			preferences = append(preferences, &storepb.NotificationsUserSetting_Preference{
+				EventType: eventType,
+				Channel:   storepb.NotificationsUserSetting_EMAIL,
+				Enabled:   false,
+			})
+		}
+		if err := s.Store.UpsertUserNotificationPreferences(c.Request().Context(), userID, preferences); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to update notification preferences")
+		}
+		page.Done = true
+	}
+
+	var body bytes.Buffer
+	if err := unsubscribePageTemplate.Execute(&body, page); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.HTMLBlob(http.StatusOK, body.Bytes())
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/internal/profile"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestNotificationEmailBatcher(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[int32][]*NotificationEvent{}
+	batcher := NewNotificationEmailBatcher(50*time.Millisecond, func(_ context.Context, receiverID int32, events []*NotificationEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent[receiverID] = append(sent[receiverID], events...)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batcher.Start(ctx)
+
+	for i := 0; i < 5; i++ {
+		batcher.Add(&NotificationEvent{Type: storepb.NotificationsUserSetting_COMMENT, ReceiverID: 1, Title: fmt.Sprintf("Comment %d", i)})
+	}
+	batcher.Add(&NotificationEvent{Type: storepb.NotificationsUserSetting_MENTION, ReceiverID: 2, Title: "Mention"})
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(sent) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Len(t, sent[1], 5)
+	require.Len(t, sent[2], 1)
+	mu.Unlock()
+
+	// The remaining batches are sent once stopped.
+	batcher.Add(&NotificationEvent{Type: storepb.NotificationsUserSetting_COMMENT, ReceiverID: 3, Title: "Comment"})
+	cancel()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(sent[3]) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBuildNotificationEmail(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Profile: &profile.Profile{InstanceURL: "http://localhost:8080/"}, Store: testStore}
+	receiver, err := testStore.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com"})
+	require.NoError(t, err)
+
+	message, err := service.buildNotificationEmail(ctx, receiver, []*NotificationEvent{
+		{Type: storepb.NotificationsUserSetting_COMMENT, Title: "Bob commented on your memo", Body: "<b>Nice</b>", Link: "/memos/abc"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice@example.com"}, message.To)
+	require.Equal(t, "Bob commented on your memo", message.Subject)
+	require.Contains(t, message.HTMLBody, "&lt;b&gt;Nice&lt;/b&gt;")
+	require.Contains(t, message.HTMLBody, `href="http://localhost:8080/memos/abc"`)
+	require.Contains(t, message.TextBody, "<b>Nice</b>")
+	require.Contains(t, message.TextBody, "http://localhost:8080/memos/abc")
+	require.Contains(t, message.TextBody, "Unsubscribe from comment emails: http://localhost:8080"+unsubscribePath+"?token=")
+	require.True(t, strings.HasPrefix(message.Headers["List-Unsubscribe"], "<http://localhost:8080"+unsubscribePath+"?token="))
+	require.Equal(t, "List-Unsubscribe=One-Click", message.Headers["List-Unsubscribe-Post"])
+
+	message.From = "wekalist@example.com"
+	messageBytes, err := message.Bytes()
+	require.NoError(t, err)
+	require.Contains(t, string(messageBytes), "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	require.Contains(t, string(messageBytes), "Content-Type: multipart/alternative;")
+
+	events := []*NotificationEvent{}
+	for i := 0; i < maxNotificationEmailItems+3; i++ {
+		events = append(events, &NotificationEvent{Type: storepb.NotificationsUserSetting_COMMENT, Title: fmt.Sprintf("Comment %d", i)})
+	}
+	events = append(events, &NotificationEvent{Type: storepb.NotificationsUserSetting_MENTION, Title: "Mention"})
+	message, err = service.buildNotificationEmail(ctx, receiver, events)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d new notifications", len(events)), message.Subject)
+	require.Contains(t, message.HTMLBody, "And 4 more notifications.")
+	require.Contains(t, message.TextBody, "Unsubscribe from comment and mention emails")
+
+	// The email is in the locale of the receiver, with the template of the workspace for that locale.
+	_, err = testStore.UpsertUserSetting(ctx, &storepb.UserSetting{
+		UserId: receiver.ID,
+		Key:    storepb.UserSetting_GENERAL,
+		Value:  &storepb.UserSetting_General{General: &storepb.GeneralUserSetting{Locale: "fr"}},
+	})
+	require.NoError(t, err)
+	message, err = service.buildNotificationEmail(ctx, receiver, events)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d nouvelles notifications", len(events)), message.Subject)
+
+	_, err = testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_EMAIL_TEMPLATES,
+		Value: &storepb.WorkspaceSetting_EmailTemplatesSetting{EmailTemplatesSetting: &storepb.WorkspaceEmailTemplatesSetting{
+			Templates: []*storepb.EmailTemplate{
+				{Name: "notification", Locale: "fr", Subject: "Quoi de neuf : {{.Count}}", TextBody: "{{range .Items}}{{.Title}}{{end}}", HtmlBody: "<p>{{range .Items}}{{.Title}}{{end}}</p>"},
+				// A template failing to render falls back to the built-in one.
+				{Name: "notification", Locale: "en", Subject: "{{.Missing}}", TextBody: "text", HtmlBody: "<p>html</p>"},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	message, err = service.buildNotificationEmail(ctx, receiver, events)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("Quoi de neuf : %d", len(events)), message.Subject)
+	require.True(t, strings.HasPrefix(message.HTMLBody, "<p>Comment 0Comment 1"))
+
+	bob, err := testStore.CreateUser(ctx, &store.User{Username: "bob", Role: store.RoleUser, Email: "bob@example.com"})
+	require.NoError(t, err)
+	message, err = service.buildNotificationEmail(ctx, bob, events)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d new notifications", len(events)), message.Subject)
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	service := &APIV1Service{Secret: "test-secret"}
+	eventTypes := []storepb.NotificationsUserSetting_EventType{storepb.NotificationsUserSetting_COMMENT, storepb.NotificationsUserSetting_MENTION}
+	token := service.newUnsubscribeToken(42, eventTypes)
+
+	userID, parsedEventTypes, err := service.parseUnsubscribeToken(token)
+	require.NoError(t, err)
+	require.Equal(t, int32(42), userID)
+	require.Equal(t, eventTypes, parsedEventTypes)
+
+	for _, invalidToken := range []string{
+		"",
+		"42.1",
+		strings.Replace(token, "42.", "43.", 1),
+		token + "x",
+	} {
+		_, _, err := service.parseUnsubscribeToken(invalidToken)
+		require.Error(t, err, invalidToken)
+	}
+	_, _, err = (&APIV1Service{Secret: "other-secret"}).parseUnsubscribeToken(token)
+	require.Error(t, err)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Profile: &profile.Profile{InstanceURL: "http://localhost:8080"}, Store: testStore}
+	user, err := testStore.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com"})
+	require.NoError(t, err)
+
+	e := echo.New()
+	service.registerNotificationRoutes(e)
+	token := service.newUnsubscribeToken(user.ID, []storepb.NotificationsUserSetting_EventType{storepb.NotificationsUserSetting_COMMENT})
+	target := unsubscribePath + "?token=" + url.QueryEscape(token)
+	isCommentEmailEnabled := func() bool {
+		preferences, err := service.getNotificationPreferences(ctx, user.ID)
+		require.NoError(t, err)
+		return isNotificationEnabled(preferences, storepb.NotificationsUserSetting_COMMENT, storepb.NotificationsUserSetting_EMAIL)
+	}
+
+	// Opening the link only asks to confirm.
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "Stop receiving comment emails?")
+	require.True(t, isCommentEmailEnabled())
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, target, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "You will no longer receive comment emails.")
+	require.False(t, isCommentEmailEnabled())
+	preferences, err := service.getNotificationPreferences(ctx, user.ID)
+	require.NoError(t, err)
+	require.True(t, isNotificationEnabled(preferences, storepb.NotificationsUserSetting_MENTION, storepb.NotificationsUserSetting_EMAIL))
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, unsubscribePath+"?token=invalid", nil))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
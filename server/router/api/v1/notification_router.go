@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/webpush"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+// notificationPushTTL is how long push services keep the notification of an event for an offline browser.
+const notificationPushTTL = 24 * time.Hour
+
+var (
+	// notificationEventTypes are the event types users set preferences for. REMINDER and VERSION_UPDATE
+	// are left out until something sends them.
+	notificationEventTypes = []storepb.NotificationsUserSetting_EventType{
+		storepb.NotificationsUserSetting_COMMENT,
+		storepb.NotificationsUserSetting_MENTION,
+		storepb.NotificationsUserSetting_REACTION,
+	}
+	notificationChannels = []storepb.NotificationsUserSetting_Channel{
+		storepb.NotificationsUserSetting_INBOX,
+		storepb.NotificationsUserSetting_PUSH,
+		storepb.NotificationsUserSetting_EMAIL,
+	}
+	// defaultNotificationChannels are the channels enabled for each event type until the user sets a preference.
+	defaultNotificationChannels = map[storepb.NotificationsUserSetting_EventType][]storepb.NotificationsUserSetting_Channel{
+		storepb.NotificationsUserSetting_COMMENT:  {storepb.NotificationsUserSetting_INBOX, storepb.NotificationsUserSetting_PUSH, storepb.NotificationsUserSetting_EMAIL},
+		storepb.NotificationsUserSetting_MENTION:  {storepb.NotificationsUserSetting_INBOX, storepb.NotificationsUserSetting_PUSH, storepb.NotificationsUserSetting_EMAIL},
+		storepb.NotificationsUserSetting_REACTION: {storepb.NotificationsUserSetting_INBOX},
+	}
+)
+
+// NotificationEvent is an event a user is notified of.
+type NotificationEvent struct {
+	Type storepb.NotificationsUserSetting_EventType
+	// SenderID is the user causing the event, or 0 for events of the system.
+	SenderID   int32
+	ReceiverID int32
+	// InboxMessage is stored in the inbox of the receiver. Events without one are not sent to the inbox.
+	InboxMessage *storepb.InboxMessage
+	// Title and Body are the text of push notifications and emails.
+	Title string
+	Body  string
+	// Link is the path of the page about the event, relative to the instance URL.
+	Link string
+}
+
+// NotifyUser fans the event out to the inbox, push and email channels enabled by the preferences of the receiver.
+// Failures are logged rather than returned, as the action causing the event has already succeeded.
+func (s *APIV1Service) NotifyUser(ctx context.Context, event *NotificationEvent) {
+	preferences, err := s.getNotificationPreferences(ctx, event.ReceiverID)
+	if err != nil {
+		slog.Warn("Failed to get notification preferences", slog.Int("user", int(event.ReceiverID)), slog.Any("err", err))
+		return
+	}
+	for _, preference := range preferences {
+		if preference.EventType != event.Type || !preference.Enabled {
+			continue
+		}
+		switch preference.Channel {
+		case storepb.NotificationsUserSetting_INBOX:
+			err = s.notifyInbox(ctx, event)
+		case storepb.NotificationsUserSetting_PUSH:
+			err = s.notifyPush(ctx, event)
+		case storepb.NotificationsUserSetting_EMAIL:
+			err = s.notifyEmail(ctx, event)
+		default:
+			continue
+		}
+		if err != nil {
+			slog.Warn("Failed to send notification", slog.String("event", event.Type.String()), slog.String("channel", preference.Channel.String()), slog.Int("user", int(event.ReceiverID)), slog.Any("err", err))
+		}
+	}
+}
+
+// getNotificationPreferences returns the preference of the user for every event type and channel,
+// the preferences set by the user taking precedence over the defaults.
+func (s *APIV1Service) getNotificationPreferences(ctx context.Context, userID int32) ([]*storepb.NotificationsUserSetting_Preference, error) {
+	userPreferences, err := s.Store.GetUserNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]*storepb.NotificationsUserSetting_Preference, 0, len(notificationEventTypes)*len(notificationChannels))
+	for _, eventType := range notificationEventTypes {
+		for _, channel := range notificationChannels {
+			preference := &storepb.NotificationsUserSetting_Preference{
+				EventType: eventType,
+				Channel:   channel,
+			}
+			for _, defaultChannel := range defaultNotificationChannels[eventType] {
+				if defaultChannel == channel {
+					preference.Enabled = true
+				}
+			}
+			for _, userPreference := range userPreferences {
+				if userPreference.EventType == eventType && userPreference.Channel == channel {
+					preference.Enabled = userPreference.Enabled
+				}
+			}
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+// getNotificationSenderName returns the name the sender is shown with in notifications.
+func getNotificationSenderName(sender *store.User) string {
+	if sender == nil {
+		return "Someone"
+	}
+	if sender.Nickname != "" {
+		return sender.Nickname
+	}
+	return sender.Username
+}
+
+// truncateNotificationText shortens the text to fit in push notifications and email subjects.
+func truncateNotificationText(text string) string {
+	const maxLength = 200
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= maxLength {
+		return string(runes)
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
+
+func (s *APIV1Service) notifyInbox(ctx context.Context, event *NotificationEvent) error {
+	if event.InboxMessage == nil {
+		return nil
+	}
+	if _, err := s.Store.CreateInbox(ctx, &store.Inbox{
+		SenderID:   event.SenderID,
+		ReceiverID: event.ReceiverID,
+		Status:     store.UNREAD,
+		Message:    event.InboxMessage,
+	}); err != nil {
+		return errors.Wrap(err, "failed to create inbox")
+	}
+	return nil
+}
+
+func (s *APIV1Service) notifyPush(ctx context.Context, event *NotificationEvent) error {
+	if s.PushDispatcher == nil {
+		return nil
+	}
+	subscriptions, err := s.Store.ListSubscriptions(ctx, &store.FindSubscription{UserID: &event.ReceiverID})
+	if err != nil {
+		return errors.Wrap(err, "failed to list subscriptions")
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	notificationData := map[string]interface{}{
+		"title": event.Title,
+		"body":  event.Body,
+	}
+	if event.Link != "" {
+		notificationData["url"] = event.Link
+	}
+	payloadJSON, err := json.Marshal(notificationData)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification payload")
+	}
+	message := &webpush.Message{
+		Payload: payloadJSON,
+		TTL:     notificationPushTTL,
+		Urgency: webpush.UrgencyNormal,
+	}
+	for _, subscription := range subscriptions {
+		if err := s.PushDispatcher.Enqueue(s.newPushDelivery(subscription, message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *APIV1Service) notifyEmail(ctx context.Context, event *NotificationEvent) error {
+	if s.NotificationEmails == nil {
+		return nil
+	}
+	receiver, err := s.Store.GetUser(ctx, &store.FindUser{ID: &event.ReceiverID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get receiver")
+	}
+	if receiver == nil || receiver.Email == "" {
+		return nil
+	}
+	smtpConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return err
+	}
+	// Email is optional, the channel is skipped until an admin configures SMTP.
+	if smtpConfig.Host == "" {
+		return nil
+	}
+	s.NotificationEmails.Add(event)
+	return nil
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/mail"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// otpMaxAttempts is the number of failed verifications after which an OTP is discarded.
+	otpMaxAttempts = 5
+	// otpResendInterval is the minimum delay between two OTPs for the same purpose and email.
+	otpResendInterval = time.Minute
+)
+
+// issueOTP generates a new OTP for the purpose and email, replacing any active one, and returns the code.
+// Only the hash of the code is stored, and the expired OTPs of the purpose are deleted.
+func (s *APIV1Service) issueOTP(ctx context.Context, purpose OtpPurpose, email string) (string, error) {
+	purposeString, identifier := string(purpose), normalizeOTPIdentifier(email)
+	existing, err := s.Store.GetOTP(ctx, &store.FindOTP{Purpose: &purposeString, Identifier: &identifier})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get otp: %v", err)
+	}
+	now := time.Now()
+	if existing != nil {
+		if wait := time.Unix(existing.CreatedTs, 0).Add(otpResendInterval).Sub(now); wait > 0 {
+			return "", status.Errorf(codes.ResourceExhausted, "please wait %d seconds before requesting a new code", int(wait.Seconds())+1)
+		}
+	}
+
+	// Prune the codes of the purpose that were never used, as each email has its own row.
+	if _, err := s.Store.DeleteExpiredOTPs(ctx, &store.DeleteExpiredOTP{
+		Purpose:         purposeString,
+		ExpiresTsBefore: now.Unix(),
+	}); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to delete expired otps: %v", err)
+	}
+
+	code := GenerateOTP()
+	if _, err := s.Store.UpsertOTP(ctx, &store.OTP{
+		Purpose:    purposeString,
+		Identifier: identifier,
+		CodeHash:   s.hashOTP(purpose, identifier, code),
+		CreatedTs:  now.Unix(),
+		ExpiresTs:  now.Add(GetOTPExpirationDuration(purpose)).Unix(),
+	}); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to store otp: %v", err)
+	}
+	return code, nil
+}
+
+// sendOTP issues a new OTP for the purpose and email and queues the email sending it, in the given locale.
+func (s *APIV1Service) sendOTP(ctx context.Context, purpose OtpPurpose, email, locale string) error {
+	smtpConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get smtp configuration: %v", err)
+	}
+	if err := smtpConfig.Validate(); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "failed to send otp: %v", err)
+	}
+	code, err := s.issueOTP(ctx, purpose, email)
+	if err != nil {
+		return err
+	}
+	message, err := s.renderEmail(ctx, mail.TemplateOTP, locale, mail.OTPData{
+		Code:             code,
+		Purpose:          string(purpose),
+		ExpiresInMinutes: int(GetOTPExpirationDuration(purpose).Minutes()),
+	})
+	if err == nil {
+		message.To = []string{email}
+		err = s.queueEmail(ctx, message)
+	}
+	if err != nil {
+		// Drop the unsent code so that the user can request a new one right away.
+		s.discardOTP(ctx, purpose, email)
+		return status.Errorf(codes.Internal, "failed to send otp: %v", err)
+	}
+	return nil
+}
+
+// verifyOTP checks the code against the active OTP for the purpose and email.
+// A matching OTP is consumed; an OTP is discarded once expired or after too many failed attempts.
+func (s *APIV1Service) verifyOTP(ctx context.Context, purpose OtpPurpose, email, code string) error {
+	purposeString, identifier := string(purpose), normalizeOTPIdentifier(email)
+	otp, err := s.Store.GetOTP(ctx, &store.FindOTP{Purpose: &purposeString, Identifier: &identifier})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get otp: %v", err)
+	}
+	if otp == nil {
+		return status.Errorf(codes.InvalidArgument, "invalid verification code")
+	}
+	if time.Now().Unix() >= otp.ExpiresTs {
+		s.deleteOTP(ctx, otp)
+		return status.Errorf(codes.InvalidArgument, "verification code has expired, please request a new one")
+	}
+	// Count the attempt before checking the code, so that concurrent attempts cannot get more guesses.
+	counted, err := s.Store.CountOTPAttempt(ctx, otp.ID, otpMaxAttempts)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to update otp: %v", err)
+	}
+	if !counted {
+		s.deleteOTP(ctx, otp)
+		return status.Errorf(codes.ResourceExhausted, "too many failed attempts, please request a new code")
+	}
+	if !hmac.Equal([]byte(otp.CodeHash), []byte(s.hashOTP(purpose, identifier, strings.TrimSpace(code)))) {
+		if otp.Attempts+1 >= otpMaxAttempts {
+			s.deleteOTP(ctx, otp)
+			return status.Errorf(codes.ResourceExhausted, "too many failed attempts, please request a new code")
+		}
+		return status.Errorf(codes.InvalidArgument, "invalid verification code")
+	}
+
+	// Only the caller that deletes the OTP redeems it.
+	deleted, err := s.Store.DeleteOTP(ctx, &store.DeleteOTP{ID: otp.ID})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to delete otp: %v", err)
+	}
+	if !deleted {
+		return status.Errorf(codes.InvalidArgument, "invalid verification code")
+	}
+	return nil
+}
+
+// storeOneTimeCode stores the hash of a code that can be redeemed once, under the purpose and identifier,
+// and deletes the expired codes of the purpose.
+func (s *APIV1Service) storeOneTimeCode(ctx context.Context, purpose OtpPurpose, identifier, code string, duration time.Duration) error {
+	now := time.Now()
+	if _, err := s.Store.DeleteExpiredOTPs(ctx, &store.DeleteExpiredOTP{
+		Purpose:         string(purpose),
+		ExpiresTsBefore: now.Unix(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to delete expired codes")
+	}
+	if _, err := s.Store.UpsertOTP(ctx, &store.OTP{
+		Purpose:    string(purpose),
+		Identifier: identifier,
+		CodeHash:   s.hashOTP(purpose, identifier, code),
+		CreatedTs:  now.Unix(),
+		ExpiresTs:  now.Add(duration).Unix(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to store code")
+	}
+	return nil
+}
+
+// redeemOneTimeCode deletes the code stored under the purpose and identifier if it matches and has not expired.
+// It returns false when there is no such code, so that only the first of concurrent callers redeems it.
+func (s *APIV1Service) redeemOneTimeCode(ctx context.Context, purpose OtpPurpose, identifier, code string) (bool, error) {
+	purposeString := string(purpose)
+	otp, err := s.Store.GetOTP(ctx, &store.FindOTP{Purpose: &purposeString, Identifier: &identifier})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get code")
+	}
+	if otp == nil || time.Now().Unix() >= otp.ExpiresTs ||
+		!hmac.Equal([]byte(otp.CodeHash), []byte(s.hashOTP(purpose, identifier, code))) {
+		return false, nil
+	}
+	deleted, err := s.Store.DeleteOTP(ctx, &store.DeleteOTP{ID: otp.ID})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete code")
+	}
+	return deleted, nil
+}
+
+// discardOTP deletes the active OTP for the purpose and email, if any.
+func (s *APIV1Service) discardOTP(ctx context.Context, purpose OtpPurpose, email string) {
+	purposeString, identifier := string(purpose), normalizeOTPIdentifier(email)
+	otp, err := s.Store.GetOTP(ctx, &store.FindOTP{Purpose: &purposeString, Identifier: &identifier})
+	if err != nil {
+		slog.Warn("failed to get otp", slog.String("purpose", purposeString), slog.Any("err", err))
+		return
+	}
+	if otp != nil {
+		s.deleteOTP(ctx, otp)
+	}
+}
+
+func (s *APIV1Service) deleteOTP(ctx context.Context, otp *store.OTP) {
+	if _, err := s.Store.DeleteOTP(ctx, &store.DeleteOTP{ID: otp.ID}); err != nil {
+		slog.Warn("failed to delete otp", slog.Int("id", int(otp.ID)), slog.Any("err", err))
+	}
+}
+
+// hashOTP returns the keyed hash of the code, bound to its purpose and identifier.
+func (s *APIV1Service) hashOTP(purpose OtpPurpose, identifier, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(string(purpose) + "\x00" + identifier + "\x00" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func normalizeOTPIdentifier(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestOTP(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+
+	getOTP := func(purpose OtpPurpose, email string) *store.OTP {
+		purposeString := string(purpose)
+		otp, err := testStore.GetOTP(ctx, &store.FindOTP{Purpose: &purposeString, Identifier: &email})
+		require.NoError(t, err)
+		return otp
+	}
+
+	t.Run("stores only a hash and is single use", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposePasswordReset, "Single@Example.com")
+		require.NoError(t, err)
+		otp := getOTP(OtpPurposePasswordReset, "single@example.com")
+		require.NotNil(t, otp)
+		require.NotEqual(t, code, otp.CodeHash)
+		require.NotContains(t, otp.CodeHash, code)
+
+		// Codes are bound to their purpose.
+		err = service.verifyOTP(ctx, OtpPurposeVerification, "single@example.com", code)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		require.NoError(t, service.verifyOTP(ctx, OtpPurposePasswordReset, "single@example.com", code))
+		require.Nil(t, getOTP(OtpPurposePasswordReset, "single@example.com"))
+		err = service.verifyOTP(ctx, OtpPurposePasswordReset, "single@example.com", code)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("throttles resends", func(t *testing.T) {
+		_, err := service.issueOTP(ctx, OtpPurposePasswordReset, "resend@example.com")
+		require.NoError(t, err)
+		_, err = service.issueOTP(ctx, OtpPurposePasswordReset, "resend@example.com")
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		// Another purpose is not throttled.
+		_, err = service.issueOTP(ctx, OtpPurposeVerification, "resend@example.com")
+		require.NoError(t, err)
+
+		// Once the interval has passed a new code replaces the old one.
+		otp := getOTP(OtpPurposePasswordReset, "resend@example.com")
+		otp.CreatedTs -= int64(otpResendInterval.Seconds())
+		_, err = testStore.UpsertOTP(ctx, otp)
+		require.NoError(t, err)
+		_, err = service.issueOTP(ctx, OtpPurposePasswordReset, "resend@example.com")
+		require.NoError(t, err)
+		require.NotEqual(t, otp.CodeHash, getOTP(OtpPurposePasswordReset, "resend@example.com").CodeHash)
+	})
+
+	t.Run("discards after too many attempts", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposeVerification, "attempts@example.com")
+		require.NoError(t, err)
+		for i := 1; i < otpMaxAttempts; i++ {
+			err := service.verifyOTP(ctx, OtpPurposeVerification, "attempts@example.com", "wrong")
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+			require.Equal(t, int32(i), getOTP(OtpPurposeVerification, "attempts@example.com").Attempts)
+		}
+		err = service.verifyOTP(ctx, OtpPurposeVerification, "attempts@example.com", "wrong")
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		require.Nil(t, getOTP(OtpPurposeVerification, "attempts@example.com"))
+		err = service.verifyOTP(ctx, OtpPurposeVerification, "attempts@example.com", code)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("redeems a code once under concurrent attempts", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposeVerification, "concurrent@example.com")
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		var redeemed atomic.Int32
+		for i := 0; i < 2*otpMaxAttempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if service.verifyOTP(ctx, OtpPurposeVerification, "concurrent@example.com", code) == nil {
+					redeemed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(1), redeemed.Load())
+	})
+
+	t.Run("rejects expired codes", func(t *testing.T) {
+		code, err := service.issueOTP(ctx, OtpPurposeVerification, "expired@example.com")
+		require.NoError(t, err)
+		otp := getOTP(OtpPurposeVerification, "expired@example.com")
+		otp.ExpiresTs = time.Now().Add(-time.Second).Unix()
+		_, err = testStore.UpsertOTP(ctx, otp)
+		require.NoError(t, err)
+
+		err = service.verifyOTP(ctx, OtpPurposeVerification, "expired@example.com", code)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Nil(t, getOTP(OtpPurposeVerification, "expired@example.com"))
+	})
+
+	t.Run("prunes expired codes when issuing", func(t *testing.T) {
+		_, err := service.issueOTP(ctx, OtpPurposeVerification, "abandoned@example.com")
+		require.NoError(t, err)
+		otp := getOTP(OtpPurposeVerification, "abandoned@example.com")
+		otp.ExpiresTs = time.Now().Add(-time.Second).Unix()
+		_, err = testStore.UpsertOTP(ctx, otp)
+		require.NoError(t, err)
+
+		_, err = service.issueOTP(ctx, OtpPurposeVerification, "other@example.com")
+		require.NoError(t, err)
+		require.Nil(t, getOTP(OtpPurposeVerification, "abandoned@example.com"))
+	})
+}
+
+func TestCreateUserEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+
+	_, err := testStore.CreateUser(ctx, &store.User{Username: "host", Role: store.RoleHost, Email: "host@example.com"})
+	require.NoError(t, err)
+	_, err = testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_GENERAL,
+		Value: &storepb.WorkspaceSetting_GeneralSetting{
+			GeneralSetting: &storepb.WorkspaceGeneralSetting{EnableEmailVerification: true},
+		},
+	})
+	require.NoError(t, err)
+
+	newRequest := func(otp string) *v1pb.CreateUserRequest {
+		return &v1pb.CreateUserRequest{
+			User: &v1pb.User{Username: "alice", Email: "alice@example.com", Password: "password"},
+			Otp:  otp,
+		}
+	}
+
+	_, err = service.CreateUser(ctx, newRequest(""))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = service.CreateUser(ctx, newRequest("000000"))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	code, err := service.issueOTP(ctx, OtpPurposeVerification, "alice@example.com")
+	require.NoError(t, err)
+	_, err = service.CreateUser(ctx, newRequest(code+"x"))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	// A sign-up failing on a taken username leaves the code for the next try.
+	takenRequest := newRequest(code)
+	takenRequest.User.Username = "host"
+	_, err = service.CreateUser(ctx, takenRequest)
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	user, err := service.CreateUser(ctx, newRequest(code))
+	require.NoError(t, err)
+	require.Equal(t, "alice", user.Username)
+}
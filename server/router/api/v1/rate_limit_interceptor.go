@@ -0,0 +1,411 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/imrany/wekalist/internal/util"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	defaultIPRequestsPerMinute      = 20
+	defaultAccountRequestsPerMinute = 5
+	defaultLockoutThreshold         = 5
+	defaultLockoutDuration          = 15 * time.Minute
+	// rateLimitSweepInterval is how often idle buckets and expired failure counters are dropped.
+	rateLimitSweepInterval = 10 * time.Minute
+)
+
+// rateLimitedMethods maps the rate limited methods to the account a request is made for.
+// An empty account means the request is only limited per client IP.
+var rateLimitedMethods = map[string]func(ctx context.Context, in *RateLimitInterceptor, request any) string{
+	"/wekalist.api.v1.AuthService/CreateSession": func(ctx context.Context, in *RateLimitInterceptor, request any) string {
+		createSessionRequest, ok := request.(*v1pb.CreateSessionRequest)
+		if !ok {
+			return ""
+		}
+		if credentials := createSessionRequest.GetPasswordCredentials(); credentials != nil {
+			return in.getAccount(ctx, credentials.Username)
+		}
+		// The second factor counts towards the lockout of the account the challenge was issued for.
+		if credentials := createSessionRequest.GetTwoFactorCredentials(); credentials != nil && credentials.Challenge != "" {
+			return in.getChallengeAccount(ctx, credentials.Challenge)
+		}
+		return ""
+	},
+	"/wekalist.api.v1.AuthService/BeginPasskeyAssertion": func(_ context.Context, _ *RateLimitInterceptor, _ any) string {
+		return ""
+	},
+	"/wekalist.api.v1.AuthService/RequestPasswordReset": func(ctx context.Context, in *RateLimitInterceptor, request any) string {
+		if resetRequest, ok := request.(*v1pb.RequestPasswordResetRequest); ok {
+			return in.getAccount(ctx, resetRequest.Email)
+		}
+		return ""
+	},
+	"/wekalist.api.v1.AuthService/ConfirmPasswordReset": func(ctx context.Context, in *RateLimitInterceptor, request any) string {
+		if confirmRequest, ok := request.(*v1pb.ConfirmPasswordResetRequest); ok {
+			return in.getAccount(ctx, confirmRequest.Email)
+		}
+		return ""
+	},
+	"/wekalist.api.v1.UserService/VerifyUser": func(_ context.Context, _ *RateLimitInterceptor, request any) string {
+		if verifyRequest, ok := request.(*v1pb.VerifyRequest); ok {
+			return normalizeRateLimitAccount(verifyRequest.Email)
+		}
+		return ""
+	},
+	"/wekalist.api.v1.UserService/CreateUser": func(_ context.Context, _ *RateLimitInterceptor, request any) string {
+		createUserRequest, ok := request.(*v1pb.CreateUserRequest)
+		if !ok || createUserRequest.User == nil {
+			return ""
+		}
+		if createUserRequest.User.Email != "" {
+			return normalizeRateLimitAccount(createUserRequest.User.Email)
+		}
+		return normalizeRateLimitAccount(createUserRequest.User.Username)
+	},
+}
+
+// lockoutMethods are the methods whose failures count towards locking the account.
+var lockoutMethods = map[string]bool{
+	"/wekalist.api.v1.AuthService/CreateSession": true,
+}
+
+// RateLimitInterceptor limits the sign-in, OTP and registration requests per client IP and per account,
+// and locks accounts temporarily after repeated failed sign-ins. The limits are configured by the
+// rate limit of the workspace general setting and kept in memory.
+type RateLimitInterceptor struct {
+	Store *store.Store
+	// secret verifies the two-factor challenges.
+	secret string
+	// TrustedProxies are the networks of the reverse proxies whose X-Forwarded-For entries are trusted.
+	TrustedProxies []*net.IPNet
+
+	mu        sync.Mutex
+	buckets   map[string]*rateLimitBucket
+	failures  map[string]*rateLimitFailures
+	lastSweep time.Time
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// rateLimitBucket is a token bucket refilled continuously up to the number of requests per minute.
+type rateLimitBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type rateLimitFailures struct {
+	count       int
+	lockedUntil time.Time
+	updatedAt   time.Time
+}
+
+type rateLimitConfig struct {
+	ipRequestsPerMinute      int
+	accountRequestsPerMinute int
+	lockoutThreshold         int
+	lockoutDuration          time.Duration
+}
+
+func NewRateLimitInterceptor(store *store.Store, secret string, trustedProxies []*net.IPNet) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		Store:          store,
+		secret:         secret,
+		TrustedProxies: trustedProxies,
+		buckets:        map[string]*rateLimitBucket{},
+		failures:       map[string]*rateLimitFailures{},
+		now:            time.Now,
+	}
+}
+
+func (in *RateLimitInterceptor) RateLimitInterceptor(ctx context.Context, request any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	getAccount, ok := rateLimitedMethods[serverInfo.FullMethod]
+	if !ok {
+		return handler(ctx, request)
+	}
+	workspaceGeneralSetting, err := in.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace general setting: %v", err)
+	}
+	if workspaceGeneralSetting.GetRateLimit().GetDisabled() {
+		return handler(ctx, request)
+	}
+	config := getRateLimitConfig(workspaceGeneralSetting.GetRateLimit())
+
+	method, ip, account := serverInfo.FullMethod, in.getClientIP(ctx), getAccount(ctx, in, request)
+	if err := in.allow(config, method, ip, account); err != nil {
+		slog.Warn("request rate limited", slog.String("method", method), slog.String("ip", ip), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	response, err := handler(ctx, request)
+	if account != "" && lockoutMethods[method] {
+		switch status.Code(err) {
+		case codes.OK:
+			// Passing the password step only issues a two-factor challenge, the account is not signed in yet.
+			if createSessionResponse, ok := response.(*v1pb.CreateSessionResponse); !ok || createSessionResponse.TwoFactorChallenge == "" {
+				in.resetFailures(method, account)
+			}
+		case codes.InvalidArgument, codes.Unauthenticated:
+			in.recordFailure(config, method, account)
+		}
+	}
+	return response, err
+}
+
+// allow takes a token from the buckets of the client IP and of the account,
+// or returns a ResourceExhausted error telling when to retry.
+func (in *RateLimitInterceptor) allow(config rateLimitConfig, method, ip, account string) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	now := in.now()
+	in.sweep(now)
+
+	if account != "" {
+		if failures := in.failures[method+"|"+account]; failures != nil && failures.lockedUntil.After(now) {
+			return newRateLimitError("too many failed attempts, the account is temporarily locked", failures.lockedUntil.Sub(now))
+		}
+	}
+	var ipBucket, accountBucket *rateLimitBucket
+	if ip != "" {
+		ipBucket = in.getBucket(method+"|ip|"+ip, config.ipRequestsPerMinute, now)
+		if ipBucket.tokens < 1 {
+			return newRateLimitError("too many requests", ipBucket.retryAfter(config.ipRequestsPerMinute))
+		}
+	}
+	if account != "" {
+		accountBucket = in.getBucket(method+"|account|"+account, config.accountRequestsPerMinute, now)
+		if accountBucket.tokens < 1 {
+			return newRateLimitError("too many requests for this account", accountBucket.retryAfter(config.accountRequestsPerMinute))
+		}
+	}
+	// Tokens are only taken once every limit allows the request.
+	if ipBucket != nil {
+		ipBucket.tokens--
+	}
+	if accountBucket != nil {
+		accountBucket.tokens--
+	}
+	return nil
+}
+
+func (in *RateLimitInterceptor) recordFailure(config rateLimitConfig, method, account string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	now := in.now()
+	key := method + "|" + account
+	failures := in.failures[key]
+	if failures == nil {
+		failures = &rateLimitFailures{}
+		in.failures[key] = failures
+	}
+	failures.count++
+	failures.updatedAt = now
+	if failures.count >= config.lockoutThreshold {
+		failures.count = 0
+		failures.lockedUntil = now.Add(config.lockoutDuration)
+		slog.Warn("account locked after failed sign-ins", slog.String("method", method), slog.String("account", account))
+	}
+}
+
+func (in *RateLimitInterceptor) resetFailures(method, account string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	delete(in.failures, method+"|"+account)
+}
+
+// getBucket returns the bucket of the key refilled up to now. New buckets are full.
+func (in *RateLimitInterceptor) getBucket(key string, requestsPerMinute int, now time.Time) *rateLimitBucket {
+	bucket := in.buckets[key]
+	if bucket == nil {
+		bucket = &rateLimitBucket{tokens: float64(requestsPerMinute), updatedAt: now}
+		in.buckets[key] = bucket
+		return bucket
+	}
+	elapsed := now.Sub(bucket.updatedAt)
+	bucket.tokens = math.Min(float64(requestsPerMinute), bucket.tokens+elapsed.Minutes()*float64(requestsPerMinute))
+	bucket.updatedAt = now
+	return bucket
+}
+
+// sweep drops the buckets refilled by now and the failure counters which are neither recent nor locking.
+func (in *RateLimitInterceptor) sweep(now time.Time) {
+	if now.Sub(in.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	in.lastSweep = now
+	for key, bucket := range in.buckets {
+		if now.Sub(bucket.updatedAt) > time.Minute {
+			delete(in.buckets, key)
+		}
+	}
+	for key, failures := range in.failures {
+		if failures.lockedUntil.Before(now) && now.Sub(failures.updatedAt) > defaultLockoutDuration {
+			delete(in.failures, key)
+		}
+	}
+}
+
+// retryAfter returns how long until the bucket holds a token again.
+func (bucket *rateLimitBucket) retryAfter(requestsPerMinute int) time.Duration {
+	return time.Duration((1 - bucket.tokens) / float64(requestsPerMinute) * float64(time.Minute))
+}
+
+func getRateLimitConfig(rateLimit *storepb.WorkspaceRateLimit) rateLimitConfig {
+	config := rateLimitConfig{
+		ipRequestsPerMinute:      defaultIPRequestsPerMinute,
+		accountRequestsPerMinute: defaultAccountRequestsPerMinute,
+		lockoutThreshold:         defaultLockoutThreshold,
+		lockoutDuration:          defaultLockoutDuration,
+	}
+	if rateLimit.GetIpRequestsPerMinute() > 0 {
+		config.ipRequestsPerMinute = int(rateLimit.GetIpRequestsPerMinute())
+	}
+	if rateLimit.GetAccountRequestsPerMinute() > 0 {
+		config.accountRequestsPerMinute = int(rateLimit.GetAccountRequestsPerMinute())
+	}
+	if rateLimit.GetLockoutThreshold() > 0 {
+		config.lockoutThreshold = int(rateLimit.GetLockoutThreshold())
+	}
+	if rateLimit.GetLockoutSeconds() > 0 {
+		config.lockoutDuration = time.Duration(rateLimit.GetLockoutSeconds()) * time.Second
+	}
+	return config
+}
+
+// newRateLimitError returns a ResourceExhausted error carrying the retry delay as RetryInfo.
+func newRateLimitError(message string, retryAfter time.Duration) error {
+	retryAfter = retryAfter.Round(time.Second)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("%s, retry in %s", message, retryAfter))
+	withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func normalizeRateLimitAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// getAccount returns the account of the user signing in with the username or email, found the same way as
+// CreateSession does, so that both count towards the same limits. Unknown users are limited by the identifier.
+func (in *RateLimitInterceptor) getAccount(ctx context.Context, identifier string) string {
+	user, err := in.Store.GetUser(ctx, &store.FindUser{Username: &identifier})
+	if err == nil && user == nil {
+		user, err = in.Store.GetUser(ctx, &store.FindUser{Email: &identifier})
+	}
+	if err != nil {
+		slog.Warn("failed to get user for rate limiting", slog.Any("err", err))
+	}
+	if user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	return normalizeRateLimitAccount(identifier)
+}
+
+// getChallengeAccount returns the account of the user a two-factor challenge was issued for,
+// or an empty account if the challenge is invalid.
+func (in *RateLimitInterceptor) getChallengeAccount(ctx context.Context, challenge string) string {
+	claims := &ClaimsMessage{}
+	if err := parseJWT(ctx, in.Store, in.secret, challenge, claims, jwt.WithAudience(TwoFactorChallengeAudienceName), jwt.WithExpirationRequired()); err != nil {
+		return ""
+	}
+	userID, err := util.ConvertStringToInt32(claims.Subject)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// getClientIP returns the client IP of the request: the right-most address of the X-Forwarded-For chain,
+// followed by the peer address, which is not a trusted proxy. Clients can only prepend addresses to the chain.
+// The gateway serving the HTTP API connects through the loopback interface or a unix socket and appends the
+// address of the HTTP client to the last X-Forwarded-For value.
+func (in *RateLimitInterceptor) getClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	peerIP := ""
+	if addr, ok := p.Addr.(*net.TCPAddr); ok {
+		peerIP = addr.IP.String()
+		if !in.isTrustedProxy(addr.IP) {
+			return peerIP
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwardedFor := md.Get("x-forwarded-for")
+	if len(forwardedFor) == 0 {
+		return peerIP
+	}
+	hops := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if ip := net.ParseIP(hop); i == 0 || ip == nil || !in.isTrustedProxy(ip) {
+			return hop
+		}
+	}
+	return peerIP
+}
+
+func (in *RateLimitInterceptor) isTrustedProxy(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, network := range in.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses the IP addresses and CIDR networks of trusted reverse proxies,
+// separated by commas or spaces.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, proxy := range strings.FieldsFunc(strings.Join(proxies, ","), func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy %q", proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,218 @@
+package v1
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestRateLimitInterceptor(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+
+	setRateLimit := func(rateLimit *storepb.WorkspaceRateLimit) {
+		_, err := testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+			Key:   storepb.WorkspaceSettingKey_GENERAL,
+			Value: &storepb.WorkspaceSetting_GeneralSetting{GeneralSetting: &storepb.WorkspaceGeneralSetting{RateLimit: rateLimit}},
+		})
+		require.NoError(t, err)
+	}
+	newInterceptor := func() (*RateLimitInterceptor, *time.Time) {
+		now := time.Now()
+		interceptor := NewRateLimitInterceptor(testStore, "test-secret", nil)
+		interceptor.now = func() time.Time { return now }
+		return interceptor, &now
+	}
+	// gatewayContext returns the context of a request from the ip through the gateway.
+	gatewayContext := func(ip string) context.Context {
+		requestCtx := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8081}})
+		return metadata.NewIncomingContext(requestCtx, metadata.Pairs("x-forwarded-for", ip))
+	}
+	// signIn succeeds only with the password "password".
+	signIn := func(interceptor *RateLimitInterceptor, ip, username, password string) error {
+		requestCtx := gatewayContext(ip)
+		request := &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+				PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: username, Password: password},
+			},
+		}
+		_, err := interceptor.RateLimitInterceptor(requestCtx, request, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.AuthService/CreateSession"}, func(context.Context, any) (any, error) {
+			if password != "password" {
+				return nil, status.Errorf(codes.InvalidArgument, unmatchedUsernameAndPasswordError)
+			}
+			return &v1pb.CreateSessionResponse{}, nil
+		})
+		return err
+	}
+	requireRetryAfter := func(err error, retryAfter time.Duration) {
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		details := status.Convert(err).Details()
+		require.Len(t, details, 1)
+		retryInfo, ok := details[0].(*errdetails.RetryInfo)
+		require.True(t, ok)
+		require.Equal(t, retryAfter, retryInfo.RetryDelay.AsDuration())
+	}
+
+	t.Run("limits requests per client IP", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 3, AccountRequestsPerMinute: 10})
+		interceptor, now := newInterceptor()
+		for _, username := range []string{"a", "b", "c"} {
+			require.NoError(t, signIn(interceptor, "10.0.0.1", username, "password"))
+		}
+		requireRetryAfter(signIn(interceptor, "10.0.0.1", "d", "password"), 20*time.Second)
+		require.NoError(t, signIn(interceptor, "10.0.0.2", "d", "password"))
+
+		*now = now.Add(20 * time.Second)
+		require.NoError(t, signIn(interceptor, "10.0.0.1", "d", "password"))
+	})
+
+	t.Run("ignores the addresses prepended by the client", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 2, AccountRequestsPerMinute: 10})
+		interceptor, _ := newInterceptor()
+		require.NoError(t, signIn(interceptor, "1.1.1.1, 10.0.0.1", "a", "password"))
+		require.NoError(t, signIn(interceptor, "2.2.2.2, 10.0.0.1", "b", "password"))
+		requireRetryAfter(signIn(interceptor, "3.3.3.3, 10.0.0.1", "c", "password"), 30*time.Second)
+
+		// A trusted proxy in front of the server forwards the address of the client.
+		trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8,192.168.1.1"})
+		require.NoError(t, err)
+		interceptor.TrustedProxies = trustedProxies
+		require.Equal(t, "203.0.113.1", interceptor.getClientIP(gatewayContext("1.1.1.1, 203.0.113.1, 192.168.1.1, 10.0.0.1")))
+		// A client connecting directly cannot forge its address.
+		directCtx := peer.NewContext(metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "1.1.1.1")), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.2"), Port: 4000}})
+		require.Equal(t, "203.0.113.2", interceptor.getClientIP(directCtx))
+
+		_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+		require.Error(t, err)
+		_, err = ParseTrustedProxies([]string{"proxy"})
+		require.Error(t, err)
+	})
+
+	t.Run("limits requests per account", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 10, AccountRequestsPerMinute: 2})
+		interceptor, _ := newInterceptor()
+		require.NoError(t, signIn(interceptor, "10.0.0.1", "alice", "password"))
+		require.NoError(t, signIn(interceptor, "10.0.0.2", "Alice", "password"))
+		requireRetryAfter(signIn(interceptor, "10.0.0.3", "alice ", "password"), 30*time.Second)
+		require.NoError(t, signIn(interceptor, "10.0.0.3", "bob", "password"))
+	})
+
+	t.Run("locks the account after repeated failures", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 100, AccountRequestsPerMinute: 100, LockoutThreshold: 3, LockoutSeconds: 60})
+		interceptor, now := newInterceptor()
+		require.Equal(t, codes.InvalidArgument, status.Code(signIn(interceptor, "10.0.0.1", "alice", "wrong")))
+		require.Equal(t, codes.InvalidArgument, status.Code(signIn(interceptor, "10.0.0.1", "alice", "wrong")))
+		// A successful sign-in resets the failures.
+		require.NoError(t, signIn(interceptor, "10.0.0.1", "alice", "password"))
+		for i := 0; i < 3; i++ {
+			require.Equal(t, codes.InvalidArgument, status.Code(signIn(interceptor, "10.0.0.2", "alice", "wrong")))
+		}
+		// The account is locked from every IP, even with the right password.
+		requireRetryAfter(signIn(interceptor, "10.0.0.3", "alice", "password"), time.Minute)
+		require.NoError(t, signIn(interceptor, "10.0.0.3", "bob", "password"))
+
+		*now = now.Add(time.Minute)
+		require.NoError(t, signIn(interceptor, "10.0.0.3", "alice", "password"))
+	})
+
+	user, err := testStore.CreateUser(ctx, &store.User{Username: "carol", Email: "carol@example.com", Role: store.RoleUser})
+	require.NoError(t, err)
+	service := &APIV1Service{Secret: "test-secret", Store: testStore}
+	challenge, err := service.generateTwoFactorChallenge(ctx, user)
+	require.NoError(t, err)
+	// signInWithTwoFactor signs in with the code of the challenge, and succeeds only with the code "123456".
+	signInWithTwoFactor := func(interceptor *RateLimitInterceptor, challenge, code string) error {
+		request := &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_TwoFactorCredentials_{
+				TwoFactorCredentials: &v1pb.CreateSessionRequest_TwoFactorCredentials{Challenge: challenge, Code: code},
+			},
+		}
+		_, err := interceptor.RateLimitInterceptor(gatewayContext("10.0.0.1"), request, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.AuthService/CreateSession"}, func(context.Context, any) (any, error) {
+			if code != "123456" {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid two-factor code")
+			}
+			return &v1pb.CreateSessionResponse{}, nil
+		})
+		return err
+	}
+
+	t.Run("limits the username and email of an account together", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 10, AccountRequestsPerMinute: 2})
+		interceptor, _ := newInterceptor()
+		require.NoError(t, signIn(interceptor, "10.0.0.1", "carol", "password"))
+		require.NoError(t, signIn(interceptor, "10.0.0.2", "carol@example.com", "password"))
+		requireRetryAfter(signIn(interceptor, "10.0.0.3", "carol", "password"), 30*time.Second)
+	})
+
+	t.Run("counts two-factor failures towards the account lockout", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 100, AccountRequestsPerMinute: 100, LockoutThreshold: 3, LockoutSeconds: 60})
+		interceptor, _ := newInterceptor()
+		require.Equal(t, codes.InvalidArgument, status.Code(signIn(interceptor, "10.0.0.1", "carol", "wrong")))
+		require.Equal(t, codes.InvalidArgument, status.Code(signInWithTwoFactor(interceptor, challenge, "000000")))
+		// Passing the password step again for a new challenge does not reset the failures.
+		passwordStep := &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+				PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "carol@example.com", Password: "password"},
+			},
+		}
+		_, err := interceptor.RateLimitInterceptor(gatewayContext("10.0.0.1"), passwordStep, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.AuthService/CreateSession"}, func(context.Context, any) (any, error) {
+			return &v1pb.CreateSessionResponse{TwoFactorChallenge: challenge}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(signInWithTwoFactor(interceptor, challenge, "111111")))
+		requireRetryAfter(signInWithTwoFactor(interceptor, challenge, "123456"), time.Minute)
+		requireRetryAfter(signIn(interceptor, "10.0.0.1", "carol", "password"), time.Minute)
+	})
+
+	t.Run("limits password resets per account", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 10, AccountRequestsPerMinute: 1})
+		interceptor, _ := newInterceptor()
+		for _, method := range []string{"RequestPasswordReset", "ConfirmPasswordReset"} {
+			fullMethod := "/wekalist.api.v1.AuthService/" + method
+			var request any = &v1pb.RequestPasswordResetRequest{Email: "carol@example.com"}
+			if method == "ConfirmPasswordReset" {
+				request = &v1pb.ConfirmPasswordResetRequest{Email: "carol@example.com", Code: "123456", NewPassword: "password"}
+			}
+			handler := func(context.Context, any) (any, error) { return &emptypb.Empty{}, nil }
+			_, err := interceptor.RateLimitInterceptor(gatewayContext("10.0.0.1"), request, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+			require.NoError(t, err)
+			_, err = interceptor.RateLimitInterceptor(gatewayContext("10.0.0.2"), request, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+			requireRetryAfter(err, time.Minute)
+		}
+	})
+
+	t.Run("can be disabled", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{Disabled: true, IpRequestsPerMinute: 1})
+		interceptor, _ := newInterceptor()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, signIn(interceptor, "10.0.0.1", "alice", "password"))
+		}
+	})
+
+	t.Run("does not limit other methods", func(t *testing.T) {
+		setRateLimit(&storepb.WorkspaceRateLimit{IpRequestsPerMinute: 1})
+		interceptor, _ := newInterceptor()
+		for i := 0; i < 3; i++ {
+			_, err := interceptor.RateLimitInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.MemoService/ListMemos"}, func(context.Context, any) (any, error) {
+				return nil, nil
+			})
+			require.NoError(t, err)
+		}
+	})
+}
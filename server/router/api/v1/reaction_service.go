@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
 	"github.com/imrany/wekalist/store"
 )
 
@@ -48,6 +49,9 @@ func (s *APIV1Service) UpsertMemoReaction(ctx context.Context, request *v1pb.Ups
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to upsert reaction")
 	}
+	if err := s.notifyMemoReaction(ctx, user, reaction); err != nil {
+		return nil, err
+	}
 
 	reactionMessage, err := s.convertReactionFromStore(ctx, reaction)
 	if err != nil {
@@ -71,6 +75,50 @@ func (s *APIV1Service) DeleteMemoReaction(ctx context.Context, request *v1pb.Del
 	return &emptypb.Empty{}, nil
 }
 
+// notifyMemoReaction notifies the creator of the memo reacted to, unless they reacted themselves.
+func (s *APIV1Service) notifyMemoReaction(ctx context.Context, user *store.User, reaction *store.Reaction) error {
+	memoUID, err := ExtractMemoUIDFromName(reaction.ContentID)
+	if err != nil {
+		// Reactions to other contents than memos are not notified.
+		return nil
+	}
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{UID: &memoUID})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get memo")
+	}
+	if memo == nil || memo.CreatorID == user.ID {
+		return nil
+	}
+
+	activity, err := s.Store.CreateActivity(ctx, &store.Activity{
+		CreatorID: user.ID,
+		Type:      store.ActivityTypeMemoReaction,
+		Level:     store.ActivityLevelInfo,
+		Payload: &storepb.ActivityPayload{
+			MemoReaction: &storepb.ActivityMemoReactionPayload{
+				MemoId:       memo.ID,
+				ReactionType: reaction.ReactionType,
+			},
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create activity")
+	}
+	s.NotifyUser(ctx, &NotificationEvent{
+		Type:       storepb.NotificationsUserSetting_REACTION,
+		SenderID:   user.ID,
+		ReceiverID: memo.CreatorID,
+		InboxMessage: &storepb.InboxMessage{
+			Type:       storepb.InboxMessage_MEMO_REACTION,
+			ActivityId: &activity.ID,
+		},
+		Title: fmt.Sprintf("%s reacted %s to your memo", getNotificationSenderName(user), reaction.ReactionType),
+		Body:  truncateNotificationText(memo.Content),
+		Link:  fmt.Sprintf("/%s%s", MemoNamePrefix, memo.UID),
+	})
+	return nil
+}
+
 func (s *APIV1Service) convertReactionFromStore(ctx context.Context, reaction *store.Reaction) (*v1pb.Reaction, error) {
 	creator, err := s.Store.GetUser(ctx, &store.FindUser{
 		ID: &reaction.CreatorID,
@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/internal/util"
+	"github.com/imrany/wekalist/plugin/idp"
+	"github.com/imrany/wekalist/plugin/idp/saml"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// SAMLRequestAudienceName is the audience name of the IDs of SAML authentication requests.
+	SAMLRequestAudienceName = "user.saml-request"
+	// SAMLLoginAudienceName is the audience name of the login codes issued by the assertion consumer service.
+	SAMLLoginAudienceName = "user.saml-login"
+	// samlRequestDuration is how long the user has to sign in with the identity provider.
+	samlRequestDuration = 10 * time.Minute
+	// samlLoginDuration is how long the web app has to exchange a login code for a session.
+	samlLoginDuration = 2 * time.Minute
+	// SAMLNonceCookieName is the name of the cookie binding a SAML sign-in to the browser that started it.
+	SAMLNonceCookieName = "saml_nonce"
+	// samlRequestOTPPurpose is the OTP purpose under which the authentication requests are stored until answered.
+	samlRequestOTPPurpose OtpPurpose = "saml_request"
+	// samlLoginOTPPurpose is the OTP purpose under which the login codes are stored until exchanged.
+	samlLoginOTPPurpose OtpPurpose = "saml_login"
+)
+
+// samlRequestClaims makes the ID of an authentication request verifiable, and carries the hash
+// of the nonce of the browser that made the request over to the login code.
+type samlRequestClaims struct {
+	IdpID     int32  `json:"idp_id"`
+	NonceHash string `json:"nonce_hash"`
+	jwt.RegisteredClaims
+}
+
+// samlLoginClaims carries the user information of a verified assertion
+// from the assertion consumer service to CreateSession.
+type samlLoginClaims struct {
+	IdpID       int32  `json:"idp_id"`
+	Identifier  string `json:"identifier"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatar_url"`
+	jwt.RegisteredClaims
+}
+
+// registerSAMLRoutes registers the SAML 2.0 service provider endpoints of the SAML identity providers.
+// The sign-in page redirects to the login endpoint with a state, which is passed back to /auth/callback
+// along with a login code once the identity provider posts a valid response to the ACS.
+// Authentication requests and login codes are used once, and a login code is only exchanged
+// by the browser holding the nonce cookie set by the login endpoint.
+func (s *APIV1Service) registerSAMLRoutes(echoServer *echo.Echo) {
+	echoServer.GET("/auth/saml/:idp/metadata", s.handleSAMLMetadata)
+	echoServer.GET("/auth/saml/:idp/login", s.handleSAMLLogin)
+	echoServer.POST("/auth/saml/:idp/acs", s.handleSAMLACS)
+}
+
+func (s *APIV1Service) handleSAMLMetadata(c echo.Context) error {
+	_, samlIdentityProvider, err := s.getSAMLIdentityProvider(c.Request().Context(), c.Param("idp"))
+	if err != nil {
+		return newSAMLHTTPError(err)
+	}
+	metadata, err := samlIdentityProvider.Metadata()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+func (s *APIV1Service) handleSAMLLogin(c echo.Context) error {
+	identityProvider, samlIdentityProvider, err := s.getSAMLIdentityProvider(c.Request().Context(), c.Param("idp"))
+	if err != nil {
+		return newSAMLHTTPError(err)
+	}
+	nonce := util.GenUUID()
+	requestID, err := s.generateSAMLRequestID(c.Request().Context(), identityProvider.Id, hashSAMLNonce(nonce))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to generate request id")
+	}
+	authnRequestURL, err := samlIdentityProvider.AuthnRequestURL(requestID, c.QueryParam("state"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	// The identity provider posts back cross-site, so the nonce is checked when the web app exchanges the login code.
+	c.SetCookie(&http.Cookie{
+		Name:     SAMLNonceCookieName,
+		Value:    nonce,
+		Path:     "/",
+		MaxAge:   int((samlRequestDuration + samlLoginDuration).Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.Profile.InstanceURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusFound, authnRequestURL)
+}
+
+func (s *APIV1Service) handleSAMLACS(c echo.Context) error {
+	identityProvider, samlIdentityProvider, err := s.getSAMLIdentityProvider(c.Request().Context(), c.Param("idp"))
+	if err != nil {
+		return newSAMLHTTPError(err)
+	}
+	requestID, err := saml.InResponseTo(c.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	// Only responses to requests made by the login endpoint are accepted, identity provider initiated sign-ins are not.
+	requestClaims, err := s.parseSAMLRequestID(c.Request().Context(), identityProvider.Id, requestID)
+	if err != nil {
+		return newSAMLHTTPError(err)
+	}
+	userInfo, err := samlIdentityProvider.ParseResponse(c.Request(), requestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	// Each request is answered once, so that a captured response cannot be replayed.
+	if err := s.consumeSAMLRequest(c.Request().Context(), requestClaims); err != nil {
+		return newSAMLHTTPError(err)
+	}
+	loginCode, err := s.generateSAMLLoginCode(c.Request().Context(), identityProvider.Id, userInfo, requestClaims.NonceHash)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to generate login code")
+	}
+	query := url.Values{
+		"code":  {loginCode},
+		"state": {c.FormValue("RelayState")},
+	}
+	return c.Redirect(http.StatusSeeOther, strings.TrimSuffix(s.Profile.InstanceURL, "/")+"/auth/callback?"+query.Encode())
+}
+
+// getSAMLIdentityProvider returns the SAML identity provider with the given ID,
+// with the service provider endpoints based on the instance URL.
+func (s *APIV1Service) getSAMLIdentityProvider(ctx context.Context, rawID string) (*storepb.IdentityProvider, *saml.IdentityProvider, error) {
+	id, err := util.ConvertStringToInt32(rawID)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid identity provider id")
+	}
+	identityProvider, err := s.Store.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to get identity provider, error: %v", err)
+	}
+	if identityProvider == nil || identityProvider.Type != storepb.IdentityProvider_SAML {
+		return nil, nil, status.Errorf(codes.NotFound, "identity provider not found")
+	}
+	// The entity ID and the ACS URL are registered with the identity provider, so they must not depend on the request.
+	if s.Profile == nil || s.Profile.InstanceURL == "" {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "instance URL is required for SAML sign-in")
+	}
+	baseURL := fmt.Sprintf("%s/auth/saml/%d", strings.TrimSuffix(s.Profile.InstanceURL, "/"), identityProvider.Id)
+	samlIdentityProvider, err := saml.NewIdentityProvider(identityProvider.Config.GetSamlConfig(), baseURL+"/metadata", baseURL+"/acs")
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to create saml identity provider, error: %v", err)
+	}
+	return identityProvider, samlIdentityProvider, nil
+}
+
+func (s *APIV1Service) generateSAMLRequestID(ctx context.Context, idpID int32, nonceHash string) (string, error) {
+	id := util.GenUUID()
+	if err := s.storeOneTimeCode(ctx, samlRequestOTPPurpose, id, nonceHash, samlRequestDuration); err != nil {
+		return "", err
+	}
+	claims := &samlRequestClaims{
+		IdpID:     idpID,
+		NonceHash: nonceHash,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{SAMLRequestAudienceName},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(samlRequestDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        id,
+		},
+	}
+	return s.signJWT(ctx, claims)
+}
+
+func (s *APIV1Service) parseSAMLRequestID(ctx context.Context, idpID int32, requestID string) (*samlRequestClaims, error) {
+	claims := &samlRequestClaims{}
+	err := s.parseJWT(ctx, requestID, claims, jwt.WithAudience(SAMLRequestAudienceName), jwt.WithExpirationRequired())
+	if err != nil || claims.IdpID != idpID {
+		return nil, status.Errorf(codes.Unauthenticated, "unknown or expired authentication request")
+	}
+	return claims, nil
+}
+
+// consumeSAMLRequest deletes the stored authentication request. Only the first caller succeeds.
+func (s *APIV1Service) consumeSAMLRequest(ctx context.Context, claims *samlRequestClaims) error {
+	redeemed, err := s.redeemOneTimeCode(ctx, samlRequestOTPPurpose, claims.ID, claims.NonceHash)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to consume authentication request: %v", err)
+	}
+	if !redeemed {
+		return status.Errorf(codes.Unauthenticated, "unknown or expired authentication request")
+	}
+	return nil
+}
+
+func (s *APIV1Service) generateSAMLLoginCode(ctx context.Context, idpID int32, userInfo *idp.IdentityProviderUserInfo, nonceHash string) (string, error) {
+	id := util.GenUUID()
+	if err := s.storeOneTimeCode(ctx, samlLoginOTPPurpose, id, nonceHash, samlLoginDuration); err != nil {
+		return "", err
+	}
+	claims := &samlLoginClaims{
+		IdpID:       idpID,
+		Identifier:  userInfo.Identifier,
+		DisplayName: userInfo.DisplayName,
+		Email:       userInfo.Email,
+		AvatarURL:   userInfo.AvatarURL,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{SAMLLoginAudienceName},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(samlLoginDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        id,
+		},
+	}
+	return s.signJWT(ctx, claims)
+}
+
+// getSAMLUserInfo returns the user information of the assertion a login code was issued for.
+func (s *APIV1Service) getSAMLUserInfo(ctx context.Context, identityProvider *storepb.IdentityProvider, credentials *v1pb.CreateSessionRequest_SSOCredentials) (*idp.IdentityProviderUserInfo, error) {
+	claims := &samlLoginClaims{}
+	if err := s.parseJWT(ctx, credentials.Code, claims, jwt.WithAudience(SAMLLoginAudienceName), jwt.WithExpirationRequired()); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired login code")
+	}
+	if claims.IdpID != identityProvider.Id {
+		return nil, status.Errorf(codes.InvalidArgument, "login code was issued for another identity provider")
+	}
+	// The code is exchanged once, by the browser that started the sign-in.
+	md, _ := metadata.FromIncomingContext(ctx)
+	redeemed, err := s.redeemOneTimeCode(ctx, samlLoginOTPPurpose, claims.ID, hashSAMLNonce(getCookieFromMetadata(md, SAMLNonceCookieName)))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to consume login code: %v", err)
+	}
+	if !redeemed {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired login code")
+	}
+	return &idp.IdentityProviderUserInfo{
+		Identifier:  claims.Identifier,
+		DisplayName: claims.DisplayName,
+		Email:       claims.Email,
+		AvatarURL:   claims.AvatarURL,
+	}, nil
+}
+
+// hashSAMLNonce returns the hash of the nonce, which is safe to send to the identity provider.
+func hashSAMLNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSAMLHTTPError(err error) error {
+	return echo.NewHTTPError(runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+}
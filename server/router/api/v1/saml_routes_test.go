@@ -0,0 +1,235 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/internal/profile"
+	"github.com/imrany/wekalist/plugin/idp/saml"
+	"github.com/imrany/wekalist/plugin/idp/saml/samltest"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+func TestSAMLSignIn(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+	service := &APIV1Service{Secret: "test-secret", Profile: &profile.Profile{InstanceURL: "http://localhost:8080"}, Store: testStore}
+
+	identityProvider := samltest.NewIdentityProvider("https://idp.example.com")
+	identityProvider.Session.NameID = "alice"
+	identityProvider.Session.CustomAttributes = []gosaml.Attribute{
+		{Name: "displayName", Values: []gosaml.AttributeValue{{Value: "Alice"}}},
+		{Name: "email", Values: []gosaml.AttributeValue{{Value: "alice@example.com"}}},
+	}
+	config := &storepb.IdentityProviderConfig{
+		Config: &storepb.IdentityProviderConfig_SamlConfig{
+			SamlConfig: &storepb.SAMLConfig{
+				IdpEntityId:    identityProvider.EntityID(),
+				IdpSsoUrl:      identityProvider.SSOURL(),
+				IdpCertificate: identityProvider.Certificate(),
+			},
+		},
+	}
+	storedIdentityProvider, err := testStore.CreateIdentityProvider(ctx, &storepb.IdentityProvider{
+		Name:   "SAML",
+		Type:   storepb.IdentityProvider_SAML,
+		Config: config,
+	})
+	require.NoError(t, err)
+	basePath := fmt.Sprintf("/auth/saml/%d", storedIdentityProvider.Id)
+	state := fmt.Sprintf("auth.signin.SAML-%d", storedIdentityProvider.Id)
+
+	e := echo.New()
+	service.registerSAMLRoutes(e)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	postResponse := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, basePath+"/acs", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		return serve(req)
+	}
+
+	rec := serve(httptest.NewRequest(http.MethodGet, basePath+"/metadata", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `entityID="http://localhost:8080`+basePath+`/metadata"`)
+	require.NoError(t, identityProvider.RegisterServiceProvider(rec.Body.Bytes()))
+
+	// login starts a sign-in and returns the authentication request URL and the nonce cookie of the browser.
+	login := func() (string, string) {
+		rec := serve(httptest.NewRequest(http.MethodGet, basePath+"/login?state="+url.QueryEscape(state), nil))
+		require.Equal(t, http.StatusFound, rec.Code)
+		location := rec.Header().Get(echo.HeaderLocation)
+		require.True(t, strings.HasPrefix(location, identityProvider.SSOURL()+"?"))
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, SAMLNonceCookieName, cookies[0].Name)
+		require.True(t, cookies[0].HttpOnly)
+		return location, cookies[0].Name + "=" + cookies[0].Value
+	}
+	// signIn exchanges the login code for a session from the browser with the cookie.
+	signIn := func(cookie string, idpID int32, code string) (*v1pb.CreateSessionResponse, *samlTestStream, error) {
+		stream := &samlTestStream{}
+		sessionCtx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(ctx, metadata.Pairs("cookie", cookie)), stream)
+		response, err := service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_SsoCredentials{
+				SsoCredentials: &v1pb.CreateSessionRequest_SSOCredentials{
+					IdpId:       idpID,
+					Code:        code,
+					RedirectUri: "http://localhost:8080/auth/callback",
+				},
+			},
+		})
+		return response, stream, err
+	}
+	// respond has the identity provider answer a new sign-in, and returns the code from the callback and the cookie.
+	respond := func(t *testing.T) (string, string) {
+		authnRequestURL, cookie := login()
+		_, form, err := identityProvider.Respond(authnRequestURL)
+		require.NoError(t, err)
+		rec := postResponse(form)
+		require.Equal(t, http.StatusSeeOther, rec.Code)
+		callbackURL, err := url.Parse(rec.Header().Get(echo.HeaderLocation))
+		require.NoError(t, err)
+		return callbackURL.Query().Get("code"), cookie
+	}
+
+	t.Run("signs in through the assertion consumer service", func(t *testing.T) {
+		authnRequestURL, cookie := login()
+		acsURL, form, err := identityProvider.Respond(authnRequestURL)
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:8080"+basePath+"/acs", acsURL)
+		rec := postResponse(form)
+		require.Equal(t, http.StatusSeeOther, rec.Code)
+		callbackURL, err := url.Parse(rec.Header().Get(echo.HeaderLocation))
+		require.NoError(t, err)
+		require.Equal(t, "/auth/callback", callbackURL.Path)
+		require.Equal(t, state, callbackURL.Query().Get("state"))
+
+		response, stream, err := signIn(cookie, storedIdentityProvider.Id, callbackURL.Query().Get("code"))
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Equal(t, "Alice", response.User.DisplayName)
+		require.Len(t, stream.header.Get("Set-Cookie"), 1)
+
+		username := "alice"
+		user, err := testStore.GetUser(ctx, &store.FindUser{Username: &username})
+		require.NoError(t, err)
+		require.Equal(t, "alice@example.com", user.Email)
+
+		_, _, err = signIn(cookie, storedIdentityProvider.Id, "code")
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("responses and login codes are single use", func(t *testing.T) {
+		authnRequestURL, cookie := login()
+		_, form, err := identityProvider.Respond(authnRequestURL)
+		require.NoError(t, err)
+		rec := postResponse(form)
+		require.Equal(t, http.StatusSeeOther, rec.Code)
+		callbackURL, err := url.Parse(rec.Header().Get(echo.HeaderLocation))
+		require.NoError(t, err)
+
+		// A captured response cannot be posted again.
+		rec = postResponse(form)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		code := callbackURL.Query().Get("code")
+		_, _, err = signIn(cookie, storedIdentityProvider.Id, code)
+		require.NoError(t, err)
+		_, _, err = signIn(cookie, storedIdentityProvider.Id, code)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("login codes are bound to the browser that started the sign-in", func(t *testing.T) {
+		code, cookie := respond(t)
+		_, otherCookie := login()
+		_, _, err := signIn(otherCookie, storedIdentityProvider.Id, code)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, _, err = signIn("", storedIdentityProvider.Id, code)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		// The failed exchanges leave the code to its browser.
+		_, _, err = signIn(cookie, storedIdentityProvider.Id, code)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects responses to unknown requests", func(t *testing.T) {
+		baseURL := "http://localhost:8080" + basePath
+		serviceProvider, err := saml.NewIdentityProvider(config.GetSamlConfig(), baseURL+"/metadata", baseURL+"/acs")
+		require.NoError(t, err)
+		authnRequestURL, err := serviceProvider.AuthnRequestURL("id-forged", state)
+		require.NoError(t, err)
+		_, form, err := identityProvider.Respond(authnRequestURL)
+		require.NoError(t, err)
+		rec := postResponse(form)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = postResponse(url.Values{"SAMLResponse": {"invalid"}})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("rejects responses signed by another identity provider", func(t *testing.T) {
+		impostor := samltest.NewIdentityProvider("https://idp.example.com")
+		impostor.Session.NameID = "admin"
+		require.NoError(t, impostor.RegisterServiceProvider(serve(httptest.NewRequest(http.MethodGet, basePath+"/metadata", nil)).Body.Bytes()))
+		authnRequestURL, _ := login()
+		_, form, err := impostor.Respond(authnRequestURL)
+		require.NoError(t, err)
+		rec := postResponse(form)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("only serves SAML identity providers", func(t *testing.T) {
+		oauth2IdentityProvider, err := testStore.CreateIdentityProvider(ctx, &storepb.IdentityProvider{
+			Name: "OAuth2",
+			Type: storepb.IdentityProvider_OAUTH2,
+			Config: &storepb.IdentityProviderConfig{
+				Config: &storepb.IdentityProviderConfig_Oauth2Config{Oauth2Config: &storepb.OAuth2Config{}},
+			},
+		})
+		require.NoError(t, err)
+		rec := serve(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/auth/saml/%d/metadata", oauth2IdentityProvider.Id), nil))
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type samlTestStream struct {
+	header metadata.MD
+}
+
+func (*samlTestStream) Method() string {
+	return ""
+}
+
+func (s *samlTestStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *samlTestStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (*samlTestStream) SetTrailer(metadata.MD) error {
+	return nil
+}
@@ -5,186 +5,401 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"regexp"
+	"time"
 
-	"github.com/SherClockHolmes/webpush-go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/imrany/wekalist/plugin/webpush"
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
 	"github.com/imrany/wekalist/store"
 )
 
+const (
+	// defaultPushTTL is how long push services keep a notification for an offline browser by default.
+	defaultPushTTL = 60 * time.Second
+	// pushDeliveryRetention is how long the outcomes of push deliveries are kept.
+	pushDeliveryRetention = 30 * 24 * time.Hour
+)
+
+// pushTopicRegexp matches the valid topics of push messages, see RFC 8030 section 5.4.
+var pushTopicRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{0,32}$`)
+
 func (s *APIV1Service) AddSubscription(ctx context.Context, request *v1pb.SubscriptionRequest) (*v1pb.SubscriptionResponse, error) {
-    if request.Endpoint == "" || request.Username == "" {
-        return wrapSubscriptionError("Endpoint and username are required", codes.InvalidArgument)
-    }
-
-    if len(request.Keys) == 0 {
-        return wrapSubscriptionError("Subscription keys are required", codes.InvalidArgument)
-    }
-
-    subscription := &store.Subscription{
-        Endpoint: request.Endpoint,
-        Username: request.Username,
-        Email:    request.Email,
-        Keys:     request.Keys,
-    }
-
-    _, err := s.Store.CreateSubscription(ctx, subscription)
-    if err != nil {
-        log.Printf("[AddSubscription] Failed for user %s: %v", request.Username, err)
-        return wrapSubscriptionError("Failed to create subscription", codes.Internal)
-    }
-
-    log.Printf("[AddSubscription] Success for user %s: %s", request.Username, request.Endpoint)
-    return &v1pb.SubscriptionResponse{
-        Success: true,
-        Message: "Subscription added successfully",
-    }, nil
+	user, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	if request.Endpoint == "" {
+		return wrapSubscriptionError("Endpoint is required", codes.InvalidArgument)
+	}
+	if len(request.Keys) == 0 {
+		return wrapSubscriptionError("Subscription keys are required", codes.InvalidArgument)
+	}
+
+	// An endpoint identifies a browser, which is subscribed for the user signed in last.
+	if err := s.Store.DeleteSubscription(ctx, &store.DeleteSubscription{Endpoint: &request.Endpoint}); err != nil {
+		log.Printf("[AddSubscription] Failed for user %d: %v", user.ID, err)
+		return wrapSubscriptionError("Failed to create subscription", codes.Internal)
+	}
+	subscription := &store.Subscription{
+		UserID:   user.ID,
+		Endpoint: request.Endpoint,
+		Keys:     request.Keys,
+	}
+	if _, err := s.Store.CreateSubscription(ctx, subscription); err != nil {
+		log.Printf("[AddSubscription] Failed for user %d: %v", user.ID, err)
+		return wrapSubscriptionError("Failed to create subscription", codes.Internal)
+	}
+
+	log.Printf("[AddSubscription] Success for user %d: %s", user.ID, request.Endpoint)
+	return &v1pb.SubscriptionResponse{
+		Success: true,
+		Message: "Subscription added successfully",
+	}, nil
 }
 
 func (s *APIV1Service) RemoveSubscription(ctx context.Context, request *v1pb.RemoveSubscriptionRequest) (*v1pb.SubscriptionResponse, error) {
-    if request.Endpoint == "" || request.Username == "" {
-        return wrapSubscriptionError("Endpoint and username are required", codes.InvalidArgument)
-    }
-
-    err := s.Store.DeleteSubscription(ctx, &store.DeleteSubscription{
-        Username: request.Username,
-        // Endpoint: request.Endpoint,
-    })
-
-    if err != nil {
-        log.Printf("[RemoveSubscription] Failed for user %s: %v", request.Username, err)
-        return wrapSubscriptionError("Failed to remove subscription", codes.Internal)
-    }
-
-    log.Printf("[RemoveSubscription] Success for user %s: %s", request.Username, request.Endpoint)
-    return &v1pb.SubscriptionResponse{
-        Success: true,
-        Message: "Subscription removed successfully",
-    }, nil
+	user, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	if request.Endpoint == "" {
+		return wrapSubscriptionError("Endpoint is required", codes.InvalidArgument)
+	}
+
+	subscriptions, err := s.Store.ListSubscriptions(ctx, &store.FindSubscription{
+		UserID:   &user.ID,
+		Endpoint: &request.Endpoint,
+	})
+	if err != nil {
+		log.Printf("[RemoveSubscription] Failed for user %d: %v", user.ID, err)
+		return wrapSubscriptionError("Failed to remove subscription", codes.Internal)
+	}
+	if len(subscriptions) == 0 {
+		return wrapSubscriptionError("Subscription not found", codes.NotFound)
+	}
+	if err := s.Store.DeleteSubscription(ctx, &store.DeleteSubscription{
+		UserID:   &user.ID,
+		Endpoint: &request.Endpoint,
+	}); err != nil {
+		log.Printf("[RemoveSubscription] Failed for user %d: %v", user.ID, err)
+		return wrapSubscriptionError("Failed to remove subscription", codes.Internal)
+	}
+
+	log.Printf("[RemoveSubscription] Success for user %d: %s", user.ID, request.Endpoint)
+	return &v1pb.SubscriptionResponse{
+		Success: true,
+		Message: "Subscription removed successfully",
+	}, nil
 }
 
 func (s *APIV1Service) SendNotification(ctx context.Context, request *v1pb.SendNotificationRequest) (*v1pb.SendNotificationResponse, error) {
-    if request.Payload == nil {
-        return &v1pb.SendNotificationResponse{
-            Success: false,
-            Message: "Notification payload is required",
-        }, status.Error(codes.InvalidArgument, "missing payload")
-    }
-
-    var subscriptions []*store.Subscription
-    var err error
-
-    if request.SendToAll {
-        subscriptions, err = s.Store.ListSubscriptions(ctx, nil)
-    } else if request.Username != "" {
-        subscriptions, err = s.Store.ListSubscriptions(ctx, &store.FindSubscription{
-            Username: &request.Username,
-        })
-    } else if request.SendToAllExcept != "" {
-        allSubscriptions, err := s.Store.ListSubscriptions(ctx, nil)
-        if err != nil {
-            return &v1pb.SendNotificationResponse{
-                Success: false,
-                Message: "Failed to retrieve subscriptions",
-            }, status.Error(codes.Internal, fmt.Sprintf("database error, %s", err.Error()))
-        }
-
-        // Filter out the excluded user
-        for _, sub := range allSubscriptions {
-            if sub.Username != request.SendToAllExcept {
-                subscriptions = append(subscriptions, sub)
-            }
-        }
-    } else {
-        return &v1pb.SendNotificationResponse{
-            Success: false,
-            Message: "Either username or send_to_all must be specified",
-        }, status.Error(codes.InvalidArgument, "invalid request")
-    }
-
-    if err != nil {
-        return &v1pb.SendNotificationResponse{
-            Success: false,
-            Message: "Failed to retrieve subscriptions",
-        }, status.Error(codes.Internal, fmt.Sprintf("database error, %s", err.Error()))
-    }
-
-    if len(subscriptions) == 0 {
-        return &v1pb.SendNotificationResponse{
-            Success: false,
-            Message: "No subscriptions found",
-        }, nil
-    }
-
-    notificationData := map[string]interface{}{
-        "title": request.Payload.Title,
-        "body":  request.Payload.Body,
-    }
-
-    if request.Payload.Icon != "" {
-        notificationData["icon"] = request.Payload.Icon
-    }
-    if request.Payload.Badge != "" {
-        notificationData["badge"] = request.Payload.Badge
-    }
-    if request.Payload.Url != "" {
-        notificationData["url"] = request.Payload.Url
-    }
-    if len(request.Payload.Data) > 0 {
-        notificationData["data"] = request.Payload.Data
-    }
-
-    payloadJSON, err := json.Marshal(notificationData)
-    if err != nil {
-        return &v1pb.SendNotificationResponse{
-            Success: false,
-            Message: "Failed to marshal notification payload",
-        }, status.Error(codes.Internal, "payload marshaling error")
-    }
-
-    var successCount int
-    for _, subscription := range subscriptions {
-        if err := s.sendPushNotification(subscription, payloadJSON); err != nil {
-            log.Printf("[SendNotification] Failed for %s: %v", subscription.Username, err)
-        } else {
-            log.Printf("[SendNotification] Sent to %s (%s)", subscription.Username, subscription.Endpoint)
-            successCount++
-        }
-    }
-
-    return &v1pb.SendNotificationResponse{
-        Success:         successCount > 0,
-        Message:         fmt.Sprintf("Sent %d/%d notifications successfully", successCount, len(subscriptions)),
-        RecipientsCount: int32(successCount),
-    }, nil
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil || (currentUser.Role != store.RoleHost && currentUser.Role != store.RoleAdmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if request.Payload == nil {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "Notification payload is required",
+		}, status.Error(codes.InvalidArgument, "missing payload")
+	}
+
+	var subscriptions []*store.Subscription
+	if request.SendToAll {
+		subscriptions, err = s.Store.ListSubscriptions(ctx, nil)
+	} else if request.Username != "" {
+		user, findErr := s.Store.GetUser(ctx, &store.FindUser{Username: &request.Username})
+		if findErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user: %v", findErr)
+		}
+		if user == nil {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		subscriptions, err = s.Store.ListSubscriptions(ctx, &store.FindSubscription{
+			UserID: &user.ID,
+		})
+	} else if request.SendToAllExcept != "" {
+		excludedUser, findErr := s.Store.GetUser(ctx, &store.FindUser{Username: &request.SendToAllExcept})
+		if findErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user: %v", findErr)
+		}
+		allSubscriptions, listErr := s.Store.ListSubscriptions(ctx, nil)
+		if listErr != nil {
+			return &v1pb.SendNotificationResponse{
+				Success: false,
+				Message: "Failed to retrieve subscriptions",
+			}, status.Error(codes.Internal, fmt.Sprintf("database error, %s", listErr.Error()))
+		}
+
+		// Filter out the excluded user
+		for _, sub := range allSubscriptions {
+			if excludedUser == nil || sub.UserID != excludedUser.ID {
+				subscriptions = append(subscriptions, sub)
+			}
+		}
+	} else {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "Either username or send_to_all must be specified",
+		}, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if err != nil {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "Failed to retrieve subscriptions",
+		}, status.Error(codes.Internal, fmt.Sprintf("database error, %s", err.Error()))
+	}
+
+	if len(subscriptions) == 0 {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "No subscriptions found",
+		}, nil
+	}
+
+	notificationData := map[string]interface{}{
+		"title": request.Payload.Title,
+		"body":  request.Payload.Body,
+	}
+
+	if request.Payload.Icon != "" {
+		notificationData["icon"] = request.Payload.Icon
+	}
+	if request.Payload.Badge != "" {
+		notificationData["badge"] = request.Payload.Badge
+	}
+	if request.Payload.Url != "" {
+		notificationData["url"] = request.Payload.Url
+	}
+	if len(request.Payload.Data) > 0 {
+		notificationData["data"] = request.Payload.Data
+	}
+
+	payloadJSON, err := json.Marshal(notificationData)
+	if err != nil {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "Failed to marshal notification payload",
+		}, status.Error(codes.Internal, "payload marshaling error")
+	}
+
+	message := &webpush.Message{
+		Payload: payloadJSON,
+		TTL:     defaultPushTTL,
+		Urgency: convertPushUrgencyToPlugin(request.Urgency),
+		Topic:   request.Topic,
+	}
+	if request.Ttl != nil {
+		if err := request.Ttl.CheckValid(); err != nil || request.Ttl.AsDuration() < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl")
+		}
+		message.TTL = request.Ttl.AsDuration()
+	}
+	if !pushTopicRegexp.MatchString(request.Topic) {
+		return nil, status.Errorf(codes.InvalidArgument, "topic must be at most 32 characters of the URL-safe base64 alphabet")
+	}
+	if s.PushDispatcher == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "push notifications are not enabled")
+	}
+
+	var queuedCount int
+	for _, subscription := range subscriptions {
+		if err := s.PushDispatcher.Enqueue(s.newPushDelivery(subscription, message)); err != nil {
+			log.Printf("[SendNotification] Failed to queue for user %d: %v", subscription.UserID, err)
+			continue
+		}
+		queuedCount++
+	}
+	if queuedCount == 0 {
+		return &v1pb.SendNotificationResponse{
+			Success: false,
+			Message: "Push delivery queue is full",
+		}, status.Error(codes.ResourceExhausted, "push delivery queue is full")
+	}
+
+	return &v1pb.SendNotificationResponse{
+		Success:         true,
+		Message:         fmt.Sprintf("Queued %d/%d notifications", queuedCount, len(subscriptions)),
+		RecipientsCount: int32(queuedCount),
+	}, nil
+}
+
+func (s *APIV1Service) ListPushDeliveries(ctx context.Context, request *v1pb.ListPushDeliveriesRequest) (*v1pb.ListPushDeliveriesResponse, error) {
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil || !isSuperUser(currentUser) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	pushDeliveryFind := &store.FindPushDelivery{}
+	if request.Username != "" {
+		user, err := s.Store.GetUser(ctx, &store.FindUser{Username: &request.Username})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+		}
+		if user == nil {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		pushDeliveryFind.UserID = &user.ID
+	}
+	if request.Status != v1pb.PushDelivery_STATUS_UNSPECIFIED {
+		deliveryStatus := request.Status.String()
+		pushDeliveryFind.Status = &deliveryStatus
+	}
+
+	var limit, offset int
+	if request.PageToken != "" {
+		var pageToken v1pb.PageToken
+		if err := unmarshalPageToken(request.PageToken, &pageToken); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		limit = int(pageToken.Limit)
+		offset = int(pageToken.Offset)
+	} else {
+		limit = int(request.PageSize)
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limitPlusOne := limit + 1
+	pushDeliveryFind.Limit = &limitPlusOne
+	pushDeliveryFind.Offset = &offset
+	pushDeliveries, err := s.Store.ListPushDeliveries(ctx, pushDeliveryFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list push deliveries: %v", err)
+	}
+
+	response := &v1pb.ListPushDeliveriesResponse{}
+	if len(pushDeliveries) == limitPlusOne {
+		pushDeliveries = pushDeliveries[:limit]
+		response.NextPageToken, err = getPageToken(limit, offset+limit)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get next page token: %v", err)
+		}
+	}
+	usernames := map[int32]string{}
+	for _, pushDelivery := range pushDeliveries {
+		username, ok := usernames[pushDelivery.UserID]
+		if !ok {
+			user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &pushDelivery.UserID})
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+			}
+			// The user may have been deleted since.
+			if user != nil {
+				username = user.Username
+			}
+			usernames[pushDelivery.UserID] = username
+		}
+		response.PushDeliveries = append(response.PushDeliveries, convertPushDeliveryFromStore(pushDelivery, username))
+	}
+	return response, nil
+}
+
+// StartPushDispatcher starts delivering the queued push notifications, and pruning the
+// outcomes of the deliveries older than the retention, until the context is done.
+func (s *APIV1Service) StartPushDispatcher(ctx context.Context) {
+	s.PushDispatcher.Start(ctx)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			createdTsBefore := time.Now().Add(-pushDeliveryRetention).Unix()
+			if _, err := s.Store.DeletePushDeliveries(ctx, &store.DeletePushDelivery{CreatedTsBefore: &createdTsBefore}); err != nil && ctx.Err() == nil {
+				log.Printf("[StartPushDispatcher] Failed to prune push deliveries: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *APIV1Service) newPushDelivery(subscription *store.Subscription, message *webpush.Message) *webpush.Delivery {
+	return &webpush.Delivery{
+		Subscription: &webpush.Subscription{
+			Endpoint: subscription.Endpoint,
+			P256dh:   subscription.Keys["p256dh"],
+			Auth:     subscription.Keys["auth"],
+		},
+		Message: message,
+		OnOutcome: func(ctx context.Context, outcome *webpush.Outcome) {
+			s.recordPushOutcome(ctx, subscription, message, outcome)
+		},
+	}
+}
+
+// recordPushOutcome records the outcome of a delivery, and deletes the subscription once its endpoint is gone.
+func (s *APIV1Service) recordPushOutcome(ctx context.Context, subscription *store.Subscription, message *webpush.Message, outcome *webpush.Outcome) {
+	if outcome.Status == webpush.StatusExpired {
+		if err := s.Store.DeleteSubscription(ctx, &store.DeleteSubscription{
+			UserID:   &subscription.UserID,
+			Endpoint: &subscription.Endpoint,
+		}); err != nil {
+			log.Printf("[SendNotification] Failed to delete expired subscription of user %d: %v", subscription.UserID, err)
+		}
+	}
+	if _, err := s.Store.CreatePushDelivery(ctx, &store.PushDelivery{
+		UserID:     subscription.UserID,
+		Endpoint:   subscription.Endpoint,
+		Topic:      message.Topic,
+		Status:     string(outcome.Status),
+		StatusCode: int32(outcome.StatusCode),
+		Attempts:   int32(outcome.Attempts),
+		Error:      outcome.Error,
+	}); err != nil {
+		log.Printf("[SendNotification] Failed to record delivery to user %d: %v", subscription.UserID, err)
+	}
+}
+
+func convertPushUrgencyToPlugin(urgency v1pb.SendNotificationRequest_Urgency) webpush.Urgency {
+	switch urgency {
+	case v1pb.SendNotificationRequest_VERY_LOW:
+		return webpush.UrgencyVeryLow
+	case v1pb.SendNotificationRequest_LOW:
+		return webpush.UrgencyLow
+	case v1pb.SendNotificationRequest_HIGH:
+		return webpush.UrgencyHigh
+	default:
+		return webpush.UrgencyNormal
+	}
 }
 
-func (s *APIV1Service) sendPushNotification(subscription *store.Subscription, payload []byte) error {
-    sub := &webpush.Subscription{
-        Endpoint: subscription.Endpoint,
-        Keys: webpush.Keys{
-            P256dh: subscription.Keys["p256dh"],
-            Auth:   subscription.Keys["auth"],
-        },
-    }
-
-    resp, err := webpush.SendNotification(payload, sub, &webpush.Options{
-        TTL:             60,
-        VAPIDPublicKey:  s.Profile.WebPushConfig.VAPIDPublicKey,
-        VAPIDPrivateKey: s.Profile.WebPushConfig.VAPIDPrivateKey,
-    })
-    if err != nil {
-        return err
-    }
-    defer resp.Body.Close()
-    return nil
+func convertPushDeliveryFromStore(pushDelivery *store.PushDelivery, username string) *v1pb.PushDelivery {
+	return &v1pb.PushDelivery{
+		Id:         pushDelivery.ID,
+		Username:   username,
+		Endpoint:   pushDelivery.Endpoint,
+		Topic:      pushDelivery.Topic,
+		Status:     v1pb.PushDelivery_Status(v1pb.PushDelivery_Status_value[pushDelivery.Status]),
+		StatusCode: pushDelivery.StatusCode,
+		Attempts:   pushDelivery.Attempts,
+		Error:      pushDelivery.Error,
+		CreateTime: timestamppb.New(time.Unix(pushDelivery.CreatedTs, 0)),
+	}
 }
 
 func wrapSubscriptionError(msg string, code codes.Code) (*v1pb.SubscriptionResponse, error) {
-    return &v1pb.SubscriptionResponse{
-        Success: false,
-        Message: msg,
-    }, status.Error(code, msg)
+	return &v1pb.SubscriptionResponse{
+		Success: false,
+		Message: msg,
+	}, status.Error(code, msg)
 }
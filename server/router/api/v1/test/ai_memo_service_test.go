@@ -0,0 +1,321 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/server/runner/embedding"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestAIMemoActions(t *testing.T) {
+	ctx := context.Background()
+
+	createMemo := func(t *testing.T, ts *TestService, creatorID int32, visibility store.Visibility, content string) *store.Memo {
+		t.Helper()
+		memo, err := ts.Store.CreateMemo(ctx, &store.Memo{
+			UID:        fmt.Sprintf("memo-%d-%s", creatorID, visibility),
+			CreatorID:  creatorID,
+			Content:    content,
+			Visibility: visibility,
+			Payload:    &storepb.MemoPayload{Tags: []string{"work"}},
+		})
+		require.NoError(t, err)
+		return memo
+	}
+
+	t.Run("SummarizeMemo returns the summary", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "  A short summary.  ")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, user.ID, store.Private, "A long memo")
+
+		resp, err := ts.Service.SummarizeMemo(ts.CreateUserContext(ctx, user.ID), &v1pb.SummarizeMemoRequest{Name: "memos/" + memo.UID})
+		require.NoError(t, err)
+		require.Equal(t, "A short summary.", resp.Summary)
+	})
+
+	t.Run("RewriteMemo strips code fences", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "```markdown\nRewritten memo\n```")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, user.ID, store.Private, "memo")
+
+		resp, err := ts.Service.RewriteMemo(ts.CreateUserContext(ctx, user.ID), &v1pb.RewriteMemoRequest{Name: "memos/" + memo.UID})
+		require.NoError(t, err)
+		require.Equal(t, "Rewritten memo", resp.Content)
+	})
+
+	t.Run("ExtractTasks skips existing tasks and appends a task list", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, `Here you go: ["Buy milk", "Call Bob", "Buy milk"]`)
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, user.ID, store.Private, "Groceries\n- [ ] buy milk")
+
+		resp, err := ts.Service.ExtractTasks(ts.CreateUserContext(ctx, user.ID), &v1pb.ExtractTasksRequest{Name: "memos/" + memo.UID})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Call Bob"}, resp.Tasks)
+		require.Equal(t, "Groceries\n- [ ] buy milk\n\n- [ ] Call Bob", resp.Content)
+	})
+
+	t.Run("SuggestTags excludes existing tags and appends hashtags", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, `["#work", "project plan", "ideas"]`)
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, user.ID, store.Protected, "Plan the project #work")
+
+		resp, err := ts.Service.SuggestTags(ts.CreateUserContext(ctx, user.ID), &v1pb.SuggestTagsRequest{Name: "memos/" + memo.UID})
+		require.NoError(t, err)
+		require.Equal(t, []string{"project-plan", "ideas"}, resp.Tags)
+		require.Equal(t, "Plan the project #work\n\n#project-plan #ideas", resp.Content)
+	})
+
+	t.Run("memo actions enforce memo visibility", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "summary")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		owner, err := ts.CreateRegularUser(ctx, "owner")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, owner.ID, store.Private, "secret")
+
+		_, err = ts.Service.SummarizeMemo(ts.CreateUserContext(ctx, other.ID), &v1pb.SummarizeMemoRequest{Name: "memos/" + memo.UID})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "permission denied")
+
+		_, err = ts.Service.SuggestTags(ctx, &v1pb.SuggestTagsRequest{Name: "memos/" + memo.UID})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "permission denied")
+
+		_, err = ts.Service.ExtractTasks(ts.CreateUserContext(ctx, other.ID), &v1pb.ExtractTasksRequest{Name: "memos/missing"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestSearchMemosSemantic(t *testing.T) {
+	ctx := context.Background()
+
+	createMemo := func(t *testing.T, ts *TestService, uid string, creatorID int32, visibility store.Visibility, content string) *store.Memo {
+		t.Helper()
+		memo, err := ts.Store.CreateMemo(ctx, &store.Memo{
+			UID:        uid,
+			CreatorID:  creatorID,
+			Content:    content,
+			Visibility: visibility,
+		})
+		require.NoError(t, err)
+		return memo
+	}
+	resultNames := func(resp *v1pb.SearchMemosSemanticResponse) []string {
+		names := []string{}
+		for _, result := range resp.Results {
+			names = append(names, result.Memo.Name)
+		}
+		return names
+	}
+
+	t.Run("SearchMemosSemantic ranks visible memos by similarity", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		owner, err := ts.CreateRegularUser(ctx, "owner")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		createMemo(t, ts, "public-cat", owner.ID, store.Public, "my cat sleeps all day")
+		createMemo(t, ts, "protected-dog", owner.ID, store.Protected, "walked the dog")
+		createMemo(t, ts, "private-mixed", owner.ID, store.Private, "the dog chased the cat")
+		createMemo(t, ts, "empty", owner.ID, store.Public, "   ")
+		embedding.NewRunner(ts.Store).RunOnce(ctx)
+
+		resp, err := ts.Service.SearchMemosSemantic(ts.CreateUserContext(ctx, owner.ID), &v1pb.SearchMemosSemanticRequest{Query: "dog"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"memos/protected-dog", "memos/private-mixed", "memos/public-cat"}, resultNames(resp))
+		require.Greater(t, resp.Results[0].Score, resp.Results[2].Score)
+
+		// Private memos of other users are never returned.
+		resp, err = ts.Service.SearchMemosSemantic(ts.CreateUserContext(ctx, other.ID), &v1pb.SearchMemosSemanticRequest{Query: "dog", PageSize: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"memos/protected-dog"}, resultNames(resp))
+	})
+
+	t.Run("memo embeddings are charged to the memo creators", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "")
+		defer server.Close()
+		_, err := ts.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+			Key: storepb.WorkspaceSettingKey_AI,
+			Value: &storepb.WorkspaceSetting_AiSetting{
+				AiSetting: &storepb.WorkspaceAISetting{
+					Provider:  storepb.WorkspaceAISetting_OLLAMA,
+					BaseUrl:   server.URL,
+					Model:     "test-model",
+					UserQuota: &storepb.WorkspaceAISetting_Quota{MaxRequests: 1},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		owner, err := ts.CreateRegularUser(ctx, "owner")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		createMemo(t, ts, "owner-cat", owner.ID, store.Public, "my cat sleeps all day")
+		createMemo(t, ts, "owner-dog", owner.ID, store.Public, "walked the dog")
+		createMemo(t, ts, "other-dog", other.ID, store.Public, "my dog barks")
+		embedding.NewRunner(ts.Store).RunOnce(ctx)
+
+		// The test provider counts a token per embedded text.
+		for userID, tokens := range map[int32]int64{owner.ID: 2, other.ID: 1} {
+			usage, err := ts.Service.GetAiUsage(ts.CreateUserContext(ctx, userID), &v1pb.GetAiUsageRequest{User: fmt.Sprintf("users/%d", userID)})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), usage.RequestCount)
+			require.Equal(t, tokens, usage.TotalTokens)
+		}
+
+		// Out of quota, neither queries nor new memos are embedded.
+		_, err = ts.Service.SearchMemosSemantic(ts.CreateUserContext(ctx, owner.ID), &v1pb.SearchMemosSemanticRequest{Query: "dog"})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		memo := createMemo(t, ts, "owner-new", owner.ID, store.Public, "another cat")
+		embedding.NewRunner(ts.Store).RunOnce(ctx)
+		memoEmbedding, err := ts.Store.GetMemoEmbedding(ctx, &store.FindMemoEmbedding{MemoID: &memo.ID})
+		require.NoError(t, err)
+		require.Nil(t, memoEmbedding)
+	})
+
+	t.Run("UpdateMemo recomputes the embedding when the content changes", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		memo := createMemo(t, ts, "memo", user.ID, store.Private, "about a cat")
+		embedding.NewRunner(ts.Store).RunOnce(ctx)
+
+		_, err = ts.Service.UpdateMemo(ts.CreateUserContext(ctx, user.ID), &v1pb.UpdateMemoRequest{
+			Memo:       &v1pb.Memo{Name: "memos/" + memo.UID, Content: "about a dog"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"content"}},
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			memoEmbedding, err := ts.Store.GetMemoEmbedding(ctx, &store.FindMemoEmbedding{MemoID: &memo.ID})
+			return err == nil && memoEmbedding != nil && memoEmbedding.ContentHash == store.HashMemoContent("about a dog")
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("SearchMemosSemantic requires an embedding provider", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		_, err = ts.Service.SearchMemosSemantic(ts.CreateUserContext(ctx, user.ID), &v1pb.SearchMemosSemanticRequest{Query: "dog"})
+		require.Error(t, err)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestAskMemos(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AskMemos answers from visible memos with citations", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		prompts := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := &struct {
+				Prompt string   `json:"prompt"`
+				Input  []string `json:"input"`
+			}{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+			encoder := json.NewEncoder(w)
+			if r.URL.Path == "/api/embed" {
+				embeddings := [][]float32{}
+				for _, input := range request.Input {
+					embeddings = append(embeddings, testEmbedding(input))
+				}
+				require.NoError(t, encoder.Encode(map[string]any{"embeddings": embeddings}))
+				return
+			}
+			prompts = append(prompts, request.Prompt)
+			require.NoError(t, encoder.Encode(map[string]any{"response": " The dog is walked daily [memos/walks]. ", "done": true}))
+		}))
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		owner, err := ts.CreateRegularUser(ctx, "owner")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		for uid, visibility := range map[string]store.Visibility{"walks": store.Protected, "secret": store.Private} {
+			_, err := ts.Store.CreateMemo(ctx, &store.Memo{
+				UID:        uid,
+				CreatorID:  owner.ID,
+				Content:    uid + " of the dog",
+				Visibility: visibility,
+			})
+			require.NoError(t, err)
+		}
+		embedding.NewRunner(ts.Store).RunOnce(ctx)
+
+		resp, err := ts.Service.AskMemos(ts.CreateUserContext(ctx, other.ID), &v1pb.AskMemosRequest{Question: "How often is the dog walked?"})
+		require.NoError(t, err)
+		require.Equal(t, "The dog is walked daily [memos/walks].", resp.Answer)
+		require.Equal(t, []string{"memos/walks"}, resp.Citations)
+		require.Len(t, prompts, 1)
+		require.Contains(t, prompts[0], "[memos/walks]")
+		require.NotContains(t, prompts[0], "secret")
+	})
+
+	t.Run("AskMemos requires authentication", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+
+		_, err := ts.Service.AskMemos(ctx, &v1pb.AskMemosRequest{Question: "anything?"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+// newTestOllamaServer starts a stand-in Ollama endpoint that replies with the given text.
+// Streaming requests receive the reply split into words followed by a final usage chunk.
+// Embed requests receive the vectors computed by testEmbedding.
+func newTestOllamaServer(t *testing.T, reply string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &struct {
+			Stream bool     `json:"stream"`
+			Input  []string `json:"input"`
+		}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		encoder := json.NewEncoder(w)
+		if r.URL.Path == "/api/embed" {
+			embeddings := [][]float32{}
+			for _, input := range request.Input {
+				embeddings = append(embeddings, testEmbedding(input))
+			}
+			require.NoError(t, encoder.Encode(map[string]any{"model": "test-embedding", "embeddings": embeddings, "prompt_eval_count": len(request.Input)}))
+			return
+		}
+		if !request.Stream {
+			require.NoError(t, encoder.Encode(map[string]any{"model": "test-model", "response": reply, "done": true, "prompt_eval_count": 3, "eval_count": 2}))
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for i, word := range strings.SplitAfter(reply, " ") {
+			require.NoError(t, encoder.Encode(map[string]any{"model": "test-model", "response": word, "done": false, "eval_count": i}))
+		}
+		require.NoError(t, encoder.Encode(map[string]any{"model": "test-model", "response": "", "done": true, "prompt_eval_count": 3, "eval_count": 2}))
+	}))
+}
+
+// testEmbedding maps a text to a vector counting the words "cat" and "dog",
+// so that texts about the same animal are semantically close.
+func testEmbedding(text string) []float32 {
+	text = strings.ToLower(text)
+	return []float32{float32(strings.Count(text, "cat")), float32(strings.Count(text, "dog")), 0.1}
+}
+
+// useTestAIProvider points the workspace AI setting at the given Ollama-style endpoint.
+func (ts *TestService) useTestAIProvider(ctx context.Context, t *testing.T, baseURL string) {
+	t.Helper()
+	_, err := ts.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_AI,
+		Value: &storepb.WorkspaceSetting_AiSetting{
+			AiSetting: &storepb.WorkspaceAISetting{
+				Provider: storepb.WorkspaceAISetting_OLLAMA,
+				BaseUrl:  baseURL,
+				Model:    "test-model",
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+type testStreamGenAiServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*v1pb.StreamGenAiResponse
+}
+
+func (s *testStreamGenAiServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testStreamGenAiServer) Send(response *v1pb.StreamGenAiResponse) error {
+	s.responses = append(s.responses, response)
+	return nil
+}
+
+func TestStreamGenAi(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("StreamGenAi emits deltas and a final usage summary", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello world")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+
+		stream := &testStreamGenAiServer{ctx: ts.CreateUserContext(ctx, user.ID)}
+		err = ts.Service.StreamGenAi(&v1pb.GenAiRequest{Prompt: "say hello"}, stream)
+		require.NoError(t, err)
+		require.Len(t, stream.responses, 3)
+		require.Equal(t, "hello ", stream.responses[0].GetDelta())
+		require.Equal(t, "world", stream.responses[1].GetDelta())
+		usage := stream.responses[2].GetUsage()
+		require.NotNil(t, usage)
+		require.Equal(t, "test-model", usage.Model)
+		require.Equal(t, int32(5), usage.TotalTokens)
+	})
+
+	t.Run("StreamGenAi requires authentication", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+
+		stream := &testStreamGenAiServer{ctx: ctx}
+		err := ts.Service.StreamGenAi(&v1pb.GenAiRequest{Prompt: "say hello"}, stream)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not authenticated")
+	})
+
+	t.Run("StreamGenAi rejects empty prompt", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+
+		stream := &testStreamGenAiServer{ctx: ctx}
+		err := ts.Service.StreamGenAi(&v1pb.GenAiRequest{}, stream)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "prompt cannot be empty")
+	})
+}
@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+func TestAIUsage(t *testing.T) {
+	ctx := context.Background()
+
+	// setQuotas points the workspace at the stand-in provider with the given quotas.
+	setQuotas := func(t *testing.T, ts *TestService, baseURL string, userQuota, workspaceQuota *storepb.WorkspaceAISetting_Quota) {
+		t.Helper()
+		_, err := ts.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+			Key: storepb.WorkspaceSettingKey_AI,
+			Value: &storepb.WorkspaceSetting_AiSetting{
+				AiSetting: &storepb.WorkspaceAISetting{
+					Provider:       storepb.WorkspaceAISetting_OLLAMA,
+					BaseUrl:        baseURL,
+					Model:          "test-model",
+					UserQuota:      userQuota,
+					WorkspaceQuota: workspaceQuota,
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("GenAi records usage in the ledger", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello")
+		defer server.Close()
+		ts.useTestAIProvider(ctx, t, server.URL)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		userCtx := ts.CreateUserContext(ctx, user.ID)
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.NoError(t, err)
+
+		usage, err := ts.Service.GetAiUsage(userCtx, &v1pb.GetAiUsageRequest{User: fmt.Sprintf("users/%d", user.ID)})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), usage.RequestCount)
+		require.Equal(t, int64(5), usage.TotalTokens)
+		require.True(t, usage.WindowEndTime.AsTime().After(usage.WindowStartTime.AsTime()))
+	})
+
+	t.Run("GenAi fails with ResourceExhausted when the user quota is used up", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello")
+		defer server.Close()
+		setQuotas(t, ts, server.URL, &storepb.WorkspaceAISetting_Quota{Window: storepb.WorkspaceAISetting_Quota_MONTHLY, MaxRequests: 2}, nil)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		userCtx := ts.CreateUserContext(ctx, user.ID)
+		for i := 0; i < 2; i++ {
+			_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+			require.NoError(t, err)
+		}
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		require.Contains(t, err.Error(), "monthly")
+
+		// Other users keep their own quota.
+		_, err = ts.Service.GenAi(ts.CreateUserContext(ctx, other.ID), &v1pb.GenAiRequest{Prompt: "hi"})
+		require.NoError(t, err)
+	})
+
+	t.Run("GenAi fails with ResourceExhausted when the workspace quota is used up", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello")
+		defer server.Close()
+		setQuotas(t, ts, server.URL, nil, &storepb.WorkspaceAISetting_Quota{MaxTokens: 8})
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		other, err := ts.CreateRegularUser(ctx, "other")
+		require.NoError(t, err)
+		for _, userID := range []int32{user.ID, other.ID} {
+			_, err = ts.Service.GenAi(ts.CreateUserContext(ctx, userID), &v1pb.GenAiRequest{Prompt: "hi"})
+			require.NoError(t, err)
+		}
+		_, err = ts.Service.GenAi(ts.CreateUserContext(ctx, user.ID), &v1pb.GenAiRequest{Prompt: "hi"})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("token quotas hold for providers that report no usage", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		// The provider replies without token counts, like the wrapper service.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"model": "test-model", "response": "twenty characters!!!", "done": true}))
+		}))
+		defer server.Close()
+		setQuotas(t, ts, server.URL, &storepb.WorkspaceAISetting_Quota{MaxTokens: 10}, nil)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		userCtx := ts.CreateUserContext(ctx, user.ID)
+		// Each call is charged 1 prompt token for "hi" and 5 completion tokens for the reply.
+		for i := 0; i < 2; i++ {
+			_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+			require.NoError(t, err)
+		}
+		usage, err := ts.Service.GetAiUsage(userCtx, &v1pb.GetAiUsageRequest{User: fmt.Sprintf("users/%d", user.ID)})
+		require.NoError(t, err)
+		require.Equal(t, int64(12), usage.TotalTokens)
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("concurrent calls cannot go over the quota together", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello")
+		defer server.Close()
+		setQuotas(t, ts, server.URL, &storepb.WorkspaceAISetting_Quota{MaxRequests: 2}, nil)
+
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		userCtx := ts.CreateUserContext(ctx, user.ID)
+		var wg sync.WaitGroup
+		var succeeded atomic.Int32
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+				if err == nil {
+					succeeded.Add(1)
+				} else {
+					assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(2), succeeded.Load())
+	})
+
+	t.Run("ResetAiUsage is admin only and clears the quota", func(t *testing.T) {
+		ts := NewTestService(t)
+		defer ts.Cleanup()
+		server := newTestOllamaServer(t, "hello")
+		defer server.Close()
+		setQuotas(t, ts, server.URL, &storepb.WorkspaceAISetting_Quota{MaxRequests: 1}, nil)
+
+		host, err := ts.CreateHostUser(ctx, "host")
+		require.NoError(t, err)
+		user, err := ts.CreateRegularUser(ctx, "user")
+		require.NoError(t, err)
+		userCtx := ts.CreateUserContext(ctx, user.ID)
+		userName := fmt.Sprintf("users/%d", user.ID)
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.NoError(t, err)
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		_, err = ts.Service.ResetAiUsage(userCtx, &v1pb.ResetAiUsageRequest{User: userName})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = ts.Service.GetAiUsage(userCtx, &v1pb.GetAiUsageRequest{})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		hostCtx := ts.CreateUserContext(ctx, host.ID)
+		usage, err := ts.Service.GetAiUsage(hostCtx, &v1pb.GetAiUsageRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), usage.RequestCount)
+		_, err = ts.Service.ResetAiUsage(hostCtx, &v1pb.ResetAiUsageRequest{User: userName})
+		require.NoError(t, err)
+		usage, err = ts.Service.GetAiUsage(hostCtx, &v1pb.GetAiUsageRequest{User: userName})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), usage.RequestCount)
+		require.Equal(t, int64(1), usage.Quota.MaxRequests)
+
+		_, err = ts.Service.GenAi(userCtx, &v1pb.GenAiRequest{Prompt: "hi"})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/idp/ldap/ldaptest"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestLDAPSignIn(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+	server := ldaptest.NewServer(
+		&ldaptest.Entry{DN: "cn=service,dc=example,dc=com", Password: "service-password"},
+		&ldaptest.Entry{
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "alice-password",
+			Attributes: map[string][]string{
+				"uid":      {"alice"},
+				"cn":       {"Alice"},
+				"mail":     {"alice@example.com"},
+				"memberOf": {"cn=admins,ou=groups,dc=example,dc=com"},
+			},
+		},
+	)
+	defer server.Close()
+
+	hostUser, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	hostCtx := ts.CreateUserContext(ctx, hostUser.ID)
+	identityProvider, err := ts.Service.CreateIdentityProvider(hostCtx, &v1pb.CreateIdentityProviderRequest{
+		IdentityProvider: &v1pb.IdentityProvider{
+			Title: "Directory",
+			Type:  v1pb.IdentityProvider_LDAP,
+			Config: &v1pb.IdentityProviderConfig{
+				Config: &v1pb.IdentityProviderConfig_LdapConfig{
+					LdapConfig: &v1pb.LDAPConfig{
+						Url:          server.URL(),
+						BindDn:       "cn=service,dc=example,dc=com",
+						BindPassword: "service-password",
+						BaseDn:       "ou=people,dc=example,dc=com",
+						GroupRoleMappings: []*v1pb.LDAPConfig_GroupRoleMapping{
+							{Group: "admins", Role: "ADMIN"},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	idpID, err := apiv1.ExtractIdentityProviderIDFromName(identityProvider.Name)
+	require.NoError(t, err)
+
+	t.Run("bind password is only visible to the host", func(t *testing.T) {
+		response, err := ts.Service.ListIdentityProviders(ctx, &v1pb.ListIdentityProvidersRequest{})
+		require.NoError(t, err)
+		require.Len(t, response.IdentityProviders, 1)
+		require.Empty(t, response.IdentityProviders[0].Config.GetLdapConfig().BindPassword)
+		require.Equal(t, server.URL(), response.IdentityProviders[0].Config.GetLdapConfig().Url)
+
+		identityProvider, err := ts.Service.GetIdentityProvider(hostCtx, &v1pb.GetIdentityProviderRequest{Name: identityProvider.Name})
+		require.NoError(t, err)
+		require.Equal(t, "service-password", identityProvider.Config.GetLdapConfig().BindPassword)
+	})
+
+	signIn := func(password string) (*v1pb.CreateSessionResponse, *HeaderCapturingStream, error) {
+		sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+		response, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+				PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "alice", Password: password, IdpId: idpID},
+			},
+		})
+		return response, stream, err
+	}
+
+	t.Run("rejects wrong passwords", func(t *testing.T) {
+		_, _, err := signIn("wrong-password")
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("signs up with the mapped role", func(t *testing.T) {
+		response, stream, err := signIn("alice-password")
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Equal(t, v1pb.User_ADMIN, response.User.Role)
+		require.Len(t, stream.Header.Get("Set-Cookie"), 1)
+
+		username := "alice"
+		user, err := ts.Store.GetUser(ctx, &store.FindUser{Username: &username})
+		require.NoError(t, err)
+		require.Equal(t, "alice@example.com", user.Email)
+		// The local password is not the directory password.
+		_, err = ts.Service.CreateSession(ctx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+				PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "alice", Password: "alice-password"},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("syncs the role on sign-in", func(t *testing.T) {
+		server.Entries[1].Attributes["memberOf"] = []string{"cn=staff,ou=groups,dc=example,dc=com"}
+		response, _, err := signIn("alice-password")
+		require.NoError(t, err)
+		require.Equal(t, v1pb.User_USER, response.User.Role)
+	})
+}
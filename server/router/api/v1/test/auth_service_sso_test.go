@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/idp/oidc/oidctest"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestOIDCSignIn(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+	issuer := oidctest.NewIssuer("wekalist", "secret")
+	defer issuer.Close()
+	issuer.Claims = map[string]any{"sub": "alice", "name": "Alice", "email": "alice@example.com"}
+
+	hostUser, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	identityProvider, err := ts.Service.CreateIdentityProvider(ts.CreateUserContext(ctx, hostUser.ID), &v1pb.CreateIdentityProviderRequest{
+		IdentityProvider: &v1pb.IdentityProvider{
+			Title: "Test OIDC Provider",
+			Type:  v1pb.IdentityProvider_OIDC,
+			Config: &v1pb.IdentityProviderConfig{
+				Config: &v1pb.IdentityProviderConfig_OidcConfig{
+					OidcConfig: &v1pb.OIDCConfig{
+						IssuerUrl:    issuer.URL(),
+						ClientId:     "wekalist",
+						ClientSecret: "secret",
+						Scopes:       []string{"profile", "email"},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, issuer.URL(), identityProvider.Config.GetOidcConfig().IssuerUrl)
+	idpID, err := apiv1.ExtractIdentityProviderIDFromName(identityProvider.Name)
+	require.NoError(t, err)
+
+	redirectURI := ts.Profile.InstanceURL + "/auth/callback"
+	authorize := func(redirectURI string) (string, string) {
+		response, err := ts.Service.BeginSSOAuthorization(ctx, &v1pb.BeginSSOAuthorizationRequest{IdpId: idpID, RedirectUri: redirectURI, State: "state"})
+		require.NoError(t, err)
+		require.Contains(t, response.AuthorizationUrl, "code_challenge=")
+		require.NotContains(t, response.AuthorizationUrl, "code_verifier")
+		redirect, err := issuer.Authorize(response.AuthorizationUrl)
+		require.NoError(t, err)
+		return redirect.Query().Get("code"), response.CeremonyToken
+	}
+	signIn := func(code, ceremonyToken string) (*v1pb.CreateSessionResponse, *HeaderCapturingStream, error) {
+		sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+		response, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_SsoCredentials{
+				SsoCredentials: &v1pb.CreateSessionRequest_SSOCredentials{
+					IdpId:         idpID,
+					Code:          code,
+					RedirectUri:   redirectURI,
+					CeremonyToken: ceremonyToken,
+				},
+			},
+		})
+		return response, stream, err
+	}
+
+	t.Run("requires the ceremony token", func(t *testing.T) {
+		code, _ := authorize(redirectURI)
+		_, _, err := signIn(code, "")
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("rejects ceremony tokens of other requests", func(t *testing.T) {
+		code, ceremonyToken := authorize(ts.Profile.InstanceURL + "/other")
+		_, _, err := signIn(code, ceremonyToken)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("signs up and signs in", func(t *testing.T) {
+		response, stream, err := signIn(authorize(redirectURI))
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Equal(t, "Alice", response.User.DisplayName)
+		require.Len(t, stream.Header.Get("Set-Cookie"), 1)
+
+		username := "alice"
+		user, err := ts.Store.GetUser(ctx, &store.FindUser{Username: &username})
+		require.NoError(t, err)
+		require.Equal(t, "alice@example.com", user.Email)
+
+		// Authorization codes are single use.
+		code, ceremonyToken := authorize(redirectURI)
+		_, _, err = signIn(code, ceremonyToken)
+		require.NoError(t, err)
+		_, _, err = signIn(code, ceremonyToken)
+		require.Error(t, err)
+	})
+}
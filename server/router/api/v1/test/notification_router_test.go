@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webpushgo "github.com/SherClockHolmes/webpush-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/imrany/wekalist/plugin/webpush"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	user, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+	userName := fmt.Sprintf("users/%d", user.ID)
+
+	findPreference := func(setting *v1pb.UserSetting, eventType v1pb.NotificationPreference_EventType, channel v1pb.NotificationPreference_Channel) *v1pb.NotificationPreference {
+		for _, preference := range setting.NotificationPreferences {
+			if preference.EventType == eventType && preference.Channel == channel {
+				return preference
+			}
+		}
+		return nil
+	}
+
+	setting, err := ts.Service.GetUserSetting(userCtx, &v1pb.GetUserSettingRequest{Name: userName})
+	require.NoError(t, err)
+	require.Len(t, setting.NotificationPreferences, 9)
+	require.True(t, findPreference(setting, v1pb.NotificationPreference_COMMENT, v1pb.NotificationPreference_INBOX).Enabled)
+	require.True(t, findPreference(setting, v1pb.NotificationPreference_COMMENT, v1pb.NotificationPreference_PUSH).Enabled)
+	require.True(t, findPreference(setting, v1pb.NotificationPreference_COMMENT, v1pb.NotificationPreference_EMAIL).Enabled)
+	require.False(t, findPreference(setting, v1pb.NotificationPreference_REACTION, v1pb.NotificationPreference_EMAIL).Enabled)
+	require.False(t, findPreference(setting, v1pb.NotificationPreference_REACTION, v1pb.NotificationPreference_PUSH).Enabled)
+
+	setting, err = ts.Service.UpdateUserSetting(userCtx, &v1pb.UpdateUserSettingRequest{
+		Setting: &v1pb.UserSetting{
+			Name: userName,
+			NotificationPreferences: []*v1pb.NotificationPreference{
+				{EventType: v1pb.NotificationPreference_COMMENT, Channel: v1pb.NotificationPreference_PUSH, Enabled: false},
+				{EventType: v1pb.NotificationPreference_REACTION, Channel: v1pb.NotificationPreference_EMAIL, Enabled: true},
+			},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"notification_preferences"}},
+	})
+	require.NoError(t, err)
+	require.True(t, findPreference(setting, v1pb.NotificationPreference_COMMENT, v1pb.NotificationPreference_INBOX).Enabled)
+	require.False(t, findPreference(setting, v1pb.NotificationPreference_COMMENT, v1pb.NotificationPreference_PUSH).Enabled)
+	require.True(t, findPreference(setting, v1pb.NotificationPreference_REACTION, v1pb.NotificationPreference_EMAIL).Enabled)
+
+	for _, preferences := range [][]*v1pb.NotificationPreference{
+		{{EventType: v1pb.NotificationPreference_COMMENT}},
+		{{Channel: v1pb.NotificationPreference_INBOX}},
+		{{EventType: 42, Channel: v1pb.NotificationPreference_INBOX}},
+		{{EventType: v1pb.NotificationPreference_REMINDER, Channel: v1pb.NotificationPreference_INBOX}},
+		{
+			{EventType: v1pb.NotificationPreference_COMMENT, Channel: v1pb.NotificationPreference_INBOX},
+			{EventType: v1pb.NotificationPreference_COMMENT, Channel: v1pb.NotificationPreference_INBOX, Enabled: true},
+		},
+	} {
+		_, err = ts.Service.UpdateUserSetting(userCtx, &v1pb.UpdateUserSettingRequest{
+			Setting:    &v1pb.UserSetting{Name: userName, NotificationPreferences: preferences},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"notification_preferences"}},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestDigestFrequencySetting(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	user, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+	userName := fmt.Sprintf("users/%d", user.ID)
+
+	setting, err := ts.Service.GetUserSetting(userCtx, &v1pb.GetUserSettingRequest{Name: userName})
+	require.NoError(t, err)
+	require.Equal(t, v1pb.UserSetting_DIGEST_FREQUENCY_UNSPECIFIED, setting.DigestFrequency)
+
+	setting, err = ts.Service.UpdateUserSetting(userCtx, &v1pb.UpdateUserSettingRequest{
+		Setting:    &v1pb.UserSetting{Name: userName, DigestFrequency: v1pb.UserSetting_WEEKLY},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"digest_frequency"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, v1pb.UserSetting_WEEKLY, setting.DigestFrequency)
+	// The first digest lists what happened since it was turned on.
+	digestSetting, err := ts.Store.GetUserDigestSetting(ctx, user.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), digestSetting.LastSentTime.AsTime(), time.Minute)
+
+	_, err = ts.Service.UpdateUserSetting(userCtx, &v1pb.UpdateUserSettingRequest{
+		Setting:    &v1pb.UserSetting{Name: userName, DigestFrequency: 42},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"digest_frequency"}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNotificationRouter(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	alice, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	aliceCtx := ts.CreateUserContext(ctx, alice.ID)
+	bob, err := ts.CreateRegularUser(ctx, "bob")
+	require.NoError(t, err)
+	bobCtx := ts.CreateUserContext(ctx, bob.ID)
+
+	var pushes atomic.Int32
+	pushService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		pushes.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer pushService.Close()
+	privateKey, publicKey, err := webpushgo.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	dispatcherCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ts.Service.PushDispatcher = webpush.NewDispatcher(webpush.Config{
+		VAPIDPublicKey:  publicKey,
+		VAPIDPrivateKey: privateKey,
+	})
+	ts.Service.StartPushDispatcher(dispatcherCtx)
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = ts.Service.AddSubscription(aliceCtx, &v1pb.SubscriptionRequest{Endpoint: pushService.URL + "/alice", Keys: map[string]string{
+		"p256dh": base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		"auth":   base64.RawURLEncoding.EncodeToString([]byte("0123456789abcdef")),
+	}})
+	require.NoError(t, err)
+
+	memo, err := ts.Service.CreateMemo(aliceCtx, &v1pb.CreateMemoRequest{Memo: &v1pb.Memo{Content: "Hello", Visibility: v1pb.Visibility_PUBLIC}})
+	require.NoError(t, err)
+	listInboxes := func() []*v1pb.Inbox {
+		response, err := ts.Service.ListInboxes(aliceCtx, &v1pb.ListInboxesRequest{Parent: fmt.Sprintf("users/%d", alice.ID)})
+		require.NoError(t, err)
+		return response.Inboxes
+	}
+	listPushDeliveries := func() []*store.PushDelivery {
+		pushDeliveries, err := ts.Store.ListPushDeliveries(ctx, &store.FindPushDelivery{UserID: &alice.ID})
+		require.NoError(t, err)
+		return pushDeliveries
+	}
+
+	t.Run("routes comments to the inbox and push by default", func(t *testing.T) {
+		_, err := ts.Service.CreateMemoComment(bobCtx, &v1pb.CreateMemoCommentRequest{Name: memo.Name, Comment: &v1pb.Memo{Content: "Nice", Visibility: v1pb.Visibility_PUBLIC}})
+		require.NoError(t, err)
+		inboxes := listInboxes()
+		require.Len(t, inboxes, 1)
+		require.Equal(t, v1pb.Inbox_MEMO_COMMENT, inboxes[0].Type)
+		require.Eventually(t, func() bool {
+			return len(listPushDeliveries()) == 1
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Equal(t, int32(1), pushes.Load())
+
+		// Own comments are not notified.
+		_, err = ts.Service.CreateMemoComment(aliceCtx, &v1pb.CreateMemoCommentRequest{Name: memo.Name, Comment: &v1pb.Memo{Content: "Thanks", Visibility: v1pb.Visibility_PUBLIC}})
+		require.NoError(t, err)
+		require.Len(t, listInboxes(), 1)
+	})
+
+	t.Run("routes reactions to the inbox by default", func(t *testing.T) {
+		_, err := ts.Service.UpsertMemoReaction(bobCtx, &v1pb.UpsertMemoReactionRequest{Name: memo.Name, Reaction: &v1pb.Reaction{ContentId: memo.Name, ReactionType: "👍"}})
+		require.NoError(t, err)
+		inboxes := listInboxes()
+		require.Len(t, inboxes, 2)
+		// Both inboxes may be created within the same second.
+		reactionInbox := inboxes[0]
+		if reactionInbox.Type != v1pb.Inbox_MEMO_REACTION {
+			reactionInbox = inboxes[1]
+		}
+		require.Equal(t, v1pb.Inbox_MEMO_REACTION, reactionInbox.Type)
+		activity, err := ts.Service.GetActivity(aliceCtx, &v1pb.GetActivityRequest{Name: fmt.Sprintf("activities/%d", reactionInbox.GetActivityId())})
+		require.NoError(t, err)
+		require.Equal(t, v1pb.Activity_MEMO_REACTION, activity.Type)
+		require.Equal(t, memo.Name, activity.Payload.GetMemoReaction().Memo)
+		require.Equal(t, "👍", activity.Payload.GetMemoReaction().ReactionType)
+	})
+
+	t.Run("skips the channels disabled by the receiver", func(t *testing.T) {
+		_, err := ts.Service.UpdateUserSetting(aliceCtx, &v1pb.UpdateUserSettingRequest{
+			Setting: &v1pb.UserSetting{
+				Name: fmt.Sprintf("users/%d", alice.ID),
+				NotificationPreferences: []*v1pb.NotificationPreference{
+					{EventType: v1pb.NotificationPreference_COMMENT, Channel: v1pb.NotificationPreference_INBOX, Enabled: false},
+					{EventType: v1pb.NotificationPreference_COMMENT, Channel: v1pb.NotificationPreference_PUSH, Enabled: false},
+				},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"notification_preferences"}},
+		})
+		require.NoError(t, err)
+		_, err = ts.Service.CreateMemoComment(bobCtx, &v1pb.CreateMemoCommentRequest{Name: memo.Name, Comment: &v1pb.Memo{Content: "Again", Visibility: v1pb.Visibility_PUBLIC}})
+		require.NoError(t, err)
+		require.Len(t, listInboxes(), 2)
+		require.Len(t, listPushDeliveries(), 1)
+	})
+}
+
+func TestMemoMentionNotifications(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	alice, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	aliceCtx := ts.CreateUserContext(ctx, alice.ID)
+	bob, err := ts.CreateRegularUser(ctx, "bob")
+	require.NoError(t, err)
+	bobCtx := ts.CreateUserContext(ctx, bob.ID)
+	listInboxes := func() []*v1pb.Inbox {
+		response, err := ts.Service.ListInboxes(aliceCtx, &v1pb.ListInboxesRequest{Parent: fmt.Sprintf("users/%d", alice.ID)})
+		require.NoError(t, err)
+		return response.Inboxes
+	}
+
+	// Mentions in private memos, emails and code are not notified.
+	_, err = ts.Service.CreateMemo(bobCtx, &v1pb.CreateMemoRequest{Memo: &v1pb.Memo{Content: "Hi @alice", Visibility: v1pb.Visibility_PRIVATE}})
+	require.NoError(t, err)
+	_, err = ts.Service.CreateMemo(bobCtx, &v1pb.CreateMemoRequest{Memo: &v1pb.Memo{Content: "Mail bob@alice.com or run `@alice`", Visibility: v1pb.Visibility_PUBLIC}})
+	require.NoError(t, err)
+	require.Empty(t, listInboxes())
+
+	memo, err := ts.Service.CreateMemo(bobCtx, &v1pb.CreateMemoRequest{Memo: &v1pb.Memo{Content: "Hi @alice and @bob", Visibility: v1pb.Visibility_PUBLIC}})
+	require.NoError(t, err)
+	inboxes := listInboxes()
+	require.Len(t, inboxes, 1)
+	require.Equal(t, v1pb.Inbox_MEMO_MENTION, inboxes[0].Type)
+	activity, err := ts.Service.GetActivity(aliceCtx, &v1pb.GetActivityRequest{Name: fmt.Sprintf("activities/%d", inboxes[0].GetActivityId())})
+	require.NoError(t, err)
+	require.Equal(t, v1pb.Activity_MEMO_MENTION, activity.Type)
+	require.Equal(t, memo.Name, activity.Payload.GetMemoMention().Memo)
+	bobInboxes, err := ts.Service.ListInboxes(bobCtx, &v1pb.ListInboxesRequest{Parent: fmt.Sprintf("users/%d", bob.ID)})
+	require.NoError(t, err)
+	require.Empty(t, bobInboxes.Inboxes)
+
+	// Users already mentioned are not notified again when the memo is edited.
+	memo.Content = "Hello @alice and @bob"
+	_, err = ts.Service.UpdateMemo(bobCtx, &v1pb.UpdateMemoRequest{Memo: memo, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"content"}}})
+	require.NoError(t, err)
+	require.Len(t, listInboxes(), 1)
+}
@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	webpushgo "github.com/SherClockHolmes/webpush-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/imrany/wekalist/plugin/webpush"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestSubscriptionService(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	host, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	hostCtx := ts.CreateUserContext(ctx, host.ID)
+	alice, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	aliceCtx := ts.CreateUserContext(ctx, alice.ID)
+	bob, err := ts.CreateRegularUser(ctx, "bob")
+	require.NoError(t, err)
+	bobCtx := ts.CreateUserContext(ctx, bob.ID)
+
+	keys := map[string]string{"p256dh": "key", "auth": "auth"}
+	listSubscriptions := func(userID int32) []*store.Subscription {
+		subscriptions, err := ts.Store.ListSubscriptions(ctx, &store.FindSubscription{UserID: &userID})
+		require.NoError(t, err)
+		return subscriptions
+	}
+
+	t.Run("requires authentication", func(t *testing.T) {
+		interceptor := apiv1.NewGRPCAuthInterceptor(ts.Store, ts.Secret)
+		for _, method := range []string{"AddSubscription", "RemoveSubscription", "SendNotification"} {
+			_, err := interceptor.AuthenticationInterceptor(metadata.NewIncomingContext(ctx, metadata.MD{}), nil, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.SubscriptionService/" + method}, func(context.Context, any) (any, error) {
+				return nil, nil
+			})
+			require.Equal(t, codes.Unauthenticated, status.Code(err), method)
+		}
+	})
+
+	t.Run("binds subscriptions to the current user", func(t *testing.T) {
+		_, err := ts.Service.AddSubscription(aliceCtx, &v1pb.SubscriptionRequest{Endpoint: "https://push.example.com/alice-1", Keys: keys})
+		require.NoError(t, err)
+		_, err = ts.Service.AddSubscription(aliceCtx, &v1pb.SubscriptionRequest{Endpoint: "https://push.example.com/alice-2", Keys: keys})
+		require.NoError(t, err)
+		_, err = ts.Service.AddSubscription(bobCtx, &v1pb.SubscriptionRequest{Endpoint: "https://push.example.com/bob", Keys: keys})
+		require.NoError(t, err)
+		require.Len(t, listSubscriptions(alice.ID), 2)
+		require.Len(t, listSubscriptions(bob.ID), 1)
+
+		// Subscribing an endpoint again moves it to the current user.
+		_, err = ts.Service.AddSubscription(bobCtx, &v1pb.SubscriptionRequest{Endpoint: "https://push.example.com/alice-2", Keys: keys})
+		require.NoError(t, err)
+		require.Len(t, listSubscriptions(alice.ID), 1)
+		require.Len(t, listSubscriptions(bob.ID), 2)
+
+		_, err = ts.Service.AddSubscription(aliceCtx, &v1pb.SubscriptionRequest{Endpoint: "https://push.example.com/alice-3"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("removes only the matching endpoint of the current user", func(t *testing.T) {
+		_, err := ts.Service.RemoveSubscription(aliceCtx, &v1pb.RemoveSubscriptionRequest{Endpoint: "https://push.example.com/bob"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		require.Len(t, listSubscriptions(bob.ID), 2)
+
+		_, err = ts.Service.RemoveSubscription(bobCtx, &v1pb.RemoveSubscriptionRequest{Endpoint: "https://push.example.com/bob"})
+		require.NoError(t, err)
+		subscriptions := listSubscriptions(bob.ID)
+		require.Len(t, subscriptions, 1)
+		require.Equal(t, "https://push.example.com/alice-2", subscriptions[0].Endpoint)
+		require.Len(t, listSubscriptions(alice.ID), 1)
+	})
+
+	t.Run("only admins send notifications", func(t *testing.T) {
+		request := &v1pb.SendNotificationRequest{Username: "alice", Payload: &v1pb.NotificationPayload{Title: "Hello", Body: "World"}}
+		_, err := ts.Service.SendNotification(aliceCtx, request)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		request.Username = "nobody"
+		_, err = ts.Service.SendNotification(hostCtx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("delivers notifications and prunes gone subscriptions", func(t *testing.T) {
+		var ttl string
+		pushService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/gone") {
+				w.WriteHeader(http.StatusGone)
+				return
+			}
+			ttl = r.Header.Get("TTL")
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer pushService.Close()
+
+		privateKey, publicKey, err := webpushgo.GenerateVAPIDKeys()
+		require.NoError(t, err)
+		dispatcherCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ts.Service.PushDispatcher = webpush.NewDispatcher(webpush.Config{
+			VAPIDPublicKey:  publicKey,
+			VAPIDPrivateKey: privateKey,
+			Backoff:         time.Millisecond,
+		})
+		ts.Service.StartPushDispatcher(dispatcherCtx)
+
+		carol, err := ts.CreateRegularUser(ctx, "carol")
+		require.NoError(t, err)
+		carolCtx := ts.CreateUserContext(ctx, carol.ID)
+		for _, endpoint := range []string{pushService.URL + "/active", pushService.URL + "/gone"} {
+			key, err := ecdh.P256().GenerateKey(rand.Reader)
+			require.NoError(t, err)
+			_, err = ts.Service.AddSubscription(carolCtx, &v1pb.SubscriptionRequest{Endpoint: endpoint, Keys: map[string]string{
+				"p256dh": base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+				"auth":   base64.RawURLEncoding.EncodeToString([]byte("0123456789abcdef")),
+			}})
+			require.NoError(t, err)
+		}
+
+		request := &v1pb.SendNotificationRequest{
+			Username: "carol",
+			Payload:  &v1pb.NotificationPayload{Title: "Hello", Body: "World"},
+			Ttl:      durationpb.New(time.Hour),
+			Urgency:  v1pb.SendNotificationRequest_HIGH,
+			Topic:    "memo-1",
+		}
+		response, err := ts.Service.SendNotification(hostCtx, request)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), response.RecipientsCount)
+
+		listPushDeliveries := func(request *v1pb.ListPushDeliveriesRequest) []*v1pb.PushDelivery {
+			response, err := ts.Service.ListPushDeliveries(hostCtx, request)
+			require.NoError(t, err)
+			return response.PushDeliveries
+		}
+		require.Eventually(t, func() bool {
+			return len(listPushDeliveries(&v1pb.ListPushDeliveriesRequest{Username: "carol"})) == 2
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Equal(t, "3600", ttl)
+
+		delivered := listPushDeliveries(&v1pb.ListPushDeliveriesRequest{Username: "carol", Status: v1pb.PushDelivery_DELIVERED})
+		require.Len(t, delivered, 1)
+		require.Equal(t, pushService.URL+"/active", delivered[0].Endpoint)
+		require.Equal(t, "carol", delivered[0].Username)
+		require.Equal(t, "memo-1", delivered[0].Topic)
+		require.Equal(t, int32(http.StatusCreated), delivered[0].StatusCode)
+		expired := listPushDeliveries(&v1pb.ListPushDeliveriesRequest{Username: "carol", Status: v1pb.PushDelivery_EXPIRED})
+		require.Len(t, expired, 1)
+		require.Equal(t, int32(http.StatusGone), expired[0].StatusCode)
+
+		subscriptions := listSubscriptions(carol.ID)
+		require.Len(t, subscriptions, 1)
+		require.Equal(t, pushService.URL+"/active", subscriptions[0].Endpoint)
+
+		request.Topic = "not a valid topic"
+		_, err = ts.Service.SendNotification(hostCtx, request)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = ts.Service.ListPushDeliveries(carolCtx, &v1pb.ListPushDeliveriesRequest{})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
 	"github.com/imrany/wekalist/internal/profile"
 	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
 	"github.com/imrany/wekalist/store"
@@ -79,3 +82,34 @@ func (*TestService) CreateUserContext(ctx context.Context, userID int32) context
 	// Use the real context key from the parent package
 	return apiv1.CreateTestUserContext(ctx, userID)
 }
+
+// HeaderCapturingStream is a grpc.ServerTransportStream that records the headers set by a handler,
+// such as the session cookie.
+type HeaderCapturingStream struct {
+	Header metadata.MD
+}
+
+func (*HeaderCapturingStream) Method() string {
+	return ""
+}
+
+func (s *HeaderCapturingStream) SetHeader(md metadata.MD) error {
+	s.Header = metadata.Join(s.Header, md)
+	return nil
+}
+
+func (s *HeaderCapturingStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (*HeaderCapturingStream) SetTrailer(metadata.MD) error {
+	return nil
+}
+
+// CreateHeaderCapturingContext creates a context of an incoming gRPC request in which handlers can set headers,
+// and returns the stream capturing them.
+func (*TestService) CreateHeaderCapturingContext(ctx context.Context) (context.Context, *HeaderCapturingStream) {
+	stream := &HeaderCapturingStream{}
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
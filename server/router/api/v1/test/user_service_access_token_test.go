@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestUserAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	user, err := ts.Store.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com"})
+	require.NoError(t, err)
+	userName := fmt.Sprintf("users/%d", user.ID)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+
+	createAccessToken := func(accessToken *v1pb.UserAccessToken) (*v1pb.UserAccessToken, error) {
+		return ts.Service.CreateUserAccessToken(userCtx, &v1pb.CreateUserAccessTokenRequest{Parent: userName, AccessToken: accessToken})
+	}
+	interceptor := apiv1.NewGRPCAuthInterceptor(ts.Store, ts.Secret)
+	call := func(accessToken, method string) error {
+		requestCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+accessToken))
+		_, err := interceptor.AuthenticationInterceptor(requestCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	t.Run("stores only the hash of the access token", func(t *testing.T) {
+		readToken, err := createAccessToken(&v1pb.UserAccessToken{Description: "read"})
+		require.NoError(t, err)
+		require.NotEmpty(t, readToken.AccessToken)
+		require.Equal(t, []string{store.AccessTokenScopeMemosRead}, readToken.Scopes)
+
+		accessTokens, err := ts.Store.GetUserAccessTokens(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, accessTokens, 1)
+		require.Empty(t, accessTokens[0].AccessToken)
+		require.Equal(t, store.HashAccessToken(readToken.AccessToken), accessTokens[0].TokenHash)
+
+		response, err := ts.Service.ListUserAccessTokens(userCtx, &v1pb.ListUserAccessTokensRequest{Parent: userName})
+		require.NoError(t, err)
+		require.Len(t, response.AccessTokens, 1)
+		require.Equal(t, readToken.Name, response.AccessTokens[0].Name)
+		require.Empty(t, response.AccessTokens[0].AccessToken)
+
+		_, err = ts.Service.DeleteUserAccessToken(userCtx, &v1pb.DeleteUserAccessTokenRequest{Name: readToken.Name})
+		require.NoError(t, err)
+		require.Equal(t, codes.Unauthenticated, status.Code(call(readToken.AccessToken, "/wekalist.api.v1.AttachmentService/ListAttachments")))
+	})
+
+	t.Run("rejects unknown scopes", func(t *testing.T) {
+		_, err := createAccessToken(&v1pb.UserAccessToken{Scopes: []string{"memos:delete"}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("enforces scopes per method", func(t *testing.T) {
+		readToken, err := createAccessToken(&v1pb.UserAccessToken{Scopes: []string{store.AccessTokenScopeMemosRead}})
+		require.NoError(t, err)
+		require.NoError(t, call(readToken.AccessToken, "/wekalist.api.v1.MemoService/ListMemos"))
+		require.NoError(t, call(readToken.AccessToken, "/wekalist.api.v1.AuthService/GetCurrentSession"))
+		require.Equal(t, codes.PermissionDenied, status.Code(call(readToken.AccessToken, "/wekalist.api.v1.MemoService/CreateMemo")))
+		require.Equal(t, codes.PermissionDenied, status.Code(call(readToken.AccessToken, "/wekalist.api.v1.UserService/CreateUserAccessToken")))
+
+		writeToken, err := createAccessToken(&v1pb.UserAccessToken{Scopes: []string{store.AccessTokenScopeMemosWrite, store.AccessTokenScopeAttachmentsWrite}})
+		require.NoError(t, err)
+		require.NoError(t, call(writeToken.AccessToken, "/wekalist.api.v1.MemoService/CreateMemo"))
+		require.NoError(t, call(writeToken.AccessToken, "/wekalist.api.v1.AttachmentService/CreateAttachment"))
+		require.Equal(t, codes.PermissionDenied, status.Code(call(writeToken.AccessToken, "/wekalist.api.v1.MemoService/ListMemos")))
+
+		adminToken, err := createAccessToken(&v1pb.UserAccessToken{Scopes: []string{store.AccessTokenScopeAdmin}})
+		require.NoError(t, err)
+		require.NoError(t, call(adminToken.AccessToken, "/wekalist.api.v1.UserService/CreateUserAccessToken"))
+	})
+
+	t.Run("records the last use", func(t *testing.T) {
+		accessToken, err := createAccessToken(&v1pb.UserAccessToken{})
+		require.NoError(t, err)
+		require.Nil(t, accessToken.LastUsedAt)
+		require.NoError(t, call(accessToken.AccessToken, "/wekalist.api.v1.MemoService/ListMemos"))
+
+		response, err := ts.Service.ListUserAccessTokens(userCtx, &v1pb.ListUserAccessTokensRequest{Parent: userName})
+		require.NoError(t, err)
+		for _, listed := range response.AccessTokens {
+			if listed.Name == accessToken.Name {
+				require.NotNil(t, listed.LastUsedAt)
+			}
+		}
+	})
+
+	t.Run("rejects expired access tokens", func(t *testing.T) {
+		_, err := createAccessToken(&v1pb.UserAccessToken{ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		accessToken, err := createAccessToken(&v1pb.UserAccessToken{ExpiresAt: timestamppb.New(time.Now().Add(time.Hour))})
+		require.NoError(t, err)
+		require.NoError(t, call(accessToken.AccessToken, "/wekalist.api.v1.MemoService/ListMemos"))
+
+		accessTokens, err := ts.Store.GetUserAccessTokens(ctx, user.ID)
+		require.NoError(t, err)
+		for _, stored := range accessTokens {
+			if stored.TokenHash == store.HashAccessToken(accessToken.AccessToken) {
+				stored.ExpireTime = timestamppb.New(time.Now().Add(-time.Minute))
+				require.NoError(t, ts.Store.UpsertUserAccessToken(ctx, user.ID, stored))
+			}
+		}
+		require.Equal(t, codes.Unauthenticated, status.Code(call(accessToken.AccessToken, "/wekalist.api.v1.AttachmentService/ListAttachments")))
+	})
+}
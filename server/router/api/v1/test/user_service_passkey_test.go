@@ -0,0 +1,254 @@
+package v1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+)
+
+const testPasskeyOrigin = "http://localhost:8080"
+
+// softwareAuthenticator is a minimal WebAuthn authenticator holding a single ES256 credential.
+type softwareAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+	return &softwareAuthenticator{t: t, key: key, credentialID: credentialID}
+}
+
+func (a *softwareAuthenticator) id() string {
+	return base64.RawURLEncoding.EncodeToString(a.credentialID)
+}
+
+func (a *softwareAuthenticator) clientData(ceremonyType string, optionsJSON string) []byte {
+	options := struct {
+		Challenge string `json:"challenge"`
+		User      struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}{}
+	require.NoError(a.t, json.Unmarshal([]byte(optionsJSON), &options))
+	if options.User.ID != "" {
+		userHandle, err := base64.RawURLEncoding.DecodeString(options.User.ID)
+		require.NoError(a.t, err)
+		a.userHandle = userHandle
+	}
+	clientData, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": options.Challenge,
+		"origin":    testPasskeyOrigin,
+	})
+	require.NoError(a.t, err)
+	return clientData
+}
+
+func (a *softwareAuthenticator) authenticatorData(flags byte, attestedCredential []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte("localhost"))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attestedCredential...)
+}
+
+// register answers navigator.credentials.create with a "none" attestation.
+func (a *softwareAuthenticator) register(optionsJSON string) string {
+	clientData := a.clientData("webauthn.create", optionsJSON)
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(a.t, err)
+	attestedCredential := make([]byte, 16) // AAGUID
+	attestedCredential = binary.BigEndian.AppendUint16(attestedCredential, uint16(len(a.credentialID)))
+	attestedCredential = append(attestedCredential, a.credentialID...)
+	attestedCredential = append(attestedCredential, publicKey...)
+	// Flags: user present, user verified, attested credential data included.
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authenticatorData(0x45, attestedCredential),
+	})
+	require.NoError(a.t, err)
+	return a.marshalCredential(map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+	})
+}
+
+// assert answers navigator.credentials.get.
+func (a *softwareAuthenticator) assert(optionsJSON string) string {
+	clientData := a.clientData("webauthn.get", optionsJSON)
+	a.signCount++
+	// Flags: user present, user verified.
+	authenticatorData := a.authenticatorData(0x05, nil)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+	return a.marshalCredential(map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authenticatorData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+	})
+}
+
+func (a *softwareAuthenticator) marshalCredential(response map[string]string) string {
+	credential, err := json.Marshal(map[string]any{
+		"id":       a.id(),
+		"rawId":    a.id(),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(a.t, err)
+	return string(credential)
+}
+
+func TestUserPasskey(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	user, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userName := fmt.Sprintf("users/%d", user.ID)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+	authenticator := newSoftwareAuthenticator(t)
+
+	registration, err := ts.Service.BeginPasskeyRegistration(userCtx, &v1pb.BeginPasskeyRegistrationRequest{Parent: userName})
+	require.NoError(t, err)
+	credentialJSON := authenticator.register(registration.OptionsJson)
+
+	other, err := ts.CreateRegularUser(ctx, "bob")
+	require.NoError(t, err)
+	_, err = ts.Service.FinishPasskeyRegistration(ts.CreateUserContext(ctx, other.ID), &v1pb.FinishPasskeyRegistrationRequest{
+		Parent:         fmt.Sprintf("users/%d", other.ID),
+		CeremonyToken:  registration.CeremonyToken,
+		CredentialJson: credentialJSON,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	passkey, err := ts.Service.FinishPasskeyRegistration(userCtx, &v1pb.FinishPasskeyRegistrationRequest{
+		Parent:         userName,
+		CeremonyToken:  registration.CeremonyToken,
+		CredentialJson: credentialJSON,
+		DisplayName:    "Laptop",
+	})
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%s/passkeys/%s", userName, authenticator.id()), passkey.Name)
+	require.Equal(t, "Laptop", passkey.DisplayName)
+
+	signIn := func(username string) (*v1pb.CreateSessionResponse, *HeaderCapturingStream, error) {
+		assertion, err := ts.Service.BeginPasskeyAssertion(ctx, &v1pb.BeginPasskeyAssertionRequest{Username: username})
+		require.NoError(t, err)
+		sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+		response, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasskeyCredentials_{
+				PasskeyCredentials: &v1pb.CreateSessionRequest_PasskeyCredentials{
+					CeremonyToken:  assertion.CeremonyToken,
+					CredentialJson: authenticator.assert(assertion.OptionsJson),
+				},
+			},
+		})
+		return response, stream, err
+	}
+
+	t.Run("sign in with username", func(t *testing.T) {
+		response, stream, err := signIn("alice")
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Len(t, stream.Header.Get("Set-Cookie"), 1)
+	})
+
+	t.Run("sign in with discoverable passkey", func(t *testing.T) {
+		response, stream, err := signIn("")
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Len(t, stream.Header.Get("Set-Cookie"), 1)
+
+		passkeys, err := ts.Service.ListUserPasskeys(userCtx, &v1pb.ListUserPasskeysRequest{Parent: userName})
+		require.NoError(t, err)
+		require.Len(t, passkeys.Passkeys, 1)
+		require.NotNil(t, passkeys.Passkeys[0].LastUsedTime)
+	})
+
+	t.Run("replayed assertions are rejected", func(t *testing.T) {
+		assertion, err := ts.Service.BeginPasskeyAssertion(ctx, &v1pb.BeginPasskeyAssertionRequest{})
+		require.NoError(t, err)
+		authenticator.signCount--
+		sessionCtx, _ := ts.CreateHeaderCapturingContext(ctx)
+		_, err = ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasskeyCredentials_{
+				PasskeyCredentials: &v1pb.CreateSessionRequest_PasskeyCredentials{
+					CeremonyToken:  assertion.CeremonyToken,
+					CredentialJson: authenticator.assert(assertion.OptionsJson),
+				},
+			},
+		})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+		authenticator.signCount++
+	})
+
+	t.Run("ceremony tokens are single use", func(t *testing.T) {
+		assertion, err := ts.Service.BeginPasskeyAssertion(ctx, &v1pb.BeginPasskeyAssertionRequest{})
+		require.NoError(t, err)
+		createSession := func() error {
+			sessionCtx, _ := ts.CreateHeaderCapturingContext(ctx)
+			_, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+				Credentials: &v1pb.CreateSessionRequest_PasskeyCredentials_{
+					PasskeyCredentials: &v1pb.CreateSessionRequest_PasskeyCredentials{
+						CeremonyToken:  assertion.CeremonyToken,
+						CredentialJson: authenticator.assert(assertion.OptionsJson),
+					},
+				},
+			})
+			return err
+		}
+		require.NoError(t, createSession())
+		// A new assertion of the same challenge is rejected, even with a valid sign count.
+		require.Equal(t, codes.Unauthenticated, status.Code(createSession()))
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		_, err := ts.Service.ListUserPasskeys(ts.CreateUserContext(ctx, other.ID), &v1pb.ListUserPasskeysRequest{Parent: userName})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = ts.Service.DeleteUserPasskey(ts.CreateUserContext(ctx, other.ID), &v1pb.DeleteUserPasskeyRequest{Name: passkey.Name})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = ts.Service.DeleteUserPasskey(userCtx, &v1pb.DeleteUserPasskeyRequest{Name: passkey.Name})
+		require.NoError(t, err)
+		passkeys, err := ts.Service.ListUserPasskeys(userCtx, &v1pb.ListUserPasskeysRequest{Parent: userName})
+		require.NoError(t, err)
+		require.Empty(t, passkeys.Passkeys)
+
+		_, _, err = signIn("alice")
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
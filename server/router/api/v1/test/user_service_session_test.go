@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestUserSessions(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user, err := ts.Store.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com", PasswordHash: string(passwordHash)})
+	require.NoError(t, err)
+	userName := fmt.Sprintf("users/%d", user.ID)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+
+	signIn := func() string {
+		sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+		_, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+				PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "alice", Password: "password"},
+			},
+		})
+		require.NoError(t, err)
+		cookie, err := http.ParseSetCookie(stream.Header.Get("Set-Cookie")[0])
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(apiv1.SessionAbsoluteDuration), cookie.Expires, time.Minute)
+		return cookie.Value
+	}
+	interceptor := apiv1.NewGRPCAuthInterceptor(ts.Store, ts.Secret)
+	call := func(cookieValue string) error {
+		requestCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("cookie", apiv1.SessionCookieName+"="+cookieValue))
+		_, err := interceptor.AuthenticationInterceptor(requestCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.MemoService/CreateMemo"}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	t.Run("stores sessions with their expiry", func(t *testing.T) {
+		cookieValue := signIn()
+		require.NoError(t, call(cookieValue))
+
+		response, err := ts.Service.ListUserSessions(userCtx, &v1pb.ListUserSessionsRequest{Parent: userName})
+		require.NoError(t, err)
+		require.Len(t, response.Sessions, 1)
+		require.Equal(t, fmt.Sprintf("%d-%s", user.ID, response.Sessions[0].SessionId), cookieValue)
+		require.WithinDuration(t, time.Now().Add(apiv1.SessionSlidingDuration), response.Sessions[0].ExpireTime.AsTime(), time.Minute)
+
+		_, err = ts.Service.RevokeUserSession(userCtx, &v1pb.RevokeUserSessionRequest{Name: response.Sessions[0].Name})
+		require.NoError(t, err)
+		require.Equal(t, codes.Unauthenticated, status.Code(call(cookieValue)))
+	})
+
+	t.Run("rejects expired sessions", func(t *testing.T) {
+		now := time.Now()
+		for id, userSession := range map[string]*store.UserSession{
+			"idle":    {LastAccessedTs: now.Add(-apiv1.SessionSlidingDuration - time.Minute).Unix(), ExpiresTs: now.Add(time.Hour).Unix()},
+			"expired": {LastAccessedTs: now.Unix(), ExpiresTs: now.Add(-time.Minute).Unix()},
+		} {
+			userSession.ID, userSession.UserID, userSession.CreatedTs = id, user.ID, now.Add(-apiv1.SessionAbsoluteDuration).Unix()
+			_, err := ts.Store.CreateUserSession(ctx, userSession)
+			require.NoError(t, err)
+			require.Equal(t, codes.Unauthenticated, status.Code(call(apiv1.BuildSessionCookieValue(user.ID, id))))
+		}
+
+		response, err := ts.Service.ListUserSessions(userCtx, &v1pb.ListUserSessionsRequest{Parent: userName})
+		require.NoError(t, err)
+		require.Empty(t, response.Sessions)
+	})
+
+	t.Run("revokes all sessions of a user", func(t *testing.T) {
+		cookieValues := []string{signIn(), signIn()}
+
+		bob, err := ts.CreateRegularUser(ctx, "bob")
+		require.NoError(t, err)
+		_, err = ts.Service.RevokeAllUserSessions(ts.CreateUserContext(ctx, bob.ID), &v1pb.RevokeAllUserSessionsRequest{Parent: userName})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		require.NoError(t, call(cookieValues[0]))
+
+		admin, err := ts.CreateHostUser(ctx, "admin")
+		require.NoError(t, err)
+		_, err = ts.Service.RevokeAllUserSessions(ts.CreateUserContext(ctx, admin.ID), &v1pb.RevokeAllUserSessionsRequest{Parent: userName})
+		require.NoError(t, err)
+		for _, cookieValue := range cookieValues {
+			require.Equal(t, codes.Unauthenticated, status.Code(call(cookieValue)))
+		}
+	})
+}
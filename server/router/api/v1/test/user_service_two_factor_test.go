@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/imrany/wekalist/plugin/totp"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestUserTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user, err := ts.Store.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com", PasswordHash: string(passwordHash)})
+	require.NoError(t, err)
+	userName := fmt.Sprintf("users/%d", user.ID)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+
+	signIn := func(request *v1pb.CreateSessionRequest) (*v1pb.CreateSessionResponse, *HeaderCapturingStream, error) {
+		sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+		response, err := ts.Service.CreateSession(sessionCtx, request)
+		return response, stream, err
+	}
+	passwordRequest := &v1pb.CreateSessionRequest{
+		Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+			PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "alice", Password: "password"},
+		},
+	}
+	twoFactorRequest := func(challenge, code string) *v1pb.CreateSessionRequest {
+		return &v1pb.CreateSessionRequest{
+			Credentials: &v1pb.CreateSessionRequest_TwoFactorCredentials_{
+				TwoFactorCredentials: &v1pb.CreateSessionRequest_TwoFactorCredentials{Challenge: challenge, Code: code},
+			},
+		}
+	}
+	codeAt := func(secret string, offset time.Duration) string {
+		code, err := totp.GenerateCode(secret, totp.Step(time.Now().Add(offset)))
+		require.NoError(t, err)
+		return code
+	}
+
+	setup, err := ts.Service.SetupUserTwoFactor(userCtx, &v1pb.SetupUserTwoFactorRequest{Name: userName})
+	require.NoError(t, err)
+	require.NotEmpty(t, setup.Secret)
+	require.True(t, strings.HasPrefix(setup.ProvisioningUri, "otpauth://totp/"))
+	require.Contains(t, setup.ProvisioningUri, ":alice@example.com?")
+	require.Len(t, setup.RecoveryCodes, 10)
+
+	// Two-factor authentication is not enforced until the setup is confirmed.
+	response, _, err := signIn(passwordRequest)
+	require.NoError(t, err)
+	require.NotNil(t, response.User)
+	require.Empty(t, response.TwoFactorChallenge)
+
+	_, err = ts.Service.EnableUserTwoFactor(userCtx, &v1pb.EnableUserTwoFactorRequest{Name: userName, Code: "000000"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	enableCode := codeAt(setup.Secret, 0)
+	_, err = ts.Service.EnableUserTwoFactor(userCtx, &v1pb.EnableUserTwoFactorRequest{Name: userName, Code: enableCode})
+	require.NoError(t, err)
+	userSetting, err := ts.Service.GetUserSetting(userCtx, &v1pb.GetUserSettingRequest{Name: userName})
+	require.NoError(t, err)
+	require.True(t, userSetting.TwoFactorEnabled)
+	_, err = ts.Service.SetupUserTwoFactor(userCtx, &v1pb.SetupUserTwoFactorRequest{Name: userName})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	t.Run("password sign-in returns a challenge", func(t *testing.T) {
+		response, stream, err := signIn(passwordRequest)
+		require.NoError(t, err)
+		require.Nil(t, response.User)
+		require.NotEmpty(t, response.TwoFactorChallenge)
+		require.Empty(t, stream.Header.Get("Set-Cookie"))
+
+		_, _, err = signIn(twoFactorRequest(response.TwoFactorChallenge, "000000"))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		// The code used to enable two-factor authentication can't be replayed.
+		_, _, err = signIn(twoFactorRequest(response.TwoFactorChallenge, enableCode))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, _, err = signIn(twoFactorRequest("invalid", codeAt(setup.Secret, totp.Period*time.Second)))
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		response, stream, err = signIn(twoFactorRequest(response.TwoFactorChallenge, codeAt(setup.Secret, totp.Period*time.Second)))
+		require.NoError(t, err)
+		require.Equal(t, "alice", response.User.Username)
+		require.Len(t, stream.Header.Get("Set-Cookie"), 1)
+	})
+
+	t.Run("recovery codes are single use", func(t *testing.T) {
+		response, _, err := signIn(passwordRequest)
+		require.NoError(t, err)
+		_, _, err = signIn(twoFactorRequest(response.TwoFactorChallenge, strings.ToUpper(setup.RecoveryCodes[0])))
+		require.NoError(t, err)
+		_, _, err = signIn(twoFactorRequest(response.TwoFactorChallenge, setup.RecoveryCodes[0]))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		other, err := ts.CreateRegularUser(ctx, "bob")
+		require.NoError(t, err)
+		_, err = ts.Service.DisableUserTwoFactor(ts.CreateUserContext(ctx, other.ID), &v1pb.DisableUserTwoFactorRequest{Name: userName})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = ts.Service.DisableUserTwoFactor(userCtx, &v1pb.DisableUserTwoFactorRequest{Name: userName})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = ts.Service.DisableUserTwoFactor(userCtx, &v1pb.DisableUserTwoFactorRequest{Name: userName, Code: setup.RecoveryCodes[1]})
+		require.NoError(t, err)
+		response, _, err := signIn(passwordRequest)
+		require.NoError(t, err)
+		require.NotNil(t, response.User)
+	})
+
+	t.Run("admins can disable two-factor authentication of other users", func(t *testing.T) {
+		setup, err := ts.Service.SetupUserTwoFactor(userCtx, &v1pb.SetupUserTwoFactorRequest{Name: userName})
+		require.NoError(t, err)
+		_, err = ts.Service.EnableUserTwoFactor(userCtx, &v1pb.EnableUserTwoFactorRequest{Name: userName, Code: codeAt(setup.Secret, 0)})
+		require.NoError(t, err)
+
+		host, err := ts.CreateHostUser(ctx, "host")
+		require.NoError(t, err)
+		_, err = ts.Service.DisableUserTwoFactor(ts.CreateUserContext(ctx, host.ID), &v1pb.DisableUserTwoFactorRequest{Name: userName})
+		require.NoError(t, err)
+		twoFactor, err := ts.Store.GetUserTwoFactor(ctx, user.ID)
+		require.NoError(t, err)
+		require.False(t, twoFactor.GetEnabled())
+	})
+}
+
+func TestRequireTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user, err := ts.Store.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com", PasswordHash: string(passwordHash)})
+	require.NoError(t, err)
+	_, err = ts.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_GENERAL,
+		Value: &storepb.WorkspaceSetting_GeneralSetting{
+			GeneralSetting: &storepb.WorkspaceGeneralSetting{RequireTwoFactor: true},
+		},
+	})
+	require.NoError(t, err)
+
+	sessionCtx, stream := ts.CreateHeaderCapturingContext(ctx)
+	response, err := ts.Service.CreateSession(sessionCtx, &v1pb.CreateSessionRequest{
+		Credentials: &v1pb.CreateSessionRequest_PasswordCredentials_{
+			PasswordCredentials: &v1pb.CreateSessionRequest_PasswordCredentials{Username: "alice", Password: "password"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, response.TwoFactorSetupRequired)
+	cookie, err := http.ParseSetCookie(stream.Header.Get("Set-Cookie")[0])
+	require.NoError(t, err)
+
+	interceptor := apiv1.NewGRPCAuthInterceptor(ts.Store, ts.Secret)
+	call := func(method string) error {
+		requestCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("cookie", apiv1.SessionCookieName+"="+cookie.Value))
+		_, err := interceptor.AuthenticationInterceptor(requestCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	// Only the methods needed to set up two-factor authentication are available.
+	require.Equal(t, codes.FailedPrecondition, status.Code(call("/wekalist.api.v1.MemoService/CreateMemo")))
+	require.NoError(t, call("/wekalist.api.v1.UserService/SetupUserTwoFactor"))
+
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+	userName := fmt.Sprintf("users/%d", user.ID)
+	setup, err := ts.Service.SetupUserTwoFactor(userCtx, &v1pb.SetupUserTwoFactorRequest{Name: userName})
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(setup.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	_, err = ts.Service.EnableUserTwoFactor(userCtx, &v1pb.EnableUserTwoFactorRequest{Name: userName, Code: code})
+	require.NoError(t, err)
+	require.NoError(t, call("/wekalist.api.v1.MemoService/CreateMemo"))
+}
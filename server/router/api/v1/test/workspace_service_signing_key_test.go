@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestSigningKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	host, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	hostCtx := ts.CreateUserContext(ctx, host.ID)
+	user, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userCtx := ts.CreateUserContext(ctx, user.ID)
+	userName := fmt.Sprintf("users/%d", user.ID)
+
+	createAccessToken := func() string {
+		accessToken, err := ts.Service.CreateUserAccessToken(userCtx, &v1pb.CreateUserAccessTokenRequest{Parent: userName, AccessToken: &v1pb.UserAccessToken{}})
+		require.NoError(t, err)
+		return accessToken.AccessToken
+	}
+	interceptor := apiv1.NewGRPCAuthInterceptor(ts.Store, ts.Secret)
+	authenticate := func(accessToken string) error {
+		requestCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+accessToken))
+		_, err := interceptor.AuthenticationInterceptor(requestCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/wekalist.api.v1.AttachmentService/ListAttachments"}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+	kid := func(accessToken string) string {
+		token, _, err := jwt.NewParser().ParseUnverified(accessToken, &jwt.RegisteredClaims{})
+		require.NoError(t, err)
+		return token.Header["kid"].(string)
+	}
+	listSigningKeys := func() []*v1pb.SigningKey {
+		response, err := ts.Service.ListSigningKeys(hostCtx, &v1pb.ListSigningKeysRequest{})
+		require.NoError(t, err)
+		return response.SigningKeys
+	}
+
+	legacyToken := createAccessToken()
+	require.Equal(t, apiv1.KeyID, kid(legacyToken))
+	require.NoError(t, authenticate(legacyToken))
+	signingKeys := listSigningKeys()
+	require.Len(t, signingKeys, 1)
+	require.Equal(t, apiv1.SigningKeyNamePrefix+apiv1.KeyID, signingKeys[0].Name)
+	require.Nil(t, signingKeys[0].ExpireTime)
+
+	t.Run("tokens signed by the previous key validate during the grace period", func(t *testing.T) {
+		signingKey, err := ts.Service.RotateSigningKey(hostCtx, &v1pb.RotateSigningKeyRequest{})
+		require.NoError(t, err)
+		require.Nil(t, signingKey.ExpireTime)
+
+		signingKeys := listSigningKeys()
+		require.Len(t, signingKeys, 2)
+		require.Equal(t, signingKey.Name, signingKeys[0].Name)
+		require.Equal(t, apiv1.SigningKeyNamePrefix+apiv1.KeyID, signingKeys[1].Name)
+		require.WithinDuration(t, time.Now().Add(7*24*time.Hour), signingKeys[1].ExpireTime.AsTime(), time.Minute)
+
+		accessToken := createAccessToken()
+		require.Equal(t, strings.TrimPrefix(signingKey.Name, apiv1.SigningKeyNamePrefix), kid(accessToken))
+		require.NoError(t, authenticate(accessToken))
+		require.NoError(t, authenticate(legacyToken))
+	})
+
+	t.Run("revoked keys stop validating immediately", func(t *testing.T) {
+		_, err := ts.Service.RevokeSigningKey(hostCtx, &v1pb.RevokeSigningKeyRequest{Name: apiv1.SigningKeyNamePrefix + apiv1.KeyID})
+		require.NoError(t, err)
+		require.Equal(t, codes.Unauthenticated, status.Code(authenticate(legacyToken)))
+		require.Len(t, listSigningKeys(), 1)
+
+		_, err = ts.Service.RevokeSigningKey(hostCtx, &v1pb.RevokeSigningKeyRequest{Name: apiv1.SigningKeyNamePrefix + apiv1.KeyID})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		_, err = ts.Service.RevokeSigningKey(hostCtx, &v1pb.RevokeSigningKeyRequest{Name: listSigningKeys()[0].Name})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("rotating without a grace period retires the previous key immediately", func(t *testing.T) {
+		accessToken := createAccessToken()
+		_, err := ts.Service.RotateSigningKey(hostCtx, &v1pb.RotateSigningKeyRequest{GracePeriod: durationpb.New(0)})
+		require.NoError(t, err)
+		require.Len(t, listSigningKeys(), 1)
+		require.Equal(t, codes.Unauthenticated, status.Code(authenticate(accessToken)))
+		require.NoError(t, authenticate(createAccessToken()))
+	})
+
+	t.Run("saving the basic setting keeps the signing keys", func(t *testing.T) {
+		workspaceBasicSetting, err := ts.Store.GetWorkspaceBasicSetting(ctx)
+		require.NoError(t, err)
+		accessToken := createAccessToken()
+		_, err = ts.Service.UpdateWorkspaceSetting(hostCtx, &v1pb.UpdateWorkspaceSettingRequest{
+			Setting: &v1pb.WorkspaceSetting{Name: "workspace/settings/BASIC"},
+		})
+		require.NoError(t, err)
+		savedWorkspaceBasicSetting, err := ts.Store.GetWorkspaceBasicSetting(ctx)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(workspaceBasicSetting, savedWorkspaceBasicSetting))
+		require.NoError(t, authenticate(accessToken))
+	})
+
+	t.Run("only the host manages signing keys", func(t *testing.T) {
+		admin, err := ts.Store.CreateUser(ctx, &store.User{Username: "bob", Role: store.RoleAdmin, Email: "bob@example.com"})
+		require.NoError(t, err)
+		for _, userID := range []int32{user.ID, admin.ID} {
+			userCtx := ts.CreateUserContext(ctx, userID)
+			_, err := ts.Service.ListSigningKeys(userCtx, &v1pb.ListSigningKeysRequest{})
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+			_, err = ts.Service.RotateSigningKey(userCtx, &v1pb.RotateSigningKeyRequest{})
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+			_, err = ts.Service.RevokeSigningKey(userCtx, &v1pb.RevokeSigningKeyRequest{Name: listSigningKeys()[0].Name})
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+		}
+	})
+}
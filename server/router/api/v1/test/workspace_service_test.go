@@ -2,10 +2,16 @@ package v1
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
 )
@@ -204,3 +210,106 @@ func TestGetWorkspaceSetting(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid workspace setting name")
 	})
 }
+
+func TestEmailTemplatesSetting(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	hostUser, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	hostCtx := ts.CreateUserContext(ctx, hostUser.ID)
+	regularUser, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userCtx := ts.CreateUserContext(ctx, regularUser.ID)
+
+	updateTemplates := func(ctx context.Context, templates ...*v1pb.EmailTemplate) (*v1pb.WorkspaceSetting, error) {
+		return ts.Service.UpdateWorkspaceSetting(ctx, &v1pb.UpdateWorkspaceSettingRequest{
+			Setting: &v1pb.WorkspaceSetting{
+				Name: "workspace/settings/EMAIL_TEMPLATES",
+				Value: &v1pb.WorkspaceSetting_EmailTemplatesSetting{
+					EmailTemplatesSetting: &v1pb.WorkspaceEmailTemplatesSetting{Templates: templates},
+				},
+			},
+		})
+	}
+	otpTemplate := &v1pb.EmailTemplate{Name: "otp", Locale: "fr", Subject: "Code {{.Code}}", TextBody: "{{.Code}}", HtmlBody: "<p>{{.Code}}</p>"}
+
+	_, err = updateTemplates(userCtx, otpTemplate)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = updateTemplates(hostCtx, &v1pb.EmailTemplate{Name: "unknown", Subject: "s", TextBody: "t", HtmlBody: "h"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = updateTemplates(hostCtx, &v1pb.EmailTemplate{Name: "otp", Subject: "{{.Code", TextBody: "t", HtmlBody: "h"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = updateTemplates(hostCtx, otpTemplate, &v1pb.EmailTemplate{Name: "otp", Locale: "FR", Subject: "s", TextBody: "t", HtmlBody: "h"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = updateTemplates(hostCtx, otpTemplate)
+	require.NoError(t, err)
+	setting, err := ts.Service.GetWorkspaceSetting(hostCtx, &v1pb.GetWorkspaceSettingRequest{Name: "workspace/settings/EMAIL_TEMPLATES"})
+	require.NoError(t, err)
+	require.Len(t, setting.GetEmailTemplatesSetting().Templates, 1)
+	require.Equal(t, "Code {{.Code}}", setting.GetEmailTemplatesSetting().Templates[0].Subject)
+
+	// Only the host can read the templates.
+	_, err = ts.Service.GetWorkspaceSetting(userCtx, &v1pb.GetWorkspaceSettingRequest{Name: "workspace/settings/EMAIL_TEMPLATES"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGeneralSettingMailSecrets(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestService(t)
+	defer ts.Cleanup()
+
+	hostUser, err := ts.CreateHostUser(ctx, "admin")
+	require.NoError(t, err)
+	hostCtx := ts.CreateUserContext(ctx, hostUser.ID)
+	regularUser, err := ts.CreateRegularUser(ctx, "alice")
+	require.NoError(t, err)
+	userCtx := ts.CreateUserContext(ctx, regularUser.ID)
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	generalSetting := &v1pb.WorkspaceGeneralSetting{
+		SmtpHost:            "smtp.example.com",
+		SmtpPort:            587,
+		SmtpAccountUsername: "wekalist",
+		SmtpAccountEmail:    "wekalist@example.com",
+		SmtpAccountPassword: "secret",
+		DkimDomain:          "example.com",
+		DkimSelector:        "wekalist",
+		DkimPrivateKey:      "not a key",
+	}
+	updateGeneralSetting := func() error {
+		_, err := ts.Service.UpdateWorkspaceSetting(hostCtx, &v1pb.UpdateWorkspaceSettingRequest{
+			Setting: &v1pb.WorkspaceSetting{
+				Name:  "workspace/settings/GENERAL",
+				Value: &v1pb.WorkspaceSetting_GeneralSetting{GeneralSetting: generalSetting},
+			},
+		})
+		return err
+	}
+	require.Equal(t, codes.InvalidArgument, status.Code(updateGeneralSetting()))
+	generalSetting.DkimPrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	require.NoError(t, updateGeneralSetting())
+
+	getGeneralSetting := func(ctx context.Context) *v1pb.WorkspaceGeneralSetting {
+		setting, err := ts.Service.GetWorkspaceSetting(ctx, &v1pb.GetWorkspaceSettingRequest{Name: "workspace/settings/GENERAL"})
+		require.NoError(t, err)
+		return setting.GetGeneralSetting()
+	}
+	setting := getGeneralSetting(hostCtx)
+	require.Equal(t, "secret", setting.SmtpAccountPassword)
+	require.Equal(t, generalSetting.DkimPrivateKey, setting.DkimPrivateKey)
+
+	// Only the host can read the mail secrets.
+	for _, ctx := range []context.Context{userCtx, ctx} {
+		setting := getGeneralSetting(ctx)
+		require.Equal(t, "smtp.example.com", setting.SmtpHost)
+		require.Equal(t, "example.com", setting.DkimDomain)
+		require.Empty(t, setting.SmtpAccountPassword)
+		require.Empty(t, setting.DkimPrivateKey)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
@@ -27,7 +26,7 @@ import (
 	"github.com/imrany/wekalist/store"
 )
 
-func (s *APIV1Service) VerifyUser(ctx context.Context, request *v1pb.VerifyRequest) (*v1pb.VerifyResponse, error){
+func (s *APIV1Service) VerifyUser(ctx context.Context, request *v1pb.VerifyRequest) (*v1pb.VerifyResponse, error) {
 	if !base.EMAILMatcher.MatchString(strings.ToLower(request.Email)) {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid email: %s", request.Email)
 	}
@@ -37,32 +36,17 @@ func (s *APIV1Service) VerifyUser(ctx context.Context, request *v1pb.VerifyReque
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to send OTP: %v", err.Error())
 	}
-	
+
 	if len(email) != 0 {
 		return nil, status.Errorf(codes.AlreadyExists, "failed, account already exist")
 	}
 
-	generalSettings, err := s.Store.GetWorkspaceGeneralSetting(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get smtp configurations %v", err.Error())
-	}
-	smtpConfig:=SMTPConfig{
-		Host: generalSettings.SmtpHost,
-		Port: int(generalSettings.SmtpPort),
-		Username: generalSettings.SmtpAccountUsername,
-		Email: generalSettings.SmtpAccountEmail,
-		Password: generalSettings.SmtpAccountPassword,
-	}
-
-	otp := GenerateOTP()
-	_, err =SendOTP(request.Email, OtpPurposeVerification ,otp, smtpConfig)
-	if err !=nil{
-		return nil, status.Errorf(codes.Internal, "%v", err.Error())
+	if err := s.sendOTP(ctx, OtpPurposeVerification, request.Email, s.getEmailLocale(ctx, nil)); err != nil {
+		return nil, err
 	}
 
 	verificationRespond := &v1pb.VerifyResponse{
 		Email: request.Email,
-		Otp: otp,
 	}
 	return verificationRespond, nil
 }
@@ -199,6 +183,7 @@ func (s *APIV1Service) CreateUser(ctx context.Context, request *v1pb.CreateUserR
 
 	// Determine the role to assign and check permissions
 	var roleToAssign store.Role
+	createdByHost := false
 	if len(existedHostUsers) == 0 {
 		// First-time setup: create the first user as HOST (no authentication required)
 		roleToAssign = store.RoleHost
@@ -208,6 +193,7 @@ func (s *APIV1Service) CreateUser(ctx context.Context, request *v1pb.CreateUserR
 		currentUser, err := s.GetCurrentUser(ctx)
 		if err == nil && currentUser != nil && currentUser.Role == store.RoleHost {
 			// Authenticated HOST user can create users with any role specified in request
+			createdByHost = true
 			if request.User.Role != v1pb.User_ROLE_UNSPECIFIED {
 				roleToAssign = convertUserRoleToStore(request.User.Role)
 			} else {
@@ -223,6 +209,43 @@ func (s *APIV1Service) CreateUser(ctx context.Context, request *v1pb.CreateUserR
 		return nil, status.Errorf(codes.InvalidArgument, "invalid username: %s", request.User.Username)
 	}
 
+	// Self sign-up requires a verified email when email verification is enabled.
+	// The first host user and users created by a host are exempt.
+	requireOTP := false
+	if len(existedHostUsers) > 0 && !createdByHost {
+		generalSettings, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace general setting: %v", err)
+		}
+		requireOTP = generalSettings.EnableEmailVerification
+	}
+	if requireOTP {
+		if request.User.Email == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "email is required")
+		}
+		if request.Otp == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "email verification is required")
+		}
+	}
+
+	// Check for conflicts before the OTP is redeemed, so that a failed sign-up does not burn the code.
+	existingUser, err := s.Store.GetUser(ctx, &store.FindUser{Username: &request.User.Username})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if existingUser != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "username %s is already taken", request.User.Username)
+	}
+	if requireOTP {
+		existingUsers, err := s.Store.ListUsers(ctx, &store.FindUser{Email: &request.User.Email})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+		}
+		if len(existingUsers) != 0 {
+			return nil, status.Errorf(codes.AlreadyExists, "failed, account already exist")
+		}
+	}
+
 	// If validate_only is true, just validate without creating
 	if request.ValidateOnly {
 		// Perform validation checks without actually creating the user
@@ -239,6 +262,13 @@ func (s *APIV1Service) CreateUser(ctx context.Context, request *v1pb.CreateUserR
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate password hash").SetInternal(err)
 	}
 
+	// Redeem the OTP last, right before the user is inserted.
+	if requireOTP {
+		if err := s.verifyOTP(ctx, OtpPurposeVerification, request.User.Email, request.Otp); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.Store.CreateUser(ctx, &store.User{
 		Username:     request.User.Username,
 		Role:         roleToAssign,
@@ -376,15 +406,15 @@ func (s *APIV1Service) DeleteUser(ctx context.Context, request *v1pb.DeleteUserR
 
 func getDefaultUserSetting() *v1pb.UserSetting {
 	return &v1pb.UserSetting{
-		Name:           "", // Will be set by caller
-		Locale:         "en",
-		Appearance:     "system",
-		MemoVisibility: "PRIVATE",
-		Theme:          "",
+		Name:                "", // Will be set by caller
+		Locale:              "en",
+		Appearance:          "system",
+		MemoVisibility:      "PRIVATE",
+		Theme:               "",
 		EnableNotifications: false,
-		WrapperApiKey: "",
+		WrapperApiKey:       "",
 		WrapperUsageCounter: 0,
-		WrapperMaxUsage: 0,
+		WrapperMaxUsage:     0,
 	}
 }
 
@@ -427,6 +457,8 @@ func (s *APIV1Service) GetUserSetting(ctx context.Context, request *v1pb.GetUser
 				userSettingMessage.WrapperMaxUsage = general.WrapperMaxUsage
 				userSettingMessage.WrapperUsageCounter = general.WrapperUsageCounter
 			}
+		} else if setting.Key == storepb.UserSetting_TWO_FACTOR {
+			userSettingMessage.TwoFactorEnabled = setting.GetTwoFactor().GetEnabled()
 		}
 	}
 
@@ -443,6 +475,24 @@ func (s *APIV1Service) GetUserSetting(ctx context.Context, request *v1pb.GetUser
 		userSettingMessage.Theme = workspaceTheme
 	}
 
+	notificationPreferences, err := s.getNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get notification preferences: %v", err)
+	}
+	for _, preference := range notificationPreferences {
+		userSettingMessage.NotificationPreferences = append(userSettingMessage.NotificationPreferences, &v1pb.NotificationPreference{
+			EventType: v1pb.NotificationPreference_EventType(preference.EventType),
+			Channel:   v1pb.NotificationPreference_Channel(preference.Channel),
+			Enabled:   preference.Enabled,
+		})
+	}
+
+	digestSetting, err := s.Store.GetUserDigestSetting(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get digest setting: %v", err)
+	}
+	userSettingMessage.DigestFrequency = v1pb.UserSetting_DigestFrequency(digestSetting.Frequency)
+
 	return userSettingMessage, nil
 }
 
@@ -478,14 +528,14 @@ func (s *APIV1Service) UpdateUserSetting(ctx context.Context, request *v1pb.Upda
 
 	// Create or update the general setting
 	generalSetting := &storepb.GeneralUserSetting{
-		Locale:         "en",
-		Appearance:     "system",
-		MemoVisibility: "PRIVATE",
-		Theme:          "",
+		Locale:              "en",
+		Appearance:          "system",
+		MemoVisibility:      "PRIVATE",
+		Theme:               "",
 		EnableNotifications: false,
-		WrapperApiKey: "",
+		WrapperApiKey:       "",
 		WrapperUsageCounter: 0,
-		WrapperMaxUsage: 0,
+		WrapperMaxUsage:     0,
 	}
 
 	// If there's an existing setting, use its values as defaults
@@ -502,7 +552,21 @@ func (s *APIV1Service) UpdateUserSetting(ctx context.Context, request *v1pb.Upda
 	}
 
 	// Apply updates based on the update mask
+	updateGeneralSetting := false
 	for _, field := range request.UpdateMask.Paths {
+		if field == "notification_preferences" {
+			if err := s.updateNotificationPreferences(ctx, userID, request.Setting.NotificationPreferences); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if field == "digest_frequency" {
+			if err := s.updateDigestFrequency(ctx, userID, request.Setting.DigestFrequency); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		updateGeneralSetting = true
 		switch field {
 		case "locale":
 			generalSetting.Locale = request.Setting.Locale
@@ -526,19 +590,68 @@ func (s *APIV1Service) UpdateUserSetting(ctx context.Context, request *v1pb.Upda
 	}
 
 	// Upsert the general setting
-	if _, err := s.Store.UpsertUserSetting(ctx, &storepb.UserSetting{
-		UserId: userID,
-		Key:    storepb.UserSetting_GENERAL,
-		Value: &storepb.UserSetting_General{
-			General: generalSetting,
-		},
-	}); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+	if updateGeneralSetting {
+		if _, err := s.Store.UpsertUserSetting(ctx, &storepb.UserSetting{
+			UserId: userID,
+			Key:    storepb.UserSetting_GENERAL,
+			Value: &storepb.UserSetting_General{
+				General: generalSetting,
+			},
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+		}
 	}
 
 	return s.GetUserSetting(ctx, &v1pb.GetUserSettingRequest{Name: request.Setting.Name})
 }
 
+// updateNotificationPreferences replaces the notification preferences of the user for the given event types and channels.
+func (s *APIV1Service) updateNotificationPreferences(ctx context.Context, userID int32, notificationPreferences []*v1pb.NotificationPreference) error {
+	preferences := make([]*storepb.NotificationsUserSetting_Preference, 0, len(notificationPreferences))
+	for _, notificationPreference := range notificationPreferences {
+		validEventType := slices.Contains(notificationEventTypes, storepb.NotificationsUserSetting_EventType(notificationPreference.EventType))
+		validChannel := slices.Contains(notificationChannels, storepb.NotificationsUserSetting_Channel(notificationPreference.Channel))
+		if !validEventType || !validChannel {
+			return status.Errorf(codes.InvalidArgument, "notification preferences require a valid event type and channel")
+		}
+		preference := &storepb.NotificationsUserSetting_Preference{
+			EventType: storepb.NotificationsUserSetting_EventType(notificationPreference.EventType),
+			Channel:   storepb.NotificationsUserSetting_Channel(notificationPreference.Channel),
+			Enabled:   notificationPreference.Enabled,
+		}
+		if slices.ContainsFunc(preferences, func(existing *storepb.NotificationsUserSetting_Preference) bool {
+			return existing.EventType == preference.EventType && existing.Channel == preference.Channel
+		}) {
+			return status.Errorf(codes.InvalidArgument, "duplicate notification preference for %s through %s", notificationPreference.EventType, notificationPreference.Channel)
+		}
+		preferences = append(preferences, preference)
+	}
+	if err := s.Store.UpsertUserNotificationPreferences(ctx, userID, preferences); err != nil {
+		return status.Errorf(codes.Internal, "failed to update notification preferences: %v", err)
+	}
+	return nil
+}
+
+// updateDigestFrequency sets how often the user is emailed a digest. Digests list what happened since they were
+// turned on, rather than since the last digest sent before they were turned off.
+func (s *APIV1Service) updateDigestFrequency(ctx context.Context, userID int32, frequency v1pb.UserSetting_DigestFrequency) error {
+	if _, ok := v1pb.UserSetting_DigestFrequency_name[int32(frequency)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid digest frequency: %d", frequency)
+	}
+	digestSetting, err := s.Store.GetUserDigestSetting(ctx, userID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get digest setting: %v", err)
+	}
+	if digestSetting.Frequency == storepb.DigestUserSetting_FREQUENCY_UNSPECIFIED {
+		digestSetting.LastSentTime = timestamppb.Now()
+	}
+	digestSetting.Frequency = storepb.DigestUserSetting_Frequency(frequency)
+	if err := s.Store.UpsertUserDigestSetting(ctx, userID, digestSetting); err != nil {
+		return status.Errorf(codes.Internal, "failed to update digest setting: %v", err)
+	}
+	return nil
+}
+
 func (s *APIV1Service) ListUserAccessTokens(ctx context.Context, request *v1pb.ListUserAccessTokensRequest) (*v1pb.ListUserAccessTokensResponse, error) {
 	userID, err := ExtractUserIDFromName(request.Parent)
 	if err != nil {
@@ -563,38 +676,16 @@ func (s *APIV1Service) ListUserAccessTokens(ctx context.Context, request *v1pb.L
 
 	accessTokens := []*v1pb.UserAccessToken{}
 	for _, userAccessToken := range userAccessTokens {
-		claims := &ClaimsMessage{}
-		_, err := jwt.ParseWithClaims(userAccessToken.AccessToken, claims, func(t *jwt.Token) (any, error) {
-			if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-				return nil, errors.Errorf("unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
-			}
-			if kid, ok := t.Header["kid"].(string); ok {
-				if kid == "v1" {
-					return []byte(s.Secret), nil
-				}
-			}
-			return nil, errors.Errorf("unexpected access token kid=%v", t.Header["kid"])
-		})
-		if err != nil {
-			// If the access token is invalid or expired, just ignore it.
+		// Expired access tokens are no longer usable, so just ignore them.
+		if userAccessToken.ExpireTime != nil && userAccessToken.ExpireTime.AsTime().Before(time.Now()) {
 			continue
 		}
-
-		accessTokenResponse := &v1pb.UserAccessToken{
-			Name:        fmt.Sprintf("users/%d/accessTokens/%s", userID, userAccessToken.AccessToken),
-			AccessToken: userAccessToken.AccessToken,
-			Description: userAccessToken.Description,
-			IssuedAt:    timestamppb.New(claims.IssuedAt.Time),
-		}
-		if claims.ExpiresAt != nil {
-			accessTokenResponse.ExpiresAt = timestamppb.New(claims.ExpiresAt.Time)
-		}
-		accessTokens = append(accessTokens, accessTokenResponse)
+		accessTokens = append(accessTokens, convertUserAccessTokenFromStore(userID, userAccessToken))
 	}
 
 	// Sort by issued time in descending order.
 	slices.SortFunc(accessTokens, func(i, j *v1pb.UserAccessToken) int {
-		return int(i.IssuedAt.Seconds - j.IssuedAt.Seconds)
+		return j.IssuedAt.AsTime().Compare(i.IssuedAt.AsTime())
 	})
 	response := &v1pb.ListUserAccessTokensResponse{
 		AccessTokens: accessTokens,
@@ -618,52 +709,50 @@ func (s *APIV1Service) CreateUserAccessToken(ctx context.Context, request *v1pb.
 		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 	}
 
+	scopes, err := normalizeAccessTokenScopes(request.AccessToken.Scopes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid scopes: %v", err)
+	}
 	expiresAt := time.Time{}
 	if request.AccessToken.ExpiresAt != nil {
 		expiresAt = request.AccessToken.ExpiresAt.AsTime()
+		if !expiresAt.After(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, "expiration time must be in the future")
+		}
 	}
 
-	accessToken, err := GenerateAccessToken(currentUser.Username, currentUser.ID, expiresAt, []byte(s.Secret))
+	accessTokenID, err := util.RandomString(16)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate access token: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate access token id: %v", err)
 	}
-
-	claims := &ClaimsMessage{}
-	_, err = jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (any, error) {
-		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-			return nil, errors.Errorf("unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
-		}
-		if kid, ok := t.Header["kid"].(string); ok {
-			if kid == "v1" {
-				return []byte(s.Secret), nil
-			}
-		}
-		return nil, errors.Errorf("unexpected access token kid=%v", t.Header["kid"])
-	})
+	accessToken, err := s.signJWT(ctx, newTokenClaims(currentUser.Username, currentUser.ID, AccessTokenAudienceName, accessTokenID, expiresAt))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to parse access token: %v", err)
-	}
-
-	// Upsert the access token to user setting store.
-	if err := s.UpsertAccessTokenToStore(ctx, currentUser, accessToken, request.AccessToken.Description); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to upsert access token to store: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate access token: %v", err)
 	}
 
-	userAccessToken := &v1pb.UserAccessToken{
-		Name:        fmt.Sprintf("users/%d/accessTokens/%s", userID, accessToken),
-		AccessToken: accessToken,
+	// Only the hash of the access token is stored, so it is returned in plain text only once.
+	storedAccessToken := &storepb.AccessTokensUserSetting_AccessToken{
+		Id:          accessTokenID,
+		TokenHash:   store.HashAccessToken(accessToken),
 		Description: request.AccessToken.Description,
-		IssuedAt:    timestamppb.New(claims.IssuedAt.Time),
+		Scopes:      scopes,
+		IssuedTime:  timestamppb.Now(),
+	}
+	if !expiresAt.IsZero() {
+		storedAccessToken.ExpireTime = timestamppb.New(expiresAt)
 	}
-	if claims.ExpiresAt != nil {
-		userAccessToken.ExpiresAt = timestamppb.New(claims.ExpiresAt.Time)
+	if err := s.Store.UpsertUserAccessToken(ctx, currentUser.ID, storedAccessToken); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to upsert access token to store: %v", err)
 	}
+
+	userAccessToken := convertUserAccessTokenFromStore(userID, storedAccessToken)
+	userAccessToken.AccessToken = accessToken
 	return userAccessToken, nil
 }
 
 func (s *APIV1Service) DeleteUserAccessToken(ctx context.Context, request *v1pb.DeleteUserAccessTokenRequest) (*emptypb.Empty, error) {
 	// Extract user ID from the access token resource name
-	// Format: users/{user}/accessTokens/{access_token}
+	// Format: users/{user}/accessTokens/{access_token_id}
 	parts := strings.Split(request.Name, "/")
 	if len(parts) != 4 || parts[0] != "users" || parts[2] != "accessTokens" {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid access token name format: %s", request.Name)
@@ -673,7 +762,7 @@ func (s *APIV1Service) DeleteUserAccessToken(ctx context.Context, request *v1pb.
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid user name: %v", err)
 	}
-	accessTokenToDelete := parts[3]
+	accessTokenIDToDelete := parts[3]
 
 	currentUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
@@ -686,27 +775,8 @@ func (s *APIV1Service) DeleteUserAccessToken(ctx context.Context, request *v1pb.
 		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 	}
 
-	userAccessTokens, err := s.Store.GetUserAccessTokens(ctx, currentUser.ID)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list access tokens: %v", err)
-	}
-	updatedUserAccessTokens := []*storepb.AccessTokensUserSetting_AccessToken{}
-	for _, userAccessToken := range userAccessTokens {
-		if userAccessToken.AccessToken == accessTokenToDelete {
-			continue
-		}
-		updatedUserAccessTokens = append(updatedUserAccessTokens, userAccessToken)
-	}
-	if _, err := s.Store.UpsertUserSetting(ctx, &storepb.UserSetting{
-		UserId: currentUser.ID,
-		Key:    storepb.UserSetting_ACCESS_TOKENS,
-		Value: &storepb.UserSetting_AccessTokens{
-			AccessTokens: &storepb.AccessTokensUserSetting{
-				AccessTokens: updatedUserAccessTokens,
-			},
-		},
-	}); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+	if err := s.Store.RemoveUserAccessToken(ctx, currentUser.ID, accessTokenIDToDelete); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove access token: %v", err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -729,18 +799,26 @@ func (s *APIV1Service) ListUserSessions(ctx context.Context, request *v1pb.ListU
 		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 	}
 
-	userSessions, err := s.Store.GetUserSessions(ctx, userID)
+	userSessions, err := s.Store.ListUserSessions(ctx, &store.FindUserSession{
+		UserID: &userID,
+	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list sessions: %v", err)
 	}
 
+	// Sessions are listed by last accessed time in descending order.
 	sessions := []*v1pb.UserSession{}
 	for _, userSession := range userSessions {
+		// Expired sessions are no longer usable, so just ignore them until they are purged.
+		if !validateUserSession(userSession) {
+			continue
+		}
 		sessionResponse := &v1pb.UserSession{
-			Name:             fmt.Sprintf("users/%d/sessions/%s", userID, userSession.SessionId),
-			SessionId:        userSession.SessionId,
-			CreateTime:       userSession.CreateTime,
-			LastAccessedTime: userSession.LastAccessedTime,
+			Name:             fmt.Sprintf("users/%d/sessions/%s", userID, userSession.ID),
+			SessionId:        userSession.ID,
+			CreateTime:       timestamppb.New(time.Unix(userSession.CreatedTs, 0)),
+			LastAccessedTime: timestamppb.New(time.Unix(userSession.LastAccessedTs, 0)),
+			ExpireTime:       timestamppb.New(getUserSessionExpireTime(userSession)),
 		}
 
 		if userSession.ClientInfo != nil {
@@ -756,11 +834,6 @@ func (s *APIV1Service) ListUserSessions(ctx context.Context, request *v1pb.ListU
 		sessions = append(sessions, sessionResponse)
 	}
 
-	// Sort by last accessed time in descending order.
-	slices.SortFunc(sessions, func(i, j *v1pb.UserSession) int {
-		return int(j.LastAccessedTime.Seconds - i.LastAccessedTime.Seconds)
-	})
-
 	response := &v1pb.ListUserSessionsResponse{
 		Sessions: sessions,
 	}
@@ -792,63 +865,55 @@ func (s *APIV1Service) RevokeUserSession(ctx context.Context, request *v1pb.Revo
 		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 	}
 
-	if err := s.Store.RemoveUserSession(ctx, userID, sessionIDToRevoke); err != nil {
+	if _, err := s.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{
+		ID:     &sessionIDToRevoke,
+		UserID: &userID,
+	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
-// UpsertUserSession adds or updates a user session.
-func (s *APIV1Service) UpsertUserSession(ctx context.Context, userID int32, sessionID string, clientInfo *storepb.SessionsUserSetting_ClientInfo) error {
-	session := &storepb.SessionsUserSetting_Session{
-		SessionId:        sessionID,
-		CreateTime:       timestamppb.Now(),
-		LastAccessedTime: timestamppb.Now(),
-		ClientInfo:       clientInfo,
+func (s *APIV1Service) RevokeAllUserSessions(ctx context.Context, request *v1pb.RevokeAllUserSessionsRequest) (*emptypb.Empty, error) {
+	userID, err := ExtractUserIDFromName(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user name: %v", err)
 	}
 
-	return s.Store.AddUserSession(ctx, userID, session)
-}
-
-func (s *APIV1Service) UpsertAccessTokenToStore(ctx context.Context, user *store.User, accessToken, description string) error {
-	userAccessTokens, err := s.Store.GetUserAccessTokens(ctx, user.ID)
+	currentUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to get user access tokens")
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
 	}
-	userAccessToken := storepb.AccessTokensUserSetting_AccessToken{
-		AccessToken: accessToken,
-		Description: description,
+	if currentUser == nil {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if currentUser.ID != userID && currentUser.Role != store.RoleHost && currentUser.Role != store.RoleAdmin {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 	}
-	userAccessTokens = append(userAccessTokens, &userAccessToken)
 
-	if _, err := s.Store.UpsertUserSetting(ctx, &storepb.UserSetting{
-		UserId: user.ID,
-		Key:    storepb.UserSetting_ACCESS_TOKENS,
-		Value: &storepb.UserSetting_AccessTokens{
-			AccessTokens: &storepb.AccessTokensUserSetting{
-				AccessTokens: userAccessTokens,
-			},
-		},
+	if _, err := s.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{
+		UserID: &userID,
 	}); err != nil {
-		return errors.Wrap(err, "failed to upsert user setting")
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %v", err)
 	}
-	return nil
+
+	return &emptypb.Empty{}, nil
 }
 
 func convertUserFromStore(user *store.User) *v1pb.User {
 	userpb := &v1pb.User{
-		Name:        fmt.Sprintf("%s%d", UserNamePrefix, user.ID),
-		State:       convertStateFromStore(user.RowStatus),
-		CreateTime:  timestamppb.New(time.Unix(user.CreatedTs, 0)),
-		UpdateTime:  timestamppb.New(time.Unix(user.UpdatedTs, 0)),
-		Role:        convertUserRoleFromStore(user.Role),
-		Username:    user.Username,
-		Email:       user.Email,
-		DisplayName: user.Nickname,
-		AvatarUrl:   user.AvatarURL,
+		Name:           fmt.Sprintf("%s%d", UserNamePrefix, user.ID),
+		State:          convertStateFromStore(user.RowStatus),
+		CreateTime:     timestamppb.New(time.Unix(user.CreatedTs, 0)),
+		UpdateTime:     timestamppb.New(time.Unix(user.UpdatedTs, 0)),
+		Role:           convertUserRoleFromStore(user.Role),
+		Username:       user.Username,
+		Email:          user.Email,
+		DisplayName:    user.Nickname,
+		AvatarUrl:      user.AvatarURL,
 		MemoVisibility: user.Value,
-		Description: user.Description,
+		Description:    user.Description,
 	}
 	// Use the avatar URL instead of raw base64 image data to reduce the response size.
 	if user.AvatarURL != "" {
@@ -918,4 +983,32 @@ func ExtractUserIDAndSettingKeyFromName(name string) (int32, string, error) {
 
 	settingKey := parts[3]
 	return userID, settingKey, nil
-}
\ No newline at end of file
+}
+
+func convertUserAccessTokenFromStore(userID int32, accessToken *storepb.AccessTokensUserSetting_AccessToken) *v1pb.UserAccessToken {
+	return &v1pb.UserAccessToken{
+		Name:        fmt.Sprintf("users/%d/accessTokens/%s", userID, accessToken.Id),
+		Description: accessToken.Description,
+		IssuedAt:    accessToken.IssuedTime,
+		ExpiresAt:   accessToken.ExpireTime,
+		Scopes:      accessToken.Scopes,
+		LastUsedAt:  accessToken.LastUsedTime,
+	}
+}
+
+// normalizeAccessTokenScopes validates the requested scopes, defaulting to read access to memos.
+func normalizeAccessTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{store.AccessTokenScopeMemosRead}, nil
+	}
+	normalized := []string{}
+	for _, scope := range scopes {
+		if !isValidAccessTokenScope(scope) {
+			return nil, errors.Errorf("unknown scope %q", scope)
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
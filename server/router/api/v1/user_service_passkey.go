@@ -0,0 +1,460 @@
+package v1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/imrany/wekalist/internal/util"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// PasskeyRegistrationAudienceName is the audience name of the passkey registration ceremony token.
+	PasskeyRegistrationAudienceName = "user.passkey-registration"
+	// PasskeyAssertionAudienceName is the audience name of the passkey assertion ceremony token.
+	PasskeyAssertionAudienceName = "user.passkey-assertion"
+	// passkeyCeremonyDuration is how long the browser has to complete a passkey ceremony.
+	passkeyCeremonyDuration = 5 * time.Minute
+	// passkeyAssertionOTPPurpose is the OTP purpose under which the assertion challenges are stored until used.
+	passkeyAssertionOTPPurpose OtpPurpose = "passkey_assertion"
+	// passkeyRelyingPartyName is shown by authenticators when the workspace has no custom title.
+	passkeyRelyingPartyName = "Wekalist"
+	// defaultPasskeyDisplayName is used when a passkey is registered without a name.
+	defaultPasskeyDisplayName = "Passkey"
+)
+
+// passkeyCeremonyClaims carries the WebAuthn session data between the begin and finish steps of a ceremony.
+// The assertion challenges are also stored server-side, so that an assertion ceremony can be finished only once.
+type passkeyCeremonyClaims struct {
+	Session webauthn.SessionData `json:"session"`
+	jwt.RegisteredClaims
+}
+
+// webAuthnUser adapts a user and their stored passkeys to webauthn.User.
+type webAuthnUser struct {
+	user     *store.User
+	passkeys []*storepb.PasskeysUserSetting_Passkey
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.Itoa(int(u.user.ID)))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.user.Nickname != "" {
+		return u.user.Nickname
+	}
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		id, err := base64.RawURLEncoding.DecodeString(passkey.Id)
+		if err != nil {
+			continue
+		}
+		transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+		for _, transport := range passkey.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              id,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transport:       transports,
+			Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(passkey.Flags)),
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    passkey.Aaguid,
+				SignCount: passkey.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+// ListUserPasskeys returns the passkeys registered by a user.
+func (s *APIV1Service) ListUserPasskeys(ctx context.Context, request *v1pb.ListUserPasskeysRequest) (*v1pb.ListUserPasskeysResponse, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Parent, true)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list passkeys: %v", err)
+	}
+
+	response := &v1pb.ListUserPasskeysResponse{
+		Passkeys: []*v1pb.UserPasskey{},
+	}
+	for _, passkey := range passkeys {
+		response.Passkeys = append(response.Passkeys, convertUserPasskeyFromStore(user.ID, passkey))
+	}
+	return response, nil
+}
+
+// BeginPasskeyRegistration returns the options to pass to navigator.credentials.create.
+func (s *APIV1Service) BeginPasskeyRegistration(ctx context.Context, request *v1pb.BeginPasskeyRegistrationRequest) (*v1pb.BeginPasskeyRegistrationResponse, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Parent, false)
+	if err != nil {
+		return nil, err
+	}
+	webAuthn, err := s.getWebAuthn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list passkeys: %v", err)
+	}
+
+	waUser := &webAuthnUser{user: user, passkeys: passkeys}
+	creation, session, err := webAuthn.BeginRegistration(waUser,
+		webauthn.WithExclusions(webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin passkey registration: %v", err)
+	}
+	optionsJSON, err := json.Marshal(creation.Response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal passkey options: %v", err)
+	}
+	ceremonyToken, err := s.generatePasskeyCeremonyToken(ctx, PasskeyRegistrationAudienceName, "", fmt.Sprint(user.ID), session)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate ceremony token: %v", err)
+	}
+	return &v1pb.BeginPasskeyRegistrationResponse{
+		OptionsJson:   string(optionsJSON),
+		CeremonyToken: ceremonyToken,
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the attestation returned by the authenticator and stores the passkey.
+func (s *APIV1Service) FinishPasskeyRegistration(ctx context.Context, request *v1pb.FinishPasskeyRegistrationRequest) (*v1pb.UserPasskey, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Parent, false)
+	if err != nil {
+		return nil, err
+	}
+	webAuthn, err := s.getWebAuthn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.parsePasskeyCeremonyToken(ctx, PasskeyRegistrationAudienceName, request.CeremonyToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject != fmt.Sprint(user.ID) {
+		return nil, status.Errorf(codes.InvalidArgument, "ceremony token was issued for another user")
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes([]byte(request.CredentialJson))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid passkey credential: %v", err)
+	}
+	passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list passkeys: %v", err)
+	}
+	credential, err := webAuthn.CreateCredential(&webAuthnUser{user: user, passkeys: passkeys}, claims.Session, parsed)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to verify passkey credential: %v", err)
+	}
+
+	passkeyID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	for _, passkey := range passkeys {
+		if passkey.Id == passkeyID {
+			return nil, status.Errorf(codes.AlreadyExists, "passkey is already registered")
+		}
+	}
+	displayName := strings.TrimSpace(request.DisplayName)
+	if displayName == "" {
+		displayName = defaultPasskeyDisplayName
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	passkey := &storepb.PasskeysUserSetting_Passkey{
+		Id:              passkeyID,
+		DisplayName:     displayName,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		Flags:           uint32(credential.Flags.ProtocolValue()),
+		Aaguid:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		CreateTime:      timestamppb.Now(),
+	}
+	if err := s.Store.UpsertUserPasskey(ctx, user.ID, passkey); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store passkey: %v", err)
+	}
+	return convertUserPasskeyFromStore(user.ID, passkey), nil
+}
+
+// DeleteUserPasskey revokes a passkey. Admins can revoke the passkeys of other users.
+func (s *APIV1Service) DeleteUserPasskey(ctx context.Context, request *v1pb.DeleteUserPasskeyRequest) (*emptypb.Empty, error) {
+	// Format: users/{user}/passkeys/{passkey}
+	parts := strings.Split(request.Name, "/")
+	if len(parts) != 4 || parts[0] != "users" || parts[2] != "passkeys" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid passkey name format: %s", request.Name)
+	}
+	user, err := s.getTwoFactorTargetUser(ctx, strings.Join(parts[:2], "/"), true)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list passkeys: %v", err)
+	}
+	found := false
+	for _, passkey := range passkeys {
+		if passkey.Id == parts[3] {
+			found = true
+		}
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "passkey not found")
+	}
+	if err := s.Store.RemoveUserPasskey(ctx, user.ID, parts[3]); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete passkey: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// BeginPasskeyAssertion returns the options to pass to navigator.credentials.get.
+// Without a username, or for unknown users, any discoverable passkey of the workspace is accepted.
+func (s *APIV1Service) BeginPasskeyAssertion(ctx context.Context, request *v1pb.BeginPasskeyAssertionRequest) (*v1pb.BeginPasskeyAssertionResponse, error) {
+	webAuthn, err := s.getWebAuthn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var waUser *webAuthnUser
+	if username := strings.TrimSpace(request.Username); username != "" {
+		user, err := s.Store.GetUser(ctx, &store.FindUser{Username: &username})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+		}
+		if user != nil {
+			passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to list passkeys: %v", err)
+			}
+			if len(passkeys) > 0 {
+				waUser = &webAuthnUser{user: user, passkeys: passkeys}
+			}
+		}
+	}
+
+	var assertion *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	if waUser != nil {
+		assertion, session, err = webAuthn.BeginLogin(waUser, webauthn.WithUserVerification(protocol.VerificationRequired))
+	} else {
+		assertion, session, err = webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin passkey assertion: %v", err)
+	}
+	optionsJSON, err := json.Marshal(assertion.Response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal passkey options: %v", err)
+	}
+	ceremonyID := util.GenUUID()
+	if err := s.storePasskeyChallenge(ctx, ceremonyID, session); err != nil {
+		return nil, err
+	}
+	ceremonyToken, err := s.generatePasskeyCeremonyToken(ctx, PasskeyAssertionAudienceName, ceremonyID, "", session)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate ceremony token: %v", err)
+	}
+	return &v1pb.BeginPasskeyAssertionResponse{
+		OptionsJson:   string(optionsJSON),
+		CeremonyToken: ceremonyToken,
+	}, nil
+}
+
+// validatePasskeyAssertion verifies a passkey assertion and returns the signed in user.
+// The sign count and last used time of the passkey are updated on success.
+func (s *APIV1Service) validatePasskeyAssertion(ctx context.Context, credentials *v1pb.CreateSessionRequest_PasskeyCredentials) (*store.User, error) {
+	webAuthn, err := s.getWebAuthn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.parsePasskeyCeremonyToken(ctx, PasskeyAssertionAudienceName, credentials.CeremonyToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.consumePasskeyChallenge(ctx, claims); err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes([]byte(credentials.CredentialJson))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid passkey credential: %v", err)
+	}
+
+	loadUser := func(userHandle []byte) (*webAuthnUser, error) {
+		userID, err := util.ConvertStringToInt32(string(userHandle))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid user handle")
+		}
+		user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors.New("user not found")
+		}
+		passkeys, err := s.Store.GetUserPasskeys(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &webAuthnUser{user: user, passkeys: passkeys}, nil
+	}
+
+	var waUser *webAuthnUser
+	var credential *webauthn.Credential
+	if len(claims.Session.UserID) > 0 {
+		waUser, err = loadUser(claims.Session.UserID)
+		if err == nil {
+			credential, err = webAuthn.ValidateLogin(waUser, claims.Session, parsed)
+		}
+	} else {
+		var user webauthn.User
+		user, credential, err = webAuthn.ValidatePasskeyLogin(func(_, userHandle []byte) (webauthn.User, error) {
+			return loadUser(userHandle)
+		}, claims.Session, parsed)
+		if err == nil {
+			waUser = user.(*webAuthnUser)
+		}
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to verify passkey")
+	}
+	if credential.Authenticator.CloneWarning {
+		return nil, status.Errorf(codes.Unauthenticated, "passkey sign count is out of sync, the authenticator may have been cloned")
+	}
+
+	passkeyID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	for _, passkey := range waUser.passkeys {
+		if passkey.Id != passkeyID {
+			continue
+		}
+		// Don't modify the cached setting.
+		passkey = proto.Clone(passkey).(*storepb.PasskeysUserSetting_Passkey)
+		passkey.SignCount = credential.Authenticator.SignCount
+		passkey.LastUsedTime = timestamppb.Now()
+		if err := s.Store.UpsertUserPasskey(ctx, waUser.user.ID, passkey); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update passkey: %v", err)
+		}
+	}
+	return waUser.user, nil
+}
+
+// getWebAuthn returns the WebAuthn relying party of the instance, identified by the instance URL.
+func (s *APIV1Service) getWebAuthn(ctx context.Context) (*webauthn.WebAuthn, error) {
+	if s.Profile == nil || s.Profile.InstanceURL == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "instance URL is required for passkeys")
+	}
+	instanceURL, err := url.Parse(s.Profile.InstanceURL)
+	if err != nil || instanceURL.Hostname() == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid instance URL: %s", s.Profile.InstanceURL)
+	}
+
+	displayName := passkeyRelyingPartyName
+	workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace general setting: %v", err)
+	}
+	if title := workspaceGeneralSetting.GetCustomProfile().GetTitle(); title != "" {
+		displayName = title
+	}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          instanceURL.Hostname(),
+		RPDisplayName: displayName,
+		RPOrigins:     []string{instanceURL.Scheme + "://" + instanceURL.Host},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to configure passkeys: %v", err)
+	}
+	return webAuthn, nil
+}
+
+func (s *APIV1Service) generatePasskeyCeremonyToken(ctx context.Context, audience, id, subject string, session *webauthn.SessionData) (string, error) {
+	claims := &passkeyCeremonyClaims{
+		Session: *session,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    Issuer,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(passkeyCeremonyDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return s.signJWT(ctx, claims)
+}
+
+func (s *APIV1Service) parsePasskeyCeremonyToken(ctx context.Context, audience, ceremonyToken string) (*passkeyCeremonyClaims, error) {
+	claims := &passkeyCeremonyClaims{}
+	if err := s.parseJWT(ctx, ceremonyToken, claims, jwt.WithAudience(audience), jwt.WithExpirationRequired()); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired ceremony token")
+	}
+	return claims, nil
+}
+
+// storePasskeyChallenge stores the challenge of an assertion ceremony as a one-time code of the ceremony,
+// and prunes the challenges of the ceremonies never finished.
+func (s *APIV1Service) storePasskeyChallenge(ctx context.Context, ceremonyID string, session *webauthn.SessionData) error {
+	if err := s.storeOneTimeCode(ctx, passkeyAssertionOTPPurpose, ceremonyID, session.Challenge, passkeyCeremonyDuration); err != nil {
+		return status.Errorf(codes.Internal, "failed to store passkey challenge: %v", err)
+	}
+	return nil
+}
+
+// consumePasskeyChallenge deletes the stored challenge of the assertion ceremony.
+// Only the first caller succeeds, so that a ceremony token cannot be replayed.
+func (s *APIV1Service) consumePasskeyChallenge(ctx context.Context, claims *passkeyCeremonyClaims) error {
+	redeemed, err := s.redeemOneTimeCode(ctx, passkeyAssertionOTPPurpose, claims.ID, claims.Session.Challenge)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to consume passkey challenge: %v", err)
+	}
+	if !redeemed {
+		return status.Errorf(codes.Unauthenticated, "invalid or expired ceremony token")
+	}
+	return nil
+}
+
+func convertUserPasskeyFromStore(userID int32, passkey *storepb.PasskeysUserSetting_Passkey) *v1pb.UserPasskey {
+	return &v1pb.UserPasskey{
+		Name:         fmt.Sprintf("users/%d/passkeys/%s", userID, passkey.Id),
+		DisplayName:  passkey.DisplayName,
+		CreateTime:   passkey.CreateTime,
+		LastUsedTime: passkey.LastUsedTime,
+	}
+}
@@ -0,0 +1,234 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/imrany/wekalist/internal/util"
+	"github.com/imrany/wekalist/plugin/totp"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// TwoFactorChallengeAudienceName is the audience name of the two-factor challenge token.
+	TwoFactorChallengeAudienceName = "user.two-factor-challenge"
+	// twoFactorChallengeDuration is how long a user has to enter the second factor after a password sign-in.
+	twoFactorChallengeDuration = 5 * time.Minute
+	// twoFactorIssuer is the issuer shown in authenticator apps when the workspace has no custom title.
+	twoFactorIssuer = "Wekalist"
+
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+// SetupUserTwoFactor generates a new TOTP secret and recovery codes for the current user.
+// Two-factor authentication is only enabled once the setup is confirmed with EnableUserTwoFactor.
+func (s *APIV1Service) SetupUserTwoFactor(ctx context.Context, request *v1pb.SetupUserTwoFactorRequest) (*v1pb.SetupUserTwoFactorResponse, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.Store.GetUserTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor setting: %v", err)
+	}
+	if twoFactor.GetEnabled() {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate two-factor secret: %v", err)
+	}
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodeHashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := util.RandomString(recoveryCodeLength)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate recovery code: %v", err)
+		}
+		code = strings.ToLower(code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:])
+		recoveryCodes = append(recoveryCodes, code)
+		recoveryCodeHashes = append(recoveryCodeHashes, hashRecoveryCode(code))
+	}
+	if err := s.Store.UpsertUserTwoFactor(ctx, user.ID, &storepb.TwoFactorUserSetting{
+		TotpSecret:         secret,
+		RecoveryCodeHashes: recoveryCodeHashes,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update two-factor setting: %v", err)
+	}
+
+	issuer := twoFactorIssuer
+	workspaceGeneralSetting, err := s.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get workspace general setting: %v", err)
+	}
+	if title := workspaceGeneralSetting.GetCustomProfile().GetTitle(); title != "" {
+		issuer = title
+	}
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+	return &v1pb.SetupUserTwoFactorResponse{
+		Secret:          secret,
+		ProvisioningUri: totp.ProvisioningURI(issuer, account, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+// EnableUserTwoFactor enables two-factor authentication once the user proves the authenticator app is set up.
+func (s *APIV1Service) EnableUserTwoFactor(ctx context.Context, request *v1pb.EnableUserTwoFactorRequest) (*emptypb.Empty, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.Store.GetUserTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor setting: %v", err)
+	}
+	if twoFactor.GetTotpSecret() == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not set up")
+	}
+	if twoFactor.GetEnabled() {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+	step, ok := totp.Validate(twoFactor.TotpSecret, request.Code, time.Now())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid two-factor code")
+	}
+
+	twoFactor = proto.Clone(twoFactor).(*storepb.TwoFactorUserSetting)
+	twoFactor.Enabled = true
+	twoFactor.LastUsedStep = step
+	if err := s.Store.UpsertUserTwoFactor(ctx, user.ID, twoFactor); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update two-factor setting: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DisableUserTwoFactor disables two-factor authentication of a user.
+// Users need a valid code, while admins can disable it for other users who lost their device.
+func (s *APIV1Service) DisableUserTwoFactor(ctx context.Context, request *v1pb.DisableUserTwoFactorRequest) (*emptypb.Empty, error) {
+	user, err := s.getTwoFactorTargetUser(ctx, request.Name, true)
+	if err != nil {
+		return nil, err
+	}
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser.ID == user.ID {
+		twoFactor, err := s.Store.GetUserTwoFactor(ctx, user.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get two-factor setting: %v", err)
+		}
+		if twoFactor.GetEnabled() {
+			if err := s.validateTwoFactorCode(ctx, user.ID, twoFactor, request.Code); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.Store.UpsertUserTwoFactor(ctx, user.ID, &storepb.TwoFactorUserSetting{}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update two-factor setting: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// getTwoFactorTargetUser returns the user named in a two-factor request after checking that the
+// current user may manage it. Admins may only manage other users when allowAdmin is set.
+func (s *APIV1Service) getTwoFactorTargetUser(ctx context.Context, name string, allowAdmin bool) (*store.User, error) {
+	userID, err := ExtractUserIDFromName(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user name: %v", err)
+	}
+	currentUser, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if currentUser == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	if currentUser.ID != userID && !(allowAdmin && isSuperUser(currentUser)) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	return user, nil
+}
+
+// validateTwoFactorCode checks a TOTP code or a recovery code of the user.
+// Accepted TOTP codes can't be replayed and recovery codes are consumed.
+func (s *APIV1Service) validateTwoFactorCode(ctx context.Context, userID int32, twoFactor *storepb.TwoFactorUserSetting, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return status.Errorf(codes.InvalidArgument, "two-factor code is required")
+	}
+	// Don't modify the cached setting.
+	twoFactor = proto.Clone(twoFactor).(*storepb.TwoFactorUserSetting)
+
+	if step, ok := totp.Validate(twoFactor.TotpSecret, code, time.Now()); ok {
+		if step <= twoFactor.LastUsedStep {
+			return status.Errorf(codes.InvalidArgument, "two-factor code has already been used")
+		}
+		twoFactor.LastUsedStep = step
+	} else {
+		codeHash := hashRecoveryCode(code)
+		index := -1
+		for i, recoveryCodeHash := range twoFactor.RecoveryCodeHashes {
+			if subtle.ConstantTimeCompare([]byte(recoveryCodeHash), []byte(codeHash)) == 1 {
+				index = i
+			}
+		}
+		if index < 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid two-factor code")
+		}
+		twoFactor.RecoveryCodeHashes = append(twoFactor.RecoveryCodeHashes[:index], twoFactor.RecoveryCodeHashes[index+1:]...)
+	}
+
+	if err := s.Store.UpsertUserTwoFactor(ctx, userID, twoFactor); err != nil {
+		return status.Errorf(codes.Internal, "failed to update two-factor setting: %v", err)
+	}
+	return nil
+}
+
+// generateTwoFactorChallenge returns a short-lived token proving that the user passed the first sign-in factor.
+func (s *APIV1Service) generateTwoFactorChallenge(ctx context.Context, user *store.User) (string, error) {
+	return s.signJWT(ctx, newTokenClaims(user.Username, user.ID, TwoFactorChallengeAudienceName, "", time.Now().Add(twoFactorChallengeDuration)))
+}
+
+// parseTwoFactorChallenge returns the ID of the user a two-factor challenge token was issued for.
+func (s *APIV1Service) parseTwoFactorChallenge(ctx context.Context, challenge string) (int32, error) {
+	claims := &ClaimsMessage{}
+	if err := s.parseJWT(ctx, challenge, claims, jwt.WithAudience(TwoFactorChallengeAudienceName), jwt.WithExpirationRequired()); err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "invalid or expired two-factor challenge")
+	}
+	userID, err := util.ConvertStringToInt32(claims.Subject)
+	if err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "malformed two-factor challenge")
+	}
+	return userID, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
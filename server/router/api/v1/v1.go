@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/imrany/wekalist/internal/profile"
+	"github.com/imrany/wekalist/plugin/webpush"
 	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
 	"github.com/imrany/wekalist/store"
 )
@@ -39,6 +40,12 @@ type APIV1Service struct {
 	Secret  string
 	Profile *profile.Profile
 	Store   *store.Store
+	// PushDispatcher delivers the push notifications once started with StartPushDispatcher.
+	PushDispatcher *webpush.Dispatcher
+	// NotificationEmails sends the batched notification emails once started.
+	NotificationEmails *NotificationEmailBatcher
+	// MailQueue sends the queued emails once started.
+	MailQueue *MailQueue
 
 	grpcServer *grpc.Server
 }
@@ -46,11 +53,18 @@ type APIV1Service struct {
 func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store, grpcServer *grpc.Server) *APIV1Service {
 	grpc.EnableTracing = true
 	apiv1Service := &APIV1Service{
-		Secret:     secret,
-		Profile:    profile,
-		Store:      store,
+		Secret:  secret,
+		Profile: profile,
+		Store:   store,
+		PushDispatcher: webpush.NewDispatcher(webpush.Config{
+			VAPIDPublicKey:  profile.WebPushConfig.VAPIDPublicKey,
+			VAPIDPrivateKey: profile.WebPushConfig.VAPIDPrivateKey,
+			Subscriber:      profile.InstanceURL,
+		}),
 		grpcServer: grpcServer,
 	}
+	apiv1Service.NotificationEmails = NewNotificationEmailBatcher(notificationEmailWindow, apiv1Service.sendNotificationEmail)
+	apiv1Service.MailQueue = NewMailQueue(store, apiv1Service.getMailConfig)
 	grpc_health_v1.RegisterHealthServer(grpcServer, apiv1Service)
 	v1pb.RegisterWorkspaceServiceServer(grpcServer, apiv1Service)
 	v1pb.RegisterAuthServiceServer(grpcServer, apiv1Service)
@@ -73,7 +87,10 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 func (s *APIV1Service) RegisterGateway(ctx context.Context, echoServer *echo.Echo) error {
 	// Register SEO routes FIRST (before gRPC gateway)
 	s.registerSEORoutes(echoServer)
-	
+	s.registerAIRoutes(echoServer)
+	s.registerSAMLRoutes(echoServer)
+	s.registerNotificationRoutes(echoServer)
+
 	var target string
 	if len(s.Profile.UNIXSock) == 0 {
 		addr := s.Profile.Addr
@@ -139,6 +156,8 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, echoServer *echo.Ech
 
 	gwGroup.Any("/api/v1/*", handler)
 	gwGroup.Any("/file/*", handler)
+	// The subscription service is served under /v1, for service workers subscribing again.
+	gwGroup.Any("/v1/*", handler)
 
 	// GRPC web proxy.
 	options := []grpcweb.Option{
@@ -16,9 +16,10 @@ import (
 // GetWorkspaceProfile returns the workspace profile.
 func (s *APIV1Service) GetWorkspaceProfile(ctx context.Context, _ *v1pb.GetWorkspaceProfileRequest) (*v1pb.WorkspaceProfile, error) {
 	workspaceProfile := &v1pb.WorkspaceProfile{
-		Version:     s.Profile.Version,
-		Mode:        s.Profile.Mode,
-		InstanceUrl: s.Profile.InstanceURL,
+		Version:        s.Profile.Version,
+		Mode:           s.Profile.Mode,
+		InstanceUrl:    s.Profile.InstanceURL,
+		VapidPublicKey: s.Profile.WebPushConfig.VAPIDPublicKey,
 	}
 	owner, err := s.GetInstanceOwner(ctx)
 	if err != nil {
@@ -39,16 +40,20 @@ func (s *APIV1Service) GetWorkspaceSetting(ctx context.Context, request *v1pb.Ge
 	workspaceSettingKey := storepb.WorkspaceSettingKey(storepb.WorkspaceSettingKey_value[workspaceSettingKeyString])
 	// Get workspace setting from store with default value.
 	switch workspaceSettingKey {
-		case storepb.WorkspaceSettingKey_BASIC:
-			_, err = s.Store.GetWorkspaceBasicSetting(ctx)
-		case storepb.WorkspaceSettingKey_GENERAL:
-			_, err = s.Store.GetWorkspaceGeneralSetting(ctx)
-		case storepb.WorkspaceSettingKey_MEMO_RELATED:
-			_, err = s.Store.GetWorkspaceMemoRelatedSetting(ctx)
-		case storepb.WorkspaceSettingKey_STORAGE:
-			_, err = s.Store.GetWorkspaceStorageSetting(ctx)
-		default:
-			return nil, status.Errorf(codes.InvalidArgument, "unsupported workspace setting key: %v", workspaceSettingKey)
+	case storepb.WorkspaceSettingKey_BASIC:
+		_, err = s.Store.GetWorkspaceBasicSetting(ctx)
+	case storepb.WorkspaceSettingKey_GENERAL:
+		_, err = s.Store.GetWorkspaceGeneralSetting(ctx)
+	case storepb.WorkspaceSettingKey_MEMO_RELATED:
+		_, err = s.Store.GetWorkspaceMemoRelatedSetting(ctx)
+	case storepb.WorkspaceSettingKey_STORAGE:
+		_, err = s.Store.GetWorkspaceStorageSetting(ctx)
+	case storepb.WorkspaceSettingKey_AI:
+		_, err = s.Store.GetWorkspaceAISetting(ctx)
+	case storepb.WorkspaceSettingKey_EMAIL_TEMPLATES:
+		_, err = s.Store.GetWorkspaceEmailTemplatesSetting(ctx)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported workspace setting key: %v", workspaceSettingKey)
 	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get workspace setting: %v", err)
@@ -64,8 +69,8 @@ func (s *APIV1Service) GetWorkspaceSetting(ctx context.Context, request *v1pb.Ge
 		return nil, status.Errorf(codes.NotFound, "workspace setting not found")
 	}
 
-	// For storage setting, only host can get it.
-	if workspaceSetting.Key == storepb.WorkspaceSettingKey_STORAGE {
+	// For storage, AI and email templates settings, only host can get it.
+	if workspaceSetting.Key == storepb.WorkspaceSettingKey_STORAGE || workspaceSetting.Key == storepb.WorkspaceSettingKey_AI || workspaceSetting.Key == storepb.WorkspaceSettingKey_EMAIL_TEMPLATES {
 		user, err := s.GetCurrentUser(ctx)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
@@ -75,7 +80,19 @@ func (s *APIV1Service) GetWorkspaceSetting(ctx context.Context, request *v1pb.Ge
 		}
 	}
 
-	return convertWorkspaceSettingFromStore(workspaceSetting), nil
+	setting := convertWorkspaceSettingFromStore(workspaceSetting)
+	// The general setting is public, but its mail secrets are only for host.
+	if generalSetting := setting.GetGeneralSetting(); generalSetting != nil {
+		user, err := s.GetCurrentUser(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+		}
+		if user == nil || user.Role != store.RoleHost {
+			generalSetting.SmtpAccountPassword = ""
+			generalSetting.DkimPrivateKey = ""
+		}
+	}
+	return setting, nil
 }
 
 func (s *APIV1Service) UpdateWorkspaceSetting(ctx context.Context, request *v1pb.UpdateWorkspaceSettingRequest) (*v1pb.WorkspaceSetting, error) {
@@ -91,6 +108,24 @@ func (s *APIV1Service) UpdateWorkspaceSetting(ctx context.Context, request *v1pb
 	_ = request.UpdateMask
 
 	updateSetting := convertWorkspaceSettingToStore(request.Setting)
+	if updateSetting.Key == storepb.WorkspaceSettingKey_BASIC {
+		// The basic setting holds the server secrets and signing keys, which the API does not expose.
+		workspaceBasicSetting, err := s.Store.GetWorkspaceBasicSetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace basic setting: %v", err)
+		}
+		updateSetting.Value = &storepb.WorkspaceSetting_BasicSetting{BasicSetting: workspaceBasicSetting}
+	}
+	if updateSetting.Key == storepb.WorkspaceSettingKey_EMAIL_TEMPLATES {
+		if err := validateEmailTemplates(updateSetting.GetEmailTemplatesSetting()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid email templates: %v", err)
+		}
+	}
+	if updateSetting.Key == storepb.WorkspaceSettingKey_GENERAL {
+		if err := validateDKIMSetting(updateSetting.GetGeneralSetting()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid dkim setting: %v", err)
+		}
+	}
 	workspaceSetting, err := s.Store.UpsertWorkspaceSetting(ctx, updateSetting)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to upsert workspace setting: %v", err)
@@ -116,6 +151,14 @@ func convertWorkspaceSettingFromStore(setting *storepb.WorkspaceSetting) *v1pb.W
 		workspaceSetting.Value = &v1pb.WorkspaceSetting_MemoRelatedSetting{
 			MemoRelatedSetting: convertWorkspaceMemoRelatedSettingFromStore(setting.GetMemoRelatedSetting()),
 		}
+	case *storepb.WorkspaceSetting_AiSetting:
+		workspaceSetting.Value = &v1pb.WorkspaceSetting_AiSetting{
+			AiSetting: convertWorkspaceAISettingFromStore(setting.GetAiSetting()),
+		}
+	case *storepb.WorkspaceSetting_EmailTemplatesSetting:
+		workspaceSetting.Value = &v1pb.WorkspaceSetting_EmailTemplatesSetting{
+			EmailTemplatesSetting: convertWorkspaceEmailTemplatesSettingFromStore(setting.GetEmailTemplatesSetting()),
+		}
 	}
 	return workspaceSetting
 }
@@ -141,6 +184,14 @@ func convertWorkspaceSettingToStore(setting *v1pb.WorkspaceSetting) *storepb.Wor
 		workspaceSetting.Value = &storepb.WorkspaceSetting_MemoRelatedSetting{
 			MemoRelatedSetting: convertWorkspaceMemoRelatedSettingToStore(setting.GetMemoRelatedSetting()),
 		}
+	case storepb.WorkspaceSettingKey_AI:
+		workspaceSetting.Value = &storepb.WorkspaceSetting_AiSetting{
+			AiSetting: convertWorkspaceAISettingToStore(setting.GetAiSetting()),
+		}
+	case storepb.WorkspaceSettingKey_EMAIL_TEMPLATES:
+		workspaceSetting.Value = &storepb.WorkspaceSetting_EmailTemplatesSetting{
+			EmailTemplatesSetting: convertWorkspaceEmailTemplatesSettingToStore(setting.GetEmailTemplatesSetting()),
+		}
 	}
 	return workspaceSetting
 }
@@ -164,12 +215,17 @@ func convertWorkspaceGeneralSettingFromStore(setting *storepb.WorkspaceGeneralSe
 		WeekStartDayOffset:       setting.WeekStartDayOffset,
 		DisallowChangeUsername:   setting.DisallowChangeUsername,
 		DisallowChangeNickname:   setting.DisallowChangeNickname,
-		EnableEmailVerification: setting.EnableEmailVerification,
-		SmtpHost: setting.SmtpHost,
-		SmtpPort: setting.SmtpPort,
-		SmtpAccountUsername: setting.SmtpAccountUsername,
-		SmtpAccountEmail: setting.SmtpAccountEmail,
-		SmtpAccountPassword: setting.SmtpAccountPassword,
+		EnableEmailVerification:  setting.EnableEmailVerification,
+		SmtpHost:                 setting.SmtpHost,
+		SmtpPort:                 setting.SmtpPort,
+		SmtpAccountUsername:      setting.SmtpAccountUsername,
+		SmtpAccountEmail:         setting.SmtpAccountEmail,
+		SmtpAccountPassword:      setting.SmtpAccountPassword,
+		SmtpSecurity:             v1pb.WorkspaceGeneralSetting_SmtpSecurity(setting.SmtpSecurity),
+		DkimDomain:               setting.DkimDomain,
+		DkimSelector:             setting.DkimSelector,
+		DkimPrivateKey:           setting.DkimPrivateKey,
+		RequireTwoFactor:         setting.RequireTwoFactor,
 	}
 	if setting.CustomProfile != nil {
 		generalSetting.CustomProfile = &v1pb.WorkspaceCustomProfile{
@@ -180,6 +236,15 @@ func convertWorkspaceGeneralSettingFromStore(setting *storepb.WorkspaceGeneralSe
 			Appearance:  setting.CustomProfile.Appearance,
 		}
 	}
+	if setting.RateLimit != nil {
+		generalSetting.RateLimit = &v1pb.WorkspaceRateLimit{
+			Disabled:                 setting.RateLimit.Disabled,
+			IpRequestsPerMinute:      setting.RateLimit.IpRequestsPerMinute,
+			AccountRequestsPerMinute: setting.RateLimit.AccountRequestsPerMinute,
+			LockoutThreshold:         setting.RateLimit.LockoutThreshold,
+			LockoutSeconds:           setting.RateLimit.LockoutSeconds,
+		}
+	}
 	return generalSetting
 }
 
@@ -197,11 +262,16 @@ func convertWorkspaceGeneralSettingToStore(setting *v1pb.WorkspaceGeneralSetting
 		DisallowChangeUsername:   setting.DisallowChangeUsername,
 		DisallowChangeNickname:   setting.DisallowChangeNickname,
 		EnableEmailVerification:  setting.EnableEmailVerification,
-		SmtpHost: setting.SmtpHost,
-		SmtpPort: setting.SmtpPort,
-		SmtpAccountUsername: setting.SmtpAccountUsername,
-		SmtpAccountEmail: setting.SmtpAccountEmail,
-		SmtpAccountPassword: setting.SmtpAccountPassword,
+		SmtpHost:                 setting.SmtpHost,
+		SmtpPort:                 setting.SmtpPort,
+		SmtpAccountUsername:      setting.SmtpAccountUsername,
+		SmtpAccountEmail:         setting.SmtpAccountEmail,
+		SmtpAccountPassword:      setting.SmtpAccountPassword,
+		SmtpSecurity:             storepb.WorkspaceGeneralSetting_SmtpSecurity(setting.SmtpSecurity),
+		DkimDomain:               setting.DkimDomain,
+		DkimSelector:             setting.DkimSelector,
+		DkimPrivateKey:           setting.DkimPrivateKey,
+		RequireTwoFactor:         setting.RequireTwoFactor,
 	}
 	if setting.CustomProfile != nil {
 		generalSetting.CustomProfile = &storepb.WorkspaceCustomProfile{
@@ -212,6 +282,15 @@ func convertWorkspaceGeneralSettingToStore(setting *v1pb.WorkspaceGeneralSetting
 			Appearance:  setting.CustomProfile.Appearance,
 		}
 	}
+	if setting.RateLimit != nil {
+		generalSetting.RateLimit = &storepb.WorkspaceRateLimit{
+			Disabled:                 setting.RateLimit.Disabled,
+			IpRequestsPerMinute:      setting.RateLimit.IpRequestsPerMinute,
+			AccountRequestsPerMinute: setting.RateLimit.AccountRequestsPerMinute,
+			LockoutThreshold:         setting.RateLimit.LockoutThreshold,
+			LockoutSeconds:           setting.RateLimit.LockoutSeconds,
+		}
+	}
 	return generalSetting
 }
 
@@ -291,6 +370,92 @@ func convertWorkspaceMemoRelatedSettingToStore(setting *v1pb.WorkspaceMemoRelate
 	}
 }
 
+func convertWorkspaceAISettingFromStore(setting *storepb.WorkspaceAISetting) *v1pb.WorkspaceAISetting {
+	if setting == nil {
+		return nil
+	}
+	return &v1pb.WorkspaceAISetting{
+		Provider:       v1pb.WorkspaceAISetting_Provider(setting.Provider),
+		BaseUrl:        setting.BaseUrl,
+		Model:          setting.Model,
+		ApiKey:         setting.ApiKey,
+		EmbeddingModel: setting.EmbeddingModel,
+		UserQuota:      convertWorkspaceAIQuotaFromStore(setting.UserQuota),
+		WorkspaceQuota: convertWorkspaceAIQuotaFromStore(setting.WorkspaceQuota),
+	}
+}
+
+func convertWorkspaceAISettingToStore(setting *v1pb.WorkspaceAISetting) *storepb.WorkspaceAISetting {
+	if setting == nil {
+		return nil
+	}
+	return &storepb.WorkspaceAISetting{
+		Provider:       storepb.WorkspaceAISetting_Provider(setting.Provider),
+		BaseUrl:        setting.BaseUrl,
+		Model:          setting.Model,
+		ApiKey:         setting.ApiKey,
+		EmbeddingModel: setting.EmbeddingModel,
+		UserQuota:      convertWorkspaceAIQuotaToStore(setting.UserQuota),
+		WorkspaceQuota: convertWorkspaceAIQuotaToStore(setting.WorkspaceQuota),
+	}
+}
+
+func convertWorkspaceAIQuotaFromStore(quota *storepb.WorkspaceAISetting_Quota) *v1pb.WorkspaceAISetting_Quota {
+	if quota == nil {
+		return nil
+	}
+	return &v1pb.WorkspaceAISetting_Quota{
+		Window:      v1pb.WorkspaceAISetting_Quota_Window(quota.Window),
+		MaxRequests: quota.MaxRequests,
+		MaxTokens:   quota.MaxTokens,
+	}
+}
+
+func convertWorkspaceAIQuotaToStore(quota *v1pb.WorkspaceAISetting_Quota) *storepb.WorkspaceAISetting_Quota {
+	if quota == nil {
+		return nil
+	}
+	return &storepb.WorkspaceAISetting_Quota{
+		Window:      storepb.WorkspaceAISetting_Quota_Window(quota.Window),
+		MaxRequests: quota.MaxRequests,
+		MaxTokens:   quota.MaxTokens,
+	}
+}
+
+func convertWorkspaceEmailTemplatesSettingFromStore(setting *storepb.WorkspaceEmailTemplatesSetting) *v1pb.WorkspaceEmailTemplatesSetting {
+	if setting == nil {
+		return nil
+	}
+	templates := make([]*v1pb.EmailTemplate, 0, len(setting.Templates))
+	for _, template := range setting.Templates {
+		templates = append(templates, &v1pb.EmailTemplate{
+			Name:     template.Name,
+			Locale:   template.Locale,
+			Subject:  template.Subject,
+			TextBody: template.TextBody,
+			HtmlBody: template.HtmlBody,
+		})
+	}
+	return &v1pb.WorkspaceEmailTemplatesSetting{Templates: templates}
+}
+
+func convertWorkspaceEmailTemplatesSettingToStore(setting *v1pb.WorkspaceEmailTemplatesSetting) *storepb.WorkspaceEmailTemplatesSetting {
+	if setting == nil {
+		return &storepb.WorkspaceEmailTemplatesSetting{}
+	}
+	templates := make([]*storepb.EmailTemplate, 0, len(setting.Templates))
+	for _, template := range setting.Templates {
+		templates = append(templates, &storepb.EmailTemplate{
+			Name:     template.Name,
+			Locale:   template.Locale,
+			Subject:  template.Subject,
+			TextBody: template.TextBody,
+			HtmlBody: template.HtmlBody,
+		})
+	}
+	return &storepb.WorkspaceEmailTemplatesSetting{Templates: templates}
+}
+
 var ownerCache *v1pb.User
 
 func (s *APIV1Service) GetInstanceOwner(ctx context.Context) (*v1pb.User, error) {
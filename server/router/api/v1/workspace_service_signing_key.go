@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/imrany/wekalist/internal/util"
+	v1pb "github.com/imrany/wekalist/proto/gen/api/v1"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// SigningKeyNamePrefix is the prefix of the resource names of signing keys.
+	SigningKeyNamePrefix = "workspace/signingKeys/"
+	// defaultSigningKeyGracePeriod is how long JWTs signed by a retired key keep validating by default.
+	defaultSigningKeyGracePeriod = 7 * 24 * time.Hour
+)
+
+func (s *APIV1Service) ListSigningKeys(ctx context.Context, _ *v1pb.ListSigningKeysRequest) (*v1pb.ListSigningKeysResponse, error) {
+	if err := s.checkSigningKeyPermission(ctx); err != nil {
+		return nil, err
+	}
+	signingKeys, err := getJWTSigningKeys(ctx, s.Store, s.Secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get signing keys: %v", err)
+	}
+	response := &v1pb.ListSigningKeysResponse{}
+	for _, signingKey := range signingKeys {
+		response.SigningKeys = append(response.SigningKeys, convertSigningKeyFromStore(signingKey))
+	}
+	return response, nil
+}
+
+func (s *APIV1Service) RotateSigningKey(ctx context.Context, request *v1pb.RotateSigningKeyRequest) (*v1pb.SigningKey, error) {
+	if err := s.checkSigningKeyPermission(ctx); err != nil {
+		return nil, err
+	}
+	gracePeriod := defaultSigningKeyGracePeriod
+	if request.GracePeriod != nil {
+		if err := request.GracePeriod.CheckValid(); err != nil || request.GracePeriod.AsDuration() < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid grace period")
+		}
+		gracePeriod = request.GracePeriod.AsDuration()
+	}
+
+	signingKeys, err := getJWTSigningKeys(ctx, s.Store, s.Secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get signing keys: %v", err)
+	}
+	signingKey, err := NewJWTSigningKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate signing key: %v", err)
+	}
+	now := time.Now()
+	rotatedSigningKeys := []*storepb.JWTSigningKey{signingKey}
+	for _, key := range signingKeys {
+		key = proto.Clone(key).(*storepb.JWTSigningKey)
+		if key.ExpireTime == nil {
+			// The previous active key is retired.
+			key.ExpireTime = timestamppb.New(now.Add(gracePeriod))
+		}
+		if !key.ExpireTime.AsTime().After(now) {
+			continue
+		}
+		rotatedSigningKeys = append(rotatedSigningKeys, key)
+	}
+	if err := s.updateJWTSigningKeys(ctx, rotatedSigningKeys); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update signing keys: %v", err)
+	}
+	return convertSigningKeyFromStore(signingKey), nil
+}
+
+func (s *APIV1Service) RevokeSigningKey(ctx context.Context, request *v1pb.RevokeSigningKeyRequest) (*emptypb.Empty, error) {
+	if err := s.checkSigningKeyPermission(ctx); err != nil {
+		return nil, err
+	}
+	keyID, ok := strings.CutPrefix(request.Name, SigningKeyNamePrefix)
+	if !ok || keyID == "" || strings.Contains(keyID, "/") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signing key name: %s", request.Name)
+	}
+
+	signingKeys, err := getJWTSigningKeys(ctx, s.Store, s.Secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get signing keys: %v", err)
+	}
+	remainingSigningKeys := []*storepb.JWTSigningKey{}
+	found := false
+	for _, key := range signingKeys {
+		if key.Id != keyID {
+			remainingSigningKeys = append(remainingSigningKeys, key)
+			continue
+		}
+		if key.ExpireTime == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "the active signing key cannot be revoked, rotate it first")
+		}
+		found = true
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "signing key not found")
+	}
+	if err := s.updateJWTSigningKeys(ctx, remainingSigningKeys); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update signing keys: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) checkSigningKeyPermission(ctx context.Context) error {
+	user, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if user == nil || user.Role != store.RoleHost {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return nil
+}
+
+// updateJWTSigningKeys replaces the signing keys of the workspace basic setting.
+func (s *APIV1Service) updateJWTSigningKeys(ctx context.Context, signingKeys []*storepb.JWTSigningKey) error {
+	workspaceBasicSetting, err := s.Store.GetWorkspaceBasicSetting(ctx)
+	if err != nil {
+		return err
+	}
+	// The basic setting is cached, so it must not be modified in place.
+	workspaceBasicSetting = proto.Clone(workspaceBasicSetting).(*storepb.WorkspaceBasicSetting)
+	workspaceBasicSetting.JwtSigningKeys = signingKeys
+	_, err = s.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key:   storepb.WorkspaceSettingKey_BASIC,
+		Value: &storepb.WorkspaceSetting_BasicSetting{BasicSetting: workspaceBasicSetting},
+	})
+	return err
+}
+
+func (s *APIV1Service) signJWT(ctx context.Context, claims jwt.Claims) (string, error) {
+	return signJWT(ctx, s.Store, s.Secret, claims)
+}
+
+func (s *APIV1Service) parseJWT(ctx context.Context, tokenString string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	return parseJWT(ctx, s.Store, s.Secret, tokenString, claims, options...)
+}
+
+// getJWTSigningKeys returns the keys JWTs are signed with, the active key first.
+// Until the server seeds the first key, JWTs are signed with the server secret under KeyID.
+func getJWTSigningKeys(ctx context.Context, stores *store.Store, secret string) ([]*storepb.JWTSigningKey, error) {
+	workspaceBasicSetting, err := stores.GetWorkspaceBasicSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(workspaceBasicSetting.JwtSigningKeys) == 0 {
+		return []*storepb.JWTSigningKey{{Id: KeyID, Secret: secret}}, nil
+	}
+	return workspaceBasicSetting.JwtSigningKeys, nil
+}
+
+// signJWT signs the claims with the active signing key, identified by the kid header.
+func signJWT(ctx context.Context, stores *store.Store, secret string, claims jwt.Claims) (string, error) {
+	signingKeys, err := getJWTSigningKeys(ctx, stores, secret)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signing keys")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = signingKeys[0].Id
+	return token.SignedString([]byte(signingKeys[0].Secret))
+}
+
+// parseJWT parses and validates a JWT signed by the active key or by a retired key within its grace period.
+func parseJWT(ctx context.Context, stores *store.Store, secret, tokenString string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	signingKeys, err := getJWTSigningKeys(ctx, stores, secret)
+	if err != nil {
+		return errors.Wrap(err, "failed to get signing keys")
+	}
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, key := range signingKeys {
+			if key.Id != kid {
+				continue
+			}
+			if key.ExpireTime != nil && !key.ExpireTime.AsTime().After(time.Now()) {
+				return nil, errors.Errorf("signing key %q is retired", kid)
+			}
+			return []byte(key.Secret), nil
+		}
+		return nil, errors.Errorf("unexpected kid=%v", t.Header["kid"])
+	}, options...)
+	return err
+}
+
+// NewJWTSigningKey generates a signing key with a random ID and secret.
+func NewJWTSigningKey() (*storepb.JWTSigningKey, error) {
+	id, err := util.RandomString(12)
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &storepb.JWTSigningKey{
+		Id:         id,
+		Secret:     base64.RawURLEncoding.EncodeToString(secret),
+		CreateTime: timestamppb.Now(),
+	}, nil
+}
+
+func convertSigningKeyFromStore(signingKey *storepb.JWTSigningKey) *v1pb.SigningKey {
+	return &v1pb.SigningKey{
+		Name:       fmt.Sprintf("%s%s", SigningKeyNamePrefix, signingKey.Id),
+		CreateTime: signingKey.CreateTime,
+		ExpireTime: signingKey.ExpireTime,
+	}
+}
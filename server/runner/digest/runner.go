@@ -0,0 +1,313 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/imrany/wekalist/internal/profile"
+	"github.com/imrany/wekalist/plugin/cron"
+	"github.com/imrany/wekalist/plugin/mail"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+// SendEmailFunc sends the user the email of the template, rendered with the data in the locale of the user.
+type SendEmailFunc func(ctx context.Context, user *store.User, template string, data any) error
+
+// Runner emails the users who opted in a digest of the new memos, the comments on their memos and
+// their unread inbox items since their previous digest.
+type Runner struct {
+	Store     *store.Store
+	Profile   *profile.Profile
+	SendEmail SendEmailFunc
+}
+
+func NewRunner(store *store.Store, profile *profile.Profile, sendEmail SendEmailFunc) *Runner {
+	return &Runner{
+		Store:     store,
+		Profile:   profile,
+		SendEmail: sendEmail,
+	}
+}
+
+// Schedule runner every 10 minutes, the digests are sent once due by their schedule.
+const runnerInterval = time.Minute * 10
+
+// schedules are the cron specs of the digests, in the local time of the server.
+var schedules = map[storepb.DigestUserSetting_Frequency]string{
+	storepb.DigestUserSetting_DAILY:  "0 8 * * *",
+	storepb.DigestUserSetting_WEEKLY: "0 8 * * 1",
+}
+
+const (
+	// maxDigestItems is the number of items listed in each section of a digest, the others are counted.
+	maxDigestItems = 10
+	// maxDigestMemos bounds the new memos read for a digest.
+	maxDigestMemos = 500
+	// maxSnippetLength is the length of the memo content quoted in a digest.
+	maxSnippetLength = 200
+)
+
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.RunOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) RunOnce(ctx context.Context) {
+	r.SendDigests(ctx, time.Now())
+}
+
+// SendDigests sends the digests due at the given time. The time of each digest sent is stored with the
+// digest setting of the user, so digests are neither sent twice nor skipped across restarts.
+func (r *Runner) SendDigests(ctx context.Context, now time.Time) {
+	workspaceGeneralSetting, err := r.Store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		slog.Error("failed to get workspace general setting", "err", err)
+		return
+	}
+	// Digests are only sent once an admin configures SMTP.
+	if workspaceGeneralSetting.SmtpHost == "" {
+		return
+	}
+
+	userSettings, err := r.Store.ListUserSettings(ctx, &store.FindUserSetting{
+		Key: storepb.UserSetting_DIGEST,
+	})
+	if err != nil {
+		slog.Error("failed to list digest settings", "err", err)
+		return
+	}
+	for _, userSetting := range userSettings {
+		digestSetting := userSetting.GetDigest()
+		spec, ok := schedules[digestSetting.GetFrequency()]
+		if !ok {
+			continue
+		}
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			slog.Error("failed to parse digest schedule", "spec", spec, "err", err)
+			continue
+		}
+		if digestSetting.LastSentTime == nil {
+			// The first digest lists what happens from now on.
+			if err := r.updateLastSentTime(ctx, userSetting.UserId, now); err != nil {
+				slog.Error("failed to update digest setting", "user", userSetting.UserId, "err", err)
+			}
+			continue
+		}
+		since := digestSetting.LastSentTime.AsTime()
+		if schedule.Next(since).After(now) {
+			continue
+		}
+		if err := r.sendDigest(ctx, userSetting.UserId, digestSetting.Frequency, since, now); err != nil {
+			slog.Error("failed to send digest", "user", userSetting.UserId, "err", err)
+			continue
+		}
+		if err := r.updateLastSentTime(ctx, userSetting.UserId, now); err != nil {
+			slog.Error("failed to update digest setting", "user", userSetting.UserId, "err", err)
+		}
+	}
+}
+
+// sendDigest emails the user the digest of what happened between the times, unless there is nothing new.
+func (r *Runner) sendDigest(ctx context.Context, userID int32, frequency storepb.DigestUserSetting_Frequency, since, until time.Time) error {
+	user, err := r.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get user")
+	}
+	if user == nil || user.RowStatus == store.Archived || user.Email == "" {
+		return nil
+	}
+
+	digest, err := r.collectDigest(ctx, user, since, until)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		return nil
+	}
+	digest.Frequency = strings.ToLower(frequency.String())
+	return r.SendEmail(ctx, user, mail.TemplateDigest, digest)
+}
+
+// updateLastSentTime stores the time of the digest sent, keeping the frequency the user may have changed meanwhile.
+func (r *Runner) updateLastSentTime(ctx context.Context, userID int32, lastSentTime time.Time) error {
+	digestSetting, err := r.Store.GetUserDigestSetting(ctx, userID)
+	if err != nil {
+		return err
+	}
+	digestSetting.LastSentTime = timestamppb.New(lastSentTime)
+	return r.Store.UpsertUserDigestSetting(ctx, userID, digestSetting)
+}
+
+// collectDigest gathers the items created between the times: the new memos of the explore page, the comments
+// of others on the memos of the user and the unread inbox items of the user.
+func (r *Runner) collectDigest(ctx context.Context, user *store.User, since, until time.Time) (*mail.DigestData, error) {
+	digest := &mail.DigestData{}
+	if r.Profile != nil {
+		digest.URL = strings.TrimSuffix(r.Profile.InstanceURL, "/")
+	}
+	users := map[int32]*store.User{user.ID: user}
+	getUserName := func(userID int32) (string, error) {
+		if _, ok := users[userID]; !ok {
+			user, err := r.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+			if err != nil {
+				return "", errors.Wrap(err, "failed to get user")
+			}
+			users[userID] = user
+		}
+		return getDisplayName(users[userID]), nil
+	}
+
+	normalStatus := store.Normal
+	limit := maxDigestMemos
+	memos, err := r.Store.ListMemos(ctx, &store.FindMemo{
+		RowStatus:      &normalStatus,
+		VisibilityList: []store.Visibility{store.Public, store.Protected},
+		Filters:        []string{fmt.Sprintf("created_ts > %d && created_ts <= %d && creator_id != %d", since.Unix(), until.Unix(), user.ID)},
+		Limit:          &limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list memos")
+	}
+	parentMemos := map[int32]*store.Memo{}
+	for _, memo := range memos {
+		creatorName, err := getUserName(memo.CreatorID)
+		if err != nil {
+			return nil, err
+		}
+		if memo.ParentID == nil {
+			addItem(&digest.Memos, mail.DigestItem{
+				Kind:  "memo",
+				Actor: creatorName,
+				Body:  getSnippet(memo.Content),
+				URL:   getMemoURL(digest, memo),
+			})
+			continue
+		}
+		parentMemo, ok := parentMemos[*memo.ParentID]
+		if !ok {
+			parentMemo, err = r.Store.GetMemo(ctx, &store.FindMemo{ID: memo.ParentID})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get memo")
+			}
+			parentMemos[*memo.ParentID] = parentMemo
+		}
+		if parentMemo == nil || parentMemo.CreatorID != user.ID {
+			continue
+		}
+		addItem(&digest.Comments, mail.DigestItem{
+			Kind:  "comment",
+			Actor: creatorName,
+			Body:  getSnippet(memo.Content),
+			URL:   getMemoURL(digest, parentMemo),
+		})
+	}
+
+	unreadStatus := store.UNREAD
+	inboxes, err := r.Store.ListInboxes(ctx, &store.FindInbox{
+		ReceiverID: &user.ID,
+		Status:     &unreadStatus,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list inboxes")
+	}
+	for _, inbox := range inboxes {
+		if inbox.CreatedTs <= since.Unix() || inbox.CreatedTs > until.Unix() {
+			continue
+		}
+		item, err := r.getInboxItem(ctx, digest, inbox, getUserName)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			addItem(&digest.Inbox, *item)
+		}
+	}
+	return digest, nil
+}
+
+// getInboxItem describes the inbox message, or returns nil for the messages already listed with the comments.
+func (r *Runner) getInboxItem(ctx context.Context, digest *mail.DigestData, inbox *store.Inbox, getUserName func(int32) (string, error)) (*mail.DigestItem, error) {
+	if inbox.Message.Type == storepb.InboxMessage_MEMO_COMMENT {
+		return nil, nil
+	}
+	if inbox.Message.Type == storepb.InboxMessage_VERSION_UPDATE {
+		return &mail.DigestItem{Kind: "version_update", URL: digest.URL}, nil
+	}
+
+	senderName, err := getUserName(inbox.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	item := &mail.DigestItem{Actor: senderName, URL: digest.URL}
+	var memoID int32
+	if inbox.Message.ActivityId != nil {
+		activity, err := r.Store.GetActivity(ctx, &store.FindActivity{ID: inbox.Message.ActivityId})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get activity")
+		}
+		if activity != nil {
+			switch inbox.Message.Type {
+			case storepb.InboxMessage_MEMO_REACTION:
+				memoID = activity.Payload.GetMemoReaction().GetMemoId()
+			case storepb.InboxMessage_MEMO_MENTION:
+				memoID = activity.Payload.GetMemoMention().GetMemoId()
+			default:
+			}
+		}
+	}
+	if memoID != 0 {
+		memo, err := r.Store.GetMemo(ctx, &store.FindMemo{ID: &memoID})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get memo")
+		}
+		if memo != nil {
+			item.Body = getSnippet(memo.Content)
+			item.URL = getMemoURL(digest, memo)
+		}
+	}
+	switch inbox.Message.Type {
+	case storepb.InboxMessage_MEMO_REACTION:
+		item.Kind = "reaction"
+	case storepb.InboxMessage_MEMO_MENTION:
+		item.Kind = "mention"
+	default:
+	}
+	return item, nil
+}
+
+// getDisplayName returns the name the user is shown with in digests.
+func getDisplayName(user *store.User) string {
+	if user == nil {
+		return "Someone"
+	}
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
+
+// getSnippet shortens the memo content quoted in a digest.
+func getSnippet(content string) string {
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) <= maxSnippetLength {
+		return string(runes)
+	}
+	return string(runes[:maxSnippetLength-1]) + "…"
+}
@@ -0,0 +1,119 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/imrany/wekalist/internal/profile"
+	"github.com/imrany/wekalist/plugin/mail"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+	teststore "github.com/imrany/wekalist/store/test"
+)
+
+type sentEmail struct {
+	To       string
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+func TestSendDigests(t *testing.T) {
+	ctx := context.Background()
+	testStore := teststore.NewTestingStore(ctx, t)
+	defer testStore.Close()
+
+	_, err := testStore.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_GENERAL,
+		Value: &storepb.WorkspaceSetting_GeneralSetting{
+			GeneralSetting: &storepb.WorkspaceGeneralSetting{SmtpHost: "smtp.example.com"},
+		},
+	})
+	require.NoError(t, err)
+	alice, err := testStore.CreateUser(ctx, &store.User{Username: "alice", Role: store.RoleUser, Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := testStore.CreateUser(ctx, &store.User{Username: "bob", Nickname: "Bob", Role: store.RoleUser, Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	aliceMemo, err := testStore.CreateMemo(ctx, &store.Memo{UID: "alice-memo", CreatorID: alice.ID, Content: "My memo", Visibility: store.Public})
+	require.NoError(t, err)
+	_, err = testStore.CreateMemo(ctx, &store.Memo{UID: "bob-memo", CreatorID: bob.ID, Content: "Hello <everyone>", Visibility: store.Public})
+	require.NoError(t, err)
+	_, err = testStore.CreateMemo(ctx, &store.Memo{UID: "bob-private", CreatorID: bob.ID, Content: "Secret", Visibility: store.Private})
+	require.NoError(t, err)
+	comment, err := testStore.CreateMemo(ctx, &store.Memo{UID: "bob-comment", CreatorID: bob.ID, Content: "Nice memo", Visibility: store.Public})
+	require.NoError(t, err)
+	_, err = testStore.UpsertMemoRelation(ctx, &store.MemoRelation{MemoID: comment.ID, RelatedMemoID: aliceMemo.ID, Type: store.MemoRelationComment})
+	require.NoError(t, err)
+	mentionMemo, err := testStore.CreateMemo(ctx, &store.Memo{UID: "bob-mention", CreatorID: bob.ID, Content: "Thanks @alice", Visibility: store.Protected})
+	require.NoError(t, err)
+	activity, err := testStore.CreateActivity(ctx, &store.Activity{
+		CreatorID: bob.ID,
+		Type:      store.ActivityTypeMemoMention,
+		Level:     store.ActivityLevelInfo,
+		Payload:   &storepb.ActivityPayload{MemoMention: &storepb.ActivityMemoMentionPayload{MemoId: mentionMemo.ID}},
+	})
+	require.NoError(t, err)
+	_, err = testStore.CreateInbox(ctx, &store.Inbox{
+		SenderID:   bob.ID,
+		ReceiverID: alice.ID,
+		Status:     store.UNREAD,
+		Message:    &storepb.InboxMessage{Type: storepb.InboxMessage_MEMO_MENTION, ActivityId: &activity.ID},
+	})
+	require.NoError(t, err)
+
+	err = testStore.UpsertUserDigestSetting(ctx, alice.ID, &storepb.DigestUserSetting{
+		Frequency:    storepb.DigestUserSetting_DAILY,
+		LastSentTime: timestamppb.New(time.Now().Add(-48 * time.Hour)),
+	})
+	require.NoError(t, err)
+	// The first digest of bob lists what happens from now on.
+	err = testStore.UpsertUserDigestSetting(ctx, bob.ID, &storepb.DigestUserSetting{Frequency: storepb.DigestUserSetting_WEEKLY})
+	require.NoError(t, err)
+
+	templates := mail.NewRegistry()
+	sent := []*sentEmail{}
+	newRunner := func() *Runner {
+		return NewRunner(testStore, &profile.Profile{InstanceURL: "http://localhost:8080"}, func(_ context.Context, user *store.User, template string, data any) error {
+			message, err := templates.Render(template, "", data)
+			if err != nil {
+				return err
+			}
+			sent = append(sent, &sentEmail{To: user.Email, Subject: message.Subject, HTMLBody: message.HTMLBody, TextBody: message.TextBody})
+			return nil
+		})
+	}
+	now := time.Now().Add(time.Minute)
+	newRunner().SendDigests(ctx, now)
+	require.Len(t, sent, 1)
+	email := sent[0]
+	require.Equal(t, "alice@example.com", email.To)
+	require.Equal(t, "Your daily wekalist digest", email.Subject)
+	require.Contains(t, email.HTMLBody, "Hello &lt;everyone&gt;")
+	require.Contains(t, email.HTMLBody, `href="http://localhost:8080/memos/bob-memo"`)
+	require.Contains(t, email.HTMLBody, "Bob commented on your memo")
+	require.Contains(t, email.HTMLBody, `href="http://localhost:8080/memos/alice-memo"`)
+	require.Contains(t, email.HTMLBody, "Bob mentioned you in a memo")
+	require.NotContains(t, email.HTMLBody, "Secret")
+	require.Contains(t, email.TextBody, "Hello <everyone>")
+	require.Contains(t, email.TextBody, "http://localhost:8080/memos/bob-mention")
+
+	aliceDigest, err := testStore.GetUserDigestSetting(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Equal(t, now.Unix(), aliceDigest.LastSentTime.AsTime().Unix())
+	bobDigest, err := testStore.GetUserDigestSetting(ctx, bob.ID)
+	require.NoError(t, err)
+	require.Equal(t, now.Unix(), bobDigest.LastSentTime.AsTime().Unix())
+
+	// Digests already sent are not sent again after a restart.
+	newRunner().SendDigests(ctx, now.Add(time.Minute))
+	require.Len(t, sent, 1)
+
+	// Digests without anything new are skipped.
+	newRunner().SendDigests(ctx, now.Add(24*time.Hour))
+	require.Len(t, sent, 1)
+}
@@ -0,0 +1,23 @@
+package digest
+
+import (
+	"github.com/imrany/wekalist/plugin/mail"
+	"github.com/imrany/wekalist/store"
+)
+
+// addItem lists the item in the section, or counts it once the section lists maxDigestItems.
+func addItem(section *mail.DigestSection, item mail.DigestItem) {
+	if len(section.Items) == maxDigestItems {
+		section.MoreCount++
+		return
+	}
+	section.Items = append(section.Items, item)
+}
+
+// getMemoURL returns the link to the memo, or empty if the instance URL is not set.
+func getMemoURL(digest *mail.DigestData, memo *store.Memo) string {
+	if digest.URL == "" {
+		return ""
+	}
+	return digest.URL + "/memos/" + memo.UID
+}
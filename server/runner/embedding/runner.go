@@ -0,0 +1,250 @@
+package embedding
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/plugin/ai"
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+type Runner struct {
+	Store *store.Store
+}
+
+func NewRunner(store *store.Store) *Runner {
+	return &Runner{
+		Store: store,
+	}
+}
+
+// Schedule runner every 10 minutes.
+const runnerInterval = time.Minute * 10
+
+// maxInputRunes bounds the memo content sent to the embedding model.
+const maxInputRunes = 8000
+
+// refreshTimeout bounds a single asynchronous memo refresh.
+const refreshTimeout = time.Minute
+
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.RunOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) RunOnce(ctx context.Context) {
+	r.RefreshEmbeddings(ctx)
+}
+
+// RefreshEmbeddings computes embeddings for all memos whose embedding is missing or stale.
+func (r *Runner) RefreshEmbeddings(ctx context.Context) {
+	provider, err := newProvider(ctx, r.Store)
+	if err != nil {
+		slog.Error("failed to create ai provider", "err", err)
+		return
+	}
+	if provider.EmbeddingModel() == "" {
+		return
+	}
+
+	// Process memos in batches to avoid loading all memos into memory at once
+	const batchSize = 100
+	offset := 0
+	normalStatus := store.Normal
+
+	for {
+		limit := batchSize
+		memos, err := r.Store.ListMemos(ctx, &store.FindMemo{
+			RowStatus:       &normalStatus,
+			ExcludeComments: true,
+			Limit:           &limit,
+			Offset:          &offset,
+		})
+		if err != nil {
+			slog.Error("failed to list memos", "err", err)
+			return
+		}
+
+		// Break if no more memos
+		if len(memos) == 0 {
+			break
+		}
+
+		if err := refreshMemoEmbeddings(ctx, r.Store, provider, memos); err != nil {
+			slog.Error("failed to refresh memo embeddings", "err", err)
+			return
+		}
+
+		// Move to next batch
+		offset += len(memos)
+	}
+}
+
+// RefreshMemoEmbeddingAsync recomputes the embedding of a single memo in the background.
+func RefreshMemoEmbeddingAsync(s *store.Store, memoID int32) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		if err := RefreshMemoEmbedding(ctx, s, memoID); err != nil {
+			slog.Warn("failed to refresh memo embedding", "err", err, "memoID", memoID)
+		}
+	}()
+}
+
+// RefreshMemoEmbedding recomputes the embedding of a single memo if it is missing or stale.
+func RefreshMemoEmbedding(ctx context.Context, s *store.Store, memoID int32) error {
+	provider, err := newProvider(ctx, s)
+	if err != nil {
+		return err
+	}
+	if provider.EmbeddingModel() == "" {
+		return nil
+	}
+	memo, err := s.GetMemo(ctx, &store.FindMemo{ID: &memoID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get memo")
+	}
+	if memo == nil {
+		return nil
+	}
+	return refreshMemoEmbeddings(ctx, s, provider, []*store.Memo{memo})
+}
+
+func newProvider(ctx context.Context, s *store.Store) (ai.AIProvider, error) {
+	workspaceAISetting, err := s.GetWorkspaceAISetting(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workspace ai setting")
+	}
+	return ai.NewProvider(workspaceAISetting)
+}
+
+func refreshMemoEmbeddings(ctx context.Context, s *store.Store, provider ai.AIProvider, memos []*store.Memo) error {
+	model := provider.EmbeddingModel()
+	memoIDList := make([]int32, 0, len(memos))
+	for _, memo := range memos {
+		memoIDList = append(memoIDList, memo.ID)
+	}
+	embeddings, err := s.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{MemoIDList: memoIDList})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memo embeddings")
+	}
+	embeddingMap := make(map[int32]*store.MemoEmbedding, len(embeddings))
+	for _, embedding := range embeddings {
+		embeddingMap[embedding.MemoID] = embedding
+	}
+
+	// Each creator is charged for the embeddings of their memos, so the memos are embedded per creator.
+	creatorIDList := []int32{}
+	staleMemoMap := map[int32][]*store.Memo{}
+	for _, memo := range memos {
+		if strings.TrimSpace(memo.Content) == "" {
+			continue
+		}
+		existing, ok := embeddingMap[memo.ID]
+		if ok && existing.Model == model && existing.ContentHash == store.HashMemoContent(memo.Content) {
+			continue
+		}
+		if _, ok := staleMemoMap[memo.CreatorID]; !ok {
+			creatorIDList = append(creatorIDList, memo.CreatorID)
+		}
+		staleMemoMap[memo.CreatorID] = append(staleMemoMap[memo.CreatorID], memo)
+	}
+	if len(creatorIDList) == 0 {
+		return nil
+	}
+
+	workspaceAISetting, err := s.GetWorkspaceAISetting(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get workspace ai setting")
+	}
+	for _, creatorID := range creatorIDList {
+		if err := embedMemos(ctx, s, provider, workspaceAISetting, creatorID, staleMemoMap[creatorID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embedMemos embeds the memos of a creator and charges the call to the creator's AI usage.
+// The memos are left stale, to be retried later, while the creator or the workspace is out of quota.
+func embedMemos(ctx context.Context, s *store.Store, provider ai.AIProvider, workspaceAISetting *storepb.WorkspaceAISetting, creatorID int32, memos []*store.Memo) error {
+	texts := make([]string, 0, len(memos))
+	for _, memo := range memos {
+		texts = append(texts, truncate(strings.TrimSpace(memo.Content), maxInputRunes))
+	}
+	estimatedTokens := ai.EstimateTokens(texts...)
+	reservation, err := s.ReserveAIUsage(ctx, &store.ReserveAIUsage{
+		Usage: &store.AIUsage{
+			UserID:       creatorID,
+			PromptTokens: estimatedTokens,
+			TotalTokens:  estimatedTokens,
+		},
+		Limits: store.GetAIUsageLimits(workspaceAISetting, creatorID, time.Now()),
+	})
+	var limitErr *store.AIUsageLimitError
+	if errors.As(err, &limitErr) {
+		slog.Debug("skipped memo embeddings over the ai quota", "count", len(memos), "creatorID", creatorID)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to reserve ai usage")
+	}
+
+	response, err := provider.Embed(ctx, &ai.EmbedRequest{Texts: texts})
+	if err != nil {
+		if err := s.DeleteAIUsage(ctx, &store.DeleteAIUsage{ID: &reservation.ID}); err != nil {
+			slog.Warn("failed to release ai usage", "err", err, "creatorID", creatorID)
+		}
+		return errors.Wrap(err, "failed to embed memos")
+	}
+	usage := response.Usage
+	if usage.TotalTokens == 0 {
+		usage = ai.Usage{PromptTokens: estimatedTokens, TotalTokens: estimatedTokens}
+	}
+	if err := s.UpdateAIUsage(ctx, &store.UpdateAIUsage{
+		ID:               reservation.ID,
+		Model:            &response.Model,
+		PromptTokens:     &usage.PromptTokens,
+		CompletionTokens: &usage.CompletionTokens,
+		TotalTokens:      &usage.TotalTokens,
+	}); err != nil {
+		slog.Warn("failed to record ai usage", "err", err, "creatorID", creatorID)
+	}
+
+	model := provider.EmbeddingModel()
+	for i, memo := range memos {
+		if _, err := s.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
+			MemoID:      memo.ID,
+			Model:       model,
+			ContentHash: store.HashMemoContent(memo.Content),
+			Embedding:   response.Embeddings[i],
+		}); err != nil {
+			return errors.Wrap(err, "failed to upsert memo embedding")
+		}
+	}
+	slog.Debug("refreshed memo embeddings", "count", len(memos), "model", model)
+	return nil
+}
+
+func truncate(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes])
+}
@@ -0,0 +1,67 @@
+package sessiongc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/imrany/wekalist/store"
+)
+
+// Runner purges the user sessions that expired, by being idle or by reaching their maximum lifetime.
+type Runner struct {
+	Store *store.Store
+	// IdleDuration is how long a session stays valid without being used.
+	IdleDuration time.Duration
+}
+
+func NewRunner(store *store.Store, idleDuration time.Duration) *Runner {
+	return &Runner{
+		Store:        store,
+		IdleDuration: idleDuration,
+	}
+}
+
+// Schedule runner every hour.
+const runnerInterval = time.Hour
+
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.RunOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) RunOnce(ctx context.Context) {
+	r.PurgeExpiredSessions(ctx, time.Now())
+}
+
+// PurgeExpiredSessions deletes the sessions expired at the given time.
+func (r *Runner) PurgeExpiredSessions(ctx context.Context, now time.Time) {
+	nowTs := now.Unix()
+	expired, err := r.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{
+		ExpiresBeforeTs: &nowTs,
+	})
+	if err != nil {
+		slog.Error("failed to purge expired user sessions", "err", err)
+		return
+	}
+	idleSinceTs := now.Add(-r.IdleDuration).Unix()
+	idle, err := r.Store.DeleteUserSessions(ctx, &store.DeleteUserSession{
+		LastAccessedBeforeTs: &idleSinceTs,
+	})
+	if err != nil {
+		slog.Error("failed to purge idle user sessions", "err", err)
+		return
+	}
+	if expired+idle > 0 {
+		slog.Info("purged user sessions", "expired", expired, "idle", idle)
+	}
+}
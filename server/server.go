@@ -17,6 +17,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/soheilhy/cmux"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/imrany/wekalist/internal/profile"
 	storepb "github.com/imrany/wekalist/proto/gen/store"
@@ -24,7 +26,10 @@ import (
 	apiv1 "github.com/imrany/wekalist/server/router/api/v1"
 	"github.com/imrany/wekalist/server/router/frontend"
 	"github.com/imrany/wekalist/server/router/rss"
+	"github.com/imrany/wekalist/server/runner/digest"
+	"github.com/imrany/wekalist/server/runner/embedding"
 	"github.com/imrany/wekalist/server/runner/s3presign"
+	"github.com/imrany/wekalist/server/runner/sessiongc"
 	"github.com/imrany/wekalist/store"
 )
 
@@ -35,6 +40,7 @@ type Server struct {
 
 	echoServer        *echo.Echo
 	grpcServer        *grpc.Server
+	apiV1Service      *apiv1.APIV1Service
 	profiler          *profiler.Profiler
 	runnerCancelFuncs []context.CancelFunc
 }
@@ -83,17 +89,37 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 	// Create and register RSS routes.
 	rss.NewRSSService(s.Profile, s.Store).RegisterRoutes(rootGroup)
 
+	loggerInterceptor := apiv1.NewLoggerInterceptor()
+	trustedProxies, err := apiv1.ParseTrustedProxies(profile.TrustedProxies)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse trusted proxies")
+	}
+	rateLimitInterceptor := apiv1.NewRateLimitInterceptor(store, secret, trustedProxies)
+	authInterceptor := apiv1.NewGRPCAuthInterceptor(store, secret)
 	grpcServer := grpc.NewServer(
 		// Override the maximum receiving message size to math.MaxInt32 for uploading large attachments.
 		grpc.MaxRecvMsgSize(math.MaxInt32),
 		grpc.ChainUnaryInterceptor(
-			apiv1.NewLoggerInterceptor().LoggerInterceptor,
+			loggerInterceptor.LoggerInterceptor,
 			grpcrecovery.UnaryServerInterceptor(),
-			apiv1.NewGRPCAuthInterceptor(store, secret).AuthenticationInterceptor,
+			rateLimitInterceptor.RateLimitInterceptor,
+			authInterceptor.AuthenticationInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			loggerInterceptor.LoggerStreamInterceptor,
+			grpcrecovery.StreamServerInterceptor(),
+			authInterceptor.AuthenticationStreamInterceptor,
 		))
 	s.grpcServer = grpcServer
 
 	apiV1Service := apiv1.NewAPIV1Service(s.Secret, profile, store, grpcServer)
+	s.apiV1Service = apiV1Service
+	// The push dispatcher, the notification emails and the mail queue are stopped with the background runners.
+	notificationContext, notificationCancel := context.WithCancel(ctx)
+	s.runnerCancelFuncs = append(s.runnerCancelFuncs, notificationCancel)
+	apiV1Service.StartPushDispatcher(notificationContext)
+	apiV1Service.NotificationEmails.Start(notificationContext)
+	apiV1Service.MailQueue.Start(notificationContext)
 	// Register gRPC gateway as api v1.
 	if err := apiV1Service.RegisterGateway(ctx, echoServer); err != nil {
 		return nil, errors.Wrap(err, "failed to register gRPC gateway")
@@ -201,6 +227,40 @@ func (s *Server) StartBackgroundRunners(ctx context.Context) {
 		slog.Info("s3presign runner stopped")
 	}()
 
+	embeddingContext, embeddingCancel := context.WithCancel(ctx)
+	s.runnerCancelFuncs = append(s.runnerCancelFuncs, embeddingCancel)
+
+	// Start memo embedding runner. Embedding calls the AI provider over the
+	// network, so even the initial pass runs in the background.
+	embeddingRunner := embedding.NewRunner(s.Store)
+	go func() {
+		embeddingRunner.RunOnce(embeddingContext)
+		embeddingRunner.Run(embeddingContext)
+		slog.Info("embedding runner stopped")
+	}()
+
+	sessionGCContext, sessionGCCancel := context.WithCancel(ctx)
+	s.runnerCancelFuncs = append(s.runnerCancelFuncs, sessionGCCancel)
+
+	// Start user session garbage collection runner
+	sessionGCRunner := sessiongc.NewRunner(s.Store, apiv1.SessionSlidingDuration)
+	go func() {
+		sessionGCRunner.RunOnce(sessionGCContext)
+		sessionGCRunner.Run(sessionGCContext)
+		slog.Info("session gc runner stopped")
+	}()
+
+	digestContext, digestCancel := context.WithCancel(ctx)
+	s.runnerCancelFuncs = append(s.runnerCancelFuncs, digestCancel)
+
+	// Start email digest runner. Sending emails goes over the network, so even the initial pass runs in the background.
+	digestRunner := digest.NewRunner(s.Store, s.Profile, s.apiV1Service.SendUserEmail)
+	go func() {
+		digestRunner.RunOnce(digestContext)
+		digestRunner.Run(digestContext)
+		slog.Info("digest runner stopped")
+	}()
+
 	// Log the number of goroutines running
 	slog.Info("background runners started", "goroutines", runtime.NumGoroutine())
 }
@@ -210,11 +270,26 @@ func (s *Server) getOrUpsertWorkspaceBasicSetting(ctx context.Context) (*storepb
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get workspace basic setting")
 	}
+	// The basic setting is cached, so it must not be modified in place.
+	workspaceBasicSetting = proto.Clone(workspaceBasicSetting).(*storepb.WorkspaceBasicSetting)
 	modified := false
 	if workspaceBasicSetting.SecretKey == "" {
 		workspaceBasicSetting.SecretKey = uuid.NewString()
 		modified = true
 	}
+	if len(workspaceBasicSetting.JwtSigningKeys) == 0 {
+		// In prod the existing JWTs are signed with the secret key, which stays the active key until
+		// the first rotation. Elsewhere the server secret is hard-coded, so a random key replaces it.
+		signingKey := &storepb.JWTSigningKey{Id: apiv1.KeyID, Secret: workspaceBasicSetting.SecretKey, CreateTime: timestamppb.Now()}
+		if s.Profile.Mode != "prod" {
+			signingKey, err = apiv1.NewJWTSigningKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to generate signing key")
+			}
+		}
+		workspaceBasicSetting.JwtSigningKeys = []*storepb.JWTSigningKey{signingKey}
+		modified = true
+	}
 	if modified {
 		workspaceSetting, err := s.Store.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
 			Key:   storepb.WorkspaceSettingKey_BASIC,
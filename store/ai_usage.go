@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+// AIUsage is a ledger entry recording a single call to the AI provider.
+type AIUsage struct {
+	ID        int32
+	UserID    int32
+	CreatedTs int64
+	// Model is the name of the model that served the call.
+	Model            string
+	PromptTokens     int32
+	CompletionTokens int32
+	TotalTokens      int32
+}
+
+type FindAIUsage struct {
+	UserID *int32
+	// CreatedTsAfter only matches entries created at or after the given unix timestamp.
+	CreatedTsAfter *int64
+}
+
+// AIUsageStats is the aggregated usage of the entries matching a FindAIUsage.
+type AIUsageStats struct {
+	RequestCount int64
+	TotalTokens  int64
+}
+
+// AIUsageLimit is a quota on the usage matching Find. A zero maximum means no limit.
+type AIUsageLimit struct {
+	Find        *FindAIUsage
+	MaxRequests int64
+	MaxTokens   int64
+	// Quota is the workspace AI quota the limit was built from, if any.
+	Quota *storepb.WorkspaceAISetting_Quota
+}
+
+// ReserveAIUsage records the estimated usage of an AI call before it is made,
+// provided that the usage including it stays within every limit.
+type ReserveAIUsage struct {
+	Usage  *AIUsage
+	Limits []*AIUsageLimit
+}
+
+// AIUsageLimitError is returned by ReserveAIUsage when a reservation would go over a limit.
+type AIUsageLimitError struct {
+	Limit *AIUsageLimit
+}
+
+func (e *AIUsageLimitError) Error() string {
+	return "ai usage limit reached"
+}
+
+// IsExceededBy returns whether the usage stats go over the limit.
+func (l *AIUsageLimit) IsExceededBy(stats *AIUsageStats) bool {
+	return (l.MaxRequests > 0 && stats.RequestCount > l.MaxRequests) ||
+		(l.MaxTokens > 0 && stats.TotalTokens > l.MaxTokens)
+}
+
+// GetAIUsageLimits returns the limits that the user quota and then the workspace quota of the AI setting
+// put on a call made by the user at now. Quotas without a maximum are left out.
+func GetAIUsageLimits(setting *storepb.WorkspaceAISetting, userID int32, now time.Time) []*AIUsageLimit {
+	limits := []*AIUsageLimit{}
+	addLimit := func(quota *storepb.WorkspaceAISetting_Quota, userID *int32) {
+		if quota.GetMaxRequests() <= 0 && quota.GetMaxTokens() <= 0 {
+			return
+		}
+		windowStart, _ := GetAIQuotaWindow(quota, now)
+		windowStartTs := windowStart.Unix()
+		limits = append(limits, &AIUsageLimit{
+			Find:        &FindAIUsage{UserID: userID, CreatedTsAfter: &windowStartTs},
+			MaxRequests: quota.MaxRequests,
+			MaxTokens:   quota.MaxTokens,
+			Quota:       quota,
+		})
+	}
+	addLimit(setting.GetUserQuota(), &userID)
+	addLimit(setting.GetWorkspaceQuota(), nil)
+	return limits
+}
+
+// GetAIQuotaWindowType returns the window of the quota, which defaults to daily.
+func GetAIQuotaWindowType(quota *storepb.WorkspaceAISetting_Quota) storepb.WorkspaceAISetting_Quota_Window {
+	if quota.GetWindow() == storepb.WorkspaceAISetting_Quota_MONTHLY {
+		return storepb.WorkspaceAISetting_Quota_MONTHLY
+	}
+	return storepb.WorkspaceAISetting_Quota_DAILY
+}
+
+// GetAIQuotaWindow returns the bounds of the quota window containing now, in UTC.
+func GetAIQuotaWindow(quota *storepb.WorkspaceAISetting_Quota, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	if GetAIQuotaWindowType(quota) == storepb.WorkspaceAISetting_Quota_MONTHLY {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// UpdateAIUsage settles a reserved entry with the actual usage of the call.
+type UpdateAIUsage struct {
+	ID               int32
+	Model            *string
+	PromptTokens     *int32
+	CompletionTokens *int32
+	TotalTokens      *int32
+}
+
+type DeleteAIUsage struct {
+	// ID limits the deletion to a single entry.
+	ID *int32
+	// UserID limits the deletion to a single user. Nil deletes the entries of all users.
+	UserID *int32
+}
+
+// CreateAIUsage records an AI usage entry. A zero CreatedTs defaults to the current time.
+func (s *Store) CreateAIUsage(ctx context.Context, create *AIUsage) (*AIUsage, error) {
+	return s.driver.CreateAIUsage(ctx, create)
+}
+
+// ReserveAIUsage records the reserved usage, or returns an *AIUsageLimitError if a limit would be exceeded.
+// The limits are checked and the usage recorded in one transaction, so that concurrent reservations
+// cannot go over a limit together.
+func (s *Store) ReserveAIUsage(ctx context.Context, reserve *ReserveAIUsage) (*AIUsage, error) {
+	return s.driver.ReserveAIUsage(ctx, reserve)
+}
+
+func (s *Store) GetAIUsageStats(ctx context.Context, find *FindAIUsage) (*AIUsageStats, error) {
+	return s.driver.GetAIUsageStats(ctx, find)
+}
+
+func (s *Store) UpdateAIUsage(ctx context.Context, update *UpdateAIUsage) error {
+	return s.driver.UpdateAIUsage(ctx, update)
+}
+
+func (s *Store) DeleteAIUsage(ctx context.Context, delete *DeleteAIUsage) error {
+	return s.driver.DeleteAIUsage(ctx, delete)
+}
@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/store"
+)
+
+const (
+	// aiUsageLockName is the named lock reservations hold while checking the limits.
+	aiUsageLockName = "wekalist_ai_usage"
+	// aiUsageLockTimeout is how many seconds a reservation waits for the lock.
+	aiUsageLockTimeout = 10
+)
+
+// aiUsageQuerier runs the AI usage statements either directly or in a transaction.
+type aiUsageQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (d *DB) CreateAIUsage(ctx context.Context, create *store.AIUsage) (*store.AIUsage, error) {
+	return createAIUsage(ctx, d.db, create)
+}
+
+func (d *DB) ReserveAIUsage(ctx context.Context, reserve *store.ReserveAIUsage) (*store.AIUsage, error) {
+	// A named lock held on the connection makes the reservations one at a time,
+	// as the transaction alone does not keep concurrent ones from passing the check together.
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", aiUsageLockName, aiUsageLockTimeout).Scan(&locked); err != nil {
+		return nil, errors.Wrap(err, "failed to get ai usage lock")
+	}
+	if locked.Int64 != 1 {
+		return nil, errors.New("timed out waiting for the ai usage lock")
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "DO RELEASE_LOCK(?)", aiUsageLockName)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	usage, err := createAIUsage(ctx, tx, reserve.Usage)
+	if err != nil {
+		return nil, err
+	}
+	for _, limit := range reserve.Limits {
+		stats, err := getAIUsageStats(ctx, tx, limit.Find)
+		if err != nil {
+			return nil, err
+		}
+		if limit.IsExceededBy(stats) {
+			return nil, &store.AIUsageLimitError{Limit: limit}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func createAIUsage(ctx context.Context, q aiUsageQuerier, create *store.AIUsage) (*store.AIUsage, error) {
+	fields := []string{"`user_id`", "`model`", "`prompt_tokens`", "`completion_tokens`", "`total_tokens`"}
+	placeholder := []string{"?", "?", "?", "?", "?"}
+	args := []any{create.UserID, create.Model, create.PromptTokens, create.CompletionTokens, create.TotalTokens}
+	if create.CreatedTs != 0 {
+		fields, placeholder, args = append(fields, "`created_ts`"), append(placeholder, "FROM_UNIXTIME(?)"), append(args, create.CreatedTs)
+	}
+
+	stmt := "INSERT INTO `ai_usage` (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ")"
+	result, err := q.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute statement")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last insert id")
+	}
+	create.ID = int32(id)
+
+	if err := q.QueryRowContext(ctx, "SELECT UNIX_TIMESTAMP(`created_ts`) FROM `ai_usage` WHERE `id` = ?", create.ID).Scan(&create.CreatedTs); err != nil {
+		return nil, errors.Wrap(err, "failed to find ai usage")
+	}
+	return create, nil
+}
+
+func (d *DB) GetAIUsageStats(ctx context.Context, find *store.FindAIUsage) (*store.AIUsageStats, error) {
+	return getAIUsageStats(ctx, d.db, find)
+}
+
+func getAIUsageStats(ctx context.Context, q aiUsageQuerier, find *store.FindAIUsage) (*store.AIUsageStats, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, "UNIX_TIMESTAMP(`created_ts`) >= ?"), append(args, *v)
+	}
+
+	stats := &store.AIUsageStats{}
+	query := "SELECT COUNT(*), COALESCE(SUM(`total_tokens`), 0) FROM `ai_usage` WHERE " + strings.Join(where, " AND ")
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&stats.RequestCount, &stats.TotalTokens); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (d *DB) UpdateAIUsage(ctx context.Context, update *store.UpdateAIUsage) error {
+	set, args := []string{}, []any{}
+	if v := update.Model; v != nil {
+		set, args = append(set, "`model` = ?"), append(args, *v)
+	}
+	if v := update.PromptTokens; v != nil {
+		set, args = append(set, "`prompt_tokens` = ?"), append(args, *v)
+	}
+	if v := update.CompletionTokens; v != nil {
+		set, args = append(set, "`completion_tokens` = ?"), append(args, *v)
+	}
+	if v := update.TotalTokens; v != nil {
+		set, args = append(set, "`total_tokens` = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return errors.New("no fields to update")
+	}
+	args = append(args, update.ID)
+
+	_, err := d.db.ExecContext(ctx, "UPDATE `ai_usage` SET "+strings.Join(set, ", ")+" WHERE `id` = ?", args...)
+	return err
+}
+
+func (d *DB) DeleteAIUsage(ctx context.Context, delete *store.DeleteAIUsage) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "`id` = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *v)
+	}
+	_, err := d.db.ExecContext(ctx, "DELETE FROM `ai_usage` WHERE "+strings.Join(where, " AND "), args...)
+	return err
+}
@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) UpsertMemoEmbedding(ctx context.Context, upsert *store.MemoEmbedding) (*store.MemoEmbedding, error) {
+	stmt := "INSERT INTO `memo_embedding` (`memo_id`, `model`, `content_hash`, `embedding`) VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE `model` = VALUES(`model`), `content_hash` = VALUES(`content_hash`), `embedding` = VALUES(`embedding`), `updated_ts` = CURRENT_TIMESTAMP"
+	if _, err := d.db.ExecContext(ctx, stmt, upsert.MemoID, upsert.Model, upsert.ContentHash, store.EncodeEmbedding(upsert.Embedding)); err != nil {
+		return nil, err
+	}
+
+	list, err := d.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{MemoID: &upsert.MemoID})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, errors.Errorf("failed to upsert memo embedding")
+	}
+	return list[0], nil
+}
+
+func (d *DB) ListMemoEmbeddings(ctx context.Context, find *store.FindMemoEmbedding) ([]*store.MemoEmbedding, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "`memo_id` = ?"), append(args, *v)
+	}
+	if v := find.MemoIDList; len(v) != 0 {
+		placeholder := []string{}
+		for _, id := range v {
+			placeholder = append(placeholder, "?")
+			args = append(args, id)
+		}
+		where = append(where, fmt.Sprintf("`memo_id` in (%s)", strings.Join(placeholder, ",")))
+	}
+	if v := find.Model; v != nil {
+		where, args = append(where, "`model` = ?"), append(args, *v)
+	}
+
+	rows, err := d.db.QueryContext(ctx, "SELECT `memo_id`, `model`, `content_hash`, `embedding`, UNIX_TIMESTAMP(`updated_ts`) FROM `memo_embedding` WHERE "+strings.Join(where, " AND ")+" ORDER BY `memo_id` ASC",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.MemoEmbedding{}
+	for rows.Next() {
+		memoEmbedding := &store.MemoEmbedding{}
+		var embedding []byte
+		if err := rows.Scan(
+			&memoEmbedding.MemoID,
+			&memoEmbedding.Model,
+			&memoEmbedding.ContentHash,
+			&embedding,
+			&memoEmbedding.UpdatedTs,
+		); err != nil {
+			return nil, err
+		}
+		if memoEmbedding.Embedding, err = store.DecodeEmbedding(embedding); err != nil {
+			return nil, err
+		}
+		list = append(list, memoEmbedding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) DeleteMemoEmbedding(ctx context.Context, delete *store.DeleteMemoEmbedding) error {
+	_, err := d.db.ExecContext(ctx, "DELETE FROM `memo_embedding` WHERE `memo_id` = ?", delete.MemoID)
+	return err
+}
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) UpsertOTP(ctx context.Context, upsert *store.OTP) (*store.OTP, error) {
+	stmt := "INSERT INTO `otp` (`purpose`, `identifier`, `code_hash`, `attempts`, `created_ts`, `expires_ts`) VALUES (?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?)) " +
+		"ON DUPLICATE KEY UPDATE `code_hash` = VALUES(`code_hash`), `attempts` = VALUES(`attempts`), `created_ts` = VALUES(`created_ts`), `expires_ts` = VALUES(`expires_ts`)"
+	if _, err := d.db.ExecContext(ctx, stmt, upsert.Purpose, upsert.Identifier, upsert.CodeHash, upsert.Attempts, upsert.CreatedTs, upsert.ExpiresTs); err != nil {
+		return nil, err
+	}
+
+	list, err := d.ListOTPs(ctx, &store.FindOTP{Purpose: &upsert.Purpose, Identifier: &upsert.Identifier})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, errors.Errorf("failed to upsert otp")
+	}
+	return list[0], nil
+}
+
+func (d *DB) ListOTPs(ctx context.Context, find *store.FindOTP) ([]*store.OTP, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.Purpose; v != nil {
+		where, args = append(where, "`purpose` = ?"), append(args, *v)
+	}
+	if v := find.Identifier; v != nil {
+		where, args = append(where, "`identifier` = ?"), append(args, *v)
+	}
+
+	query := "SELECT `id`, `purpose`, `identifier`, `code_hash`, `attempts`, UNIX_TIMESTAMP(`created_ts`), UNIX_TIMESTAMP(`expires_ts`) FROM `otp` WHERE " + strings.Join(where, " AND ") + " ORDER BY `id` ASC"
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.OTP{}
+	for rows.Next() {
+		otp := &store.OTP{}
+		if err := rows.Scan(
+			&otp.ID,
+			&otp.Purpose,
+			&otp.Identifier,
+			&otp.CodeHash,
+			&otp.Attempts,
+			&otp.CreatedTs,
+			&otp.ExpiresTs,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, otp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) CountOTPAttempt(ctx context.Context, id int32, maxAttempts int32) (bool, error) {
+	result, err := d.db.ExecContext(ctx, "UPDATE `otp` SET `attempts` = `attempts` + 1 WHERE `id` = ? AND `attempts` < ?", id, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (d *DB) DeleteOTP(ctx context.Context, delete *store.DeleteOTP) (bool, error) {
+	result, err := d.db.ExecContext(ctx, "DELETE FROM `otp` WHERE `id` = ?", delete.ID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (d *DB) DeleteExpiredOTPs(ctx context.Context, delete *store.DeleteExpiredOTP) (int64, error) {
+	result, err := d.db.ExecContext(ctx, "DELETE FROM `otp` WHERE `purpose` = ? AND `expires_ts` < ?", delete.Purpose, delete.ExpiresTsBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
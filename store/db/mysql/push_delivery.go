@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) CreatePushDelivery(ctx context.Context, create *store.PushDelivery) (*store.PushDelivery, error) {
+	fields := []string{"`user_id`", "`endpoint`", "`topic`", "`status`", "`status_code`", "`attempts`", "`error`"}
+	placeholder := []string{"?", "?", "?", "?", "?", "?", "?"}
+	args := []any{create.UserID, create.Endpoint, create.Topic, create.Status, create.StatusCode, create.Attempts, create.Error}
+
+	stmt := "INSERT INTO `push_delivery` (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ")"
+	result, err := d.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute statement")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last insert id")
+	}
+	create.ID = int32(id)
+
+	if err := d.db.QueryRowContext(ctx, "SELECT UNIX_TIMESTAMP(`created_ts`) FROM `push_delivery` WHERE `id` = ?", create.ID).Scan(&create.CreatedTs); err != nil {
+		return nil, errors.Wrap(err, "failed to find push delivery")
+	}
+	return create, nil
+}
+
+func (d *DB) ListPushDeliveries(ctx context.Context, find *store.FindPushDelivery) ([]*store.PushDelivery, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, "`status` = ?"), append(args, *v)
+	}
+
+	query := "SELECT `id`, UNIX_TIMESTAMP(`created_ts`), `user_id`, `endpoint`, `topic`, `status`, `status_code`, `attempts`, `error` FROM `push_delivery` WHERE " + strings.Join(where, " AND ") + " ORDER BY `created_ts` DESC, `id` DESC"
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+		if find.Offset != nil {
+			query = fmt.Sprintf("%s OFFSET %d", query, *find.Offset)
+		}
+	}
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.PushDelivery{}
+	for rows.Next() {
+		pushDelivery := &store.PushDelivery{}
+		if err := rows.Scan(
+			&pushDelivery.ID,
+			&pushDelivery.CreatedTs,
+			&pushDelivery.UserID,
+			&pushDelivery.Endpoint,
+			&pushDelivery.Topic,
+			&pushDelivery.Status,
+			&pushDelivery.StatusCode,
+			&pushDelivery.Attempts,
+			&pushDelivery.Error,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, pushDelivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) DeletePushDeliveries(ctx context.Context, delete *store.DeletePushDelivery) (int64, error) {
+	where, args := []string{}, []any{}
+	if v := delete.CreatedTsBefore; v != nil {
+		where, args = append(where, "UNIX_TIMESTAMP(`created_ts`) < ?"), append(args, *v)
+	}
+	if len(where) == 0 {
+		return 0, errors.New("no conditions to delete")
+	}
+
+	result, err := d.db.ExecContext(ctx, "DELETE FROM `push_delivery` WHERE "+strings.Join(where, " AND "), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
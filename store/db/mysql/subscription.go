@@ -61,14 +61,14 @@ func (j JSONMap) Value() (driver.Value, error) {
 func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription) (*store.Subscription, error) {
 	// Convert Keys to JSONMap for proper handling
 	jsonKeys := JSONMap(create.Keys)
-	
-	fields := []string{"`username`", "`email`", "`keys`", "`endpoint`"}
-	placeholder := []string{"?", "?", "?", "?"}
-	args := []any{create.Username, create.Email, jsonKeys, create.Endpoint}
+
+	fields := []string{"`user_id`", "`keys`", "`endpoint`"}
+	placeholder := []string{"?", "?", "?"}
+	args := []any{create.UserID, jsonKeys, create.Endpoint}
 
 	// MySQL doesn't support RETURNING - use INSERT and then get LAST_INSERT_ID
 	stmt := "INSERT INTO `subscription` (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ")"
-	
+
 	result, err := d.db.ExecContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
@@ -83,13 +83,13 @@ func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription)
 	// Convert int64 to string for ID
 	idStr := fmt.Sprintf("%d", lastInsertID)
 	create.ID = &idStr
-	
+
 	return create, nil
 }
 
 func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription) ([]*store.Subscription, error) {
 	where, args := []string{"1 = 1"}, []any{}
-	
+
 	if find != nil {
 		if find.ID != nil {
 			where = append(where, "`id` = ?")
@@ -99,13 +99,13 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 			where = append(where, "`endpoint` = ?")
 			args = append(args, *find.Endpoint)
 		}
-		if find.Username != nil {
-			where = append(where, "`username` = ?")
-			args = append(args, *find.Username)
+		if find.UserID != nil {
+			where = append(where, "`user_id` = ?")
+			args = append(args, *find.UserID)
 		}
 	}
 
-	query := "SELECT `id`, `endpoint`, `username`, `email`, `keys` FROM `subscription` WHERE " + strings.Join(where, " AND ")
+	query := "SELECT `id`, `endpoint`, `user_id`, `keys` FROM `subscription` WHERE " + strings.Join(where, " AND ")
 	rows, err := d.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
@@ -116,17 +116,16 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 	for rows.Next() {
 		subscription := &store.Subscription{}
 		var keysJSON JSONMap
-		
+
 		if err := rows.Scan(
 			&subscription.ID,
 			&subscription.Endpoint,
-			&subscription.Username,
-			&subscription.Email,
+			&subscription.UserID,
 			&keysJSON,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan subscription: %w", err)
 		}
-		
+
 		// Convert JSONMap back to map[string]string
 		subscription.Keys = map[string]string(keysJSON)
 		subscriptions = append(subscriptions, subscription)
@@ -140,19 +139,19 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 }
 
 func (d *DB) DeleteSubscription(ctx context.Context, delete *store.DeleteSubscription) error {
-	result, err := d.db.ExecContext(ctx, "DELETE FROM `subscription` WHERE `username` = ?", delete.Username)
-	if err != nil {
-		return fmt.Errorf("failed to delete subscription: %w", err)
+	where, args := []string{}, []any{}
+	if delete.UserID != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *delete.UserID)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+	if delete.Endpoint != nil {
+		where, args = append(where, "`endpoint` = ?"), append(args, *delete.Endpoint)
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no subscription found for username: %s", delete.Username)
+	if len(where) == 0 {
+		return fmt.Errorf("no conditions to delete subscriptions")
 	}
 
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM `subscription` WHERE "+strings.Join(where, " AND "), args...); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) UpsertMemoEmbedding(ctx context.Context, upsert *store.MemoEmbedding) (*store.MemoEmbedding, error) {
+	stmt := `
+		INSERT INTO memo_embedding (
+			memo_id, model, content_hash, embedding
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(memo_id) DO UPDATE
+		SET model = EXCLUDED.model, content_hash = EXCLUDED.content_hash, embedding = EXCLUDED.embedding, updated_ts = EXTRACT(EPOCH FROM NOW())::BIGINT
+		RETURNING updated_ts
+	`
+	if err := d.db.QueryRowContext(ctx, stmt, upsert.MemoID, upsert.Model, upsert.ContentHash, store.EncodeEmbedding(upsert.Embedding)).Scan(
+		&upsert.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	memoEmbedding := upsert
+	return memoEmbedding, nil
+}
+
+func (d *DB) ListMemoEmbeddings(ctx context.Context, find *store.FindMemoEmbedding) ([]*store.MemoEmbedding, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "memo_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.MemoIDList; len(v) != 0 {
+		holders := []string{}
+		for _, id := range v {
+			holders = append(holders, placeholder(len(args)+1))
+			args = append(args, id)
+		}
+		where = append(where, fmt.Sprintf("memo_id in (%s)", strings.Join(holders, ", ")))
+	}
+	if v := find.Model; v != nil {
+		where, args = append(where, "model = "+placeholder(len(args)+1)), append(args, *v)
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT
+			memo_id,
+			model,
+			content_hash,
+			embedding,
+			updated_ts
+		FROM memo_embedding
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY memo_id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.MemoEmbedding{}
+	for rows.Next() {
+		memoEmbedding := &store.MemoEmbedding{}
+		var embedding []byte
+		if err := rows.Scan(
+			&memoEmbedding.MemoID,
+			&memoEmbedding.Model,
+			&memoEmbedding.ContentHash,
+			&embedding,
+			&memoEmbedding.UpdatedTs,
+		); err != nil {
+			return nil, err
+		}
+		if memoEmbedding.Embedding, err = store.DecodeEmbedding(embedding); err != nil {
+			return nil, err
+		}
+		list = append(list, memoEmbedding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) DeleteMemoEmbedding(ctx context.Context, delete *store.DeleteMemoEmbedding) error {
+	_, err := d.db.ExecContext(ctx, "DELETE FROM memo_embedding WHERE memo_id = $1", delete.MemoID)
+	return err
+}
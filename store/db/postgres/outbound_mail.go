@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) CreateOutboundMail(ctx context.Context, create *store.OutboundMail) (*store.OutboundMail, error) {
+	messageString := "{}"
+	if create.Message != nil {
+		bytes, err := protojson.Marshal(create.Message)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal outbound mail message")
+		}
+		messageString = string(bytes)
+	}
+
+	fields := []string{"status", "message", "attempts", "next_attempt_ts", "error"}
+	args := []any{create.Status, messageString, create.Attempts, create.NextAttemptTs, create.Error}
+
+	stmt := "INSERT INTO outbound_mail (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(args)) + ") RETURNING id, created_ts"
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
+		&create.ID,
+		&create.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListOutboundMails(ctx context.Context, find *store.FindOutboundMail) ([]*store.OutboundMail, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, "status = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.DueTs; v != nil {
+		where, args = append(where, "next_attempt_ts <= "+placeholder(len(args)+1)), append(args, *v)
+	}
+
+	query := "SELECT id, created_ts, status, message, attempts, next_attempt_ts, error FROM outbound_mail WHERE " + strings.Join(where, " AND ") + " ORDER BY next_attempt_ts ASC, id ASC"
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+	}
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.OutboundMail{}
+	for rows.Next() {
+		outboundMail := &store.OutboundMail{}
+		var messageBytes []byte
+		if err := rows.Scan(
+			&outboundMail.ID,
+			&outboundMail.CreatedTs,
+			&outboundMail.Status,
+			&messageBytes,
+			&outboundMail.Attempts,
+			&outboundMail.NextAttemptTs,
+			&outboundMail.Error,
+		); err != nil {
+			return nil, err
+		}
+		message := &storepb.OutboundMailMessage{}
+		if err := protojsonUnmarshaler.Unmarshal(messageBytes, message); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal outbound mail message")
+		}
+		outboundMail.Message = message
+		list = append(list, outboundMail)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) UpdateOutboundMail(ctx context.Context, update *store.UpdateOutboundMail) error {
+	set, args := []string{}, []any{}
+	if v := update.Status; v != nil {
+		set, args = append(set, "status = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Attempts; v != nil {
+		set, args = append(set, "attempts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.NextAttemptTs; v != nil {
+		set, args = append(set, "next_attempt_ts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Error; v != nil {
+		set, args = append(set, "error = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return errors.New("no fields to update")
+	}
+	args = append(args, update.ID)
+
+	_, err := d.db.ExecContext(ctx, "UPDATE outbound_mail SET "+strings.Join(set, ", ")+" WHERE id = "+placeholder(len(args)), args...)
+	return err
+}
+
+func (d *DB) DeleteOutboundMails(ctx context.Context, delete *store.DeleteOutboundMail) (int64, error) {
+	where, args := []string{}, []any{}
+	if v := delete.Status; v != nil {
+		where, args = append(where, "status = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := delete.CreatedTsBefore; v != nil {
+		where, args = append(where, "created_ts < "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if len(where) == 0 {
+		return 0, errors.New("no conditions to delete")
+	}
+
+	result, err := d.db.ExecContext(ctx, "DELETE FROM outbound_mail WHERE "+strings.Join(where, " AND "), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
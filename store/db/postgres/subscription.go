@@ -61,12 +61,12 @@ func (j JSONMap) Value() (driver.Value, error) {
 func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription) (*store.Subscription, error) {
 	// Convert Keys to JSONMap for proper handling
 	jsonKeys := JSONMap(create.Keys)
-	
-	fields := []string{"username", "email", "keys", "endpoint"}
-	
+
+	fields := []string{"user_id", "keys", "endpoint"}
+
 	// PostgreSQL uses $1, $2, etc. for placeholders
-	placeholder := []string{"$1", "$2", "$3", "$4"}
-	args := []any{create.Username, create.Email, jsonKeys, create.Endpoint}
+	placeholder := []string{"$1", "$2", "$3"}
+	args := []any{create.UserID, jsonKeys, create.Endpoint}
 
 	stmt := "INSERT INTO subscription (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ") RETURNING id"
 	var id string
@@ -82,7 +82,7 @@ func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription)
 func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription) ([]*store.Subscription, error) {
 	where, args := []string{"1 = 1"}, []any{}
 	argIndex := 1
-	
+
 	if find != nil {
 		if find.ID != nil {
 			where = append(where, fmt.Sprintf("id = $%d", argIndex))
@@ -94,14 +94,14 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 			args = append(args, *find.Endpoint)
 			argIndex++
 		}
-		if find.Username != nil {
-			where = append(where, fmt.Sprintf("username = $%d", argIndex))
-			args = append(args, *find.Username)
+		if find.UserID != nil {
+			where = append(where, fmt.Sprintf("user_id = $%d", argIndex))
+			args = append(args, *find.UserID)
 			argIndex++
 		}
 	}
 
-	query := "SELECT id, endpoint, username, email, keys FROM subscription WHERE " + strings.Join(where, " AND ")
+	query := "SELECT id, endpoint, user_id, keys FROM subscription WHERE " + strings.Join(where, " AND ")
 	rows, err := d.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
@@ -112,17 +112,16 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 	for rows.Next() {
 		subscription := &store.Subscription{}
 		var keysJSON JSONMap
-		
+
 		if err := rows.Scan(
 			&subscription.ID,
 			&subscription.Endpoint,
-			&subscription.Username,
-			&subscription.Email,
+			&subscription.UserID,
 			&keysJSON,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan subscription: %w", err)
 		}
-		
+
 		// Convert JSONMap back to map[string]string
 		subscription.Keys = map[string]string(keysJSON)
 		subscriptions = append(subscriptions, subscription)
@@ -136,21 +135,19 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 }
 
 func (d *DB) DeleteSubscription(ctx context.Context, delete *store.DeleteSubscription) error {
-	result, err := d.db.ExecContext(ctx, `
-		DELETE FROM subscription WHERE username = $1
-	`, delete.Username)
-	if err != nil {
-		return fmt.Errorf("failed to delete subscription: %w", err)
+	where, args := []string{}, []any{}
+	if delete.UserID != nil {
+		where, args = append(where, "user_id = "+placeholder(len(args)+1)), append(args, *delete.UserID)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+	if delete.Endpoint != nil {
+		where, args = append(where, "endpoint = "+placeholder(len(args)+1)), append(args, *delete.Endpoint)
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no subscription found for username: %s", delete.Username)
+	if len(where) == 0 {
+		return fmt.Errorf("no conditions to delete subscriptions")
 	}
 
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM subscription WHERE "+strings.Join(where, " AND "), args...); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
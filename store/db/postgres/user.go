@@ -170,11 +170,14 @@ func (d *DB) DeleteUser(ctx context.Context, delete *store.DeleteUser) error {
 
 	// Delete other related records if they exist
 	// Add more DELETE statements here for other tables that reference the user
-	// For example:
-	// _, err = tx.ExecContext(ctx, `DELETE FROM "user_sessions" WHERE user_id = $1`, delete.ID)
-	// if err != nil {
-	//     return fmt.Errorf("failed to delete user sessions: %w", err)
-	// }
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_session WHERE user_id = $1`, delete.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM subscription WHERE user_id = $1`, delete.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user subscriptions: %w", err)
+	}
 
 	// Finally, delete the user
 	result, err := tx.ExecContext(ctx, `DELETE FROM "user" WHERE id = $1`, delete.ID)
@@ -192,4 +195,4 @@ func (d *DB) DeleteUser(ctx context.Context, delete *store.DeleteUser) error {
 
 	// Commit the transaction
 	return tx.Commit()
-}
\ No newline at end of file
+}
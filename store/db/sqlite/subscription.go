@@ -61,21 +61,20 @@ func (j JSONMap) Value() (driver.Value, error) {
 func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription) (*store.Subscription, error) {
 	// Convert Keys to JSONMap for proper handling
 	jsonKeys := JSONMap(create.Keys)
-	
-	fields := []string{"`endpoint`", "`username`", "`email`", "`keys`"}
-	placeholder := []string{"?", "?", "?", "?"}
-	args := []any{create.Endpoint, create.Username, create.Email, jsonKeys}
 
-	stmt := "INSERT INTO subscription (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ") RETURNING `id`, `endpoint`, `username`, `email`, `keys`"
-	
+	fields := []string{"`endpoint`", "`user_id`", "`keys`"}
+	placeholder := []string{"?", "?", "?"}
+	args := []any{create.Endpoint, create.UserID, jsonKeys}
+
+	stmt := "INSERT INTO subscription (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ") RETURNING `id`, `endpoint`, `user_id`, `keys`"
+
 	var id string
 	var keysJSON JSONMap
-	
+
 	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
 		&id,
 		&create.Endpoint,
-		&create.Username,
-		&create.Email,
+		&create.UserID,
 		&keysJSON,
 	); err != nil {
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
@@ -88,7 +87,7 @@ func (d *DB) CreateSubscription(ctx context.Context, create *store.Subscription)
 
 func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription) ([]*store.Subscription, error) {
 	where, args := []string{"1 = 1"}, []any{}
-	
+
 	if find != nil {
 		if find.ID != nil {
 			where, args = append(where, "`id` = ?"), append(args, *find.ID)
@@ -96,12 +95,12 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 		if find.Endpoint != nil {
 			where, args = append(where, "`endpoint` = ?"), append(args, *find.Endpoint)
 		}
-		if find.Username != nil {
-			where, args = append(where, "`username` = ?"), append(args, *find.Username)
+		if find.UserID != nil {
+			where, args = append(where, "`user_id` = ?"), append(args, *find.UserID)
 		}
 	}
 
-	query := "SELECT `id`, `endpoint`, `username`, `email`, `keys` FROM `subscription` WHERE " + strings.Join(where, " AND ")
+	query := "SELECT `id`, `endpoint`, `user_id`, `keys` FROM `subscription` WHERE " + strings.Join(where, " AND ")
 	rows, err := d.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
@@ -112,17 +111,16 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 	for rows.Next() {
 		subscription := &store.Subscription{}
 		var keysJSON JSONMap
-		
+
 		if err := rows.Scan(
 			&subscription.ID,
 			&subscription.Endpoint,
-			&subscription.Username,
-			&subscription.Email,
+			&subscription.UserID,
 			&keysJSON,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan subscription: %w", err)
 		}
-		
+
 		// Convert JSONMap back to map[string]string
 		subscription.Keys = map[string]string(keysJSON)
 		subscriptions = append(subscriptions, subscription)
@@ -136,21 +134,19 @@ func (d *DB) ListSubscriptions(ctx context.Context, find *store.FindSubscription
 }
 
 func (d *DB) DeleteSubscription(ctx context.Context, delete *store.DeleteSubscription) error {
-	result, err := d.db.ExecContext(ctx, `
-		DELETE FROM subscription WHERE username = ?
-	`, delete.Username)
-	if err != nil {
-		return fmt.Errorf("failed to delete subscription: %w", err)
+	where, args := []string{}, []any{}
+	if delete.UserID != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *delete.UserID)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+	if delete.Endpoint != nil {
+		where, args = append(where, "`endpoint` = ?"), append(args, *delete.Endpoint)
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no subscription found for username: %s", delete.Username)
+	if len(where) == 0 {
+		return fmt.Errorf("no conditions to delete subscriptions")
 	}
 
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM `subscription` WHERE "+strings.Join(where, " AND "), args...); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -103,7 +103,7 @@ func (d *DB) ListUsers(ctx context.Context, find *store.FindUser) ([]*store.User
 	}
 
 	orderBy := []string{"u.created_ts DESC", "u.row_status DESC"}
-	
+
 	// Use LEFT JOIN to include users even if they don't have a GENERAL setting
 	// Use COALESCE to handle NULL values from the LEFT JOIN
 	query := `
@@ -123,10 +123,10 @@ func (d *DB) ListUsers(ctx context.Context, find *store.FindUser) ([]*store.User
 		FROM user u 
 		LEFT JOIN user_setting us ON u.id = us.user_id AND us.key = ?
 		WHERE ` + strings.Join(where, " AND ") + ` ORDER BY ` + strings.Join(orderBy, ", ")
-	
+
 	// Add the "GENERAL" parameter at the beginning of args for the LEFT JOIN condition
 	finalArgs := append([]any{"GENERAL"}, args...)
-	
+
 	if v := find.Limit; v != nil {
 		query += fmt.Sprintf(" LIMIT %d", *v)
 	}
@@ -166,7 +166,6 @@ func (d *DB) ListUsers(ctx context.Context, find *store.FindUser) ([]*store.User
 	return list, nil
 }
 
-
 func (d *DB) DeleteUser(ctx context.Context, delete *store.DeleteUser) error {
 	// Start a transaction
 	tx, err := d.db.BeginTx(ctx, nil)
@@ -183,11 +182,14 @@ func (d *DB) DeleteUser(ctx context.Context, delete *store.DeleteUser) error {
 
 	// Delete other related records if they exist
 	// Add more DELETE statements here for other tables that reference the user
-	// For example:
-	// _, err = tx.ExecContext(ctx, `DELETE FROM "user_sessions" WHERE user_id = $1`, delete.ID)
-	// if err != nil {
-	//     return fmt.Errorf("failed to delete user sessions: %w", err)
-	// }
+	_, err = tx.ExecContext(ctx, `DELETE FROM "user_session" WHERE user_id = ?`, delete.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM subscription WHERE user_id = ?`, delete.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user subscriptions: %w", err)
+	}
 
 	// Finally, delete the user
 	result, err := tx.ExecContext(ctx, `DELETE FROM "user" WHERE id = ?`, delete.ID)
@@ -205,4 +207,4 @@ func (d *DB) DeleteUser(ctx context.Context, delete *store.DeleteUser) error {
 
 	// Commit the transaction
 	return tx.Commit()
-}
\ No newline at end of file
+}
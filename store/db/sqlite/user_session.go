@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+func (d *DB) CreateUserSession(ctx context.Context, create *store.UserSession) (*store.UserSession, error) {
+	clientInfoString := "{}"
+	if create.ClientInfo != nil {
+		bytes, err := protojson.Marshal(create.ClientInfo)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal client info")
+		}
+		clientInfoString = string(bytes)
+	}
+
+	stmt := "INSERT INTO `user_session` (`id`, `user_id`, `created_ts`, `last_accessed_ts`, `expires_ts`, `client_info`) VALUES (?, ?, ?, ?, ?, ?)"
+	if _, err := d.db.ExecContext(ctx, stmt, create.ID, create.UserID, create.CreatedTs, create.LastAccessedTs, create.ExpiresTs, clientInfoString); err != nil {
+		return nil, err
+	}
+
+	userSession := create
+	return userSession, nil
+}
+
+func (d *DB) ListUserSessions(ctx context.Context, find *store.FindUserSession) ([]*store.UserSession, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "`id` = ?"), append(args, *v)
+	}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *v)
+	}
+
+	query := "SELECT `id`, `user_id`, `created_ts`, `last_accessed_ts`, `expires_ts`, `client_info` FROM `user_session` WHERE " + strings.Join(where, " AND ") + " ORDER BY `last_accessed_ts` DESC"
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.UserSession{}
+	for rows.Next() {
+		userSession := &store.UserSession{}
+		var clientInfoBytes []byte
+		if err := rows.Scan(
+			&userSession.ID,
+			&userSession.UserID,
+			&userSession.CreatedTs,
+			&userSession.LastAccessedTs,
+			&userSession.ExpiresTs,
+			&clientInfoBytes,
+		); err != nil {
+			return nil, err
+		}
+
+		clientInfo := &storepb.SessionsUserSetting_ClientInfo{}
+		if err := protojsonUnmarshaler.Unmarshal(clientInfoBytes, clientInfo); err != nil {
+			return nil, err
+		}
+		userSession.ClientInfo = clientInfo
+		list = append(list, userSession)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) UpdateUserSession(ctx context.Context, update *store.UpdateUserSession) error {
+	set, args := []string{}, []any{}
+	if v := update.LastAccessedTs; v != nil {
+		set, args = append(set, "`last_accessed_ts` = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return errors.New("no fields to update")
+	}
+	args = append(args, update.ID)
+
+	_, err := d.db.ExecContext(ctx, "UPDATE `user_session` SET "+strings.Join(set, ", ")+" WHERE `id` = ?", args...)
+	return err
+}
+
+func (d *DB) DeleteUserSessions(ctx context.Context, delete *store.DeleteUserSession) (int64, error) {
+	where, args := []string{}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "`id` = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "`user_id` = ?"), append(args, *v)
+	}
+	if v := delete.ExpiresBeforeTs; v != nil {
+		where, args = append(where, "`expires_ts` < ?"), append(args, *v)
+	}
+	if v := delete.LastAccessedBeforeTs; v != nil {
+		where, args = append(where, "`last_accessed_ts` < ?"), append(args, *v)
+	}
+	if len(where) == 0 {
+		return 0, errors.New("no conditions to delete")
+	}
+
+	result, err := d.db.ExecContext(ctx, "DELETE FROM `user_session` WHERE "+strings.Join(where, " AND "), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
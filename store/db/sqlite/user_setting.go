@@ -66,3 +66,27 @@ func (d *DB) ListUserSettings(ctx context.Context, find *store.FindUserSetting)
 
 	return userSettingList, nil
 }
+
+func (d *DB) CreateUserSetting(ctx context.Context, create *store.UserSetting) (bool, error) {
+	result, err := d.db.ExecContext(ctx, "INSERT INTO user_setting (user_id, key, value) VALUES (?, ?, ?) ON CONFLICT(user_id, key) DO NOTHING", create.UserID, create.Key.String(), create.Value)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (d *DB) UpdateUserSetting(ctx context.Context, update *store.UpdateUserSetting) (bool, error) {
+	result, err := d.db.ExecContext(ctx, "UPDATE user_setting SET value = ? WHERE user_id = ? AND key = ? AND value = ?", update.Value, update.UserID, update.Key.String(), update.OldValue)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
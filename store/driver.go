@@ -33,6 +33,25 @@ type Driver interface {
 	UpdateMemo(ctx context.Context, update *UpdateMemo) error
 	DeleteMemo(ctx context.Context, delete *DeleteMemo) error
 
+	// MemoEmbedding model related methods.
+	UpsertMemoEmbedding(ctx context.Context, upsert *MemoEmbedding) (*MemoEmbedding, error)
+	ListMemoEmbeddings(ctx context.Context, find *FindMemoEmbedding) ([]*MemoEmbedding, error)
+	DeleteMemoEmbedding(ctx context.Context, delete *DeleteMemoEmbedding) error
+
+	// AIUsage model related methods.
+	CreateAIUsage(ctx context.Context, create *AIUsage) (*AIUsage, error)
+	ReserveAIUsage(ctx context.Context, reserve *ReserveAIUsage) (*AIUsage, error)
+	GetAIUsageStats(ctx context.Context, find *FindAIUsage) (*AIUsageStats, error)
+	UpdateAIUsage(ctx context.Context, update *UpdateAIUsage) error
+	DeleteAIUsage(ctx context.Context, delete *DeleteAIUsage) error
+
+	// OTP model related methods.
+	UpsertOTP(ctx context.Context, upsert *OTP) (*OTP, error)
+	ListOTPs(ctx context.Context, find *FindOTP) ([]*OTP, error)
+	CountOTPAttempt(ctx context.Context, id int32, maxAttempts int32) (bool, error)
+	DeleteOTP(ctx context.Context, delete *DeleteOTP) (bool, error)
+	DeleteExpiredOTPs(ctx context.Context, delete *DeleteExpiredOTP) (int64, error)
+
 	// MemoRelation model related methods.
 	UpsertMemoRelation(ctx context.Context, create *MemoRelation) (*MemoRelation, error)
 	ListMemoRelations(ctx context.Context, find *FindMemoRelation) ([]*MemoRelation, error)
@@ -49,9 +68,17 @@ type Driver interface {
 	ListUsers(ctx context.Context, find *FindUser) ([]*User, error)
 	DeleteUser(ctx context.Context, delete *DeleteUser) error
 
+	// UserSession model related methods.
+	CreateUserSession(ctx context.Context, create *UserSession) (*UserSession, error)
+	ListUserSessions(ctx context.Context, find *FindUserSession) ([]*UserSession, error)
+	UpdateUserSession(ctx context.Context, update *UpdateUserSession) error
+	DeleteUserSessions(ctx context.Context, delete *DeleteUserSession) (int64, error)
+
 	// UserSetting model related methods.
 	UpsertUserSetting(ctx context.Context, upsert *UserSetting) (*UserSetting, error)
+	CreateUserSetting(ctx context.Context, create *UserSetting) (bool, error)
 	ListUserSettings(ctx context.Context, find *FindUserSetting) ([]*UserSetting, error)
+	UpdateUserSetting(ctx context.Context, update *UpdateUserSetting) (bool, error)
 
 	// IdentityProvider model related methods.
 	CreateIdentityProvider(ctx context.Context, create *IdentityProvider) (*IdentityProvider, error)
@@ -71,7 +98,18 @@ type Driver interface {
 	DeleteReaction(ctx context.Context, delete *DeleteReaction) error
 
 	//subscription model related methods
-	CreateSubscription(ctx context.Context, create *Subscription)(*Subscription, error)
-	ListSubscriptions(ctx context.Context, find *FindSubscription)([]*Subscription, error)
+	CreateSubscription(ctx context.Context, create *Subscription) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, find *FindSubscription) ([]*Subscription, error)
 	DeleteSubscription(ctx context.Context, delete *DeleteSubscription) error
+
+	// PushDelivery model related methods.
+	CreatePushDelivery(ctx context.Context, create *PushDelivery) (*PushDelivery, error)
+	ListPushDeliveries(ctx context.Context, find *FindPushDelivery) ([]*PushDelivery, error)
+	DeletePushDeliveries(ctx context.Context, delete *DeletePushDelivery) (int64, error)
+
+	// OutboundMail model related methods.
+	CreateOutboundMail(ctx context.Context, create *OutboundMail) (*OutboundMail, error)
+	ListOutboundMails(ctx context.Context, find *FindOutboundMail) ([]*OutboundMail, error)
+	UpdateOutboundMail(ctx context.Context, update *UpdateOutboundMail) error
+	DeleteOutboundMails(ctx context.Context, delete *DeleteOutboundMail) (int64, error)
 }
@@ -165,6 +165,24 @@ func convertIdentityProviderConfigFromRaw(identityProviderType storepb.IdentityP
 			return nil, errors.Wrap(err, "Failed to unmarshal OAuth2Config")
 		}
 		config.Config = &storepb.IdentityProviderConfig_Oauth2Config{Oauth2Config: oauth2Config}
+	} else if identityProviderType == storepb.IdentityProvider_OIDC {
+		oidcConfig := &storepb.OIDCConfig{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw), oidcConfig); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal OIDCConfig")
+		}
+		config.Config = &storepb.IdentityProviderConfig_OidcConfig{OidcConfig: oidcConfig}
+	} else if identityProviderType == storepb.IdentityProvider_LDAP {
+		ldapConfig := &storepb.LDAPConfig{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw), ldapConfig); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal LDAPConfig")
+		}
+		config.Config = &storepb.IdentityProviderConfig_LdapConfig{LdapConfig: ldapConfig}
+	} else if identityProviderType == storepb.IdentityProvider_SAML {
+		samlConfig := &storepb.SAMLConfig{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw), samlConfig); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal SAMLConfig")
+		}
+		config.Config = &storepb.IdentityProviderConfig_SamlConfig{SamlConfig: samlConfig}
 	}
 	return config, nil
 }
@@ -177,6 +195,24 @@ func convertIdentityProviderConfigToRaw(identityProviderType storepb.IdentityPro
 			return "", errors.Wrap(err, "Failed to marshal OAuth2Config")
 		}
 		raw = string(bytes)
+	} else if identityProviderType == storepb.IdentityProvider_OIDC {
+		bytes, err := protojson.Marshal(config.GetOidcConfig())
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to marshal OIDCConfig")
+		}
+		raw = string(bytes)
+	} else if identityProviderType == storepb.IdentityProvider_LDAP {
+		bytes, err := protojson.Marshal(config.GetLdapConfig())
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to marshal LDAPConfig")
+		}
+		raw = string(bytes)
+	} else if identityProviderType == storepb.IdentityProvider_SAML {
+		bytes, err := protojson.Marshal(config.GetSamlConfig())
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to marshal SAMLConfig")
+		}
+		raw = string(bytes)
 	}
 	return raw, nil
 }
@@ -56,8 +56,9 @@ type Memo struct {
 }
 
 type FindMemo struct {
-	ID  *int32
-	UID *string
+	ID     *int32
+	UID    *string
+	IDList []int32
 
 	// Standard fields
 	RowStatus *RowStatus
@@ -135,5 +136,9 @@ func (s *Store) UpdateMemo(ctx context.Context, update *UpdateMemo) error {
 }
 
 func (s *Store) DeleteMemo(ctx context.Context, delete *DeleteMemo) error {
-	return s.driver.DeleteMemo(ctx, delete)
+	if err := s.driver.DeleteMemo(ctx, delete); err != nil {
+		return err
+	}
+	// Drop the embedding of the memo so it no longer shows up in semantic search.
+	return s.driver.DeleteMemoEmbedding(ctx, &DeleteMemoEmbedding{MemoID: delete.ID})
 }
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// MemoEmbedding is the vector representation of a memo's content.
+type MemoEmbedding struct {
+	MemoID int32
+	// Model is the name of the embedding model that produced the vector.
+	Model string
+	// ContentHash is the hash of the content the vector was computed from.
+	ContentHash string
+	Embedding   []float32
+	UpdatedTs   int64
+}
+
+type FindMemoEmbedding struct {
+	MemoID     *int32
+	MemoIDList []int32
+	Model      *string
+}
+
+type DeleteMemoEmbedding struct {
+	MemoID int32
+}
+
+func (s *Store) UpsertMemoEmbedding(ctx context.Context, upsert *MemoEmbedding) (*MemoEmbedding, error) {
+	if len(upsert.Embedding) == 0 {
+		return nil, errors.New("embedding is empty")
+	}
+	return s.driver.UpsertMemoEmbedding(ctx, upsert)
+}
+
+func (s *Store) ListMemoEmbeddings(ctx context.Context, find *FindMemoEmbedding) ([]*MemoEmbedding, error) {
+	return s.driver.ListMemoEmbeddings(ctx, find)
+}
+
+func (s *Store) GetMemoEmbedding(ctx context.Context, find *FindMemoEmbedding) (*MemoEmbedding, error) {
+	list, err := s.ListMemoEmbeddings(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (s *Store) DeleteMemoEmbedding(ctx context.Context, delete *DeleteMemoEmbedding) error {
+	return s.driver.DeleteMemoEmbedding(ctx, delete)
+}
+
+// HashMemoContent returns the content hash used to detect stale embeddings.
+func HashMemoContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// EncodeEmbedding encodes the vector as little-endian float32 values for storage.
+func EncodeEmbedding(embedding []float32) []byte {
+	b := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v))
+	}
+	return b
+}
+
+// DecodeEmbedding decodes a vector encoded by EncodeEmbedding.
+func DecodeEmbedding(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.Errorf("invalid embedding length %d", len(b))
+	}
+	embedding := make([]float32, len(b)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return embedding, nil
+}
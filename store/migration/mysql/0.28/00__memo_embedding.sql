@@ -0,0 +1,7 @@
+CREATE TABLE `memo_embedding` (
+  `memo_id` INT NOT NULL PRIMARY KEY,
+  `model` VARCHAR(256) NOT NULL,
+  `content_hash` VARCHAR(64) NOT NULL,
+  `embedding` LONGBLOB NOT NULL,
+  `updated_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,10 @@
+CREATE TABLE `ai_usage` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `user_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `model` VARCHAR(256) NOT NULL DEFAULT '',
+  `prompt_tokens` INT NOT NULL DEFAULT 0,
+  `completion_tokens` INT NOT NULL DEFAULT 0,
+  `total_tokens` INT NOT NULL DEFAULT 0,
+  INDEX `idx_ai_usage_user_id_created_ts` (`user_id`, `created_ts`)
+);
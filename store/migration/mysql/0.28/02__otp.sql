@@ -0,0 +1,10 @@
+CREATE TABLE `otp` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `purpose` VARCHAR(64) NOT NULL,
+  `identifier` VARCHAR(256) NOT NULL,
+  `code_hash` VARCHAR(64) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE(`purpose`,`identifier`)
+);
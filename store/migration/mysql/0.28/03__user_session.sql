@@ -0,0 +1,32 @@
+CREATE TABLE `user_session` (
+  `id` VARCHAR(64) NOT NULL PRIMARY KEY,
+  `user_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `last_accessed_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `client_info` TEXT NOT NULL,
+  INDEX `idx_user_session_user_id` (`user_id`)
+);
+
+-- Carry over the sessions stored in the user settings. They had no absolute expiry, so it counts from their creation.
+-- The RFC 3339 times are in UTC, so they are turned into epoch seconds before FROM_UNIXTIME.
+INSERT INTO `user_session` (`id`, `user_id`, `created_ts`, `last_accessed_ts`, `expires_ts`, `client_info`)
+SELECT `id`, `user_id`, FROM_UNIXTIME(`created_ts`), FROM_UNIXTIME(`last_accessed_ts`), FROM_UNIXTIME(`created_ts` + 2592000), `client_info`
+FROM (
+  SELECT
+    `session`.`session_id` AS `id`,
+    `user_setting`.`user_id` AS `user_id`,
+    TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', STR_TO_DATE(LEFT(`session`.`create_time`, 19), '%Y-%m-%dT%H:%i:%s')) AS `created_ts`,
+    TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', STR_TO_DATE(LEFT(COALESCE(`session`.`last_accessed_time`, `session`.`create_time`), 19), '%Y-%m-%dT%H:%i:%s')) AS `last_accessed_ts`,
+    COALESCE(CAST(`session`.`client_info` AS CHAR), '{}') AS `client_info`
+  FROM `user_setting`, JSON_TABLE(`user_setting`.`value`, '$.sessions[*]' COLUMNS (
+    `session_id` VARCHAR(64) PATH '$.sessionId',
+    `create_time` VARCHAR(64) PATH '$.createTime',
+    `last_accessed_time` VARCHAR(64) PATH '$.lastAccessedTime',
+    `client_info` JSON PATH '$.clientInfo'
+  )) AS `session`
+  WHERE `user_setting`.`key` = 'SESSIONS'
+) AS `sessions`
+WHERE `id` IS NOT NULL AND `created_ts` IS NOT NULL;
+
+DELETE FROM `user_setting` WHERE `key` = 'SESSIONS';
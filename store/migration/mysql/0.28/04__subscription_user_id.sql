@@ -0,0 +1,9 @@
+-- Subscriptions were bound to free-text usernames, which cannot be trusted to identify their owner.
+-- They are dropped, users turn notifications on again to resubscribe.
+DELETE FROM `subscription`;
+
+ALTER TABLE `subscription` ADD COLUMN `user_id` INT NOT NULL DEFAULT 0;
+
+ALTER TABLE `subscription` DROP COLUMN `username`, DROP COLUMN `email`;
+
+CREATE INDEX `idx_subscription_user_id` ON `subscription` (`user_id`);
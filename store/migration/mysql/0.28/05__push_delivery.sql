@@ -0,0 +1,12 @@
+CREATE TABLE `push_delivery` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `user_id` INT NOT NULL,
+  `endpoint` TEXT NOT NULL,
+  `topic` VARCHAR(32) NOT NULL DEFAULT '',
+  `status` VARCHAR(32) NOT NULL,
+  `status_code` INT NOT NULL DEFAULT 0,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `error` TEXT NOT NULL,
+  INDEX `idx_push_delivery_created_ts` (`created_ts`)
+);
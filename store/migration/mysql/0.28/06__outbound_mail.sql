@@ -0,0 +1,10 @@
+CREATE TABLE `outbound_mail` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `status` VARCHAR(32) NOT NULL DEFAULT 'PENDING',
+  `message` LONGTEXT NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `error` TEXT NOT NULL,
+  INDEX `idx_outbound_mail_status_next_attempt_ts` (`status`, `next_attempt_ts`)
+);
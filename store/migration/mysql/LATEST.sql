@@ -125,8 +125,78 @@ CREATE TABLE `reaction` (
 -- subscription
 CREATE TABLE `subscription` (
   `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
-  `username` VARCHAR(256) NOT NULL,
-  `email` VARCHAR(256),
   `endpoint` VARCHAR(256) NOT NULL,
-  `keys` JSON NOT NULL
+  `keys` JSON NOT NULL,
+  `user_id` INT NOT NULL DEFAULT 0,
+  INDEX `idx_subscription_user_id` (`user_id`)
+);
+
+-- memo_embedding
+CREATE TABLE `memo_embedding` (
+  `memo_id` INT NOT NULL PRIMARY KEY,
+  `model` VARCHAR(256) NOT NULL,
+  `content_hash` VARCHAR(64) NOT NULL,
+  `embedding` LONGBLOB NOT NULL,
+  `updated_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ai_usage
+CREATE TABLE `ai_usage` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `user_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `model` VARCHAR(256) NOT NULL DEFAULT '',
+  `prompt_tokens` INT NOT NULL DEFAULT 0,
+  `completion_tokens` INT NOT NULL DEFAULT 0,
+  `total_tokens` INT NOT NULL DEFAULT 0,
+  INDEX `idx_ai_usage_user_id_created_ts` (`user_id`, `created_ts`)
+);
+
+-- otp
+CREATE TABLE `otp` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `purpose` VARCHAR(64) NOT NULL,
+  `identifier` VARCHAR(256) NOT NULL,
+  `code_hash` VARCHAR(64) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE(`purpose`,`identifier`)
+);
+
+-- user_session
+CREATE TABLE `user_session` (
+  `id` VARCHAR(64) NOT NULL PRIMARY KEY,
+  `user_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `last_accessed_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `client_info` TEXT NOT NULL,
+  INDEX `idx_user_session_user_id` (`user_id`)
+);
+
+-- push_delivery
+CREATE TABLE `push_delivery` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `user_id` INT NOT NULL,
+  `endpoint` TEXT NOT NULL,
+  `topic` VARCHAR(32) NOT NULL DEFAULT '',
+  `status` VARCHAR(32) NOT NULL,
+  `status_code` INT NOT NULL DEFAULT 0,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `error` TEXT NOT NULL,
+  INDEX `idx_push_delivery_created_ts` (`created_ts`)
+);
+
+-- outbound_mail
+CREATE TABLE `outbound_mail` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `status` VARCHAR(32) NOT NULL DEFAULT 'PENDING',
+  `message` LONGTEXT NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `error` TEXT NOT NULL,
+  INDEX `idx_outbound_mail_status_next_attempt_ts` (`status`, `next_attempt_ts`)
 );
@@ -0,0 +1,10 @@
+CREATE TABLE otp (
+  id SERIAL PRIMARY KEY,
+  purpose TEXT NOT NULL,
+  identifier TEXT NOT NULL,
+  code_hash TEXT NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  expires_ts BIGINT NOT NULL,
+  UNIQUE(purpose, identifier)
+);
@@ -0,0 +1,27 @@
+CREATE TABLE user_session (
+  id TEXT NOT NULL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  last_accessed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  expires_ts BIGINT NOT NULL,
+  client_info TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_user_session_user_id ON user_session (user_id);
+
+-- Carry over the sessions stored in the user settings. They had no absolute expiry, so it counts from their creation.
+INSERT INTO user_session (id, user_id, created_ts, last_accessed_ts, expires_ts, client_info)
+SELECT id, user_id, created_ts, last_accessed_ts, created_ts + 2592000, client_info
+FROM (
+  SELECT
+    session->>'sessionId' AS id,
+    user_setting.user_id AS user_id,
+    EXTRACT(EPOCH FROM (session->>'createTime')::TIMESTAMPTZ)::BIGINT AS created_ts,
+    EXTRACT(EPOCH FROM COALESCE(session->>'lastAccessedTime', session->>'createTime')::TIMESTAMPTZ)::BIGINT AS last_accessed_ts,
+    COALESCE(session->'clientInfo', '{}'::JSONB)::TEXT AS client_info
+  FROM user_setting, jsonb_array_elements(user_setting.value::JSONB->'sessions') AS session
+  WHERE user_setting.key = 'SESSIONS'
+) AS sessions
+WHERE id IS NOT NULL AND created_ts IS NOT NULL;
+
+DELETE FROM user_setting WHERE key = 'SESSIONS';
@@ -0,0 +1,11 @@
+CREATE TABLE outbound_mail (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  status TEXT NOT NULL DEFAULT 'PENDING',
+  message TEXT NOT NULL DEFAULT '{}',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_outbound_mail_status_next_attempt_ts ON outbound_mail (status, next_attempt_ts);
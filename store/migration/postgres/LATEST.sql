@@ -125,8 +125,83 @@ CREATE TABLE reaction (
 -- subscription
 CREATE TABLE subscription (
   id SERIAL PRIMARY KEY,
-  username TEXT NOT NULL,
-  email TEXT,
   endpoint TEXT NOT NULL,
-  keys JSONB NOT NULL DEFAULT '{}'
-);
\ No newline at end of file
+  keys JSONB NOT NULL DEFAULT '{}',
+  user_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_subscription_user_id ON subscription (user_id);
+
+-- memo_embedding
+CREATE TABLE memo_embedding (
+  memo_id INTEGER NOT NULL PRIMARY KEY,
+  model TEXT NOT NULL,
+  content_hash TEXT NOT NULL,
+  embedding BYTEA NOT NULL,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+-- ai_usage
+CREATE TABLE ai_usage (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  model TEXT NOT NULL DEFAULT '',
+  prompt_tokens INTEGER NOT NULL DEFAULT 0,
+  completion_tokens INTEGER NOT NULL DEFAULT 0,
+  total_tokens INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_ai_usage_user_id_created_ts ON ai_usage (user_id, created_ts);
+
+-- otp
+CREATE TABLE otp (
+  id SERIAL PRIMARY KEY,
+  purpose TEXT NOT NULL,
+  identifier TEXT NOT NULL,
+  code_hash TEXT NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  expires_ts BIGINT NOT NULL,
+  UNIQUE(purpose, identifier)
+);
+
+-- user_session
+CREATE TABLE user_session (
+  id TEXT NOT NULL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  last_accessed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  expires_ts BIGINT NOT NULL,
+  client_info TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_user_session_user_id ON user_session (user_id);
+
+-- push_delivery
+CREATE TABLE push_delivery (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  user_id INTEGER NOT NULL,
+  endpoint TEXT NOT NULL,
+  topic TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_push_delivery_created_ts ON push_delivery (created_ts);
+
+-- outbound_mail
+CREATE TABLE outbound_mail (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  status TEXT NOT NULL DEFAULT 'PENDING',
+  message TEXT NOT NULL DEFAULT '{}',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_outbound_mail_status_next_attempt_ts ON outbound_mail (status, next_attempt_ts);
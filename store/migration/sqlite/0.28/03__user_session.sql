@@ -0,0 +1,27 @@
+CREATE TABLE user_session (
+  id TEXT NOT NULL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  last_accessed_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  expires_ts BIGINT NOT NULL,
+  client_info TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_user_session_user_id ON user_session (user_id);
+
+-- Carry over the sessions stored in the user settings. They had no absolute expiry, so it counts from their creation.
+INSERT INTO user_session (id, user_id, created_ts, last_accessed_ts, expires_ts, client_info)
+SELECT id, user_id, created_ts, last_accessed_ts, created_ts + 2592000, client_info
+FROM (
+  SELECT
+    json_extract(session.value, '$.sessionId') AS id,
+    user_setting.user_id AS user_id,
+    CAST(strftime('%s', json_extract(session.value, '$.createTime')) AS INTEGER) AS created_ts,
+    CAST(strftime('%s', COALESCE(json_extract(session.value, '$.lastAccessedTime'), json_extract(session.value, '$.createTime'))) AS INTEGER) AS last_accessed_ts,
+    COALESCE(json_extract(session.value, '$.clientInfo'), '{}') AS client_info
+  FROM user_setting, json_each(user_setting.value, '$.sessions') AS session
+  WHERE user_setting.key = 'SESSIONS' AND json_valid(user_setting.value)
+)
+WHERE id IS NOT NULL AND created_ts IS NOT NULL;
+
+DELETE FROM user_setting WHERE key = 'SESSIONS';
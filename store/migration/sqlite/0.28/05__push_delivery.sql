@@ -0,0 +1,13 @@
+CREATE TABLE push_delivery (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  user_id INTEGER NOT NULL,
+  endpoint TEXT NOT NULL,
+  topic TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_push_delivery_created_ts ON push_delivery (created_ts);
@@ -0,0 +1,11 @@
+CREATE TABLE outbound_mail (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  status TEXT NOT NULL DEFAULT 'PENDING',
+  message TEXT NOT NULL DEFAULT '{}',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_outbound_mail_status_next_attempt_ts ON outbound_mail (status, next_attempt_ts);
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+)
+
+// OTP is a one-time password issued to an identifier, such as an email address, for a purpose.
+// Only one OTP is active per purpose and identifier; issuing a new one replaces it.
+type OTP struct {
+	ID         int32
+	Purpose    string
+	Identifier string
+	// CodeHash is the keyed hash of the code, the code itself is never stored.
+	CodeHash string
+	// Attempts is the number of verification attempts.
+	Attempts  int32
+	CreatedTs int64
+	ExpiresTs int64
+}
+
+type FindOTP struct {
+	Purpose    *string
+	Identifier *string
+}
+
+type DeleteOTP struct {
+	ID int32
+}
+
+type DeleteExpiredOTP struct {
+	Purpose         string
+	ExpiresTsBefore int64
+}
+
+// UpsertOTP stores the OTP, replacing the active OTP of the same purpose and identifier.
+func (s *Store) UpsertOTP(ctx context.Context, upsert *OTP) (*OTP, error) {
+	return s.driver.UpsertOTP(ctx, upsert)
+}
+
+func (s *Store) ListOTPs(ctx context.Context, find *FindOTP) ([]*OTP, error) {
+	return s.driver.ListOTPs(ctx, find)
+}
+
+func (s *Store) GetOTP(ctx context.Context, find *FindOTP) (*OTP, error) {
+	list, err := s.ListOTPs(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// CountOTPAttempt adds a verification attempt to the OTP unless it already has the max attempts,
+// and reports whether the attempt was counted. The check and the increment are a single update,
+// so concurrent attempts cannot exceed the max.
+func (s *Store) CountOTPAttempt(ctx context.Context, id int32, maxAttempts int32) (bool, error) {
+	return s.driver.CountOTPAttempt(ctx, id, maxAttempts)
+}
+
+// DeleteOTP deletes the OTP and reports whether it was deleted, which is false when
+// another caller has deleted it first.
+func (s *Store) DeleteOTP(ctx context.Context, delete *DeleteOTP) (bool, error) {
+	return s.driver.DeleteOTP(ctx, delete)
+}
+
+// DeleteExpiredOTPs deletes the OTPs of the purpose expired before the time and returns how many were deleted.
+func (s *Store) DeleteExpiredOTPs(ctx context.Context, delete *DeleteExpiredOTP) (int64, error) {
+	return s.driver.DeleteExpiredOTPs(ctx, delete)
+}
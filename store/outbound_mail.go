@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+// OutboundMailStatus is the status of a queued email.
+type OutboundMailStatus string
+
+const (
+	// OutboundMailPending is an email waiting for its next attempt.
+	OutboundMailPending OutboundMailStatus = "PENDING"
+	// OutboundMailSent is an email accepted by the SMTP server.
+	OutboundMailSent OutboundMailStatus = "SENT"
+	// OutboundMailFailed is an email rejected by the SMTP server, or failing every attempt.
+	OutboundMailFailed OutboundMailStatus = "FAILED"
+)
+
+// OutboundMail is an email of the outbound mail queue.
+type OutboundMail struct {
+	ID        int32
+	CreatedTs int64
+	Status    OutboundMailStatus
+	Message   *storepb.OutboundMailMessage
+	Attempts  int32
+	// NextAttemptTs is when the email is next sent while pending.
+	NextAttemptTs int64
+	// Error is the error of the last attempt, empty once sent.
+	Error string
+}
+
+type FindOutboundMail struct {
+	ID     *int32
+	Status *OutboundMailStatus
+	// DueTs matches the emails whose next attempt is due by the given time.
+	DueTs *int64
+	Limit *int
+}
+
+type UpdateOutboundMail struct {
+	ID            int32
+	Status        *OutboundMailStatus
+	Attempts      *int32
+	NextAttemptTs *int64
+	Error         *string
+}
+
+// DeleteOutboundMail deletes the emails matching all the given conditions.
+type DeleteOutboundMail struct {
+	Status *OutboundMailStatus
+	// CreatedTsBefore matches the emails created before the given unix timestamp.
+	CreatedTsBefore *int64
+}
+
+func (s *Store) CreateOutboundMail(ctx context.Context, create *OutboundMail) (*OutboundMail, error) {
+	return s.driver.CreateOutboundMail(ctx, create)
+}
+
+// ListOutboundMails lists the matching emails, the earliest next attempt first.
+func (s *Store) ListOutboundMails(ctx context.Context, find *FindOutboundMail) ([]*OutboundMail, error) {
+	return s.driver.ListOutboundMails(ctx, find)
+}
+
+func (s *Store) GetOutboundMail(ctx context.Context, find *FindOutboundMail) (*OutboundMail, error) {
+	list, err := s.ListOutboundMails(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (s *Store) UpdateOutboundMail(ctx context.Context, update *UpdateOutboundMail) error {
+	return s.driver.UpdateOutboundMail(ctx, update)
+}
+
+// DeleteOutboundMails deletes the matching emails and returns how many were deleted.
+func (s *Store) DeleteOutboundMails(ctx context.Context, delete *DeleteOutboundMail) (int64, error) {
+	return s.driver.DeleteOutboundMails(ctx, delete)
+}
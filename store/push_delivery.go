@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+)
+
+// PushDelivery records the outcome of delivering a push notification to a subscription.
+type PushDelivery struct {
+	ID        int32
+	CreatedTs int64
+	UserID    int32
+	Endpoint  string
+	Topic     string
+	// Status is the final status of the delivery: DELIVERED, EXPIRED or FAILED.
+	Status string
+	// StatusCode is the HTTP status code of the last response of the push service, 0 if none.
+	StatusCode int32
+	Attempts   int32
+	Error      string
+}
+
+type FindPushDelivery struct {
+	UserID *int32
+	Status *string
+	Limit  *int
+	Offset *int
+}
+
+type DeletePushDelivery struct {
+	// CreatedTsBefore matches the deliveries created before the given unix timestamp.
+	CreatedTsBefore *int64
+}
+
+func (s *Store) CreatePushDelivery(ctx context.Context, create *PushDelivery) (*PushDelivery, error) {
+	return s.driver.CreatePushDelivery(ctx, create)
+}
+
+// ListPushDeliveries lists the matching deliveries, the latest first.
+func (s *Store) ListPushDeliveries(ctx context.Context, find *FindPushDelivery) ([]*PushDelivery, error) {
+	return s.driver.ListPushDeliveries(ctx, find)
+}
+
+// DeletePushDeliveries deletes the matching deliveries and returns how many were deleted.
+func (s *Store) DeletePushDeliveries(ctx context.Context, delete *DeletePushDelivery) (int64, error) {
+	return s.driver.DeletePushDeliveries(ctx, delete)
+}
@@ -4,23 +4,24 @@ import (
 	"context"
 )
 
-// Subscription represents a push subscription
+// Subscription represents a push subscription of a user's browser.
 type Subscription struct {
-    ID        *string           `json:"id,omitempty"`
-    Endpoint  string            `json:"endpoint"`
-    Username  string            `json:"username"`
-    Email     string            `json:"email,omitempty"`
-    Keys      map[string]string `json:"keys"`
+	ID       *string           `json:"id,omitempty"`
+	UserID   int32             `json:"user_id"`
+	Endpoint string            `json:"endpoint"`
+	Keys     map[string]string `json:"keys"`
 }
 
 type FindSubscription struct {
-	ID         *int32
-	Endpoint   *string
-	Username   *string
+	ID       *int32
+	Endpoint *string
+	UserID   *int32
 }
 
+// DeleteSubscription deletes the subscriptions matching all the given conditions.
 type DeleteSubscription struct {
-	Username string
+	UserID   *int32
+	Endpoint *string
 }
 
 func (s *Store) CreateSubscription(ctx context.Context, create *Subscription) (*Subscription, error) {
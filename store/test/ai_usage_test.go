@@ -0,0 +1,75 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func TestAIUsageStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	usage, err := ts.CreateAIUsage(ctx, &store.AIUsage{
+		UserID:           user.ID,
+		Model:            "test-model",
+		PromptTokens:     3,
+		CompletionTokens: 2,
+		TotalTokens:      5,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, usage.ID)
+	require.NotZero(t, usage.CreatedTs)
+	yesterday := time.Now().Add(-24 * time.Hour).Unix()
+	_, err = ts.CreateAIUsage(ctx, &store.AIUsage{UserID: user.ID, CreatedTs: yesterday - 60, TotalTokens: 10})
+	require.NoError(t, err)
+	otherUserID := user.ID + 1
+	_, err = ts.CreateAIUsage(ctx, &store.AIUsage{UserID: otherUserID, TotalTokens: 7})
+	require.NoError(t, err)
+
+	stats, err := ts.GetAIUsageStats(ctx, &store.FindAIUsage{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 2, TotalTokens: 15}, stats)
+	stats, err = ts.GetAIUsageStats(ctx, &store.FindAIUsage{UserID: &user.ID, CreatedTsAfter: &yesterday})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 1, TotalTokens: 5}, stats)
+	stats, err = ts.GetAIUsageStats(ctx, &store.FindAIUsage{})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 3, TotalTokens: 22}, stats)
+
+	// Reservations are made only within the limits, and settled with the actual usage.
+	limit := &store.AIUsageLimit{Find: &store.FindAIUsage{UserID: &otherUserID}, MaxRequests: 2, MaxTokens: 10}
+	reservation, err := ts.ReserveAIUsage(ctx, &store.ReserveAIUsage{
+		Usage:  &store.AIUsage{UserID: otherUserID, PromptTokens: 1, TotalTokens: 1},
+		Limits: []*store.AIUsageLimit{limit},
+	})
+	require.NoError(t, err)
+	_, err = ts.ReserveAIUsage(ctx, &store.ReserveAIUsage{
+		Usage:  &store.AIUsage{UserID: otherUserID, PromptTokens: 1, TotalTokens: 1},
+		Limits: []*store.AIUsageLimit{limit},
+	})
+	var limitErr *store.AIUsageLimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, limit, limitErr.Limit)
+	model, totalTokens := "test-model", int32(4)
+	require.NoError(t, ts.UpdateAIUsage(ctx, &store.UpdateAIUsage{ID: reservation.ID, Model: &model, TotalTokens: &totalTokens}))
+	stats, err = ts.GetAIUsageStats(ctx, &store.FindAIUsage{UserID: &otherUserID})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 2, TotalTokens: 11}, stats)
+	require.NoError(t, ts.DeleteAIUsage(ctx, &store.DeleteAIUsage{ID: &reservation.ID}))
+	stats, err = ts.GetAIUsageStats(ctx, &store.FindAIUsage{UserID: &otherUserID})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 1, TotalTokens: 7}, stats)
+
+	require.NoError(t, ts.DeleteAIUsage(ctx, &store.DeleteAIUsage{UserID: &user.ID}))
+	stats, err = ts.GetAIUsageStats(ctx, &store.FindAIUsage{})
+	require.NoError(t, err)
+	require.Equal(t, &store.AIUsageStats{RequestCount: 1, TotalTokens: 7}, stats)
+	ts.Close()
+}
@@ -58,3 +58,92 @@ func TestIdentityProviderStore(t *testing.T) {
 	require.Equal(t, 0, len(idpList))
 	ts.Close()
 }
+
+func TestOIDCIdentityProviderStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	createdIDP, err := ts.CreateIdentityProvider(ctx, &storepb.IdentityProvider{
+		Name: "Keycloak",
+		Type: storepb.IdentityProvider_OIDC,
+		Config: &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_OidcConfig{
+				OidcConfig: &storepb.OIDCConfig{
+					IssuerUrl:    "https://keycloak.example.com/realms/wekalist",
+					ClientId:     "client_id",
+					ClientSecret: "client_secret",
+					Scopes:       []string{"profile", "email"},
+					FieldMapping: &storepb.FieldMapping{
+						Identifier: "preferred_username",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	idp, err := ts.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &createdIDP.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, createdIDP, idp)
+	require.Equal(t, "https://keycloak.example.com/realms/wekalist", idp.Config.GetOidcConfig().IssuerUrl)
+	ts.Close()
+}
+
+func TestLDAPIdentityProviderStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	createdIDP, err := ts.CreateIdentityProvider(ctx, &storepb.IdentityProvider{
+		Name: "Directory",
+		Type: storepb.IdentityProvider_LDAP,
+		Config: &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_LdapConfig{
+				LdapConfig: &storepb.LDAPConfig{
+					Url:          "ldaps://ldap.example.com",
+					BindDn:       "cn=service,dc=example,dc=com",
+					BindPassword: "password",
+					BaseDn:       "dc=example,dc=com",
+					GroupRoleMappings: []*storepb.LDAPConfig_GroupRoleMapping{
+						{Group: "admins", Role: "ADMIN"},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	idp, err := ts.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &createdIDP.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, createdIDP, idp)
+	require.Equal(t, "admins", idp.Config.GetLdapConfig().GroupRoleMappings[0].Group)
+	ts.Close()
+}
+
+func TestSAMLIdentityProviderStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	createdIDP, err := ts.CreateIdentityProvider(ctx, &storepb.IdentityProvider{
+		Name: "SAML",
+		Type: storepb.IdentityProvider_SAML,
+		Config: &storepb.IdentityProviderConfig{
+			Config: &storepb.IdentityProviderConfig_SamlConfig{
+				SamlConfig: &storepb.SAMLConfig{
+					IdpEntityId:    "https://idp.example.com",
+					IdpSsoUrl:      "https://idp.example.com/sso",
+					IdpCertificate: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n",
+					FieldMapping: &storepb.FieldMapping{
+						Email: "mail",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	idp, err := ts.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &createdIDP.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, createdIDP, idp)
+	require.Equal(t, "https://idp.example.com/sso", idp.Config.GetSamlConfig().IdpSsoUrl)
+	ts.Close()
+}
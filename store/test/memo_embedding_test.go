@@ -0,0 +1,64 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func TestMemoEmbeddingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		UID:        "test-memo",
+		CreatorID:  user.ID,
+		Content:    "test content",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+
+	embedding, err := ts.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
+		MemoID:      memo.ID,
+		Model:       "test-model",
+		ContentHash: store.HashMemoContent(memo.Content),
+		Embedding:   []float32{0.25, -1, 3.5},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float32{0.25, -1, 3.5}, embedding.Embedding)
+
+	// Upserting again replaces the existing embedding.
+	_, err = ts.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
+		MemoID:      memo.ID,
+		Model:       "other-model",
+		ContentHash: store.HashMemoContent("new content"),
+		Embedding:   []float32{1, 2},
+	})
+	require.NoError(t, err)
+	embedding, err = ts.GetMemoEmbedding(ctx, &store.FindMemoEmbedding{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, "other-model", embedding.Model)
+	require.Equal(t, []float32{1, 2}, embedding.Embedding)
+
+	model := "test-model"
+	embeddings, err := ts.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{Model: &model})
+	require.NoError(t, err)
+	require.Empty(t, embeddings)
+	embeddings, err = ts.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{MemoIDList: []int32{memo.ID}})
+	require.NoError(t, err)
+	require.Len(t, embeddings, 1)
+
+	// Deleting the memo drops its embedding.
+	require.NoError(t, ts.DeleteMemo(ctx, &store.DeleteMemo{ID: memo.ID}))
+	embedding, err = ts.GetMemoEmbedding(ctx, &store.FindMemoEmbedding{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Nil(t, embedding)
+
+	_, err = ts.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{MemoID: memo.ID, Model: "test-model"})
+	require.Error(t, err)
+	ts.Close()
+}
@@ -13,5 +13,5 @@ func TestGetCurrentSchemaVersion(t *testing.T) {
 
 	currentSchemaVersion, err := ts.GetCurrentSchemaVersion()
 	require.NoError(t, err)
-	require.Equal(t, "0.25.1", currentSchemaVersion)
+	require.Equal(t, "0.28.7", currentSchemaVersion)
 }
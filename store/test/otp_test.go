@@ -0,0 +1,80 @@
+package teststore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func TestOTPStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	purpose, identifier := "verification", "user@example.com"
+	now := time.Now().Unix()
+
+	otp, err := ts.UpsertOTP(ctx, &store.OTP{
+		Purpose:    purpose,
+		Identifier: identifier,
+		CodeHash:   "first",
+		CreatedTs:  now,
+		ExpiresTs:  now + 600,
+	})
+	require.NoError(t, err)
+	// Attempts are counted up to the max only.
+	for i := 0; i < 3; i++ {
+		counted, err := ts.CountOTPAttempt(ctx, otp.ID, 2)
+		require.NoError(t, err)
+		require.Equal(t, i < 2, counted)
+	}
+	otp, err = ts.GetOTP(ctx, &store.FindOTP{Purpose: &purpose, Identifier: &identifier})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), otp.Attempts)
+	require.Equal(t, now+600, otp.ExpiresTs)
+
+	// Issuing a new OTP replaces the active one and resets the attempts.
+	_, err = ts.UpsertOTP(ctx, &store.OTP{
+		Purpose:    purpose,
+		Identifier: identifier,
+		CodeHash:   "second",
+		CreatedTs:  now + 60,
+		ExpiresTs:  now + 660,
+	})
+	require.NoError(t, err)
+	otps, err := ts.ListOTPs(ctx, &store.FindOTP{Identifier: &identifier})
+	require.NoError(t, err)
+	require.Len(t, otps, 1)
+	require.Equal(t, "second", otps[0].CodeHash)
+	require.Equal(t, int32(0), otps[0].Attempts)
+	require.Equal(t, now+60, otps[0].CreatedTs)
+
+	// Only the first delete succeeds.
+	deleted, err := ts.DeleteOTP(ctx, &store.DeleteOTP{ID: otps[0].ID})
+	require.NoError(t, err)
+	require.True(t, deleted)
+	deleted, err = ts.DeleteOTP(ctx, &store.DeleteOTP{ID: otps[0].ID})
+	require.NoError(t, err)
+	require.False(t, deleted)
+	otp, err = ts.GetOTP(ctx, &store.FindOTP{Purpose: &purpose, Identifier: &identifier})
+	require.NoError(t, err)
+	require.Nil(t, otp)
+
+	// Only the expired OTPs of the purpose are pruned.
+	for _, expiresTs := range []int64{now - 1, now + 600} {
+		_, err := ts.UpsertOTP(ctx, &store.OTP{Purpose: purpose, Identifier: fmt.Sprint(expiresTs), CodeHash: "hash", CreatedTs: now, ExpiresTs: expiresTs})
+		require.NoError(t, err)
+	}
+	_, err = ts.UpsertOTP(ctx, &store.OTP{Purpose: "login", Identifier: identifier, CodeHash: "hash", CreatedTs: now, ExpiresTs: now - 1})
+	require.NoError(t, err)
+	count, err := ts.DeleteExpiredOTPs(ctx, &store.DeleteExpiredOTP{Purpose: purpose, ExpiresTsBefore: now})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	otps, err = ts.ListOTPs(ctx, &store.FindOTP{})
+	require.NoError(t, err)
+	require.Len(t, otps, 2)
+	ts.Close()
+}
@@ -0,0 +1,89 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestOutboundMailStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	now := time.Now().Unix()
+
+	later, err := ts.CreateOutboundMail(ctx, &store.OutboundMail{
+		Status:        store.OutboundMailPending,
+		Message:       &storepb.OutboundMailMessage{To: []string{"bob@example.com"}, Subject: "Later"},
+		NextAttemptTs: now + 60,
+	})
+	require.NoError(t, err)
+	due, err := ts.CreateOutboundMail(ctx, &store.OutboundMail{
+		Status: store.OutboundMailPending,
+		Message: &storepb.OutboundMailMessage{
+			To:       []string{"alice@example.com"},
+			Subject:  "Hello",
+			TextBody: "Hello",
+			HtmlBody: "<p>Hello</p>",
+			Headers:  map[string]string{"List-Unsubscribe": "<http://localhost:8080/unsubscribe>"},
+		},
+		NextAttemptTs: now,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, due.ID)
+	require.NotZero(t, due.CreatedTs)
+
+	list, err := ts.ListOutboundMails(ctx, &store.FindOutboundMail{})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	// The earliest next attempt comes first.
+	require.Equal(t, due.ID, list[0].ID)
+	require.Equal(t, later.ID, list[1].ID)
+
+	pendingStatus := store.OutboundMailPending
+	list, err = ts.ListOutboundMails(ctx, &store.FindOutboundMail{Status: &pendingStatus, DueTs: &now})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	mail := list[0]
+	require.Equal(t, due.ID, mail.ID)
+	require.Equal(t, []string{"alice@example.com"}, mail.Message.To)
+	require.Equal(t, "<p>Hello</p>", mail.Message.HtmlBody)
+	require.Equal(t, "<http://localhost:8080/unsubscribe>", mail.Message.Headers["List-Unsubscribe"])
+
+	attempts, nextAttemptTs, errorMessage := int32(1), now+30, "connection refused"
+	err = ts.UpdateOutboundMail(ctx, &store.UpdateOutboundMail{ID: due.ID, Attempts: &attempts, NextAttemptTs: &nextAttemptTs, Error: &errorMessage})
+	require.NoError(t, err)
+	list, err = ts.ListOutboundMails(ctx, &store.FindOutboundMail{Status: &pendingStatus, DueTs: &now})
+	require.NoError(t, err)
+	require.Empty(t, list)
+	mail, err = ts.GetOutboundMail(ctx, &store.FindOutboundMail{ID: &due.ID})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), mail.Attempts)
+	require.Equal(t, now+30, mail.NextAttemptTs)
+	require.Equal(t, "connection refused", mail.Error)
+
+	sentStatus, noError := store.OutboundMailSent, ""
+	err = ts.UpdateOutboundMail(ctx, &store.UpdateOutboundMail{ID: due.ID, Status: &sentStatus, Error: &noError})
+	require.NoError(t, err)
+	mail, err = ts.GetOutboundMail(ctx, &store.FindOutboundMail{ID: &due.ID})
+	require.NoError(t, err)
+	require.Equal(t, store.OutboundMailSent, mail.Status)
+	require.Empty(t, mail.Error)
+
+	_, err = ts.DeleteOutboundMails(ctx, &store.DeleteOutboundMail{})
+	require.Error(t, err)
+	createdTsBefore := now + 3600
+	deleted, err := ts.DeleteOutboundMails(ctx, &store.DeleteOutboundMail{Status: &sentStatus, CreatedTsBefore: &createdTsBefore})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	list, err = ts.ListOutboundMails(ctx, &store.FindOutboundMail{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, later.ID, list[0].ID)
+
+	ts.Close()
+}
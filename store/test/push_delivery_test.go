@@ -0,0 +1,78 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func TestPushDeliveryStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	otherUserID := user.ID + 1
+
+	delivered, err := ts.CreatePushDelivery(ctx, &store.PushDelivery{
+		UserID:     user.ID,
+		Endpoint:   "https://push.example.com/1",
+		Topic:      "memos",
+		Status:     "DELIVERED",
+		StatusCode: 201,
+		Attempts:   2,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, delivered.ID)
+	require.NotZero(t, delivered.CreatedTs)
+	_, err = ts.CreatePushDelivery(ctx, &store.PushDelivery{
+		UserID:     user.ID,
+		Endpoint:   "https://push.example.com/2",
+		Status:     "EXPIRED",
+		StatusCode: 410,
+		Attempts:   1,
+		Error:      "push service responded with status code 410",
+	})
+	require.NoError(t, err)
+	_, err = ts.CreatePushDelivery(ctx, &store.PushDelivery{
+		UserID:   otherUserID,
+		Endpoint: "https://push.example.com/3",
+		Status:   "FAILED",
+		Attempts: 4,
+	})
+	require.NoError(t, err)
+
+	list, err := ts.ListPushDeliveries(ctx, &store.FindPushDelivery{})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	// The latest deliveries come first.
+	require.Equal(t, "https://push.example.com/3", list[0].Endpoint)
+
+	list, err = ts.ListPushDeliveries(ctx, &store.FindPushDelivery{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	status := "EXPIRED"
+	list, err = ts.ListPushDeliveries(ctx, &store.FindPushDelivery{UserID: &user.ID, Status: &status})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, int32(410), list[0].StatusCode)
+	require.Equal(t, "push service responded with status code 410", list[0].Error)
+
+	limit, offset := 1, 2
+	list, err = ts.ListPushDeliveries(ctx, &store.FindPushDelivery{Limit: &limit, Offset: &offset})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, delivered, list[0])
+
+	createdTsBefore := time.Now().Add(time.Hour).Unix()
+	deleted, err := ts.DeletePushDeliveries(ctx, &store.DeletePushDelivery{CreatedTsBefore: &createdTsBefore})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+	_, err = ts.DeletePushDeliveries(ctx, &store.DeletePushDelivery{})
+	require.Error(t, err)
+
+	ts.Close()
+}
@@ -50,7 +50,14 @@ func resetTestingDB(ctx context.Context, profile *profile.Profile, dbDriver stor
 		DROP TABLE IF EXISTS storage;
 		DROP TABLE IF EXISTS idp;
 		DROP TABLE IF EXISTS inbox;
-		DROP TABLE IF EXISTS reaction;`)
+		DROP TABLE IF EXISTS reaction;
+		DROP TABLE IF EXISTS memo_embedding;
+		DROP TABLE IF EXISTS ai_usage;
+		DROP TABLE IF EXISTS otp;
+		DROP TABLE IF EXISTS user_session;
+		DROP TABLE IF EXISTS subscription;
+		DROP TABLE IF EXISTS push_delivery;
+		DROP TABLE IF EXISTS outbound_mail;`)
 		if err != nil {
 			slog.Error("failed to reset testing db", slog.String("error", err.Error()))
 			panic(err)
@@ -70,7 +77,14 @@ func resetTestingDB(ctx context.Context, profile *profile.Profile, dbDriver stor
 		DROP TABLE IF EXISTS storage CASCADE;
 		DROP TABLE IF EXISTS idp CASCADE;
 		DROP TABLE IF EXISTS inbox CASCADE;
-		DROP TABLE IF EXISTS reaction CASCADE;`)
+		DROP TABLE IF EXISTS reaction CASCADE;
+		DROP TABLE IF EXISTS memo_embedding CASCADE;
+		DROP TABLE IF EXISTS ai_usage CASCADE;
+		DROP TABLE IF EXISTS otp CASCADE;
+		DROP TABLE IF EXISTS user_session CASCADE;
+		DROP TABLE IF EXISTS subscription CASCADE;
+		DROP TABLE IF EXISTS push_delivery CASCADE;
+		DROP TABLE IF EXISTS outbound_mail CASCADE;`)
 		if err != nil {
 			slog.Error("failed to reset testing db", slog.String("error", err.Error()))
 			panic(err)
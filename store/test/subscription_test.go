@@ -0,0 +1,54 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/imrany/wekalist/store"
+)
+
+func TestSubscriptionStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	otherUserID := user.ID + 1
+
+	createSubscription := func(userID int32, endpoint string) {
+		_, err := ts.CreateSubscription(ctx, &store.Subscription{
+			UserID:   userID,
+			Endpoint: endpoint,
+			Keys:     map[string]string{"p256dh": "key", "auth": "auth"},
+		})
+		require.NoError(t, err)
+	}
+	createSubscription(user.ID, "https://push.example.com/1")
+	createSubscription(user.ID, "https://push.example.com/2")
+	createSubscription(otherUserID, "https://push.example.com/3")
+
+	subscriptions, err := ts.ListSubscriptions(ctx, &store.FindSubscription{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	require.Equal(t, user.ID, subscriptions[0].UserID)
+	require.Equal(t, "key", subscriptions[0].Keys["p256dh"])
+
+	// Only the subscription of the endpoint is deleted.
+	endpoint := "https://push.example.com/1"
+	require.NoError(t, ts.DeleteSubscription(ctx, &store.DeleteSubscription{UserID: &user.ID, Endpoint: &endpoint}))
+	subscriptions, err = ts.ListSubscriptions(ctx, &store.FindSubscription{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, "https://push.example.com/2", subscriptions[0].Endpoint)
+
+	// The endpoint of another user is not deleted.
+	endpoint = "https://push.example.com/3"
+	require.NoError(t, ts.DeleteSubscription(ctx, &store.DeleteSubscription{UserID: &user.ID, Endpoint: &endpoint}))
+	subscriptions, err = ts.ListSubscriptions(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+
+	require.Error(t, ts.DeleteSubscription(ctx, &store.DeleteSubscription{}))
+	ts.Close()
+}
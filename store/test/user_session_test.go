@@ -0,0 +1,73 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+	"github.com/imrany/wekalist/store"
+)
+
+func TestUserSessionStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	now := time.Now().Unix()
+
+	createUserSession := func(id string, lastAccessedTs, expiresTs int64) {
+		_, err := ts.CreateUserSession(ctx, &store.UserSession{
+			ID:             id,
+			UserID:         user.ID,
+			CreatedTs:      now - 3600,
+			LastAccessedTs: lastAccessedTs,
+			ExpiresTs:      expiresTs,
+			ClientInfo:     &storepb.SessionsUserSetting_ClientInfo{UserAgent: "agent-" + id, DeviceType: "desktop"},
+		})
+		require.NoError(t, err)
+	}
+	createUserSession("active", now-60, now+3600)
+	createUserSession("idle", now-7200, now+3600)
+	createUserSession("expired", now-60, now-1)
+
+	userSessions, err := ts.ListUserSessions(ctx, &store.FindUserSession{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, userSessions, 3)
+	id := "active"
+	userSession, err := ts.GetUserSession(ctx, &store.FindUserSession{ID: &id, UserID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, now-60, userSession.LastAccessedTs)
+	require.Equal(t, now+3600, userSession.ExpiresTs)
+	require.Equal(t, "agent-active", userSession.ClientInfo.UserAgent)
+
+	lastAccessedTs := now
+	require.NoError(t, ts.UpdateUserSession(ctx, &store.UpdateUserSession{ID: id, LastAccessedTs: &lastAccessedTs}))
+	userSession, err = ts.GetUserSession(ctx, &store.FindUserSession{ID: &id})
+	require.NoError(t, err)
+	require.Equal(t, now, userSession.LastAccessedTs)
+
+	deleted, err := ts.DeleteUserSessions(ctx, &store.DeleteUserSession{ExpiresBeforeTs: &now})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	idleSinceTs := now - 3600
+	deleted, err = ts.DeleteUserSessions(ctx, &store.DeleteUserSession{LastAccessedBeforeTs: &idleSinceTs})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	userSessions, err = ts.ListUserSessions(ctx, &store.FindUserSession{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, userSessions, 1)
+	require.Equal(t, "active", userSessions[0].ID)
+
+	_, err = ts.DeleteUserSessions(ctx, &store.DeleteUserSession{})
+	require.Error(t, err)
+
+	// Sessions are deleted along with their user.
+	require.NoError(t, ts.DeleteUser(ctx, &store.DeleteUser{ID: user.ID}))
+	userSessions, err = ts.ListUserSessions(ctx, &store.FindUserSession{})
+	require.NoError(t, err)
+	require.Empty(t, userSessions)
+	ts.Close()
+}
@@ -2,9 +2,14 @@ package teststore
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	storepb "github.com/imrany/wekalist/proto/gen/store"
 	"github.com/imrany/wekalist/store"
@@ -26,3 +31,202 @@ func TestUserSettingStore(t *testing.T) {
 	require.Equal(t, 1, len(list))
 	ts.Close()
 }
+
+func TestUserTwoFactorSettingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	twoFactor, err := ts.GetUserTwoFactor(ctx, user.ID)
+	require.NoError(t, err)
+	require.Nil(t, twoFactor)
+
+	err = ts.UpsertUserTwoFactor(ctx, user.ID, &storepb.TwoFactorUserSetting{
+		TotpSecret:         "secret",
+		Enabled:            true,
+		RecoveryCodeHashes: []string{"a", "b"},
+		LastUsedStep:       42,
+	})
+	require.NoError(t, err)
+	twoFactor, err = ts.GetUserTwoFactor(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "secret", twoFactor.TotpSecret)
+	require.True(t, twoFactor.Enabled)
+	require.Equal(t, []string{"a", "b"}, twoFactor.RecoveryCodeHashes)
+	require.Equal(t, int64(42), twoFactor.LastUsedStep)
+
+	list, err := ts.ListUserSettings(ctx, &store.FindUserSetting{UserID: &user.ID, Key: storepb.UserSetting_TWO_FACTOR})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	ts.Close()
+}
+
+func TestUserPasskeysSettingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	passkeys, err := ts.GetUserPasskeys(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, passkeys)
+
+	err = ts.UpsertUserPasskey(ctx, user.ID, &storepb.PasskeysUserSetting_Passkey{Id: "a", DisplayName: "Laptop", PublicKey: []byte{1, 2}})
+	require.NoError(t, err)
+	err = ts.UpsertUserPasskey(ctx, user.ID, &storepb.PasskeysUserSetting_Passkey{Id: "b", DisplayName: "Phone"})
+	require.NoError(t, err)
+	err = ts.UpsertUserPasskey(ctx, user.ID, &storepb.PasskeysUserSetting_Passkey{Id: "a", DisplayName: "Laptop", PublicKey: []byte{1, 2}, SignCount: 3})
+	require.NoError(t, err)
+	passkeys, err = ts.GetUserPasskeys(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, passkeys, 2)
+	require.Equal(t, "a", passkeys[0].Id)
+	require.Equal(t, []byte{1, 2}, passkeys[0].PublicKey)
+	require.Equal(t, uint32(3), passkeys[0].SignCount)
+
+	err = ts.RemoveUserPasskey(ctx, user.ID, "a")
+	require.NoError(t, err)
+	passkeys, err = ts.GetUserPasskeys(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, passkeys, 1)
+	require.Equal(t, "b", passkeys[0].Id)
+	ts.Close()
+}
+
+func TestUserNotificationPreferencesSettingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	preferences, err := ts.GetUserNotificationPreferences(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, preferences)
+
+	err = ts.UpsertUserNotificationPreferences(ctx, user.ID, []*storepb.NotificationsUserSetting_Preference{
+		{EventType: storepb.NotificationsUserSetting_COMMENT, Channel: storepb.NotificationsUserSetting_PUSH, Enabled: false},
+		{EventType: storepb.NotificationsUserSetting_COMMENT, Channel: storepb.NotificationsUserSetting_EMAIL, Enabled: true},
+	})
+	require.NoError(t, err)
+	err = ts.UpsertUserNotificationPreferences(ctx, user.ID, []*storepb.NotificationsUserSetting_Preference{
+		{EventType: storepb.NotificationsUserSetting_COMMENT, Channel: storepb.NotificationsUserSetting_EMAIL, Enabled: false},
+		{EventType: storepb.NotificationsUserSetting_REACTION, Channel: storepb.NotificationsUserSetting_INBOX, Enabled: true},
+	})
+	require.NoError(t, err)
+	preferences, err = ts.GetUserNotificationPreferences(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, preferences, 3)
+	require.Equal(t, storepb.NotificationsUserSetting_PUSH, preferences[0].Channel)
+	require.False(t, preferences[0].Enabled)
+	require.Equal(t, storepb.NotificationsUserSetting_EMAIL, preferences[1].Channel)
+	require.False(t, preferences[1].Enabled)
+	require.Equal(t, storepb.NotificationsUserSetting_REACTION, preferences[2].EventType)
+	require.True(t, preferences[2].Enabled)
+	ts.Close()
+}
+
+func TestUserDigestSettingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	digest, err := ts.GetUserDigestSetting(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, storepb.DigestUserSetting_FREQUENCY_UNSPECIFIED, digest.Frequency)
+
+	lastSentTime := timestamppb.New(time.Unix(1700000000, 0))
+	err = ts.UpsertUserDigestSetting(ctx, user.ID, &storepb.DigestUserSetting{
+		Frequency:    storepb.DigestUserSetting_WEEKLY,
+		LastSentTime: lastSentTime,
+	})
+	require.NoError(t, err)
+	userSettings, err := ts.ListUserSettings(ctx, &store.FindUserSetting{Key: storepb.UserSetting_DIGEST})
+	require.NoError(t, err)
+	require.Len(t, userSettings, 1)
+	require.Equal(t, user.ID, userSettings[0].UserId)
+	require.Equal(t, storepb.DigestUserSetting_WEEKLY, userSettings[0].GetDigest().Frequency)
+	require.True(t, userSettings[0].GetDigest().LastSentTime.AsTime().Equal(lastSentTime.AsTime()))
+	ts.Close()
+}
+
+func TestUserAccessTokensSettingStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	// Access tokens stored in plaintext are replaced with their hash, keeping the full rights of the user.
+	_, err = ts.UpsertUserSetting(ctx, &storepb.UserSetting{
+		UserId: user.ID,
+		Key:    storepb.UserSetting_ACCESS_TOKENS,
+		Value: &storepb.UserSetting_AccessTokens{
+			AccessTokens: &storepb.AccessTokensUserSetting{
+				AccessTokens: []*storepb.AccessTokensUserSetting_AccessToken{{AccessToken: "legacy", Description: "CLI"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	accessTokens, err := ts.GetUserAccessTokens(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, accessTokens, 1)
+	require.Empty(t, accessTokens[0].AccessToken)
+	require.Equal(t, store.HashAccessToken("legacy"), accessTokens[0].TokenHash)
+	require.Equal(t, "CLI", accessTokens[0].Description)
+	require.Equal(t, []string{store.AccessTokenScopeAdmin}, accessTokens[0].Scopes)
+	legacyID := accessTokens[0].Id
+	require.NotEmpty(t, legacyID)
+
+	err = ts.UpsertUserAccessToken(ctx, user.ID, &storepb.AccessTokensUserSetting_AccessToken{Id: "a", TokenHash: store.HashAccessToken("a"), Scopes: []string{store.AccessTokenScopeMemosRead}})
+	require.NoError(t, err)
+	err = ts.UpsertUserAccessToken(ctx, user.ID, &storepb.AccessTokensUserSetting_AccessToken{Id: "a", TokenHash: store.HashAccessToken("a"), Scopes: []string{store.AccessTokenScopeMemosWrite}})
+	require.NoError(t, err)
+	accessTokens, err = ts.GetUserAccessTokens(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, accessTokens, 2)
+	require.Equal(t, []string{store.AccessTokenScopeMemosWrite}, accessTokens[1].Scopes)
+
+	err = ts.RemoveUserAccessToken(ctx, user.ID, legacyID)
+	require.NoError(t, err)
+	accessTokens, err = ts.GetUserAccessTokens(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, accessTokens, 1)
+	require.Equal(t, "a", accessTokens[0].Id)
+
+	// Recording the last use keeps the other access tokens and does not restore revoked ones.
+	err = ts.UpsertUserAccessToken(ctx, user.ID, &storepb.AccessTokensUserSetting_AccessToken{Id: "b", TokenHash: store.HashAccessToken("b")})
+	require.NoError(t, err)
+	lastUsedTime := timestamppb.New(time.Unix(1700000000, 0))
+	require.NoError(t, ts.UpdateUserAccessTokenLastUsedTime(ctx, user.ID, "a", lastUsedTime))
+	require.NoError(t, ts.UpdateUserAccessTokenLastUsedTime(ctx, user.ID, legacyID, lastUsedTime))
+	accessTokens, err = ts.GetUserAccessTokens(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, accessTokens, 2)
+	require.Equal(t, lastUsedTime.AsTime(), accessTokens[0].LastUsedTime.AsTime())
+	require.Equal(t, "b", accessTokens[1].Id)
+	require.Nil(t, accessTokens[1].LastUsedTime)
+
+	// Concurrent creations and revocations do not overwrite each other, including the first ones of a user.
+	user, err = ts.CreateUser(ctx, &store.User{Username: "concurrent", Role: store.RoleUser, Email: "concurrent@example.com"})
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("concurrent-%d", i)
+			assert.NoError(t, ts.UpsertUserAccessToken(ctx, user.ID, &storepb.AccessTokensUserSetting_AccessToken{Id: id, TokenHash: store.HashAccessToken(id)}))
+			if i%2 == 1 {
+				assert.NoError(t, ts.RemoveUserAccessToken(ctx, user.ID, id))
+			}
+		}()
+	}
+	wg.Wait()
+	accessTokens, err = ts.GetUserAccessTokens(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, accessTokens, 4)
+	for _, accessToken := range accessTokens {
+		var index int
+		_, err := fmt.Sscanf(accessToken.Id, "concurrent-%d", &index)
+		require.NoError(t, err)
+		require.Zero(t, index%2)
+	}
+	ts.Close()
+}
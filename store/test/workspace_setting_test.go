@@ -29,3 +29,39 @@ func TestWorkspaceSettingV1Store(t *testing.T) {
 	require.Equal(t, workspaceSetting, setting)
 	ts.Close()
 }
+
+func TestWorkspaceAISettingDefault(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	aiSetting, err := ts.GetWorkspaceAISetting(ctx)
+	require.NoError(t, err)
+	require.Equal(t, storepb.WorkspaceAISetting_WRAPPER, aiSetting.Provider)
+
+	_, err = ts.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key: storepb.WorkspaceSettingKey_AI,
+		Value: &storepb.WorkspaceSetting_AiSetting{
+			AiSetting: &storepb.WorkspaceAISetting{
+				Provider: storepb.WorkspaceAISetting_OLLAMA,
+				BaseUrl:  "http://localhost:11434",
+				Model:    "llama3.2",
+			},
+		},
+	})
+	require.NoError(t, err)
+	aiSetting, err = ts.GetWorkspaceAISetting(ctx)
+	require.NoError(t, err)
+	require.Equal(t, storepb.WorkspaceAISetting_OLLAMA, aiSetting.Provider)
+	require.Equal(t, "llama3.2", aiSetting.Model)
+
+	// The default provider is applied to a copy, not to the setting returned by the upsert.
+	workspaceSetting, err := ts.UpsertWorkspaceSetting(ctx, &storepb.WorkspaceSetting{
+		Key:   storepb.WorkspaceSettingKey_AI,
+		Value: &storepb.WorkspaceSetting_AiSetting{AiSetting: &storepb.WorkspaceAISetting{Model: "llama3.2"}},
+	})
+	require.NoError(t, err)
+	aiSetting, err = ts.GetWorkspaceAISetting(ctx)
+	require.NoError(t, err)
+	require.Equal(t, storepb.WorkspaceAISetting_WRAPPER, aiSetting.Provider)
+	require.Equal(t, storepb.WorkspaceAISetting_PROVIDER_UNSPECIFIED, workspaceSetting.GetAiSetting().Provider)
+	ts.Close()
+}
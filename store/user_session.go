@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+
+	storepb "github.com/imrany/wekalist/proto/gen/store"
+)
+
+// UserSession is a signed-in session of a user, identified by the session ID of the session cookie.
+type UserSession struct {
+	ID        string
+	UserID    int32
+	CreatedTs int64
+	// LastAccessedTs is the last time the session was used, for the idle expiry.
+	LastAccessedTs int64
+	// ExpiresTs is the absolute expiry of the session, however often it is used.
+	ExpiresTs  int64
+	ClientInfo *storepb.SessionsUserSetting_ClientInfo
+}
+
+type FindUserSession struct {
+	ID     *string
+	UserID *int32
+}
+
+type UpdateUserSession struct {
+	ID             string
+	LastAccessedTs *int64
+}
+
+// DeleteUserSession deletes the sessions matching all the given conditions.
+type DeleteUserSession struct {
+	ID     *string
+	UserID *int32
+	// ExpiresBeforeTs matches the sessions past their absolute expiry at the given time.
+	ExpiresBeforeTs *int64
+	// LastAccessedBeforeTs matches the sessions not used since the given time.
+	LastAccessedBeforeTs *int64
+}
+
+func (s *Store) CreateUserSession(ctx context.Context, create *UserSession) (*UserSession, error) {
+	return s.driver.CreateUserSession(ctx, create)
+}
+
+func (s *Store) ListUserSessions(ctx context.Context, find *FindUserSession) ([]*UserSession, error) {
+	return s.driver.ListUserSessions(ctx, find)
+}
+
+func (s *Store) GetUserSession(ctx context.Context, find *FindUserSession) (*UserSession, error) {
+	list, err := s.ListUserSessions(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (s *Store) UpdateUserSession(ctx context.Context, update *UpdateUserSession) error {
+	return s.driver.UpdateUserSession(ctx, update)
+}
+
+// DeleteUserSessions deletes the matching sessions and returns how many were deleted.
+func (s *Store) DeleteUserSessions(ctx context.Context, delete *DeleteUserSession) (int64, error) {
+	return s.driver.DeleteUserSessions(ctx, delete)
+}
@@ -2,6 +2,9 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -10,6 +13,15 @@ import (
 	storepb "github.com/imrany/wekalist/proto/gen/store"
 )
 
+// The scopes access tokens can be granted.
+const (
+	AccessTokenScopeMemosRead        = "memos:read"
+	AccessTokenScopeMemosWrite       = "memos:write"
+	AccessTokenScopeAttachmentsWrite = "attachments:write"
+	// AccessTokenScopeAdmin grants the full rights of the user.
+	AccessTokenScopeAdmin = "admin"
+)
+
 type UserSetting struct {
 	UserID int32
 	Key    storepb.UserSetting_Key
@@ -21,6 +33,14 @@ type FindUserSetting struct {
 	Key    storepb.UserSetting_Key
 }
 
+// UpdateUserSetting sets the value of a user setting only if it is still the old value.
+type UpdateUserSetting struct {
+	UserID   int32
+	Key      storepb.UserSetting_Key
+	Value    string
+	OldValue string
+}
+
 func (s *Store) UpsertUserSetting(ctx context.Context, upsert *storepb.UserSetting) (*storepb.UserSetting, error) {
 	userSettingRaw, err := convertUserSettingToRaw(upsert)
 	if err != nil {
@@ -102,111 +122,275 @@ func (s *Store) GetUserAccessTokens(ctx context.Context, userID int32) ([]*store
 		return []*storepb.AccessTokensUserSetting_AccessToken{}, nil
 	}
 
-	accessTokensUserSetting := userSetting.GetAccessTokens()
-	return accessTokensUserSetting.AccessTokens, nil
+	accessTokens := userSetting.GetAccessTokens().AccessTokens
+	if !slices.ContainsFunc(accessTokens, isLegacyAccessToken) {
+		return accessTokens, nil
+	}
+	accessTokens, err = s.updateUserAccessTokens(ctx, userID, migrateLegacyAccessTokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to migrate access tokens")
+	}
+	return accessTokens, nil
+}
+
+// migrateLegacyAccessTokens replaces the access tokens that used to be stored in plaintext with the full
+// rights of the user with their hash, keeping the full rights.
+func migrateLegacyAccessTokens(accessTokens []*storepb.AccessTokensUserSetting_AccessToken) []*storepb.AccessTokensUserSetting_AccessToken {
+	migratedAccessTokens := make([]*storepb.AccessTokensUserSetting_AccessToken, 0, len(accessTokens))
+	for _, accessToken := range accessTokens {
+		if isLegacyAccessToken(accessToken) {
+			tokenHash := HashAccessToken(accessToken.AccessToken)
+			accessToken = &storepb.AccessTokensUserSetting_AccessToken{
+				Id:          tokenHash[:16],
+				TokenHash:   tokenHash,
+				Description: accessToken.Description,
+				Scopes:      []string{AccessTokenScopeAdmin},
+			}
+		}
+		migratedAccessTokens = append(migratedAccessTokens, accessToken)
+	}
+	return migratedAccessTokens
+}
+
+// UpsertUserAccessToken adds an access token for the user, or replaces the access token with the same ID.
+func (s *Store) UpsertUserAccessToken(ctx context.Context, userID int32, accessToken *storepb.AccessTokensUserSetting_AccessToken) error {
+	_, err := s.updateUserAccessTokens(ctx, userID, func(existingAccessTokens []*storepb.AccessTokensUserSetting_AccessToken) []*storepb.AccessTokensUserSetting_AccessToken {
+		updatedAccessTokens := make([]*storepb.AccessTokensUserSetting_AccessToken, 0, len(existingAccessTokens)+1)
+		accessTokenExists := false
+		for _, existing := range existingAccessTokens {
+			if existing.Id == accessToken.Id {
+				updatedAccessTokens = append(updatedAccessTokens, accessToken)
+				accessTokenExists = true
+			} else {
+				updatedAccessTokens = append(updatedAccessTokens, existing)
+			}
+		}
+		if !accessTokenExists {
+			updatedAccessTokens = append(updatedAccessTokens, accessToken)
+		}
+		return updatedAccessTokens
+	})
+	return err
 }
 
-// RemoveUserAccessToken remove the access token of the user.
-func (s *Store) RemoveUserAccessToken(ctx context.Context, userID int32, token string) error {
-	oldAccessTokens, err := s.GetUserAccessTokens(ctx, userID)
+// UpdateUserAccessTokenLastUsedTime records the last use of the access token with the given ID of the user.
+// The access tokens are written only if they have not changed since they were read, so that a concurrent
+// creation or revocation of an access token is never overwritten; the last use is then not recorded.
+func (s *Store) UpdateUserAccessTokenLastUsedTime(ctx context.Context, userID int32, accessTokenID string, lastUsedTime *timestamppb.Timestamp) error {
+	// Read the stored value rather than the cached one, as it is the value compared on write.
+	list, err := s.driver.ListUserSettings(ctx, &FindUserSetting{
+		UserID: &userID,
+		Key:    storepb.UserSetting_ACCESS_TOKENS,
+	})
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	userSetting, err := convertUserSettingFromRaw(list[0])
 	if err != nil {
 		return err
 	}
+	accessTokens := userSetting.GetAccessTokens().GetAccessTokens()
+	index := slices.IndexFunc(accessTokens, func(accessToken *storepb.AccessTokensUserSetting_AccessToken) bool {
+		return accessToken.Id == accessTokenID
+	})
+	if index < 0 {
+		return nil
+	}
+	accessTokens[index].LastUsedTime = lastUsedTime
+	userSettingRaw, err := convertUserSettingToRaw(userSetting)
+	if err != nil {
+		return err
+	}
+	updated, err := s.driver.UpdateUserSetting(ctx, &UpdateUserSetting{
+		UserID:   userID,
+		Key:      storepb.UserSetting_ACCESS_TOKENS,
+		Value:    userSettingRaw.Value,
+		OldValue: list[0].Value,
+	})
+	if err != nil {
+		return err
+	}
+	cacheKey := getUserSettingCacheKey(userID, storepb.UserSetting_ACCESS_TOKENS.String())
+	if updated {
+		s.userSettingCache.Set(ctx, cacheKey, userSetting)
+	} else {
+		s.userSettingCache.Delete(ctx, cacheKey)
+	}
+	return nil
+}
+
+// RemoveUserAccessToken removes the access token with the given ID of the user.
+func (s *Store) RemoveUserAccessToken(ctx context.Context, userID int32, accessTokenID string) error {
+	_, err := s.updateUserAccessTokens(ctx, userID, func(oldAccessTokens []*storepb.AccessTokensUserSetting_AccessToken) []*storepb.AccessTokensUserSetting_AccessToken {
+		newAccessTokens := make([]*storepb.AccessTokensUserSetting_AccessToken, 0, len(oldAccessTokens))
+		for _, t := range oldAccessTokens {
+			if accessTokenID != t.Id {
+				newAccessTokens = append(newAccessTokens, t)
+			}
+		}
+		return newAccessTokens
+	})
+	return err
+}
+
+// maxUserAccessTokensUpdateAttempts bounds the retries of an access token update losing to concurrent updates.
+const maxUserAccessTokensUpdateAttempts = 10
+
+// updateUserAccessTokens applies the update to the access tokens of the user and returns the updated access tokens.
+// The access tokens are written only if they have not changed since they were read, and the update is applied
+// again to the new access tokens otherwise, so that concurrent updates are never overwritten.
+func (s *Store) updateUserAccessTokens(ctx context.Context, userID int32, update func([]*storepb.AccessTokensUserSetting_AccessToken) []*storepb.AccessTokensUserSetting_AccessToken) ([]*storepb.AccessTokensUserSetting_AccessToken, error) {
+	cacheKey := getUserSettingCacheKey(userID, storepb.UserSetting_ACCESS_TOKENS.String())
+	for attempt := 0; attempt < maxUserAccessTokensUpdateAttempts; attempt++ {
+		// Read the stored value rather than the cached one, as it is the value compared on write.
+		list, err := s.driver.ListUserSettings(ctx, &FindUserSetting{
+			UserID: &userID,
+			Key:    storepb.UserSetting_ACCESS_TOKENS,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accessTokens := []*storepb.AccessTokensUserSetting_AccessToken{}
+		if len(list) > 0 {
+			userSetting, err := convertUserSettingFromRaw(list[0])
+			if err != nil {
+				return nil, err
+			}
+			accessTokens = userSetting.GetAccessTokens().GetAccessTokens()
+		}
 
-	newAccessTokens := make([]*storepb.AccessTokensUserSetting_AccessToken, 0, len(oldAccessTokens))
-	for _, t := range oldAccessTokens {
-		if token != t.AccessToken {
-			newAccessTokens = append(newAccessTokens, t)
+		userSetting := &storepb.UserSetting{
+			UserId: userID,
+			Key:    storepb.UserSetting_ACCESS_TOKENS,
+			Value: &storepb.UserSetting_AccessTokens{
+				AccessTokens: &storepb.AccessTokensUserSetting{
+					AccessTokens: update(accessTokens),
+				},
+			},
+		}
+		userSettingRaw, err := convertUserSettingToRaw(userSetting)
+		if err != nil {
+			return nil, err
+		}
+		var updated bool
+		if len(list) == 0 {
+			updated, err = s.driver.CreateUserSetting(ctx, userSettingRaw)
+		} else {
+			updated, err = s.driver.UpdateUserSetting(ctx, &UpdateUserSetting{
+				UserID:   userID,
+				Key:      storepb.UserSetting_ACCESS_TOKENS,
+				Value:    userSettingRaw.Value,
+				OldValue: list[0].Value,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			s.userSettingCache.Set(ctx, cacheKey, userSetting)
+			return userSetting.GetAccessTokens().AccessTokens, nil
 		}
 	}
+	s.userSettingCache.Delete(ctx, cacheKey)
+	return nil, errors.New("the access tokens were changed concurrently too many times")
+}
 
-	_, err = s.UpsertUserSetting(ctx, &storepb.UserSetting{
+// HashAccessToken returns the hash access tokens are stored and looked up with.
+func HashAccessToken(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func isLegacyAccessToken(accessToken *storepb.AccessTokensUserSetting_AccessToken) bool {
+	return accessToken.AccessToken != ""
+}
+
+// RemoveAllUserAccessTokens removes all access tokens of the user.
+func (s *Store) RemoveAllUserAccessTokens(ctx context.Context, userID int32) error {
+	_, err := s.UpsertUserSetting(ctx, &storepb.UserSetting{
 		UserId: userID,
 		Key:    storepb.UserSetting_ACCESS_TOKENS,
 		Value: &storepb.UserSetting_AccessTokens{
-			AccessTokens: &storepb.AccessTokensUserSetting{
-				AccessTokens: newAccessTokens,
-			},
+			AccessTokens: &storepb.AccessTokensUserSetting{},
 		},
 	})
-
 	return err
 }
 
-// GetUserSessions returns the sessions of the user.
-func (s *Store) GetUserSessions(ctx context.Context, userID int32) ([]*storepb.SessionsUserSetting_Session, error) {
+// GetUserTwoFactor returns the two-factor authentication setting of the user, or nil if it is not set up.
+func (s *Store) GetUserTwoFactor(ctx context.Context, userID int32) (*storepb.TwoFactorUserSetting, error) {
 	userSetting, err := s.GetUserSetting(ctx, &FindUserSetting{
 		UserID: &userID,
-		Key:    storepb.UserSetting_SESSIONS,
+		Key:    storepb.UserSetting_TWO_FACTOR,
 	})
 	if err != nil {
 		return nil, err
 	}
 	if userSetting == nil {
-		return []*storepb.SessionsUserSetting_Session{}, nil
+		return nil, nil
 	}
+	return userSetting.GetTwoFactor(), nil
+}
 
-	sessionsUserSetting := userSetting.GetSessions()
-	return sessionsUserSetting.Sessions, nil
+// UpsertUserTwoFactor updates the two-factor authentication setting of the user.
+func (s *Store) UpsertUserTwoFactor(ctx context.Context, userID int32, twoFactor *storepb.TwoFactorUserSetting) error {
+	_, err := s.UpsertUserSetting(ctx, &storepb.UserSetting{
+		UserId: userID,
+		Key:    storepb.UserSetting_TWO_FACTOR,
+		Value: &storepb.UserSetting_TwoFactor{
+			TwoFactor: twoFactor,
+		},
+	})
+	return err
 }
 
-// RemoveUserSession removes the session of the user.
-func (s *Store) RemoveUserSession(ctx context.Context, userID int32, sessionID string) error {
-	oldSessions, err := s.GetUserSessions(ctx, userID)
+// GetUserPasskeys returns the passkeys of the user.
+func (s *Store) GetUserPasskeys(ctx context.Context, userID int32) ([]*storepb.PasskeysUserSetting_Passkey, error) {
+	userSetting, err := s.GetUserSetting(ctx, &FindUserSetting{
+		UserID: &userID,
+		Key:    storepb.UserSetting_PASSKEYS,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	newSessions := make([]*storepb.SessionsUserSetting_Session, 0, len(oldSessions))
-	for _, session := range oldSessions {
-		if sessionID != session.SessionId {
-			newSessions = append(newSessions, session)
-		}
+	if userSetting == nil {
+		return []*storepb.PasskeysUserSetting_Passkey{}, nil
 	}
 
-	_, err = s.UpsertUserSetting(ctx, &storepb.UserSetting{
-		UserId: userID,
-		Key:    storepb.UserSetting_SESSIONS,
-		Value: &storepb.UserSetting_Sessions{
-			Sessions: &storepb.SessionsUserSetting{
-				Sessions: newSessions,
-			},
-		},
-	})
-
-	return err
+	passkeysUserSetting := userSetting.GetPasskeys()
+	return passkeysUserSetting.Passkeys, nil
 }
 
-// AddUserSession adds a new session for the user.
-func (s *Store) AddUserSession(ctx context.Context, userID int32, session *storepb.SessionsUserSetting_Session) error {
-	existingSessions, err := s.GetUserSessions(ctx, userID)
+// UpsertUserPasskey adds a passkey for the user, or replaces the passkey with the same ID.
+func (s *Store) UpsertUserPasskey(ctx context.Context, userID int32, passkey *storepb.PasskeysUserSetting_Passkey) error {
+	existingPasskeys, err := s.GetUserPasskeys(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	// Check if session already exists, update if it does
-	var updatedSessions []*storepb.SessionsUserSetting_Session
-	sessionExists := false
-	for _, existing := range existingSessions {
-		if existing.SessionId == session.SessionId {
-			updatedSessions = append(updatedSessions, session)
-			sessionExists = true
+	updatedPasskeys := make([]*storepb.PasskeysUserSetting_Passkey, 0, len(existingPasskeys)+1)
+	passkeyExists := false
+	for _, existing := range existingPasskeys {
+		if existing.Id == passkey.Id {
+			updatedPasskeys = append(updatedPasskeys, passkey)
+			passkeyExists = true
 		} else {
-			updatedSessions = append(updatedSessions, existing)
+			updatedPasskeys = append(updatedPasskeys, existing)
 		}
 	}
-
-	// If session doesn't exist, add it
-	if !sessionExists {
-		updatedSessions = append(updatedSessions, session)
+	if !passkeyExists {
+		updatedPasskeys = append(updatedPasskeys, passkey)
 	}
 
 	_, err = s.UpsertUserSetting(ctx, &storepb.UserSetting{
 		UserId: userID,
-		Key:    storepb.UserSetting_SESSIONS,
-		Value: &storepb.UserSetting_Sessions{
-			Sessions: &storepb.SessionsUserSetting{
-				Sessions: updatedSessions,
+		Key:    storepb.UserSetting_PASSKEYS,
+		Value: &storepb.UserSetting_Passkeys{
+			Passkeys: &storepb.PasskeysUserSetting{
+				Passkeys: updatedPasskeys,
 			},
 		},
 	})
@@ -214,26 +398,26 @@ func (s *Store) AddUserSession(ctx context.Context, userID int32, session *store
 	return err
 }
 
-// UpdateUserSessionLastAccessed updates the last accessed time of a session.
-func (s *Store) UpdateUserSessionLastAccessed(ctx context.Context, userID int32, sessionID string, lastAccessedTime *timestamppb.Timestamp) error {
-	sessions, err := s.GetUserSessions(ctx, userID)
+// RemoveUserPasskey removes the passkey of the user.
+func (s *Store) RemoveUserPasskey(ctx context.Context, userID int32, passkeyID string) error {
+	oldPasskeys, err := s.GetUserPasskeys(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	for _, session := range sessions {
-		if session.SessionId == sessionID {
-			session.LastAccessedTime = lastAccessedTime
-			break
+	newPasskeys := make([]*storepb.PasskeysUserSetting_Passkey, 0, len(oldPasskeys))
+	for _, passkey := range oldPasskeys {
+		if passkeyID != passkey.Id {
+			newPasskeys = append(newPasskeys, passkey)
 		}
 	}
 
 	_, err = s.UpsertUserSetting(ctx, &storepb.UserSetting{
 		UserId: userID,
-		Key:    storepb.UserSetting_SESSIONS,
-		Value: &storepb.UserSetting_Sessions{
-			Sessions: &storepb.SessionsUserSetting{
-				Sessions: sessions,
+		Key:    storepb.UserSetting_PASSKEYS,
+		Value: &storepb.UserSetting_Passkeys{
+			Passkeys: &storepb.PasskeysUserSetting{
+				Passkeys: newPasskeys,
 			},
 		},
 	})
@@ -349,6 +533,78 @@ func (s *Store) UpdateUserWebhook(ctx context.Context, userID int32, webhook *st
 	return err
 }
 
+// GetUserNotificationPreferences returns the notification preferences set by the user.
+func (s *Store) GetUserNotificationPreferences(ctx context.Context, userID int32) ([]*storepb.NotificationsUserSetting_Preference, error) {
+	userSetting, err := s.GetUserSetting(ctx, &FindUserSetting{
+		UserID: &userID,
+		Key:    storepb.UserSetting_NOTIFICATIONS,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if userSetting == nil {
+		return []*storepb.NotificationsUserSetting_Preference{}, nil
+	}
+	return userSetting.GetNotifications().Preferences, nil
+}
+
+// UpsertUserNotificationPreferences sets the preferences of the user, replacing the preferences
+// of the same event types and channels.
+func (s *Store) UpsertUserNotificationPreferences(ctx context.Context, userID int32, preferences []*storepb.NotificationsUserSetting_Preference) error {
+	existingPreferences, err := s.GetUserNotificationPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	updatedPreferences := make([]*storepb.NotificationsUserSetting_Preference, 0, len(existingPreferences)+len(preferences))
+	for _, existing := range existingPreferences {
+		if !slices.ContainsFunc(preferences, func(preference *storepb.NotificationsUserSetting_Preference) bool {
+			return preference.EventType == existing.EventType && preference.Channel == existing.Channel
+		}) {
+			updatedPreferences = append(updatedPreferences, existing)
+		}
+	}
+	updatedPreferences = append(updatedPreferences, preferences...)
+
+	_, err = s.UpsertUserSetting(ctx, &storepb.UserSetting{
+		UserId: userID,
+		Key:    storepb.UserSetting_NOTIFICATIONS,
+		Value: &storepb.UserSetting_Notifications{
+			Notifications: &storepb.NotificationsUserSetting{
+				Preferences: updatedPreferences,
+			},
+		},
+	})
+	return err
+}
+
+// GetUserDigestSetting returns the email digest setting of the user.
+func (s *Store) GetUserDigestSetting(ctx context.Context, userID int32) (*storepb.DigestUserSetting, error) {
+	userSetting, err := s.GetUserSetting(ctx, &FindUserSetting{
+		UserID: &userID,
+		Key:    storepb.UserSetting_DIGEST,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if userSetting == nil {
+		return &storepb.DigestUserSetting{}, nil
+	}
+	return userSetting.GetDigest(), nil
+}
+
+// UpsertUserDigestSetting sets the email digest setting of the user.
+func (s *Store) UpsertUserDigestSetting(ctx context.Context, userID int32, digest *storepb.DigestUserSetting) error {
+	_, err := s.UpsertUserSetting(ctx, &storepb.UserSetting{
+		UserId: userID,
+		Key:    storepb.UserSetting_DIGEST,
+		Value: &storepb.UserSetting_Digest{
+			Digest: digest,
+		},
+	})
+	return err
+}
+
 func convertUserSettingFromRaw(raw *UserSetting) (*storepb.UserSetting, error) {
 	userSetting := &storepb.UserSetting{
 		UserId: raw.UserID,
@@ -386,6 +642,30 @@ func convertUserSettingFromRaw(raw *UserSetting) (*storepb.UserSetting, error) {
 			return nil, err
 		}
 		userSetting.Value = &storepb.UserSetting_Webhooks{Webhooks: webhooksUserSetting}
+	case storepb.UserSetting_TWO_FACTOR:
+		twoFactorUserSetting := &storepb.TwoFactorUserSetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw.Value), twoFactorUserSetting); err != nil {
+			return nil, err
+		}
+		userSetting.Value = &storepb.UserSetting_TwoFactor{TwoFactor: twoFactorUserSetting}
+	case storepb.UserSetting_PASSKEYS:
+		passkeysUserSetting := &storepb.PasskeysUserSetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw.Value), passkeysUserSetting); err != nil {
+			return nil, err
+		}
+		userSetting.Value = &storepb.UserSetting_Passkeys{Passkeys: passkeysUserSetting}
+	case storepb.UserSetting_NOTIFICATIONS:
+		notificationsUserSetting := &storepb.NotificationsUserSetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw.Value), notificationsUserSetting); err != nil {
+			return nil, err
+		}
+		userSetting.Value = &storepb.UserSetting_Notifications{Notifications: notificationsUserSetting}
+	case storepb.UserSetting_DIGEST:
+		digestUserSetting := &storepb.DigestUserSetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(raw.Value), digestUserSetting); err != nil {
+			return nil, err
+		}
+		userSetting.Value = &storepb.UserSetting_Digest{Digest: digestUserSetting}
 	default:
 		return nil, nil
 	}
@@ -434,6 +714,34 @@ func convertUserSettingToRaw(userSetting *storepb.UserSetting) (*UserSetting, er
 			return nil, err
 		}
 		raw.Value = string(value)
+	case storepb.UserSetting_TWO_FACTOR:
+		twoFactorUserSetting := userSetting.GetTwoFactor()
+		value, err := protojson.Marshal(twoFactorUserSetting)
+		if err != nil {
+			return nil, err
+		}
+		raw.Value = string(value)
+	case storepb.UserSetting_PASSKEYS:
+		passkeysUserSetting := userSetting.GetPasskeys()
+		value, err := protojson.Marshal(passkeysUserSetting)
+		if err != nil {
+			return nil, err
+		}
+		raw.Value = string(value)
+	case storepb.UserSetting_NOTIFICATIONS:
+		notificationsUserSetting := userSetting.GetNotifications()
+		value, err := protojson.Marshal(notificationsUserSetting)
+		if err != nil {
+			return nil, err
+		}
+		raw.Value = string(value)
+	case storepb.UserSetting_DIGEST:
+		digestUserSetting := userSetting.GetDigest()
+		value, err := protojson.Marshal(digestUserSetting)
+		if err != nil {
+			return nil, err
+		}
+		raw.Value = string(value)
 	default:
 		return nil, errors.Errorf("unsupported user setting key: %v", userSetting.Key)
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	storepb "github.com/imrany/wekalist/proto/gen/store"
 )
@@ -30,16 +31,20 @@ func (s *Store) UpsertWorkspaceSetting(ctx context.Context, upsert *storepb.Work
 	var valueBytes []byte
 	var err error
 	switch upsert.Key {
-		case storepb.WorkspaceSettingKey_BASIC:
-			valueBytes, err = protojson.Marshal(upsert.GetBasicSetting())
-		case storepb.WorkspaceSettingKey_GENERAL:
-			valueBytes, err = protojson.Marshal(upsert.GetGeneralSetting())
-		case storepb.WorkspaceSettingKey_STORAGE:
-			valueBytes, err = protojson.Marshal(upsert.GetStorageSetting())
-		case storepb.WorkspaceSettingKey_MEMO_RELATED:
-			valueBytes, err = protojson.Marshal(upsert.GetMemoRelatedSetting())
-		default:
-			return nil, errors.Errorf("unsupported workspace setting key: %v", upsert.Key)
+	case storepb.WorkspaceSettingKey_BASIC:
+		valueBytes, err = protojson.Marshal(upsert.GetBasicSetting())
+	case storepb.WorkspaceSettingKey_GENERAL:
+		valueBytes, err = protojson.Marshal(upsert.GetGeneralSetting())
+	case storepb.WorkspaceSettingKey_STORAGE:
+		valueBytes, err = protojson.Marshal(upsert.GetStorageSetting())
+	case storepb.WorkspaceSettingKey_MEMO_RELATED:
+		valueBytes, err = protojson.Marshal(upsert.GetMemoRelatedSetting())
+	case storepb.WorkspaceSettingKey_AI:
+		valueBytes, err = protojson.Marshal(upsert.GetAiSetting())
+	case storepb.WorkspaceSettingKey_EMAIL_TEMPLATES:
+		valueBytes, err = protojson.Marshal(upsert.GetEmailTemplatesSetting())
+	default:
+		return nil, errors.Errorf("unsupported workspace setting key: %v", upsert.Key)
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal workspace setting value")
@@ -209,6 +214,50 @@ func (s *Store) GetWorkspaceStorageSetting(ctx context.Context) (*storepb.Worksp
 	return workspaceStorageSetting, nil
 }
 
+const defaultWorkspaceAIProvider = storepb.WorkspaceAISetting_WRAPPER
+
+func (s *Store) GetWorkspaceAISetting(ctx context.Context) (*storepb.WorkspaceAISetting, error) {
+	workspaceSetting, err := s.GetWorkspaceSetting(ctx, &FindWorkspaceSetting{
+		Name: storepb.WorkspaceSettingKey_AI.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workspace ai setting")
+	}
+
+	workspaceAISetting := &storepb.WorkspaceAISetting{}
+	if workspaceSetting != nil && workspaceSetting.GetAiSetting() != nil {
+		// The setting may come from the cache, so the defaults are applied to a copy.
+		workspaceAISetting = proto.Clone(workspaceSetting.GetAiSetting()).(*storepb.WorkspaceAISetting)
+	}
+	if workspaceAISetting.Provider == storepb.WorkspaceAISetting_PROVIDER_UNSPECIFIED {
+		workspaceAISetting.Provider = defaultWorkspaceAIProvider
+	}
+	s.workspaceSettingCache.Set(ctx, storepb.WorkspaceSettingKey_AI.String(), &storepb.WorkspaceSetting{
+		Key:   storepb.WorkspaceSettingKey_AI,
+		Value: &storepb.WorkspaceSetting_AiSetting{AiSetting: workspaceAISetting},
+	})
+	return workspaceAISetting, nil
+}
+
+func (s *Store) GetWorkspaceEmailTemplatesSetting(ctx context.Context) (*storepb.WorkspaceEmailTemplatesSetting, error) {
+	workspaceSetting, err := s.GetWorkspaceSetting(ctx, &FindWorkspaceSetting{
+		Name: storepb.WorkspaceSettingKey_EMAIL_TEMPLATES.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workspace email templates setting")
+	}
+
+	workspaceEmailTemplatesSetting := &storepb.WorkspaceEmailTemplatesSetting{}
+	if workspaceSetting != nil {
+		workspaceEmailTemplatesSetting = workspaceSetting.GetEmailTemplatesSetting()
+	}
+	s.workspaceSettingCache.Set(ctx, storepb.WorkspaceSettingKey_EMAIL_TEMPLATES.String(), &storepb.WorkspaceSetting{
+		Key:   storepb.WorkspaceSettingKey_EMAIL_TEMPLATES,
+		Value: &storepb.WorkspaceSetting_EmailTemplatesSetting{EmailTemplatesSetting: workspaceEmailTemplatesSetting},
+	})
+	return workspaceEmailTemplatesSetting, nil
+}
+
 func convertWorkspaceSettingFromRaw(workspaceSettingRaw *WorkspaceSetting) (*storepb.WorkspaceSetting, error) {
 	workspaceSetting := &storepb.WorkspaceSetting{
 		Key: storepb.WorkspaceSettingKey(storepb.WorkspaceSettingKey_value[workspaceSettingRaw.Name]),
@@ -238,6 +287,18 @@ func convertWorkspaceSettingFromRaw(workspaceSettingRaw *WorkspaceSetting) (*sto
 			return nil, err
 		}
 		workspaceSetting.Value = &storepb.WorkspaceSetting_MemoRelatedSetting{MemoRelatedSetting: memoRelatedSetting}
+	case storepb.WorkspaceSettingKey_AI.String():
+		aiSetting := &storepb.WorkspaceAISetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(workspaceSettingRaw.Value), aiSetting); err != nil {
+			return nil, err
+		}
+		workspaceSetting.Value = &storepb.WorkspaceSetting_AiSetting{AiSetting: aiSetting}
+	case storepb.WorkspaceSettingKey_EMAIL_TEMPLATES.String():
+		emailTemplatesSetting := &storepb.WorkspaceEmailTemplatesSetting{}
+		if err := protojsonUnmarshaler.Unmarshal([]byte(workspaceSettingRaw.Value), emailTemplatesSetting); err != nil {
+			return nil, err
+		}
+		workspaceSetting.Value = &storepb.WorkspaceSetting_EmailTemplatesSetting{EmailTemplatesSetting: emailTemplatesSetting}
 	default:
 		// Skip unsupported workspace setting key.
 		return nil, nil
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/imrany/wekalist/internal/profile"
+	"github.com/imrany/wekalist/plugin/webpush"
+	"github.com/imrany/wekalist/store"
+	"github.com/imrany/wekalist/store/db"
+)
+
+// vapidKeysFile is the file of the data directory storing the VAPID keys unless they are set by flags.
+const vapidKeysFile = "vapid.json"
+
+var vapidCmd = &cobra.Command{
+	Use:   "vapid",
+	Short: "Manage the VAPID keys of web push notifications",
+}
+
+var vapidRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the VAPID keys and ask the subscribed browsers to subscribe again",
+	Long: `Replace the VAPID keys stored in the data directory with a new keypair.
+
+Push subscriptions are bound to the VAPID public key they were made with, so every
+subscribed browser is sent a push message, signed with the previous keys, asking it to
+subscribe again with the new public key. The previous subscriptions are then deleted.
+Restart the server afterwards to send notifications with the new keys.`,
+	Args: cobra.NoArgs,
+	RunE: runVAPIDRotate,
+}
+
+func init() {
+	vapidCmd.AddCommand(vapidRotateCmd)
+	rootCmd.AddCommand(vapidCmd)
+}
+
+// loadVAPIDKeys sets the VAPID keys of the profile from the flags, or from the keys file of the
+// data directory, generated on first start.
+func loadVAPIDKeys(ctx context.Context, profile *profile.Profile, stores *store.Store) error {
+	keys := &webpush.VAPIDKeys{
+		PublicKey:  viper.GetString("vapid-public-key"),
+		PrivateKey: viper.GetString("vapid-private-key"),
+	}
+	if keys.PublicKey == "" && keys.PrivateKey == "" {
+		var generated bool
+		var err error
+		keys, generated, err = webpush.LoadOrGenerateVAPIDKeys(filepath.Join(profile.Data, vapidKeysFile))
+		if err != nil {
+			return err
+		}
+		if generated {
+			slog.Info("generated VAPID keys", slog.String("file", filepath.Join(profile.Data, vapidKeysFile)))
+			// The existing subscriptions were made with other keys, so they cannot be delivered anymore.
+			// Browsers subscribe again with the new public key once wekalist is opened.
+			subscriptions, err := stores.ListSubscriptions(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to list subscriptions: %w", err)
+			}
+			if err := deleteSubscriptions(ctx, stores, subscriptions); err != nil {
+				return err
+			}
+			if len(subscriptions) > 0 {
+				slog.Info("deleted push subscriptions made with the previous VAPID keys", slog.Int("count", len(subscriptions)))
+			}
+		}
+	} else if err := keys.Validate(); err != nil {
+		return fmt.Errorf("both --vapid-public-key and --vapid-private-key must be set to a valid keypair: %w", err)
+	}
+
+	profile.WebPushConfig.VAPIDPublicKey = keys.PublicKey
+	profile.WebPushConfig.VAPIDPrivateKey = keys.PrivateKey
+	return nil
+}
+
+func runVAPIDRotate(cmd *cobra.Command, _ []string) error {
+	if viper.GetString("vapid-public-key") != "" || viper.GetString("vapid-private-key") != "" {
+		return errors.New("the VAPID keys are set by flags or environment variables, replace them there")
+	}
+	profile := newProfile()
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	storeInstance := store.New(dbDriver, profile)
+	defer storeInstance.Close()
+	if err := storeInstance.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate store: %w", err)
+	}
+
+	keysPath := filepath.Join(profile.Data, vapidKeysFile)
+	previousKeys, err := webpush.LoadVAPIDKeys(keysPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		return err
+	}
+	// The new keys are saved first, so the browsers never subscribe again with keys the server does not have.
+	if err := webpush.SaveVAPIDKeys(keysPath, keys); err != nil {
+		return err
+	}
+	fmt.Printf("Saved the new VAPID keys to %s\n", keysPath)
+
+	// Only the subscriptions listed before the browsers are asked to subscribe again are deleted.
+	subscriptions, err := storeInstance.ListSubscriptions(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	if previousKeys != nil && len(subscriptions) > 0 {
+		signalled, err := signalResubscription(ctx, profile, previousKeys, keys.PublicKey, subscriptions)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Asked %d/%d subscribed browsers to subscribe again\n", signalled, len(subscriptions))
+	}
+	if err := deleteSubscriptions(ctx, storeInstance, subscriptions); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d subscriptions made with the previous keys\n", len(subscriptions))
+	fmt.Println("Restart the server to send notifications with the new keys.")
+	return nil
+}
+
+// signalResubscription sends the new public key to the subscriptions with the previous keys,
+// and returns how many push services accepted the message.
+func signalResubscription(ctx context.Context, profile *profile.Profile, previousKeys *webpush.VAPIDKeys, publicKey string, subscriptions []*store.Subscription) (int, error) {
+	payload, err := json.Marshal(&webpush.VAPIDRotatedMessage{
+		Type:           webpush.VAPIDRotatedMessageType,
+		VAPIDPublicKey: publicKey,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal push message: %w", err)
+	}
+	message := &webpush.Message{
+		Payload: payload,
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyHigh,
+		Topic:   webpush.VAPIDRotatedMessageType,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	dispatcher := webpush.NewDispatcher(webpush.Config{
+		VAPIDPublicKey:  previousKeys.PublicKey,
+		VAPIDPrivateKey: previousKeys.PrivateKey,
+		Subscriber:      profile.InstanceURL,
+		QueueSize:       len(subscriptions),
+	})
+	dispatcher.Start(ctx)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	signalled := 0
+	for _, subscription := range subscriptions {
+		wg.Add(1)
+		if err := dispatcher.Enqueue(&webpush.Delivery{
+			Subscription: &webpush.Subscription{
+				Endpoint: subscription.Endpoint,
+				P256dh:   subscription.Keys["p256dh"],
+				Auth:     subscription.Keys["auth"],
+			},
+			Message: message,
+			OnOutcome: func(_ context.Context, outcome *webpush.Outcome) {
+				defer wg.Done()
+				if outcome.Status != webpush.StatusDelivered {
+					slog.Warn("failed to ask the browser to subscribe again", slog.Int("user", int(subscription.UserID)), slog.String("error", outcome.Error))
+					return
+				}
+				mu.Lock()
+				signalled++
+				mu.Unlock()
+			},
+		}); err != nil {
+			wg.Done()
+			return 0, err
+		}
+	}
+	wg.Wait()
+	return signalled, nil
+}
+
+func deleteSubscriptions(ctx context.Context, stores *store.Store, subscriptions []*store.Subscription) error {
+	for _, subscription := range subscriptions {
+		if err := stores.DeleteSubscription(ctx, &store.DeleteSubscription{
+			UserID:   &subscription.UserID,
+			Endpoint: &subscription.Endpoint,
+		}); err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+	}
+	return nil
+}
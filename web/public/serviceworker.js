@@ -155,11 +155,38 @@ self.addEventListener('fetch', (evt) => {
     );
 });
 
+// Subscribe again with the new VAPID public key once the server rotated its keys
+const resubscribe = async (vapidPublicKey) => {
+    const previousSubscription = await self.registration.pushManager.getSubscription();
+    if (previousSubscription) {
+        await previousSubscription.unsubscribe();
+    }
+    const subscription = await self.registration.pushManager.subscribe({
+        userVisibleOnly: true,
+        applicationServerKey: vapidPublicKey
+    });
+    const { endpoint, keys } = subscription.toJSON();
+    const response = await fetch('/v1/subscribe', {
+        method: 'POST',
+        credentials: 'same-origin',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ endpoint, keys })
+    });
+    if (!response.ok) {
+        console.error('Failed to subscribe again:', response.status);
+    }
+};
+
 // Enhanced push notification handler
 self.addEventListener("push", event => {
     console.log("Push event received:", event);
     const data = event.data.json();
 
+    if (data.type === "vapid-rotated") {
+        event.waitUntil(resubscribe(data.vapidPublicKey));
+        return;
+    }
+
     self.registration.showNotification(data.title, {
         body: data.body,
         icon: data.icon || '/full-logo.png',
@@ -20,6 +20,19 @@ import { SubscriptionRequest, RemoveSubscriptionRequest } from "@/types/proto/ap
 import { SubscriptionServiceClient } from "@/grpcweb";
 import useCurrentUser from "@/hooks/useCurrentUser";
 
+// isSubscribedWithKey checks whether the subscription was made with the base64url encoded VAPID public key.
+const isSubscribedWithKey = (subscription: PushSubscription, vapidPublicKey: string): boolean => {
+  const applicationServerKey = subscription.options.applicationServerKey;
+  if (!applicationServerKey) {
+    return false;
+  }
+  const encodedKey = btoa(String.fromCharCode(...new Uint8Array(applicationServerKey)))
+    .replace(/\+/g, "-")
+    .replace(/\//g, "_")
+    .replace(/=+$/, "");
+  return encodedKey === vapidPublicKey.replace(/=+$/, "");
+};
+
 const PreferencesSection = observer(() => {
   const t = useTranslate();
   const { email, username } = useCurrentUser();
@@ -198,14 +211,21 @@ const PreferencesSection = observer(() => {
       }
 
       // Check if we already have a subscription
+      const vapidPublicKey = workspaceStore.state.profile.vapidPublicKey;
       let subscription = await registration.pushManager.getSubscription();
 
+      // A subscription made with previous VAPID keys can no longer receive notifications
+      if (subscription && !isSubscribedWithKey(subscription, vapidPublicKey)) {
+        await subscription.unsubscribe();
+        subscription = null;
+      }
+
       // If no subscription exists, create one
       if (!subscription) {
         try {
           subscription = await registration.pushManager.subscribe({
             userVisibleOnly: true,
-            applicationServerKey: 'BOxQ1MrRQ3E0EvBglmCHO_FeoBOoHCKXnaOKhDni1EzD2rThuBeLo3WsBys-Brddm8afLm4XPo2AY16irwslYDs'
+            applicationServerKey: vapidPublicKey
           });
         } catch (subscribeError: any) {
           if (subscribeError.name === 'AbortError' || subscribeError.message?.includes('could not connect to push server')) {
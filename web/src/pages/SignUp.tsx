@@ -35,7 +35,6 @@ const SignUp = observer(() => {
   // Clean up localStorage on component unmount
   useEffect(() => {
     return () => {
-      localStorage.removeItem("otp");
       localStorage.removeItem("verifiedEmail");
     };
   }, []);
@@ -126,10 +125,9 @@ const SignUp = observer(() => {
         email: email.trim()
       };
 
-      const { otp: serverOtp } = await userServiceClient.verifyUser(request);
+      await userServiceClient.verifyUser(request);
 
-      // Store OTP and verified email securely
-      localStorage.setItem("otp", serverOtp);
+      // The code is checked by the server when the account is created.
       localStorage.setItem("verifiedEmail", email.trim());
 
       setShowOTPInput(true);
@@ -150,10 +148,9 @@ const SignUp = observer(() => {
       return;
     }
 
-    const storedOTP = localStorage.getItem("otp");
     const verifiedEmail = localStorage.getItem("verifiedEmail");
 
-    if (!storedOTP || !verifiedEmail) {
+    if (!verifiedEmail) {
       toast.error(t("auth.verification-expired"));
       setShowOTPInput(false);
       return;
@@ -165,15 +162,8 @@ const SignUp = observer(() => {
       return;
     }
 
-    if (otpToVerify === storedOTP) {
-      setVerified(true);
-      toast.success(t("auth.email-verified"));
-      // Clean up sensitive data
-      localStorage.removeItem("otp");
-    } else {
-      toast.error(t("auth.invalid-otp"));
-      setOtp(""); // Clear invalid OTP
-    }
+    setOtp(otpToVerify);
+    setVerified(true);
   };
 
   const handleSignUpButtonClick = async () => {
@@ -218,7 +208,7 @@ const SignUp = observer(() => {
         role: User_Role.USER,
       });
 
-      await userServiceClient.createUser({ user });
+      await userServiceClient.createUser({ user, otp: enableEmailVerification ? otp : "" });
       await authServiceClient.createSession({
         passwordCredentials: { username: username.trim(), password: password.trim() },
       });
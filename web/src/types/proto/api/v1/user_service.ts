@@ -114,8 +114,6 @@ export interface VerifyRequest {
 export interface VerifyResponse {
   /** The user email */
   email: string;
-  /** The One Time Password (OTP) */
-  otp: string;
 }
 
 export interface ListUsersRequest {
@@ -190,6 +188,11 @@ export interface CreateUserRequest {
    * requests to create a user have the same result.
    */
   requestId: string;
+  /**
+   * Optional. The verification code sent by VerifyUser.
+   * Required when email verification is enabled.
+   */
+  otp: string;
 }
 
 export interface UpdateUserRequest {
@@ -754,7 +757,7 @@ export const VerifyRequest: MessageFns<VerifyRequest> = {
 };
 
 function createBaseVerifyResponse(): VerifyResponse {
-  return { email: "", otp: "" };
+  return { email: "" };
 }
 
 export const VerifyResponse: MessageFns<VerifyResponse> = {
@@ -762,9 +765,6 @@ export const VerifyResponse: MessageFns<VerifyResponse> = {
     if (message.email !== "") {
       writer.uint32(10).string(message.email);
     }
-    if (message.otp !== "") {
-      writer.uint32(18).string(message.otp);
-    }
     return writer;
   },
 
@@ -783,14 +783,6 @@ export const VerifyResponse: MessageFns<VerifyResponse> = {
           message.email = reader.string();
           continue;
         }
-        case 2: {
-          if (tag !== 18) {
-            break;
-          }
-
-          message.otp = reader.string();
-          continue;
-        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -806,7 +798,6 @@ export const VerifyResponse: MessageFns<VerifyResponse> = {
   fromPartial(object: DeepPartial<VerifyResponse>): VerifyResponse {
     const message = createBaseVerifyResponse();
     message.email = object.email ?? "";
-    message.otp = object.otp ?? "";
     return message;
   },
 };
@@ -1034,7 +1025,7 @@ export const GetUserRequest: MessageFns<GetUserRequest> = {
 };
 
 function createBaseCreateUserRequest(): CreateUserRequest {
-  return { user: undefined, userId: "", validateOnly: false, requestId: "" };
+  return { user: undefined, userId: "", validateOnly: false, requestId: "", otp: "" };
 }
 
 export const CreateUserRequest: MessageFns<CreateUserRequest> = {
@@ -1051,6 +1042,9 @@ export const CreateUserRequest: MessageFns<CreateUserRequest> = {
     if (message.requestId !== "") {
       writer.uint32(34).string(message.requestId);
     }
+    if (message.otp !== "") {
+      writer.uint32(42).string(message.otp);
+    }
     return writer;
   },
 
@@ -1093,6 +1087,14 @@ export const CreateUserRequest: MessageFns<CreateUserRequest> = {
           message.requestId = reader.string();
           continue;
         }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.otp = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1111,6 +1113,7 @@ export const CreateUserRequest: MessageFns<CreateUserRequest> = {
     message.userId = object.userId ?? "";
     message.validateOnly = object.validateOnly ?? false;
     message.requestId = object.requestId ?? "";
+    message.otp = object.otp ?? "";
     return message;
   },
 };
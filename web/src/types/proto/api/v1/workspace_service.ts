@@ -23,6 +23,8 @@ export interface WorkspaceProfile {
   mode: string;
   /** Instance URL is the URL of the instance. */
   instanceUrl: string;
+  /** The VAPID public key browsers subscribe to push notifications with. */
+  vapidPublicKey: string;
 }
 
 /** Request for workspace profile. */
@@ -136,6 +138,68 @@ export interface WorkspaceGeneralSetting {
   smtpAccountEmail: string;
   /** smtp_account_password is the host's sender email address password */
   smtpAccountPassword: string;
+  /** require_two_factor requires all users to enable two-factor authentication. */
+  requireTwoFactor: boolean;
+  /** smtp_security is how the connection to the SMTP server is secured. */
+  smtpSecurity: WorkspaceGeneralSetting_SmtpSecurity;
+  /** dkim_domain is the domain signing the emails with DKIM. Emails are not signed if empty. */
+  dkimDomain: string;
+  /** dkim_selector is the selector of the DKIM public key, published at {selector}._domainkey.{domain}. */
+  dkimSelector: string;
+  /** dkim_private_key is the PEM encoded RSA or Ed25519 private key signing the emails. */
+  dkimPrivateKey: string;
+}
+
+export enum WorkspaceGeneralSetting_SmtpSecurity {
+  /** SMTP_SECURITY_UNSPECIFIED - SMTP_SECURITY_UNSPECIFIED upgrades the connection with STARTTLS. */
+  SMTP_SECURITY_UNSPECIFIED = "SMTP_SECURITY_UNSPECIFIED",
+  /** STARTTLS - STARTTLS upgrades the connection with STARTTLS, usually on port 587. */
+  STARTTLS = "STARTTLS",
+  /** TLS - TLS connects over TLS, usually on port 465. */
+  TLS = "TLS",
+  /**
+   * PLAIN - PLAIN does not encrypt the connection, for relays on a trusted network.
+   * Credentials are then only sent to localhost.
+   */
+  PLAIN = "PLAIN",
+  UNRECOGNIZED = "UNRECOGNIZED",
+}
+
+export function workspaceGeneralSetting_SmtpSecurityFromJSON(object: any): WorkspaceGeneralSetting_SmtpSecurity {
+  switch (object) {
+    case 0:
+    case "SMTP_SECURITY_UNSPECIFIED":
+      return WorkspaceGeneralSetting_SmtpSecurity.SMTP_SECURITY_UNSPECIFIED;
+    case 1:
+    case "STARTTLS":
+      return WorkspaceGeneralSetting_SmtpSecurity.STARTTLS;
+    case 2:
+    case "TLS":
+      return WorkspaceGeneralSetting_SmtpSecurity.TLS;
+    case 3:
+    case "PLAIN":
+      return WorkspaceGeneralSetting_SmtpSecurity.PLAIN;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return WorkspaceGeneralSetting_SmtpSecurity.UNRECOGNIZED;
+  }
+}
+
+export function workspaceGeneralSetting_SmtpSecurityToNumber(object: WorkspaceGeneralSetting_SmtpSecurity): number {
+  switch (object) {
+    case WorkspaceGeneralSetting_SmtpSecurity.SMTP_SECURITY_UNSPECIFIED:
+      return 0;
+    case WorkspaceGeneralSetting_SmtpSecurity.STARTTLS:
+      return 1;
+    case WorkspaceGeneralSetting_SmtpSecurity.TLS:
+      return 2;
+    case WorkspaceGeneralSetting_SmtpSecurity.PLAIN:
+      return 3;
+    case WorkspaceGeneralSetting_SmtpSecurity.UNRECOGNIZED:
+    default:
+      return -1;
+  }
 }
 
 export interface WorkspaceCustomProfile {
@@ -258,7 +322,7 @@ export interface UpdateWorkspaceSettingRequest {
 }
 
 function createBaseWorkspaceProfile(): WorkspaceProfile {
-  return { owner: "", version: "", mode: "", instanceUrl: "" };
+  return { owner: "", version: "", mode: "", instanceUrl: "", vapidPublicKey: "" };
 }
 
 export const WorkspaceProfile: MessageFns<WorkspaceProfile> = {
@@ -275,6 +339,9 @@ export const WorkspaceProfile: MessageFns<WorkspaceProfile> = {
     if (message.instanceUrl !== "") {
       writer.uint32(50).string(message.instanceUrl);
     }
+    if (message.vapidPublicKey !== "") {
+      writer.uint32(58).string(message.vapidPublicKey);
+    }
     return writer;
   },
 
@@ -317,6 +384,14 @@ export const WorkspaceProfile: MessageFns<WorkspaceProfile> = {
           message.instanceUrl = reader.string();
           continue;
         }
+        case 7: {
+          if (tag !== 58) {
+            break;
+          }
+
+          message.vapidPublicKey = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -335,6 +410,7 @@ export const WorkspaceProfile: MessageFns<WorkspaceProfile> = {
     message.version = object.version ?? "";
     message.mode = object.mode ?? "";
     message.instanceUrl = object.instanceUrl ?? "";
+    message.vapidPublicKey = object.vapidPublicKey ?? "";
     return message;
   },
 };
@@ -478,6 +554,11 @@ function createBaseWorkspaceGeneralSetting(): WorkspaceGeneralSetting {
     smtpAccountUsername: "",
     smtpAccountEmail: "",
     smtpAccountPassword: "",
+    requireTwoFactor: false,
+    smtpSecurity: WorkspaceGeneralSetting_SmtpSecurity.SMTP_SECURITY_UNSPECIFIED,
+    dkimDomain: "",
+    dkimSelector: "",
+    dkimPrivateKey: "",
   };
 }
 
@@ -528,6 +609,21 @@ export const WorkspaceGeneralSetting: MessageFns<WorkspaceGeneralSetting> = {
     if (message.smtpAccountPassword !== "") {
       writer.uint32(122).string(message.smtpAccountPassword);
     }
+    if (message.requireTwoFactor !== false) {
+      writer.uint32(128).bool(message.requireTwoFactor);
+    }
+    if (message.smtpSecurity !== WorkspaceGeneralSetting_SmtpSecurity.SMTP_SECURITY_UNSPECIFIED) {
+      writer.uint32(144).int32(workspaceGeneralSetting_SmtpSecurityToNumber(message.smtpSecurity));
+    }
+    if (message.dkimDomain !== "") {
+      writer.uint32(154).string(message.dkimDomain);
+    }
+    if (message.dkimSelector !== "") {
+      writer.uint32(162).string(message.dkimSelector);
+    }
+    if (message.dkimPrivateKey !== "") {
+      writer.uint32(170).string(message.dkimPrivateKey);
+    }
     return writer;
   },
 
@@ -658,6 +754,46 @@ export const WorkspaceGeneralSetting: MessageFns<WorkspaceGeneralSetting> = {
           message.smtpAccountPassword = reader.string();
           continue;
         }
+        case 16: {
+          if (tag !== 128) {
+            break;
+          }
+
+          message.requireTwoFactor = reader.bool();
+          continue;
+        }
+        case 18: {
+          if (tag !== 144) {
+            break;
+          }
+
+          message.smtpSecurity = workspaceGeneralSetting_SmtpSecurityFromJSON(reader.int32());
+          continue;
+        }
+        case 19: {
+          if (tag !== 154) {
+            break;
+          }
+
+          message.dkimDomain = reader.string();
+          continue;
+        }
+        case 20: {
+          if (tag !== 162) {
+            break;
+          }
+
+          message.dkimSelector = reader.string();
+          continue;
+        }
+        case 21: {
+          if (tag !== 170) {
+            break;
+          }
+
+          message.dkimPrivateKey = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -689,6 +825,11 @@ export const WorkspaceGeneralSetting: MessageFns<WorkspaceGeneralSetting> = {
     message.smtpAccountUsername = object.smtpAccountUsername ?? "";
     message.smtpAccountEmail = object.smtpAccountEmail ?? "";
     message.smtpAccountPassword = object.smtpAccountPassword ?? "";
+    message.requireTwoFactor = object.requireTwoFactor ?? false;
+    message.smtpSecurity = object.smtpSecurity ?? WorkspaceGeneralSetting_SmtpSecurity.SMTP_SECURITY_UNSPECIFIED;
+    message.dkimDomain = object.dkimDomain ?? "";
+    message.dkimSelector = object.dkimSelector ?? "";
+    message.dkimPrivateKey = object.dkimPrivateKey ?? "";
     return message;
   },
 };